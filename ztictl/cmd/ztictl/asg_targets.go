@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// asgInstanceLookup lists the members of an Auto Scaling group; tests replace it
+var asgInstanceLookup = func(ctx context.Context, region, groupName string) ([]ssm.AutoScalingInstance, error) {
+	return ssm.NewManager(logger).AutoScalingGroupInstances(ctx, region, groupName)
+}
+
+// addASGFlag registers --asg for commands that otherwise target instances by --tags or --instances
+func addASGFlag(cmd *cobra.Command) {
+	cmd.Flags().String("asg", "", "Target the InService and Standby instances of this Auto Scaling group (instead of --tags or --instances)")
+}
+
+// resolveASGFlag returns the instances of the --asg group as a comma-separated list for the
+// --instances code path, which looks up their state and agent status, or instancesFlag unchanged
+// when --asg is not set. Members that are pending,
+// terminating or detaching are left out and reported by lifecycle state.
+func resolveASGFlag(ctx context.Context, cmd *cobra.Command, region, tagsFlag, instancesFlag string) (string, error) {
+	flag := cmd.Flags().Lookup("asg")
+	if flag == nil || flag.Value.String() == "" {
+		return instancesFlag, nil
+	}
+	groupName := flag.Value.String()
+	if tagsFlag != "" || instancesFlag != "" {
+		return "", fmt.Errorf("--asg cannot be combined with --tags or --instances")
+	}
+
+	members, err := asgInstanceLookup(ctx, region, groupName)
+	if err != nil {
+		return "", err
+	}
+
+	var instanceIDs []string
+	skippedByState := map[string]int{}
+	for _, member := range members {
+		if member.Targetable() {
+			instanceIDs = append(instanceIDs, member.InstanceID)
+		} else {
+			skippedByState[member.LifecycleState]++
+		}
+	}
+
+	if len(skippedByState) > 0 {
+		states := make([]string, 0, len(skippedByState))
+		skipped := 0
+		for state, count := range skippedByState {
+			states = append(states, fmt.Sprintf("%d %s", count, state))
+			skipped += count
+		}
+		sort.Strings(states)
+		colors.PrintWarning("⚠ Skipping %d instance(s) of Auto Scaling group %s that are not in service: %s\n", skipped, groupName, strings.Join(states, ", "))
+	}
+	if len(instanceIDs) == 0 {
+		return "", fmt.Errorf("no InService or Standby instances in Auto Scaling group %s (%d member(s))", groupName, len(members))
+	}
+
+	logging.LogInfo("Auto Scaling group %s has %d instance(s) to target in region: %s", groupName, len(instanceIDs), region)
+	return strings.Join(instanceIDs, ","), nil
+}
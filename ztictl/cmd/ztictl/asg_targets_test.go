@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+func newASGTestCommand(t *testing.T, asg string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "test"}
+	addASGFlag(cmd)
+	if asg != "" {
+		if err := cmd.Flags().Set("asg", asg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cmd
+}
+
+func TestResolveASGFlag(t *testing.T) {
+	originalLookup := asgInstanceLookup
+	defer func() { asgInstanceLookup = originalLookup }()
+
+	var lookedUp string
+	asgInstanceLookup = func(ctx context.Context, region, groupName string) ([]ssm.AutoScalingInstance, error) {
+		lookedUp = region + "/" + groupName
+		switch groupName {
+		case "web-asg":
+			return []ssm.AutoScalingInstance{
+				{InstanceID: "i-0a", LifecycleState: "InService"},
+				{InstanceID: "i-0b", LifecycleState: "Pending"},
+				{InstanceID: "i-0c", LifecycleState: "Standby"},
+				{InstanceID: "i-0d", LifecycleState: "Terminating"},
+			}, nil
+		case "draining-asg":
+			return []ssm.AutoScalingInstance{{InstanceID: "i-0e", LifecycleState: "Terminating"}}, nil
+		}
+		return nil, fmt.Errorf("no Auto Scaling group named %s in %s", groupName, region)
+	}
+
+	instances, err := resolveASGFlag(context.Background(), newASGTestCommand(t, "web-asg"), "ca-central-1", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if instances != "i-0a,i-0c" {
+		t.Errorf("Expected the InService and Standby instances, got %q", instances)
+	}
+	if lookedUp != "ca-central-1/web-asg" {
+		t.Errorf("Expected the group to be looked up in the command's region, got %q", lookedUp)
+	}
+
+	if _, err := resolveASGFlag(context.Background(), newASGTestCommand(t, "draining-asg"), "ca-central-1", "", ""); err == nil || !strings.Contains(err.Error(), "no InService or Standby instances") {
+		t.Errorf("Expected an error for a group with nothing to target, got %v", err)
+	}
+	if _, err := resolveASGFlag(context.Background(), newASGTestCommand(t, "missing"), "ca-central-1", "", ""); err == nil {
+		t.Error("Expected the lookup error for an unknown group")
+	}
+	if _, err := resolveASGFlag(context.Background(), newASGTestCommand(t, "web-asg"), "ca-central-1", "Role=web", ""); err == nil {
+		t.Error("Expected --asg to be rejected together with --tags")
+	}
+
+	instances, err = resolveASGFlag(context.Background(), newASGTestCommand(t, ""), "ca-central-1", "", "i-0z")
+	if err != nil || instances != "i-0z" {
+		t.Errorf("Expected --instances unchanged without --asg, got %q (%v)", instances, err)
+	}
+}
+
+func TestExecTaggedASGTargetsAreNotSkipped(t *testing.T) {
+	if logger == nil {
+		logger = logging.NewLogger(false)
+	}
+	originalLookup := asgInstanceLookup
+	defer func() { asgInstanceLookup = originalLookup }()
+	asgInstanceLookup = func(ctx context.Context, region, groupName string) ([]ssm.AutoScalingInstance, error) {
+		return []ssm.AutoScalingInstance{
+			{InstanceID: "i-0a", LifecycleState: "InService"},
+			{InstanceID: "i-0b", LifecycleState: "InService"},
+			{InstanceID: "i-0c", LifecycleState: "Standby"},
+		}, nil
+	}
+	fakeInstanceLookup(t, []interactive.Instance{
+		{InstanceID: "i-0a", Name: "web-1", State: "running", SSMStatus: "Online"},
+		{InstanceID: "i-0b", Name: "web-2", State: "running", SSMStatus: "Online"},
+		{InstanceID: "i-0c", Name: "web-3", State: "stopped", SSMStatus: "ConnectionLost"},
+	})
+
+	// exec-tagged feeds the group's members through the --instances code path
+	instancesFlag, err := resolveASGFlag(context.Background(), newASGTestCommand(t, "web-asg"), "ca-central-1", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := execOptions{}
+	instances, err := resolveExecTaggedInstances(context.Background(), nil, "ca-central-1", "uptime", "", instancesFlag, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := countTargets(context.Background(), nil, "ca-central-1", instances, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count.Matched != 3 || count.Targets != 2 || count.Skipped != 1 {
+		t.Errorf("Expected the running, online members to be targeted and the stopped one skipped, got %+v", count)
+	}
+	for _, instance := range instances[:2] {
+		if reason := execSkipReason(instance, opts); reason != "" {
+			t.Errorf("Expected %s to be executed, got skipped: %s", instance.InstanceID, reason)
+		}
+	}
+}
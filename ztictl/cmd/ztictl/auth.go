@@ -1,13 +1,18 @@
 package main
 
 import (
-	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
+	"strings"
+	"time"
 
 	"ztictl/internal/auth"
+	"ztictl/internal/config"
 	"ztictl/pkg/colors"
+	"ztictl/pkg/format"
 	"ztictl/pkg/logging"
 
 	"github.com/spf13/cobra"
@@ -22,7 +27,7 @@ var authCmd = &cobra.Command{
 Examples:
   ztictl auth login <profile>           # SSO login (profile required)
   ztictl auth logout [profile]          # SSO logout  
-  ztictl auth profiles                  # List/manage profiles
+  ztictl auth profiles                  # List/manage profiles (alias: list)
   ztictl auth creds [profile]           # Show credentials`,
 }
 
@@ -33,12 +38,45 @@ var authLoginCmd = &cobra.Command{
 	Long: `Login to AWS SSO with interactive account and role selection.
 A profile name must be specified to ensure intentional credential management.
 
+With --profile-template, the account, role and region come from a template in the
+profile_templates section of ~/.ztictl.yaml instead of the pickers, and the profile
+name defaults to the template name.
+
+With --profile-region, the profile's region is set to the given region instead of the
+template's region or default_region. The SSO region is not affected.
+
+With --no-browser, the verification URL and code are printed without opening a browser,
+so they can be opened on another device (useful over SSH). --browser opens the URL with
+a specific browser binary instead of the system default. --qr also shows the URL as a
+QR code, to complete the sign-in on a phone.
+
 Note: AWS SSO authentication requires browser interaction and cannot be used in CI/CD pipelines.
 For automated environments, use IAM-based authentication (OIDC, EC2 instance profiles, or IAM access keys).
 See docs/CI_CD_AUTHENTICATION.md for details.`,
-	Args: cobra.ExactArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		// The profile name defaults to the template name with --profile-template
+		if template, _ := cmd.Flags().GetString("profile-template"); template != "" {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
-		profileName := args[0]
+		templateName, _ := cmd.Flags().GetString("profile-template")
+		var template *config.ProfileTemplate
+		if templateName != "" {
+			var err error
+			if template, err = config.Get().ProfileTemplate(templateName); err != nil {
+				logging.LogError("%v", err)
+				os.Exit(1)
+			}
+		}
+
+		var profileName string
+		if len(args) == 1 {
+			profileName = args[0]
+		} else {
+			profileName = template.Name
+		}
 
 		// Check if running in non-interactive mode (CI/CD environment)
 		execCtx := GetExecutionContext(cmd)
@@ -82,7 +120,12 @@ For complete examples and setup instructions:
 			}
 		}
 
-		if err := performLogin(profileName); err != nil {
+		opts, err := loginOptionsFromFlags(cmd, template)
+		if err != nil {
+			logging.LogError("Login failed: %v", err)
+			os.Exit(1)
+		}
+		if err := performLogin(profileName, opts); err != nil {
 			logging.LogError("Login failed: %v", err)
 			os.Exit(1)
 		}
@@ -93,15 +136,24 @@ For complete examples and setup instructions:
 var authLogoutCmd = &cobra.Command{
 	Use:   "logout [profile]",
 	Short: "Logout from AWS SSO",
-	Long:  `Logout from AWS SSO and clear cached credentials.`,
-	Args:  cobra.MaximumNArgs(1),
+	Long: `Logout from AWS SSO by removing cached SSO tokens from ~/.aws/sso/cache.
+With a profile, removes the token for that profile's sso_start_url; without one, the token for the
+start URL configured in ~/.ztictl.yaml. Only files that contain an SSO token are removed.
+
+Examples:
+  ztictl auth logout                        # Configured SSO start URL
+  ztictl auth logout prod-admin             # A specific profile's start URL
+  ztictl auth logout --all --dry-run        # Preview removing every cached SSO token`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		var profileName string
 		if len(args) > 0 {
 			profileName = args[0]
 		}
+		all, _ := cmd.Flags().GetBool("all")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
 
-		if err := performLogout(profileName); err != nil {
+		if err := performLogout(profileName, auth.LogoutOptions{All: all, DryRun: dryRun}); err != nil {
 			logging.LogError("Logout failed: %v", err)
 			os.Exit(1)
 		}
@@ -110,11 +162,21 @@ var authLogoutCmd = &cobra.Command{
 
 // authProfilesCmd represents the auth profiles command
 var authProfilesCmd = &cobra.Command{
-	Use:   "profiles",
-	Short: "List and manage AWS profiles",
-	Long:  `List all configured AWS profiles and their status.`,
+	Use:     "profiles",
+	Aliases: []string{"list"},
+	Short:   "List and manage AWS profiles",
+	Long: `List all configured AWS profiles and their status.
+Session expiry is read from the SSO token cache for each profile's start URL.
+
+Examples:
+  ztictl auth profiles                      # Human-readable list
+  ztictl auth profiles --json               # JSON output for scripting
+  ztictl auth list --json --only-valid      # Only profiles with a valid session`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := listAuthProfiles(); err != nil {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		onlyValid, _ := cmd.Flags().GetBool("only-valid")
+
+		if err := listAuthProfiles(jsonOutput, onlyValid); err != nil {
 			logging.LogError("Failed to list profiles: %v", err)
 			os.Exit(1)
 		}
@@ -126,10 +188,20 @@ var authCredsCmd = &cobra.Command{
 	Use:   "creds [profile]",
 	Short: "Show AWS credentials",
 	Long: `Display AWS credentials for the specified profile in environment variable format.
-If no profile is specified, uses the current AWS_PROFILE or default profile.`,
+If no profile is specified, uses the current AWS_PROFILE or default profile.
+
+With --write-profile NAME the credentials are written to ~/.aws/credentials (or
+AWS_SHARED_CREDENTIALS_FILE) as a static [NAME] profile instead, for tools that
+support neither SSO nor credential_process. They are temporary: run the command
+again to refresh them once they expire.
+
+With --region the exported AWS_REGION and AWS_DEFAULT_REGION use that region instead
+of the profile's, for tools run against another region. The profile is not changed.`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := showCredentials(args); err != nil {
+		writeProfile, _ := cmd.Flags().GetString("write-profile")
+		regionCode, _ := cmd.Flags().GetString("region")
+		if err := showCredentials(args, writeProfile, regionCode); err != nil {
 			logging.LogError("Failed to show credentials: %v", err)
 			logging.LogInfo("Usage: ztictl auth creds [profile-name]")
 			os.Exit(1)
@@ -137,12 +209,35 @@ If no profile is specified, uses the current AWS_PROFILE or default profile.`,
 	},
 }
 
-// performLogin handles the authentication login logic and returns errors instead of calling os.Exit
-func performLogin(profileName string) error {
-	authManager := auth.NewManager()
-	ctx := context.Background()
+// loginOptionsFromFlags builds the login options from the auth login flags. The profile region is
+// passed through as given and resolved by performLogin.
+func loginOptionsFromFlags(cmd *cobra.Command, template *config.ProfileTemplate) (auth.LoginOptions, error) {
+	opts := auth.LoginOptions{Template: template}
+	opts.ProfileRegion, _ = cmd.Flags().GetString("profile-region")
+	opts.NoBrowser, _ = cmd.Flags().GetBool("no-browser")
+	opts.Browser, _ = cmd.Flags().GetString("browser")
+	opts.Browser = strings.TrimSpace(opts.Browser)
+	opts.QR, _ = cmd.Flags().GetBool("qr")
+	if opts.NoBrowser && opts.Browser != "" {
+		return opts, fmt.Errorf("--no-browser and --browser cannot be used together")
+	}
+	return opts, nil
+}
+
+// performLogin handles the authentication login logic and returns errors instead of calling os.Exit.
+// A non-nil opts.Template selects the account and role instead of the interactive pickers, and a
+// non-empty opts.ProfileRegion (shortcode or region name) is written to the profile instead of the
+// template's region or default_region.
+func performLogin(profileName string, opts auth.LoginOptions) error {
+	if err := validateRegionInput(opts.ProfileRegion); err != nil {
+		return fmt.Errorf("--profile-region: %w", err)
+	}
+	if opts.ProfileRegion != "" {
+		opts.ProfileRegion = resolveRegion(opts.ProfileRegion)
+	}
 
-	if err := authManager.Login(ctx, profileName); err != nil {
+	authManager := auth.NewManager()
+	if err := authManager.LoginWithOptions(commandContext(), profileName, opts); err != nil {
 		return fmt.Errorf("authentication failed for profile %s: %w", profileName, err)
 	}
 
@@ -151,32 +246,61 @@ func performLogin(profileName string) error {
 }
 
 // performLogout handles the authentication logout logic and returns errors instead of calling os.Exit
-func performLogout(profileName string) error {
+func performLogout(profileName string, opts auth.LogoutOptions) error {
+	if opts.All && profileName != "" {
+		return fmt.Errorf("specify either a profile or --all, not both")
+	}
+
 	authManager := auth.NewManager()
-	ctx := context.Background()
+	ctx := commandContext()
 
-	if err := authManager.Logout(ctx, profileName); err != nil {
+	files, err := authManager.Logout(ctx, profileName, opts)
+	if err != nil {
 		return fmt.Errorf("logout failed: %w", err)
 	}
 
+	if opts.DryRun {
+		if len(files) == 0 {
+			colors.PrintData("No cached SSO tokens would be removed\n")
+			return nil
+		}
+		colors.PrintHeader("Would remove %d cached SSO token(s):\n", len(files))
+		for _, file := range files {
+			colors.PrintData("  %s\n", file)
+		}
+		return nil
+	}
+
+	if len(files) == 0 {
+		logging.LogInfo("No cached SSO tokens found; already logged out")
+		return nil
+	}
 	if profileName != "" {
-		logging.LogSuccess("Logout successful for profile: %s", profileName)
+		logging.LogSuccess("Logout successful for profile: %s (removed %d cached token(s))", profileName, len(files))
 	} else {
-		logging.LogSuccess("Logout successful for all profiles")
+		logging.LogSuccess("Logout successful (removed %d cached token(s))", len(files))
 	}
 	return nil
 }
 
 // listAuthProfiles handles the profile listing logic and returns errors instead of calling os.Exit
-func listAuthProfiles() error {
+func listAuthProfiles(jsonOutput, onlyValid bool) error {
 	authManager := auth.NewManager()
-	ctx := context.Background()
+	ctx := commandContext()
 
 	profiles, err := authManager.ListProfiles(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list profiles: %w", err)
 	}
 
+	if onlyValid {
+		profiles = filterAuthenticatedProfiles(profiles)
+	}
+
+	if jsonOutput {
+		return printProfilesJSON(os.Stdout, profiles)
+	}
+
 	if len(profiles) == 0 {
 		logging.LogInfo("No AWS profiles found")
 		return nil
@@ -205,13 +329,44 @@ func listAuthProfiles() error {
 			fmt.Printf("  Role: ")
 			_, _ = colors.Data.Printf("%s\n", profile.RoleName) // #nosec G104
 		}
+		if profile.ExpiresAt != nil {
+			fmt.Printf("  Expires: ")
+			_, _ = colors.Data.Printf("%s\n", profile.ExpiresAt.Local().Format(time.RFC3339)) // #nosec G104
+		}
 		fmt.Println()
 	}
 	return nil
 }
 
-// showCredentials handles the credential display logic and returns errors instead of calling os.Exit
-func showCredentials(args []string) error {
+// filterAuthenticatedProfiles returns only the profiles with a valid session
+func filterAuthenticatedProfiles(profiles []auth.Profile) []auth.Profile {
+	filtered := make([]auth.Profile, 0, len(profiles))
+	for _, profile := range profiles {
+		if profile.IsAuthenticated {
+			filtered = append(filtered, profile)
+		}
+	}
+	return filtered
+}
+
+// printProfilesJSON writes profiles as an indented JSON array
+func printProfilesJSON(w io.Writer, profiles []auth.Profile) error {
+	if profiles == nil {
+		profiles = []auth.Profile{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(profiles); err != nil {
+		return fmt.Errorf("failed to encode profiles as JSON: %w", err)
+	}
+	return nil
+}
+
+// showCredentials handles the credential display logic and returns errors instead of calling os.Exit.
+// With writeProfile set the credentials are saved to the shared credentials file instead of printed.
+// A regionCode replaces the profile's region in the printed exports.
+func showCredentials(args []string, writeProfile, regionCode string) error {
 	var profileName string
 	if len(args) > 0 {
 		profileName = args[0]
@@ -222,9 +377,17 @@ func showCredentials(args []string) error {
 			return fmt.Errorf("no profile specified. Usage: ztictl auth creds [profile-name]")
 		}
 	}
+	// A static profile with the SSO profile's name would shadow it once the credentials expire
+	if writeProfile != "" && writeProfile == profileName {
+		return fmt.Errorf("--write-profile must differ from the SSO profile %s", profileName)
+	}
+	// The credentials file has no region setting, so the override would be silently dropped
+	if writeProfile != "" && regionCode != "" {
+		return fmt.Errorf("--region cannot be used with --write-profile")
+	}
 
 	authManager := auth.NewManager()
-	ctx := context.Background()
+	ctx := commandContext()
 
 	creds, err := authManager.GetCredentials(ctx, profileName)
 	if err != nil {
@@ -233,6 +396,19 @@ func showCredentials(args []string) error {
 		return fmt.Errorf("failed to get credentials for profile %s: %w", profileName, err)
 	}
 
+	if writeProfile != "" {
+		return writeCredentialsProfile(authManager, profileName, writeProfile, creds)
+	}
+
+	if regionCode != "" {
+		creds.Region = resolveRegion(regionCode)
+	}
+	printCredentials(profileName, creds)
+	return nil
+}
+
+// printCredentials prints credentials as environment variable assignments for the current platform's shells
+func printCredentials(profileName string, creds *auth.Credentials) {
 	fmt.Printf("\n")
 	colors.PrintHeader("🔑 AWS Credentials for profile: %s\n", profileName)
 	colors.PrintHeader("----------------------------------------\n")
@@ -248,6 +424,7 @@ func showCredentials(args []string) error {
 			colors.PrintData("set AWS_SESSION_TOKEN=%s\n", creds.SessionToken)
 		}
 		colors.PrintData("set AWS_REGION=%s\n", creds.Region)
+		colors.PrintData("set AWS_DEFAULT_REGION=%s\n", creds.Region)
 
 		colors.PrintHeader("\nFor PowerShell:\n")
 		colors.PrintData("$env:AWS_ACCESS_KEY_ID=\"%s\"\n", creds.AccessKeyID)
@@ -256,6 +433,7 @@ func showCredentials(args []string) error {
 			colors.PrintData("$env:AWS_SESSION_TOKEN=\"%s\"\n", creds.SessionToken)
 		}
 		colors.PrintData("$env:AWS_REGION=\"%s\"\n", creds.Region)
+		colors.PrintData("$env:AWS_DEFAULT_REGION=\"%s\"\n", creds.Region)
 
 	default:
 		// Unix/Linux/macOS instructions
@@ -265,10 +443,28 @@ func showCredentials(args []string) error {
 			colors.PrintData("export AWS_SESSION_TOKEN=%s\n", creds.SessionToken)
 		}
 		colors.PrintData("export AWS_REGION=%s\n", creds.Region)
+		colors.PrintData("export AWS_DEFAULT_REGION=%s\n", creds.Region)
 		colors.PrintHeader("----------------------------------------\n")
 		fmt.Printf("To use these credentials in your current shell, run:\n")
 		colors.PrintSuccess("eval $(ztictl auth creds %s)\n", profileName)
 	}
+}
+
+// writeCredentialsProfile saves credentials as a static profile and warns when they expire
+func writeCredentialsProfile(authManager *auth.Manager, profileName, writeProfile string, creds *auth.Credentials) error {
+	path, err := authManager.WriteCredentialsProfile(writeProfile, creds)
+	if err != nil {
+		return fmt.Errorf("failed to write profile %s: %w", writeProfile, err)
+	}
+
+	colors.PrintSuccess("✓ Wrote credentials for %s to profile [%s] in %s\n", profileName, writeProfile, path)
+	if creds.ExpiresAt != nil {
+		colors.PrintWarning("⚠ These credentials expire at %s (in %s). Run 'ztictl auth creds %s --write-profile %s' again to refresh them.\n",
+			creds.ExpiresAt.Local().Format(time.RFC1123), format.Duration(time.Until(*creds.ExpiresAt).Round(time.Minute)), profileName, writeProfile)
+	} else {
+		colors.PrintWarning("⚠ These are temporary credentials. Run 'ztictl auth creds %s --write-profile %s' again once they expire.\n", profileName, writeProfile)
+	}
+	fmt.Printf("Use them with: AWS_PROFILE=%s <command>\n", writeProfile)
 	return nil
 }
 
@@ -278,4 +474,19 @@ func init() {
 	authCmd.AddCommand(authLogoutCmd)
 	authCmd.AddCommand(authProfilesCmd)
 	authCmd.AddCommand(authCredsCmd)
+
+	authLoginCmd.Flags().String("profile-template", "", "Configure the profile from this template in ~/.ztictl.yaml instead of selecting an account and role")
+	authLoginCmd.Flags().String("profile-region", "", "Region written to the profile (shortcode or region name), instead of the template's region or default_region")
+	authLoginCmd.Flags().Bool("no-browser", false, "Print the verification URL and code without opening a browser, e.g. over SSH (same as sso.no_browser)")
+	authLoginCmd.Flags().String("browser", "", "Browser binary to open the verification URL with, instead of the system default (overrides sso.browser)")
+	authLoginCmd.Flags().Bool("qr", false, "Also show the verification URL as a QR code to scan with a phone")
+
+	authLogoutCmd.Flags().Bool("all", false, "Remove every cached SSO token, not just the one for the profile's start URL")
+	authLogoutCmd.Flags().Bool("dry-run", false, "Show which cached token files would be removed without deleting them")
+
+	authCredsCmd.Flags().String("write-profile", "", "Write the credentials to ~/.aws/credentials as this profile instead of printing them")
+	addRegionFlag(authCredsCmd)
+
+	authProfilesCmd.Flags().Bool("json", false, "Output profiles as JSON")
+	authProfilesCmd.Flags().Bool("only-valid", false, "Only show profiles with a valid (unexpired) session")
 }
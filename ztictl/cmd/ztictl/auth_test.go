@@ -3,12 +3,17 @@ package main
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"os"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"ztictl/internal/auth"
 	"ztictl/internal/testutil"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -652,3 +657,166 @@ func TestMockAuthManagerBehavior(t *testing.T) {
 		t.Error("Mock credentials should have region")
 	}
 }
+
+func TestFilterAuthenticatedProfiles(t *testing.T) {
+	profiles := []auth.Profile{
+		{Name: "dev", IsAuthenticated: true},
+		{Name: "prod", IsAuthenticated: false},
+		{Name: "staging", IsAuthenticated: true},
+	}
+
+	filtered := filterAuthenticatedProfiles(profiles)
+	if len(filtered) != 2 {
+		t.Fatalf("Expected 2 authenticated profiles, got %d", len(filtered))
+	}
+	for _, profile := range filtered {
+		if !profile.IsAuthenticated {
+			t.Errorf("Profile %s should not be included", profile.Name)
+		}
+	}
+
+	if got := filterAuthenticatedProfiles(nil); len(got) != 0 {
+		t.Errorf("Expected empty result for nil input, got %d", len(got))
+	}
+}
+
+func TestPrintProfilesJSON(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	profiles := []auth.Profile{
+		{Name: "dev", IsAuthenticated: true, AccountID: "123456789012", ExpiresAt: &expiresAt},
+		{Name: "prod"},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := printProfilesJSON(buf, profiles); err != nil {
+		t.Fatalf("printProfilesJSON() error = %v", err)
+	}
+
+	var decoded []auth.Profile
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if len(decoded) != 2 {
+		t.Fatalf("Expected 2 profiles, got %d", len(decoded))
+	}
+	if decoded[0].ExpiresAt == nil || !decoded[0].ExpiresAt.Equal(expiresAt) {
+		t.Errorf("Expected expires_at %v, got %v", expiresAt, decoded[0].ExpiresAt)
+	}
+	if decoded[1].ExpiresAt != nil {
+		t.Error("Profile without cached token should omit expires_at")
+	}
+
+	buf.Reset()
+	if err := printProfilesJSON(buf, nil); err != nil {
+		t.Fatalf("printProfilesJSON() error = %v", err)
+	}
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Expected empty JSON array, got %q", buf.String())
+	}
+}
+
+func TestShowCredentialsRejectsWritingOverSourceProfile(t *testing.T) {
+	err := showCredentials([]string{"dev"}, "dev", "")
+	if err == nil || !strings.Contains(err.Error(), "--write-profile must differ") {
+		t.Errorf("Expected writing over the SSO profile to be rejected, got %v", err)
+	}
+}
+
+func TestShowCredentialsRejectsRegionWithWriteProfile(t *testing.T) {
+	err := showCredentials([]string{"dev"}, "dev-static", "use1")
+	if err == nil || !strings.Contains(err.Error(), "--region cannot be used with --write-profile") {
+		t.Errorf("Expected --region with --write-profile to be rejected, got %v", err)
+	}
+}
+
+func TestPrintCredentialsExportsRegion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Checks the Unix export format")
+	}
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	printCredentials("dev", &auth.Credentials{AccessKeyID: "AKIA", SecretAccessKey: "secret", Region: "us-east-1"})
+
+	output := buf.String()
+	for _, want := range []string{"export AWS_REGION=us-east-1\n", "export AWS_DEFAULT_REGION=us-east-1\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "AWS_SESSION_TOKEN") {
+		t.Error("Expected no session token export without a token")
+	}
+}
+
+func TestAuthCredsHasRegionFlag(t *testing.T) {
+	if authCredsCmd.Flags().Lookup("region") == nil {
+		t.Error("Expected --region on auth creds")
+	}
+}
+
+func TestAuthLoginArgsWithProfileTemplate(t *testing.T) {
+	cmd := &cobra.Command{Use: "login", Args: authLoginCmd.Args}
+	cmd.Flags().String("profile-template", "", "")
+
+	if err := cmd.Args(cmd, []string{}); err == nil {
+		t.Error("Expected a profile name to be required without --profile-template")
+	}
+	if err := cmd.Flags().Set("profile-template", "prod-admin"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Args(cmd, []string{}); err != nil {
+		t.Errorf("Expected the profile name to be optional with --profile-template, got %v", err)
+	}
+	if err := cmd.Args(cmd, []string{"p1", "p2"}); err == nil {
+		t.Error("Expected two profile names to be rejected")
+	}
+}
+
+func TestLoginOptionsFromFlags(t *testing.T) {
+	for _, name := range []string{"no-browser", "browser", "qr"} {
+		if authLoginCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected --%s on auth login", name)
+		}
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		wantNoBrowser bool
+		wantBrowser   string
+		wantQR        bool
+		wantErr       bool
+	}{
+		{name: "defaults"},
+		{name: "no browser", args: []string{"--no-browser"}, wantNoBrowser: true},
+		{name: "browser path", args: []string{"--browser", " /usr/bin/firefox "}, wantBrowser: "/usr/bin/firefox"},
+		{name: "qr code", args: []string{"--no-browser", "--qr"}, wantNoBrowser: true, wantQR: true},
+		{name: "both", args: []string{"--no-browser", "--browser", "firefox"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "login"}
+			cmd.Flags().String("profile-region", "", "")
+			cmd.Flags().Bool("no-browser", false, "")
+			cmd.Flags().String("browser", "", "")
+			cmd.Flags().Bool("qr", false, "")
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			opts, err := loginOptionsFromFlags(cmd, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected --no-browser with --browser to be rejected")
+				}
+				return
+			}
+			if err != nil || opts.NoBrowser != tt.wantNoBrowser || opts.Browser != tt.wantBrowser || opts.QR != tt.wantQR {
+				t.Errorf("loginOptionsFromFlags() = %+v, %v", opts, err)
+			}
+		})
+	}
+}
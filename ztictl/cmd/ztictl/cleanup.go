@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 
 	"ztictl/internal/ssm"
@@ -15,13 +14,15 @@ var cleanupCmd = &cobra.Command{
 	Long: `Clean up temporary IAM policies, S3 objects, and other resources
 created during file transfer operations. This includes:
 
-- Removing stale IAM policies attached for S3 access
-- Cleaning up old registry entries
-- Removing stale lock files
+- Detaching and deleting ZTIaws-SSM-S3-Access-* IAM policies older than 6 hours
+- Deleting files staged in the region's transfer bucket older than 6 hours
 
+Resources of transfers that are still running are left alone.
 Use this command if file transfer operations were interrupted
-and temporary resources were not cleaned up automatically.`,
-	Args: cobra.ExactArgs(1),
+and temporary resources were not cleaned up automatically.
+The same check runs before the first large transfer of each command.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateRegionArgs(firstRegionArg),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		region := args[0]
 
@@ -31,7 +32,7 @@ and temporary resources were not cleaned up automatically.`,
 
 		ssmManager := ssm.NewManager(logger)
 
-		ctx := context.Background()
+		ctx := commandContext()
 
 		logger.Info("Starting cleanup operation", "region", region)
 
@@ -49,16 +50,16 @@ var emergencyCleanupCmd = &cobra.Command{
 	Use:   "emergency-cleanup [region]",
 	Short: "Perform emergency cleanup of all temporary resources",
 	Long: `Perform emergency cleanup of all temporary resources created by ztictl.
-This is a more aggressive cleanup that:
+This is a more aggressive cleanup that, regardless of age:
 
-- Removes all IAM policies created by ztictl for S3 access
-- Cleans up all registry entries regardless of age
-- Removes all lock files
-- Attempts to detach policies from instance roles
+- Detaches all IAM policies created by ztictl for S3 access from instance roles
+- Deletes those policies
+- Deletes all files staged in the region's transfer bucket
 
-Use this command if normal cleanup fails or if you need to
-ensure all temporary resources are removed.`,
-	Args: cobra.ExactArgs(1),
+Transfers running at the same time will fail. Use this command if normal
+cleanup fails or if you need to ensure all temporary resources are removed.`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateRegionArgs(firstRegionArg),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		region := args[0]
 
@@ -68,7 +69,7 @@ ensure all temporary resources are removed.`,
 
 		ssmManager := ssm.NewManager(logger)
 
-		ctx := context.Background()
+		ctx := commandContext()
 
 		logger.Info("Starting emergency cleanup operation", "region", region)
 
@@ -8,8 +8,10 @@ import (
 	"strings"
 
 	"ztictl/internal/config"
+	"ztictl/internal/metrics"
 	"ztictl/internal/system"
 	"ztictl/pkg/aws"
+	"ztictl/pkg/format"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -83,6 +85,12 @@ var configShowCmd = &cobra.Command{
 		fmt.Printf("\nSSO Configuration:\n")
 		fmt.Printf("  Start URL: %s\n", cfg.SSO.StartURL)
 		fmt.Printf("  Region: %s\n", cfg.SSO.Region)
+		fmt.Printf("  Login Timeout: %d-%d seconds\n", cfg.SSO.MinTimeout, cfg.SSO.MaxTimeout)
+		fmt.Printf("  Encrypted Token Cache: %t\n", cfg.SSO.EncryptTokenCache)
+		fmt.Printf("  Open Browser: %t\n", !cfg.SSO.NoBrowser)
+		if cfg.SSO.Browser != "" {
+			fmt.Printf("  Browser: %s\n", cfg.SSO.Browser)
+		}
 
 		fmt.Printf("\nDefaults:\n")
 		fmt.Printf("  Default Region: %s\n", cfg.DefaultRegion)
@@ -94,9 +102,30 @@ var configShowCmd = &cobra.Command{
 
 		fmt.Printf("\nSystem:\n")
 		fmt.Printf("  IAM Propagation Delay: %d seconds\n", cfg.System.IAMPropagationDelay)
-		fmt.Printf("  File Size Threshold: %d bytes\n", cfg.System.FileSizeThreshold)
+		fmt.Printf("  File Size Threshold: %s\n", format.Bytes(cfg.System.FileSizeThreshold))
 		fmt.Printf("  S3 Bucket Prefix: %s\n", cfg.System.S3BucketPrefix)
 		fmt.Printf("  Temp Directory: %s\n", cfg.System.TempDirectory)
+		fmt.Printf("  S3 Part Size: %d MiB\n", cfg.System.S3PartSizeMB)
+		fmt.Printf("  S3 Concurrency: %d\n", cfg.System.S3Concurrency)
+		fmt.Printf("  S3 Lifecycle: %d day(s)\n", cfg.System.S3LifecycleDays)
+		fmt.Printf("  Default Parallel: %d\n", cfg.System.DefaultParallel)
+		fmt.Printf("  Instance Cache TTL: %d seconds\n", cfg.System.InstanceCacheTTL)
+		fmt.Printf("  Wait Polling: every %d seconds, up to %d seconds\n", cfg.System.WaitPollInterval, cfg.System.WaitTimeout)
+		if cfg.System.SessionManagerPluginPath != "" {
+			fmt.Printf("  Session Manager Plugin: %s\n", cfg.System.SessionManagerPluginPath)
+		}
+		fmt.Printf("  Dualstack Endpoints: %v\n", cfg.System.UseDualStackEndpoint)
+		fmt.Printf("  FIPS Endpoints: %v\n", cfg.System.UseFIPSEndpoint)
+
+		fmt.Printf("\nMetrics:\n")
+		fmt.Printf("  Sink: %s\n", cfg.Metrics.Sink)
+		switch cfg.Metrics.Sink {
+		case metrics.SinkFile:
+			fmt.Printf("  Path: %s\n", cfg.Metrics.Path)
+		case metrics.SinkStatsd, metrics.SinkDogStatsd:
+			fmt.Printf("  Address: %s\n", cfg.Metrics.StatsdAddress)
+			fmt.Printf("  Prefix: %s\n", cfg.Metrics.Prefix)
+		}
 
 		// Display file path
 		home, err := os.UserHomeDir()
@@ -543,6 +572,12 @@ func repairConfiguration() error {
 	fmt.Printf("The %s has an invalid value: '%s'\n", valErr.Field, valErr.Value)
 	fmt.Printf("Error: %s\n\n", valErr.Message)
 
+	// Unknown keys and mistyped values are reported by key path and have to be edited by hand
+	if !isRepairableField(valErr.Field) {
+		fmt.Printf("Edit ~/.ztictl.yaml to fix %s, then run 'ztictl config validate'\n", valErr.Field)
+		return fmt.Errorf("%s cannot be repaired interactively", valErr.Field)
+	}
+
 	// Prompt for interactive fix
 	fmt.Println("Would you like to fix this interactively? (yes/no)")
 	reader := bufio.NewReader(os.Stdin)
@@ -670,6 +705,15 @@ func repairConfiguration() error {
 	return nil
 }
 
+// isRepairableField reports whether config repair can prompt for a new value for a validation error field
+func isRepairableField(field string) bool {
+	switch field {
+	case "SSO region", "Default region", "SSO start URL":
+		return true
+	}
+	return false
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configInitCmd)
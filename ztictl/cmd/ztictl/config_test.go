@@ -269,3 +269,16 @@ func TestValidateConfiguration(t *testing.T) {
 		t.Log("Test completed - function returned instead of calling os.Exit")
 	})
 }
+
+func TestIsRepairableField(t *testing.T) {
+	for _, field := range []string{"SSO region", "Default region", "SSO start URL"} {
+		if !isRepairableField(field) {
+			t.Errorf("Expected %q to be repairable", field)
+		}
+	}
+	for _, field := range []string{"system.file_size_threshold", "sso.start_ulr", "Default tag key"} {
+		if isRepairableField(field) {
+			t.Errorf("Expected %q to require a manual edit", field)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+)
+
+var (
+	// cliContext is the root context of the running command, bounded by --deadline when set
+	cliContext context.Context
+
+	// cancelDeadline releases the --deadline timer; it is a no-op until a deadline is applied
+	cancelDeadline context.CancelFunc = func() {}
+)
+
+// commandContext returns the context that AWS calls and worker pools should use.
+// It falls back to context.Background() when no command is running (e.g. in tests).
+func commandContext() context.Context {
+	if cliContext == nil {
+		return context.Background()
+	}
+	return cliContext
+}
+
+// applyDeadline bounds ctx by the given wall-clock budget (0 disables it)
+// and makes the result the command context.
+func applyDeadline(ctx context.Context, budget time.Duration) context.Context {
+	if budget > 0 {
+		ctx, cancelDeadline = context.WithTimeout(ctx, budget)
+	}
+	cliContext = ctx
+	return ctx
+}
+
+// deadlineExceeded reports whether the global --deadline has expired
+func deadlineExceeded() bool {
+	return errors.Is(commandContext().Err(), context.DeadlineExceeded)
+}
+
+// isCancelled reports whether err was caused by context cancellation or deadline expiry
+func isCancelled(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// stopReason names what ended ctx early, for the partial completion report
+func stopReason(ctx context.Context) string {
+	if errors.Is(context.Cause(ctx), ssm.ErrBatchTimeout) {
+		return "Batch timeout reached"
+	}
+	return "Deadline exceeded"
+}
+
+// printDeadlineReport lists which targets completed and which were cancelled when reason stopped the run
+func printDeadlineReport(reason string, completed, cancelled []string) {
+	if len(cancelled) == 0 {
+		return
+	}
+
+	colors.PrintWarning("\n⚠ %s: %d target(s) completed, %d cancelled\n", reason, len(completed), len(cancelled))
+	if len(completed) > 0 {
+		colors.PrintData("Completed: %s\n", strings.Join(completed, ", "))
+	}
+	colors.PrintData("Cancelled: %s\n", strings.Join(cancelled, ", "))
+}
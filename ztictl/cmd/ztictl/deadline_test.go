@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"ztictl/internal/interactive"
+)
+
+// resetCommandContext restores the package-level command context after a test
+func resetCommandContext(t *testing.T) {
+	t.Helper()
+	origCtx, origCancel := cliContext, cancelDeadline
+	t.Cleanup(func() {
+		cancelDeadline()
+		cliContext, cancelDeadline = origCtx, origCancel
+	})
+}
+
+func TestDeadlineFlagsRegistered(t *testing.T) {
+	for _, name := range []string{"deadline", "max-duration"} {
+		flag := rootCmd.PersistentFlags().Lookup(name)
+		if flag == nil {
+			t.Fatalf("Expected persistent flag --%s to be registered", name)
+		}
+		if flag.DefValue != "0s" {
+			t.Errorf("Expected --%s to default to 0s, got %s", name, flag.DefValue)
+		}
+	}
+}
+
+func TestCommandContextFallback(t *testing.T) {
+	resetCommandContext(t)
+	cliContext = nil
+
+	if ctx := commandContext(); ctx == nil || ctx.Err() != nil {
+		t.Errorf("Expected a live background context, got %v", ctx)
+	}
+	if deadlineExceeded() {
+		t.Error("Expected no deadline to be exceeded without a command context")
+	}
+}
+
+func TestApplyDeadline(t *testing.T) {
+	t.Run("zero budget leaves context unbounded", func(t *testing.T) {
+		resetCommandContext(t)
+
+		ctx := applyDeadline(context.Background(), 0)
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("Expected no deadline for a zero budget")
+		}
+		if commandContext() != ctx {
+			t.Error("Expected applied context to become the command context")
+		}
+	})
+
+	t.Run("budget expires the command context", func(t *testing.T) {
+		resetCommandContext(t)
+
+		ctx := applyDeadline(context.Background(), 10*time.Millisecond)
+		if _, ok := ctx.Deadline(); !ok {
+			t.Fatal("Expected a deadline to be set")
+		}
+
+		<-ctx.Done()
+		if !deadlineExceeded() {
+			t.Error("Expected deadlineExceeded() to report the expired budget")
+		}
+	})
+}
+
+func TestIsCancelled(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{context.DeadlineExceeded, true},
+		{fmt.Errorf("failed to check command status: %w", context.DeadlineExceeded), true},
+		{context.Canceled, true},
+		{errors.New("AccessDenied"), false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := isCancelled(tt.err); got != tt.want {
+			t.Errorf("isCancelled(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestExecuteCommandParallelSkipsWorkAfterDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	instances := []interactive.Instance{{InstanceID: "i-1"}, {InstanceID: "i-2"}, {InstanceID: "i-3"}}
+
+	// The manager is never touched once the context is done
+	results := executeCommandParallel(ctx, nil, instances, "ca-central-1", "uptime", 2, execOptions{})
+	if len(results) != len(instances) {
+		t.Fatalf("Expected a result for every instance, got %d", len(results))
+	}
+	for _, result := range results {
+		if !isCancelled(result.Error) {
+			t.Errorf("Expected %s to be reported as cancelled, got %v", result.Instance.InstanceID, result.Error)
+		}
+	}
+}
+
+func TestSplitDeadlineTargets(t *testing.T) {
+	results := []MultiRegionResult{
+		{
+			Region: "cac1",
+			Instances: []InstanceResult{
+				{Instance: interactive.Instance{InstanceID: "i-done"}, Success: true},
+				{Instance: interactive.Instance{InstanceID: "i-failed"}, ExitCode: 1},
+				{Instance: interactive.Instance{InstanceID: "i-slow"}, Error: context.DeadlineExceeded},
+			},
+		},
+		{Region: "use1", Error: fmt.Errorf("failed to list instances: %w", context.DeadlineExceeded)},
+	}
+
+	completed, cancelled := splitDeadlineTargets([]string{"cac1", "use1", "euw1"}, results)
+
+	wantCompleted := []string{"cac1/i-done", "cac1/i-failed"}
+	wantCancelled := []string{"cac1/i-slow", "use1", "euw1 (not started)"}
+
+	if fmt.Sprint(completed) != fmt.Sprint(wantCompleted) {
+		t.Errorf("completed = %v, want %v", completed, wantCompleted)
+	}
+	if fmt.Sprint(cancelled) != fmt.Sprint(wantCancelled) {
+		t.Errorf("cancelled = %v, want %v", cancelled, wantCancelled)
+	}
+}
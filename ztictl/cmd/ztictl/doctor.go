@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"ztictl/internal/config"
+	"ztictl/internal/ssm"
+	awspkg "ztictl/pkg/aws"
+	"ztictl/pkg/colors"
+
+	"github.com/spf13/cobra"
+)
+
+// doctorSTSTimeout bounds the STS connectivity probe
+const doctorSTSTimeout = 10 * time.Second
+
+// doctorStatus is the outcome of a single doctor check
+type doctorStatus int
+
+const (
+	doctorPass doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+// doctorCheck is a single line of the doctor checklist
+type doctorCheck struct {
+	Name   string
+	Status doctorStatus
+	Detail string
+	Hint   string
+}
+
+// doctorEnv holds the environment probes used by the doctor checks, so tests can replace them
+type doctorEnv struct {
+	lookPath    func(file string) (string, error)
+	configPath  string
+	loadConfig  func() (*config.ConfigValidationError, error)
+	ssoCacheDir string
+	callerARN   func(ctx context.Context) (string, error)
+}
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the local environment for common setup problems",
+	Long: `Check that ztictl's prerequisites are in place and print a checklist with remediation hints.
+
+Checks:
+  - AWS CLI is installed and on PATH
+  - session-manager-plugin is installed and on PATH (or in system.session_manager_plugin_path)
+  - ~/.ztictl.yaml exists and passes validation
+  - The AWS SSO token cache is readable
+  - AWS STS is reachable with the current credentials
+
+Exits with a non-zero status if a hard prerequisite (AWS CLI, plugin or configuration) is missing.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := runDoctorChecks(commandContext(), defaultDoctorEnv())
+		if !printDoctorReport(os.Stdout, checks) {
+			os.Exit(1)
+		}
+	},
+}
+
+// defaultDoctorEnv returns the doctor environment backed by the real system
+func defaultDoctorEnv() doctorEnv {
+	home, _ := os.UserHomeDir()
+
+	configPath := configFile
+	if configPath == "" {
+		configPath = filepath.Join(home, ".ztictl.yaml")
+	}
+
+	return doctorEnv{
+		lookPath:    exec.LookPath,
+		configPath:  configPath,
+		loadConfig:  func() (*config.ConfigValidationError, error) { return config.LoadWithOptions(true) },
+		ssoCacheDir: filepath.Join(home, ".aws", "sso", "cache"),
+		callerARN: func(ctx context.Context) (string, error) {
+			client, err := awspkg.NewClient(ctx, awspkg.ClientOptions{Region: config.Get().DefaultRegion})
+			if err != nil {
+				return "", err
+			}
+			identity, err := client.GetCallerIdentity(ctx)
+			if err != nil {
+				return "", err
+			}
+			return *identity.Arn, nil
+		},
+	}
+}
+
+// runDoctorChecks runs every doctor check in checklist order
+func runDoctorChecks(ctx context.Context, env doctorEnv) []doctorCheck {
+	return []doctorCheck{
+		checkDoctorBinary(env, "AWS CLI", "aws",
+			"Install the AWS CLI: https://docs.aws.amazon.com/cli/latest/userguide/getting-started-install.html"),
+		checkDoctorBinary(env, "Session Manager plugin", "session-manager-plugin",
+			ssm.SessionManagerPluginInstallHint()+"\nIf it is installed outside PATH, set system.session_manager_plugin_path"),
+		checkDoctorConfig(env),
+		checkDoctorSSOCache(env),
+		checkDoctorSTS(ctx, env),
+	}
+}
+
+// checkDoctorBinary verifies that a required executable resolves on PATH
+func checkDoctorBinary(env doctorEnv, name, binary, hint string) doctorCheck {
+	if runtime.GOOS == "windows" {
+		binary += ".exe"
+	}
+
+	path, err := env.lookPath(binary)
+	if err != nil {
+		return doctorCheck{Name: name, Status: doctorFail, Detail: fmt.Sprintf("%s not found on PATH", binary), Hint: hint}
+	}
+	return doctorCheck{Name: name, Status: doctorPass, Detail: path}
+}
+
+// checkDoctorConfig verifies that the configuration file exists and validates
+func checkDoctorConfig(env doctorEnv) doctorCheck {
+	check := doctorCheck{Name: "Configuration"}
+
+	if _, err := os.Stat(env.configPath); err != nil {
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("%s not found", env.configPath)
+		check.Hint = "Run 'ztictl config init' to create it"
+		return check
+	}
+
+	valErr, err := env.loadConfig()
+	switch {
+	case valErr != nil:
+		check.Status = doctorFail
+		check.Detail = valErr.Error()
+		check.Hint = "Run 'ztictl config repair' to fix invalid values"
+	case err != nil:
+		check.Status = doctorFail
+		check.Detail = err.Error()
+		check.Hint = "Run 'ztictl config validate' for details"
+	default:
+		check.Status = doctorPass
+		check.Detail = env.configPath
+	}
+	return check
+}
+
+// checkDoctorSSOCache verifies that the AWS SSO token cache can be read.
+// A missing cache is only a warning since IAM-based authentication does not use it.
+func checkDoctorSSOCache(env doctorEnv) doctorCheck {
+	check := doctorCheck{Name: "SSO token cache"}
+
+	entries, err := os.ReadDir(env.ssoCacheDir)
+	switch {
+	case os.IsNotExist(err):
+		check.Status = doctorWarn
+		check.Detail = fmt.Sprintf("%s does not exist", env.ssoCacheDir)
+		check.Hint = "Run 'ztictl auth login' to authenticate with AWS SSO"
+	case err != nil:
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("cannot read %s: %v", env.ssoCacheDir, err)
+		check.Hint = fmt.Sprintf("Check the ownership and permissions of %s", env.ssoCacheDir)
+	default:
+		check.Status = doctorPass
+		check.Detail = fmt.Sprintf("%s (%d cached file(s))", env.ssoCacheDir, len(entries))
+	}
+	return check
+}
+
+// checkDoctorSTS verifies connectivity to AWS STS with the current credentials
+func checkDoctorSTS(ctx context.Context, env doctorEnv) doctorCheck {
+	ctx, cancel := context.WithTimeout(ctx, doctorSTSTimeout)
+	defer cancel()
+
+	arn, err := env.callerARN(ctx)
+	if err != nil {
+		return doctorCheck{
+			Name:   "AWS STS connectivity",
+			Status: doctorWarn,
+			Detail: err.Error(),
+			Hint:   "Authenticate with 'ztictl auth login', or check network access to sts.amazonaws.com",
+		}
+	}
+	return doctorCheck{Name: "AWS STS connectivity", Status: doctorPass, Detail: arn}
+}
+
+// printDoctorReport prints the checklist and returns false if any hard prerequisite failed
+func printDoctorReport(w io.Writer, checks []doctorCheck) bool {
+	_, _ = fmt.Fprintf(w, "%s\n", colors.ColorHeader("ztictl doctor"))
+
+	passed, warnings, failures := 0, 0, 0
+	for _, check := range checks {
+		var marker string
+		switch check.Status {
+		case doctorPass:
+			marker = colors.ColorSuccess("✓ PASS")
+			passed++
+		case doctorWarn:
+			marker = colors.ColorWarning("⚠ WARN")
+			warnings++
+		default:
+			marker = colors.ColorError("✗ FAIL")
+			failures++
+		}
+
+		_, _ = fmt.Fprintf(w, "  %s  %s: %s\n", marker, check.Name, check.Detail)
+		if check.Hint != "" {
+			// Continuation lines of multi-line hints line up under the first
+			_, _ = fmt.Fprintf(w, "          💡 %s\n", strings.ReplaceAll(check.Hint, "\n", "\n             "))
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%d passed, %d warning(s), %d failed\n", passed, warnings, failures)
+	return failures == 0
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ztictl/internal/config"
+)
+
+// healthyDoctorEnv returns a doctor environment in which every check passes
+func healthyDoctorEnv(t *testing.T) doctorEnv {
+	t.Helper()
+	dir := t.TempDir()
+
+	configPath := filepath.Join(dir, ".ztictl.yaml")
+	if err := os.WriteFile(configPath, []byte("default_region: ca-central-1\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := filepath.Join(dir, "sso", "cache")
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	return doctorEnv{
+		lookPath:    func(file string) (string, error) { return "/usr/local/bin/" + file, nil },
+		configPath:  configPath,
+		loadConfig:  func() (*config.ConfigValidationError, error) { return nil, nil },
+		ssoCacheDir: cacheDir,
+		callerARN: func(ctx context.Context) (string, error) {
+			return "arn:aws:sts::123456789012:assumed-role/Dev/user", nil
+		},
+	}
+}
+
+func findDoctorCheck(t *testing.T, checks []doctorCheck, name string) doctorCheck {
+	t.Helper()
+	for _, check := range checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("Check %q not found", name)
+	return doctorCheck{}
+}
+
+func TestRunDoctorChecksHealthy(t *testing.T) {
+	checks := runDoctorChecks(context.Background(), healthyDoctorEnv(t))
+
+	if len(checks) != 5 {
+		t.Fatalf("Expected 5 checks, got %d", len(checks))
+	}
+	for _, check := range checks {
+		if check.Status != doctorPass {
+			t.Errorf("Expected %s to pass, got status %d (%s)", check.Name, check.Status, check.Detail)
+		}
+	}
+
+	var out bytes.Buffer
+	if !printDoctorReport(&out, checks) {
+		t.Error("Expected report to succeed when all checks pass")
+	}
+	if !strings.Contains(out.String(), "5 passed, 0 warning(s), 0 failed") {
+		t.Errorf("Unexpected report summary:\n%s", out.String())
+	}
+}
+
+func TestRunDoctorChecksMissingBinaries(t *testing.T) {
+	env := healthyDoctorEnv(t)
+	env.lookPath = func(file string) (string, error) { return "", errors.New("executable file not found in $PATH") }
+
+	checks := runDoctorChecks(context.Background(), env)
+
+	for _, name := range []string{"AWS CLI", "Session Manager plugin"} {
+		check := findDoctorCheck(t, checks, name)
+		if check.Status != doctorFail || check.Hint == "" {
+			t.Errorf("Expected %s to fail with a hint, got %+v", name, check)
+		}
+	}
+
+	if printDoctorReport(&bytes.Buffer{}, checks) {
+		t.Error("Expected report to fail when a hard prerequisite is missing")
+	}
+}
+
+func TestCheckDoctorConfig(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		env := healthyDoctorEnv(t)
+		env.configPath = filepath.Join(t.TempDir(), "missing.yaml")
+
+		check := checkDoctorConfig(env)
+		if check.Status != doctorFail || !strings.Contains(check.Hint, "config init") {
+			t.Errorf("Expected missing config to fail with init hint, got %+v", check)
+		}
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		env := healthyDoctorEnv(t)
+		env.loadConfig = func() (*config.ConfigValidationError, error) {
+			return &config.ConfigValidationError{Field: "SSO region", Value: "nowhere", Message: "invalid AWS region format"}, nil
+		}
+
+		check := checkDoctorConfig(env)
+		if check.Status != doctorFail || !strings.Contains(check.Hint, "config repair") {
+			t.Errorf("Expected invalid config to fail with repair hint, got %+v", check)
+		}
+	})
+}
+
+func TestDoctorSoftChecksOnlyWarn(t *testing.T) {
+	env := healthyDoctorEnv(t)
+	env.ssoCacheDir = filepath.Join(t.TempDir(), "no-cache")
+	env.callerARN = func(ctx context.Context) (string, error) { return "", errors.New("no credentials") }
+
+	checks := runDoctorChecks(context.Background(), env)
+
+	if check := findDoctorCheck(t, checks, "SSO token cache"); check.Status != doctorWarn {
+		t.Errorf("Expected missing SSO cache to warn, got %+v", check)
+	}
+	if check := findDoctorCheck(t, checks, "AWS STS connectivity"); check.Status != doctorWarn {
+		t.Errorf("Expected STS failure to warn, got %+v", check)
+	}
+	if !printDoctorReport(&bytes.Buffer{}, checks) {
+		t.Error("Expected warnings alone not to fail the report")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"ztictl/internal/config"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+
+	"github.com/spf13/cobra"
+)
+
+// archiveTimeFormat names archived reports by their UTC start time, so they sort chronologically
+const archiveTimeFormat = "20060102T150405Z"
+
+// addArchiveFlag registers --archive-s3 for exec commands
+func addArchiveFlag(cmd *cobra.Command) {
+	cmd.Flags().String("archive-s3", "", "Upload the JSON report of every run to this S3 location (s3://bucket/prefix), named with the start time and run ID")
+}
+
+// resolveArchiveS3 reads and validates --archive-s3; empty disables archiving
+func resolveArchiveS3(cmd *cobra.Command) (string, error) {
+	if cmd.Flags().Lookup("archive-s3") == nil {
+		return "", nil
+	}
+	uri, _ := cmd.Flags().GetString("archive-s3")
+	uri = strings.TrimSpace(uri)
+	if uri == "" {
+		return "", nil
+	}
+	if _, _, err := ssm.ParseS3URI(uri); err != nil {
+		return "", fmt.Errorf("invalid --archive-s3: %w", err)
+	}
+	return uri, nil
+}
+
+// archiveKey returns the object key of an archived report: <prefix>/<start time>-<run id>.json
+func archiveKey(prefix string, report *execReport) string {
+	name := fmt.Sprintf("%s-%s.json", report.StartedAt.UTC().Format(archiveTimeFormat), report.RunID)
+	if prefix == "" {
+		return name
+	}
+	return prefix + "/" + name
+}
+
+// archiveReport uploads the finished report as JSON to --archive-s3, whatever the --output format.
+// It runs even after --deadline expired so the record of a partial run is kept.
+func (o execOptions) archiveReport(report *execReport) error {
+	if o.ArchiveS3 == "" {
+		return nil
+	}
+	bucket, prefix, err := ssm.ParseS3URI(o.ArchiveS3)
+	if err != nil {
+		return fmt.Errorf("invalid --archive-s3: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, report, o.JSONStyle == jsonStylePretty); err != nil {
+		return err
+	}
+
+	// The S3 client of the run's first region finds the bucket, wherever it lives
+	region := config.Get().DefaultRegion
+	if len(report.Regions) > 0 {
+		region = resolveRegion(report.Regions[0])
+	}
+	key := archiveKey(prefix, report)
+	ctx := context.WithoutCancel(commandContext())
+	if err := ssm.NewManager(logger).ArchiveObject(ctx, region, bucket, key, "application/json", buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to archive report: %w", err)
+	}
+	colors.PrintData("Report archived to s3://%s/%s\n", bucket, key)
+	return nil
+}
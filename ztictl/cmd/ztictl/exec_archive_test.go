@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestResolveArchiveS3(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("archive-s3") == nil {
+			t.Errorf("Expected --archive-s3 on %s", cmd.Name())
+		}
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{name: "disabled", args: nil},
+		{name: "bucket and prefix", args: []string{"--archive-s3", " s3://audit-logs/ztictl/exec "}, want: "s3://audit-logs/ztictl/exec"},
+		{name: "bucket only", args: []string{"--archive-s3", "s3://audit-logs"}, want: "s3://audit-logs"},
+		{name: "not an s3 uri", args: []string{"--archive-s3", "audit-logs/ztictl"}, wantErr: "invalid --archive-s3"},
+		{name: "invalid bucket", args: []string{"--archive-s3", "s3://Audit_Logs/x"}, wantErr: "invalid S3 bucket name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			addArchiveFlag(cmd)
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := resolveArchiveS3(cmd)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveArchiveS3() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveKey(t *testing.T) {
+	startedAt := time.Date(2026, 3, 14, 9, 26, 53, 0, time.FixedZone("EST", -5*60*60))
+	report := &execReport{RunID: "3f2b8c1e-5d4a-4b6f-9e7d-1a2b3c4d5e6f", StartedAt: startedAt}
+
+	if got, want := archiveKey("ztictl/exec", report), "ztictl/exec/20260314T142653Z-3f2b8c1e-5d4a-4b6f-9e7d-1a2b3c4d5e6f.json"; got != want {
+		t.Errorf("archiveKey() = %q, want %q", got, want)
+	}
+	if got := archiveKey("", report); got != "20260314T142653Z-3f2b8c1e-5d4a-4b6f-9e7d-1a2b3c4d5e6f.json" {
+		t.Errorf("Expected no leading slash without a prefix, got %q", got)
+	}
+}
+
+func TestWantsReportForArchive(t *testing.T) {
+	if (execOptions{}).wantsReport() {
+		t.Error("Expected no report by default")
+	}
+	if !(execOptions{ArchiveS3: "s3://audit-logs"}).wantsReport() {
+		t.Error("Expected --archive-s3 to build a report even with text output")
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"ztictl/internal/config"
+	"ztictl/pkg/format"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// chunkPreviewLines is how many lines of a chunked output are shown from its start and from its end
+	chunkPreviewLines = 10
+	// chunkPreviewLineLength cuts long preview lines, so a single huge line cannot flood the terminal
+	chunkPreviewLineLength = 500
+)
+
+// addChunkOutputFlags registers --chunk-output and --log-dir
+func addChunkOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().String("chunk-output", "", "Write instance output larger than this size (e.g. 64KiB, 1MiB) to a file and print a head/tail preview")
+	cmd.Flags().String("log-dir", "", "Directory for output files written by --chunk-output (default: <logging.directory>/exec-output)")
+}
+
+// resolveChunkOutput reads --chunk-output and --log-dir; a zero threshold disables chunking
+func resolveChunkOutput(cmd *cobra.Command) (int64, string, error) {
+	value, _ := cmd.Flags().GetString("chunk-output")
+	logDir, _ := cmd.Flags().GetString("log-dir")
+
+	threshold, err := parseByteSize(value)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid --chunk-output '%s': %w", value, err)
+	}
+	if threshold == 0 {
+		if logDir != "" {
+			return 0, "", fmt.Errorf("--log-dir requires --chunk-output")
+		}
+		return 0, "", nil
+	}
+
+	if logDir == "" {
+		logDir = filepath.Join(config.Get().Logging.Directory, "exec-output")
+	}
+	return threshold, logDir, nil
+}
+
+// parseByteSize parses a size such as 65536, 64K, 64KiB or 1MB; K and M are binary (1024) units
+func parseByteSize(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	upper := strings.ToUpper(value)
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffixes []string
+		size     int64
+	}{
+		{[]string{"GIB", "GB", "G"}, 1 << 30},
+		{[]string{"MIB", "MB", "M"}, 1 << 20},
+		{[]string{"KIB", "KB", "K"}, 1 << 10},
+		{[]string{"B"}, 1},
+	} {
+		found := false
+		for _, suffix := range unit.suffixes {
+			if number, ok := strings.CutSuffix(upper, suffix); ok {
+				upper, multiplier, found = strings.TrimSpace(number), unit.size, true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+
+	size, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil || size <= 0 || size > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("expected a positive size such as 65536, 64KiB or 1MiB")
+	}
+	return size * multiplier, nil
+}
+
+// chunkedOutput returns an instance's stdout or stderr as it should be printed in text output. Output over
+// the --chunk-output threshold is written to a file under --log-dir and replaced by a head/tail preview.
+// Reports written with --output or --output-file always carry the full output.
+func (o execOptions) chunkedOutput(instanceID, stream, output string) string {
+	if o.ChunkOutput <= 0 || int64(len(output)) <= o.ChunkOutput {
+		return output
+	}
+
+	path, err := o.writeOutputChunk(instanceID, stream, output)
+	if err != nil {
+		logging.LogWarn("Could not save the %s of %s to a file, printing it in full: %v", stream, instanceID, err)
+		return output
+	}
+	return outputPreview(output, path)
+}
+
+// writeOutputChunk saves output to <log-dir>/<run-id>/<instance-id>.<stream>.log, readable only by the user
+func (o execOptions) writeOutputChunk(instanceID, stream, output string) (string, error) {
+	dir := filepath.Join(o.LogDir, o.RunID)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%s.%s.log", instanceID, stream))
+	if err := os.WriteFile(path, []byte(output), 0600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// outputPreview renders the first and last lines of output around a note pointing at the saved file
+func outputPreview(output, path string) string {
+	lines := outputLines(output)
+	head, tail := lines, []string(nil)
+	if len(lines) > 2*chunkPreviewLines {
+		head, tail = lines[:chunkPreviewLines], lines[len(lines)-chunkPreviewLines:]
+	}
+
+	var b strings.Builder
+	for _, line := range head {
+		b.WriteString(previewLine(line))
+		b.WriteByte('\n')
+	}
+	if omitted := len(lines) - len(head) - len(tail); omitted > 0 {
+		fmt.Fprintf(&b, "... %d line(s) not shown; full output (%s) saved to %s ...", omitted, format.Bytes(int64(len(output))), path)
+	} else {
+		fmt.Fprintf(&b, "... full output (%s) saved to %s ...", format.Bytes(int64(len(output))), path)
+	}
+	for _, line := range tail {
+		b.WriteByte('\n')
+		b.WriteString(previewLine(line))
+	}
+	return b.String()
+}
+
+// previewLine cuts a line to at most chunkPreviewLineLength bytes without splitting a UTF-8 character
+func previewLine(line string) string {
+	if len(line) <= chunkPreviewLineLength {
+		return line
+	}
+	end := chunkPreviewLineLength
+	for end > 0 && !utf8.RuneStart(line[end]) {
+		end--
+	}
+	return line[:end] + "…"
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "65536", want: 65536},
+		{value: "64K", want: 64 << 10},
+		{value: "64kib", want: 64 << 10},
+		{value: "1MB", want: 1 << 20},
+		{value: " 2 MiB ", want: 2 << 20},
+		{value: "1G", want: 1 << 30},
+		{value: "512B", want: 512},
+		{value: "0", wantErr: true},
+		{value: "-1K", wantErr: true},
+		{value: "1.5M", wantErr: true},
+		{value: "lots", wantErr: true},
+		{value: "99999999999G", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseByteSize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseByteSize(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseByteSize(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChunkedOutput(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	output := strings.Join(lines, "\n") + "\n"
+	opts := execOptions{ChunkOutput: 100, LogDir: t.TempDir(), RunID: "run-1"}
+
+	if got := (execOptions{}).chunkedOutput("i-1", "stdout", output); got != output {
+		t.Error("Expected output unchanged without --chunk-output")
+	}
+	if got := opts.chunkedOutput("i-1", "stdout", "short"); got != "short" {
+		t.Errorf("Expected output under the threshold unchanged, got %q", got)
+	}
+
+	preview := opts.chunkedOutput("i-1", "stdout", output)
+	path := filepath.Join(opts.LogDir, "run-1", "i-1.stdout.log")
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the full output in %s: %v", path, err)
+	}
+	if string(saved) != output {
+		t.Error("Saved file does not hold the full output")
+	}
+
+	previewLines := strings.Split(preview, "\n")
+	if len(previewLines) != 2*chunkPreviewLines+1 {
+		t.Fatalf("Expected %d preview lines, got %d:\n%s", 2*chunkPreviewLines+1, len(previewLines), preview)
+	}
+	if previewLines[0] != "line 1" || previewLines[len(previewLines)-1] != "line 100" {
+		t.Errorf("Expected the preview to keep the first and last lines, got:\n%s", preview)
+	}
+	note := previewLines[chunkPreviewLines]
+	if !strings.Contains(note, "80 line(s) not shown") || !strings.Contains(note, path) {
+		t.Errorf("Expected the note to count omitted lines and name the file, got %q", note)
+	}
+}
+
+func TestOutputPreviewCutsLongLines(t *testing.T) {
+	preview := outputPreview(strings.Repeat("é", chunkPreviewLineLength), "/tmp/out.log")
+	first := strings.SplitN(preview, "\n", 2)[0]
+	if !strings.HasSuffix(first, "…") || len(first) > chunkPreviewLineLength+len("…") {
+		t.Errorf("Expected the line cut to %d bytes, got %d bytes", chunkPreviewLineLength, len(first))
+	}
+	if !strings.Contains(preview, "saved to /tmp/out.log") {
+		t.Errorf("Expected the note to name the file, got %q", preview)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+	"ztictl/pkg/security"
+
+	"github.com/spf13/cobra"
+)
+
+// addCollectFlags registers --collect and --collect-dir
+func addCollectFlags(cmd *cobra.Command) {
+	cmd.Flags().String("collect", "", "After the command succeeds on an instance, download this remote file into a directory named after the instance ID")
+	cmd.Flags().String("collect-dir", ".", "Local directory for --collect, with one subdirectory per instance ID (must be inside the current directory)")
+}
+
+// resolveCollect reads --collect and --collect-dir; an empty remote path disables collection
+func resolveCollect(cmd *cobra.Command) (remotePath, localDir string, err error) {
+	if cmd.Flags().Lookup("collect") == nil {
+		return "", "", nil
+	}
+	remotePath, _ = cmd.Flags().GetString("collect")
+	remotePath = strings.TrimSpace(remotePath)
+	if remotePath == "" {
+		return "", "", nil
+	}
+	if remoteBaseName(remotePath) == "" {
+		return "", "", fmt.Errorf("invalid --collect '%s' (expected the path of a file, not a directory)", remotePath)
+	}
+
+	localDir, _ = cmd.Flags().GetString("collect-dir")
+	if err := security.ValidateFilePathWithWorkingDir(localDir); err != nil {
+		return "", "", fmt.Errorf("invalid --collect-dir: %w", err)
+	}
+	if info, err := os.Stat(localDir); err == nil && !info.IsDir() {
+		return "", "", fmt.Errorf("invalid --collect-dir: %s is a file", localDir)
+	}
+	return remotePath, localDir, nil
+}
+
+// remoteBaseName returns the file name of a Linux or Windows remote path, or empty when it ends in a separator
+func remoteBaseName(remotePath string) string {
+	return remotePath[strings.LastIndexAny(remotePath, `/\`)+1:]
+}
+
+// collectPath returns where the --collect file of an instance is written: <collect-dir>/<instance-id>/<file name>
+func (o execOptions) collectPath(instanceID string) string {
+	return filepath.Join(o.CollectDir, instanceID, remoteBaseName(o.Collect))
+}
+
+// collectFile downloads the --collect file from an instance the command succeeded on. A failed download is
+// recorded on the result and printed, but does not fail the instance.
+func collectFile(ctx context.Context, ssmManager *ssm.Manager, region string, opts execOptions, result *ParallelExecutionResult) {
+	if opts.Collect == "" || !result.succeeded() {
+		return
+	}
+
+	instanceID := result.Instance.InstanceID
+	localPath := opts.collectPath(instanceID)
+	logging.LogInfo("Collecting %s from instance %s to %s", opts.Collect, instanceID, localPath)
+	if err := os.MkdirAll(filepath.Dir(localPath), 0700); err != nil {
+		result.CollectError = fmt.Errorf("failed to create %s: %w", filepath.Dir(localPath), err)
+	} else if err := ssmManager.DownloadFile(ctx, instanceID, region, opts.Collect, localPath); err != nil {
+		result.CollectError = err
+	} else {
+		result.Collected = localPath
+		return
+	}
+	colors.PrintWarning("⚠ Failed to collect %s from %s: %v\n", opts.Collect, instanceID, result.CollectError)
+}
+
+// withCollect adds the outcome of --collect to a report entry
+func withCollect(entry execReportInstance, result ParallelExecutionResult) execReportInstance {
+	entry.Collected = result.Collected
+	if result.CollectError != nil {
+		entry.CollectError = result.CollectError.Error()
+	}
+	return entry
+}
+
+// printCollectSummary prints how many instances the --collect file was downloaded from
+func printCollectSummary(opts execOptions, results []ParallelExecutionResult) {
+	if opts.Collect == "" {
+		return
+	}
+	var collected, failed int
+	for _, result := range results {
+		switch {
+		case result.Collected != "":
+			collected++
+		case result.CollectError != nil:
+			failed++
+		}
+	}
+	if failed > 0 {
+		colors.PrintWarning("Collected %s from %d instance(s) into %s; %d failed\n", opts.Collect, collected, opts.CollectDir, failed)
+		return
+	}
+	colors.PrintData("Collected %s from %d instance(s) into %s\n", opts.Collect, collected, opts.CollectDir)
+}
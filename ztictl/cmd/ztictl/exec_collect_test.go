@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+
+	"github.com/spf13/cobra"
+)
+
+func TestResolveCollect(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd} {
+		if cmd.Flags().Lookup("collect") == nil || cmd.Flags().Lookup("collect-dir") == nil {
+			t.Errorf("Expected --collect and --collect-dir flags on %s", cmd.Name())
+		}
+	}
+
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+	if err := os.WriteFile("notes.txt", []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantRemote string
+		wantDir    string
+		wantErr    string
+	}{
+		{name: "disabled", args: nil},
+		{name: "default directory", args: []string{"--collect", "/tmp/report.txt"}, wantRemote: "/tmp/report.txt", wantDir: "."},
+		{name: "custom directory", args: []string{"--collect", `C:\Temp\report.txt`, "--collect-dir", "out"}, wantRemote: `C:\Temp\report.txt`, wantDir: "out"},
+		{name: "directory path", args: []string{"--collect", "/var/log/"}, wantErr: "invalid --collect"},
+		{name: "outside the working directory", args: []string{"--collect", "/tmp/report.txt", "--collect-dir", "../elsewhere"}, wantErr: "invalid --collect-dir"},
+		{name: "collect dir is a file", args: []string{"--collect", "/tmp/report.txt", "--collect-dir", "notes.txt"}, wantErr: "is a file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			addCollectFlags(cmd)
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			remote, localDir, err := resolveCollect(cmd)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || remote != tt.wantRemote || localDir != tt.wantDir {
+				t.Errorf("resolveCollect() = %q, %q, %v; want %q, %q", remote, localDir, err, tt.wantRemote, tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestCollectPath(t *testing.T) {
+	tests := []struct {
+		remote string
+		want   string
+	}{
+		{remote: "/tmp/report.txt", want: filepath.Join("out", "i-0abc", "report.txt")},
+		{remote: `C:\Temp\report.txt`, want: filepath.Join("out", "i-0abc", "report.txt")},
+		{remote: "report.txt", want: filepath.Join("out", "i-0abc", "report.txt")},
+	}
+
+	for _, tt := range tests {
+		opts := execOptions{Collect: tt.remote, CollectDir: "out"}
+		if got := opts.collectPath("i-0abc"); got != tt.want {
+			t.Errorf("collectPath() for %q = %q, want %q", tt.remote, got, tt.want)
+		}
+	}
+}
+
+func TestCollectFileSkipsFailedCommands(t *testing.T) {
+	exitCode := int32(2)
+	opts := execOptions{Collect: "/tmp/report.txt", CollectDir: t.TempDir()}
+	results := []ParallelExecutionResult{
+		{Instance: interactive.Instance{InstanceID: "i-failed"}, Result: &ssm.CommandResult{ExitCode: &exitCode}},
+		{Instance: interactive.Instance{InstanceID: "i-error"}, Error: errors.New("send failed")},
+	}
+
+	for i := range results {
+		// A nil manager would panic if a download were attempted
+		collectFile(context.Background(), nil, "us-east-1", opts, &results[i])
+		if results[i].Collected != "" || results[i].CollectError != nil {
+			t.Errorf("Expected nothing collected from %s, got %+v", results[i].Instance.InstanceID, results[i])
+		}
+	}
+}
+
+func TestWithCollect(t *testing.T) {
+	entry := withCollect(execReportInstance{InstanceID: "i-1"}, ParallelExecutionResult{Collected: "out/i-1/report.txt"})
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"collected":"out/i-1/report.txt"`) || strings.Contains(string(data), "collect_error") {
+		t.Errorf("Unexpected report entry %s", data)
+	}
+
+	entry = withCollect(execReportInstance{InstanceID: "i-2"}, ParallelExecutionResult{CollectError: errors.New("file not found")})
+	if entry.Collected != "" || entry.CollectError != "file not found" {
+		t.Errorf("Expected the collect error in the report, got %+v", entry)
+	}
+
+	data, _ = json.Marshal(withCollect(execReportInstance{InstanceID: "i-3"}, ParallelExecutionResult{}))
+	if strings.Contains(string(data), "collect") {
+		t.Errorf("Expected no collect fields without --collect, got %s", data)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"ztictl/pkg/security"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// commandFileStdin is the --command-file value that reads the command from standard input
+	commandFileStdin = "-"
+	// maxCommandFileSize caps --command-file so the command stays within SSM request limits
+	maxCommandFileSize = 64 * 1024
+)
+
+// addCommandFileFlag registers --command-file for exec commands
+func addCommandFileFlag(cmd *cobra.Command) {
+	cmd.Flags().String("command-file", "", "Read the command from a file (or - for stdin) instead of the command argument; it runs inline like a typed command")
+}
+
+// execArgs validates positional arguments for exec commands. Without --command-file or --command the
+// command is the last argument after at least minTargets region/instance arguments; with either, the
+// command argument is omitted, so between minTargets and maxTargets arguments are accepted. --count-only
+// sends nothing, so the command argument is optional there.
+func execArgs(minTargets, maxTargets int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if countOnly, _ := cmd.Flags().GetBool("count-only"); countOnly {
+			return cobra.MinimumNArgs(minTargets)(cmd, args)
+		}
+		if source := commandSource(cmd); source != "" {
+			if len(args) > maxTargets {
+				return fmt.Errorf("%s replaces the command argument; got %d unexpected argument(s)", source, len(args)-maxTargets)
+			}
+			return cobra.MinimumNArgs(minTargets)(cmd, args)
+		}
+		return cobra.MinimumNArgs(minTargets+1)(cmd, args)
+	}
+}
+
+// commandSource returns the flag that replaces the command argument, --command-file or --command,
+// or "" when the command is an argument
+func commandSource(cmd *cobra.Command) string {
+	if commandFile, _ := cmd.Flags().GetString("command-file"); commandFile != "" {
+		return "--command-file"
+	}
+	if len(commandSteps(cmd)) > 0 {
+		return "--command"
+	}
+	return ""
+}
+
+// withCommandFile appends the --command-file contents, or the --command steps joined for display, to
+// args as the command argument. args is returned unchanged when neither flag is set.
+func withCommandFile(cmd *cobra.Command, args []string) ([]string, error) {
+	commandFile, _ := cmd.Flags().GetString("command-file")
+	if steps := commandSteps(cmd); len(steps) > 0 {
+		if commandFile != "" {
+			return nil, fmt.Errorf("--command and --command-file cannot be combined")
+		}
+		steps, continueOnError, err := resolveCommandSteps(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return append(append([]string{}, args...), stepsCommand(steps, continueOnError)), nil
+	}
+	if commandFile == "" {
+		return args, nil
+	}
+
+	command, err := readCommandFile(commandFile, cmd.InOrStdin())
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]string{}, args...), command), nil
+}
+
+// readCommandFile reads a command body from path, or from stdin when path is "-".
+// Windows line endings are normalized and trailing whitespace is trimmed.
+func readCommandFile(path string, stdin io.Reader) (string, error) {
+	var reader io.Reader
+	if path == commandFileStdin {
+		reader = stdin
+	} else {
+		if err := security.ValidateFilePathWithWorkingDir(path); err != nil {
+			return "", fmt.Errorf("invalid --command-file: %w", err)
+		}
+		// #nosec G304 - path is validated above using security.ValidateFilePathWithWorkingDir()
+		file, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to open --command-file: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+		reader = file
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, maxCommandFileSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read --command-file: %w", err)
+	}
+	if len(data) > maxCommandFileSize {
+		return "", fmt.Errorf("--command-file is larger than %d KiB; copy large scripts with 'ztictl ssm transfer' and run them instead", maxCommandFileSize/1024)
+	}
+
+	command := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), " \t\r\n")
+	if strings.TrimSpace(command) == "" {
+		return "", fmt.Errorf("--command-file is empty")
+	}
+	return command, nil
+}
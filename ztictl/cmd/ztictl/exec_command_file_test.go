@@ -0,0 +1,122 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestReadCommandFile(t *testing.T) {
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+
+	if err := os.WriteFile("steps.sh", []byte("cd /opt/app\r\ngit pull\r\nsystemctl restart app\r\n\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("empty.sh", []byte("  \n\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("huge.sh", []byte(strings.Repeat("x", maxCommandFileSize+1)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		stdin   string
+		want    string
+		wantErr string
+	}{
+		{name: "file", path: "steps.sh", want: "cd /opt/app\ngit pull\nsystemctl restart app"},
+		{name: "stdin", path: "-", stdin: "echo 'it''s \"quoted\"'\nuptime\n", want: "echo 'it''s \"quoted\"'\nuptime"},
+		{name: "empty", path: "empty.sh", wantErr: "is empty"},
+		{name: "too large", path: "huge.sh", wantErr: "larger than"},
+		{name: "missing", path: "missing.sh", wantErr: "failed to open"},
+		{name: "outside working directory", path: "../../etc/passwd", wantErr: "invalid --command-file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readCommandFile(tt.path, strings.NewReader(tt.stdin))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readCommandFile() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExecArgsWithCommandFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		minTargets  int
+		maxTargets  int
+		commandFile string
+		args        []string
+		wantErr     bool
+	}{
+		{name: "command argument required", minTargets: 1, maxTargets: 1, args: []string{"cac1"}, wantErr: true},
+		{name: "command argument given", minTargets: 1, maxTargets: 1, args: []string{"cac1", "uptime"}},
+		{name: "file replaces command", minTargets: 1, maxTargets: 1, commandFile: "steps.sh", args: []string{"cac1"}},
+		{name: "file and command argument", minTargets: 1, maxTargets: 1, commandFile: "steps.sh", args: []string{"cac1", "uptime"}, wantErr: true},
+		{name: "file without targets", minTargets: 0, maxTargets: 2, commandFile: "-"},
+		{name: "file missing region", minTargets: 1, maxTargets: 1, commandFile: "-", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			addCommandFileFlag(cmd)
+			_ = cmd.Flags().Set("command-file", tt.commandFile)
+
+			err := execArgs(tt.minTargets, tt.maxTargets)(cmd, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("execArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithCommandFile(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	addCommandFileFlag(cmd)
+
+	args := []string{"cac1", "i-1234567890abcdef0"}
+	got, err := withCommandFile(cmd, args)
+	if err != nil || len(got) != 2 {
+		t.Fatalf("Expected args unchanged without --command-file, got %v (%v)", got, err)
+	}
+
+	_ = cmd.Flags().Set("command-file", "-")
+	cmd.SetIn(strings.NewReader("uptime\ndf -h\n"))
+	got, err = withCommandFile(cmd, args)
+	if err != nil {
+		t.Fatalf("withCommandFile() error = %v", err)
+	}
+	if len(got) != 3 || got[2] != "uptime\ndf -h" {
+		t.Errorf("Expected command appended as last argument, got %q", got)
+	}
+	if len(args) != 2 {
+		t.Errorf("Expected original args to be untouched, got %q", args)
+	}
+
+	for _, c := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if c.Flags().Lookup("command-file") == nil {
+			t.Errorf("Expected --command-file flag on %s", c.Name())
+		}
+	}
+}
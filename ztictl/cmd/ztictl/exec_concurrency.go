@@ -0,0 +1,104 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// throttleBurstWindow groups throttling errors that arrive close together into one burst,
+// so that a wave of in-flight failures only halves the concurrency once
+const throttleBurstWindow = 2 * time.Second
+
+// adaptiveConcurrency limits how many exec workers run at once. It halves the limit when SSM
+// throttles requests and raises it by one after a run of successes, staying within [min, max].
+type adaptiveConcurrency struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	min, max int
+	limit    int // Current number of workers allowed to run
+	active   int // Workers currently running
+
+	successes     int       // Consecutive unthrottled completions since the last change
+	lastDecrease  time.Time // When the limit was last lowered
+	throttleCount int       // Total throttled completions, for the end-of-run summary
+
+	now func() time.Time
+}
+
+// newAdaptiveConcurrency creates a controller that starts at max and never drops below min
+func newAdaptiveConcurrency(minLimit, maxLimit int) *adaptiveConcurrency {
+	if maxLimit < 1 {
+		maxLimit = 1
+	}
+	if minLimit < 1 {
+		minLimit = 1
+	}
+	if minLimit > maxLimit {
+		minLimit = maxLimit
+	}
+
+	c := &adaptiveConcurrency{min: minLimit, max: maxLimit, limit: maxLimit, now: time.Now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// acquire blocks until the current limit allows another worker to run
+func (c *adaptiveConcurrency) acquire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.active >= c.limit {
+		c.cond.Wait()
+	}
+	c.active++
+}
+
+// release frees a worker slot and adjusts the limit based on whether the work was throttled
+func (c *adaptiveConcurrency) release(throttled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.active--
+
+	if throttled {
+		c.throttleCount++
+		c.successes = 0
+		if c.limit > c.min && c.now().Sub(c.lastDecrease) >= throttleBurstWindow {
+			c.limit = max(c.min, c.limit/2)
+			c.lastDecrease = c.now()
+			logging.LogWarn("SSM is throttling requests, reducing concurrency to %d", c.limit)
+		}
+	} else if c.limit < c.max {
+		// Recover gradually: one more worker after as many successes as are currently allowed to run
+		c.successes++
+		if c.successes >= c.limit {
+			c.limit++
+			c.successes = 0
+			logging.LogDebug("No recent throttling, raising concurrency to %d", c.limit)
+		}
+	}
+
+	c.cond.Broadcast()
+}
+
+// current returns the current concurrency limit
+func (c *adaptiveConcurrency) current() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.limit
+}
+
+// throttled returns how many completions were throttled during the run
+func (c *adaptiveConcurrency) throttled() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.throttleCount
+}
+
+// addMinParallelFlag registers --min-parallel, the floor for adaptive concurrency; --parallel is the ceiling
+func addMinParallelFlag(cmd *cobra.Command) {
+	cmd.Flags().Int("min-parallel", 1, "Minimum concurrent executions when SSM throttling forces ztictl to back off (--parallel is the maximum)")
+}
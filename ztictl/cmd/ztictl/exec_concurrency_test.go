@@ -0,0 +1,141 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAdaptiveConcurrencyBounds(t *testing.T) {
+	tests := []struct {
+		min, max         int
+		wantMin, wantMax int
+	}{
+		{min: 1, max: 8, wantMin: 1, wantMax: 8},
+		{min: 0, max: 4, wantMin: 1, wantMax: 4},
+		{min: 10, max: 4, wantMin: 4, wantMax: 4},
+		{min: 1, max: 0, wantMin: 1, wantMax: 1},
+	}
+
+	for _, tt := range tests {
+		c := newAdaptiveConcurrency(tt.min, tt.max)
+		if c.min != tt.wantMin || c.max != tt.wantMax || c.current() != tt.wantMax {
+			t.Errorf("newAdaptiveConcurrency(%d, %d) = min %d, max %d, limit %d; want min %d, max %d, limit %d",
+				tt.min, tt.max, c.min, c.max, c.current(), tt.wantMin, tt.wantMax, tt.wantMax)
+		}
+	}
+}
+
+func TestAdaptiveConcurrencyBacksOffAndRecovers(t *testing.T) {
+	now := time.Now()
+	c := newAdaptiveConcurrency(2, 16)
+	c.now = func() time.Time { return now }
+
+	throttle := func() {
+		c.acquire()
+		c.release(true)
+	}
+	succeed := func(n int) {
+		for i := 0; i < n; i++ {
+			c.acquire()
+			c.release(false)
+		}
+	}
+
+	throttle()
+	if got := c.current(); got != 8 {
+		t.Fatalf("Expected limit halved to 8 after throttling, got %d", got)
+	}
+
+	// Throttles within the same burst only count once
+	throttle()
+	throttle()
+	if got := c.current(); got != 8 {
+		t.Fatalf("Expected one decrease per burst, got limit %d", got)
+	}
+
+	for i := 0; i < 5; i++ {
+		now = now.Add(throttleBurstWindow)
+		throttle()
+	}
+	if got := c.current(); got != 2 {
+		t.Fatalf("Expected limit to stop at the minimum of 2, got %d", got)
+	}
+
+	// One step up after as many successes as the current limit
+	succeed(1)
+	if got := c.current(); got != 2 {
+		t.Fatalf("Expected no increase after a single success, got %d", got)
+	}
+	succeed(1)
+	if got := c.current(); got != 3 {
+		t.Fatalf("Expected limit 3 after 2 successes, got %d", got)
+	}
+
+	succeed(1000)
+	if got := c.current(); got != 16 {
+		t.Fatalf("Expected limit to recover to the maximum of 16, got %d", got)
+	}
+	if got := c.throttled(); got != 8 {
+		t.Errorf("Expected 8 throttled completions, got %d", got)
+	}
+}
+
+func TestAdaptiveConcurrencyLimitsActiveWorkers(t *testing.T) {
+	c := newAdaptiveConcurrency(1, 3)
+	c.limit = 2
+
+	var active, peak int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.acquire()
+			n := atomic.AddInt32(&active, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&active, -1)
+			c.release(true)
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("Expected at most 2 concurrent workers, got %d", peak)
+	}
+}
+
+func TestResolveExecOptionsMinParallel(t *testing.T) {
+	for _, c := range []*cobra.Command{ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if c.Flags().Lookup("min-parallel") == nil {
+			t.Errorf("Expected --min-parallel flag on %s", c.Name())
+		}
+	}
+
+	// Commands without the flag keep the default floor
+	opts, err := resolveExecOptions(&cobra.Command{Use: "test"})
+	if err != nil || opts.MinParallel != 1 {
+		t.Errorf("Expected MinParallel 1 without the flag, got %d (%v)", opts.MinParallel, err)
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	addMinParallelFlag(cmd)
+	_ = cmd.Flags().Set("min-parallel", "4")
+	if opts, err := resolveExecOptions(cmd); err != nil || opts.MinParallel != 4 {
+		t.Errorf("Expected MinParallel 4, got %d (%v)", opts.MinParallel, err)
+	}
+
+	_ = cmd.Flags().Set("min-parallel", "0")
+	if _, err := resolveExecOptions(cmd); err == nil {
+		t.Error("Expected error for --min-parallel 0")
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+
+	"github.com/spf13/cobra"
+)
+
+// addCountOnlyFlag registers --count-only for exec commands that resolve targets from tags
+func addCountOnlyFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("count-only", false, "Resolve the targets and print how many instances the command would run on, without sending it (add --list-targets to list them)")
+}
+
+// targetCount is the --count-only result for one region
+type targetCount struct {
+	Region   string `json:"region"`
+	Matched  int    `json:"matched"`
+	Excluded int    `json:"excluded"`
+	Skipped  int    `json:"skipped"`
+	Targets  int    `json:"targets"`
+	// Instances lists the targeted instance IDs with --list-targets
+	Instances []string `json:"instances,omitempty"`
+}
+
+// execSkipReason explains why an instance cannot run a command, or returns "" when it can.
+// --target-status already selected the agent status to target, so only the state is checked then.
+func execSkipReason(instance interactive.Instance, opts execOptions) string {
+	if instance.State != "running" {
+		return fmt.Sprintf("not running (state: %s)", instance.State)
+	}
+	if opts.TargetStatus == "" && instance.SSMStatus != "Online" {
+		return fmt.Sprintf("SSM agent not online (status: %s)", instance.SSMStatus)
+	}
+	return ""
+}
+
+// countTargets applies the same exclusions and state checks as an execution to the matched instances
+// and returns how many would be targeted, without sending anything
+func countTargets(ctx context.Context, ssmManager *ssm.Manager, region string, instances []interactive.Instance, opts execOptions) (targetCount, error) {
+	count := targetCount{Region: region, Matched: len(instances)}
+
+	instances, count.Excluded = excludeInstances(instances, opts.Exclude)
+	if opts.TargetStatus != "" {
+		var statusFiltered int
+		var err error
+		instances, statusFiltered, err = filterByTargetStatus(ctx, ssmManager, region, opts.TargetStatus, instances)
+		if err != nil {
+			return targetCount{}, err
+		}
+		count.Excluded += statusFiltered
+	}
+
+	for _, instance := range instances {
+		if execSkipReason(instance, opts) != "" {
+			count.Skipped++
+			continue
+		}
+		count.Targets++
+		if opts.ListTargets {
+			count.Instances = append(count.Instances, instance.InstanceID)
+		}
+	}
+	return count, nil
+}
+
+// printTargetCount writes a --count-only result in the --output format. Quiet text output is the bare
+// number, for scripts.
+func printTargetCount(w io.Writer, count targetCount, opts execOptions) error {
+	switch opts.Output {
+	case outputFormatJSON, outputFormatJSONL:
+		return writeJSON(w, count, opts.Output == outputFormatJSON && opts.prettyJSON(w))
+	case outputFormatYAML:
+		return writeYAML(w, count)
+	}
+
+	if quiet {
+		_, err := fmt.Fprintf(w, "%d\n", count.Targets)
+		return err
+	}
+	_, _ = fmt.Fprintf(w, "%d instance(s) would be targeted in %s\n", count.Targets, count.Region)
+	_, _ = fmt.Fprintf(w, "  matched: %d, excluded: %d, skipped (not running or SSM agent offline): %d\n", count.Matched, count.Excluded, count.Skipped)
+	for _, id := range count.Instances {
+		_, _ = fmt.Fprintf(w, "  %s\n", id)
+	}
+	if count.Targets == 0 {
+		colors.PrintWarning("No instances would be targeted\n")
+	}
+	return nil
+}
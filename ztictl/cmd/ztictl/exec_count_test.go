@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"ztictl/internal/interactive"
+
+	"github.com/spf13/cobra"
+)
+
+func countTestInstances() []interactive.Instance {
+	return []interactive.Instance{
+		{InstanceID: "i-0web1", Name: "web-1", State: "running", SSMStatus: "Online"},
+		{InstanceID: "i-0web2", Name: "web-2", State: "running", SSMStatus: "Online"},
+		{InstanceID: "i-0canary", Name: "canary", State: "running", SSMStatus: "Online"},
+		{InstanceID: "i-0stopped", Name: "web-3", State: "stopped", SSMStatus: "ConnectionLost"},
+		{InstanceID: "i-0lost", Name: "web-4", State: "running", SSMStatus: "ConnectionLost"},
+	}
+}
+
+func TestExecSkipReason(t *testing.T) {
+	instances := countTestInstances()
+	if reason := execSkipReason(instances[0], execOptions{}); reason != "" {
+		t.Errorf("Expected a running, online instance to be targeted, got %q", reason)
+	}
+	if reason := execSkipReason(instances[3], execOptions{}); !strings.Contains(reason, "not running") {
+		t.Errorf("Expected a stopped instance to be skipped, got %q", reason)
+	}
+	if reason := execSkipReason(instances[4], execOptions{}); !strings.Contains(reason, "SSM agent not online") {
+		t.Errorf("Expected an offline agent to be skipped, got %q", reason)
+	}
+	if reason := execSkipReason(instances[4], execOptions{TargetStatus: "ConnectionLost"}); reason != "" {
+		t.Errorf("Expected --target-status to allow the agent status, got %q", reason)
+	}
+}
+
+func TestCountTargets(t *testing.T) {
+	opts := execOptions{Exclude: []string{"canary"}, ListTargets: true}
+	count, err := countTargets(context.Background(), nil, "ca-central-1", countTestInstances(), opts)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if count.Matched != 5 || count.Excluded != 1 || count.Skipped != 2 || count.Targets != 2 {
+		t.Errorf("Unexpected count: %+v", count)
+	}
+	if strings.Join(count.Instances, ",") != "i-0web1,i-0web2" {
+		t.Errorf("Expected the targeted instance IDs, got %v", count.Instances)
+	}
+
+	count, _ = countTargets(context.Background(), nil, "ca-central-1", countTestInstances(), execOptions{})
+	if count.Targets != 3 || count.Instances != nil {
+		t.Errorf("Expected 3 targets without a list, got %+v", count)
+	}
+}
+
+func TestPrintTargetCount(t *testing.T) {
+	count := targetCount{Region: "ca-central-1", Matched: 5, Excluded: 1, Skipped: 2, Targets: 2, Instances: []string{"i-0web1", "i-0web2"}}
+
+	var buf bytes.Buffer
+	if err := printTargetCount(&buf, count, execOptions{Output: outputFormatText}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"2 instance(s) would be targeted in ca-central-1", "matched: 5, excluded: 1", "  i-0web2\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, buf.String())
+		}
+	}
+
+	originalQuiet := quiet
+	quiet = true
+	buf.Reset()
+	_ = printTargetCount(&buf, count, execOptions{Output: outputFormatText})
+	quiet = originalQuiet
+	if buf.String() != "2\n" {
+		t.Errorf("Expected the bare count in quiet mode, got %q", buf.String())
+	}
+
+	buf.Reset()
+	if err := printTargetCount(&buf, count, execOptions{Output: outputFormatJSON}); err != nil {
+		t.Fatal(err)
+	}
+	var decoded targetCount
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.Targets != 2 || decoded.Region != "ca-central-1" {
+		t.Errorf("Expected a JSON count, got %s (%v)", buf.String(), err)
+	}
+}
+
+func TestExecArgsCountOnly(t *testing.T) {
+	cmd := &cobra.Command{}
+	addCommandFileFlag(cmd)
+	addCountOnlyFlag(cmd)
+	validate := execArgs(1, 1)
+
+	if err := validate(cmd, []string{"cac1"}); err == nil {
+		t.Error("Expected the command argument to be required without --count-only")
+	}
+	if err := cmd.ParseFlags([]string{"--count-only"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := validate(cmd, []string{"cac1"}); err != nil {
+		t.Errorf("Expected the command argument to be optional with --count-only, got %v", err)
+	}
+	if err := validate(cmd, nil); err == nil {
+		t.Error("Expected the region to stay required with --count-only")
+	}
+	if ssmExecTaggedCmd.Flags().Lookup("count-only") == nil {
+		t.Error("Expected --count-only on exec-tagged")
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"ztictl/pkg/security"
+
+	"github.com/spf13/cobra"
+)
+
+// addEnvFlags registers the repeatable --env flag and --env-file
+func addEnvFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("env", nil, "Set an environment variable for the command on the instance (NAME=VALUE, repeatable; overrides --env-file)")
+	cmd.Flags().String("env-file", "", "Set environment variables for the command from a dotenv file of NAME=VALUE lines")
+}
+
+// resolveEnv reads --env-file and --env into the variables exported before the command runs.
+// A variable given with --env replaces the same variable from the file.
+func resolveEnv(cmd *cobra.Command) (map[string]string, error) {
+	env := map[string]string{}
+
+	if path, _ := cmd.Flags().GetString("env-file"); path != "" {
+		if err := security.ValidateFilePathWithWorkingDir(path); err != nil {
+			return nil, fmt.Errorf("invalid --env-file: %w", err)
+		}
+		// #nosec G304 - path is validated above using security.ValidateFilePathWithWorkingDir()
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open --env-file: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+
+		if env, err = parseEnvFile(file, path); err != nil {
+			return nil, err
+		}
+	}
+
+	values, _ := cmd.Flags().GetStringArray("env")
+	for _, value := range values {
+		name, variable, found := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !found {
+			return nil, fmt.Errorf("invalid --env '%s' (expected NAME=VALUE)", name)
+		}
+		if !envNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid environment variable name '%s' in --env", name)
+		}
+		env[name] = variable
+	}
+
+	if len(env) == 0 {
+		return nil, nil
+	}
+	return env, nil
+}
+
+// parseEnvFile parses dotenv content: NAME=VALUE lines, optionally prefixed with "export ". Blank lines
+// and lines starting with # are skipped. Values may be wrapped in single or double quotes; unquoted
+// values end at " #". Errors give the line number but never the value, which may be a secret.
+func parseEnvFile(r io.Reader, path string) (map[string]string, error) {
+	env := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		name = strings.TrimSpace(name)
+		if !found {
+			return nil, fmt.Errorf("%s:%d: expected NAME=VALUE", path, lineNumber)
+		}
+		if !envNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("%s:%d: invalid environment variable name '%s'", path, lineNumber, name)
+		}
+
+		value, err := unquoteEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+		env[name] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read --env-file: %w", err)
+	}
+	return env, nil
+}
+
+// unquoteEnvValue removes the quotes around a dotenv value. Double-quoted values support the \n, \t, \"
+// and \\ escapes; single-quoted values are taken literally.
+func unquoteEnvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch quote := value[0]; quote {
+	case '"', '\'':
+		end := strings.LastIndexByte(value, quote)
+		if end == 0 {
+			return "", fmt.Errorf("unterminated %c quote", quote)
+		}
+		if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected text after the closing %c quote", quote)
+		}
+		value = value[1:end]
+		if quote == '"' {
+			value = strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`).Replace(value)
+		}
+		return value, nil
+	}
+
+	if comment := strings.Index(value, " #"); comment >= 0 {
+		value = strings.TrimSpace(value[:comment])
+	}
+	return value, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseEnvFile(t *testing.T) {
+	content := strings.Join([]string{
+		"# deployment settings",
+		"",
+		"APP_ENV=production",
+		"export REGION = ca-central-1",
+		`GREETING="hello \"world\"\nbye"`,
+		`LITERAL='$HOME is not expanded'`,
+		"LOG_LEVEL=debug # inline comment",
+		"EMPTY=",
+		"URL=https://example.com/#anchor",
+	}, "\n")
+
+	env, err := parseEnvFile(strings.NewReader(content), ".env")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"APP_ENV":   "production",
+		"REGION":    "ca-central-1",
+		"GREETING":  "hello \"world\"\nbye",
+		"LITERAL":   "$HOME is not expanded",
+		"LOG_LEVEL": "debug",
+		"EMPTY":     "",
+		"URL":       "https://example.com/#anchor",
+	}
+	if len(env) != len(want) {
+		t.Errorf("Expected %d variables, got %v", len(want), env)
+	}
+	for name, value := range want {
+		if env[name] != value {
+			t.Errorf("Expected %s=%q, got %q", name, value, env[name])
+		}
+	}
+}
+
+func TestParseEnvFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "missing equals", content: "A=1\nNOT_A_PAIR", wantErr: ".env:2: expected NAME=VALUE"},
+		{name: "invalid name", content: "\n\n1BAD=x", wantErr: ".env:3: invalid environment variable name '1BAD'"},
+		{name: "unterminated quote", content: `TOKEN="s3cret`, wantErr: ".env:1: unterminated \" quote"},
+		{name: "text after quote", content: `TOKEN='s3cret' extra`, wantErr: ".env:1: unexpected text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseEnvFile(strings.NewReader(tt.content), ".env")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+			if strings.Contains(err.Error(), "s3cret") {
+				t.Errorf("Error must not include the value: %v", err)
+			}
+		})
+	}
+}
+
+func TestResolveEnv(t *testing.T) {
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+
+	if err := os.WriteFile("app.env", []byte("APP_ENV=staging\nLOG_LEVEL=info\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "exec"}
+		addEnvFlags(cmd)
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+
+	env, err := resolveEnv(newCmd("--env-file", "app.env", "--env", "APP_ENV=production", "--env", "EXTRA=a=b"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if env["APP_ENV"] != "production" || env["LOG_LEVEL"] != "info" || env["EXTRA"] != "a=b" {
+		t.Errorf("Expected --env to override the file, got %v", env)
+	}
+
+	if env, err := resolveEnv(newCmd()); err != nil || env != nil {
+		t.Errorf("Expected no variables without flags, got %v (%v)", env, err)
+	}
+	if _, err := resolveEnv(newCmd("--env", "NO_VALUE")); err == nil {
+		t.Error("Expected --env without '=' to be rejected")
+	}
+	if _, err := resolveEnv(newCmd("--env-file", "missing.env")); err == nil {
+		t.Error("Expected a missing --env-file to be rejected")
+	}
+}
+
+func TestEnvMergedWithParameters(t *testing.T) {
+	opts := execOptions{
+		Env:      map[string]string{"APP_ENV": "production"},
+		paramEnv: map[string]string{"DB_PASS": "secret"},
+	}
+	env := opts.ssmOptions().Env
+	if env["APP_ENV"] != "production" || env["DB_PASS"] != "secret" {
+		t.Errorf("Expected --env and --param-from-ssm variables to be merged, got %v", env)
+	}
+}
+
+func TestResolveExecOptionsEnv(t *testing.T) {
+	for _, c := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if c.Flags().Lookup("env-file") == nil || c.Flags().Lookup("env") == nil {
+			t.Errorf("Expected --env and --env-file flags on %s", c.Name())
+		}
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	addEnvFlags(cmd)
+	addParamFromSSMFlag(cmd)
+	_ = cmd.Flags().Set("env", "APP_ENV=production")
+	opts, err := resolveExecOptions(cmd)
+	if err != nil || opts.ssmOptions().Env["APP_ENV"] != "production" {
+		t.Errorf("Expected --env to reach the SSM options, got %v (%v)", opts.Env, err)
+	}
+
+	_ = cmd.Flags().Set("param-from-ssm", "APP_ENV=/app/env")
+	if _, err := resolveExecOptions(cmd); err == nil || !strings.Contains(err.Error(), "both --param-from-ssm and --env") {
+		t.Errorf("Expected a variable set by both --env and --param-from-ssm to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultErrorContext is how many trailing lines of a failed instance's error output are printed
+const defaultErrorContext = 20
+
+// addErrorContextFlag registers --error-context for exec commands
+func addErrorContextFlag(cmd *cobra.Command) {
+	cmd.Flags().Int("error-context", defaultErrorContext, "Print only the last N lines of a failed instance's error output in text output (0 prints all of it)")
+}
+
+// resolveErrorContext reads --error-context
+func resolveErrorContext(cmd *cobra.Command) (int, error) {
+	if cmd.Flags().Lookup("error-context") == nil {
+		return 0, nil
+	}
+	lines, _ := cmd.Flags().GetInt("error-context")
+	if lines < 0 {
+		return 0, fmt.Errorf("invalid --error-context %d (expected a number of lines, or 0 for all)", lines)
+	}
+	return lines, nil
+}
+
+// errorOutput returns an instance's stderr as it should be printed in text output. For a failed
+// instance only the last --error-context lines are printed; with --chunk-output the full error output
+// is saved under --log-dir, and reports written with --output or --output-file always carry all of it.
+func (o execOptions) errorOutput(instanceID, output string, failed bool) string {
+	lines := outputLines(output)
+	if !failed || o.ErrorContext <= 0 || len(lines) <= o.ErrorContext {
+		return o.chunkedOutput(instanceID, "stderr", output)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "... %d earlier line(s) not shown (--error-context %d)", len(lines)-o.ErrorContext, o.ErrorContext)
+	if o.ChunkOutput > 0 {
+		if path, err := o.writeOutputChunk(instanceID, "stderr", output); err != nil {
+			logging.LogWarn("Could not save the stderr of %s to a file: %v", instanceID, err)
+		} else {
+			fmt.Fprintf(&b, "; full error output saved to %s", path)
+		}
+	}
+	b.WriteString(" ...")
+	for _, line := range lines[len(lines)-o.ErrorContext:] {
+		b.WriteByte('\n')
+		b.WriteString(previewLine(line))
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestResolveErrorContext(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("error-context") == nil {
+			t.Errorf("Expected --error-context on %s", cmd.Name())
+		}
+	}
+
+	tests := []struct {
+		args    []string
+		want    int
+		wantErr bool
+	}{
+		{args: nil, want: defaultErrorContext},
+		{args: []string{"--error-context", "5"}, want: 5},
+		{args: []string{"--error-context", "0"}, want: 0},
+		{args: []string{"--error-context", "-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		cmd := &cobra.Command{Use: "test"}
+		addErrorContextFlag(cmd)
+		if err := cmd.Flags().Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		got, err := resolveErrorContext(cmd)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("resolveErrorContext(%v) expected an error", tt.args)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("resolveErrorContext(%v) = %d, %v; want %d", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestErrorOutput(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 50; i++ {
+		lines = append(lines, fmt.Sprintf("error line %d", i))
+	}
+	stderr := strings.Join(lines, "\n") + "\n"
+	opts := execOptions{ErrorContext: 3}
+
+	got := opts.errorOutput("i-0abc", stderr, true)
+	want := "... 47 earlier line(s) not shown (--error-context 3) ...\nerror line 48\nerror line 49\nerror line 50"
+	if got != want {
+		t.Errorf("errorOutput() = %q, want %q", got, want)
+	}
+
+	if got := opts.errorOutput("i-0abc", stderr, false); got != stderr {
+		t.Error("Expected the error output of a successful instance to be printed in full")
+	}
+	if got := opts.errorOutput("i-0abc", "one\ntwo\n", true); got != "one\ntwo\n" {
+		t.Errorf("Expected short error output unchanged, got %q", got)
+	}
+	if got := (execOptions{}).errorOutput("i-0abc", stderr, true); got != stderr {
+		t.Error("Expected --error-context 0 to print all of the error output")
+	}
+}
+
+func TestErrorOutputSavesFullOutputWithChunkOutput(t *testing.T) {
+	stderr := strings.Repeat("Traceback line\n", 30)
+	opts := execOptions{ErrorContext: 2, ChunkOutput: 1 << 20, LogDir: t.TempDir(), RunID: "run-1"}
+
+	got := opts.errorOutput("i-0abc", stderr, true)
+	path := filepath.Join(opts.LogDir, "run-1", "i-0abc.stderr.log")
+	if !strings.Contains(got, "full error output saved to "+path) {
+		t.Errorf("Expected the saved file in the note, got %q", got)
+	}
+	saved, err := os.ReadFile(path)
+	if err != nil || string(saved) != stderr {
+		t.Errorf("Expected the full error output in %s, got %q (%v)", path, saved, err)
+	}
+}
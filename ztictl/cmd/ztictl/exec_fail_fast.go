@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ztictl/pkg/colors"
+
+	"github.com/spf13/cobra"
+)
+
+// errFailFastAborted marks instances that were never started because --fail-fast-threshold was exceeded
+var errFailFastAborted = errors.New("fail-fast threshold exceeded")
+
+// failFastThreshold is a parsed --fail-fast-threshold: an absolute number of failures or a percentage of targets
+type failFastThreshold struct {
+	count     int
+	percent   float64
+	isPercent bool
+	raw       string
+}
+
+// addFailFastThresholdFlag registers --fail-fast-threshold
+func addFailFastThresholdFlag(cmd *cobra.Command) {
+	cmd.Flags().String("fail-fast-threshold", "", "Stop starting new executions once more than this many instances fail, as a count (10) or a percentage of targets (5%)")
+}
+
+// parseFailFastThreshold parses a --fail-fast-threshold value; an empty value disables it
+func parseFailFastThreshold(value string) (*failFastThreshold, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if number, ok := strings.CutSuffix(value, "%"); ok {
+		percent, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil || percent < 0 || percent >= 100 {
+			return nil, fmt.Errorf("invalid --fail-fast-threshold '%s' (percentage must be at least 0%% and below 100%%)", value)
+		}
+		return &failFastThreshold{percent: percent, isPercent: true, raw: value}, nil
+	}
+
+	count, err := strconv.Atoi(value)
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid --fail-fast-threshold '%s' (expected a failure count such as 10 or a percentage such as 5%%)", value)
+	}
+	return &failFastThreshold{count: count, raw: value}, nil
+}
+
+// allowedFailures returns how many of total targets may fail before the run is aborted
+func (t *failFastThreshold) allowedFailures(total int) int {
+	if t.isPercent {
+		return int(t.percent * float64(total) / 100)
+	}
+	return t.count
+}
+
+// failFastTracker counts failures of one fan-out and cancels its queue once the threshold is exceeded.
+// It is only used from the goroutine collecting results.
+type failFastTracker struct {
+	allowed  int
+	failures int
+	cancel   context.CancelCauseFunc
+}
+
+// newFailFastTracker returns a context that gates starting queued work, and a tracker that cancels it.
+// Without a threshold the tracker is nil and the context is ctx itself. Commands already running
+// keep using the parent context, so they finish and report normally.
+func newFailFastTracker(ctx context.Context, threshold *failFastThreshold, total int) (context.Context, *failFastTracker) {
+	if threshold == nil {
+		return ctx, nil
+	}
+	queueCtx, cancel := context.WithCancelCause(ctx)
+	return queueCtx, &failFastTracker{allowed: threshold.allowedFailures(total), cancel: cancel}
+}
+
+// record counts a completed execution, cancelling the queue when failures exceed the allowed number
+func (t *failFastTracker) record(result ParallelExecutionResult) {
+	if t == nil || result.succeeded() || errors.Is(result.Error, errFailFastAborted) || isCancelled(result.Error) {
+		return
+	}
+	t.failures++
+	if t.failures > t.allowed {
+		t.cancel(fmt.Errorf("%w (%d failure(s), %d allowed)", errFailFastAborted, t.failures, t.allowed))
+	}
+}
+
+// stop releases the queue context
+func (t *failFastTracker) stop() {
+	if t != nil {
+		t.cancel(nil)
+	}
+}
+
+// notStartedError explains why queued work was skipped: the fail-fast abort or the parent context's error
+func notStartedError(queueCtx context.Context) error {
+	return fmt.Errorf("not started: %w", context.Cause(queueCtx))
+}
+
+// printFailFastAbort reports how many instances were skipped after the threshold was exceeded
+func printFailFastAbort(threshold *failFastThreshold, failures int, abortedIDs []string) {
+	if len(abortedIDs) == 0 {
+		return
+	}
+	colors.PrintError("\n✗ Aborted: %d failure(s) exceeded --fail-fast-threshold %s; %d instance(s) were not started\n",
+		failures, threshold.raw, len(abortedIDs))
+	colors.PrintData("Not started: %s\n", strings.Join(abortedIDs, ", "))
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"ztictl/internal/interactive"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseFailFastThreshold(t *testing.T) {
+	tests := []struct {
+		value   string
+		total   int
+		allowed int
+		wantNil bool
+		wantErr bool
+	}{
+		{value: "", wantNil: true},
+		{value: "10", total: 100, allowed: 10},
+		{value: "0", total: 100, allowed: 0},
+		{value: "5%", total: 200, allowed: 10},
+		{value: "5%", total: 10, allowed: 0},
+		{value: "12.5%", total: 80, allowed: 10},
+		{value: " 3 ", total: 5, allowed: 3},
+		{value: "-1", wantErr: true},
+		{value: "100%", wantErr: true},
+		{value: "-5%", wantErr: true},
+		{value: "ten", wantErr: true},
+		{value: "%", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			threshold, err := parseFailFastThreshold(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFailFastThreshold(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (threshold == nil) != tt.wantNil {
+				t.Fatalf("parseFailFastThreshold(%q) = %v, wantNil %v", tt.value, threshold, tt.wantNil)
+			}
+			if threshold != nil && threshold.allowedFailures(tt.total) != tt.allowed {
+				t.Errorf("allowedFailures(%d) = %d, want %d", tt.total, threshold.allowedFailures(tt.total), tt.allowed)
+			}
+		})
+	}
+}
+
+func TestFailFastTrackerCancelsQueueOnceExceeded(t *testing.T) {
+	threshold, _ := parseFailFastThreshold("1")
+	queueCtx, tracker := newFailFastTracker(context.Background(), threshold, 10)
+	defer tracker.stop()
+
+	failed := ParallelExecutionResult{Result: exitResult(1)}
+	tracker.record(ParallelExecutionResult{Result: exitResult(0)})
+	tracker.record(failed)
+	if queueCtx.Err() != nil {
+		t.Fatal("queue cancelled before the threshold was exceeded")
+	}
+
+	// Skipped instances do not count as failures
+	tracker.record(ParallelExecutionResult{Error: fmt.Errorf("not started: %w", errFailFastAborted)})
+	tracker.record(ParallelExecutionResult{Error: context.Canceled})
+	if queueCtx.Err() != nil {
+		t.Fatal("unstarted instances must not count towards the threshold")
+	}
+
+	tracker.record(ParallelExecutionResult{Error: errors.New("send failed")})
+	if queueCtx.Err() == nil {
+		t.Fatal("expected the queue to be cancelled after the second failure")
+	}
+	if err := notStartedError(queueCtx); !errors.Is(err, errFailFastAborted) {
+		t.Errorf("notStartedError = %v, want it to wrap errFailFastAborted", err)
+	}
+}
+
+func TestFailFastTrackerDisabled(t *testing.T) {
+	ctx := context.Background()
+	queueCtx, tracker := newFailFastTracker(ctx, nil, 10)
+	if tracker != nil || queueCtx != ctx {
+		t.Fatal("expected no tracker without a threshold")
+	}
+	// A nil tracker is safe to use
+	tracker.record(ParallelExecutionResult{Result: exitResult(1)})
+	tracker.stop()
+}
+
+func TestNotStartedResults(t *testing.T) {
+	batch := []interactive.Instance{{InstanceID: "i-1"}, {InstanceID: "i-2"}}
+	results := notStartedResults(batch, errFailFastAborted)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for i, result := range results {
+		if result.Instance.InstanceID != batch[i].InstanceID || !errors.Is(result.Error, errFailFastAborted) {
+			t.Errorf("result %d = %+v", i, result)
+		}
+	}
+}
+
+func TestResolveExecOptionsFailFastThreshold(t *testing.T) {
+	cmd := &cobra.Command{}
+	addFailFastThresholdFlag(cmd)
+	if err := cmd.Flags().Set("fail-fast-threshold", "5%"); err != nil {
+		t.Fatal(err)
+	}
+	opts, err := resolveExecOptions(cmd)
+	if err != nil {
+		t.Fatalf("resolveExecOptions: %v", err)
+	}
+	if opts.FailFast == nil || !opts.FailFast.isPercent || opts.FailFast.percent != 5 {
+		t.Errorf("FailFast = %+v, want 5%%", opts.FailFast)
+	}
+
+	if err := cmd.Flags().Set("fail-fast-threshold", "lots"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := resolveExecOptions(cmd); err == nil {
+		t.Error("expected an invalid threshold to be rejected")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"ztictl/internal/interactive"
+	"ztictl/pkg/colors"
+
+	"github.com/spf13/cobra"
+)
+
+// addGroupByTagFlag registers --group-by-tag for exec commands
+func addGroupByTagFlag(cmd *cobra.Command) {
+	cmd.Flags().String("group-by-tag", "", "Summarize results per value of this instance tag, e.g. Component (reports nest instances by group)")
+}
+
+// tagGroupValue returns an instance's --group-by-tag value, empty when it lacks the tag
+func (o execOptions) tagGroupValue(instance interactive.Instance) string {
+	return instance.Tags[o.GroupByTag]
+}
+
+// tagGroupLabel names a group in output; instances without the tag form their own group
+func tagGroupLabel(tag, value string) string {
+	if value == "" {
+		return fmt.Sprintf("(no %s tag)", tag)
+	}
+	return value
+}
+
+// sortGroupValues orders group values alphabetically, with the untagged group last
+func sortGroupValues(values []string) {
+	sort.Slice(values, func(i, j int) bool {
+		if (values[i] == "") != (values[j] == "") {
+			return values[j] == ""
+		}
+		return values[i] < values[j]
+	})
+}
+
+// tagGroupCounts tallies exec outcomes per --group-by-tag value
+type tagGroupCounts map[string]*execReportSummary
+
+// add records one instance outcome for a group value
+func (c tagGroupCounts) add(value string, succeeded bool) {
+	counts, ok := c[value]
+	if !ok {
+		counts = &execReportSummary{}
+		c[value] = counts
+	}
+	counts.Total++
+	if succeeded {
+		counts.Succeeded++
+	} else {
+		counts.Failed++
+	}
+}
+
+// successRate returns the percentage of instances that succeeded
+func successRate(summary execReportSummary) int {
+	if summary.Total == 0 {
+		return 0
+	}
+	return summary.Succeeded * 100 / summary.Total
+}
+
+// printTagGroupSummary prints a sub-summary with the success rate for each tag value
+func printTagGroupSummary(tag string, counts tagGroupCounts) {
+	if len(counts) == 0 {
+		return
+	}
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	sortGroupValues(values)
+
+	colors.PrintData("\n")
+	colors.PrintHeader("=== Summary by %s ===\n", tag)
+	for _, value := range values {
+		summary := *counts[value]
+		line := fmt.Sprintf("%s: %d instances, %d successful, %d failed (%d%% success)\n",
+			tagGroupLabel(tag, value), summary.Total, summary.Succeeded, summary.Failed, successRate(summary))
+		if summary.Failed > 0 {
+			colors.PrintError("✗ %s", line)
+		} else {
+			colors.PrintSuccess("✓ %s", line)
+		}
+	}
+}
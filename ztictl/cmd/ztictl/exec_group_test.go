@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// groupedEntry returns a report entry for an instance with a --group-by-tag value
+func groupedEntry(instanceID, group string, exitCode int32) execReportInstance {
+	entry := reportInstance(instanceID, "", "us-east-1", &ssm.CommandResult{ExitCode: &exitCode}, nil, time.Second)
+	entry.group = group
+	return entry
+}
+
+func TestPrintTagGroupSummary(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	counts := tagGroupCounts{}
+	counts.add("api", true)
+	counts.add("api", false)
+	counts.add("", true)
+	counts.add("web", true)
+	printTagGroupSummary("Component", counts)
+
+	output := buf.String()
+	for _, want := range []string{
+		"=== Summary by Component ===",
+		"api: 2 instances, 1 successful, 1 failed (50% success)",
+		"web: 1 instances, 1 successful, 0 failed (100% success)",
+		"(no Component tag): 1 instances",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "web:") > strings.Index(output, "(no Component tag)") {
+		t.Errorf("Expected untagged instances listed last:\n%s", output)
+	}
+}
+
+func TestExecReportGroupsByTag(t *testing.T) {
+	originalQuiet := quiet
+	quiet = true
+	defer func() { quiet = originalQuiet }()
+
+	report := newExecReport("uptime", []string{"us-east-1"}, time.Now())
+	report.GroupByTag = "Component"
+	report.add(groupedEntry("i-web1", "web", 0))
+	report.add(groupedEntry("i-api1", "api", 0))
+	report.add(groupedEntry("i-api2", "api", 2))
+	report.add(groupedEntry("i-misc", "", 1))
+	report.finish()
+
+	if len(report.Instances) != 0 {
+		t.Errorf("Expected grouped instances only under groups, got %+v", report.Instances)
+	}
+	if report.Summary.Total != 4 || report.Summary.Failed != 2 {
+		t.Errorf("Expected the overall summary to count every instance, got %+v", report.Summary)
+	}
+
+	var values []string
+	for _, group := range report.Groups {
+		values = append(values, group.Value)
+	}
+	if strings.Join(values, ",") != "api,web," {
+		t.Fatalf("Expected groups ordered api, web, untagged; got %q", values)
+	}
+
+	api := report.Groups[0]
+	if api.Summary.Total != 2 || api.Summary.Succeeded != 1 || len(api.Instances) != 1 || api.Instances[0].InstanceID != "i-api2" {
+		t.Errorf("Expected the api group to count both instances and keep the failure in quiet mode, got %+v", api)
+	}
+
+	var data bytes.Buffer
+	if err := writeExecReport(&data, report, outputFormatJSON, true); err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		GroupByTag string `json:"group_by_tag"`
+		Groups     []struct {
+			Value     string            `json:"value"`
+			Instances []json.RawMessage `json:"instances"`
+			Summary   execReportSummary `json:"summary"`
+		} `json:"groups"`
+	}
+	if err := json.Unmarshal(data.Bytes(), &decoded); err != nil {
+		t.Fatalf("Report is not valid JSON: %v", err)
+	}
+	if decoded.GroupByTag != "Component" || len(decoded.Groups) != 3 || decoded.Groups[1].Summary.Succeeded != 1 {
+		t.Errorf("Unexpected grouped JSON report:\n%s", data.String())
+	}
+
+	var text bytes.Buffer
+	if err := writeExecReport(&text, report, outputFormatText, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"##### Component: api #####", "=== i-api2 [us-east-1] ===", "api: 1 of 2 succeeded (50%)", "(no Component tag): 0 of 1 succeeded (0%)"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Expected %q in text report, got:\n%s", want, text.String())
+		}
+	}
+}
+
+func TestMultiRegionTagGroups(t *testing.T) {
+	opts := execOptions{GroupByTag: "Component"}
+	api := interactive.Instance{InstanceID: "i-1", Tags: map[string]string{"Component": "api"}}
+	results := []MultiRegionResult{
+		{Region: "us-east-1", Instances: []InstanceResult{{Instance: api, Success: true}}},
+		{Region: "eu-west-1", Instances: []InstanceResult{{Instance: api, Success: false}, {Instance: interactive.Instance{InstanceID: "i-2"}, Success: true}}},
+	}
+
+	counts := multiRegionTagGroups(results, opts)
+	if got := counts["api"]; got == nil || got.Total != 2 || got.Failed != 1 {
+		t.Errorf("Expected api counted across regions, got %+v", got)
+	}
+	if got := counts[""]; got == nil || got.Succeeded != 1 {
+		t.Errorf("Expected the untagged instance counted, got %+v", got)
+	}
+}
+
+func TestResolveExecOptionsGroupByTag(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("group-by-tag") == nil {
+			t.Errorf("Expected --group-by-tag on %s", cmd.Name())
+		}
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	addGroupByTagFlag(cmd)
+	_ = cmd.Flags().Set("group-by-tag", " Component ")
+	opts, err := resolveExecOptions(cmd)
+	if err != nil {
+		t.Fatalf("resolveExecOptions() error = %v", err)
+	}
+	if opts.GroupByTag != "Component" {
+		t.Errorf("Expected GroupByTag Component, got %q", opts.GroupByTag)
+	}
+}
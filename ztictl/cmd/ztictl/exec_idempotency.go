@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ztictl/internal/idempotency"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// idempotencyTargetFlags are the flags that choose targets and so are part of an operation's identity
+var idempotencyTargetFlags = []string{"region", "tags", "instances", "asg", "vpc", "subnet", "exclude"}
+
+// idempotencyGuard skips a run already completed under its --idempotency-key and records the run once
+// it completes. A nil guard runs everything and records nothing.
+type idempotencyGuard struct {
+	store         *idempotency.Store
+	key           string
+	operationHash string
+	force         bool
+}
+
+// addIdempotencyFlags registers --idempotency-key and --force
+func addIdempotencyFlags(cmd *cobra.Command) {
+	cmd.Flags().String("idempotency-key", "", "Run at most once per key: skip the run when the same operation already completed under this key")
+	cmd.Flags().Bool("force", false, "With --idempotency-key, run even when the operation already completed under the key")
+}
+
+// resolveIdempotency reads --idempotency-key and --force. The operation is identified by the command
+// path, its arguments (which hold the command) and the targeting flags. It returns nil without a key.
+func resolveIdempotency(cmd *cobra.Command, args []string) (*idempotencyGuard, error) {
+	if cmd.Flags().Lookup("idempotency-key") == nil {
+		return nil, nil
+	}
+	key, _ := cmd.Flags().GetString("idempotency-key")
+	force, _ := cmd.Flags().GetBool("force")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		if cmd.Flags().Changed("idempotency-key") {
+			return nil, fmt.Errorf("invalid --idempotency-key (expected a non-empty key)")
+		}
+		if force {
+			return nil, fmt.Errorf("--force requires --idempotency-key")
+		}
+		return nil, nil
+	}
+	if countOnly, _ := cmd.Flags().GetBool("count-only"); countOnly {
+		return nil, fmt.Errorf("--idempotency-key cannot be combined with --count-only")
+	}
+
+	store, err := idempotency.NewStore("")
+	if err != nil {
+		return nil, err
+	}
+
+	parts := append([]string{cmd.CommandPath()}, args...)
+	for _, name := range idempotencyTargetFlags {
+		if flag := cmd.Flags().Lookup(name); flag != nil {
+			parts = append(parts, "--"+name+"="+flag.Value.String())
+		}
+	}
+	return &idempotencyGuard{store: store, key: key, operationHash: idempotency.HashOperation(parts...), force: force}, nil
+}
+
+// completed reports whether the operation already completed under the key, in which case it should
+// be skipped. Reusing a key for a different operation is an error, since that is almost always a
+// mistake in the calling pipeline. --force runs in both cases.
+func (g *idempotencyGuard) completed() (bool, error) {
+	if g == nil {
+		return false, nil
+	}
+	record, found, err := g.store.Lookup(g.key)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, nil
+	}
+	completedAt := record.CompletedAt.Local().Format(time.RFC3339)
+	if g.force {
+		logging.LogWarn("Idempotency key %s was already used at %s; running again because of --force", g.key, completedAt)
+		return false, nil
+	}
+	if record.OperationHash != g.operationHash {
+		return false, fmt.Errorf("idempotency key %s was already used for a different operation at %s (use --force to run anyway)", g.key, completedAt)
+	}
+	colors.PrintWarning("⚠ Skipped: this operation already completed under idempotency key %s at %s (use --force to run it again)\n", g.key, completedAt)
+	return true, nil
+}
+
+// complete records the operation as completed under the key. A failure is logged and does not
+// change the outcome of the run, which has already happened.
+func (g *idempotencyGuard) complete() {
+	if g == nil {
+		return
+	}
+	if err := g.store.Complete(idempotency.Record{Key: g.key, OperationHash: g.operationHash}); err != nil {
+		logging.LogWarn("Failed to record idempotency key %s: %v", g.key, err)
+	}
+}
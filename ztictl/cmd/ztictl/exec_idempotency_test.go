@@ -0,0 +1,92 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newIdempotencyTestCmd(t *testing.T, flags ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "exec-tagged"}
+	cmd.Flags().String("tags", "", "")
+	addCountOnlyFlag(cmd)
+	addIdempotencyFlags(cmd)
+	if err := cmd.Flags().Parse(flags); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestResolveIdempotency(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd} {
+		if cmd.Flags().Lookup("idempotency-key") == nil || cmd.Flags().Lookup("force") == nil {
+			t.Errorf("Expected --idempotency-key and --force flags on %s", cmd.Name())
+		}
+	}
+
+	if guard, err := resolveIdempotency(newIdempotencyTestCmd(t), []string{"cac1", "uptime"}); guard != nil || err != nil {
+		t.Errorf("Expected no guard without a key, got %+v, %v", guard, err)
+	}
+
+	for _, tt := range []struct {
+		flags   []string
+		wantErr string
+	}{
+		{flags: []string{"--force"}, wantErr: "--force requires --idempotency-key"},
+		{flags: []string{"--idempotency-key", " "}, wantErr: "invalid --idempotency-key"},
+		{flags: []string{"--idempotency-key", "deploy-42", "--count-only"}, wantErr: "--count-only"},
+	} {
+		if _, err := resolveIdempotency(newIdempotencyTestCmd(t, tt.flags...), []string{"cac1", "uptime"}); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%v: expected an error containing %q, got %v", tt.flags, tt.wantErr, err)
+		}
+	}
+
+	web, _ := resolveIdempotency(newIdempotencyTestCmd(t, "--idempotency-key", "deploy-42", "--tags", "Role=web"), []string{"cac1", "uptime"})
+	db, _ := resolveIdempotency(newIdempotencyTestCmd(t, "--idempotency-key", "deploy-42", "--tags", "Role=db"), []string{"cac1", "uptime"})
+	other, _ := resolveIdempotency(newIdempotencyTestCmd(t, "--idempotency-key", "deploy-42", "--tags", "Role=web"), []string{"cac1", "reboot"})
+	if web == nil || web.key != "deploy-42" {
+		t.Fatalf("Expected a guard for the key, got %+v", web)
+	}
+	if web.operationHash == db.operationHash || web.operationHash == other.operationHash {
+		t.Error("Expected the targets and the command to identify the operation")
+	}
+}
+
+func TestIdempotencyGuard(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	args := []string{"cac1", "systemctl restart nginx"}
+
+	first, err := resolveIdempotency(newIdempotencyTestCmd(t, "--idempotency-key", "deploy-42", "--tags", "Role=web"), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done, err := first.completed(); done || err != nil {
+		t.Fatalf("Expected a new key to run, got %v, %v", done, err)
+	}
+	first.complete()
+
+	retry, _ := resolveIdempotency(newIdempotencyTestCmd(t, "--idempotency-key", "deploy-42", "--tags", "Role=web"), args)
+	if done, err := retry.completed(); !done || err != nil {
+		t.Errorf("Expected the retried operation to be skipped, got %v, %v", done, err)
+	}
+
+	forced, _ := resolveIdempotency(newIdempotencyTestCmd(t, "--idempotency-key", "deploy-42", "--tags", "Role=web", "--force"), args)
+	if done, err := forced.completed(); done || err != nil {
+		t.Errorf("Expected --force to run again, got %v, %v", done, err)
+	}
+
+	reused, _ := resolveIdempotency(newIdempotencyTestCmd(t, "--idempotency-key", "deploy-42", "--tags", "Role=db"), args)
+	if _, err := reused.completed(); err == nil || !strings.Contains(err.Error(), "different operation") {
+		t.Errorf("Expected reusing the key for another operation to fail, got %v", err)
+	}
+
+	var none *idempotencyGuard
+	if done, err := none.completed(); done || err != nil {
+		t.Errorf("Expected a nil guard to run, got %v, %v", done, err)
+	}
+	none.complete()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	// jsonStylePretty indents JSON output
+	jsonStylePretty = "pretty"
+	// jsonStyleCompact writes JSON output on one line
+	jsonStyleCompact = "compact"
+)
+
+// addJSONStyleFlags registers --pretty and --compact, which override the terminal-based choice of
+// JSON layout
+func addJSONStyleFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("pretty", false, "Indent --output json (the default when writing to a terminal)")
+	cmd.Flags().Bool("compact", false, "Write --output json on a single line (the default when piped or written to a file)")
+	cmd.MarkFlagsMutuallyExclusive("pretty", "compact")
+}
+
+// resolveJSONStyle reads --pretty and --compact; an empty style picks the layout from the destination
+func resolveJSONStyle(cmd *cobra.Command, output string) (string, error) {
+	if cmd.Flags().Lookup("pretty") == nil {
+		return "", nil
+	}
+	pretty, _ := cmd.Flags().GetBool("pretty")
+	compact, _ := cmd.Flags().GetBool("compact")
+	if (pretty || compact) && output != outputFormatJSON {
+		return "", fmt.Errorf("--pretty and --compact require --output %s", outputFormatJSON)
+	}
+	switch {
+	case pretty:
+		return jsonStylePretty, nil
+	case compact:
+		return jsonStyleCompact, nil
+	}
+	return "", nil
+}
+
+// prettyJSON reports whether JSON written to w is indented: as set by --pretty or --compact,
+// otherwise only when w is a terminal
+func (o execOptions) prettyJSON(w io.Writer) bool {
+	switch o.JSONStyle {
+	case jsonStylePretty:
+		return true
+	case jsonStyleCompact:
+		return false
+	}
+	file, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// writeJSON encodes v as one JSON document, indented when pretty is set
+func writeJSON(w io.Writer, v any, pretty bool) error {
+	encoder := json.NewEncoder(w)
+	if pretty {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(v)
+}
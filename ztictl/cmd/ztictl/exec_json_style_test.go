@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestResolveJSONStyle(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   []string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", output: outputFormatJSON, want: ""},
+		{name: "pretty", flags: []string{"--pretty"}, output: outputFormatJSON, want: jsonStylePretty},
+		{name: "compact", flags: []string{"--compact"}, output: outputFormatJSON, want: jsonStyleCompact},
+		{name: "both", flags: []string{"--pretty", "--compact"}, output: outputFormatJSON, wantErr: true},
+		{name: "not json", flags: []string{"--compact"}, output: outputFormatYAML, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+			addJSONStyleFlags(cmd)
+			cmd.SetArgs(tt.flags)
+			err := cmd.Execute()
+			var style string
+			if err == nil {
+				style, err = resolveJSONStyle(cmd, tt.output)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if style != tt.want {
+				t.Errorf("Expected style %q, got %q", tt.want, style)
+			}
+		})
+	}
+}
+
+func TestExecReportJSONStyle(t *testing.T) {
+	report := newExecReport("uptime", []string{"us-east-1"}, time.Now())
+
+	var buf bytes.Buffer
+	opts := execOptions{Output: outputFormatJSON}
+	// A buffer is not a terminal, so the default is compact
+	if err := writeExecReport(&buf, report, opts.Output, opts.prettyJSON(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("Expected compact JSON on one line, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	opts.JSONStyle = jsonStylePretty
+	if err := writeExecReport(&buf, report, opts.Output, opts.prettyJSON(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "\n  \"command\": \"uptime\"") {
+		t.Errorf("Expected indented JSON with --pretty, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+)
+
+// outputFormatJSONL streams one JSON object per instance to stdout as each instance completes
+const outputFormatJSONL = "jsonl"
+
+// jsonLine is one --output jsonl record: an instance outcome tagged with the run it belongs to
+type jsonLine struct {
+	RunID string `json:"run_id,omitempty"`
+	Label string `json:"label,omitempty"`
+	Group string `json:"group,omitempty"` // --group-by-tag value
+	execReportInstance
+}
+
+var (
+	// jsonLinesOutput receives streamed records; tests replace it
+	jsonLinesOutput io.Writer = os.Stdout
+
+	// jsonLinesMu keeps lines whole when several workers or regions complete at once
+	jsonLinesMu sync.Mutex
+)
+
+// streamResult writes a completed instance to stdout with --output jsonl.
+// Quiet mode streams only failures, matching what the aggregated reports keep.
+func (o execOptions) streamResult(entry execReportInstance) {
+	if o.Output != outputFormatJSONL || (quiet && entry.Status == reportStatusSuccess) {
+		return
+	}
+
+	jsonLinesMu.Lock()
+	defer jsonLinesMu.Unlock()
+	_ = writeJSONLine(jsonLinesOutput, o.newJSONLine(entry))
+}
+
+// streamParallelResult streams a result of the parallel worker pool
+func (o execOptions) streamParallelResult(region string, result ParallelExecutionResult) {
+	if o.Output != outputFormatJSONL {
+		return
+	}
+	entry := reportInstance(result.Instance.InstanceID, result.Instance.Name, region, result.Result, result.Error, result.Duration)
+	entry.group = o.tagGroupValue(result.Instance)
+	o.streamResult(withCollect(o.withSteps(o.withAttempts(entry, result.Attempts)), result))
+}
+
+// newJSONLine builds the record for an instance
+func (o execOptions) newJSONLine(entry execReportInstance) jsonLine {
+	line := jsonLine{RunID: o.RunID, Label: o.Label, execReportInstance: entry}
+	if o.GroupByTag != "" {
+		line.Group = entry.group
+	}
+	return line
+}
+
+// writeJSONLine encodes one record on its own line
+func writeJSONLine(w io.Writer, line jsonLine) error {
+	return json.NewEncoder(w).Encode(line)
+}
+
+// writeJSONLines renders every instance in a report as JSON lines, for --output-file with --output jsonl
+func writeJSONLines(w io.Writer, report *execReport) error {
+	for _, instance := range report.Instances {
+		if err := writeJSONLine(w, jsonLine{RunID: report.RunID, Label: report.Label, execReportInstance: instance}); err != nil {
+			return err
+		}
+	}
+	for _, group := range report.Groups {
+		for _, instance := range group.Instances {
+			line := jsonLine{RunID: report.RunID, Label: report.Label, Group: group.Value, execReportInstance: instance}
+			if err := writeJSONLine(w, line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
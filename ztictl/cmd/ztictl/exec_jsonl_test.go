@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+
+	"github.com/spf13/cobra"
+)
+
+// decodeJSONLines parses every line of out as a jsonLine
+func decodeJSONLines(t *testing.T, out string) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, text := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		var line map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &line); err != nil {
+			t.Fatalf("line %q is not JSON: %v", text, err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestStreamParallelResult(t *testing.T) {
+	originalOutput, originalQuiet := jsonLinesOutput, quiet
+	defer func() { jsonLinesOutput, quiet = originalOutput, originalQuiet }()
+	var buf bytes.Buffer
+	jsonLinesOutput, quiet = &buf, false
+
+	opts := execOptions{Output: outputFormatJSONL, RunID: "run-1", Retries: 2}
+	exitCode := int32(2)
+	opts.streamParallelResult("us-east-1", ParallelExecutionResult{
+		Instance: interactive.Instance{InstanceID: "i-1", Name: "web"},
+		Result:   &ssm.CommandResult{Output: "ok\n", ExitCode: new(int32)},
+		Duration: time.Second,
+		Attempts: 1,
+	})
+	opts.streamParallelResult("us-east-1", ParallelExecutionResult{
+		Instance: interactive.Instance{InstanceID: "i-2"},
+		Result:   &ssm.CommandResult{ExitCode: &exitCode},
+		Attempts: 3,
+	})
+
+	lines := decodeJSONLines(t, buf.String())
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), buf.String())
+	}
+	if lines[0]["instance_id"] != "i-1" || lines[0]["status"] != reportStatusSuccess || lines[0]["output"] != "ok\n" ||
+		lines[0]["run_id"] != "run-1" || lines[0]["region"] != "us-east-1" {
+		t.Errorf("unexpected first line %v", lines[0])
+	}
+	if lines[1]["status"] != reportStatusFailed || lines[1]["exit_code"] != float64(2) || lines[1]["attempts"] != float64(3) {
+		t.Errorf("unexpected second line %v", lines[1])
+	}
+}
+
+func TestStreamResultSkipsOtherFormatsAndQuietSuccesses(t *testing.T) {
+	originalOutput, originalQuiet := jsonLinesOutput, quiet
+	defer func() { jsonLinesOutput, quiet = originalOutput, originalQuiet }()
+	var buf bytes.Buffer
+	jsonLinesOutput = &buf
+
+	success := reportInstance("i-1", "", "us-east-1", &ssm.CommandResult{ExitCode: new(int32)}, nil, time.Second)
+	failure := reportInstance("i-2", "", "us-east-1", nil, errors.New("unreachable"), time.Second)
+
+	quiet = false
+	execOptions{Output: outputFormatJSON}.streamResult(success)
+	if buf.Len() != 0 {
+		t.Fatalf("--output json must not stream, got %q", buf.String())
+	}
+
+	quiet = true
+	opts := execOptions{Output: outputFormatJSONL}
+	opts.streamResult(success)
+	opts.streamResult(failure)
+	lines := decodeJSONLines(t, buf.String())
+	if len(lines) != 1 || lines[0]["instance_id"] != "i-2" {
+		t.Errorf("quiet mode should stream only failures, got %q", buf.String())
+	}
+}
+
+func TestWriteJSONLinesGroups(t *testing.T) {
+	report := newExecReport("uptime", []string{"us-east-1"}, time.Now())
+	report.RunID = "run-1"
+	report.GroupByTag = "Component"
+	report.add(groupedEntry("i-web1", "web", 0))
+	report.add(groupedEntry("i-api1", "api", 1))
+	report.finish()
+
+	var buf bytes.Buffer
+	if err := writeExecReport(&buf, report, outputFormatJSONL, false); err != nil {
+		t.Fatal(err)
+	}
+	lines := decodeJSONLines(t, buf.String())
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	if lines[0]["group"] != "api" || lines[0]["instance_id"] != "i-api1" || lines[1]["group"] != "web" || lines[1]["run_id"] != "run-1" {
+		t.Errorf("unexpected grouped lines %v", lines)
+	}
+}
+
+func TestResolveExecOptionsJSONL(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	addReportFlags(cmd)
+	_ = cmd.Flags().Set("output", outputFormatJSONL)
+
+	opts, err := resolveExecOptions(cmd)
+	if err != nil {
+		t.Fatalf("resolveExecOptions() error = %v", err)
+	}
+	if !opts.structuredOutput() || !opts.wantsReport() {
+		t.Error("Expected --output jsonl to keep progress off stdout")
+	}
+
+	_ = cmd.Flags().Set("output-template", "{{.InstanceID}}")
+	if _, err := resolveExecOptions(cmd); err == nil {
+		t.Error("Expected --output-template with --output jsonl to be rejected")
+	}
+}
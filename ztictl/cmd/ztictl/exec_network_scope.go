@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+
+	"github.com/spf13/cobra"
+)
+
+// networkScope limits targets to instances in the --vpc and --subnet IDs
+type networkScope struct {
+	VPC    string // Comma-separated VPC IDs
+	Subnet string // Comma-separated subnet IDs
+}
+
+// addNetworkScopeFlags registers --vpc and --subnet
+func addNetworkScopeFlags(cmd *cobra.Command) {
+	cmd.Flags().String("vpc", "", "Only target instances in these comma-separated VPC IDs (e.g. vpc-0abc123)")
+	cmd.Flags().String("subnet", "", "Only target instances in these comma-separated subnet IDs (e.g. subnet-0abc123)")
+}
+
+// resolveNetworkScope reads --vpc and --subnet, checking that every ID has the right prefix
+func resolveNetworkScope(cmd *cobra.Command) (networkScope, error) {
+	if cmd.Flags().Lookup("vpc") == nil {
+		return networkScope{}, nil
+	}
+	vpc, _ := cmd.Flags().GetString("vpc")
+	subnet, _ := cmd.Flags().GetString("subnet")
+
+	if err := validateResourceIDs("--vpc", "vpc-", vpc); err != nil {
+		return networkScope{}, err
+	}
+	if err := validateResourceIDs("--subnet", "subnet-", subnet); err != nil {
+		return networkScope{}, err
+	}
+	return networkScope{VPC: strings.TrimSpace(vpc), Subnet: strings.TrimSpace(subnet)}, nil
+}
+
+// validateResourceIDs checks that each comma-separated ID in list starts with prefix
+func validateResourceIDs(flag, prefix, list string) error {
+	for _, id := range strings.Split(list, ",") {
+		id = strings.TrimSpace(id)
+		if id != "" && (!strings.HasPrefix(id, prefix) || len(id) == len(prefix)) {
+			return fmt.Errorf("invalid %s '%s' (expected an ID like %s0abc123)", flag, id, prefix)
+		}
+	}
+	return nil
+}
+
+// isSet reports whether --vpc or --subnet was given
+func (s networkScope) isSet() bool {
+	return s.VPC != "" || s.Subnet != ""
+}
+
+// apply adds the scope to a tag listing, so EC2 filters the instances
+func (s networkScope) apply(filters *ssm.ListFilters) {
+	filters.VPC = s.VPC
+	filters.Subnet = s.Subnet
+}
+
+// restrict keeps the explicitly listed instances that are inside the scope. Instances outside it are
+// reported and dropped. The instances are resolved first, so those kept still carry their state and agent status.
+func (s networkScope) restrict(ctx context.Context, ssmManager *ssm.Manager, region string, instances []interactive.Instance) ([]interactive.Instance, error) {
+	if !s.isSet() || len(instances) == 0 {
+		return instances, nil
+	}
+
+	filters := &ssm.ListFilters{}
+	s.apply(filters)
+	inScope, err := instanceLookup(ctx, ssmManager, region, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances in --vpc/--subnet: %w", err)
+	}
+	return intersectInstances(instances, inScope), nil
+}
+
+// intersectInstances keeps the targets whose instance ID is also in inScope, in target order
+func intersectInstances(targets, inScope []interactive.Instance) []interactive.Instance {
+	ids := make(map[string]bool, len(inScope))
+	for _, instance := range inScope {
+		ids[instance.InstanceID] = true
+	}
+
+	var kept, dropped []interactive.Instance
+	for _, instance := range targets {
+		if ids[instance.InstanceID] {
+			kept = append(kept, instance)
+		} else {
+			dropped = append(dropped, instance)
+		}
+	}
+	if len(dropped) > 0 && !quiet {
+		droppedIDs := make([]string, len(dropped))
+		for i, instance := range dropped {
+			droppedIDs[i] = instance.InstanceID
+		}
+		colors.PrintWarning("⚠ Skipping %d instance(s) outside --vpc/--subnet: %s\n", len(dropped), strings.Join(droppedIDs, ", "))
+	}
+	return kept
+}
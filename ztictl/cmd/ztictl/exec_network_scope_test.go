@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/logging"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func TestResolveNetworkScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    networkScope
+		wantErr string
+	}{
+		{name: "unset", args: nil},
+		{name: "vpc", args: []string{"--vpc", "vpc-0abc"}, want: networkScope{VPC: "vpc-0abc"}},
+		{name: "several subnets", args: []string{"--subnet", "subnet-1, subnet-2"}, want: networkScope{Subnet: "subnet-1, subnet-2"}},
+		{name: "subnet ID given to --vpc", args: []string{"--vpc", "subnet-1"}, wantErr: "invalid --vpc 'subnet-1'"},
+		{name: "bare prefix", args: []string{"--subnet", "subnet-"}, wantErr: "invalid --subnet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addNetworkScopeFlags(cmd)
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatalf("Failed to parse flags: %v", err)
+			}
+
+			got, err := resolveNetworkScope(cmd)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveNetworkScope() = %+v, %v; want %+v", got, err, tt.want)
+			}
+		})
+	}
+
+	if scope, err := resolveNetworkScope(&cobra.Command{}); err != nil || scope.isSet() {
+		t.Errorf("Expected an empty scope without the flags, got %+v, %v", scope, err)
+	}
+}
+
+func TestNetworkScopeApply(t *testing.T) {
+	filters := &ssm.ListFilters{Tags: "Role=web"}
+	networkScope{VPC: "vpc-1", Subnet: "subnet-2"}.apply(filters)
+
+	if filters.Tags != "Role=web" || filters.VPC != "vpc-1" || filters.Subnet != "subnet-2" {
+		t.Errorf("Expected the scope added to the tag filters, got %+v", filters)
+	}
+}
+
+func TestIntersectInstances(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	targets := []interactive.Instance{{InstanceID: "i-1"}, {InstanceID: "i-2"}, {InstanceID: "i-3"}}
+	inScope := []interactive.Instance{{InstanceID: "i-3"}, {InstanceID: "i-1"}, {InstanceID: "i-9"}}
+
+	kept := intersectInstances(targets, inScope)
+
+	var ids []string
+	for _, instance := range kept {
+		ids = append(ids, instance.InstanceID)
+	}
+	if !slices.Equal(ids, []string{"i-1", "i-3"}) {
+		t.Errorf("Expected i-1 and i-3 in target order, got %v", ids)
+	}
+	if !strings.Contains(buf.String(), "Skipping 1 instance(s) outside --vpc/--subnet: i-2") {
+		t.Errorf("Expected the dropped instance to be reported, got %q", buf.String())
+	}
+}
+
+func TestNetworkScopeRestrictsResolvedInstances(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+	if logger == nil {
+		logger = logging.NewLogger(false)
+	}
+
+	original := instanceLookup
+	defer func() { instanceLookup = original }()
+	details := map[string]interactive.Instance{
+		"i-in":  {InstanceID: "i-in", Name: "app-1", State: "running", SSMStatus: "Online"},
+		"i-out": {InstanceID: "i-out", Name: "app-2", State: "running", SSMStatus: "Online"},
+	}
+	instanceLookup = func(ctx context.Context, ssmManager *ssm.Manager, region string, filters *ssm.ListFilters) ([]interactive.Instance, error) {
+		if filters.VPC == "vpc-1" {
+			return []interactive.Instance{details["i-in"]}, nil
+		}
+		var found []interactive.Instance
+		for _, id := range splitInstanceIDs(filters.InstanceIDs) {
+			found = append(found, details[id])
+		}
+		return found, nil
+	}
+
+	opts := execOptions{Network: networkScope{VPC: "vpc-1"}}
+	instances, err := resolveExecTaggedInstances(context.Background(), nil, "ca-central-1", "uptime", "", "i-in,i-out", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 1 || !reflect.DeepEqual(instances[0], details["i-in"]) {
+		t.Fatalf("Expected only i-in with its details, got %+v", instances)
+	}
+	if reason := execSkipReason(instances[0], opts); reason != "" {
+		t.Errorf("Expected the in-scope instance to be executed, got skipped: %s", reason)
+	}
+	if !strings.Contains(buf.String(), "outside --vpc/--subnet: i-out") {
+		t.Errorf("Expected i-out to be reported, got %q", buf.String())
+	}
+}
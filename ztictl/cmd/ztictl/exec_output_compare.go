@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"ztictl/pkg/colors"
+
+	"github.com/spf13/cobra"
+)
+
+// addOutputCompareFlag registers --output-compare for exec commands
+func addOutputCompareFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("output-compare", false, "Group instances by identical output and report the distinct variants instead of each instance's output")
+}
+
+// outputVariant is one distinct output and the instances that produced it
+type outputVariant struct {
+	Output    string
+	ExitCode  int32
+	Instances []string // Instance labels in result order
+}
+
+// outputComparison groups exec results by output for --output-compare
+type outputComparison struct {
+	Variants []outputVariant // Largest first; ties keep the order they were first seen
+	Errors   []string        // Instances whose command did not complete, so they have no output to compare
+}
+
+// compareOutputs groups completed results by stdout and exit code. Trailing whitespace is ignored,
+// so a missing final newline does not make an instance an outlier.
+func compareOutputs(results []ParallelExecutionResult) outputComparison {
+	var comparison outputComparison
+	index := make(map[string]int)
+	for _, result := range results {
+		label := fmt.Sprintf("%s (%s)", result.Instance.Name, result.Instance.InstanceID)
+		if result.Error != nil || result.Result == nil {
+			comparison.Errors = append(comparison.Errors, label)
+			continue
+		}
+
+		var exitCode int32
+		if result.Result.ExitCode != nil {
+			exitCode = *result.Result.ExitCode
+		}
+		output := strings.TrimRight(result.Result.Output, " \t\r\n")
+		key := fmt.Sprintf("%d\x00%s", exitCode, output)
+
+		i, ok := index[key]
+		if !ok {
+			i = len(comparison.Variants)
+			index[key] = i
+			comparison.Variants = append(comparison.Variants, outputVariant{Output: output, ExitCode: exitCode})
+		}
+		comparison.Variants[i].Instances = append(comparison.Variants[i].Instances, label)
+	}
+
+	sort.SliceStable(comparison.Variants, func(i, j int) bool {
+		return len(comparison.Variants[i].Instances) > len(comparison.Variants[j].Instances)
+	})
+	return comparison
+}
+
+// majority returns the variant shared by the most instances, or false when the largest variants are tied
+func (c outputComparison) majority() (outputVariant, bool) {
+	if len(c.Variants) == 0 {
+		return outputVariant{}, false
+	}
+	if len(c.Variants) > 1 && len(c.Variants[1].Instances) == len(c.Variants[0].Instances) {
+		return outputVariant{}, false
+	}
+	return c.Variants[0], true
+}
+
+// outliers returns the instances outside the majority variant
+func (c outputComparison) outliers() []string {
+	var outliers []string
+	for _, variant := range c.Variants[1:] {
+		outliers = append(outliers, variant.Instances...)
+	}
+	return outliers
+}
+
+// printOutputComparison prints each distinct output with its instances, then a majority vs outliers summary
+func printOutputComparison(comparison outputComparison) {
+	colors.PrintData("\n")
+	colors.PrintHeader("=== Output Comparison ===\n")
+
+	for i, variant := range comparison.Variants {
+		colors.PrintData("\n")
+		colors.PrintHeader("--- Variant %d: %d instance(s), exit code %d ---\n", i+1, len(variant.Instances), variant.ExitCode)
+		colors.PrintData("Instances: %s\n", strings.Join(variant.Instances, ", "))
+		if variant.Output == "" {
+			colors.PrintData("(no output)\n")
+		} else {
+			colors.PrintData("%s\n", variant.Output)
+		}
+	}
+
+	colors.PrintData("\n")
+	completed := 0
+	for _, variant := range comparison.Variants {
+		completed += len(variant.Instances)
+	}
+	majority, hasMajority := comparison.majority()
+	switch {
+	case len(comparison.Variants) == 0:
+		colors.PrintWarning("⚠ No instance completed the command, nothing to compare\n")
+	case len(comparison.Variants) == 1:
+		colors.PrintSuccess("✓ All %d completed instance(s) produced identical output\n", completed)
+	case hasMajority:
+		colors.PrintWarning("⚠ %d distinct outputs: %d of %d instance(s) match the majority (variant 1)\n", len(comparison.Variants), len(majority.Instances), completed)
+		colors.PrintWarning("Outliers: %s\n", strings.Join(comparison.outliers(), ", "))
+	default:
+		colors.PrintWarning("⚠ %d distinct outputs and no majority: the largest variants each have %d instance(s)\n", len(comparison.Variants), len(comparison.Variants[0].Instances))
+	}
+	if len(comparison.Errors) > 0 {
+		colors.PrintError("✗ Not compared (execution failed): %s\n", strings.Join(comparison.Errors, ", "))
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func compareResult(name, output string, exitCode int32) ParallelExecutionResult {
+	return ParallelExecutionResult{
+		Instance: interactive.Instance{InstanceID: "i-" + name, Name: name},
+		Result:   &ssm.CommandResult{Output: output, ExitCode: &exitCode},
+	}
+}
+
+func TestCompareOutputs(t *testing.T) {
+	results := []ParallelExecutionResult{
+		compareResult("web-1", "openssl-3.0.7\n", 0),
+		compareResult("web-2", "openssl-1.1.1\n", 0),
+		compareResult("web-3", "openssl-3.0.7", 0),
+		compareResult("web-4", "openssl-3.0.7\n", 1),
+		compareResult("web-5", "openssl-3.0.7\n", 0),
+		{Instance: interactive.Instance{InstanceID: "i-web-6", Name: "web-6"}, Error: errors.New("timed out")},
+	}
+
+	comparison := compareOutputs(results)
+	if len(comparison.Variants) != 3 {
+		t.Fatalf("Expected 3 variants, got %+v", comparison.Variants)
+	}
+	if got := strings.Join(comparison.Variants[0].Instances, ","); got != "web-1 (i-web-1),web-3 (i-web-3),web-5 (i-web-5)" {
+		t.Errorf("Expected the trailing newline to be ignored in the majority, got %s", got)
+	}
+	if comparison.Variants[1].Output != "openssl-1.1.1" || comparison.Variants[2].ExitCode != 1 {
+		t.Errorf("Expected outliers in first-seen order, got %+v", comparison.Variants[1:])
+	}
+	if len(comparison.Errors) != 1 || comparison.Errors[0] != "web-6 (i-web-6)" {
+		t.Errorf("Expected web-6 reported as not compared, got %v", comparison.Errors)
+	}
+
+	majority, ok := comparison.majority()
+	if !ok || len(majority.Instances) != 3 {
+		t.Errorf("Expected a majority of 3, got %+v, %v", majority, ok)
+	}
+	if got := strings.Join(comparison.outliers(), ","); got != "web-2 (i-web-2),web-4 (i-web-4)" {
+		t.Errorf("outliers() = %s", got)
+	}
+}
+
+func TestOutputComparisonWithoutMajority(t *testing.T) {
+	comparison := compareOutputs([]ParallelExecutionResult{
+		compareResult("a", "x", 0),
+		compareResult("b", "y", 0),
+	})
+	if _, ok := comparison.majority(); ok {
+		t.Error("Expected no majority for a tie")
+	}
+	if _, ok := (outputComparison{}).majority(); ok {
+		t.Error("Expected no majority without variants")
+	}
+}
+
+func TestPrintOutputComparison(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	printOutputComparison(compareOutputs([]ParallelExecutionResult{
+		compareResult("web-1", "v2\n", 0),
+		compareResult("web-2", "v2\n", 0),
+		compareResult("web-3", "v1\n", 0),
+	}))
+
+	output := buf.String()
+	for _, want := range []string{
+		"Variant 1: 2 instance(s), exit code 0",
+		"Instances: web-1 (i-web-1), web-2 (i-web-2)",
+		"2 distinct outputs: 2 of 3 instance(s) match the majority",
+		"Outliers: web-3 (i-web-3)",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output:\n%s", want, output)
+		}
+	}
+
+	buf.Reset()
+	printOutputComparison(compareOutputs([]ParallelExecutionResult{compareResult("web-1", "same", 0), compareResult("web-2", "same", 0)}))
+	if !strings.Contains(buf.String(), "All 2 completed instance(s) produced identical output") {
+		t.Errorf("Expected an identical-output summary, got:\n%s", buf.String())
+	}
+}
+
+func TestResolveExecOptionsOutputCompare(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd} {
+		if cmd.Flags().Lookup("output-compare") == nil {
+			t.Errorf("Expected --output-compare flag on %s", cmd.Name())
+		}
+	}
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		addOutputCompareFlag(cmd)
+		addOutputModeFlag(cmd)
+		addHideOutputFlags(cmd)
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+
+	opts, err := resolveExecOptions(newCmd("--output-compare"))
+	if err != nil || !opts.OutputCompare {
+		t.Errorf("Expected --output-compare to be set, got %+v (%v)", opts.OutputCompare, err)
+	}
+	for _, args := range [][]string{
+		{"--output-compare", "--hide-output"},
+		{"--output-compare", "--output-mode", "interleaved"},
+	} {
+		if _, err := resolveExecOptions(newCmd(args...)); err == nil || !strings.Contains(err.Error(), "--output-compare") {
+			t.Errorf("Expected %v to be rejected, got %v", args, err)
+		}
+	}
+}
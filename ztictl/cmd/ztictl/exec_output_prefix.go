@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"ztictl/internal/interactive"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// outputPrefixID labels interleaved lines with the instance ID
+	outputPrefixID = "id"
+	// outputPrefixName labels interleaved lines with the instance's Name tag, falling back to its ID
+	outputPrefixName = "name"
+	// outputPrefixIndex labels interleaved lines with the instance's position in the target list
+	outputPrefixIndex = "index"
+	// outputPrefixNone prints interleaved output lines without a label
+	outputPrefixNone = "none"
+)
+
+// nameTagLookup returns instance tags for the name prefix of instances listed without a Name; tests replace it
+var nameTagLookup = describeInstanceTags
+
+// addOutputPrefixFlag registers --output-prefix with shell completion for its values
+func addOutputPrefixFlag(cmd *cobra.Command) {
+	cmd.Flags().String("output-prefix", outputPrefixID, "Label before each interleaved output line: id, name (Name tag), index (position in the target list) or none")
+	_ = cmd.RegisterFlagCompletionFunc("output-prefix", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputPrefixID, outputPrefixName, outputPrefixIndex, outputPrefixNone}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// resolveOutputPrefix reads --output-prefix, which only applies to --output-mode interleaved
+func resolveOutputPrefix(cmd *cobra.Command, outputMode string) (string, error) {
+	flag := cmd.Flags().Lookup("output-prefix")
+	if flag == nil {
+		return outputPrefixID, nil
+	}
+
+	switch flag.Value.String() {
+	case outputPrefixID, outputPrefixName, outputPrefixIndex, outputPrefixNone:
+	default:
+		return "", fmt.Errorf("invalid --output-prefix '%s' (expected %s, %s, %s or %s)", flag.Value.String(), outputPrefixID, outputPrefixName, outputPrefixIndex, outputPrefixNone)
+	}
+	if flag.Changed && outputMode != outputModeInterleaved {
+		return "", fmt.Errorf("--output-prefix requires --output-mode %s", outputModeInterleaved)
+	}
+	return flag.Value.String(), nil
+}
+
+// withLinePrefixes returns options carrying the interleaved label of each instance. Name tags
+// missing from the target list are looked up once here, not per line.
+func (o execOptions) withLinePrefixes(ctx context.Context, region string, instances []interactive.Instance) execOptions {
+	o.linePrefixes = make(map[string]string, len(instances))
+	switch o.OutputPrefix {
+	case outputPrefixName:
+		var unnamed []string
+		for _, instance := range instances {
+			if instance.Name != "" && instance.Name != instance.InstanceID {
+				o.linePrefixes[instance.InstanceID] = instance.Name
+			} else {
+				unnamed = append(unnamed, instance.InstanceID)
+			}
+		}
+		if len(unnamed) > 0 {
+			tags, err := nameTagLookup(ctx, region, unnamed)
+			if err != nil {
+				logging.LogWarn("Could not look up Name tags, labelling those instances by ID: %v", err)
+			}
+			for _, id := range unnamed {
+				if name := tags[id]["Name"]; name != "" {
+					o.linePrefixes[id] = name
+				}
+			}
+		}
+	case outputPrefixIndex:
+		width := len(fmt.Sprint(len(instances)))
+		for i, instance := range instances {
+			o.linePrefixes[instance.InstanceID] = fmt.Sprintf("%0*d", width, i+1)
+		}
+	}
+	return o
+}
+
+// instanceLabel returns the label identifying an instance in interleaved output. It is used for the
+// status line even with --output-prefix none, so every result stays attributable.
+func (o execOptions) instanceLabel(instanceID string) string {
+	if label, ok := o.linePrefixes[instanceID]; ok {
+		return label
+	}
+	return instanceID
+}
+
+// linePrefix returns the text printed before each interleaved output line of an instance
+func (o execOptions) linePrefix(instanceID, stream string) string {
+	if o.OutputPrefix == outputPrefixNone {
+		return ""
+	}
+	if stream != "" {
+		return fmt.Sprintf("[%s:%s] ", o.instanceLabel(instanceID), stream)
+	}
+	return fmt.Sprintf("[%s] ", o.instanceLabel(instanceID))
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func TestResolveOutputPrefix(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("output-prefix") == nil {
+			t.Errorf("Expected --output-prefix flag on %s", cmd.Name())
+		}
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		outputMode string
+		want       string
+		wantErr    string
+	}{
+		{name: "default", outputMode: outputModeGrouped, want: outputPrefixID},
+		{name: "name", args: []string{"--output-prefix", "name"}, outputMode: outputModeInterleaved, want: outputPrefixName},
+		{name: "none", args: []string{"--output-prefix", "none"}, outputMode: outputModeInterleaved, want: outputPrefixNone},
+		{name: "invalid", args: []string{"--output-prefix", "tag"}, outputMode: outputModeInterleaved, wantErr: "invalid --output-prefix"},
+		{name: "grouped mode", args: []string{"--output-prefix", "index"}, outputMode: outputModeGrouped, wantErr: "requires --output-mode interleaved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addOutputPrefixFlag(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			got, err := resolveOutputPrefix(cmd, tt.outputMode)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("resolveOutputPrefix() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithLinePrefixes(t *testing.T) {
+	var lookups [][]string
+	originalLookup := nameTagLookup
+	nameTagLookup = func(ctx context.Context, region string, instanceIDs []string) (map[string]map[string]string, error) {
+		lookups = append(lookups, instanceIDs)
+		return map[string]map[string]string{"i-0b": {"Name": "worker-2"}}, nil
+	}
+	defer func() { nameTagLookup = originalLookup }()
+
+	instances := []interactive.Instance{
+		{InstanceID: "i-0a", Name: "web-1"},
+		{InstanceID: "i-0b", Name: "i-0b"},
+		{InstanceID: "i-0c"},
+	}
+
+	opts := execOptions{OutputPrefix: outputPrefixName}.withLinePrefixes(context.Background(), "ca-central-1", instances)
+	for id, want := range map[string]string{"i-0a": "web-1", "i-0b": "worker-2", "i-0c": "i-0c"} {
+		if got := opts.instanceLabel(id); got != want {
+			t.Errorf("instanceLabel(%s) = %q, want %q", id, got, want)
+		}
+	}
+	if len(lookups) != 1 || strings.Join(lookups[0], ",") != "i-0b,i-0c" {
+		t.Errorf("Expected one Name tag lookup for the unnamed instances, got %v", lookups)
+	}
+
+	nameTagLookup = func(ctx context.Context, region string, instanceIDs []string) (map[string]map[string]string, error) {
+		return nil, fmt.Errorf("access denied")
+	}
+	opts = execOptions{OutputPrefix: outputPrefixName}.withLinePrefixes(context.Background(), "ca-central-1", instances)
+	if got := opts.instanceLabel("i-0c"); got != "i-0c" {
+		t.Errorf("Expected the instance ID when the lookup fails, got %q", got)
+	}
+
+	many := make([]interactive.Instance, 12)
+	for i := range many {
+		many[i] = interactive.Instance{InstanceID: fmt.Sprintf("i-%02d", i)}
+	}
+	opts = execOptions{OutputPrefix: outputPrefixIndex}.withLinePrefixes(context.Background(), "ca-central-1", many)
+	if got := opts.linePrefix("i-00", ""); got != "[01] " {
+		t.Errorf("Expected a zero-padded index, got %q", got)
+	}
+	if got := opts.linePrefix("i-11", "stderr"); got != "[12:stderr] " {
+		t.Errorf("Expected the index with the stream, got %q", got)
+	}
+}
+
+func TestPrintInterleavedResultOutputPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	instance := interactive.Instance{InstanceID: "i-0web1", Name: "web-1"}
+	result := ParallelExecutionResult{
+		Instance: instance,
+		Result:   &ssm.CommandResult{Output: "up 3 days\n", ErrorOutput: "warning\n"},
+	}
+
+	opts := execOptions{OutputPrefix: outputPrefixName}.withLinePrefixes(context.Background(), "ca-central-1", []interactive.Instance{instance})
+	printInterleavedResult(result, opts)
+	opts = execOptions{OutputPrefix: outputPrefixNone}.withLinePrefixes(context.Background(), "ca-central-1", []interactive.Instance{instance})
+	printInterleavedResult(result, opts)
+
+	output := buf.String()
+	for _, want := range []string{"[web-1] up 3 days\n", "[web-1:stderr] warning\n", "[web-1] ✓ exit code 0", "\nup 3 days\n", "\nwarning\n", "[i-0web1] ✓ exit code 0"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in interleaved output, got:\n%s", want, output)
+		}
+	}
+}
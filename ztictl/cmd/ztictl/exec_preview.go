@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"ztictl/internal/interactive"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// maxPreviewInstances caps how many instance names the target preview lists without --list-targets
+const maxPreviewInstances = 10
+
+// targetPreview summarizes the instances a tag selector resolved to before any command is sent, and
+// asks for confirmation in interactive sessions. It guards against tags that match far more than intended.
+type targetPreview struct {
+	listAll bool      // List every target instead of a sample
+	prompt  bool      // Ask before sending; false with --yes and in non-interactive sessions
+	input   io.Reader // Where the answer is read from
+}
+
+// addTargetPreviewFlags registers --list-targets and --no-preview
+func addTargetPreviewFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("list-targets", false, "List every targeted instance in the preview shown before the command is sent")
+	cmd.Flags().Bool("no-preview", false, "Skip the target preview and its confirmation prompt")
+}
+
+// newTargetPreview creates the preview for a command from its flags, or nil with --no-preview.
+// --yes and sessions that cannot answer a prompt still print the preview but do not wait for an answer.
+func newTargetPreview(cmd *cobra.Command) *targetPreview {
+	if noPreview, _ := cmd.Flags().GetBool("no-preview"); noPreview {
+		return nil
+	}
+	listAll, _ := cmd.Flags().GetBool("list-targets")
+	execCtx := GetExecutionContext(cmd)
+
+	return &targetPreview{
+		listAll: listAll,
+		prompt:  !execCtx.AutoYes && !execCtx.NonInteractive && term.IsTerminal(int(os.Stdin.Fd())),
+		input:   os.Stdin,
+	}
+}
+
+// confirm prints the preview for the instances about to be targeted in region and, when prompting,
+// returns an error unless the user agrees. A nil preview does nothing.
+func (p *targetPreview) confirm(region string, instances []interactive.Instance) error {
+	if p == nil || (quiet && !p.prompt) {
+		return nil
+	}
+
+	colors.PrintData("\n")
+	for _, line := range previewLines(region, instances, p.listAll) {
+		colors.PrintHeader("%s\n", line)
+	}
+	if !p.prompt {
+		return nil
+	}
+
+	// The prompt goes through colors so that --output json keeps stdout clean
+	colors.PrintData("Run the command on these %d instance(s)? (yes/no): ", len(instances))
+	response, _ := bufio.NewReader(p.input).ReadString('\n')
+	if answer := strings.ToLower(strings.TrimSpace(response)); answer != "yes" && answer != "y" {
+		return fmt.Errorf("execution cancelled at the target preview")
+	}
+	logging.LogInfo("Target preview confirmed by user | region=%s targets=%d", region, len(instances))
+	return nil
+}
+
+// previewLines renders the target count, the spread over availability zones and a sample of instances
+func previewLines(region string, instances []interactive.Instance, listAll bool) []string {
+	lines := []string{fmt.Sprintf("This will target %d instance(s) in %s", len(instances), region)}
+
+	zones := map[string]int{}
+	for _, instance := range instances {
+		zone := instance.AvailabilityZone
+		if zone == "" {
+			zone = "unknown"
+		}
+		zones[zone]++
+	}
+	names := sortedKeys(zones)
+	sort.SliceStable(names, func(i, j int) bool { return zones[names[i]] > zones[names[j]] })
+	counts := make([]string, len(names))
+	for i, zone := range names {
+		counts[i] = fmt.Sprintf("%s (%d)", zone, zones[zone])
+	}
+	lines = append(lines, "  Availability zones: "+strings.Join(counts, ", "))
+
+	shown := instances
+	if !listAll && len(shown) > maxPreviewInstances {
+		shown = shown[:maxPreviewInstances]
+	}
+	for _, instance := range shown {
+		lines = append(lines, fmt.Sprintf("  - %s (%s)", instance.Name, instance.InstanceID))
+	}
+	if more := len(instances) - len(shown); more > 0 {
+		lines = append(lines, fmt.Sprintf("  ... and %d more (use --list-targets to list all)", more))
+	}
+	return lines
+}
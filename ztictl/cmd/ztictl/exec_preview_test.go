@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"ztictl/internal/interactive"
+)
+
+func previewInstances(count int) []interactive.Instance {
+	instances := make([]interactive.Instance, count)
+	for i := range instances {
+		zone := "ca-central-1a"
+		if i%3 == 0 {
+			zone = "ca-central-1b"
+		}
+		instances[i] = interactive.Instance{InstanceID: fmt.Sprintf("i-%03d", i), Name: fmt.Sprintf("web-%d", i), AvailabilityZone: zone}
+	}
+	return instances
+}
+
+func TestPreviewLines(t *testing.T) {
+	instances := previewInstances(12)
+	instances = append(instances, interactive.Instance{InstanceID: "i-explicit", Name: "i-explicit"})
+
+	lines := previewLines("ca-central-1", instances, false)
+	if lines[0] != "This will target 13 instance(s) in ca-central-1" {
+		t.Errorf("Unexpected header %q", lines[0])
+	}
+	if lines[1] != "  Availability zones: ca-central-1a (8), ca-central-1b (4), unknown (1)" {
+		t.Errorf("Unexpected zone line %q", lines[1])
+	}
+	if len(lines) != 2+maxPreviewInstances+1 {
+		t.Fatalf("Expected a sample of %d instances, got %d lines", maxPreviewInstances, len(lines))
+	}
+	if !strings.Contains(lines[len(lines)-1], "and 3 more") {
+		t.Errorf("Expected the remainder to be counted, got %q", lines[len(lines)-1])
+	}
+
+	all := previewLines("ca-central-1", instances, true)
+	if len(all) != 2+len(instances) {
+		t.Errorf("Expected --list-targets to list all %d instances, got %d lines", len(instances), len(all))
+	}
+}
+
+func TestTargetPreviewConfirm(t *testing.T) {
+	instances := previewInstances(2)
+
+	var preview *targetPreview
+	if err := preview.confirm("ca-central-1", instances); err != nil {
+		t.Errorf("Expected a nil preview to pass, got %v", err)
+	}
+
+	if err := (&targetPreview{}).confirm("ca-central-1", instances); err != nil {
+		t.Errorf("Expected a preview without a prompt to pass, got %v", err)
+	}
+
+	accepted := &targetPreview{prompt: true, input: strings.NewReader("yes\n")}
+	if err := accepted.confirm("ca-central-1", instances); err != nil {
+		t.Errorf("Expected 'yes' to confirm, got %v", err)
+	}
+
+	declined := &targetPreview{prompt: true, input: strings.NewReader("n\n")}
+	if err := declined.confirm("ca-central-1", instances); err == nil {
+		t.Error("Expected 'n' to cancel the run")
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// addRateFlag registers --rate, the cap on how fast exec sends new SendCommand calls
+func addRateFlag(cmd *cobra.Command) {
+	cmd.Flags().String("rate", "", "Maximum SendCommand calls per second, as 5/s or 30/m (default unlimited)")
+}
+
+// parseDispatchRate parses a --rate value into calls per second; an empty value means unlimited (0)
+func parseDispatchRate(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	number, per := value, time.Second
+	if n, ok := strings.CutSuffix(value, "/s"); ok {
+		number = n
+	} else if n, ok := strings.CutSuffix(value, "/m"); ok {
+		number, per = n, time.Minute
+	}
+
+	rate, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || rate <= 0 || math.IsInf(rate, 0) {
+		return 0, fmt.Errorf("invalid --rate '%s' (expected calls per second such as 5/s, or per minute such as 30/m)", value)
+	}
+	return rate / per.Seconds(), nil
+}
+
+// dispatchLimiter is a token bucket that spaces out SendCommand calls. The bucket holds up to one
+// second's worth of calls and starts full, so a run can send that many at once before being paced.
+type dispatchLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64 // Negative while callers are waiting for tokens they have already reserved
+	last      time.Time
+
+	now func() time.Time
+}
+
+// newDispatchLimiter creates a limiter for perSecond calls; it returns nil, which never waits, for 0
+func newDispatchLimiter(perSecond float64) *dispatchLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	burst := math.Max(1, math.Floor(perSecond))
+	return &dispatchLimiter{perSecond: perSecond, burst: burst, tokens: burst, last: time.Now(), now: time.Now}
+}
+
+// reserve takes a token and returns how long the caller must wait before using it
+func (l *dispatchLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = math.Min(l.burst, l.tokens+elapsed.Seconds()*l.perSecond)
+		l.last = now
+	}
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+}
+
+// wait blocks until the next call may be sent, or returns the context's error if it ends first.
+// A nil limiter returns immediately.
+func (l *dispatchLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDispatchRate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "5/s", want: 5},
+		{value: "5", want: 5},
+		{value: " 2.5/s ", want: 2.5},
+		{value: "30/m", want: 0.5},
+		{value: "0/s", wantErr: true},
+		{value: "-1", wantErr: true},
+		{value: "fast", wantErr: true},
+		{value: "5/h", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDispatchRate(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseDispatchRate(%q) expected an error, got %v", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseDispatchRate(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestDispatchLimiterPacesAfterBurst(t *testing.T) {
+	now := time.Now()
+	l := newDispatchLimiter(5)
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	for i := 0; i < 5; i++ {
+		if delay := l.reserve(); delay != 0 {
+			t.Fatalf("Expected call %d within the burst to go immediately, waited %v", i+1, delay)
+		}
+	}
+	if delay := l.reserve(); delay != 200*time.Millisecond {
+		t.Errorf("Expected the 6th call to wait 200ms, got %v", delay)
+	}
+	if delay := l.reserve(); delay != 400*time.Millisecond {
+		t.Errorf("Expected the 7th call to wait 400ms, got %v", delay)
+	}
+
+	// Idle time refills the bucket, but never beyond one second's worth of calls
+	now = now.Add(10 * time.Second)
+	for i := 0; i < 5; i++ {
+		if delay := l.reserve(); delay != 0 {
+			t.Fatalf("Expected call %d after idling to go immediately, waited %v", i+1, delay)
+		}
+	}
+	if delay := l.reserve(); delay == 0 {
+		t.Error("Expected the bucket to be capped at the burst size")
+	}
+}
+
+func TestDispatchLimiterBelowOnePerSecond(t *testing.T) {
+	now := time.Now()
+	l := newDispatchLimiter(0.5)
+	l.now = func() time.Time { return now }
+	l.last = now
+
+	if delay := l.reserve(); delay != 0 {
+		t.Fatalf("Expected the first call to go immediately, waited %v", delay)
+	}
+	if delay := l.reserve(); delay != 2*time.Second {
+		t.Errorf("Expected the second call to wait 2s at 30/m, got %v", delay)
+	}
+}
+
+func TestDispatchLimiterWait(t *testing.T) {
+	var unlimited *dispatchLimiter
+	if newDispatchLimiter(0) != nil {
+		t.Fatal("Expected no limiter for an unlimited rate")
+	}
+	if err := unlimited.wait(context.Background()); err != nil {
+		t.Errorf("Expected a nil limiter not to wait, got %v", err)
+	}
+
+	l := newDispatchLimiter(1)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("Expected the first call to go immediately, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled wait to return context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"text/template"
+
+	"ztictl/internal/config"
+	"ztictl/internal/platform"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/security"
+)
+
+// newOutputRedactor builds the redactor for command output from exec.redact_patterns, plus the
+// built-in patterns unless exec.redact_defaults is false. It returns nil when nothing is redacted.
+func newOutputRedactor() (*security.Redactor, error) {
+	execConfig := config.Get().Exec
+	var patterns []string
+	if execConfig.RedactDefaults {
+		patterns = append(patterns, security.DefaultRedactPatterns...)
+	}
+	patterns = append(patterns, execConfig.RedactPatterns...)
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	return security.NewRedactor(patterns)
+}
+
+// commandWrapperTemplate parses exec.command_wrapper_template. It returns nil when no wrapper is configured.
+func commandWrapperTemplate() (*template.Template, error) {
+	text := config.Get().Exec.CommandWrapperTemplate
+	if text == "" {
+		return nil, nil
+	}
+	tmpl, err := platform.ParseCommandTemplate(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exec.command_wrapper_template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// redactResult masks secrets in a command result as soon as it is received, so that the terminal,
+// reports, chunk files and hooks only ever see the redacted output
+func redactResult(redactor *security.Redactor, result *ssm.CommandResult) {
+	if result == nil {
+		return
+	}
+	result.Output = redactor.Redact(result.Output)
+	result.ErrorOutput = redactor.Redact(result.ErrorOutput)
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/security"
+
+	"github.com/spf13/cobra"
+)
+
+// Output formats for exec results (--output)
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+	outputFormatYAML = "yaml"
+)
+
+// Instance statuses in an exec report
+const (
+	reportStatusSuccess  = "success"   // Exited with code 0
+	reportStatusFailed   = "failed"    // Exited with a non-zero code
+	reportStatusError    = "error"     // Could not be run or its result could not be read
+	reportStatusTimedOut = "timed_out" // Still running when ztictl stopped waiting
+)
+
+// execReport is the aggregated result of an exec run, printed by --output json or yaml and written by --output-file
+type execReport struct {
+	RunID      string               `json:"run_id,omitempty"`
+	Label      string               `json:"label,omitempty"`
+	Command    string               `json:"command"`
+	Regions    []string             `json:"regions"`
+	StartedAt  time.Time            `json:"started_at"`
+	DurationMS int64                `json:"duration_ms"`
+	Instances  []execReportInstance `json:"instances"`
+	Summary    execReportSummary    `json:"summary"`
+
+	// RegionErrors holds failures that affected a whole region in exec-multi, keyed by region
+	RegionErrors map[string]string `json:"region_errors,omitempty"`
+	// RegionDurationsMS holds how long each region took in exec-multi, keyed by region
+	RegionDurationsMS map[string]int64 `json:"region_duration_ms,omitempty"`
+
+	// GroupByTag is the --group-by-tag key; instances are then listed under Groups instead of Instances
+	GroupByTag string             `json:"group_by_tag,omitempty"`
+	Groups     []*execReportGroup `json:"groups,omitempty"`
+}
+
+// execReportGroup holds the instances sharing one --group-by-tag value (empty for untagged instances)
+type execReportGroup struct {
+	Value     string               `json:"value"`
+	Instances []execReportInstance `json:"instances"`
+	Summary   execReportSummary    `json:"summary"`
+}
+
+// execReportInstance is the outcome on one instance
+type execReportInstance struct {
+	InstanceID  string `json:"instance_id"`
+	Name        string `json:"name,omitempty"`
+	Region      string `json:"region,omitempty"`
+	Status      string `json:"status"`
+	ExitCode    *int32 `json:"exit_code,omitempty"`
+	Output      string `json:"output"`
+	ErrorOutput string `json:"error_output,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DurationMS  int64  `json:"duration_ms"`
+	Attempts    int    `json:"attempts,omitempty"` // Recorded when --retries is set
+
+	// Output of each --command step that started, split at the step markers
+	Steps []execReportStep `json:"steps,omitempty"`
+
+	// Local path of the --collect file, or why it could not be downloaded
+	Collected    string `json:"collected,omitempty"`
+	CollectError string `json:"collect_error,omitempty"`
+
+	// Set when SSM cut the inline output at its limit
+	OutputTruncated      bool `json:"output_truncated,omitempty"`
+	ErrorOutputTruncated bool `json:"error_output_truncated,omitempty"`
+
+	group string // --group-by-tag value, used to place the entry in a group
+}
+
+// execReportSummary counts outcomes across all targeted instances, including any omitted by --quiet
+type execReportSummary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	Skipped   int `json:"skipped,omitempty"`
+	Excluded  int `json:"excluded,omitempty"`
+}
+
+// newExecReport starts a report for a command run
+func newExecReport(command string, regions []string, startedAt time.Time) *execReport {
+	return &execReport{Command: command, Regions: regions, StartedAt: startedAt, Instances: []execReportInstance{}}
+}
+
+// reportInstance converts a command result to a report entry
+func reportInstance(instanceID, name, region string, result *ssm.CommandResult, err error, duration time.Duration) execReportInstance {
+	entry := execReportInstance{
+		InstanceID: instanceID,
+		Name:       name,
+		Region:     region,
+		Status:     reportStatusSuccess,
+		DurationMS: duration.Milliseconds(),
+	}
+
+	if result != nil {
+		entry.Output = result.Output
+		entry.ErrorOutput = result.ErrorOutput
+		entry.ExitCode = result.ExitCode
+		entry.OutputTruncated = result.OutputTruncated
+		entry.ErrorOutputTruncated = result.ErrorOutputTruncated
+	}
+
+	switch {
+	case result.TimedOut():
+		entry.Status = reportStatusTimedOut
+		if err != nil {
+			entry.Error = err.Error()
+		}
+	case err != nil:
+		entry.Status = reportStatusError
+		entry.Error = err.Error()
+	case result != nil && result.ExitCode != nil && *result.ExitCode != 0:
+		entry.Status = reportStatusFailed
+	}
+	return entry
+}
+
+// add records an instance outcome; in quiet mode only failures are kept, but all are counted
+func (r *execReport) add(entry execReportInstance) {
+	summaries := []*execReportSummary{&r.Summary}
+	instances := &r.Instances
+	if r.GroupByTag != "" {
+		group := r.group(entry.group)
+		summaries = append(summaries, &group.Summary)
+		instances = &group.Instances
+	}
+
+	succeeded := entry.Status == reportStatusSuccess
+	for _, summary := range summaries {
+		summary.Total++
+		if succeeded {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+	}
+	if succeeded && quiet {
+		return
+	}
+	*instances = append(*instances, entry)
+}
+
+// group returns the report group for a --group-by-tag value, adding it on first use
+func (r *execReport) group(value string) *execReportGroup {
+	for _, group := range r.Groups {
+		if group.Value == value {
+			return group
+		}
+	}
+	group := &execReportGroup{Value: value, Instances: []execReportInstance{}}
+	r.Groups = append(r.Groups, group)
+	return group
+}
+
+// addRegionError records a failure that prevented a region from running the command
+func (r *execReport) addRegionError(region string, err error) {
+	if r.RegionErrors == nil {
+		r.RegionErrors = make(map[string]string)
+	}
+	r.RegionErrors[region] = err.Error()
+}
+
+// finish records the total duration of the run and orders any groups by value
+func (r *execReport) finish() {
+	r.DurationMS = time.Since(r.StartedAt).Milliseconds()
+
+	values := make([]string, len(r.Groups))
+	byValue := make(map[string]*execReportGroup, len(r.Groups))
+	for i, group := range r.Groups {
+		values[i] = group.Value
+		byValue[group.Value] = group
+	}
+	sortGroupValues(values)
+	for i, value := range values {
+		r.Groups[i] = byValue[value]
+	}
+}
+
+// structuredOutput reports whether stdout carries machine-readable results (--output json, yaml or jsonl, or --output-template)
+func (o execOptions) structuredOutput() bool {
+	return o.Output == outputFormatJSON || o.Output == outputFormatYAML || o.Output == outputFormatJSONL || o.OutputTemplate != nil
+}
+
+// wantsReport reports whether the run needs an aggregated report
+func (o execOptions) wantsReport() bool {
+	return o.structuredOutput() || o.OutputFile != "" || o.ArchiveS3 != ""
+}
+
+// applyOutputFormat sends human-readable output to stderr when stdout carries the report
+func (o execOptions) applyOutputFormat() {
+	if o.structuredOutput() {
+		colors.SetOutput(os.Stderr)
+	}
+}
+
+// emitReport prints the report to stdout for --output json or yaml, writes it to --output-file and
+// uploads it to --archive-s3. With --output jsonl the instances were already streamed as they completed.
+func (o execOptions) emitReport(report *execReport) error {
+	report.RunID, report.Label = o.RunID, o.Label
+	report.finish()
+
+	switch {
+	case o.Output == outputFormatJSONL:
+	case o.OutputTemplate != nil:
+		if err := writeTemplateReport(os.Stdout, report, o.OutputTemplate); err != nil {
+			return err
+		}
+	case o.structuredOutput():
+		if err := writeExecReport(os.Stdout, report, o.Output, o.prettyJSON(os.Stdout)); err != nil {
+			return err
+		}
+	}
+
+	if o.OutputFile != "" {
+		var buf bytes.Buffer
+		if err := writeExecReport(&buf, report, o.Output, o.JSONStyle == jsonStylePretty); err != nil {
+			return err
+		}
+		if err := os.WriteFile(o.OutputFile, buf.Bytes(), 0600); err != nil {
+			return fmt.Errorf("failed to write --output-file: %w", err)
+		}
+		colors.PrintData("Report written to %s\n", o.OutputFile)
+	}
+	return o.archiveReport(report)
+}
+
+// writeExecReport renders a report in the given format; pretty indents JSON
+func writeExecReport(w io.Writer, report *execReport, format string, pretty bool) error {
+	switch format {
+	case outputFormatJSON:
+		return writeJSON(w, report, pretty)
+	case outputFormatYAML:
+		return writeYAML(w, report)
+	case outputFormatJSONL:
+		return writeJSONLines(w, report)
+	}
+
+	var b strings.Builder
+	if report.RunID != "" {
+		fmt.Fprintf(&b, "Run ID:   %s\n", report.RunID)
+	}
+	if report.Label != "" {
+		fmt.Fprintf(&b, "Label:    %s\n", report.Label)
+	}
+	fmt.Fprintf(&b, "Command:  %s\n", report.Command)
+	fmt.Fprintf(&b, "Regions:  %s\n", strings.Join(report.Regions, ", "))
+	fmt.Fprintf(&b, "Started:  %s\n", report.StartedAt.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Duration: %v\n", time.Duration(report.DurationMS)*time.Millisecond)
+
+	for _, instance := range report.Instances {
+		writeReportInstance(&b, instance)
+	}
+	for _, group := range report.Groups {
+		fmt.Fprintf(&b, "\n##### %s: %s #####\n", report.GroupByTag, tagGroupLabel(report.GroupByTag, group.Value))
+		for _, instance := range group.Instances {
+			writeReportInstance(&b, instance)
+		}
+	}
+
+	if len(report.RegionDurationsMS) > 0 {
+		b.WriteString("\n=== Region timings ===\n")
+		for _, region := range sortedKeys(report.RegionDurationsMS) {
+			fmt.Fprintf(&b, "%s: %v\n", region, time.Duration(report.RegionDurationsMS[region])*time.Millisecond)
+		}
+	}
+
+	if len(report.RegionErrors) > 0 {
+		b.WriteString("\n=== Region errors ===\n")
+		for _, region := range sortedKeys(report.RegionErrors) {
+			fmt.Fprintf(&b, "%s: %s\n", region, report.RegionErrors[region])
+		}
+	}
+
+	b.WriteString("\n=== Summary ===\n")
+	fmt.Fprintf(&b, "Total: %d  Succeeded: %d  Failed: %d", report.Summary.Total, report.Summary.Succeeded, report.Summary.Failed)
+	if report.Summary.Skipped > 0 {
+		fmt.Fprintf(&b, "  Skipped: %d", report.Summary.Skipped)
+	}
+	if report.Summary.Excluded > 0 {
+		fmt.Fprintf(&b, "  Excluded: %d", report.Summary.Excluded)
+	}
+	b.WriteString("\n")
+	for _, group := range report.Groups {
+		fmt.Fprintf(&b, "%s: %d of %d succeeded (%d%%)\n", tagGroupLabel(report.GroupByTag, group.Value),
+			group.Summary.Succeeded, group.Summary.Total, successRate(group.Summary))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeReportInstance renders one instance section of a text report
+func writeReportInstance(b *strings.Builder, instance execReportInstance) {
+	fmt.Fprintf(b, "\n=== %s", instance.InstanceID)
+	if instance.Name != "" && instance.Name != instance.InstanceID {
+		fmt.Fprintf(b, " (%s)", instance.Name)
+	}
+	if instance.Region != "" {
+		fmt.Fprintf(b, " [%s]", instance.Region)
+	}
+	b.WriteString(" ===\n")
+
+	fmt.Fprintf(b, "Status:   %s", instance.Status)
+	if instance.ExitCode != nil {
+		fmt.Fprintf(b, " (exit code %d)", *instance.ExitCode)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(b, "Duration: %v\n", time.Duration(instance.DurationMS)*time.Millisecond)
+	if instance.Error != "" {
+		fmt.Fprintf(b, "Error:    %s\n", instance.Error)
+	}
+	if instance.Collected != "" {
+		fmt.Fprintf(b, "Collected: %s\n", instance.Collected)
+	}
+	if instance.CollectError != "" {
+		fmt.Fprintf(b, "Collect error: %s\n", instance.CollectError)
+	}
+	if instance.Output != "" {
+		fmt.Fprintf(b, "--- output ---\n%s\n", strings.TrimRight(instance.Output, "\n"))
+	}
+	if instance.ErrorOutput != "" {
+		fmt.Fprintf(b, "--- error output ---\n%s\n", strings.TrimRight(instance.ErrorOutput, "\n"))
+	}
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// addReportFlags registers --output and --output-file for exec commands
+func addReportFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", outputFormatText, "Output format: text, json, yaml or jsonl (json and yaml print an aggregated report to stdout, jsonl prints one line per instance as it completes; progress goes to stderr)")
+	cmd.Flags().String("output-file", "", "Also write an aggregated report with per-instance sections and a summary to this file (in the --output format)")
+	addJSONStyleFlags(cmd)
+	addArchiveFlag(cmd)
+	cmd.Flags().String("output-template", "", "Render each instance result to stdout with a Go text/template, e.g. '{{.InstanceID}}: {{.ExitCode}}' (progress goes to stderr)")
+	_ = cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputFormatText, outputFormatJSON, outputFormatYAML, outputFormatJSONL}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// validateOutputFile checks an --output-file path before any command is sent
+func validateOutputFile(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := security.ValidateFilePathWithWorkingDir(path); err != nil {
+		return fmt.Errorf("invalid --output-file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/internal/ssm"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func TestReportInstanceStatus(t *testing.T) {
+	zero, three := int32(0), int32(3)
+
+	tests := []struct {
+		name   string
+		result *ssm.CommandResult
+		err    error
+		want   string
+	}{
+		{name: "success", result: &ssm.CommandResult{ExitCode: &zero}, want: reportStatusSuccess},
+		{name: "no exit code", result: &ssm.CommandResult{}, want: reportStatusSuccess},
+		{name: "non-zero exit", result: &ssm.CommandResult{ExitCode: &three}, want: reportStatusFailed},
+		{name: "error", err: fmt.Errorf("instance not found"), want: reportStatusError},
+		{name: "timed out", result: &ssm.CommandResult{Status: ssm.CommandStatusTimedOut}, err: fmt.Errorf("timed out"), want: reportStatusTimedOut},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := reportInstance("i-1", "web-1", "us-east-1", tt.result, tt.err, time.Second)
+			if entry.Status != tt.want {
+				t.Errorf("Expected status %q, got %q", tt.want, entry.Status)
+			}
+			if tt.err != nil && entry.Error != tt.err.Error() {
+				t.Errorf("Expected error %q, got %q", tt.err.Error(), entry.Error)
+			}
+		})
+	}
+}
+
+func TestExecReportQuietKeepsFailuresOnly(t *testing.T) {
+	originalQuiet := quiet
+	quiet = true
+	defer func() { quiet = originalQuiet }()
+
+	three := int32(3)
+	report := newExecReport("uptime", []string{"us-east-1"}, time.Now())
+	report.add(reportInstance("i-ok", "", "us-east-1", &ssm.CommandResult{}, nil, 0))
+	report.add(reportInstance("i-bad", "", "us-east-1", &ssm.CommandResult{ExitCode: &three}, nil, 0))
+
+	if len(report.Instances) != 1 || report.Instances[0].InstanceID != "i-bad" {
+		t.Errorf("Expected only the failed instance in quiet mode, got %+v", report.Instances)
+	}
+	if report.Summary.Total != 2 || report.Summary.Succeeded != 1 || report.Summary.Failed != 1 {
+		t.Errorf("Expected summary to count every instance, got %+v", report.Summary)
+	}
+}
+
+func TestWriteExecReport(t *testing.T) {
+	three := int32(3)
+	report := newExecReport("uptime", []string{"us-east-1", "eu-west-1"}, time.Now())
+	report.add(reportInstance("i-web1", "web-1", "us-east-1", &ssm.CommandResult{Output: "up 3 days\n"}, nil, time.Second))
+	report.add(reportInstance("i-web2", "web-2", "eu-west-1", &ssm.CommandResult{Output: "", ErrorOutput: "boom", ExitCode: &three}, nil, time.Second))
+	report.addRegionError("ap-southeast-1", fmt.Errorf("failed to list instances"))
+	report.Summary.Excluded = 1
+	report.RegionDurationsMS = map[string]int64{"us-east-1": 1500, "eu-west-1": 900}
+	report.finish()
+
+	var text bytes.Buffer
+	if err := writeExecReport(&text, report, outputFormatText, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Command:  uptime", "=== i-web1 (web-1) [us-east-1] ===", "up 3 days", "Status:   failed (exit code 3)", "--- error output ---\nboom", "ap-southeast-1: failed to list instances", "=== Region timings ===\neu-west-1: 900ms\nus-east-1: 1.5s", "Total: 2  Succeeded: 1  Failed: 1  Excluded: 1"} {
+		if !strings.Contains(text.String(), want) {
+			t.Errorf("Expected %q in text report, got:\n%s", want, text.String())
+		}
+	}
+
+	var data bytes.Buffer
+	if err := writeExecReport(&data, report, outputFormatJSON, true); err != nil {
+		t.Fatal(err)
+	}
+	var decoded execReport
+	if err := json.Unmarshal(data.Bytes(), &decoded); err != nil {
+		t.Fatalf("Report is not valid JSON: %v", err)
+	}
+	if len(decoded.Instances) != 2 || *decoded.Instances[1].ExitCode != 3 || decoded.Summary.Failed != 1 || decoded.RegionDurationsMS["us-east-1"] != 1500 {
+		t.Errorf("Unexpected decoded report: %+v", decoded)
+	}
+}
+
+func TestEmitReportWritesFile(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+
+	opts := execOptions{Output: outputFormatText, OutputFile: "change-1234.txt"}
+	report := newExecReport("uptime", []string{"us-east-1"}, time.Now())
+	report.add(reportInstance("i-web1", "", "us-east-1", &ssm.CommandResult{Output: "ok"}, nil, 0))
+	if err := opts.emitReport(report); err != nil {
+		t.Fatalf("emitReport() error = %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "change-1234.txt"))
+	if err != nil {
+		t.Fatalf("Expected report file: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 && os.PathSeparator == '/' {
+		t.Errorf("Expected 0600 permissions, got %v", perm)
+	}
+}
+
+func TestResolveExecOptionsOutput(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("output") == nil || cmd.Flags().Lookup("output-file") == nil {
+			t.Errorf("Expected --output and --output-file flags on %s", cmd.Name())
+		}
+	}
+
+	tests := []struct {
+		output, file string
+		wantErr      bool
+	}{
+		{output: "json"},
+		{output: "text", file: "report.txt"},
+		{output: "xml", wantErr: true},
+		{output: "text", file: "../../etc/report.txt", wantErr: true},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{Use: "test"}
+		addReportFlags(cmd)
+		_ = cmd.Flags().Set("output", tt.output)
+		_ = cmd.Flags().Set("output-file", tt.file)
+
+		opts, err := resolveExecOptions(cmd)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("--output %s --output-file %q: error = %v, wantErr %v", tt.output, tt.file, err, tt.wantErr)
+		}
+		if !tt.wantErr && (opts.Output != tt.output || opts.OutputFile != tt.file) {
+			t.Errorf("Unexpected options: %+v", opts)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ztictl/internal/ssm"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// maxCommandRetries bounds --retries so a failing fleet cannot keep a run going indefinitely
+	maxCommandRetries = 10
+	// defaultRetryDelay is how long --retries waits before re-running a failed command
+	defaultRetryDelay = 5 * time.Second
+)
+
+// addRetryFlags registers --retries and --retry-delay for exec commands
+func addRetryFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("retries", 0, fmt.Sprintf("Re-run the command on an instance up to this many times while it exits with a non-zero code (max %d)", maxCommandRetries))
+	cmd.Flags().Duration("retry-delay", defaultRetryDelay, "Time to wait before each --retries attempt")
+}
+
+// validateRetries checks --retries and --retry-delay values
+func validateRetries(retries int, delay time.Duration) error {
+	if retries < 0 || retries > maxCommandRetries {
+		return fmt.Errorf("invalid --retries %d (expected 0 to %d)", retries, maxCommandRetries)
+	}
+	if delay < 0 {
+		return fmt.Errorf("invalid --retry-delay %v (must not be negative)", delay)
+	}
+	return nil
+}
+
+// exitedNonZero reports whether a command completed with a non-zero exit code
+func exitedNonZero(result *ssm.CommandResult) bool {
+	return result != nil && result.ExitCode != nil && *result.ExitCode != 0
+}
+
+// retryOnExitCode takes the result of a command's first attempt on an instance and re-runs it
+// with rerun, up to opts.Retries times, while it exits with a non-zero code. Errors such as
+// timeouts or API failures are returned as they are, since the command may still be running.
+// It returns the last result and the number of attempts made.
+func retryOnExitCode(ctx context.Context, opts execOptions, instanceID string, result *ssm.CommandResult, err error, rerun func() (*ssm.CommandResult, error)) (*ssm.CommandResult, int, error) {
+	attempts := 1
+	for err == nil && exitedNonZero(result) && attempts <= opts.Retries {
+		logging.LogWarn("Command exited with code %d on %s, retrying in %v (attempt %d of %d)",
+			*result.ExitCode, instanceID, opts.RetryDelay, attempts+1, opts.Retries+1)
+
+		select {
+		case <-ctx.Done():
+			return result, attempts, nil
+		case <-time.After(opts.RetryDelay):
+		}
+
+		attempts++
+		result, err = rerun()
+	}
+	return result, attempts, err
+}
+
+// withAttempts records the attempt count on a report entry when --retries is set
+func (o execOptions) withAttempts(entry execReportInstance, attempts int) execReportInstance {
+	if o.Retries > 0 {
+		entry.Attempts = attempts
+	}
+	return entry
+}
+
+// attemptsSuffix describes a retried command for status lines, e.g. ", 3 attempts"
+func attemptsSuffix(attempts int) string {
+	if attempts <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(", %d attempts", attempts)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ztictl/internal/ssm"
+
+	"github.com/spf13/cobra"
+)
+
+// exitResult returns a completed command result with the given exit code
+func exitResult(code int32) *ssm.CommandResult {
+	return &ssm.CommandResult{ExitCode: &code}
+}
+
+func TestRetryOnExitCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		retries      int
+		first        *ssm.CommandResult
+		firstErr     error
+		reruns       []int32
+		wantAttempts int
+		wantExit     int32
+		wantErr      bool
+	}{
+		{name: "success is not retried", retries: 3, first: exitResult(0), wantAttempts: 1},
+		{name: "retries disabled", retries: 0, first: exitResult(1), wantAttempts: 1, wantExit: 1},
+		{name: "succeeds on a retry", retries: 3, first: exitResult(1), reruns: []int32{1, 0}, wantAttempts: 3},
+		{name: "gives up after the last retry", retries: 2, first: exitResult(100), reruns: []int32{100, 42}, wantAttempts: 3, wantExit: 42},
+		{name: "errors are not retried", retries: 3, first: nil, firstErr: errors.New("timed out"), wantAttempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			rerun := func() (*ssm.CommandResult, error) {
+				code := tt.reruns[calls]
+				calls++
+				return exitResult(code), nil
+			}
+
+			opts := execOptions{Retries: tt.retries}
+			result, attempts, err := retryOnExitCode(context.Background(), opts, "i-0abc", tt.first, tt.firstErr, rerun)
+			if attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.wantAttempts)
+			}
+			if calls != tt.wantAttempts-1 {
+				t.Errorf("rerun called %d times, want %d", calls, tt.wantAttempts-1)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && *result.ExitCode != tt.wantExit {
+				t.Errorf("exit code = %d, want %d", *result.ExitCode, tt.wantExit)
+			}
+		})
+	}
+}
+
+func TestRetryOnExitCodeStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	opts := execOptions{Retries: 3, RetryDelay: time.Hour}
+	_, attempts, err := retryOnExitCode(ctx, opts, "i-0abc", exitResult(1), nil, func() (*ssm.CommandResult, error) {
+		t.Fatal("Expected no retry after cancellation")
+		return nil, nil
+	})
+	if attempts != 1 || err != nil {
+		t.Errorf("Expected the first result after cancellation, got attempts=%d err=%v", attempts, err)
+	}
+}
+
+func TestResolveExecOptionsRetries(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("retries") == nil || cmd.Flags().Lookup("retry-delay") == nil {
+			t.Errorf("Expected --retries and --retry-delay flags on %s", cmd.Name())
+		}
+	}
+
+	tests := []struct {
+		retries string
+		delay   string
+		wantErr bool
+	}{
+		{"2", "1s", false},
+		{"-1", "1s", true},
+		{"11", "1s", true},
+		{"1", "-5s", true},
+	}
+	for _, tt := range tests {
+		cmd := &cobra.Command{Use: "test"}
+		addRetryFlags(cmd)
+		_ = cmd.Flags().Set("retries", tt.retries)
+		_ = cmd.Flags().Set("retry-delay", tt.delay)
+
+		opts, err := resolveExecOptions(cmd)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("--retries %s --retry-delay %s: err = %v, wantErr %v", tt.retries, tt.delay, err, tt.wantErr)
+		}
+		if err == nil && opts.Retries != 2 {
+			t.Errorf("Expected 2 retries, got %d", opts.Retries)
+		}
+	}
+}
+
+func TestWithAttempts(t *testing.T) {
+	entry := execReportInstance{InstanceID: "i-0abc"}
+	if got := (execOptions{}).withAttempts(entry, 1); got.Attempts != 0 {
+		t.Errorf("Expected no attempt count without --retries, got %d", got.Attempts)
+	}
+	if got := (execOptions{Retries: 2}).withAttempts(entry, 3); got.Attempts != 3 {
+		t.Errorf("Expected 3 attempts with --retries, got %d", got.Attempts)
+	}
+	if got := attemptsSuffix(3); got != ", 3 attempts" {
+		t.Errorf("attemptsSuffix(3) = %q", got)
+	}
+}
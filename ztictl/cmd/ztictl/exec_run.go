@@ -0,0 +1,69 @@
+package main
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+
+	"ztictl/pkg/colors"
+
+	"github.com/spf13/cobra"
+)
+
+// maxLabelLength keeps "ztictl run=<uuid> label=<label>" within the 100 character SendCommand comment limit
+const maxLabelLength = 40
+
+// labelPattern restricts labels to characters that are safe to search for in CloudTrail and the SSM console
+var labelPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 _.:/#-]*$`)
+
+// addLabelFlag registers --label for exec commands
+func addLabelFlag(cmd *cobra.Command) {
+	cmd.Flags().String("label", "", fmt.Sprintf("Label recorded in the SSM command comment of every invocation in this run (up to %d characters)", maxLabelLength))
+}
+
+// validateLabel checks a --label value
+func validateLabel(label string) error {
+	if label == "" {
+		return nil
+	}
+	if len(label) > maxLabelLength {
+		return fmt.Errorf("invalid --label: must be at most %d characters, got %d", maxLabelLength, len(label))
+	}
+	if !labelPattern.MatchString(label) {
+		return fmt.Errorf("invalid --label '%s': use letters, digits, spaces and _ . : / # -", label)
+	}
+	return nil
+}
+
+// newRunID returns a random (version 4) UUID identifying one exec run
+func newRunID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate run ID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// commandComment returns the SendCommand comment shared by every invocation in the run
+func (o execOptions) commandComment() string {
+	comment := "ztictl run=" + o.RunID
+	if o.Label != "" {
+		comment += " label=" + o.Label
+	}
+	return comment
+}
+
+// announceRun prints the run ID (and label) so command IDs can be traced back to this run
+func (o execOptions) announceRun() {
+	// --count-only sends nothing, so there is no run to identify
+	if quiet || o.CountOnly {
+		return
+	}
+	if o.Label != "" {
+		colors.PrintData("Run ID: %s (label: %s)\n", o.RunID, o.Label)
+	} else {
+		colors.PrintData("Run ID: %s\n", o.RunID)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func TestValidateLabel(t *testing.T) {
+	valid := []string{"", "CHG-1234", "deploy api 2026-10-15", "team/payments#42", strings.Repeat("a", maxLabelLength)}
+	for _, label := range valid {
+		if err := validateLabel(label); err != nil {
+			t.Errorf("validateLabel(%q) = %v, want nil", label, err)
+		}
+	}
+
+	invalid := []string{"-leading-dash", "bad\nnewline", "quote'd", strings.Repeat("a", maxLabelLength+1)}
+	for _, label := range invalid {
+		if err := validateLabel(label); err == nil {
+			t.Errorf("validateLabel(%q) = nil, want error", label)
+		}
+	}
+}
+
+func TestNewRunID(t *testing.T) {
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first, err := newRunID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := newRunID()
+	if !uuidPattern.MatchString(first) {
+		t.Errorf("Expected a version 4 UUID, got %q", first)
+	}
+	if first == second {
+		t.Error("Expected run IDs to differ")
+	}
+}
+
+func TestCommandCommentFitsSendCommandLimit(t *testing.T) {
+	runID, _ := newRunID()
+
+	opts := execOptions{RunID: runID}
+	if got := opts.commandComment(); got != "ztictl run="+runID {
+		t.Errorf("Unexpected comment without label: %q", got)
+	}
+
+	opts.Label = strings.Repeat("x", maxLabelLength)
+	comment := opts.commandComment()
+	if !strings.HasSuffix(comment, " label="+opts.Label) {
+		t.Errorf("Expected label in comment, got %q", comment)
+	}
+	if len(comment) > 100 {
+		t.Errorf("Comment is %d characters, over the SendCommand limit of 100", len(comment))
+	}
+}
+
+func TestResolveExecOptionsLabel(t *testing.T) {
+	for _, c := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if c.Flags().Lookup("label") == nil {
+			t.Errorf("Expected --label flag on %s", c.Name())
+		}
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	addLabelFlag(cmd)
+	_ = cmd.Flags().Set("label", "CHG-1234")
+
+	opts, err := resolveExecOptions(cmd)
+	if err != nil {
+		t.Fatalf("resolveExecOptions() error = %v", err)
+	}
+	if opts.Label != "CHG-1234" || opts.RunID == "" {
+		t.Errorf("Expected label and run ID, got %+v", opts)
+	}
+
+	_ = cmd.Flags().Set("label", "bad\tlabel")
+	if _, err := resolveExecOptions(cmd); err == nil {
+		t.Error("Expected error for invalid label")
+	}
+}
+
+func TestAnnounceRunAndReport(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	opts := execOptions{RunID: "11111111-2222-4333-8444-555555555555", Label: "CHG-1234"}
+	opts.announceRun()
+	if !strings.Contains(buf.String(), "Run ID: 11111111-2222-4333-8444-555555555555 (label: CHG-1234)") {
+		t.Errorf("Expected run ID announcement, got %q", buf.String())
+	}
+
+	report := newExecReport("uptime", []string{"us-east-1"}, time.Now())
+	report.RunID, report.Label = opts.RunID, opts.Label
+	var text bytes.Buffer
+	if err := writeExecReport(&text, report, outputFormatText, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "Run ID:   "+opts.RunID) || !strings.Contains(text.String(), "Label:    CHG-1234") {
+		t.Errorf("Expected run ID and label in report, got:\n%s", text.String())
+	}
+}
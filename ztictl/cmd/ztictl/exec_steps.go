@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ztictl/internal/platform"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// stepStartPattern matches the marker line printed before a step: "<prefix>2/3: <command>"
+	stepStartPattern = regexp.MustCompile(`^` + regexp.QuoteMeta(platform.StepMarkerPrefix) + `(\d+)/(\d+): `)
+	// stepFailurePattern matches the line printed on stderr when a step fails
+	stepFailurePattern = regexp.MustCompile(`^` + regexp.QuoteMeta(platform.StepMarkerPrefix) + `(\d+)/(\d+) failed with exit code (-?\d+)$`)
+)
+
+// execReportStep attributes part of an instance's output to one --command step
+type execReportStep struct {
+	Step        int    `json:"step"`
+	Command     string `json:"command"`
+	Output      string `json:"output"`
+	ErrorOutput string `json:"error_output,omitempty"`
+	ExitCode    *int32 `json:"exit_code,omitempty"` // Set for a step that failed
+}
+
+// addCommandStepsFlags registers --command and --continue-on-step-error for exec commands
+func addCommandStepsFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("command", nil, "Command to run instead of the command argument; repeat to run several in order on each instance, stopping at the first that fails")
+	cmd.Flags().Bool("continue-on-step-error", false, "With several --command steps, run the remaining steps after one fails; the instance still fails with the first failed step's exit code")
+}
+
+// commandSteps returns the --command values; nil when the flag is not set
+func commandSteps(cmd *cobra.Command) []string {
+	if cmd.Flags().Lookup("command") == nil {
+		return nil
+	}
+	steps, _ := cmd.Flags().GetStringArray("command")
+	return steps
+}
+
+// resolveCommandSteps reads and validates --command and --continue-on-step-error. Windows line endings
+// are normalized and trailing whitespace is trimmed, as for --command-file.
+func resolveCommandSteps(cmd *cobra.Command) ([]string, bool, error) {
+	values := commandSteps(cmd)
+	continueOnError := false
+	if cmd.Flags().Lookup("continue-on-step-error") != nil {
+		continueOnError, _ = cmd.Flags().GetBool("continue-on-step-error")
+	}
+	if len(values) == 0 {
+		if continueOnError {
+			return nil, false, fmt.Errorf("--continue-on-step-error requires --command")
+		}
+		return nil, false, nil
+	}
+
+	steps := make([]string, 0, len(values))
+	for i, value := range values {
+		step := strings.TrimRight(strings.ReplaceAll(value, "\r\n", "\n"), " \t\r\n")
+		if strings.TrimSpace(step) == "" {
+			return nil, false, fmt.Errorf("invalid --command: step %d is empty", i+1)
+		}
+		steps = append(steps, step)
+	}
+	return steps, continueOnError, nil
+}
+
+// stepsCommand joins steps into the command shown in output, logs and reports. It is never sent: the
+// platform builder runs the steps themselves on each instance.
+func stepsCommand(steps []string, continueOnError bool) string {
+	if continueOnError {
+		return strings.Join(steps, "; ")
+	}
+	return strings.Join(steps, " && ")
+}
+
+// withSteps attributes an instance's output to the --command steps on a report entry
+func (o execOptions) withSteps(entry execReportInstance) execReportInstance {
+	if len(o.Steps) > 0 {
+		entry.Steps = splitStepOutput(o.Steps, entry.Output, entry.ErrorOutput)
+	}
+	return entry
+}
+
+// splitStepOutput splits stdout and stderr at the step markers printed on the instance. Steps that never
+// started are left out, as is output before the first marker.
+func splitStepOutput(steps []string, stdout, stderr string) []execReportStep {
+	found := make(map[int]*execReportStep)
+	split := func(output string, errorOutput bool) {
+		var current *execReportStep
+		var lines []string
+		flush := func() {
+			if current == nil {
+				return
+			}
+			text := strings.Join(lines, "\n")
+			if errorOutput {
+				current.ErrorOutput = text
+			} else {
+				current.Output = text
+			}
+		}
+
+		for _, line := range outputLines(output) {
+			marker := strings.TrimSuffix(line, "\r")
+			if n, _ := matchStepMarker(stepStartPattern, marker, len(steps)); n > 0 {
+				flush()
+				if found[n] == nil {
+					found[n] = &execReportStep{Step: n, Command: steps[n-1]}
+				}
+				current, lines = found[n], nil
+				continue
+			}
+			if n, match := matchStepMarker(stepFailurePattern, marker, len(steps)); n > 0 && errorOutput && found[n] != nil {
+				if code, err := strconv.ParseInt(match[3], 10, 32); err == nil {
+					exitCode := int32(code)
+					found[n].ExitCode = &exitCode
+				}
+				continue
+			}
+			if current != nil {
+				lines = append(lines, line)
+			}
+		}
+		flush()
+	}
+	split(stdout, false)
+	split(stderr, true)
+
+	result := make([]execReportStep, 0, len(found))
+	for _, step := range found {
+		result = append(result, *step)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Step < result[j].Step })
+	return result
+}
+
+// matchStepMarker returns the step number and submatches of a marker line of a sequence of total steps;
+// the number is 0 when the line is not such a marker
+func matchStepMarker(pattern *regexp.Regexp, line string, total int) (int, []string) {
+	match := pattern.FindStringSubmatch(line)
+	if match == nil || match[2] != strconv.Itoa(total) {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil || n < 1 || n > total {
+		return 0, nil
+	}
+	return n, match
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestResolveCommandSteps(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("command") == nil || cmd.Flags().Lookup("continue-on-step-error") == nil {
+			t.Errorf("Expected --command and --continue-on-step-error flags on %s", cmd.Name())
+		}
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantSteps    []string
+		wantContinue bool
+		wantErr      string
+	}{
+		{name: "not set", args: nil},
+		{name: "one step", args: []string{"--command", "uptime"}, wantSteps: []string{"uptime"}},
+		{
+			name:      "steps in order",
+			args:      []string{"--command", "apt update", "--command", "apt install -y jq, curl\r\n"},
+			wantSteps: []string{"apt update", "apt install -y jq, curl"},
+		},
+		{
+			name:         "continue on error",
+			args:         []string{"--command", "false", "--command", "true", "--continue-on-step-error"},
+			wantSteps:    []string{"false", "true"},
+			wantContinue: true,
+		},
+		{name: "empty step", args: []string{"--command", "uptime", "--command", "  "}, wantErr: "step 2 is empty"},
+		{name: "continue without steps", args: []string{"--continue-on-step-error"}, wantErr: "requires --command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			addCommandStepsFlags(cmd)
+			if err := cmd.Flags().Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			steps, continueOnError, err := resolveCommandSteps(cmd)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(steps, tt.wantSteps) || continueOnError != tt.wantContinue {
+				t.Errorf("resolveCommandSteps() = %q, %v, %v; want %q, %v", steps, continueOnError, err, tt.wantSteps, tt.wantContinue)
+			}
+		})
+	}
+}
+
+func TestExecArgsWithCommandSteps(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	addCommandFileFlag(cmd)
+	addCommandStepsFlags(cmd)
+	_ = cmd.Flags().Set("command", "uptime")
+
+	if err := execArgs(1, 1)(cmd, []string{"cac1"}); err != nil {
+		t.Errorf("Expected --command to replace the command argument, got %v", err)
+	}
+	if err := execArgs(1, 1)(cmd, []string{"cac1", "uptime"}); err == nil || !strings.Contains(err.Error(), "--command replaces") {
+		t.Errorf("Expected an error for a command argument with --command, got %v", err)
+	}
+}
+
+func TestWithCommandFileSteps(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	addCommandFileFlag(cmd)
+	addCommandStepsFlags(cmd)
+	_ = cmd.Flags().Set("command", "apt update")
+	_ = cmd.Flags().Set("command", "apt install -y jq")
+
+	got, err := withCommandFile(cmd, []string{"cac1"})
+	if err != nil || !reflect.DeepEqual(got, []string{"cac1", "apt update && apt install -y jq"}) {
+		t.Errorf("withCommandFile() = %q, %v; want the steps joined as the command", got, err)
+	}
+
+	_ = cmd.Flags().Set("continue-on-step-error", "true")
+	if got, _ := withCommandFile(cmd, []string{"cac1"}); got[1] != "apt update; apt install -y jq" {
+		t.Errorf("Expected steps joined with ; when continuing after errors, got %q", got[1])
+	}
+
+	_ = cmd.Flags().Set("command-file", "steps.sh")
+	if _, err := withCommandFile(cmd, []string{"cac1"}); err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("Expected --command and --command-file to conflict, got %v", err)
+	}
+}
+
+func TestSplitStepOutput(t *testing.T) {
+	steps := []string{"apt update", "apt install -y jq", "jq --version"}
+	stdout := "==> [ztictl] step 1/3: apt update\nHit:1 http://archive.ubuntu.com\nReading package lists...\n" +
+		"==> [ztictl] step 2/3: apt install -y jq\r\n"
+	stderr := "sudo: unable to resolve host\n==> [ztictl] step 1/3: apt update\n" +
+		"==> [ztictl] step 2/3: apt install -y jq\nE: Unable to locate package jq\n" +
+		"==> [ztictl] step 2/3 failed with exit code 100\n"
+
+	got := splitStepOutput(steps, stdout, stderr)
+	if len(got) != 2 {
+		t.Fatalf("Expected the two steps that started, got %+v", got)
+	}
+	if got[0].Step != 1 || got[0].Command != "apt update" || got[0].Output != "Hit:1 http://archive.ubuntu.com\nReading package lists..." ||
+		got[0].ErrorOutput != "" || got[0].ExitCode != nil {
+		t.Errorf("Unexpected first step %+v", got[0])
+	}
+	if got[1].Step != 2 || got[1].Output != "" || got[1].ErrorOutput != "E: Unable to locate package jq" ||
+		got[1].ExitCode == nil || *got[1].ExitCode != 100 {
+		t.Errorf("Unexpected second step %+v", got[1])
+	}
+
+	// Markers of a sequence with a different number of steps are ordinary output
+	if got := splitStepOutput(steps[:2], stdout, ""); len(got) != 0 {
+		t.Errorf("Expected no steps for markers of another sequence, got %+v", got)
+	}
+}
+
+func TestWithSteps(t *testing.T) {
+	entry := execReportInstance{InstanceID: "i-1", Output: "==> [ztictl] step 1/1: uptime\n 10:00 up 3 days\n"}
+	if got := (execOptions{}).withSteps(entry); got.Steps != nil {
+		t.Errorf("Expected no steps without --command, got %+v", got.Steps)
+	}
+
+	opts := execOptions{Steps: []string{"uptime"}, ContinueOnStepError: true}
+	got := opts.withSteps(entry)
+	if len(got.Steps) != 1 || got.Steps[0].Output != " 10:00 up 3 days" {
+		t.Errorf("Expected the output attributed to the step, got %+v", got.Steps)
+	}
+	if ssmOpts := opts.ssmOptions(); !reflect.DeepEqual(ssmOpts.Steps, opts.Steps) || !ssmOpts.ContinueOnStepError {
+		t.Errorf("Expected the steps passed to the SSM manager, got %+v", ssmOpts)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+
+	"github.com/spf13/cobra"
+)
+
+// targetStatuses are the SSM agent ping statuses --target-status accepts
+var targetStatuses = []string{"Online", "ConnectionLost", "Inactive"}
+
+// addTargetStatusFlag registers --target-status
+func addTargetStatusFlag(cmd *cobra.Command) {
+	cmd.Flags().String("target-status", "", "Only target instances whose SSM agent has this status: "+strings.Join(targetStatuses, ", "))
+	_ = cmd.RegisterFlagCompletionFunc("target-status", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return targetStatuses, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// parseTargetStatus validates a --target-status value, accepting any letter case; empty disables the filter
+func parseTargetStatus(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	for _, status := range targetStatuses {
+		if strings.EqualFold(value, status) {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("invalid --target-status '%s' (expected %s)", value, strings.Join(targetStatuses, ", "))
+}
+
+// filterByTargetStatus keeps the instances whose SSM agent currently reports status and returns how many
+// were dropped. Statuses come fresh from DescribeInstanceInformation, so explicitly listed instances are
+// covered too; instances unknown to SSM never match.
+func filterByTargetStatus(ctx context.Context, ssmManager *ssm.Manager, region, status string, instances []interactive.Instance) ([]interactive.Instance, int, error) {
+	current, err := agentStatuses(ctx, ssmManager, region)
+	if err != nil {
+		return nil, 0, err
+	}
+	kept, dropped := matchTargetStatus(instances, current, status)
+	return kept, dropped, nil
+}
+
+// checkTargetStatus returns an error unless the SSM agent of a single exec target currently reports status
+func checkTargetStatus(ctx context.Context, ssmManager *ssm.Manager, region, instanceID, status string) error {
+	current, err := agentStatuses(ctx, ssmManager, region)
+	if err != nil {
+		return err
+	}
+	return targetStatusMismatch(current, instanceID, status)
+}
+
+// agentStatuses maps each instance registered with SSM in a region to its agent's ping status
+func agentStatuses(ctx context.Context, ssmManager *ssm.Manager, region string) (map[string]string, error) {
+	statuses, err := ssmManager.ListInstanceStatuses(ctx, region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSM agent statuses: %w", err)
+	}
+	current := make(map[string]string, len(statuses))
+	for _, instance := range statuses {
+		current[instance.InstanceID] = instance.SSMStatus
+	}
+	return current, nil
+}
+
+// targetStatusMismatch explains why an instance's agent status in current is not status, or returns nil
+func targetStatusMismatch(current map[string]string, instanceID, status string) error {
+	actual, ok := current[instanceID]
+	switch {
+	case !ok:
+		return fmt.Errorf("instance %s is not registered with SSM, so its agent is not %s (--target-status)", instanceID, status)
+	case actual != status:
+		return fmt.Errorf("instance %s SSM agent is %s, not %s (--target-status)", instanceID, actual, status)
+	}
+	return nil
+}
+
+// matchTargetStatus keeps the instances whose status in current equals status, recording it on each kept instance
+func matchTargetStatus(instances []interactive.Instance, current map[string]string, status string) ([]interactive.Instance, int) {
+	var kept []interactive.Instance
+	for _, instance := range instances {
+		if current[instance.InstanceID] != status {
+			continue
+		}
+		instance.SSMStatus = status
+		kept = append(kept, instance)
+	}
+	return kept, len(instances) - len(kept)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"ztictl/internal/interactive"
+	"ztictl/pkg/logging"
+)
+
+func TestParseTargetStatus(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "", want: ""},
+		{value: "Online", want: "Online"},
+		{value: "connectionlost", want: "ConnectionLost"},
+		{value: " INACTIVE ", want: "Inactive"},
+		{value: "offline", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseTargetStatus(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTargetStatus(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseTargetStatus(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchTargetStatus(t *testing.T) {
+	instances := []interactive.Instance{
+		{InstanceID: "i-online", State: "running", SSMStatus: "Online"},
+		{InstanceID: "i-lost", State: "running", SSMStatus: "Online"},
+		{InstanceID: "i-explicit", State: "running"},
+		{InstanceID: "i-unmanaged", State: "running"},
+	}
+	current := map[string]string{
+		"i-online":   "Online",
+		"i-lost":     "ConnectionLost",
+		"i-explicit": "ConnectionLost",
+	}
+
+	kept, dropped := matchTargetStatus(instances, current, "ConnectionLost")
+	if dropped != 2 {
+		t.Errorf("dropped = %d, want 2", dropped)
+	}
+	if len(kept) != 2 || kept[0].InstanceID != "i-lost" || kept[1].InstanceID != "i-explicit" {
+		t.Fatalf("kept = %+v, want i-lost and i-explicit", kept)
+	}
+	for _, instance := range kept {
+		if instance.SSMStatus != "ConnectionLost" {
+			t.Errorf("%s SSMStatus = %q, want the fresh status", instance.InstanceID, instance.SSMStatus)
+		}
+	}
+
+	if kept, dropped := matchTargetStatus(instances, current, "Inactive"); len(kept) != 0 || dropped != 4 {
+		t.Errorf("Inactive matched %d and dropped %d, want 0 and 4", len(kept), dropped)
+	}
+}
+
+func TestTargetStatusMismatch(t *testing.T) {
+	current := map[string]string{"i-lost": "ConnectionLost", "i-online": "Online"}
+
+	if err := targetStatusMismatch(current, "i-lost", "ConnectionLost"); err != nil {
+		t.Errorf("Expected a matching status to pass, got %v", err)
+	}
+	if err := targetStatusMismatch(current, "i-online", "ConnectionLost"); err == nil || !strings.Contains(err.Error(), "SSM agent is Online, not ConnectionLost") {
+		t.Errorf("Expected a status mismatch, got %v", err)
+	}
+	if err := targetStatusMismatch(current, "i-unknown", "Inactive"); err == nil || !strings.Contains(err.Error(), "not registered with SSM") {
+		t.Errorf("Expected an unregistered instance to fail, got %v", err)
+	}
+}
+
+func TestTargetStatusOnExplicitInstances(t *testing.T) {
+	if logger == nil {
+		logger = logging.NewLogger(false)
+	}
+	fakeInstanceLookup(t, []interactive.Instance{
+		{InstanceID: "i-lost", Name: "app-1", State: "running", SSMStatus: "ConnectionLost"},
+		{InstanceID: "i-online", Name: "app-2", State: "running", SSMStatus: "Online"},
+	})
+
+	opts := execOptions{TargetStatus: "ConnectionLost"}
+	instances, err := resolveExecTaggedInstances(context.Background(), nil, "ca-central-1", "systemctl restart amazon-ssm-agent", "", "i-lost,i-online", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := map[string]string{"i-lost": "ConnectionLost", "i-online": "Online"}
+	kept, dropped := matchTargetStatus(instances, current, opts.TargetStatus)
+	if len(kept) != 1 || kept[0].InstanceID != "i-lost" || dropped != 1 {
+		t.Fatalf("Expected only i-lost to match, got %+v (%d dropped)", kept, dropped)
+	}
+	if reason := execSkipReason(kept[0], opts); reason != "" {
+		t.Errorf("Expected i-lost to be executed, got skipped: %s", reason)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateResult is the data an --output-template is executed with, once per instance
+type templateResult struct {
+	InstanceID  string
+	Name        string
+	Region      string
+	Status      string // success, failed, error or timed_out
+	ExitCode    int    // -1 when no exit code was reported
+	Output      string
+	ErrorOutput string
+	Error       string
+	Duration    time.Duration
+	Attempts    int
+	Group       string // The --group-by-tag value
+}
+
+// outputTemplateFuncs are the helper functions available to --output-template
+var outputTemplateFuncs = template.FuncMap{
+	"trim":  strings.TrimSpace,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// parseOutputTemplate compiles an --output-template and checks it against a sample result,
+// so unknown fields are reported before any command is sent
+func parseOutputTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New("output-template").Funcs(outputTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --output-template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, templateResult{}); err != nil {
+		return nil, fmt.Errorf("invalid --output-template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// newTemplateResult converts a report entry to template data
+func newTemplateResult(instance execReportInstance) templateResult {
+	exitCode := -1
+	if instance.ExitCode != nil {
+		exitCode = int(*instance.ExitCode)
+	}
+	return templateResult{
+		InstanceID:  instance.InstanceID,
+		Name:        instance.Name,
+		Region:      instance.Region,
+		Status:      instance.Status,
+		ExitCode:    exitCode,
+		Output:      instance.Output,
+		ErrorOutput: instance.ErrorOutput,
+		Error:       instance.Error,
+		Duration:    time.Duration(instance.DurationMS) * time.Millisecond,
+		Attempts:    instance.Attempts,
+		Group:       instance.group,
+	}
+}
+
+// writeTemplateReport renders every instance in a report through the template,
+// ending each rendering with a newline when the template does not
+func writeTemplateReport(w io.Writer, report *execReport, tmpl *template.Template) error {
+	instances := report.Instances
+	for _, group := range report.Groups {
+		for _, instance := range group.Instances {
+			instance.group = group.Value
+			instances = append(instances, instance)
+		}
+	}
+
+	for _, instance := range instances {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, newTemplateResult(instance)); err != nil {
+			return fmt.Errorf("failed to render --output-template for %s: %w", instance.InstanceID, err)
+		}
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
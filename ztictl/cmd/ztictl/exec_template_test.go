@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/internal/ssm"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseOutputTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "empty", text: ""},
+		{name: "fields", text: "{{.InstanceID}}: {{.ExitCode}} {{.Status | upper}} {{trim .Output}}"},
+		{name: "syntax error", text: "{{.InstanceID", wantErr: "invalid --output-template"},
+		{name: "unknown field", text: "{{.Hostname}}", wantErr: "Hostname"},
+		{name: "unknown function", text: "{{shout .InstanceID}}", wantErr: "shout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := parseOutputTemplate(tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if (tmpl == nil) != (tt.text == "") {
+				t.Errorf("Expected a template only for non-empty text, got %v", tmpl)
+			}
+		})
+	}
+}
+
+func TestWriteTemplateReport(t *testing.T) {
+	originalQuiet := quiet
+	quiet = false
+	defer func() { quiet = originalQuiet }()
+
+	exitCode := int32(3)
+	report := newExecReport("uptime", []string{"us-east-1"}, time.Now())
+	report.add(reportInstance("i-1", "web", "us-east-1", &ssm.CommandResult{Output: " up 3 days\n", ExitCode: new(int32)}, nil, time.Second))
+	report.add(reportInstance("i-2", "", "us-east-1", &ssm.CommandResult{ExitCode: &exitCode}, nil, time.Second))
+	report.add(reportInstance("i-3", "", "us-east-1", nil, errors.New("instance unreachable"), time.Second))
+
+	tmpl, err := parseOutputTemplate("{{.InstanceID}} {{.Status}} {{.ExitCode}} {{trim .Output}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeTemplateReport(&buf, report, tmpl); err != nil {
+		t.Fatalf("writeTemplateReport() error = %v", err)
+	}
+	want := "i-1 success 0 up 3 days\ni-2 failed 3 \ni-3 error -1 \n"
+	if buf.String() != want {
+		t.Errorf("writeTemplateReport() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestWriteTemplateReportGroups(t *testing.T) {
+	report := newExecReport("uptime", []string{"us-east-1"}, time.Now())
+	report.GroupByTag = "Component"
+	report.add(groupedEntry("i-web1", "web", 0))
+	report.add(groupedEntry("i-api1", "api", 0))
+	report.finish()
+
+	tmpl, err := parseOutputTemplate("{{.Group}}/{{.InstanceID}}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := writeTemplateReport(&buf, report, tmpl); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "api/i-api1\nweb/i-web1\n" {
+		t.Errorf("Unexpected grouped template output %q", buf.String())
+	}
+}
+
+func TestResolveExecOptionsOutputTemplate(t *testing.T) {
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		addReportFlags(cmd)
+		return cmd
+	}
+
+	cmd := newCmd()
+	_ = cmd.Flags().Set("output-template", "{{.InstanceID}}")
+	opts, err := resolveExecOptions(cmd)
+	if err != nil {
+		t.Fatalf("resolveExecOptions() error = %v", err)
+	}
+	if opts.OutputTemplate == nil || !opts.structuredOutput() || !opts.wantsReport() {
+		t.Error("Expected --output-template to produce a report on stdout")
+	}
+
+	cmd = newCmd()
+	_ = cmd.Flags().Set("output-template", "{{.InstanceID}}")
+	_ = cmd.Flags().Set("output", outputFormatJSON)
+	if _, err := resolveExecOptions(cmd); err == nil {
+		t.Error("Expected --output-template with --output json to be rejected")
+	}
+
+	cmd = newCmd()
+	_ = cmd.Flags().Set("output-template", "{{.Nope}}")
+	if _, err := resolveExecOptions(cmd); err == nil {
+		t.Error("Expected an invalid template to be rejected")
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ztictl/internal/ssm"
+
+	"github.com/spf13/cobra"
+)
+
+// addTimeoutFlags registers --timeout and --batch-timeout for exec commands
+func addTimeoutFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration("timeout", 0, "Stop the command on each instance after this long, e.g. 90s or 10m (max 48h; default waits 5m)")
+	cmd.Flags().Duration("batch-timeout", 0, "Stop the whole run after this long, cancelling commands still running and reporting partial completion")
+}
+
+// validateTimeouts checks --timeout and --batch-timeout values
+func validateTimeouts(timeout, batchTimeout time.Duration) error {
+	if timeout != 0 && (timeout < time.Second || timeout > ssm.MaxCommandTimeout) {
+		return fmt.Errorf("invalid --timeout %v (expected 1s to %v)", timeout, ssm.MaxCommandTimeout)
+	}
+	if batchTimeout < 0 {
+		return fmt.Errorf("invalid --batch-timeout %v (must not be negative)", batchTimeout)
+	}
+	return nil
+}
+
+// batchContext bounds ctx by --batch-timeout for the fan-out. When it expires, the context's cause is
+// ssm.ErrBatchTimeout, which makes the SSM manager cancel the commands it was still waiting for.
+func (o execOptions) batchContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.BatchTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, o.BatchTimeout, ssm.ErrBatchTimeout)
+}
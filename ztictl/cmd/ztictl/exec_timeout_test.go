@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestValidateTimeouts(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		batchTimeout time.Duration
+		wantErr      string
+	}{
+		{name: "defaults", timeout: 0, batchTimeout: 0},
+		{name: "both set", timeout: 2 * time.Minute, batchTimeout: 10 * time.Minute},
+		{name: "maximum timeout", timeout: 48 * time.Hour},
+		{name: "timeout below one second", timeout: 500 * time.Millisecond, wantErr: "invalid --timeout"},
+		{name: "negative timeout", timeout: -time.Minute, wantErr: "invalid --timeout"},
+		{name: "timeout above 48h", timeout: 49 * time.Hour, wantErr: "invalid --timeout"},
+		{name: "negative batch timeout", batchTimeout: -time.Second, wantErr: "invalid --batch-timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTimeouts(tt.timeout, tt.batchTimeout)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestResolveExecOptionsTimeouts(t *testing.T) {
+	cmd := &cobra.Command{}
+	addTimeoutFlags(cmd)
+	if err := cmd.Flags().Parse([]string{"--timeout", "90s", "--batch-timeout", "15m"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	opts, err := resolveExecOptions(cmd)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if opts.Timeout != 90*time.Second || opts.BatchTimeout != 15*time.Minute {
+		t.Errorf("Expected 90s and 15m, got %v and %v", opts.Timeout, opts.BatchTimeout)
+	}
+	if got := opts.ssmOptions().Timeout; got != 90*time.Second {
+		t.Errorf("Expected --timeout to reach the SSM options, got %v", got)
+	}
+}
+
+func TestBatchContext(t *testing.T) {
+	t.Run("no batch timeout", func(t *testing.T) {
+		ctx, cancel := execOptions{}.batchContext(context.Background())
+		defer cancel()
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("Expected no deadline without --batch-timeout")
+		}
+	})
+
+	t.Run("expiry is reported as the batch timeout", func(t *testing.T) {
+		ctx, cancel := execOptions{BatchTimeout: 10 * time.Millisecond}.batchContext(context.Background())
+		defer cancel()
+		<-ctx.Done()
+
+		if !isCancelled(context.Cause(ctx)) {
+			t.Errorf("Expected the batch timeout to count as a cancellation, got %v", context.Cause(ctx))
+		}
+		if got := stopReason(ctx); got != "Batch timeout reached" {
+			t.Errorf("stopReason = %q, want %q", got, "Batch timeout reached")
+		}
+	})
+
+	t.Run("parent deadline keeps its reason", func(t *testing.T) {
+		parent, cancelParent := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancelParent()
+		ctx, cancel := execOptions{BatchTimeout: time.Hour}.batchContext(parent)
+		defer cancel()
+		<-ctx.Done()
+
+		if got := stopReason(ctx); got != "Deadline exceeded" {
+			t.Errorf("stopReason = %q, want %q", got, "Deadline exceeded")
+		}
+	})
+}
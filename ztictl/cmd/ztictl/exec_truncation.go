@@ -0,0 +1,24 @@
+package main
+
+import (
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+)
+
+// warnTruncatedOutput tells the user when SSM cut the inline output of a displayed result at its
+// limit, so a partial result is not mistaken for the whole one. Structured reports carry the
+// output_truncated fields instead; the warning then goes to stderr with the other messages.
+func warnTruncatedOutput(instanceID string, result *ssm.CommandResult, succeeded bool, opts execOptions) {
+	if result == nil || (quiet && succeeded) || !opts.showsOutput(succeeded) {
+		return
+	}
+	if result.OutputTruncated {
+		colors.PrintWarning("⚠ Output from %s was truncated by SSM at %d characters; the output shown is incomplete\n", instanceID, ssm.MaxInlineOutputChars)
+	}
+	if result.ErrorOutputTruncated {
+		colors.PrintWarning("⚠ Error output from %s was truncated by SSM at %d characters; the output shown is incomplete\n", instanceID, ssm.MaxInlineErrorOutputChars)
+	}
+	if result.OutputTruncated || result.ErrorOutputTruncated {
+		colors.PrintWarning("  Write large output to a file on the instance and fetch it with 'ztictl ssm transfer download'\n")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/internal/ssm"
+
+	"github.com/fatih/color"
+)
+
+func TestWarnTruncatedOutput(t *testing.T) {
+	var buf bytes.Buffer
+	originalOutput := color.Output
+	color.Output = &buf
+	defer func() { color.Output = originalOutput }()
+
+	warnTruncatedOutput("i-1", &ssm.CommandResult{Output: "partial"}, true, execOptions{})
+	if buf.Len() != 0 {
+		t.Errorf("Expected no warning for complete output, got %q", buf.String())
+	}
+
+	warnTruncatedOutput("i-1", &ssm.CommandResult{OutputTruncated: true, ErrorOutputTruncated: true}, true, execOptions{})
+	for _, want := range []string{"Output from i-1 was truncated by SSM at 24000 characters", "Error output from i-1 was truncated by SSM at 8000 characters", "ztictl ssm transfer download"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("Expected %q in warning, got:\n%s", want, buf.String())
+		}
+	}
+
+	// Output that is not displayed needs no warning
+	buf.Reset()
+	warnTruncatedOutput("i-1", &ssm.CommandResult{OutputTruncated: true}, true, execOptions{HideOutput: true})
+	if buf.Len() != 0 {
+		t.Errorf("Expected no warning with hidden output, got %q", buf.String())
+	}
+}
+
+func TestReportInstanceTruncation(t *testing.T) {
+	entry := reportInstance("i-1", "", "us-east-1", &ssm.CommandResult{OutputTruncated: true}, nil, time.Second)
+	if !entry.OutputTruncated || entry.ErrorOutputTruncated {
+		t.Errorf("Expected only stdout marked truncated, got %+v", entry)
+	}
+}
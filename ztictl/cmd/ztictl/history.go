@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"ztictl/internal/config"
+	"ztictl/internal/history"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DefaultHistoryLimit is the default number of entries shown by ztictl history
+	DefaultHistoryLimit = 20
+
+	// historyCommandDisplayLength is the maximum command length shown in the history table
+	historyCommandDisplayLength = 60
+)
+
+// History operation names
+const (
+	historyOpExec             = "exec"
+	historyOpExecTagged       = "exec-tagged"
+	historyOpExecMulti        = "exec-multi"
+	historyOpConnect          = "connect"
+	historyOpTransferUpload   = "transfer-upload"
+	historyOpTransferDownload = "transfer-download"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recorded exec, session and transfer operations",
+	Long: `Show recent operations recorded in the local history log.
+History recording is opt-in; enable it with 'history.enabled: true' in ~/.ztictl.yaml.
+Entries are stored as JSON lines in ~/.ztictl/history/history.jsonl (or history.path).
+
+Examples:
+  ztictl history                     # Show the last 20 operations
+  ztictl history --limit 100         # Show the last 100 operations
+  ztictl history --grep i-1234       # Operations targeting a specific instance
+  ztictl history --grep nginx --json # Matching operations as JSON`,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		pattern, _ := cmd.Flags().GetString("grep")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		if err := showHistory(os.Stdout, limit, pattern, jsonOutput); err != nil {
+			logging.LogError("Failed to show history: %v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// historyFilePath returns the configured history file path
+func historyFilePath() (string, error) {
+	if path := config.Get().History.Path; path != "" {
+		return path, nil
+	}
+	return history.DefaultPath()
+}
+
+// showHistory prints recent history entries, optionally filtered by pattern
+func showHistory(w io.Writer, limit int, pattern string, jsonOutput bool) error {
+	path, err := historyFilePath()
+	if err != nil {
+		return err
+	}
+
+	entries, err := history.Read(path)
+	if err != nil {
+		return err
+	}
+	entries = history.Last(history.Filter(entries, pattern), limit)
+
+	if jsonOutput {
+		if entries == nil {
+			entries = []history.Entry{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		if !config.Get().History.Enabled {
+			colors.PrintWarning("⚠ History recording is disabled. Set 'history.enabled: true' in ~/.ztictl.yaml to enable it.\n")
+		} else {
+			colors.PrintData("No history entries found\n")
+		}
+		return nil
+	}
+
+	formatter := NewTableFormatter(2)
+	timestamps := make([]string, len(entries))
+	operations := make([]string, len(entries))
+	regions := make([]string, len(entries))
+	targets := make([]string, len(entries))
+	commands := make([]string, len(entries))
+
+	for i, entry := range entries {
+		timestamps[i] = entry.Timestamp.Local().Format("2006-01-02 15:04:05")
+		operations[i] = entry.Operation
+		regions[i] = entry.Region
+		targets[i] = strings.Join(entry.Targets, ",")
+
+		command := entry.Command
+		if command == "" && entry.CommandHash != "" {
+			command = "sha256:" + entry.CommandHash
+			if len(command) > 19 {
+				command = command[:19]
+			}
+		}
+		if len(command) > historyCommandDisplayLength {
+			command = command[:historyCommandDisplayLength-3] + "..."
+		}
+		commands[i] = command
+	}
+
+	formatter.AddColumn("Time", timestamps, 19)
+	formatter.AddColumn("Operation", operations, 9)
+	formatter.AddColumn("Region", regions, 6)
+	formatter.AddColumn("Targets", targets, 7)
+	formatter.AddColumn("Command", commands, 7)
+
+	_, _ = fmt.Fprintf(w, "%s\n", colors.ColorHeader("%s", formatter.FormatHeader()))
+	for i := 0; i < formatter.GetRowCount(); i++ {
+		_, _ = fmt.Fprintf(w, "%s\n", formatter.FormatRow(i))
+	}
+
+	return nil
+}
+
+// recordHistory appends an operation to the history log when history is enabled.
+// Failures are logged and never interrupt the operation being recorded.
+func recordHistory(operation, region string, targets []string, command string) {
+	historyCfg := config.Get().History
+	if !historyCfg.Enabled {
+		return
+	}
+
+	recorder, err := history.NewRecorder(historyCfg.Path, historyCfg.HashCommands)
+	if err != nil {
+		logging.LogWarn("History recording skipped: %v", err)
+		return
+	}
+
+	if err := recorder.Record(history.Entry{
+		Operation: operation,
+		Region:    region,
+		Targets:   targets,
+		Command:   command,
+	}); err != nil {
+		logging.LogWarn("History recording failed: %v", err)
+	}
+}
+
+func init() {
+	historyCmd.Flags().IntP("limit", "n", DefaultHistoryLimit, "Maximum number of entries to show (0 for all)")
+	historyCmd.Flags().StringP("grep", "g", "", "Only show entries whose operation, region, targets or command contain this text")
+	historyCmd.Flags().Bool("json", false, "Output entries as JSON")
+
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"ztictl/internal/config"
+	"ztictl/internal/history"
+)
+
+func withHistoryConfig(t *testing.T, historyCfg config.HistoryConfig) {
+	t.Helper()
+	cfg := config.Get()
+	original := cfg.History
+	cfg.History = historyCfg
+	t.Cleanup(func() { cfg.History = original })
+}
+
+func TestRecordHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), history.FileName)
+
+	t.Run("disabled does not write", func(t *testing.T) {
+		withHistoryConfig(t, config.HistoryConfig{Enabled: false, Path: path})
+
+		recordHistory(historyOpExec, "ca-central-1", []string{"i-123"}, "uptime")
+
+		entries, err := history.Read(path)
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		if len(entries) != 0 {
+			t.Errorf("Expected no entries when history is disabled, got %d", len(entries))
+		}
+	})
+
+	t.Run("enabled appends entry", func(t *testing.T) {
+		withHistoryConfig(t, config.HistoryConfig{Enabled: true, Path: path})
+
+		recordHistory(historyOpExecTagged, "us-east-1", []string{"i-1", "i-2"}, "df -h")
+
+		entries, err := history.Read(path)
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		if len(entries) != 1 {
+			t.Fatalf("Expected 1 entry, got %d", len(entries))
+		}
+		if entries[0].Operation != historyOpExecTagged || entries[0].Command != "df -h" || len(entries[0].Targets) != 2 {
+			t.Errorf("Unexpected entry: %+v", entries[0])
+		}
+	})
+}
+
+func TestShowHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), history.FileName)
+	withHistoryConfig(t, config.HistoryConfig{Enabled: true, Path: path, HashCommands: true})
+
+	recordHistory(historyOpExec, "ca-central-1", []string{"i-web"}, "systemctl restart nginx")
+	recordHistory(historyOpConnect, "us-east-1", []string{"i-db"}, "")
+
+	t.Run("table output", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := showHistory(&buf, DefaultHistoryLimit, "", false); err != nil {
+			t.Fatalf("showHistory() error = %v", err)
+		}
+		output := buf.String()
+		for _, want := range []string{"exec", "connect", "i-web", "sha256:"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+			}
+		}
+		if strings.Contains(output, "nginx") {
+			t.Error("Expected hashed command text to be hidden")
+		}
+	})
+
+	t.Run("json output with grep and limit", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := showHistory(&buf, 1, "i-", true); err != nil {
+			t.Fatalf("showHistory() error = %v", err)
+		}
+
+		var entries []history.Entry
+		if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+			t.Fatalf("Invalid JSON output: %v", err)
+		}
+		if len(entries) != 1 || entries[0].Operation != historyOpConnect {
+			t.Errorf("Expected only the most recent entry, got %+v", entries)
+		}
+	})
+
+	t.Run("json output with no matches is an empty array", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := showHistory(&buf, 0, "no-such-target", true); err != nil {
+			t.Fatalf("showHistory() error = %v", err)
+		}
+		if strings.TrimSpace(buf.String()) != "[]" {
+			t.Errorf("Expected empty JSON array, got %q", buf.String())
+		}
+	})
+}
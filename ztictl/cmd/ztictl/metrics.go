@@ -0,0 +1,29 @@
+package main
+
+import (
+	"ztictl/internal/config"
+	"ztictl/internal/metrics"
+	"ztictl/pkg/logging"
+)
+
+// initMetrics installs the metrics sink selected by metrics.sink; metrics stay disabled otherwise
+func initMetrics() {
+	sink, err := newMetricsSink(config.Get().Metrics)
+	if err != nil {
+		logging.LogWarn("Metrics disabled: %v", err)
+		return
+	}
+	metrics.SetSink(sink)
+}
+
+// newMetricsSink creates the sink for a metrics configuration, or nil for none
+func newMetricsSink(cfg config.MetricsConfig) (metrics.Sink, error) {
+	switch cfg.Sink {
+	case metrics.SinkFile:
+		return metrics.NewFileSink(cfg.Path)
+	case metrics.SinkStatsd, metrics.SinkDogStatsd:
+		return metrics.NewStatsdSink(cfg.StatsdAddress, cfg.Prefix, cfg.Sink == metrics.SinkDogStatsd)
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	awspkg "ztictl/pkg/aws"
+	zti_errors "ztictl/pkg/errors"
+	"ztictl/pkg/logging"
+
+	"github.com/aws/smithy-go"
+	"github.com/spf13/cobra"
+)
+
+// Error envelope types besides the ztictl error types (auth, ssm, config, aws and validation)
+const (
+	errorTypeThrottling = "throttling" // SSM or another AWS API kept throttling requests
+	errorTypeCancelled  = "cancelled"  // Interrupted or stopped by --deadline
+	errorTypeCommand    = "command"    // Any other failure
+)
+
+// authErrorCodes are AWS API error codes caused by missing, expired or rejected credentials
+var authErrorCodes = map[string]struct{}{
+	"ExpiredToken":                {},
+	"ExpiredTokenException":       {},
+	"InvalidClientTokenId":        {},
+	"UnrecognizedClientException": {},
+	"UnauthorizedException":       {},
+	"AccessDenied":                {},
+	"AccessDeniedException":       {},
+}
+
+// errorEnvelope is written to stdout with --output json or jsonl when a command fails before it
+// has results to report, so scripts can parse failures the same way as successes
+type errorEnvelope struct {
+	Error errorEnvelopeBody `json:"error"`
+}
+
+// errorEnvelopeBody describes the failure
+type errorEnvelopeBody struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// newErrorEnvelope classifies err for an error envelope
+func newErrorEnvelope(err error) errorEnvelope {
+	return errorEnvelope{Error: errorEnvelopeBody{Type: errorEnvelopeType(err), Message: err.Error()}}
+}
+
+// errorEnvelopeType returns the type of a failure: throttling or auth for those AWS errors, otherwise
+// the ztictl error type when err carries one
+func errorEnvelopeType(err error) string {
+	if errType := awsErrorType(err); errType != "" {
+		return errType
+	}
+	var ztiErr *zti_errors.ZtiError
+	if errors.As(err, &ztiErr) {
+		return string(ztiErr.Type)
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return errorTypeCancelled
+	}
+	return errorTypeCommand
+}
+
+// awsErrorType returns the envelope type for AWS throttling and credential errors, or empty for other errors
+func awsErrorType(err error) string {
+	if awspkg.IsThrottlingError(err) {
+		return errorTypeThrottling
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if _, ok := authErrorCodes[apiErr.ErrorCode()]; ok {
+			return string(zti_errors.ErrTypeAuth)
+		}
+	}
+	return ""
+}
+
+// writeErrorEnvelope writes err as an error envelope when cmd's --output is json or jsonl and reports
+// whether it did. Failures already recorded in an exec report (exitCodeError) are not written again.
+func writeErrorEnvelope(w io.Writer, cmd *cobra.Command, err error) bool {
+	if cmd.Flags().Lookup("output") == nil {
+		return false
+	}
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return false
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	var pretty bool
+	switch output {
+	case outputFormatJSON:
+		style, _ := resolveJSONStyle(cmd, output)
+		pretty = execOptions{JSONStyle: style}.prettyJSON(w)
+	case outputFormatJSONL:
+	default:
+		return false
+	}
+
+	if writeErr := writeJSON(w, newErrorEnvelope(err), pretty); writeErr != nil {
+		logging.LogDebug("Failed to write error envelope: %v", writeErr)
+		return false
+	}
+	return true
+}
+
+// exitWithError logs a command failure, writes its error envelope for --output json or jsonl, and
+// exits with code. A non-empty prefix is logged before the error.
+func exitWithError(cmd *cobra.Command, code int, prefix string, err error) {
+	if prefix != "" {
+		logging.LogError("%s: %v", prefix, err)
+	} else {
+		logging.LogError("%v", err)
+	}
+	writeErrorEnvelope(os.Stdout, cmd, err)
+	os.Exit(code)
+}
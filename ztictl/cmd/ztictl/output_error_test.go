@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	zti_errors "ztictl/pkg/errors"
+
+	"github.com/aws/smithy-go"
+	"github.com/spf13/cobra"
+)
+
+func TestErrorEnvelopeType(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "ztictl auth error", err: zti_errors.NewAuthError("failed to retrieve credentials", errors.New("no token")), want: "auth"},
+		{name: "wrapped validation error", err: fmt.Errorf("config: %w", zti_errors.NewValidationError("bad region")), want: "validation"},
+		{name: "expired token", err: fmt.Errorf("failed to list instances: %w", &smithy.GenericAPIError{Code: "ExpiredTokenException"}), want: "auth"},
+		{name: "expired token inside ssm error", err: zti_errors.NewSSMError("failed to send command", &smithy.GenericAPIError{Code: "ExpiredToken"}), want: "auth"},
+		{name: "throttling", err: &smithy.GenericAPIError{Code: "ThrottlingException"}, want: errorTypeThrottling},
+		{name: "other aws error", err: zti_errors.NewAWSError("failed to get SSM client", &smithy.GenericAPIError{Code: "InvalidInstanceId"}), want: "aws"},
+		{name: "deadline", err: fmt.Errorf("not started: %w", context.DeadlineExceeded), want: errorTypeCancelled},
+		{name: "plain error", err: errors.New("no valid instances available for execution"), want: errorTypeCommand},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorEnvelopeType(tt.err); got != tt.want {
+				t.Errorf("errorEnvelopeType(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteErrorEnvelope(t *testing.T) {
+	authErr := zti_errors.NewAuthError("failed to retrieve credentials", nil)
+
+	tests := []struct {
+		name      string
+		flags     []string
+		err       error
+		wantWrite bool
+	}{
+		{name: "json", flags: []string{"--output", "json"}, err: authErr, wantWrite: true},
+		{name: "jsonl", flags: []string{"--output", "jsonl"}, err: authErr, wantWrite: true},
+		{name: "text", err: authErr},
+		{name: "yaml", flags: []string{"--output", "yaml"}, err: authErr},
+		{name: "failures already in the report", flags: []string{"--output", "json"}, err: &exitCodeError{code: 3, err: errors.New("command failed on 3 of 5 instance(s)")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+			addReportFlags(cmd)
+			cmd.SetArgs(tt.flags)
+			if err := cmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			var buf bytes.Buffer
+			if got := writeErrorEnvelope(&buf, cmd, tt.err); got != tt.wantWrite {
+				t.Fatalf("writeErrorEnvelope() = %v, want %v", got, tt.wantWrite)
+			}
+			if !tt.wantWrite {
+				if buf.Len() != 0 {
+					t.Errorf("Expected nothing written, got %q", buf.String())
+				}
+				return
+			}
+
+			if strings.Count(buf.String(), "\n") != 1 {
+				t.Errorf("Expected the envelope on one line when not writing to a terminal, got:\n%s", buf.String())
+			}
+			var envelope errorEnvelope
+			if err := json.Unmarshal(buf.Bytes(), &envelope); err != nil {
+				t.Fatalf("Expected valid JSON, got %v: %s", err, buf.String())
+			}
+			if envelope.Error.Type != "auth" || envelope.Error.Message != authErr.Error() {
+				t.Errorf("Unexpected envelope %+v", envelope)
+			}
+		})
+	}
+}
+
+func TestWriteErrorEnvelopeWithoutOutputFlag(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	var buf bytes.Buffer
+	if writeErrorEnvelope(&buf, cmd, errors.New("boom")) || buf.Len() != 0 {
+		t.Errorf("Expected no envelope for a command without --output, got %q", buf.String())
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// writeYAML renders v as YAML using its JSON encoding, so --output yaml has the same field
+// names (the json tags), field order and omitted empty fields as --output json
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode YAML output: %w", err)
+	}
+
+	// JSON is valid YAML; decoding into a node keeps the key order that a map would lose
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("failed to encode YAML output: %w", err)
+	}
+	clearYAMLStyle(&doc)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode YAML output: %w", err)
+	}
+	return encoder.Close()
+}
+
+// clearYAMLStyle drops the flow and quoting styles carried over from JSON so the encoder
+// writes block style, quoting only scalars that would otherwise change type
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/internal/ssm"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteYAMLMatchesJSONFieldNames(t *testing.T) {
+	three := int32(3)
+	report := newExecReport("echo 123", []string{"us-east-1"}, time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC))
+	report.add(reportInstance("i-web1", "web-1", "us-east-1", &ssm.CommandResult{Output: "line one\nline two\n"}, nil, time.Second))
+	report.add(reportInstance("i-web2", "", "us-east-1", &ssm.CommandResult{Output: "123", ExitCode: &three}, nil, time.Second))
+
+	var buf bytes.Buffer
+	if err := writeExecReport(&buf, report, outputFormatYAML, false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"command: echo 123\n", "instance_id: i-web1\n", "exit_code: 3\n", "output: \"123\"\n", "duration_ms: 1000\n", "summary:\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in YAML report, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "{") || strings.Contains(out, "instanceid") {
+		t.Errorf("Expected block style YAML with JSON field names, got:\n%s", out)
+	}
+	if strings.Index(out, "run_id") > -1 || strings.Index(out, "command:") > strings.Index(out, "instances:") {
+		t.Errorf("Expected JSON field order and omitempty to carry over, got:\n%s", out)
+	}
+
+	var decoded struct {
+		Instances []struct {
+			InstanceID string `yaml:"instance_id"`
+			Output     string `yaml:"output"`
+			ExitCode   *int   `yaml:"exit_code"`
+		} `yaml:"instances"`
+	}
+	if err := yaml.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Report is not valid YAML: %v", err)
+	}
+	if len(decoded.Instances) != 2 || decoded.Instances[0].Output != "line one\nline two\n" || decoded.Instances[1].Output != "123" || *decoded.Instances[1].ExitCode != 3 {
+		t.Errorf("Unexpected decoded report: %+v", decoded)
+	}
+}
+
+func TestWriteYAMLEmptyList(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeYAML(&buf, []staleInstance{}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("Expected an empty YAML list, got %q", buf.String())
+	}
+}
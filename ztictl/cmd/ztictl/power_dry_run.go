@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+	awsservice "ztictl/pkg/aws"
+	"ztictl/pkg/colors"
+
+	"github.com/spf13/cobra"
+)
+
+// addPowerDryRunFlag registers --dry-run for power commands
+func addPowerDryRunFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("dry-run", false, "Show the state transition each instance would go through without changing anything")
+}
+
+// powerTargetStates is the state each power operation leaves an instance in
+var powerTargetStates = map[string]string{"start": "running", "stop": "stopped", "reboot": "running"}
+
+// powerPlan describes what a power operation would do to one instance
+type powerPlan struct {
+	InstanceID string
+	Name       string
+	State      string
+	Target     string
+	SkipReason string // Empty when the operation would proceed
+}
+
+// planPowerTransition checks an instance against the allowed states of a power operation.
+// A nil instance means it was not found in the region.
+func planPowerTransition(operation, instanceID, region string, instance *interactive.Instance) (powerPlan, error) {
+	requirements, err := buildRequirementsForOperation(operation)
+	if err != nil {
+		return powerPlan{}, err
+	}
+
+	plan := powerPlan{InstanceID: instanceID, Target: powerTargetStates[operation]}
+	if instance == nil {
+		plan.SkipReason = fmt.Sprintf("not found in region %s", region)
+		return plan, nil
+	}
+
+	plan.Name = instance.Name
+	plan.State = instance.State
+	switch {
+	case slices.Contains(requirements.AllowedStates, instance.State):
+	case operation != "reboot" && instance.State == plan.Target:
+		plan.SkipReason = "already " + instance.State
+	default:
+		plan.SkipReason = fmt.Sprintf("state is %s, requires %s", instance.State, strings.Join(requirements.AllowedStates, " or "))
+	}
+	return plan, nil
+}
+
+// previewPowerOperation prints the transition a power operation would cause on each instance
+// without calling the mutating EC2 APIs
+func previewPowerOperation(ctx context.Context, ssmManager *ssm.Manager, instanceIDs []string, region, operation string) error {
+	instances, err := ssmManager.GetInstanceService().ListInstances(awsservice.WithoutInstanceCache(ctx), region, &awsservice.ListFilters{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch instance details: %w", err)
+	}
+	byID := make(map[string]*interactive.Instance, len(instances))
+	for i := range instances {
+		byID[instances[i].InstanceID] = &instances[i]
+	}
+
+	colors.PrintHeader("=== Dry run: %s %d instance(s) in %s ===\n", operation, len(instanceIDs), region)
+	proceeding := 0
+	for _, instanceID := range instanceIDs {
+		plan, err := planPowerTransition(operation, instanceID, region, byID[instanceID])
+		if err != nil {
+			return err
+		}
+
+		label := plan.InstanceID
+		if plan.Name != "" {
+			label = fmt.Sprintf("%s (%s)", plan.InstanceID, plan.Name)
+		}
+		if plan.SkipReason != "" {
+			colors.PrintWarning("- %s: skipped, %s\n", label, plan.SkipReason)
+			continue
+		}
+		proceeding++
+		colors.PrintSuccess("✓ %s: %s → %s\n", label, plan.State, plan.Target)
+	}
+
+	colors.PrintData("\n%d would %s, %d would be skipped. No changes were made.\n", proceeding, operation, len(instanceIDs)-proceeding)
+	return nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"ztictl/internal/interactive"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPlanPowerTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		operation  string
+		instance   *interactive.Instance
+		wantState  string
+		wantTarget string
+		wantSkip   string
+	}{
+		{name: "start stopped", operation: "start", instance: &interactive.Instance{State: "stopped"}, wantState: "stopped", wantTarget: "running"},
+		{name: "start running", operation: "start", instance: &interactive.Instance{State: "running"}, wantState: "running", wantTarget: "running", wantSkip: "already running"},
+		{name: "stop running", operation: "stop", instance: &interactive.Instance{State: "running"}, wantState: "running", wantTarget: "stopped"},
+		{name: "stop stopped", operation: "stop", instance: &interactive.Instance{State: "stopped"}, wantState: "stopped", wantTarget: "stopped", wantSkip: "already stopped"},
+		{name: "stop pending", operation: "stop", instance: &interactive.Instance{State: "pending"}, wantState: "pending", wantTarget: "stopped", wantSkip: "state is pending, requires running"},
+		{name: "reboot running", operation: "reboot", instance: &interactive.Instance{State: "running"}, wantState: "running", wantTarget: "running"},
+		{name: "reboot stopped", operation: "reboot", instance: &interactive.Instance{State: "stopped"}, wantState: "stopped", wantTarget: "running", wantSkip: "state is stopped, requires running"},
+		{name: "missing instance", operation: "start", wantTarget: "running", wantSkip: "not found in region ca-central-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planPowerTransition(tt.operation, "i-1234567890abcdef0", "ca-central-1", tt.instance)
+			if err != nil {
+				t.Fatalf("planPowerTransition() error = %v", err)
+			}
+			if plan.State != tt.wantState || plan.Target != tt.wantTarget || plan.SkipReason != tt.wantSkip {
+				t.Errorf("planPowerTransition() = %+v, want state %q target %q skip %q", plan, tt.wantState, tt.wantTarget, tt.wantSkip)
+			}
+		})
+	}
+
+	if _, err := planPowerTransition("hibernate", "i-1234567890abcdef0", "ca-central-1", nil); err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+}
+
+func TestPowerCommandsHaveDryRun(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmStartCmd, ssmStopCmd, ssmRebootCmd, ssmStartTaggedCmd, ssmStopTaggedCmd, ssmRebootTaggedCmd} {
+		if cmd.Flags().Lookup("dry-run") == nil {
+			t.Errorf("Expected --dry-run on %s", cmd.Name())
+		}
+	}
+}
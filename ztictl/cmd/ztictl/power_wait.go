@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"ztictl/internal/config"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// powerWait controls waiting for started instances to come online in SSM
+type powerWait struct {
+	Enabled      bool
+	PollInterval time.Duration // Time between SSM status checks
+	MaxWait      time.Duration // Time to wait before giving up
+}
+
+// pingStatusFunc returns the SSM ping status of the managed instances in a region, keyed by instance ID
+type pingStatusFunc func(ctx context.Context) (map[string]string, error)
+
+// addPowerWaitFlags registers --wait, --poll-interval and --max-wait for start commands
+func addPowerWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("wait", false, "Wait until the started instances are Online in SSM")
+	cmd.Flags().Duration("poll-interval", 0, fmt.Sprintf("Time between status checks with --wait (default: system.wait_poll_interval, %ds)", config.DefaultWaitPollInterval))
+	cmd.Flags().Duration("max-wait", 0, fmt.Sprintf("Longest time to wait with --wait (default: system.wait_timeout, %ds)", config.DefaultWaitTimeout))
+}
+
+// resolvePowerWait reads the wait flags, filling unset durations from system.wait_poll_interval
+// and system.wait_timeout
+func resolvePowerWait(cmd *cobra.Command) (powerWait, error) {
+	enabled, _ := cmd.Flags().GetBool("wait")
+	pollInterval, _ := cmd.Flags().GetDuration("poll-interval")
+	maxWait, _ := cmd.Flags().GetDuration("max-wait")
+
+	if !enabled {
+		if cmd.Flags().Changed("poll-interval") || cmd.Flags().Changed("max-wait") {
+			return powerWait{}, fmt.Errorf("--poll-interval and --max-wait require --wait")
+		}
+		return powerWait{}, nil
+	}
+	if pollInterval < 0 {
+		return powerWait{}, fmt.Errorf("--poll-interval must be greater than 0, got %v", pollInterval)
+	}
+	if maxWait < 0 {
+		return powerWait{}, fmt.Errorf("--max-wait must be greater than 0, got %v", maxWait)
+	}
+
+	system := config.Get().System
+	if pollInterval == 0 {
+		pollInterval = configSeconds(system.WaitPollInterval, config.DefaultWaitPollInterval)
+	}
+	if maxWait == 0 {
+		maxWait = configSeconds(system.WaitTimeout, config.DefaultWaitTimeout)
+	}
+	return powerWait{Enabled: true, PollInterval: pollInterval, MaxWait: maxWait}, nil
+}
+
+// configSeconds converts a seconds setting to a duration, using fallback when it is unset
+func configSeconds(seconds, fallback int) time.Duration {
+	if seconds <= 0 {
+		seconds = fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// ssmPingStatuses reads ping statuses from SSM for a region
+func ssmPingStatuses(ssmManager *ssm.Manager, region string) pingStatusFunc {
+	return func(ctx context.Context) (map[string]string, error) {
+		instances, err := ssmManager.ListInstanceStatuses(ctx, region)
+		if err != nil {
+			return nil, err
+		}
+		statuses := make(map[string]string, len(instances))
+		for _, instance := range instances {
+			statuses[instance.InstanceID] = instance.SSMStatus
+		}
+		return statuses, nil
+	}
+}
+
+// waitForSSMOnline polls until every instance reports Online in SSM or wait.MaxWait passes.
+// Failed status checks are retried until then, since SSM is often briefly unreachable after a start.
+func waitForSSMOnline(ctx context.Context, instanceIDs []string, wait powerWait, statuses pingStatusFunc) error {
+	pending := make(map[string]bool, len(instanceIDs))
+	for _, instanceID := range instanceIDs {
+		pending[instanceID] = true
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	colors.PrintData("Waiting up to %v for %d instance(s) to come online in SSM (checking every %v)...\n", wait.MaxWait, len(pending), wait.PollInterval)
+	deadline := time.Now().Add(wait.MaxWait)
+	for {
+		current, err := statuses(ctx)
+		if err != nil {
+			logging.LogWarn("Failed to check SSM status: %v", err)
+		}
+		for _, instanceID := range instanceIDs {
+			if pending[instanceID] && current[instanceID] == "Online" {
+				delete(pending, instanceID)
+				colors.PrintSuccess("✓ Instance %s is online in SSM\n", instanceID)
+			}
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			waiting := make([]string, 0, len(pending))
+			for instanceID := range pending {
+				waiting = append(waiting, instanceID)
+			}
+			sort.Strings(waiting)
+			return fmt.Errorf("%s not online in SSM after %v (raise --max-wait or system.wait_timeout for slow-booting instances)", strings.Join(waiting, ", "), wait.MaxWait)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(min(wait.PollInterval, remaining)):
+		}
+	}
+}
+
+// startedInstanceIDs returns the instances a start operation succeeded on
+func startedInstanceIDs(results []PowerOperationResult) []string {
+	var instanceIDs []string
+	for _, result := range results {
+		if result.Error == nil {
+			instanceIDs = append(instanceIDs, result.InstanceID)
+		}
+	}
+	return instanceIDs
+}
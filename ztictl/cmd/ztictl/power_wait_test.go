@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+func newPowerWaitCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	addPowerWaitFlags(cmd)
+	return cmd
+}
+
+func TestResolvePowerWait(t *testing.T) {
+	system := &config.Get().System
+	originalInterval, originalTimeout := system.WaitPollInterval, system.WaitTimeout
+	defer func() { system.WaitPollInterval, system.WaitTimeout = originalInterval, originalTimeout }()
+
+	system.WaitPollInterval, system.WaitTimeout = 0, 0
+	cmd := newPowerWaitCmd()
+	if wait, err := resolvePowerWait(cmd); err != nil || wait.Enabled {
+		t.Errorf("Expected waiting disabled by default, got %+v, %v", wait, err)
+	}
+
+	_ = cmd.Flags().Set("wait", "true")
+	wait, err := resolvePowerWait(cmd)
+	if err != nil {
+		t.Fatalf("resolvePowerWait() error: %v", err)
+	}
+	if wait.PollInterval != 5*time.Second || wait.MaxWait != 5*time.Minute {
+		t.Errorf("Expected the built-in 5s/5m defaults, got %+v", wait)
+	}
+
+	system.WaitPollInterval, system.WaitTimeout = 20, 900
+	if wait, _ := resolvePowerWait(cmd); wait.PollInterval != 20*time.Second || wait.MaxWait != 15*time.Minute {
+		t.Errorf("Expected the configured 20s/15m, got %+v", wait)
+	}
+
+	_ = cmd.Flags().Set("poll-interval", "2s")
+	_ = cmd.Flags().Set("max-wait", "30m")
+	if wait, _ := resolvePowerWait(cmd); wait.PollInterval != 2*time.Second || wait.MaxWait != 30*time.Minute {
+		t.Errorf("Expected flags to override the config, got %+v", wait)
+	}
+
+	_ = cmd.Flags().Set("max-wait", "-1s")
+	if _, err := resolvePowerWait(cmd); err == nil {
+		t.Error("Expected a negative --max-wait to be rejected")
+	}
+
+	cmd = newPowerWaitCmd()
+	_ = cmd.Flags().Set("max-wait", "10m")
+	if _, err := resolvePowerWait(cmd); err == nil || !strings.Contains(err.Error(), "require --wait") {
+		t.Errorf("Expected --max-wait without --wait to be rejected, got %v", err)
+	}
+}
+
+func TestWaitForSSMOnline(t *testing.T) {
+	wait := powerWait{Enabled: true, PollInterval: time.Millisecond, MaxWait: time.Second}
+
+	calls := 0
+	statuses := func(ctx context.Context) (map[string]string, error) {
+		calls++
+		switch calls {
+		case 1:
+			return map[string]string{"i-a": "Online"}, nil
+		case 2:
+			return nil, errors.New("throttled")
+		default:
+			return map[string]string{"i-a": "Online", "i-b": "Online"}, nil
+		}
+	}
+	if err := waitForSSMOnline(context.Background(), []string{"i-a", "i-b"}, wait, statuses); err != nil {
+		t.Fatalf("waitForSSMOnline() error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected polling to retry past the failed check, got %d calls", calls)
+	}
+
+	wait.MaxWait = 20 * time.Millisecond
+	offline := func(ctx context.Context) (map[string]string, error) {
+		return map[string]string{"i-a": "Online", "i-b": "ConnectionLost"}, nil
+	}
+	err := waitForSSMOnline(context.Background(), []string{"i-a", "i-b"}, wait, offline)
+	if err == nil || !strings.Contains(err.Error(), "i-b not online in SSM") {
+		t.Errorf("Expected a timeout naming i-b, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	wait.MaxWait = time.Minute
+	if err := waitForSSMOnline(ctx, []string{"i-b"}, wait, offline); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation to stop the wait, got %v", err)
+	}
+}
+
+func TestStartedInstanceIDs(t *testing.T) {
+	results := []PowerOperationResult{
+		{InstanceID: "i-a"},
+		{InstanceID: "i-b", Error: errors.New("not stopped")},
+		{InstanceID: "i-c"},
+	}
+	got := startedInstanceIDs(results)
+	if strings.Join(got, ",") != "i-a,i-c" {
+		t.Errorf("startedInstanceIDs() = %v", got)
+	}
+}
+
+func TestStartCommandsHaveWait(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmStartCmd, ssmStartTaggedCmd} {
+		for _, name := range []string{"wait", "poll-interval", "max-wait"} {
+			if cmd.Flags().Lookup(name) == nil {
+				t.Errorf("Expected --%s on %s", name, cmd.Name())
+			}
+		}
+	}
+}
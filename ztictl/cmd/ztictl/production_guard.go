@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"ztictl/internal/config"
+	awspkg "ztictl/pkg/aws"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+const (
+	// productionConfirmWord is what the user types to confirm an operation on production targets
+	productionConfirmWord = "production"
+	// maxListedProductionInstances caps how many production-tagged instances the warning lists
+	maxListedProductionInstances = 10
+	// describeInstancesBatchSize keeps DescribeInstances requests well within the instance ID limit
+	describeInstancesBatchSize = 200
+)
+
+// productionGuard requires explicit confirmation before exec, power and transfer operations touch
+// a production account or production-tagged instance (the production section of ~/.ztictl.yaml).
+// It is deliberately separate from --yes: only --confirm-production or typing the confirmation
+// word gets past it. One confirmation covers the rest of the run, including other regions.
+type productionGuard struct {
+	mu             sync.Mutex
+	confirmed      bool // --confirm-production was passed or the user confirmed at the prompt
+	nonInteractive bool // No prompt is possible, so production targets need --confirm-production
+	input          io.Reader
+
+	accountID    func(ctx context.Context, region string) (string, error)
+	instanceTags func(ctx context.Context, region string, instanceIDs []string) (map[string]map[string]string, error)
+}
+
+// addConfirmProductionFlag registers --confirm-production and its --i-know-this-is-prod alias
+func addConfirmProductionFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("confirm-production", false, "Proceed on production accounts or production-tagged instances without the typed confirmation (required in non-interactive sessions)")
+	cmd.Flags().Bool("i-know-this-is-prod", false, "Alias for --confirm-production")
+}
+
+// newProductionGuard creates the guard for a command from its flags and execution context.
+// Sessions without a terminal on stdin cannot answer the prompt and are treated as non-interactive.
+func newProductionGuard(cmd *cobra.Command) *productionGuard {
+	confirmProduction, _ := cmd.Flags().GetBool("confirm-production")
+	knowProd, _ := cmd.Flags().GetBool("i-know-this-is-prod")
+
+	return &productionGuard{
+		confirmed:      confirmProduction || knowProd,
+		nonInteractive: GetExecutionContext(cmd).NonInteractive || !term.IsTerminal(int(os.Stdin.Fd())),
+		input:          os.Stdin,
+		accountID:      callerAccountID,
+		instanceTags:   describeInstanceTags,
+	}
+}
+
+// check returns nil when the targets in region are not production or the operation is confirmed.
+// A nil guard never prompts, so production targets are refused.
+func (g *productionGuard) check(ctx context.Context, region string, instanceIDs []string) error {
+	settings := config.Get().Production
+	if !settings.Enabled {
+		return nil
+	}
+	if g == nil {
+		g = &productionGuard{nonInteractive: true, accountID: callerAccountID, instanceTags: describeInstanceTags}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.confirmed {
+		return nil
+	}
+
+	reasons, err := g.productionReasons(ctx, settings, region, instanceIDs)
+	if err != nil {
+		return fmt.Errorf("cannot verify whether targets are production (use --confirm-production to proceed anyway): %w", err)
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+
+	colors.PrintWarning("⚠ This operation targets production in region %s:\n", region)
+	for _, reason := range reasons {
+		colors.PrintWarning("  - %s\n", reason)
+	}
+
+	if g.nonInteractive {
+		return fmt.Errorf("production targets require --confirm-production (or --i-know-this-is-prod) in non-interactive sessions")
+	}
+
+	// The prompt goes through colors so that 'transfer download ... -' keeps stdout clean
+	colors.PrintData("Type '%s' to continue: ", productionConfirmWord)
+	response, _ := bufio.NewReader(g.input).ReadString('\n')
+	if strings.TrimSpace(response) != productionConfirmWord {
+		return fmt.Errorf("operation on production targets cancelled")
+	}
+
+	logging.LogInfo("Production targets confirmed by user | region=%s", region)
+	g.confirmed = true
+	return nil
+}
+
+// productionReasons describes why the targets count as production; it is empty when they do not
+func (g *productionGuard) productionReasons(ctx context.Context, settings config.ProductionConfig, region string, instanceIDs []string) ([]string, error) {
+	var reasons []string
+
+	if len(settings.AccountIDs) > 0 {
+		account, err := g.accountID(ctx, region)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(settings.AccountIDs, account) {
+			reasons = append(reasons, fmt.Sprintf("account %s is listed in production.account_ids", account))
+		}
+	}
+
+	if len(settings.Tags) > 0 && len(instanceIDs) > 0 {
+		tags, err := g.instanceTags(ctx, region, instanceIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		var tagged []string
+		for _, instanceID := range instanceIDs {
+			if tag, ok := matchProductionTag(tags[instanceID], settings.Tags); ok {
+				tagged = append(tagged, fmt.Sprintf("instance %s is tagged %s=%s", instanceID, tag.Key, tag.Value))
+			}
+		}
+		if len(tagged) > maxListedProductionInstances {
+			more := len(tagged) - maxListedProductionInstances
+			tagged = append(tagged[:maxListedProductionInstances], fmt.Sprintf("... and %d more production-tagged instance(s)", more))
+		}
+		reasons = append(reasons, tagged...)
+	}
+
+	return reasons, nil
+}
+
+// matchProductionTag returns the first instance tag matching a production tag.
+// Keys match exactly, as AWS tag keys are case-sensitive; values match case-insensitively.
+func matchProductionTag(instanceTags map[string]string, productionTags []config.ResourceTag) (config.ResourceTag, bool) {
+	for _, tag := range productionTags {
+		if value, ok := instanceTags[tag.Key]; ok && strings.EqualFold(value, tag.Value) {
+			return config.ResourceTag{Key: tag.Key, Value: value}, true
+		}
+	}
+	return config.ResourceTag{}, false
+}
+
+// callerAccountID returns the AWS account ID of the current credentials
+func callerAccountID(ctx context.Context, region string) (string, error) {
+	client, err := awspkg.NewClient(ctx, awspkg.ClientOptions{Region: region})
+	if err != nil {
+		return "", err
+	}
+	identity, err := client.GetCallerIdentity(ctx)
+	if err != nil {
+		return "", err
+	}
+	return awssdk.ToString(identity.Account), nil
+}
+
+// describeInstanceTags returns the tags of each instance, keyed by instance ID
+func describeInstanceTags(ctx context.Context, region string, instanceIDs []string) (map[string]map[string]string, error) {
+	client, err := awspkg.NewClient(ctx, awspkg.ClientOptions{Region: region})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]map[string]string, len(instanceIDs))
+	for batch := range slices.Chunk(instanceIDs, describeInstancesBatchSize) {
+		paginator := ec2.NewDescribeInstancesPaginator(client.EC2, &ec2.DescribeInstancesInput{InstanceIds: batch})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe instance tags: %w", err)
+			}
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					instanceTags := make(map[string]string, len(instance.Tags))
+					for _, tag := range instance.Tags {
+						instanceTags[awssdk.ToString(tag.Key)] = awssdk.ToString(tag.Value)
+					}
+					tags[awssdk.ToString(instance.InstanceId)] = instanceTags
+				}
+			}
+		}
+	}
+	return tags, nil
+}
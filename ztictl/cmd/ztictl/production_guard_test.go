@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"ztictl/internal/config"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// withProductionConfig enables the production guard with the given settings for one test
+func withProductionConfig(t *testing.T, production config.ProductionConfig) {
+	t.Helper()
+	cfg := config.Get()
+	original := cfg.Production
+	t.Cleanup(func() { cfg.Production = original })
+	cfg.Production = production
+}
+
+// fakeProductionGuard returns a guard backed by a fixed account ID and instance tags
+func fakeProductionGuard(account string, tags map[string]map[string]string) *productionGuard {
+	return &productionGuard{
+		accountID: func(ctx context.Context, region string) (string, error) { return account, nil },
+		instanceTags: func(ctx context.Context, region string, instanceIDs []string) (map[string]map[string]string, error) {
+			return tags, nil
+		},
+	}
+}
+
+func TestProductionGuardDisabled(t *testing.T) {
+	withProductionConfig(t, config.ProductionConfig{Enabled: false, AccountIDs: []string{"111111111111"}})
+
+	guard := fakeProductionGuard("111111111111", nil)
+	guard.nonInteractive = true
+	if err := guard.check(context.Background(), "ca-central-1", []string{"i-0abc"}); err != nil {
+		t.Errorf("Disabled guard should not block, got %v", err)
+	}
+}
+
+func TestProductionGuardNonInteractive(t *testing.T) {
+	withProductionConfig(t, config.ProductionConfig{
+		Enabled:    true,
+		AccountIDs: []string{"111111111111"},
+		Tags:       []config.ResourceTag{{Key: "Environment", Value: "prod"}},
+	})
+
+	var buf bytes.Buffer
+	originalOutput := color.Output
+	color.Output = &buf
+	defer func() { color.Output = originalOutput }()
+
+	tests := []struct {
+		name      string
+		account   string
+		tags      map[string]map[string]string
+		confirmed bool
+		wantErr   bool
+	}{
+		{name: "non-production account and tags", account: "222222222222", tags: map[string]map[string]string{"i-0abc": {"Environment": "dev"}}},
+		{name: "production account", account: "111111111111", wantErr: true},
+		{name: "production tag matches case-insensitively", account: "222222222222", tags: map[string]map[string]string{"i-0abc": {"Environment": "PROD"}}, wantErr: true},
+		{name: "tag key is case-sensitive", account: "222222222222", tags: map[string]map[string]string{"i-0abc": {"environment": "prod"}}},
+		{name: "confirmed by flag", account: "111111111111", confirmed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			guard := fakeProductionGuard(tt.account, tt.tags)
+			guard.nonInteractive = true
+			guard.confirmed = tt.confirmed
+
+			err := guard.check(context.Background(), "ca-central-1", []string{"i-0abc"})
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "--confirm-production") {
+					t.Errorf("Expected an error pointing at --confirm-production, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestProductionGuardPrompt(t *testing.T) {
+	withProductionConfig(t, config.ProductionConfig{Enabled: true, Tags: []config.ResourceTag{{Key: "Environment", Value: "prod"}}})
+
+	var buf bytes.Buffer
+	originalOutput := color.Output
+	color.Output = &buf
+	defer func() { color.Output = originalOutput }()
+
+	prodTags := map[string]map[string]string{"i-0abc": {"Environment": "prod"}}
+
+	t.Run("yes is not enough", func(t *testing.T) {
+		guard := fakeProductionGuard("", prodTags)
+		guard.input = strings.NewReader("yes\n")
+		if err := guard.check(context.Background(), "ca-central-1", []string{"i-0abc"}); err == nil {
+			t.Error("Expected answering 'yes' to cancel the operation")
+		}
+	})
+
+	t.Run("typed confirmation covers later checks", func(t *testing.T) {
+		guard := fakeProductionGuard("", prodTags)
+		guard.input = strings.NewReader(productionConfirmWord + "\n")
+		if err := guard.check(context.Background(), "ca-central-1", []string{"i-0abc"}); err != nil {
+			t.Fatalf("Expected typed confirmation to proceed, got %v", err)
+		}
+
+		guard.input = strings.NewReader("")
+		if err := guard.check(context.Background(), "us-east-1", []string{"i-0abc"}); err != nil {
+			t.Errorf("Expected the confirmation to cover the rest of the run, got %v", err)
+		}
+	})
+
+	if !strings.Contains(buf.String(), "instance i-0abc is tagged Environment=prod") {
+		t.Errorf("Expected the warning to name the production instance, got %q", buf.String())
+	}
+}
+
+func TestProductionGuardLookupFailure(t *testing.T) {
+	withProductionConfig(t, config.ProductionConfig{Enabled: true, AccountIDs: []string{"111111111111"}})
+
+	guard := &productionGuard{
+		nonInteractive: true,
+		accountID: func(ctx context.Context, region string) (string, error) {
+			return "", errors.New("expired credentials")
+		},
+	}
+	err := guard.check(context.Background(), "ca-central-1", nil)
+	if err == nil || !strings.Contains(err.Error(), "expired credentials") {
+		t.Errorf("Expected the guard to fail closed with the lookup error, got %v", err)
+	}
+}
+
+func TestProductionReasonsCapsInstanceList(t *testing.T) {
+	tags := make(map[string]map[string]string)
+	var ids []string
+	for i := 0; i < maxListedProductionInstances+3; i++ {
+		id := fmt.Sprintf("i-%04d", i)
+		ids = append(ids, id)
+		tags[id] = map[string]string{"Environment": "prod"}
+	}
+
+	guard := fakeProductionGuard("", tags)
+	reasons, err := guard.productionReasons(context.Background(),
+		config.ProductionConfig{Tags: []config.ResourceTag{{Key: "Environment", Value: "prod"}}}, "ca-central-1", ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reasons) != maxListedProductionInstances+1 || !strings.Contains(reasons[len(reasons)-1], "3 more") {
+		t.Errorf("Expected %d listed instances and a summary line, got %v", maxListedProductionInstances, reasons)
+	}
+}
+
+func TestNewProductionGuardFlags(t *testing.T) {
+	for _, flag := range []string{"confirm-production", "i-know-this-is-prod"} {
+		cmd := &cobra.Command{Use: "test"}
+		addConfirmProductionFlag(cmd)
+		if err := cmd.Flags().Set(flag, "true"); err != nil {
+			t.Fatal(err)
+		}
+		if guard := newProductionGuard(cmd); !guard.confirmed {
+			t.Errorf("Expected --%s to confirm production targets", flag)
+		}
+	}
+}
@@ -124,7 +124,7 @@ Examples:
 // performRDSList lists all RDS instances in the region
 func performRDSList(regionCode string) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 
 	rdsClient, err := rdsClientPool.GetRDSClient(ctx, region)
 	if err != nil {
@@ -174,7 +174,7 @@ func performRDSList(regionCode string) error {
 // performRDSStart starts a stopped RDS instance
 func performRDSStart(regionCode, dbIdentifier string, wait bool) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 
 	rdsClient, err := rdsClientPool.GetRDSClient(ctx, region)
 	if err != nil {
@@ -209,7 +209,7 @@ func performRDSStart(regionCode, dbIdentifier string, wait bool) error {
 // performRDSStop stops a running RDS instance
 func performRDSStop(regionCode, dbIdentifier string, wait bool) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 
 	rdsClient, err := rdsClientPool.GetRDSClient(ctx, region)
 	if err != nil {
@@ -245,7 +245,7 @@ func performRDSStop(regionCode, dbIdentifier string, wait bool) error {
 // performRDSReboot reboots an RDS instance
 func performRDSReboot(regionCode, dbIdentifier string, forceFailover, wait bool) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 
 	rdsClient, err := rdsClientPool.GetRDSClient(ctx, region)
 	if err != nil {
@@ -343,18 +343,18 @@ func colorizeRDSStatus(status string) string {
 
 func init() {
 	// List command flags
-	rdsListCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(rdsListCmd)
 
 	// Start command flags
-	rdsStartCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(rdsStartCmd)
 	rdsStartCmd.Flags().BoolP("wait", "w", false, "Wait for the instance to become available")
 
 	// Stop command flags
-	rdsStopCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(rdsStopCmd)
 	rdsStopCmd.Flags().BoolP("wait", "w", false, "Wait for the instance to stop")
 
 	// Reboot command flags
-	rdsRebootCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(rdsRebootCmd)
 	rdsRebootCmd.Flags().BoolP("force-failover", "f", false, "Force a failover for Multi-AZ instances")
 	rdsRebootCmd.Flags().BoolP("wait", "w", false, "Wait for the instance to become available")
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"ztictl/internal/config"
+	awspkg "ztictl/pkg/aws"
+	"ztictl/pkg/colors"
+
+	"github.com/spf13/cobra"
+)
+
+// regionsCmd represents the regions command
+var regionsCmd = &cobra.Command{
+	Use:   "regions",
+	Short: "List the region shortcodes ztictl understands",
+	Long: `List every region shortcode accepted by --region and region arguments, with the AWS region it maps to.
+Built-in shortcodes are listed together with custom ones defined under regions.shortcodes in ~/.ztictl.yaml.
+A custom shortcode overrides a built-in one with the same name.
+
+Examples:
+  ztictl regions`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		printRegionMappings(os.Stdout, config.RegionResolver().List())
+	},
+}
+
+// printRegionMappings prints shortcode to region mappings as a table
+func printRegionMappings(w io.Writer, mappings []awspkg.RegionShortcode) {
+	_, _ = fmt.Fprint(w, colors.ColorHeader("%-10s %-16s %-9s %s\n", "Shortcode", "Region", "Source", "Description"))
+	for _, mapping := range mappings {
+		source := "built-in"
+		if mapping.Custom {
+			source = "custom"
+		}
+		_, _ = fmt.Fprintf(w, "%-10s %-16s %-9s %s\n", mapping.Shortcode, mapping.Region, source, mapping.Description)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(regionsCmd)
+}
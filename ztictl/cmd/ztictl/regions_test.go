@@ -0,0 +1,21 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	awspkg "ztictl/pkg/aws"
+)
+
+func TestPrintRegionMappings(t *testing.T) {
+	var out bytes.Buffer
+	printRegionMappings(&out, awspkg.NewRegionResolver(map[string]string{"tlv": "il-central-1"}).List())
+
+	output := out.String()
+	for _, want := range []string{"Shortcode", "cac1", "ca-central-1", "built-in", "tlv", "il-central-1", "custom"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in regions output:\n%s", want, output)
+		}
+	}
+}
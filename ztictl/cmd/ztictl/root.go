@@ -10,9 +10,14 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
+	"ztictl/internal/auth"
 	"ztictl/internal/config"
 	"ztictl/internal/splash"
+	"ztictl/internal/ssm"
+	awspkg "ztictl/pkg/aws"
+	"ztictl/pkg/colors"
 	"ztictl/pkg/logging"
 	"ztictl/pkg/version"
 
@@ -30,6 +35,14 @@ var (
 	showSplash     bool
 	nonInteractive bool
 	autoYes        bool
+	noColor        bool
+	quiet          bool
+	deadline       time.Duration
+	awsEndpointURL string
+	noCache        bool
+	refreshCache   bool
+	useDualStack   bool
+	useFIPS        bool
 	logger         *logging.Logger
 )
 
@@ -141,11 +154,11 @@ Features:
 			parentCtx = context.Background()
 		}
 
-		ctx := context.WithValue(parentCtx, execContextKey, execCtx)
+		ctx := applyDeadline(context.WithValue(parentCtx, execContextKey, execCtx), deadline)
 		cmd.SetContext(ctx)
 
-		// Skip splash for help, version, and completion commands
-		if cmd.Name() == "help" || cmd.Name() == "version" || cmd.Name() == cobra.ShellCompRequestCmd || cmd.Parent() == nil {
+		// Skip splash for help, version, and completion commands, and in quiet mode
+		if cmd.Name() == "help" || cmd.Name() == "version" || cmd.Name() == cobra.ShellCompRequestCmd || cmd.Parent() == nil || quiet {
 			return
 		}
 
@@ -197,12 +210,24 @@ Features:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The --deadline budget covers everything the command does; on expiry all in-flight calls are cancelled.
 func Execute() error {
-	return rootCmd.Execute()
+	defer func() { cancelDeadline() }()
+	// Remove the temporary IAM policies and S3 objects of interrupted transfers before a panic ends the process
+	defer ssm.CleanupOnPanic()
+
+	err := rootCmd.Execute()
+	if deadlineExceeded() {
+		logging.LogError("Deadline of %v exceeded, remaining operations were cancelled", deadline)
+	}
+	return err
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initColors, initQuiet, initAWSEndpoint, initConfig, initInstanceCache, initSessionManagerPlugin, initEndpointVariants, initAWSRetry, initMetrics)
+
+	// Resolve SSO credentials from the encrypted token cache when sso.encrypt_token_cache is set
+	awspkg.SetCredentialOptions(auth.CredentialLoadOptions)
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "config file (default is $HOME/.ztictl.yaml)")
@@ -210,6 +235,15 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&showSplash, "show-splash", false, "force display of welcome splash screen")
 	rootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false, "disable all interactive prompts (fail with error if input required)")
 	rootCmd.PersistentFlags().BoolVarP(&autoYes, "yes", "y", false, "automatically answer yes to all confirmation prompts")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output (also honors the NO_COLOR environment variable)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "only print failures (suppresses success output, summaries and info logs)")
+	rootCmd.PersistentFlags().DurationVar(&deadline, "deadline", 0, "overall time budget for the command, e.g. 10m (0 for no limit)")
+	rootCmd.PersistentFlags().DurationVar(&deadline, "max-duration", 0, "alias for --deadline")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "do not read or write the instance lookup cache")
+	rootCmd.PersistentFlags().BoolVar(&refreshCache, "refresh", false, "ignore cached instance lookups and cache fresh results")
+	rootCmd.PersistentFlags().StringVar(&awsEndpointURL, "aws-endpoint-url", "", "send AWS API calls to a custom endpoint such as LocalStack (AWS_ENDPOINT_URL is also honored)")
+	rootCmd.PersistentFlags().BoolVar(&useDualStack, "dualstack", false, "use dualstack (IPv6) AWS endpoints (or set system.use_dualstack_endpoint)")
+	rootCmd.PersistentFlags().BoolVar(&useFIPS, "fips", false, "use FIPS AWS endpoints (or set system.use_fips_endpoint)")
 
 	// Bind flags to viper
 	_ = viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug")) // #nosec G104
@@ -218,6 +252,76 @@ func init() {
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 }
 
+// initColors disables colored output when requested via --no-color or NO_COLOR
+func initColors() {
+	if colors.NoColorRequested(noColor) {
+		colors.Disable()
+	}
+}
+
+// initQuiet limits console output to warnings and errors when --quiet is set
+func initQuiet() {
+	logging.SetQuiet(quiet)
+}
+
+// initAWSEndpoint applies --aws-endpoint-url to the AWS clients created by every command
+func initAWSEndpoint() {
+	if err := awspkg.SetEndpointURL(awsEndpointURL); err != nil {
+		logging.LogError("%v", err)
+		os.Exit(1)
+	}
+	if awsEndpointURL != "" {
+		logging.LogInfo("Using custom AWS endpoint %s", awsEndpointURL)
+	}
+}
+
+// initInstanceCache enables the on-disk instance lookup cache unless --no-cache or system.instance_cache_ttl 0 turns it off
+func initInstanceCache() {
+	ttl := config.Get().System.InstanceCacheTTL
+	if noCache || ttl <= 0 {
+		return
+	}
+	dir, err := awspkg.DefaultInstanceCacheDir()
+	if err != nil {
+		logging.LogDebug("Instance cache disabled: %v", err)
+		return
+	}
+	awspkg.SetInstanceCache(awspkg.NewInstanceCache(dir, time.Duration(ttl)*time.Second, refreshCache))
+}
+
+// initEndpointVariants switches AWS clients to dualstack and/or FIPS endpoints when the flag or config asks for them
+func initEndpointVariants() {
+	system := config.Get().System
+	dualStack := useDualStack || system.UseDualStackEndpoint
+	fips := useFIPS || system.UseFIPSEndpoint
+	awspkg.SetEndpointVariants(dualStack, fips)
+	if dualStack {
+		logging.LogDebug("Using dualstack AWS endpoints")
+	}
+	if fips {
+		logging.LogDebug("Using FIPS AWS endpoints")
+	}
+}
+
+// initAWSRetry applies system.aws_max_attempts and system.aws_retry_mode to the SDK retries of every AWS client
+func initAWSRetry() {
+	system := config.Get().System
+	if err := awspkg.SetRetryOptions(system.AWSMaxAttempts, system.AWSRetryMode); err != nil {
+		logging.LogWarn("Ignoring AWS retry settings: %v", err)
+		return
+	}
+	if system.AWSMaxAttempts > 0 || system.AWSRetryMode != "" {
+		logging.LogDebug("AWS SDK retries | max_attempts=%d mode=%s", system.AWSMaxAttempts, system.AWSRetryMode)
+	}
+}
+
+// initSessionManagerPlugin puts system.session_manager_plugin_path on PATH for the AWS CLI subprocesses
+func initSessionManagerPlugin() {
+	if err := ssm.SetSessionManagerPluginPath(config.Get().System.SessionManagerPluginPath); err != nil {
+		logging.LogWarn("Ignoring system.session_manager_plugin_path: %v", err)
+	}
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	// Initialize logger with our adapter
@@ -225,13 +329,13 @@ func initConfig() {
 
 	// Perform configuration setup and handle any errors
 	if err := setupConfiguration(); err != nil {
-		// Check if we're running a config command
+		// Check if we're running a config or doctor command
 		if len(os.Args) > 1 {
-			// Check for config command or its subcommands
-			if os.Args[1] == "config" ||
+			// Check for config command or its subcommands, or doctor (which reports the errors itself)
+			if os.Args[1] == "config" || os.Args[1] == "doctor" ||
 				(len(os.Args) > 2 && os.Args[1] == "config" &&
 					(os.Args[2] == "init" || os.Args[2] == "repair" || os.Args[2] == "check")) {
-				logger.Warn("Configuration has errors, but allowing config command to run", "error", err)
+				logger.Warn("Configuration has errors, but allowing command to run", "error", err)
 				// Load config with invalid values allowed for config commands
 				_, _ = config.LoadWithOptions(true) // Ignore error, we're in repair mode
 				return
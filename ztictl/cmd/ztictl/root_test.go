@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -136,6 +137,52 @@ func TestGlobalFlags(t *testing.T) {
 	}
 }
 
+func TestInitColors(t *testing.T) {
+	origNoColor := noColor
+	origColorNoColor := color.NoColor
+	defer func() {
+		noColor = origNoColor
+		color.NoColor = origColorNoColor
+	}()
+
+	if rootCmd.PersistentFlags().Lookup("no-color") == nil {
+		t.Fatal("Expected --no-color global flag to be registered")
+	}
+
+	t.Run("leaves colors alone by default", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		noColor = false
+		color.NoColor = false
+
+		initColors()
+		if color.NoColor {
+			t.Error("Expected colors to remain enabled")
+		}
+	})
+
+	t.Run("flag disables colors", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		noColor = true
+		color.NoColor = false
+
+		initColors()
+		if !color.NoColor {
+			t.Error("Expected --no-color to disable colors")
+		}
+	})
+
+	t.Run("NO_COLOR disables colors", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		noColor = false
+		color.NoColor = false
+
+		initColors()
+		if !color.NoColor {
+			t.Error("Expected NO_COLOR to disable colors")
+		}
+	})
+}
+
 func TestInitConfig(t *testing.T) {
 	// Save original viper state
 	viperInstance := viper.GetViper()
@@ -17,12 +17,14 @@ Examples:
   ztictl ssm ssh-config <instance>      # Generate SSH config for SSM access
   ztictl ssm rdp <instance>             # RDP to Windows instance via SSM tunnel
   ztictl ssm list [filters]             # List SSM-enabled instances
+  ztictl ssm watch [filters]            # Live-refresh instance list and SSM status
   ztictl ssm forward <instance> <ports> # Port forwarding via SSM
   ztictl ssm transfer <src> <dst>       # File transfer via SSM
   ztictl ssm command <instance> <cmd>   # Execute command via SSM
   ztictl ssm exec <region> <instance> <cmd>           # Quick exec with region shortcode
   ztictl ssm exec-tagged <region> --tags <tags> <cmd> # Execute on tagged instances
   ztictl ssm status [instance]          # Check SSM agent status
+  ztictl ssm inventory [--tags <tags>]  # OS and installed software from SSM Inventory
   ztictl ssm start <instance>           # Start a stopped instance
   ztictl ssm stop <instance>            # Stop a running instance
   ztictl ssm reboot <instance>          # Reboot an instance
@@ -38,10 +40,15 @@ func init() {
 	// Equivalent to sourcing individual .sh files in bash version
 	ssmCmd.AddCommand(ssmConnectCmd)          // ssm_connect.go
 	ssmCmd.AddCommand(ssmListCmd)             // ssm_list.go
+	ssmCmd.AddCommand(ssmWatchCmd)            // ssm_watch.go
 	ssmCmd.AddCommand(ssmCommandCmd)          // ssm_command.go
 	ssmCmd.AddCommand(ssmTransferCmd)         // ssm_transfer.go
 	ssmCmd.AddCommand(ssmForwardCmd)          // ssm_management.go
 	ssmCmd.AddCommand(ssmStatusCmd)           // ssm_management.go
+	ssmCmd.AddCommand(ssmStaleCmd)            // ssm_stale.go
+	ssmCmd.AddCommand(ssmMetricsCmd)          // ssm_metrics.go
+	ssmCmd.AddCommand(ssmInventoryCmd)        // ssm_inventory.go
+	ssmCmd.AddCommand(ssmCheckIAMCmd)         // ssm_check_iam.go
 	ssmCmd.AddCommand(ssmExecCmd)             // ssm_exec.go
 	ssmCmd.AddCommand(ssmExecTaggedCmd)       // ssm_exec.go
 	ssmCmd.AddCommand(ssmExecMultiCmd)        // ssm_exec_multi.go
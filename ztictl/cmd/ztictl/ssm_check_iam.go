@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"ztictl/internal/ssm"
+	"ztictl/pkg/aws"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// ssmCheckIAMCmd represents the ssm check-iam command
+var ssmCheckIAMCmd = &cobra.Command{
+	Use:   "check-iam",
+	Short: "Check that instances have the IAM setup needed for S3 file transfers",
+	Long: `Check the IAM prerequisites of large file transfers before starting one, without transferring anything.
+Each target needs an IAM instance profile with a role, and the role needs the SSM agent permissions of
+the AmazonSSMManagedInstanceCore managed policy. The role's permissions are evaluated with the IAM policy
+simulator, which needs iam:SimulatePrincipalPolicy; without it they are reported as unverified.
+Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
+Use --tags and --instances to select targets; with both, explicit instances are added to the tag matches.
+Exits with status 1 when any target is not ready.
+
+Examples:
+  ztictl ssm check-iam --region cac1 --instances i-1234567890abcdef0,i-0987654321fedcba0
+  ztictl ssm check-iam --region use1 --tags Environment=prod,Role=web
+  ztictl ssm check-iam --region cac1 --asg web-prod-asg --output json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		regionCode, _ := cmd.Flags().GetString("region")
+		tagsFlag, _ := cmd.Flags().GetString("tags")
+		instancesFlag, _ := cmd.Flags().GetString("instances")
+		output, _ := cmd.Flags().GetString("output")
+
+		region := resolveRegion(regionCode)
+		instancesFlag, err := resolveASGFlag(commandContext(), cmd, region, tagsFlag, instancesFlag)
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
+
+		ready, err := performIAMCheck(os.Stdout, region, tagsFlag, instancesFlag, output)
+		if err != nil {
+			exitWithError(cmd, 1, "IAM check failed", err)
+		}
+		if !ready {
+			os.Exit(1)
+		}
+	},
+}
+
+// performIAMCheck checks the IAM setup of the tag and explicit instance targets in a region and prints
+// the results in the given output format. It reports whether every target is ready.
+func performIAMCheck(w io.Writer, region, tagsFlag, instancesFlag, output string) (bool, error) {
+	switch output {
+	case outputFormatText, outputFormatJSON, outputFormatYAML:
+	default:
+		return false, fmt.Errorf("invalid --output '%s' (expected text, json or yaml)", output)
+	}
+	if tagsFlag == "" && instancesFlag == "" {
+		return false, fmt.Errorf("either --tags, --instances or --asg is required")
+	}
+
+	ctx := commandContext()
+	awsClient, err := aws.NewClient(ctx, aws.ClientOptions{Region: region})
+	if err != nil {
+		return false, fmt.Errorf("failed to create AWS client: %w", err)
+	}
+	instanceIDs, err := resolveTaggedTargets(ctx, awsClient, tagsFlag, instancesFlag)
+	if err != nil {
+		return false, err
+	}
+	if len(instanceIDs) == 0 {
+		return false, fmt.Errorf("no instances found %s in %s", describeTargets(tagsFlag, instancesFlag), region)
+	}
+	logging.LogInfo("Checking IAM setup of %d instance(s) %s in %s", len(instanceIDs), describeTargets(tagsFlag, instancesFlag), region)
+
+	results, err := ssm.NewManager(logger).CheckInstanceIAM(ctx, region, instanceIDs)
+	if err != nil {
+		return false, err
+	}
+
+	ready := true
+	for _, result := range results {
+		ready = ready && result.Ready()
+	}
+
+	switch output {
+	case outputFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return ready, encoder.Encode(results)
+	case outputFormatYAML:
+		return ready, writeYAML(w, results)
+	}
+	printIAMCheck(w, results)
+	return ready, nil
+}
+
+// printIAMCheck prints IAM check results as a table followed by a summary
+func printIAMCheck(w io.Writer, results []ssm.IAMCheckResult) {
+	formatter := NewTableFormatter(2)
+	ids := make([]string, len(results))
+	statuses := make([]string, len(results))
+	roles := make([]string, len(results))
+	details := make([]string, len(results))
+
+	readyCount := 0
+	for i, result := range results {
+		ids[i] = result.InstanceID
+		statuses[i] = result.Status
+		roles[i] = result.Role
+		if roles[i] == "" {
+			roles[i] = "-"
+		}
+		details[i] = result.Error
+		if len(result.MissingActions) > 0 {
+			details[i] = "missing " + strings.Join(result.MissingActions, ", ")
+		}
+		if result.Ready() {
+			readyCount++
+		}
+	}
+
+	formatter.AddColumn("Instance ID", ids, 19)
+	formatter.AddColumn("Status", statuses, 6)
+	formatter.AddColumn("Role", roles, 4)
+	formatter.AddColumn("Details", details, 7)
+
+	_, _ = fmt.Fprintf(w, "%s\n", colors.ColorHeader("%s", formatter.FormatHeader()))
+	for i := 0; i < formatter.GetRowCount(); i++ {
+		_, _ = fmt.Fprintf(w, "%s\n", formatter.FormatRow(i))
+	}
+	_, _ = fmt.Fprintf(w, "\n%d of %d instance(s) ready for transfers\n", readyCount, len(results))
+}
+
+func init() {
+	addRegionFlag(ssmCheckIAMCmd)
+	ssmCheckIAMCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, or key alone to match any value, separated by commas")
+	ssmCheckIAMCmd.Flags().StringP("instances", "i", "", "Comma-separated list of instance IDs to target explicitly")
+	addASGFlag(ssmCheckIAMCmd)
+	ssmCheckIAMCmd.Flags().StringP("output", "o", "text", "Output format: text, json or yaml")
+}
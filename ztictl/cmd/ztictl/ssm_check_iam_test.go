@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"ztictl/internal/ssm"
+)
+
+func TestPrintIAMCheck(t *testing.T) {
+	var buf bytes.Buffer
+	printIAMCheck(&buf, []ssm.IAMCheckResult{
+		{InstanceID: "i-ready", Status: ssm.IAMCheckReady, InstanceProfile: "web-profile", Role: "web-role"},
+		{InstanceID: "i-bare", Status: ssm.IAMCheckNoInstanceProfile, Error: "no IAM instance profile is attached"},
+		{InstanceID: "i-limited", Status: ssm.IAMCheckMissingPermissions, Role: "batch-role",
+			MissingActions: []string{"ssmmessages:CreateControlChannel", "ssmmessages:OpenControlChannel"}, Error: "the role is missing SSM agent permissions"},
+	})
+
+	output := buf.String()
+	for _, want := range []string{
+		"Instance ID", "Status", "Role", "Details",
+		"i-ready", "ready", "web-role",
+		"i-bare", "no_instance_profile", "no IAM instance profile is attached",
+		"i-limited", "missing ssmmessages:CreateControlChannel, ssmmessages:OpenControlChannel",
+		"1 of 3 instance(s) ready for transfers",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestPerformIAMCheckValidation(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := performIAMCheck(&buf, "ca-central-1", "", "i-1234567890abcdef0", "csv"); err == nil {
+		t.Error("Expected unsupported --output to be rejected")
+	}
+	if _, err := performIAMCheck(&buf, "ca-central-1", "", "", "text"); err == nil || !strings.Contains(err.Error(), "--tags, --instances or --asg") {
+		t.Errorf("Expected targets to be required, got %v", err)
+	}
+
+	if ssmCheckIAMCmd.Flags().Lookup("asg") == nil || ssmCheckIAMCmd.Flags().Lookup("tags") == nil {
+		t.Error("Expected --tags and --asg flags on check-iam")
+	}
+}
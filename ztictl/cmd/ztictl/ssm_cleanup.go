@@ -1,8 +1,6 @@
 package main
 
 import (
-	"context"
-
 	"ztictl/internal/ssm"
 	"ztictl/pkg/colors"
 	"ztictl/pkg/logging"
@@ -17,12 +15,13 @@ var ssmCleanupCmd = &cobra.Command{
 	Long: `Clean up temporary IAM policies, S3 objects, and other resources
 created during file transfer operations. This includes:
 
-- Removing stale IAM policies attached for S3 access
-- Cleaning up old registry entries
-- Removing stale lock files
+- Detaching and deleting ZTIaws-SSM-S3-Access-* IAM policies older than 6 hours
+- Deleting files staged in the region's transfer bucket older than 6 hours
 
+Resources of transfers that are still running are left alone.
 Use this command if file transfer operations were interrupted
 and temporary resources were not cleaned up automatically.
+The same check runs before the first large transfer of each command.
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		regionCode, _ := cmd.Flags().GetString("region")
@@ -36,7 +35,7 @@ Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-
 		logging.LogInfo("Starting cleanup operation in region: %s", region)
 
 		ssmManager := ssm.NewManager(logger)
-		ctx := context.Background()
+		ctx := commandContext()
 
 		// Perform routine cleanup
 		if err := ssmManager.Cleanup(ctx, region); err != nil {
@@ -56,15 +55,14 @@ var ssmEmergencyCleanupCmd = &cobra.Command{
 	Use:   "emergency-cleanup",
 	Short: "Perform emergency cleanup of all temporary resources",
 	Long: `Perform emergency cleanup of all temporary resources created by ztictl.
-This is a more aggressive cleanup that:
+This is a more aggressive cleanup that, regardless of age:
 
-- Removes all IAM policies created by ztictl for S3 access
-- Cleans up all registry entries regardless of age
-- Removes all lock files
-- Attempts to detach policies from instance roles
+- Detaches all IAM policies created by ztictl for S3 access from instance roles
+- Deletes those policies
+- Deletes all files staged in the region's transfer bucket
 
-Use this command if normal cleanup fails or if you need to
-ensure all temporary resources are removed.`,
+Transfers running at the same time will fail. Use this command if normal
+cleanup fails or if you need to ensure all temporary resources are removed.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		regionCode, _ := cmd.Flags().GetString("region")
 		region := resolveRegion(regionCode)
@@ -77,7 +75,7 @@ ensure all temporary resources are removed.`,
 		logging.LogInfo("Starting emergency cleanup operation in region: %s", region)
 
 		ssmManager := ssm.NewManager(logger)
-		ctx := context.Background()
+		ctx := commandContext()
 
 		// Perform emergency cleanup
 		if err := ssmManager.EmergencyCleanup(ctx, region); err != nil {
@@ -93,6 +91,6 @@ ensure all temporary resources are removed.`,
 }
 
 func init() {
-	ssmCleanupCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
-	ssmEmergencyCleanupCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmCleanupCmd)
+	addRegionFlag(ssmEmergencyCleanupCmd)
 }
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -18,7 +17,7 @@ var ssmCommandCmd = &cobra.Command{
 	Use:   "command <instance-identifier> <command>",
 	Short: "Execute a command on an instance",
 	Long: `Execute a command on an EC2 instance via SSM Run Command.
-Instance identifier can be an instance ID (i-1234567890abcdef0) or instance name.
+Instance identifier can be an instance ID (i-1234567890abcdef0), instance name, private IP or private DNS name.
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.`,
 	Args: cobra.MinimumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -41,13 +40,19 @@ func performCommandExecution(regionCode, instanceIdentifier, command, comment st
 	logging.LogInfo("Executing command '%s' on instance %s in region: %s", command, instanceIdentifier, region)
 
 	ssmManager := ssm.NewManager(logger)
-	ctx := context.Background()
+	ctx := commandContext()
+
+	redactor, err := newOutputRedactor()
+	if err != nil {
+		return err
+	}
 
 	result, err := ssmManager.ExecuteCommand(ctx, instanceIdentifier, region, command, comment)
 	if err != nil {
 		colors.PrintError("✗ Command execution failed on instance %s\n", instanceIdentifier)
 		return fmt.Errorf("command execution failed: %w", err)
 	}
+	redactResult(redactor, result)
 
 	fmt.Printf("\n")
 	colors.PrintHeader("=== Command Execution Result ===\n")
@@ -68,6 +73,6 @@ func performCommandExecution(regionCode, instanceIdentifier, command, comment st
 }
 
 func init() {
-	ssmCommandCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmCommandCmd)
 	ssmCommandCmd.Flags().StringP("comment", "c", "", "Comment for the command execution")
 }
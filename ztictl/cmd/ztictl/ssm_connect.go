@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
 
@@ -17,7 +16,7 @@ var ssmConnectCmd = &cobra.Command{
 	Short: "Connect to an instance via SSM Session Manager",
 	Long: `Connect to an EC2 instance using SSM Session Manager.
 If no instance identifier is provided, an interactive fuzzy finder will be launched.
-Instance identifier can be an instance ID (i-1234567890abcdef0) or instance name.
+Instance identifier can be an instance ID (i-1234567890abcdef0), instance name, private IP or private DNS name.
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -38,7 +37,7 @@ Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-
 // performConnection handles SSM connection logic and returns errors instead of calling os.Exit
 func performConnection(regionCode, instanceIdentifier string) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 	ssmManager := ssm.NewManager(logger)
 
 	// Use the shared instance selection logic
@@ -62,6 +61,7 @@ func performConnection(regionCode, instanceIdentifier string) error {
 	}
 
 	logging.LogInfo("Connecting to instance %s in region: %s", instanceID, region)
+	recordHistory(historyOpConnect, region, []string{instanceID}, "")
 
 	if err := ssmManager.StartSession(ctx, instanceID, region); err != nil {
 		return fmt.Errorf("failed to start session: %w", err)
@@ -71,5 +71,5 @@ func performConnection(regionCode, instanceIdentifier string) error {
 }
 
 func init() {
-	ssmConnectCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmConnectCmd)
 }
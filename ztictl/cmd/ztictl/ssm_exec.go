@@ -2,18 +2,24 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"runtime"
-	"strconv"
+	"path"
+	"regexp"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"ztictl/internal/interactive"
+	"ztictl/internal/platform"
 	"ztictl/internal/ssm"
+	awspkg "ztictl/pkg/aws"
 	"ztictl/pkg/colors"
+	"ztictl/pkg/format"
 	"ztictl/pkg/logging"
+	"ztictl/pkg/security"
 
 	"github.com/spf13/cobra"
 )
@@ -25,7 +31,8 @@ var ssmExecCmd = &cobra.Command{
 	Long: `Execute a command on a single EC2 instance via SSM.
 If no instance identifier is provided, an interactive fuzzy finder will be launched.
 Region shortcuts supported: cac1, use1, euw1, etc.
-Instance identifier can be an instance ID or name.
+Instance identifier can be an instance ID, a name, a private IP or private DNS name, or a Name tag pattern with * and ? wildcards.
+A pattern runs the command on every matching instance (confirmation is required above 5 matches).
 
 Examples:
   # Interactive fuzzy finder (new):
@@ -34,25 +41,61 @@ Examples:
 
   # Direct instance specification (backward compatible):
   ztictl ssm exec cac1 i-1234567890abcdef0 "uptime"
-  ztictl ssm exec use1 web-server "sudo systemctl status nginx"`,
-	Args: cobra.MinimumNArgs(1),
+  ztictl ssm exec use1 web-server "sudo systemctl status nginx"
+
+  # Run as root on instances where the agent runs as ssm-user (requires passwordless sudo):
+  ztictl ssm exec cac1 web-server --sudo "systemctl restart nginx"
+
+  # Run as a specific user, with their home directory and permissions:
+  ztictl ssm exec cac1 web-server --run-as appuser "./bin/console cache:clear"
+
+  # Fan out to all instances whose Name tag matches a pattern:
+  ztictl ssm exec cac1 "web-*" "uptime"
+
+  # Watch results live as each instance finishes, prefixed with its instance ID:
+  ztictl ssm exec cac1 "web-*" --output-mode interleaved "uptime"
+  ztictl ssm exec cac1 "web-*" --output-mode interleaved --output-prefix name "uptime"
+  ztictl ssm exec cac1 "web-*" --output-compare "rpm -q openssl"
+
+  # Run local hooks around the execution:
+  ztictl ssm exec cac1 web-server --pre-hook "./notify.sh start" --post-hook "./notify.sh done" "uptime"
+
+  # Read a multi-line command from a file or stdin instead of the command argument:
+  ztictl ssm exec cac1 web-server --command-file rotate-logs.sh
+  ./render-steps.sh | ztictl ssm exec cac1 web-server --command-file -
+
+  # Run several commands in order, stopping at the first that fails:
+  ztictl ssm exec cac1 web-server --command "apt-get update" --command "apt-get install -y jq"`,
+	Args:    execArgs(0, 2),
+	PreRunE: validateRegionArgs(execRegionArg),
 	Run: func(cmd *cobra.Command, args []string) {
+		args, err := withCommandFile(cmd, args)
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
 		regionFlag, _ := cmd.Flags().GetString("region")
+		opts, err := resolveExecOptions(cmd)
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
+		opts.applyOutputFormat()
+		idempotent, err := resolveIdempotency(cmd, args)
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
+		done, err := idempotent.completed()
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
+		if done {
+			return
+		}
+		opts.announceRun()
 
-		if err := executeCommandWithFuzzyFinder(args, regionFlag); err != nil {
-			logging.LogError("Command execution failed: %v", err)
-			// Check if it's a non-zero exit code error and exit with that code
-			if strings.Contains(err.Error(), "command exited with non-zero status:") {
-				// Extract exit code from error message
-				parts := strings.Split(err.Error(), ": ")
-				if len(parts) > 1 {
-					if exitCode, parseErr := strconv.Atoi(parts[len(parts)-1]); parseErr == nil {
-						os.Exit(exitCode)
-					}
-				}
-			}
-			os.Exit(1)
+		if err := executeCommandWithFuzzyFinder(args, regionFlag, opts, GetExecutionContext(cmd)); err != nil {
+			exitWithError(cmd, execExitCode(err), "Command execution failed", err)
 		}
+		idempotent.complete()
 	},
 }
 
@@ -63,8 +106,11 @@ var ssmExecTaggedCmd = &cobra.Command{
 	Long: `Execute a command on EC2 instances that match the specified tags via SSM.
 Region shortcuts supported: cac1, use1, euw1, etc.
 Use --tags flag to specify one or more tag filters in key=value format, separated by commas.
-Use --instances to explicitly specify instance IDs to target (comma-separated).
-Use --parallel to control maximum concurrent executions (default: number of CPU cores).
+Use --instances to explicitly specify instance IDs to target (comma-separated); with --tags they are added to the tag matches.
+Use --asg to target the InService and Standby instances of an Auto Scaling group.
+Use --vpc and --subnet to keep only the targets in those VPCs or subnets.
+Use --parallel to control maximum concurrent executions (default: system.default_parallel, 10),
+or --parallel auto to run one per target up to 20.
 
 ALL COMMANDS RUN IN PARALLEL BY DEFAULT for improved performance at scale.
 
@@ -72,39 +118,641 @@ Examples:
   ztictl ssm exec-tagged cac1 --tags Environment=Production "uptime"
   ztictl ssm exec-tagged use1 --tags Environment=dev,Component=fts --parallel 5 "sudo systemctl restart nginx"
   ztictl ssm exec-tagged cac1 --instances i-1234,i-5678 "ps aux | grep java"
-  ztictl ssm exec-tagged use1 --tags Team=backend --parallel 10 "df -h"`,
-	Args: cobra.MinimumNArgs(2),
+  ztictl ssm exec-tagged use1 --tags Team=backend --parallel 10 "df -h"
+  ztictl ssm exec-tagged use1 --tags Team=backend --parallel auto "df -h"
+  ztictl ssm exec-tagged cac1 --asg web-prod-asg "systemctl status nginx"
+  ztictl ssm exec-tagged cac1 --tags Role=web --subnet subnet-0abc123 "ip route"
+  ztictl ssm exec-tagged cac1 --tags Environment=prod --exclude i-0canary,"maint-*" "uptime"
+  ztictl ssm exec-tagged cac1 --tags Role=worker --output-mode interleaved "tail -n 5 /var/log/app.log"
+  ztictl ssm exec-tagged cac1 --tags Environment=prod --verbose-on-failure "yum -y update"
+  ztictl ssm exec-tagged cac1 --tags App=api --post-hook 'echo "$ZTICTL_FAILURE_COUNT failed"' "uptime"
+  ztictl ssm exec-tagged cac1 --tags Role=worker --command-file drain-and-restart.sh
+  ztictl ssm exec-tagged cac1 --tags Role=web --command "apt-get update" --command "apt-get install -y jq"
+  ztictl ssm exec-tagged cac1 --tags Role=db --idempotency-key migrate-42 "/opt/app/bin/migrate"  # Skipped when retried
+  ztictl ssm exec-tagged cac1 --tags Role=web --timeout 2m --batch-timeout 10m "yum -y update"
+  ztictl ssm exec-tagged cac1 --tags Environment=prod --count-only        # How many instances would run it`,
+	Args:    execArgs(1, 1),
+	PreRunE: validateRegionArgs(firstRegionArg),
 	Run: func(cmd *cobra.Command, args []string) {
+		args, err := withCommandFile(cmd, args)
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
 		regionCode := args[0]
 		command := strings.Join(args[1:], " ")
 
 		// Get flags
 		tagsFlag, _ := cmd.Flags().GetString("tags")
 		instancesFlag, _ := cmd.Flags().GetString("instances")
-		parallelFlag, _ := cmd.Flags().GetInt("parallel")
+		parallelFlag := getParallelFlag(cmd)
+		opts, err := resolveExecOptions(cmd)
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
+		opts.applyOutputFormat()
 
-		successful, err := executeTaggedCommand(regionCode, command, tagsFlag, instancesFlag, parallelFlag)
+		idempotent, err := resolveIdempotency(cmd, args)
 		if err != nil {
-			logging.LogError("Tagged command execution failed: %v", err)
-			os.Exit(1)
+			exitWithError(cmd, 1, "", err)
+		}
+		done, err := idempotent.completed()
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
+		if done {
+			return
+		}
+
+		instancesFlag, err = resolveASGFlag(commandContext(), cmd, resolveRegion(regionCode), tagsFlag, instancesFlag)
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
+		opts.announceRun()
+
+		successful, err := executeTaggedCommand(regionCode, command, tagsFlag, instancesFlag, parallelFlag, opts)
+		if err != nil {
+			exitWithError(cmd, execExitCode(err), "Tagged command execution failed", err)
 		}
 
 		if !successful {
 			os.Exit(1)
 		}
+		idempotent.complete()
 	},
 }
 
+// execOptions holds per-run settings shared by the exec, exec-tagged and exec-multi commands
+type execOptions struct {
+	Hooks   execHooks
+	Sudo    bool
+	RunAs   string   // OS user to run the command as, in their environment (Linux only)
+	NoWrap  bool     // Sends the command verbatim, without the exit code wrapper
+	Exclude []string // Instance IDs or Name tag globs removed from the resolved targets
+
+	// Steps are the --command values run in order in place of the command; nil runs the command
+	Steps               []string
+	ContinueOnStepError bool
+
+	// CommandTemplate is exec.command_wrapper_template, rendered around the command on each instance
+	CommandTemplate *template.Template
+
+	// TargetStatus keeps only instances whose SSM agent reports this ping status; empty requires Online
+	TargetStatus string
+	// Network keeps only tag, instance and ASG targets inside the --vpc and --subnet IDs
+	Network networkScope
+
+	CancelOnTimeout bool
+	Timeout         time.Duration      // Limits each instance's command; 0 waits up to 5 minutes
+	BatchTimeout    time.Duration      // Bounds the whole fan-out; 0 disables it
+	Platform        platform.Platform  // Forces the SSM document (linux or windows); empty auto-detects
+	OutputMode      string             // outputModeGrouped or outputModeInterleaved
+	OutputPrefix    string             // Label before interleaved lines: outputPrefixID, Name, Index or None
+	BatchSize       int                // Instances per SendCommand call; 0 sends one command per instance
+	MinParallel     int                // Lowest concurrency the pool backs off to when SSM throttles requests
+	Rate            float64            // SendCommand calls per second allowed in each region; 0 is unlimited
+	Label           string             // User label recorded in every SendCommand comment
+	RunID           string             // Generated per run and recorded in every SendCommand comment
+	Output          string             // outputFormatText, outputFormatJSON, outputFormatYAML or outputFormatJSONL
+	OutputTemplate  *template.Template // Renders each instance result to stdout instead of --output
+	FailFast        *failFastThreshold // Stops starting queued executions once too many fail; nil disables it
+	OutputFile      string             // Aggregated report path, written in the Output format
+	ArchiveS3       string             // s3://bucket/prefix the JSON report of the run is uploaded to; empty disables it
+	JSONStyle       string             // jsonStylePretty or jsonStyleCompact; empty indents JSON only on a terminal
+	Redactor        *security.Redactor // Masks secrets in command output; nil leaves it unchanged
+	HideOutput      bool               // Print only the status and exit code of each instance in text output
+	ShowErrors      bool               // With HideOutput, still print the output of failed instances
+	OutputCompare   bool               // Group instances by identical output instead of printing each one's
+
+	// VerboseOnFailure collapses each successful instance to one status line and prints failed ones in
+	// full; it implies HideOutput and ShowErrors
+	VerboseOnFailure bool
+
+	// ErrorContext limits the error output printed for a failed instance to its last lines; 0 prints all of it
+	ErrorContext int
+
+	// ChunkOutput moves text output larger than this many bytes to a file under LogDir; 0 disables it
+	ChunkOutput int64
+	LogDir      string
+
+	// Collect is a remote file downloaded from each instance the command succeeded on, into
+	// CollectDir/<instance-id>/; empty disables it
+	Collect    string
+	CollectDir string
+
+	// Retries re-runs a command on an instance that exited non-zero, waiting RetryDelay before each attempt
+	Retries    int
+	RetryDelay time.Duration
+
+	// GroupByTag summarizes results per value of this instance tag
+	GroupByTag string
+
+	// Production asks for confirmation before production targets are touched
+	Production *productionGuard
+
+	// Preview shows the resolved tag targets and asks before sending; nil skips it
+	Preview *targetPreview
+
+	// CountOnly resolves the targets and prints their count without sending the command
+	CountOnly bool
+	// ListTargets lists every target in the preview or the --count-only result
+	ListTargets bool
+
+	// Env holds the --env and --env-file variables exported on the instance; it is never printed
+	Env map[string]string
+	// ParamsFromSSM maps environment variable names to Parameter Store parameter names
+	ParamsFromSSM map[string]string
+	// paramEnv holds the fetched parameter values by variable name; it is never printed
+	paramEnv map[string]string
+	// linePrefixes holds the interleaved label of each instance ID, set by withLinePrefixes
+	linePrefixes map[string]string
+	// dispatch paces SendCommand calls to Rate; executeCommandParallel creates one per region
+	dispatch *dispatchLimiter
+}
+
+// envNamePattern matches names that are valid as environment variables on Linux and Windows
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+const (
+	// outputModeGrouped buffers each instance's output and prints it as one block under a header
+	outputModeGrouped = "grouped"
+	// outputModeInterleaved prints output lines as each instance completes, prefixed with the instance ID
+	outputModeInterleaved = "interleaved"
+)
+
+// resolveExecOptions reads exec options from command flags and configuration
+func resolveExecOptions(cmd *cobra.Command) (execOptions, error) {
+	sudo, _ := cmd.Flags().GetBool("sudo")
+	runAs, err := resolveRunAs(cmd, sudo)
+	if err != nil {
+		return execOptions{}, err
+	}
+	exclude, _ := cmd.Flags().GetString("exclude")
+	cancelOnTimeout, _ := cmd.Flags().GetBool("cancel-on-timeout")
+
+	platformFlag, _ := cmd.Flags().GetString("platform")
+	forcedPlatform, err := platform.ParsePlatform(platformFlag)
+	if err != nil {
+		return execOptions{}, fmt.Errorf("invalid --platform: %w", err)
+	}
+
+	outputMode, _ := cmd.Flags().GetString("output-mode")
+	switch outputMode {
+	case "":
+		outputMode = outputModeGrouped
+	case outputModeGrouped, outputModeInterleaved:
+	default:
+		return execOptions{}, fmt.Errorf("invalid --output-mode '%s' (expected %s or %s)", outputMode, outputModeGrouped, outputModeInterleaved)
+	}
+
+	outputPrefix, err := resolveOutputPrefix(cmd, outputMode)
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	output, _ := cmd.Flags().GetString("output")
+	switch output {
+	case "":
+		output = outputFormatText
+	case outputFormatText, outputFormatJSON, outputFormatYAML, outputFormatJSONL:
+	default:
+		return execOptions{}, fmt.Errorf("invalid --output '%s' (expected %s, %s, %s or %s)", output, outputFormatText, outputFormatJSON, outputFormatYAML, outputFormatJSONL)
+	}
+
+	jsonStyle, err := resolveJSONStyle(cmd, output)
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	redactor, err := newOutputRedactor()
+	if err != nil {
+		return execOptions{}, err
+	}
+	commandTemplate, err := commandWrapperTemplate()
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	outputFile, _ := cmd.Flags().GetString("output-file")
+	if err := validateOutputFile(outputFile); err != nil {
+		return execOptions{}, err
+	}
+	archiveS3, err := resolveArchiveS3(cmd)
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	templateText, _ := cmd.Flags().GetString("output-template")
+	outputTemplate, err := parseOutputTemplate(templateText)
+	if err != nil {
+		return execOptions{}, err
+	}
+	if outputTemplate != nil && output != outputFormatText {
+		return execOptions{}, fmt.Errorf("--output-template cannot be combined with --output %s", output)
+	}
+
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	if batchSize < 0 || batchSize > ssm.MaxInstancesPerCommand {
+		return execOptions{}, fmt.Errorf("invalid --batch-size %d (expected 0 to %d)", batchSize, ssm.MaxInstancesPerCommand)
+	}
+
+	minParallel := 1
+	if cmd.Flags().Lookup("min-parallel") != nil {
+		minParallel, _ = cmd.Flags().GetInt("min-parallel")
+		if minParallel < 1 {
+			return execOptions{}, fmt.Errorf("invalid --min-parallel %d (must be at least 1)", minParallel)
+		}
+	}
+
+	var rate float64
+	if cmd.Flags().Lookup("rate") != nil {
+		value, _ := cmd.Flags().GetString("rate")
+		if rate, err = parseDispatchRate(value); err != nil {
+			return execOptions{}, err
+		}
+	}
+
+	label, _ := cmd.Flags().GetString("label")
+	if err := validateLabel(label); err != nil {
+		return execOptions{}, err
+	}
+	runID, err := newRunID()
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	retries, _ := cmd.Flags().GetInt("retries")
+	retryDelay, _ := cmd.Flags().GetDuration("retry-delay")
+	if err := validateRetries(retries, retryDelay); err != nil {
+		return execOptions{}, err
+	}
+
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	batchTimeout, _ := cmd.Flags().GetDuration("batch-timeout")
+	if err := validateTimeouts(timeout, batchTimeout); err != nil {
+		return execOptions{}, err
+	}
+
+	var targetStatus string
+	if cmd.Flags().Lookup("target-status") != nil {
+		value, _ := cmd.Flags().GetString("target-status")
+		if targetStatus, err = parseTargetStatus(value); err != nil {
+			return execOptions{}, err
+		}
+	}
+
+	network, err := resolveNetworkScope(cmd)
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	var failFast *failFastThreshold
+	if cmd.Flags().Lookup("fail-fast-threshold") != nil {
+		value, _ := cmd.Flags().GetString("fail-fast-threshold")
+		if failFast, err = parseFailFastThreshold(value); err != nil {
+			return execOptions{}, err
+		}
+	}
+
+	groupByTag, _ := cmd.Flags().GetString("group-by-tag")
+
+	hideOutput, _ := cmd.Flags().GetBool("hide-output")
+	showErrors, _ := cmd.Flags().GetBool("show-errors")
+	verboseOnFailure, _ := cmd.Flags().GetBool("verbose-on-failure")
+
+	outputCompare, _ := cmd.Flags().GetBool("output-compare")
+	if outputCompare && verboseOnFailure {
+		return execOptions{}, fmt.Errorf("--output-compare cannot be combined with --verbose-on-failure")
+	}
+	if verboseOnFailure {
+		hideOutput, showErrors = true, true
+	}
+	if outputCompare && (hideOutput || outputMode == outputModeInterleaved) {
+		return execOptions{}, fmt.Errorf("--output-compare cannot be combined with --hide-output or --output-mode interleaved")
+	}
+
+	errorContext, err := resolveErrorContext(cmd)
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	chunkOutput, logDir, err := resolveChunkOutput(cmd)
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	collect, collectDir, err := resolveCollect(cmd)
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	var preview *targetPreview
+	if cmd.Flags().Lookup("no-preview") != nil {
+		preview = newTargetPreview(cmd)
+	}
+	listTargets, _ := cmd.Flags().GetBool("list-targets")
+	countOnly, _ := cmd.Flags().GetBool("count-only")
+
+	paramFlags, _ := cmd.Flags().GetStringArray("param-from-ssm")
+	paramsFromSSM, err := parseParamFromSSM(paramFlags)
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	var env map[string]string
+	if cmd.Flags().Lookup("env-file") != nil {
+		if env, err = resolveEnv(cmd); err != nil {
+			return execOptions{}, err
+		}
+		for name := range paramsFromSSM {
+			if _, exists := env[name]; exists {
+				return execOptions{}, fmt.Errorf("environment variable '%s' is set by both --param-from-ssm and --env/--env-file", name)
+			}
+		}
+	}
+
+	steps, continueOnStepError, err := resolveCommandSteps(cmd)
+	if err != nil {
+		return execOptions{}, err
+	}
+
+	noWrap, _ := cmd.Flags().GetBool("no-wrap")
+	if noWrap && (sudo || runAs != "" || len(env) > 0 || len(paramsFromSSM) > 0 || len(steps) > 0) {
+		return execOptions{}, fmt.Errorf("--no-wrap sends the command verbatim and cannot be combined with --sudo, --run-as, --env, --env-file, --param-from-ssm or --command")
+	}
+
+	return execOptions{
+		Hooks:           resolveExecHooks(cmd),
+		Sudo:            sudo,
+		RunAs:           runAs,
+		NoWrap:          noWrap,
+		Exclude:         parseExcludePatterns(exclude),
+		TargetStatus:    targetStatus,
+		Network:         network,
+		CancelOnTimeout: cancelOnTimeout,
+		Timeout:         timeout,
+		BatchTimeout:    batchTimeout,
+		Platform:        forcedPlatform,
+		OutputMode:      outputMode,
+		OutputPrefix:    outputPrefix,
+		BatchSize:       batchSize,
+		MinParallel:     minParallel,
+		Rate:            rate,
+		Label:           label,
+		RunID:           runID,
+		Output:          output,
+		OutputTemplate:  outputTemplate,
+		FailFast:        failFast,
+		OutputFile:      outputFile,
+		ArchiveS3:       archiveS3,
+		JSONStyle:       jsonStyle,
+		Redactor:        redactor,
+		HideOutput:      hideOutput,
+		ShowErrors:      showErrors,
+		OutputCompare:   outputCompare,
+		ErrorContext:    errorContext,
+		ChunkOutput:     chunkOutput,
+		LogDir:          logDir,
+		Collect:         collect,
+		CollectDir:      collectDir,
+		Retries:         retries,
+		RetryDelay:      retryDelay,
+		GroupByTag:      strings.TrimSpace(groupByTag),
+		Env:             env,
+		ParamsFromSSM:   paramsFromSSM,
+		Production:      newProductionGuard(cmd),
+		Preview:         preview,
+		CountOnly:       countOnly,
+		ListTargets:     listTargets,
+
+		Steps:               steps,
+		ContinueOnStepError: continueOnStepError,
+
+		VerboseOnFailure: verboseOnFailure,
+		CommandTemplate:  commandTemplate,
+	}, nil
+}
+
+// parseParamFromSSM parses --param-from-ssm NAME=/parameter/path values into a variable to parameter map
+func parseParamFromSSM(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	params := make(map[string]string, len(values))
+	for _, value := range values {
+		name, parameter, found := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		parameter = strings.TrimSpace(parameter)
+		if !found || parameter == "" {
+			return nil, fmt.Errorf("invalid --param-from-ssm '%s' (expected NAME=/parameter/name)", value)
+		}
+		if !envNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid environment variable name '%s' in --param-from-ssm", name)
+		}
+		if _, exists := params[name]; exists {
+			return nil, fmt.Errorf("environment variable '%s' is set more than once by --param-from-ssm", name)
+		}
+		params[name] = parameter
+	}
+	return params, nil
+}
+
+// withParameters fetches the --param-from-ssm values from Parameter Store in the given region.
+// The values are kept out of every log line and only reach the instance as environment variables.
+func (o execOptions) withParameters(ctx context.Context, ssmManager *ssm.Manager, region string) (execOptions, error) {
+	if len(o.ParamsFromSSM) == 0 {
+		return o, nil
+	}
+
+	names := make([]string, 0, len(o.ParamsFromSSM))
+	for _, parameter := range o.ParamsFromSSM {
+		names = append(names, parameter)
+	}
+	values, err := ssmManager.GetParameterStore().GetParameters(ctx, region, names)
+	if err != nil {
+		return o, fmt.Errorf("failed to read --param-from-ssm values: %w", err)
+	}
+
+	o.paramEnv = make(map[string]string, len(o.ParamsFromSSM))
+	for name, parameter := range o.ParamsFromSSM {
+		o.paramEnv[name] = values[parameter]
+	}
+	logging.LogDebug("Loaded %d parameter(s) from Parameter Store for injection", len(o.paramEnv))
+	return o, nil
+}
+
+// parseExcludePatterns splits a comma-separated --exclude value into trimmed, non-empty patterns
+func parseExcludePatterns(value string) []string {
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// excludeInstances removes instances whose ID equals a pattern or whose Name matches a pattern glob.
+// It returns the remaining instances and the number excluded.
+func excludeInstances(instances []interactive.Instance, patterns []string) ([]interactive.Instance, int) {
+	if len(patterns) == 0 {
+		return instances, 0
+	}
+
+	kept := make([]interactive.Instance, 0, len(instances))
+	for _, instance := range instances {
+		if matchesExcludePattern(instance, patterns) {
+			logging.LogDebug("Excluding instance %s (%s)", instance.InstanceID, instance.Name)
+			continue
+		}
+		kept = append(kept, instance)
+	}
+	return kept, len(instances) - len(kept)
+}
+
+// matchesExcludePattern reports whether an instance matches any exclude pattern
+func matchesExcludePattern(instance interactive.Instance, patterns []string) bool {
+	for _, pattern := range patterns {
+		if pattern == instance.InstanceID || pattern == instance.Name {
+			return true
+		}
+		if matched, err := path.Match(pattern, instance.Name); err == nil && matched && instance.Name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ssmOptions converts exec options to SSM manager execution options
+func (o execOptions) ssmOptions() ssm.ExecOptions {
+	env := o.paramEnv
+	if len(o.Env) > 0 {
+		env = make(map[string]string, len(o.Env)+len(o.paramEnv))
+		for name, value := range o.Env {
+			env[name] = value
+		}
+		for name, value := range o.paramEnv {
+			env[name] = value
+		}
+	}
+	return ssm.ExecOptions{Sudo: o.Sudo, RunAs: o.RunAs, CancelOnTimeout: o.CancelOnTimeout, Env: env, Platform: o.Platform, NoWrap: o.NoWrap,
+		Steps: o.Steps, ContinueOnStepError: o.ContinueOnStepError, Timeout: o.Timeout, CommandTemplate: o.CommandTemplate}
+}
+
+// showsOutput reports whether an instance's stdout and stderr are printed in text output.
+// Reports written with --output json/yaml or --output-file always include them.
+func (o execOptions) showsOutput(succeeded bool) bool {
+	return !o.HideOutput || (o.ShowErrors && !succeeded)
+}
+
+// printPartialOutput shows the output a timed-out command produced before ztictl stopped waiting
+func printPartialOutput(instanceID string, result *ssm.CommandResult, opts execOptions) {
+	if !result.TimedOut() {
+		return
+	}
+
+	if result.Output != "" {
+		colors.PrintHeader("Partial output before timeout:\n")
+		colors.PrintData("%s\n", opts.chunkedOutput(instanceID, "stdout", result.Output))
+	}
+	if result.ErrorOutput != "" {
+		colors.PrintHeader("Partial error output before timeout:\n")
+		colors.PrintData("%s\n", opts.errorOutput(instanceID, result.ErrorOutput, true))
+	}
+}
+
 // ParallelExecutionResult represents the result of a parallel command execution
 type ParallelExecutionResult struct {
 	Instance interactive.Instance
 	Result   *ssm.CommandResult
 	Error    error
 	Duration time.Duration
+	Attempts int // Times the command ran on the instance; more than 1 only with --retries
+
+	// Collected is the local path of the --collect file, and CollectError why it could not be downloaded
+	Collected    string
+	CollectError error
+}
+
+// succeeded reports whether the command ran and exited with status 0
+func (r ParallelExecutionResult) succeeded() bool {
+	return r.Error == nil && (r.Result.ExitCode == nil || *r.Result.ExitCode == 0)
+}
+
+// printExitStatus prints the status line of a completed command; a missing exit code counts as success
+func printExitStatus(exitCode *int32) {
+	if exitCode == nil || *exitCode == 0 {
+		colors.PrintSuccess("✓ Success (exit code: 0)\n")
+		return
+	}
+	colors.PrintError("✗ Failed (exit code: %d)\n", int(*exitCode))
+}
+
+// printSuccessLine prints a successful instance as a single status line, for --verbose-on-failure
+func printSuccessLine(result ParallelExecutionResult) {
+	line := fmt.Sprintf("✓ %s (%s): exit code 0 (%s%s)", result.Instance.Name, result.Instance.InstanceID, format.Duration(result.Duration), attemptsSuffix(result.Attempts))
+	if result.Collected != "" {
+		line += ", collected " + result.Collected
+	}
+	colors.PrintSuccess("%s\n", line)
+}
+
+// interleavedOutputMu keeps each instance's lines together when several regions stream at once
+var interleavedOutputMu sync.Mutex
+
+// printInterleavedResult prints a completed instance's output line by line, prefixed with its --output-prefix label
+func printInterleavedResult(result ParallelExecutionResult, opts execOptions) {
+	if quiet && result.succeeded() {
+		return
+	}
+
+	interleavedOutputMu.Lock()
+	defer interleavedOutputMu.Unlock()
+
+	id := result.Instance.InstanceID
+	if result.Result != nil && opts.showsOutput(result.succeeded()) {
+		prefix := opts.linePrefix(id, "")
+		for _, line := range outputLines(opts.chunkedOutput(id, "stdout", result.Result.Output)) {
+			colors.PrintData("%s%s\n", prefix, line)
+		}
+		prefix = opts.linePrefix(id, "stderr")
+		for _, line := range outputLines(opts.errorOutput(id, result.Result.ErrorOutput, !result.succeeded())) {
+			colors.PrintWarning("%s%s\n", prefix, line)
+		}
+	}
+
+	label := opts.instanceLabel(id)
+	attempts := attemptsSuffix(result.Attempts)
+	switch {
+	case result.Error != nil:
+		colors.PrintError("[%s] ✗ %v\n", label, result.Error)
+	case result.succeeded():
+		colors.PrintSuccess("[%s] ✓ exit code 0 (%s%s)\n", label, format.Duration(result.Duration), attempts)
+	default:
+		colors.PrintError("[%s] ✗ exit code %d (%s%s)\n", label, *result.Result.ExitCode, format.Duration(result.Duration), attempts)
+	}
+}
+
+// outputLines splits command output into lines, ignoring the trailing newline
+func outputLines(output string) []string {
+	output = strings.TrimRight(output, "\r\n")
+	if output == "" {
+		return nil
+	}
+	return strings.Split(output, "\n")
 }
 
 // executeCommandParallel runs commands in parallel across multiple instances
-func executeCommandParallel(ctx context.Context, ssmManager *ssm.Manager, instances []interactive.Instance, region, command string, maxParallel int) []ParallelExecutionResult {
+func executeCommandParallel(ctx context.Context, ssmManager *ssm.Manager, instances []interactive.Instance, region, command string, maxParallel int, opts execOptions) []ParallelExecutionResult {
+	maxParallel = resolveParallel(maxParallel, len(instances))
+	if opts.OutputMode == outputModeInterleaved {
+		opts = opts.withLinePrefixes(ctx, region, instances)
+	}
+	opts.dispatch = newDispatchLimiter(opts.Rate)
+	if opts.BatchSize > 0 {
+		return executeCommandBatched(ctx, ssmManager, instances, region, command, maxParallel, opts)
+	}
+
 	// Create channels for work distribution and result collection
 	instanceChan := make(chan interactive.Instance, len(instances))
 	resultChan := make(chan ParallelExecutionResult, len(instances))
@@ -115,25 +763,52 @@ func executeCommandParallel(ctx context.Context, ssmManager *ssm.Manager, instan
 	}
 	close(instanceChan)
 
-	// Start worker goroutines
+	// Start worker goroutines; the controller lowers how many run at once while SSM is throttling
+	queueCtx, failFast := newFailFastTracker(ctx, opts.FailFast, len(instances))
+	defer failFast.stop()
+	limiter := newAdaptiveConcurrency(opts.MinParallel, maxParallel)
 	var wg sync.WaitGroup
 	for i := 0; i < maxParallel; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 			for instance := range instanceChan {
+				limiter.acquire()
+
+				// Drain remaining work without starting it once the queue is cancelled
+				// (--deadline expired or --fail-fast-threshold exceeded)
+				if queueCtx.Err() != nil {
+					limiter.release(false)
+					resultChan <- ParallelExecutionResult{
+						Instance: instance,
+						Error:    notStartedError(queueCtx),
+					}
+					continue
+				}
+
 				startTime := time.Now()
 				logging.LogInfo("Executing command on instance %s (%s)", instance.InstanceID, instance.Name)
 
-				result, err := ssmManager.ExecuteCommand(ctx, instance.InstanceID, region, command, "")
-				duration := time.Since(startTime)
-
-				resultChan <- ParallelExecutionResult{
+				run := func() (*ssm.CommandResult, error) {
+					if err := opts.dispatch.wait(ctx); err != nil {
+						return nil, fmt.Errorf("not started: %w", err)
+					}
+					return ssmManager.ExecuteCommandWithOptions(ctx, instance.InstanceID, region, command, opts.commandComment(), opts.ssmOptions())
+				}
+				result, err := run()
+				result, attempts, err := retryOnExitCode(ctx, opts, instance.InstanceID, result, err, run)
+				redactResult(opts.Redactor, result)
+				execResult := ParallelExecutionResult{
 					Instance: instance,
 					Result:   result,
 					Error:    err,
-					Duration: duration,
+					Duration: time.Since(startTime),
+					Attempts: attempts,
 				}
+				collectFile(ctx, ssmManager, region, opts, &execResult)
+				limiter.release(awspkg.IsThrottlingError(err))
+
+				resultChan <- execResult
 			}
 		}()
 	}
@@ -144,17 +819,196 @@ func executeCommandParallel(ctx context.Context, ssmManager *ssm.Manager, instan
 		close(resultChan)
 	}()
 
-	// Collect all results
+	// Collect all results, streaming them in interleaved mode
 	var results []ParallelExecutionResult
 	for result := range resultChan {
+		failFast.record(result)
+		if opts.OutputMode == outputModeInterleaved {
+			printInterleavedResult(result, opts)
+		}
+		warnTruncatedOutput(result.Instance.InstanceID, result.Result, result.succeeded(), opts)
+		opts.streamParallelResult(region, result)
 		results = append(results, result)
 	}
 
+	reportThrottling(limiter)
+	return results
+}
+
+// executeCommandBatched runs a command with one SendCommand call per batch of opts.BatchSize instances,
+// processing up to maxParallel batches at a time
+func executeCommandBatched(ctx context.Context, ssmManager *ssm.Manager, instances []interactive.Instance, region, command string, maxParallel int, opts execOptions) []ParallelExecutionResult {
+	batches := chunkInstances(instances, opts.BatchSize)
+	batchChan := make(chan []interactive.Instance, len(batches))
+	for _, batch := range batches {
+		batchChan <- batch
+	}
+	close(batchChan)
+
+	resultChan := make(chan []ParallelExecutionResult, len(batches))
+	queueCtx, failFast := newFailFastTracker(ctx, opts.FailFast, len(instances))
+	defer failFast.stop()
+	limiter := newAdaptiveConcurrency(opts.MinParallel, maxParallel)
+	var wg sync.WaitGroup
+	for i := 0; i < maxParallel && i < len(batches); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batchChan {
+				limiter.acquire()
+				if queueCtx.Err() != nil {
+					limiter.release(false)
+					resultChan <- notStartedResults(batch, notStartedError(queueCtx))
+					continue
+				}
+				batchResults := executeBatch(ctx, ssmManager, batch, region, command, limiter.current(), opts)
+				for i := range batchResults {
+					collectFile(ctx, ssmManager, region, opts, &batchResults[i])
+				}
+				limiter.release(batchThrottled(batchResults))
+				resultChan <- batchResults
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	var results []ParallelExecutionResult
+	for batchResults := range resultChan {
+		for _, result := range batchResults {
+			failFast.record(result)
+			if opts.OutputMode == outputModeInterleaved {
+				printInterleavedResult(result, opts)
+			}
+			warnTruncatedOutput(result.Instance.InstanceID, result.Result, result.succeeded(), opts)
+			opts.streamParallelResult(region, result)
+			results = append(results, result)
+		}
+	}
+
+	reportThrottling(limiter)
 	return results
 }
 
+// batchThrottled reports whether SSM throttled any request made for a batch
+func batchThrottled(results []ParallelExecutionResult) bool {
+	for _, result := range results {
+		if awspkg.IsThrottlingError(result.Error) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportThrottling warns when SSM throttled part of a run so the user can lower --parallel next time
+func reportThrottling(limiter *adaptiveConcurrency) {
+	if count := limiter.throttled(); count > 0 {
+		logging.LogWarn("SSM throttled %d request(s); concurrency ended at %d (range %d-%d)", count, limiter.current(), limiter.min, limiter.max)
+	}
+}
+
+// executeBatch runs a command on one batch of instances with a single SendCommand call. If SSM rejects the
+// batch's document, its instances are sent to one by one, up to parallel at once and within --rate.
+func executeBatch(ctx context.Context, ssmManager *ssm.Manager, batch []interactive.Instance, region, command string, parallel int, opts execOptions) []ParallelExecutionResult {
+	// Leave the batch unstarted once the context is done (e.g. --deadline expired)
+	if err := ctx.Err(); err != nil {
+		return notStartedResults(batch, fmt.Errorf("not started: %w", err))
+	}
+
+	results := make([]ParallelExecutionResult, len(batch))
+	for i, instance := range batch {
+		results[i].Instance = instance
+	}
+
+	instanceIDs := make([]string, len(batch))
+	for i, instance := range batch {
+		instanceIDs[i] = instance.InstanceID
+	}
+	logging.LogInfo("Executing command on batch of %d instances", len(batch))
+
+	if err := opts.dispatch.wait(ctx); err != nil {
+		return notStartedResults(batch, fmt.Errorf("not started: %w", err))
+	}
+
+	startTime := time.Now()
+	ssmOpts := opts.ssmOptions()
+	ssmOpts.FallbackParallel = parallel
+	ssmOpts.FallbackWait = opts.dispatch.wait
+	batchResults, err := ssmManager.ExecuteCommandBatch(ctx, region, instanceIDs, command, opts.commandComment(), ssmOpts)
+	duration := time.Since(startTime)
+
+	for i := range results {
+		results[i].Duration = duration
+		results[i].Attempts = 1
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].Result = batchResults[i].Result
+		results[i].Error = batchResults[i].Err
+	}
+
+	// Retries re-run the command on each failed instance on its own, outside the batch
+	for i := range results {
+		if opts.Retries == 0 || results[i].Error != nil || !exitedNonZero(results[i].Result) {
+			continue
+		}
+		instanceID := results[i].Instance.InstanceID
+		retryStart := time.Now()
+		results[i].Result, results[i].Attempts, results[i].Error = retryOnExitCode(ctx, opts, instanceID, results[i].Result, nil, func() (*ssm.CommandResult, error) {
+			if err := opts.dispatch.wait(ctx); err != nil {
+				return nil, fmt.Errorf("not started: %w", err)
+			}
+			return ssmManager.ExecuteCommandWithOptions(ctx, instanceID, region, command, opts.commandComment(), opts.ssmOptions())
+		})
+		results[i].Duration += time.Since(retryStart)
+	}
+	for i := range results {
+		redactResult(opts.Redactor, results[i].Result)
+	}
+	return results
+}
+
+// notStartedResults returns a result carrying err for every instance of a batch that was never sent
+func notStartedResults(batch []interactive.Instance, err error) []ParallelExecutionResult {
+	results := make([]ParallelExecutionResult, len(batch))
+	for i, instance := range batch {
+		results[i] = ParallelExecutionResult{Instance: instance, Error: err}
+	}
+	return results
+}
+
+// chunkInstances splits instances into consecutive batches of at most size instances
+func chunkInstances(instances []interactive.Instance, size int) [][]interactive.Instance {
+	var batches [][]interactive.Instance
+	for start := 0; start < len(instances); start += size {
+		end := start + size
+		if end > len(instances) {
+			end = len(instances)
+		}
+		batches = append(batches, instances[start:end])
+	}
+	return batches
+}
+
+// execRegionArg picks out the region of ssm exec, the first of three arguments when the command
+// from --command-file or --command is counted; two arguments start with a region only when it is a shortcode
+func execRegionArg(cmd *cobra.Command, args []string) []string {
+	count := len(args)
+	if commandSource(cmd) != "" {
+		count++
+	}
+	if count < 3 {
+		return nil
+	}
+	return args[:1]
+}
+
 // executeCommandWithFuzzyFinder handles command execution with support for fuzzy finder and backward compatibility
-func executeCommandWithFuzzyFinder(args []string, regionFlag string) error {
+func executeCommandWithFuzzyFinder(args []string, regionFlag string, opts execOptions, execCtx *ExecutionContext) error {
 	var regionCode, instanceIdentifier, command string
 
 	// Determine which format is being used based on args
@@ -192,13 +1046,59 @@ func executeCommandWithFuzzyFinder(args []string, regionFlag string) error {
 		return fmt.Errorf("insufficient arguments provided")
 	}
 
-	return executeSingleCommand(regionCode, instanceIdentifier, command)
+	if awspkg.IsNamePattern(instanceIdentifier) {
+		return executeNamePatternCommand(regionCode, instanceIdentifier, command, opts, execCtx)
+	}
+
+	return executeSingleCommand(regionCode, instanceIdentifier, command, opts)
+}
+
+// executeNamePatternCommand fans a command out to every instance whose Name tag matches a glob pattern.
+// Confirmation is required when the pattern matches more than namePatternConfirmThreshold instances.
+func executeNamePatternCommand(regionCode, pattern, command string, opts execOptions, execCtx *ExecutionContext) error {
+	region := resolveRegion(regionCode)
+	ctx := commandContext()
+	ssmManager := ssm.NewManager(logger)
+
+	instances, err := ssmManager.ListInstances(ctx, region, &ssm.ListFilters{NamePattern: pattern})
+	if err != nil {
+		return fmt.Errorf("failed to resolve name pattern '%s': %w", pattern, err)
+	}
+
+	if len(instances) == 0 {
+		return fmt.Errorf("no instances found matching name pattern '%s' in region %s", pattern, region)
+	}
+
+	if len(instances) == 1 {
+		return executeSingleCommand(regionCode, instances[0].InstanceID, command, opts)
+	}
+
+	// The match list is still shown in quiet mode when the user is about to be asked to confirm it
+	if !quiet || len(instances) > namePatternConfirmThreshold {
+		colors.PrintHeader("Name pattern '%s' matches %d instances:\n", pattern, len(instances))
+		for _, instance := range instances {
+			colors.PrintData("  %s (%s) - %s\n", instance.Name, instance.InstanceID, instance.State)
+		}
+	}
+
+	if len(instances) > namePatternConfirmThreshold {
+		confirmed, err := confirmAction(execCtx, fmt.Sprintf("Execute command on all %d instances?", len(instances)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("execution cancelled by user")
+		}
+	}
+
+	_, err = executeOnInstances(ctx, ssmManager, region, command, instances, defaultParallel(), opts, historyOpExec)
+	return err
 }
 
 // executeSingleCommand handles single instance command execution and returns errors instead of calling os.Exit
-func executeSingleCommand(regionCode, instanceIdentifier, command string) error {
+func executeSingleCommand(regionCode, instanceIdentifier, command string, opts execOptions) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 	ssmManager := ssm.NewManager(logger)
 
 	// Use SelectInstanceWithFallback to handle both direct and fuzzy finder modes
@@ -212,38 +1112,104 @@ func executeSingleCommand(regionCode, instanceIdentifier, command string) error
 		return fmt.Errorf("instance selection failed: %w", err)
 	}
 
-	// Validate instance state before attempting execution
+	// Validate instance state before attempting execution; --target-status replaces the Online requirement
 	if err := ValidateInstanceState(ctx, ssmManager, instanceID, region, InstanceValidationRequirements{
 		AllowedStates:    []string{"running"},
-		RequireSSMOnline: true,
+		RequireSSMOnline: opts.TargetStatus == "",
 		Operation:        "execute commands",
 	}); err != nil {
 		return err
 	}
+	if opts.TargetStatus != "" {
+		if err := checkTargetStatus(ctx, ssmManager, region, instanceID, opts.TargetStatus); err != nil {
+			return err
+		}
+	}
+
+	if err := opts.Production.check(ctx, region, []string{instanceID}); err != nil {
+		return err
+	}
+
+	opts, err = opts.withParameters(ctx, ssmManager, region)
+	if err != nil {
+		return err
+	}
+
+	hookCtx := hookContext{Region: region, Command: command, TargetCount: 1}
+	if err := opts.Hooks.runPreHook(ctx, hookCtx); err != nil {
+		return err
+	}
 
 	logging.LogInfo("Executing command '%s' on instance %s in region: %s", command, instanceID, region)
+	recordHistory(historyOpExec, region, []string{instanceID}, command)
 
-	result, err := ssmManager.ExecuteCommand(ctx, instanceID, region, command, "")
+	startTime := time.Now()
+	run := func() (*ssm.CommandResult, error) {
+		return ssmManager.ExecuteCommandWithOptions(ctx, instanceID, region, command, opts.commandComment(), opts.ssmOptions())
+	}
+	result, err := run()
+	result, attempts, err := retryOnExitCode(ctx, opts, instanceID, result, err, run)
+	redactResult(opts.Redactor, result)
+	duration := time.Since(startTime)
+	collected := ParallelExecutionResult{Instance: interactive.Instance{InstanceID: instanceID}, Result: result, Error: err}
+	collectFile(ctx, ssmManager, region, opts, &collected)
+	if opts.wantsReport() {
+		entry := withCollect(opts.withSteps(opts.withAttempts(reportInstance(instanceID, "", region, result, err, duration), attempts)), collected)
+		opts.streamResult(entry)
+		report := newExecReport(command, []string{region}, startTime)
+		report.add(entry)
+		if reportErr := opts.emitReport(report); reportErr != nil {
+			logging.LogError("Failed to write report: %v", reportErr)
+		}
+	}
 	if err != nil {
 		colors.PrintError("✗ Failed to execute command on instance %s\n", instanceID)
-		return fmt.Errorf("failed to execute command: %w", err)
+		if opts.showsOutput(false) {
+			printPartialOutput(instanceID, result, opts)
+		}
+		hookCtx.FailureCount = 1
+		opts.Hooks.runPostHook(context.WithoutCancel(ctx), hookCtx)
+		// The failure is in the report already, so it is not written again as an error envelope
+		return &exitCodeError{code: 1, err: fmt.Errorf("failed to execute command: %w", err)}
 	}
 
-	colors.PrintHeader("Command executed successfully:\n")
-	colors.PrintData("%s\n", result.Output)
-	if result.ErrorOutput != "" {
-		colors.PrintHeader("Error output:\n")
-		colors.PrintData("%s\n", result.ErrorOutput)
+	failed := result.ExitCode != nil && *result.ExitCode != 0
+	if (!quiet || failed) && opts.showsOutput(!failed) {
+		colors.PrintHeader("Command executed successfully:\n")
+		colors.PrintData("%s\n", opts.chunkedOutput(instanceID, "stdout", result.Output))
+		if result.ErrorOutput != "" {
+			colors.PrintHeader("Error output:\n")
+			colors.PrintData("%s\n", opts.errorOutput(instanceID, result.ErrorOutput, failed))
+		}
+	} else if !quiet || failed {
+		printExitStatus(result.ExitCode)
+	}
+	warnTruncatedOutput(instanceID, result, !failed, opts)
+	if collected.Collected != "" && !quiet {
+		colors.PrintSuccess("✓ Collected %s -> %s\n", opts.Collect, collected.Collected)
+	}
+
+	if failed {
+		hookCtx.FailureCount = 1
+	} else {
+		hookCtx.SuccessCount = 1
 	}
+	opts.Hooks.runPostHook(context.WithoutCancel(ctx), hookCtx)
 
-	if result.ExitCode != nil && *result.ExitCode != 0 {
+	if failed {
 		logging.LogWarn("Command exited with non-zero status: %d", *result.ExitCode)
-		return fmt.Errorf("command exited with non-zero status: %d", *result.ExitCode)
+		return &exitCodeError{
+			code: remoteExitCode(*result.ExitCode),
+			err:  fmt.Errorf("command exited with non-zero status: %d", *result.ExitCode),
+		}
 	}
 
 	return nil
 }
 
+// namePatternConfirmThreshold is the number of pattern matches above which exec asks for confirmation
+const namePatternConfirmThreshold = 5
+
 const (
 	// Region shortcode length constraints
 	regionShortcodeMinLength = 3
@@ -253,6 +1219,11 @@ const (
 // isRegionShortcode checks if a string looks like a region shortcode
 // Region shortcodes are typically 3-6 characters: cac1, use1, euw1, apne1, etc.
 func isRegionShortcode(candidate string) bool {
+	// Name tag patterns (web-1*) are instance identifiers, never regions
+	if strings.ContainsAny(candidate, "*?") {
+		return false
+	}
+
 	// Check length constraints
 	if len(candidate) < regionShortcodeMinLength || len(candidate) > regionShortcodeMaxLength {
 		return false
@@ -280,14 +1251,8 @@ func validateExecTaggedArgs(tagsFlag, instancesFlag string, parallelFlag int) er
 		return fmt.Errorf("no tags or instances specified for exec-tagged command")
 	}
 
-	// Validate mutual exclusion - cannot specify both tags and instances
-	if tagsFlag != "" && instancesFlag != "" {
-		colors.PrintError("✗ Cannot specify both --tags and --instances flags\n")
-		return fmt.Errorf("both tags and instances flags provided - only one is allowed")
-	}
-
 	// Validate parallel value
-	if parallelFlag <= 0 {
+	if !validParallel(parallelFlag) {
 		colors.PrintError("✗ --parallel must be greater than 0\n")
 		return fmt.Errorf("parallel must be greater than 0")
 	}
@@ -296,57 +1261,106 @@ func validateExecTaggedArgs(tagsFlag, instancesFlag string, parallelFlag int) er
 }
 
 // executeTaggedCommand handles tagged command execution and returns success status and errors instead of calling os.Exit
-func executeTaggedCommand(regionCode, command, tagsFlag, instancesFlag string, parallelFlag int) (bool, error) {
+func executeTaggedCommand(regionCode, command, tagsFlag, instancesFlag string, parallelFlag int, opts execOptions) (bool, error) {
 	if err := validateExecTaggedArgs(tagsFlag, instancesFlag, parallelFlag); err != nil {
 		return false, err
 	}
 
 	region := resolveRegion(regionCode)
 	ssmManager := ssm.NewManager(logger)
-	ctx := context.Background()
+	ctx := commandContext()
 
-	var instances []interactive.Instance
-	var err error
+	if tagsFlag == "" {
+		// Explicitly listed instances need no preview of what a tag matched
+		opts.Preview = nil
+	}
+	instances, err := resolveExecTaggedInstances(ctx, ssmManager, region, command, tagsFlag, instancesFlag, opts)
+	if err != nil {
+		return false, err
+	}
 
-	if instancesFlag != "" {
-		// Use explicit instance IDs
-		instanceIDs := strings.Split(instancesFlag, ",")
-		for i, id := range instanceIDs {
-			instanceIDs[i] = strings.TrimSpace(id)
+	if opts.CountOnly {
+		count, err := countTargets(ctx, ssmManager, region, instances, opts)
+		if err != nil {
+			return false, err
 		}
+		return true, printTargetCount(os.Stdout, count, opts)
+	}
 
-		logging.LogInfo("Targeting %d explicit instance IDs in region: %s", len(instanceIDs), region)
-
-		// Create Instance objects from IDs (we'll resolve details during execution)
-		for _, instanceID := range instanceIDs {
-			instances = append(instances, interactive.Instance{
-				InstanceID: instanceID,
-				Name:       instanceID, // Will be resolved later if needed
-			})
+	if len(instances) == 0 {
+		if tagsFlag != "" {
+			logging.LogInfo("No instances found with tags: %s", tagsFlag)
+		} else {
+			logging.LogInfo("No instances specified")
 		}
-	} else {
+		return true, nil
+	}
+
+	return executeOnInstances(ctx, ssmManager, region, command, instances, parallelFlag, opts, historyOpExecTagged)
+}
+
+// resolveExecTaggedInstances returns the instances matching tagsFlag together with those listed in
+// instancesFlag, each with the state and SSM agent status that execution checks
+func resolveExecTaggedInstances(ctx context.Context, ssmManager *ssm.Manager, region, command, tagsFlag, instancesFlag string, opts execOptions) ([]interactive.Instance, error) {
+	var instances []interactive.Instance
+	if tagsFlag != "" {
 		// Use tag filtering
-		logging.LogInfo("Executing command '%s' on instances with tags '%s' in region: %s", command, tagsFlag, region)
+		if opts.CountOnly {
+			logging.LogInfo("Counting instances with tags '%s' in region: %s", tagsFlag, region)
+		} else {
+			logging.LogInfo("Executing command '%s' on instances with tags '%s' in region: %s", command, tagsFlag, region)
+		}
 
 		// First, list instances with the specified tags
 		filters := &ssm.ListFilters{
 			Tags: tagsFlag,
 		}
+		opts.Network.apply(filters)
 
-		instances, err = ssmManager.ListInstances(ctx, region, filters)
+		var err error
+		instances, err = instanceLookup(ctx, ssmManager, region, filters)
 		if err != nil {
 			colors.PrintError("✗ Failed to list instances in region %s\n", region)
-			return false, fmt.Errorf("failed to list instances: %w", err)
+			return nil, fmt.Errorf("failed to list instances: %w", err)
 		}
 	}
 
-	if len(instances) == 0 {
-		if instancesFlag != "" {
-			logging.LogInfo("No instances specified")
-		} else {
-			logging.LogInfo("No instances found with tags: %s", tagsFlag)
+	if instancesFlag != "" {
+		// Explicit instance IDs are added to any tag matches
+		instanceIDs := splitInstanceIDs(instancesFlag)
+		logging.LogInfo("Targeting %d explicit instance IDs in region: %s", len(instanceIDs), region)
+
+		explicit, err := resolveExplicitInstances(ctx, ssmManager, region, instanceIDs)
+		if err != nil {
+			return nil, err
+		}
+		if explicit, err = opts.Network.restrict(ctx, ssmManager, region, explicit); err != nil {
+			return nil, err
+		}
+		instances = mergeExplicitInstances(instances, explicit)
+	}
+	return instances, nil
+}
+
+// executeOnInstances runs a command in parallel on the running, SSM-online subset of instances
+// and prints per-instance results and a summary. It returns whether every execution succeeded;
+// when some failed, the error is an exitCodeError carrying the number of failures.
+func executeOnInstances(ctx context.Context, ssmManager *ssm.Manager, region, command string, instances []interactive.Instance, parallelFlag int, opts execOptions, historyOp string) (bool, error) {
+	instances, excludedCount := excludeInstances(instances, opts.Exclude)
+	if excludedCount > 0 && !quiet {
+		colors.PrintData("Excluded %d instance(s) matching --exclude\n", excludedCount)
+	}
+
+	statusFilteredCount := 0
+	if opts.TargetStatus != "" {
+		var err error
+		instances, statusFilteredCount, err = filterByTargetStatus(ctx, ssmManager, region, opts.TargetStatus, instances)
+		if err != nil {
+			return false, err
+		}
+		if statusFilteredCount > 0 && !quiet {
+			colors.PrintData("Filtered out %d instance(s) whose SSM agent is not %s (--target-status)\n", statusFilteredCount, opts.TargetStatus)
 		}
-		return true, nil
 	}
 
 	// Filter instances to only include those that are running with online SSM status
@@ -354,16 +1368,12 @@ func executeTaggedCommand(regionCode, command, tagsFlag, instancesFlag string, p
 	var skippedInstances []interactive.Instance
 
 	for _, instance := range instances {
-		if instance.State != "running" {
+		if reason := execSkipReason(instance, opts); reason != "" {
 			skippedInstances = append(skippedInstances, instance)
-			colors.PrintWarning("⚠ Skipping instance %s (%s) - not running (state: %s)\n",
-				instance.InstanceID, instance.Name, instance.State)
-			continue
-		}
-		if instance.SSMStatus != "Online" {
-			skippedInstances = append(skippedInstances, instance)
-			colors.PrintWarning("⚠ Skipping instance %s (%s) - SSM agent not online (status: %s)\n",
-				instance.InstanceID, instance.Name, instance.SSMStatus)
+			if quiet {
+				continue
+			}
+			colors.PrintWarning("⚠ Skipping instance %s (%s) - %s\n", instance.InstanceID, instance.Name, reason)
 			continue
 		}
 		validInstances = append(validInstances, instance)
@@ -372,87 +1382,353 @@ func executeTaggedCommand(regionCode, command, tagsFlag, instancesFlag string, p
 	if len(validInstances) == 0 {
 		colors.PrintError("\n✗ No instances available for command execution\n")
 		if len(skippedInstances) > 0 {
-			fmt.Printf("\nAll %d instance(s) were skipped due to state or SSM status issues.\n", len(skippedInstances))
+			colors.PrintData("\nAll %d instance(s) were skipped due to state or SSM status issues.\n", len(skippedInstances))
 			colors.PrintData("💡 Tip: Ensure instances are running and have SSM Agent Online.\n")
 		}
 		return false, fmt.Errorf("no valid instances available for execution")
 	}
 
-	if len(skippedInstances) > 0 {
-		fmt.Printf("\n")
+	if len(skippedInstances) > 0 && !quiet {
+		colors.PrintData("\n")
 		colors.PrintWarning("⚠ %d instance(s) skipped, %d instance(s) will be targeted\n",
 			len(skippedInstances), len(validInstances))
 	}
 
+	if err := opts.Preview.confirm(region, validInstances); err != nil {
+		return false, err
+	}
+
+	targetIDs := make([]string, len(validInstances))
+	for i, instance := range validInstances {
+		targetIDs[i] = instance.InstanceID
+	}
+	if err := opts.Production.check(ctx, region, targetIDs); err != nil {
+		return false, err
+	}
+
+	opts, err := opts.withParameters(ctx, ssmManager, region)
+	if err != nil {
+		return false, err
+	}
+
+	hookCtx := hookContext{Region: region, Command: command, TargetCount: len(validInstances)}
+	if err := opts.Hooks.runPreHook(ctx, hookCtx); err != nil {
+		return false, err
+	}
+
+	parallelFlag = resolveParallel(parallelFlag, len(validInstances))
 	logging.LogInfo("Executing command on %d instances with parallelism: %d", len(validInstances), parallelFlag)
+	recordHistory(historyOp, region, targetIDs, command)
 
-	// Execute commands in parallel
+	// Execute commands in parallel, within --batch-timeout when set
+	batchCtx, cancelBatch := opts.batchContext(ctx)
+	defer cancelBatch()
 	startTime := time.Now()
-	results := executeCommandParallel(ctx, ssmManager, validInstances, region, command, parallelFlag)
+	results := executeCommandParallel(batchCtx, ssmManager, validInstances, region, command, parallelFlag, opts)
 	totalDuration := time.Since(startTime)
 
+	var report *execReport
+	if opts.wantsReport() {
+		report = newExecReport(command, []string{region}, startTime)
+		report.Summary.Skipped = len(skippedInstances)
+		report.Summary.Excluded = excludedCount + statusFilteredCount
+		report.GroupByTag = opts.GroupByTag
+	}
+	groupCounts := tagGroupCounts{}
+
 	// Process and display results
 	successCount := 0
+	var completedIDs, cancelledIDs, abortedIDs []string
 	for _, result := range results {
-		fmt.Printf("\n")
+		switch {
+		case errors.Is(result.Error, errFailFastAborted):
+			abortedIDs = append(abortedIDs, result.Instance.InstanceID)
+		case result.Error != nil && isCancelled(result.Error):
+			cancelledIDs = append(cancelledIDs, result.Instance.InstanceID)
+		default:
+			completedIDs = append(completedIDs, result.Instance.InstanceID)
+		}
+
+		succeeded := result.succeeded()
+		if succeeded {
+			successCount++
+		}
+		if opts.GroupByTag != "" {
+			groupCounts.add(opts.tagGroupValue(result.Instance), succeeded)
+		}
+		if report != nil {
+			entry := reportInstance(result.Instance.InstanceID, result.Instance.Name, region, result.Result, result.Error, result.Duration)
+			entry.group = opts.tagGroupValue(result.Instance)
+			report.add(withCollect(opts.withSteps(opts.withAttempts(entry, result.Attempts)), result))
+		}
+		// Quiet mode only reports failed instances; interleaved output was already streamed, and
+		// --output-compare prints completed instances as variants after the summary
+		if (succeeded && quiet) || opts.OutputMode == outputModeInterleaved || (opts.OutputCompare && result.Error == nil) {
+			continue
+		}
+		if succeeded && opts.VerboseOnFailure {
+			printSuccessLine(result)
+			continue
+		}
+
+		colors.PrintData("\n")
 		colors.PrintHeader("=== Instance: %s (%s) ===\n", result.Instance.Name, result.Instance.InstanceID)
 		colors.PrintHeader("Command: %s\n", command)
-		colors.PrintData("Execution Time: %v\n", result.Duration.Round(time.Millisecond))
+		colors.PrintData("Execution Time: %s\n", format.Duration(result.Duration))
+		if result.Attempts > 1 {
+			colors.PrintData("Attempts: %d\n", result.Attempts)
+		}
 
 		if result.Error != nil {
 			colors.PrintError("✗ Execution failed: %v\n", result.Error)
+			if opts.showsOutput(false) {
+				printPartialOutput(result.Instance.InstanceID, result.Result, opts)
+			}
 			continue
 		}
 
-		colors.PrintHeader("Output:\n")
-		colors.PrintData("%s\n", result.Result.Output)
+		if opts.showsOutput(succeeded) {
+			colors.PrintHeader("Output:\n")
+			colors.PrintData("%s\n", opts.chunkedOutput(result.Instance.InstanceID, "stdout", result.Result.Output))
 
-		if result.Result.ErrorOutput != "" {
-			colors.PrintHeader("Error output:\n")
-			colors.PrintData("%s\n", result.Result.ErrorOutput)
+			if result.Result.ErrorOutput != "" {
+				colors.PrintHeader("Error output:\n")
+				colors.PrintData("%s\n", opts.errorOutput(result.Instance.InstanceID, result.Result.ErrorOutput, !succeeded))
+			}
 		}
 
-		if result.Result.ExitCode == nil || *result.Result.ExitCode == 0 {
-			successCount++
-			exitCode := 0
-			if result.Result.ExitCode != nil {
-				exitCode = int(*result.Result.ExitCode)
-			}
-			colors.PrintSuccess("✓ Success (exit code: %d)\n", exitCode)
-		} else {
-			colors.PrintError("✗ Failed (exit code: %d)\n", int(*result.Result.ExitCode))
+		printExitStatus(result.Result.ExitCode)
+		if result.Collected != "" {
+			colors.PrintData("Collected: %s\n", result.Collected)
 		}
 	}
 
 	// Summary
-	fmt.Printf("\n")
-	colors.PrintHeader("=== Execution Summary ===\n")
-	colors.PrintData("Total instances targeted: %d\n", len(validInstances))
-	if len(skippedInstances) > 0 {
-		colors.PrintData("Skipped (not running/no agent): %d\n", len(skippedInstances))
-	}
-	colors.PrintData("Successful: %d\n", successCount)
-	colors.PrintData("Failed: %d\n", len(validInstances)-successCount)
-	colors.PrintData("Total execution time: %v\n", totalDuration.Round(time.Millisecond))
-	colors.PrintData("Max parallelism: %d\n", parallelFlag)
-
-	if successCount < len(validInstances) {
-		logging.LogWarn("Some executions failed: %d successful, %d failed", successCount, len(validInstances)-successCount)
-		return false, nil
+	if !quiet {
+		colors.PrintData("\n")
+		colors.PrintHeader("=== Execution Summary ===\n")
+		colors.PrintData("Total instances targeted: %d\n", len(validInstances))
+		if excludedCount > 0 {
+			colors.PrintData("Excluded (--exclude): %d\n", excludedCount)
+		}
+		if statusFilteredCount > 0 {
+			colors.PrintData("Filtered (--target-status %s): %d\n", opts.TargetStatus, statusFilteredCount)
+		}
+		if len(skippedInstances) > 0 {
+			colors.PrintData("Skipped (not running/no agent): %d\n", len(skippedInstances))
+		}
+		colors.PrintData("Successful: %d\n", successCount)
+		colors.PrintData("Failed: %d\n", len(validInstances)-successCount)
+		colors.PrintData("Total execution time: %s\n", format.Duration(totalDuration))
+		colors.PrintData("Max parallelism: %d\n", parallelFlag)
+		if opts.GroupByTag != "" {
+			printTagGroupSummary(opts.GroupByTag, groupCounts)
+		}
+		printCollectSummary(opts, results)
+	}
+	if opts.OutputCompare {
+		printOutputComparison(compareOutputs(results))
+	}
+	printDeadlineReport(stopReason(batchCtx), completedIDs, cancelledIDs)
+	printFailFastAbort(opts.FailFast, len(validInstances)-successCount-len(abortedIDs), abortedIDs)
+
+	if report != nil {
+		if err := opts.emitReport(report); err != nil {
+			logging.LogError("Failed to write report: %v", err)
+		}
+	}
+
+	hookCtx.SuccessCount = successCount
+	hookCtx.FailureCount = len(validInstances) - successCount
+	// Post-hooks still run after a --deadline expiry so they can report the partial outcome
+	opts.Hooks.runPostHook(context.WithoutCancel(ctx), hookCtx)
+
+	if failures := len(validInstances) - successCount; failures > 0 {
+		logging.LogWarn("Some executions failed: %d successful, %d failed", successCount, failures)
+		return false, &exitCodeError{
+			code: failureExitCode(failures),
+			err:  fmt.Errorf("command failed on %d of %d instance(s)", failures, len(validInstances)),
+		}
 	} else {
 		logging.LogSuccess("All executions completed successfully")
 		return true, nil
 	}
 }
 
+// exitCodeError is an exec failure that maps to a specific process exit code
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string { return e.err.Error() }
+
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// maxFailureExitCode caps the failure count used as an exit code, staying below the codes shells reserve (126+)
+const maxFailureExitCode = 125
+
+// execExitCode returns the process exit code for a failed exec: the code carried by an exitCodeError, otherwise 1
+func execExitCode(err error) int {
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return 1
+}
+
+// remoteExitCode converts a remote command's exit code to a valid non-zero process exit code.
+// Codes outside 1-255 (SSM reports -1 when no code is available) become 1.
+func remoteExitCode(code int32) int {
+	if code < 1 || code > 255 {
+		return 1
+	}
+	return int(code)
+}
+
+// failureExitCode converts the number of failed instances of a multi-target run to a process exit code
+func failureExitCode(failures int) int {
+	if failures < 1 {
+		return 1
+	}
+	if failures > maxFailureExitCode {
+		return maxFailureExitCode
+	}
+	return failures
+}
+
+// addOutputModeFlag registers --output-mode with shell completion for its values
+func addOutputModeFlag(cmd *cobra.Command) {
+	cmd.Flags().String("output-mode", outputModeGrouped, "How parallel results are printed: grouped (one block per instance) or interleaved (lines prefixed with the instance ID as each finishes)")
+	_ = cmd.RegisterFlagCompletionFunc("output-mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{outputModeGrouped, outputModeInterleaved}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// addPlatformFlag registers --platform, which overrides platform auto-detection
+func addPlatformFlag(cmd *cobra.Command) {
+	cmd.Flags().String("platform", "", "Force the SSM document for linux or windows instead of detecting each instance's platform")
+	_ = cmd.RegisterFlagCompletionFunc("platform", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"linux", "windows"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// addBatchSizeFlag registers --batch-size for commands that target several instances
+func addBatchSizeFlag(cmd *cobra.Command) {
+	cmd.Flags().Int("batch-size", 0, fmt.Sprintf("Send the command to up to this many instances per SendCommand call (max %d; 0 sends one command per instance)", ssm.MaxInstancesPerCommand))
+}
+
+// addHideOutputFlags registers --hide-output, --show-errors and --verbose-on-failure
+func addHideOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("hide-output", false, "Print only the status and exit code of each instance, not its output (reports still include output)")
+	cmd.Flags().Bool("show-errors", false, "With --hide-output, still print the output of failed instances")
+	cmd.Flags().Bool("verbose-on-failure", false, "Print one status line per successful instance and the full output of failed ones")
+}
+
+// addRunAsFlag registers --run-as, which runs the command as another OS user
+func addRunAsFlag(cmd *cobra.Command) {
+	cmd.Flags().String("run-as", "", "Run the command as this OS user with their home directory on Linux (sudo -u USER -H; requires passwordless sudo; not supported on Windows)")
+}
+
+// resolveRunAs reads and validates --run-as, which cannot be combined with --sudo
+func resolveRunAs(cmd *cobra.Command, sudo bool) (string, error) {
+	if cmd.Flags().Lookup("run-as") == nil {
+		return "", nil
+	}
+	runAs, _ := cmd.Flags().GetString("run-as")
+	runAs = strings.TrimSpace(runAs)
+	if runAs == "" {
+		return "", nil
+	}
+	if sudo {
+		return "", fmt.Errorf("--run-as and --sudo cannot be combined (use --run-as root to run as root)")
+	}
+	if err := platform.ValidateUsername(runAs); err != nil {
+		return "", fmt.Errorf("invalid --run-as: %w", err)
+	}
+	return runAs, nil
+}
+
+// addNoWrapFlag registers --no-wrap, which sends the command without the platform's exec wrapper
+func addNoWrapFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool("no-wrap", false, "Send the command verbatim, without the wrapper that reports its exit code (the SSM response code is still used)")
+}
+
+// addParamFromSSMFlag registers the repeatable --param-from-ssm flag
+func addParamFromSSMFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray("param-from-ssm", nil, "Inject a Parameter Store value as an environment variable (NAME=/parameter/name, repeatable; SecureString values are decrypted)")
+}
+
 func init() {
 	// Add flags for exec command
-	ssmExecCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmExecCmd)
+	ssmExecCmd.Flags().Bool("cancel-on-timeout", false, "Cancel the command on the instance if it is still running when ztictl stops waiting")
+	ssmExecCmd.Flags().Bool("sudo", false, "Run the command with sudo on Linux (requires passwordless sudo; ignored on Windows)")
+	addRunAsFlag(ssmExecCmd)
+	addPlatformFlag(ssmExecCmd)
+	addNoWrapFlag(ssmExecCmd)
+	addOutputModeFlag(ssmExecCmd)
+	addOutputPrefixFlag(ssmExecCmd)
+	addFailFastThresholdFlag(ssmExecCmd)
+	addTargetStatusFlag(ssmExecCmd)
+	addHideOutputFlags(ssmExecCmd)
+	addOutputCompareFlag(ssmExecCmd)
+	addChunkOutputFlags(ssmExecCmd)
+	addErrorContextFlag(ssmExecCmd)
+	addCollectFlags(ssmExecCmd)
+	addRetryFlags(ssmExecCmd)
+	addTimeoutFlags(ssmExecCmd)
+	addGroupByTagFlag(ssmExecCmd)
+	addBatchSizeFlag(ssmExecCmd)
+	addRateFlag(ssmExecCmd)
+	addReportFlags(ssmExecCmd)
+	addParamFromSSMFlag(ssmExecCmd)
+	addEnvFlags(ssmExecCmd)
+	addCommandFileFlag(ssmExecCmd)
+	addCommandStepsFlags(ssmExecCmd)
+	addLabelFlag(ssmExecCmd)
+	addConfirmProductionFlag(ssmExecCmd)
+	addExecHookFlags(ssmExecCmd)
+	addIdempotencyFlags(ssmExecCmd)
 
 	// Add flags for exec-tagged command
-	ssmExecTaggedCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, separated by commas")
+	ssmExecTaggedCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, or key alone to match any value, separated by commas")
 	ssmExecTaggedCmd.Flags().StringP("instances", "i", "", "Comma-separated list of instance IDs to target explicitly")
-	ssmExecTaggedCmd.Flags().IntP("parallel", "p", runtime.NumCPU(), "Maximum number of concurrent executions")
+	addASGFlag(ssmExecTaggedCmd)
+	addParallelFlag(ssmExecTaggedCmd, "Maximum number of concurrent executions")
+	addMinParallelFlag(ssmExecTaggedCmd)
+	addRateFlag(ssmExecTaggedCmd)
+	ssmExecTaggedCmd.Flags().String("exclude", "", "Comma-separated instance IDs or Name tag globs to remove from the targets")
+	ssmExecTaggedCmd.Flags().Bool("cancel-on-timeout", false, "Cancel the command on the instance if it is still running when ztictl stops waiting")
+	ssmExecTaggedCmd.Flags().Bool("sudo", false, "Run the command with sudo on Linux (requires passwordless sudo; ignored on Windows)")
+	addRunAsFlag(ssmExecTaggedCmd)
+	addPlatformFlag(ssmExecTaggedCmd)
+	addNoWrapFlag(ssmExecTaggedCmd)
+	addOutputModeFlag(ssmExecTaggedCmd)
+	addOutputPrefixFlag(ssmExecTaggedCmd)
+	addFailFastThresholdFlag(ssmExecTaggedCmd)
+	addTargetStatusFlag(ssmExecTaggedCmd)
+	addNetworkScopeFlags(ssmExecTaggedCmd)
+	addTargetPreviewFlags(ssmExecTaggedCmd)
+	addCountOnlyFlag(ssmExecTaggedCmd)
+	addHideOutputFlags(ssmExecTaggedCmd)
+	addOutputCompareFlag(ssmExecTaggedCmd)
+	addChunkOutputFlags(ssmExecTaggedCmd)
+	addErrorContextFlag(ssmExecTaggedCmd)
+	addCollectFlags(ssmExecTaggedCmd)
+	addRetryFlags(ssmExecTaggedCmd)
+	addTimeoutFlags(ssmExecTaggedCmd)
+	addGroupByTagFlag(ssmExecTaggedCmd)
+	addBatchSizeFlag(ssmExecTaggedCmd)
+	addReportFlags(ssmExecTaggedCmd)
+	addParamFromSSMFlag(ssmExecTaggedCmd)
+	addEnvFlags(ssmExecTaggedCmd)
+	addCommandFileFlag(ssmExecTaggedCmd)
+	addCommandStepsFlags(ssmExecTaggedCmd)
+	addLabelFlag(ssmExecTaggedCmd)
+	addConfirmProductionFlag(ssmExecTaggedCmd)
+	addExecHookFlags(ssmExecTaggedCmd)
+	addIdempotencyFlags(ssmExecTaggedCmd)
 
 	// Register exec commands - this ensures they're available when ssm.go's init runs
 	// Commands will be added to ssmCmd in ssm.go's init function
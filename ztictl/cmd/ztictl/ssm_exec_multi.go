@@ -2,9 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -12,8 +12,9 @@ import (
 	"ztictl/internal/config"
 	"ztictl/internal/interactive"
 	"ztictl/internal/ssm"
-	awspkg "ztictl/pkg/aws"
 	"ztictl/pkg/colors"
+	zti_errors "ztictl/pkg/errors"
+	"ztictl/pkg/format"
 	"ztictl/pkg/logging"
 
 	"github.com/spf13/cobra"
@@ -60,18 +61,41 @@ Examples:
   ztictl ssm exec-multi --all-regions --tags App=api --parallel-regions 10 "health-check.sh"
   
   # Continue on region failures
-  ztictl ssm exec-multi --regions cac1,use1 --tags App=api --continue-on-error "health-check.sh"`,
-	Args: cobra.MinimumNArgs(1),
+  ztictl ssm exec-multi --regions cac1,use1 --tags App=api --continue-on-error "health-check.sh"
+
+  # Spare a canary and maintenance nodes (instance IDs or Name globs)
+  ztictl ssm exec-multi --all-regions --tags Environment=prod --exclude i-0canary,"maint-*" "deploy.sh"
+
+  # Stream results as each instance finishes instead of per-region blocks
+  ztictl ssm exec-multi --all-regions --tags App=api --output-mode interleaved "health-check.sh"
+
+  # Run local hooks before and after the whole run
+  ztictl ssm exec-multi --all-regions --tags App=api --pre-hook "./snapshot.sh" --post-hook "./notify.sh" "deploy.sh"
+
+  # Read a multi-line command from a file (or - for stdin)
+  ztictl ssm exec-multi cac1,use1 --tags App=api --command-file deploy-steps.sh`,
+	Args:    execArgs(0, 1),
+	PreRunE: validateRegionArgs(multiRegionArgs),
 	Run: func(cmd *cobra.Command, args []string) {
+		args, err := withCommandFile(cmd, args)
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
 		// Get flags
 		allRegions, _ := cmd.Flags().GetBool("all-regions")
 		regionsFlag, _ := cmd.Flags().GetString("regions")
 		regionGroup, _ := cmd.Flags().GetString("region-group")
 		tagsFlag, _ := cmd.Flags().GetString("tags")
 		instancesFlag, _ := cmd.Flags().GetString("instances")
-		parallelFlag, _ := cmd.Flags().GetInt("parallel")
+		parallelFlag := getParallelFlag(cmd)
 		parallelRegionsFlag, _ := cmd.Flags().GetInt("parallel-regions")
 		continueOnError, _ := cmd.Flags().GetBool("continue-on-error")
+		opts, err := resolveExecOptions(cmd)
+		if err != nil {
+			exitWithError(cmd, 1, "", err)
+		}
+		opts.applyOutputFormat()
+		opts.announceRun()
 
 		// Parse regions
 		var regions []string
@@ -170,13 +194,18 @@ Examples:
 		// Validate that we have either tags or instances specified
 		if tagsFlag == "" && instancesFlag == "" {
 			colors.PrintError("✗ Either --tags or --instances flag is required\n")
+			writeErrorEnvelope(os.Stdout, cmd, zti_errors.NewValidationError("either --tags or --instances flag is required"))
+			os.Exit(1)
+		}
+		if err := validateMultiRegionParallelism(parallelFlag, parallelRegionsFlag); err != nil {
+			colors.PrintError("✗ %v\n", err)
+			writeErrorEnvelope(os.Stdout, cmd, zti_errors.NewValidationError(err.Error()))
 			os.Exit(1)
 		}
 
 		// Execute multi-region command
-		success := executeMultiRegionCommand(regions, command, tagsFlag, instancesFlag, parallelFlag, parallelRegionsFlag, continueOnError)
-		if !success {
-			os.Exit(1)
+		if exitCode := executeMultiRegionCommand(regions, command, tagsFlag, instancesFlag, parallelFlag, parallelRegionsFlag, continueOnError, opts); exitCode != 0 {
+			os.Exit(exitCode)
 		}
 	},
 }
@@ -186,6 +215,7 @@ type MultiRegionResult struct {
 	Region     string
 	RegionName string
 	Instances  []InstanceResult
+	Excluded   int // Instances removed by --exclude
 	Error      error
 	Duration   time.Duration
 }
@@ -198,6 +228,8 @@ type InstanceResult struct {
 	ExitCode    int
 	Success     bool
 	Error       error
+	Duration    time.Duration
+	Attempts    int // Times the command ran; set only with --retries
 }
 
 // RegionExecutionRequest represents a request to execute command in a region
@@ -207,10 +239,25 @@ type RegionExecutionRequest struct {
 	TagsFlag      string
 	InstancesFlag string
 	ParallelFlag  int
+	Options       execOptions
+}
+
+// validateMultiRegionParallelism checks the per-region instance concurrency and the region concurrency
+func validateMultiRegionParallelism(parallel, parallelRegions int) error {
+	if !validParallel(parallel) {
+		return fmt.Errorf("--parallel must be greater than 0")
+	}
+	if parallelRegions <= 0 {
+		return fmt.Errorf("--parallel-regions must be greater than 0")
+	}
+	return nil
 }
 
-// executeMultiRegionCommand handles multi-region command execution with parallel processing
-func executeMultiRegionCommand(regions []string, command, tagsFlag, instancesFlag string, parallelFlag, parallelRegionsFlag int, continueOnError bool) bool {
+// executeMultiRegionCommand handles multi-region command execution with parallel processing.
+// Tag targets are resolved per region, so the pre-hook target count is only known for explicit instances.
+// It returns the process exit code: 0 on success, otherwise the number of failed instances and
+// failed regions capped at maxFailureExitCode, or 1 if the run was aborted before it started.
+func executeMultiRegionCommand(regions []string, command, tagsFlag, instancesFlag string, parallelFlag, parallelRegionsFlag int, continueOnError bool, opts execOptions) int {
 	startTime := time.Now()
 	isDebug := viper.GetBool("debug")
 
@@ -221,21 +268,44 @@ func executeMultiRegionCommand(regions []string, command, tagsFlag, instancesFla
 		regions[i] = config.NormalizeRegion(region)
 	}
 
-	colors.PrintHeader("=== MULTI-REGION EXECUTION STARTING ===\n")
-	colors.PrintData("Regions: %s\n", strings.Join(regions, ", "))
-	colors.PrintData("Command: %s\n", command)
-	if tagsFlag != "" {
-		colors.PrintData("Tags: %s\n", tagsFlag)
+	if !quiet {
+		colors.PrintHeader("=== MULTI-REGION EXECUTION STARTING ===\n")
+		colors.PrintData("Regions: %s\n", strings.Join(regions, ", "))
+		colors.PrintData("Command: %s\n", command)
+		if tagsFlag != "" {
+			colors.PrintData("Tags: %s\n", tagsFlag)
+		}
+		if instancesFlag != "" {
+			colors.PrintData("Instances: %s\n", instancesFlag)
+		}
+		colors.PrintData("Parallelism per region: %s\n", formatParallel(parallelFlag))
+		colors.PrintData("Parallel regions: %d\n", parallelRegionsFlag)
+		colors.PrintData("Continue on error: %v\n\n", continueOnError)
 	}
-	if instancesFlag != "" {
-		colors.PrintData("Instances: %s\n", instancesFlag)
+
+	hookCtx := hookContext{Region: strings.Join(regions, ","), Command: command}
+	// The tag matches are not known until each region is listed
+	if instancesFlag != "" && tagsFlag == "" {
+		hookCtx.TargetCount = len(strings.Split(instancesFlag, ",")) * len(regions)
+	}
+	if err := opts.Hooks.runPreHook(commandContext(), hookCtx); err != nil {
+		logging.LogError("Aborting multi-region execution: %v", err)
+		return 1
 	}
-	colors.PrintData("Parallelism per region: %d\n", parallelFlag)
-	colors.PrintData("Parallel regions: %d\n", parallelRegionsFlag)
-	colors.PrintData("Continue on error: %v\n\n", continueOnError)
+
+	var historyTargets []string
+	if tagsFlag != "" {
+		historyTargets = append(historyTargets, "tags:"+tagsFlag)
+	}
+	historyTargets = append(historyTargets, splitInstanceIDs(instancesFlag)...)
+	recordHistory(historyOpExecMulti, strings.Join(regions, ","), historyTargets, command)
+
+	// Regions run within --batch-timeout when set
+	batchCtx, cancelBatch := opts.batchContext(commandContext())
+	defer cancelBatch()
 
 	// Create context for cancellation
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(commandContext())
 	defer cancel()
 
 	// Create channels for worker pool pattern
@@ -250,6 +320,7 @@ func executeMultiRegionCommand(regions []string, command, tagsFlag, instancesFla
 			TagsFlag:      tagsFlag,
 			InstancesFlag: instancesFlag,
 			ParallelFlag:  parallelFlag,
+			Options:       opts,
 		}
 	}
 	close(regionChan)
@@ -276,7 +347,7 @@ func executeMultiRegionCommand(regions []string, command, tagsFlag, instancesFla
 
 					// Get full region name and description
 					fullRegion := resolveRegion(request.RegionCode)
-					regionDesc := awspkg.GetRegionDescription(request.RegionCode)
+					regionDesc := config.RegionResolver().Description(request.RegionCode)
 
 					// Only log if debug is enabled
 					if isDebug {
@@ -285,11 +356,13 @@ func executeMultiRegionCommand(regions []string, command, tagsFlag, instancesFla
 
 					// Execute command in this region
 					result := executeRegionCommandWithOutput(
+						batchCtx,
 						request.RegionCode,
 						request.Command,
 						request.TagsFlag,
 						request.InstancesFlag,
 						request.ParallelFlag,
+						request.Options,
 						isDebug,
 					)
 
@@ -330,56 +403,77 @@ func executeMultiRegionCommand(regions []string, command, tagsFlag, instancesFla
 
 	// Collect all results
 	var results []MultiRegionResult
-	overallSuccess := true
+	failedRegions := 0
 	for result := range resultChan {
 		results = append(results, result)
 
-		// Print region result with command outputs
-		printRegionResult(result)
+		// Print region result with command outputs; quiet mode only reports failures
+		if quiet {
+			printRegionFailures(result, opts)
+		} else {
+			printRegionResult(result, opts)
+		}
 
-		if result.Error != nil || hasFailedInstances(result) {
-			overallSuccess = false
+		if result.Error != nil {
+			failedRegions++
 		}
 	}
 
 	// Print multi-region summary
-	printMultiRegionSummary(results, time.Since(startTime))
+	if !quiet {
+		printMultiRegionSummary(results, time.Since(startTime))
+		if opts.GroupByTag != "" {
+			printTagGroupSummary(opts.GroupByTag, multiRegionTagGroups(results, opts))
+		}
+	}
+	if errors.Is(batchCtx.Err(), context.DeadlineExceeded) {
+		completed, cancelled := splitDeadlineTargets(regions, results)
+		printDeadlineReport(stopReason(batchCtx), completed, cancelled)
+	}
+
+	if opts.wantsReport() {
+		report := newExecReport(command, regions, startTime)
+		report.RegionDurationsMS = make(map[string]int64, len(results))
+		report.GroupByTag = opts.GroupByTag
+		for _, result := range results {
+			report.Summary.Excluded += result.Excluded
+			report.RegionDurationsMS[result.Region] = result.Duration.Milliseconds()
+			if result.Error != nil {
+				report.addRegionError(result.Region, result.Error)
+			}
+			for _, inst := range result.Instances {
+				entry := opts.withSteps(multiRegionReportInstance(result.Region, inst))
+				entry.group = opts.tagGroupValue(inst.Instance)
+				report.add(entry)
+			}
+		}
+		if err := opts.emitReport(report); err != nil {
+			logging.LogError("Failed to write report: %v", err)
+		}
+	}
 
-	return overallSuccess
+	hookCtx.TargetCount, hookCtx.SuccessCount, hookCtx.FailureCount = countInstanceResults(results)
+	opts.Hooks.runPostHook(context.WithoutCancel(commandContext()), hookCtx)
+
+	if failures := hookCtx.FailureCount + failedRegions; failures > 0 {
+		return failureExitCode(failures)
+	}
+	return 0
 }
 
 // executeRegionCommandWithOutput executes command in a single region and returns detailed results
-func executeRegionCommandWithOutput(regionCode, command, tagsFlag, instancesFlag string, parallelFlag int, isDebug bool) MultiRegionResult {
+func executeRegionCommandWithOutput(ctx context.Context, regionCode, command, tagsFlag, instancesFlag string, parallelFlag int, opts execOptions, isDebug bool) MultiRegionResult {
 	result := MultiRegionResult{
 		Region: regionCode,
 	}
 
 	region := resolveRegion(regionCode)
 	ssmManager := ssm.NewManager(logger)
-	ctx := context.Background()
 
 	var instances []interactive.Instance
 	var err error
 
-	if instancesFlag != "" {
-		// Use explicit instance IDs
-		instanceIDs := strings.Split(instancesFlag, ",")
-		for i, id := range instanceIDs {
-			instanceIDs[i] = strings.TrimSpace(id)
-		}
-
-		if isDebug {
-			logging.LogInfo("Targeting %d explicit instance IDs in region: %s", len(instanceIDs), region)
-		}
-
-		// Create Instance objects from IDs
-		for _, instanceID := range instanceIDs {
-			instances = append(instances, interactive.Instance{
-				InstanceID: instanceID,
-				Name:       instanceID,
-			})
-		}
-	} else {
+	if tagsFlag != "" {
 		// Use tag filtering
 		if isDebug {
 			logging.LogInfo("Finding instances with tags '%s' in region: %s", tagsFlag, region)
@@ -389,14 +483,35 @@ func executeRegionCommandWithOutput(regionCode, command, tagsFlag, instancesFlag
 		filters := &ssm.ListFilters{
 			Tags: tagsFlag,
 		}
+		opts.Network.apply(filters)
 
-		instances, err = ssmManager.ListInstances(ctx, region, filters)
+		instances, err = instanceLookup(ctx, ssmManager, region, filters)
 		if err != nil {
 			result.Error = fmt.Errorf("failed to list instances: %w", err)
 			return result
 		}
 	}
 
+	if instancesFlag != "" {
+		// Explicit instance IDs, added to any tag matches
+		instanceIDs := splitInstanceIDs(instancesFlag)
+		if isDebug {
+			logging.LogInfo("Targeting %d explicit instance IDs in region: %s", len(instanceIDs), region)
+		}
+
+		explicit, err := resolveExplicitInstances(ctx, ssmManager, region, instanceIDs)
+		if err == nil {
+			explicit, err = opts.Network.restrict(ctx, ssmManager, region, explicit)
+		}
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		instances = mergeExplicitInstances(instances, explicit)
+	}
+
+	instances, result.Excluded = excludeInstances(instances, opts.Exclude)
+
 	if len(instances) == 0 {
 		if isDebug {
 			logging.LogInfo("No instances found in region %s", region)
@@ -404,21 +519,44 @@ func executeRegionCommandWithOutput(regionCode, command, tagsFlag, instancesFlag
 		return result
 	}
 
+	instanceIDs := make([]string, len(instances))
+	for i, instance := range instances {
+		instanceIDs[i] = instance.InstanceID
+	}
+	if err := opts.Production.check(ctx, region, instanceIDs); err != nil {
+		result.Error = err
+		return result
+	}
+
 	if isDebug {
 		logging.LogInfo("Executing command on %d instances in region %s", len(instances), region)
 	}
 
+	opts, err = opts.withParameters(ctx, ssmManager, region)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
 	// Execute commands in parallel using existing function
-	execResults := executeCommandParallel(ctx, ssmManager, instances, region, command, parallelFlag)
+	execResults := executeCommandParallel(ctx, ssmManager, instances, region, command, parallelFlag, opts)
 
 	// Convert results to our format
 	for _, execResult := range execResults {
 		instResult := InstanceResult{
 			Instance: execResult.Instance,
+			Duration: execResult.Duration,
+		}
+		if opts.Retries > 0 {
+			instResult.Attempts = execResult.Attempts
 		}
 
 		if execResult.Error != nil {
 			instResult.Error = execResult.Error
+			if execResult.Result.TimedOut() {
+				instResult.Output = execResult.Result.Output
+				instResult.ErrorOutput = execResult.Result.ErrorOutput
+			}
 		} else if execResult.Result != nil {
 			instResult.Output = execResult.Result.Output
 			instResult.ErrorOutput = execResult.Result.ErrorOutput
@@ -436,9 +574,10 @@ func executeRegionCommandWithOutput(regionCode, command, tagsFlag, instancesFlag
 	return result
 }
 
-// printRegionResult prints the result for a single region with command outputs
-func printRegionResult(result MultiRegionResult) {
-	fmt.Printf("\n")
+// printRegionResult prints the result for a single region, with command outputs unless they were
+// already streamed or hidden by --hide-output
+func printRegionResult(result MultiRegionResult, opts execOptions) {
+	colors.PrintData("\n")
 	colors.PrintHeader("=== REGION: %s (%s) ===\n", result.Region, result.RegionName)
 
 	if result.Error != nil {
@@ -453,38 +592,53 @@ func printRegionResult(result MultiRegionResult) {
 
 	// Print results for each instance
 	for _, inst := range result.Instances {
-		fmt.Printf("\n")
+		showOutput := opts.OutputMode != outputModeInterleaved && opts.showsOutput(inst.Success && inst.Error == nil)
+		colors.PrintData("\n")
 		if inst.Error != nil {
 			colors.PrintError("✗ %s (%s): %v\n", inst.Instance.Name, inst.Instance.InstanceID, inst.Error)
+
+			// Timed-out commands keep the output produced before the timeout
+			if showOutput && inst.Output != "" {
+				colors.PrintHeader("Partial output before timeout:\n")
+				colors.PrintData("%s\n", opts.chunkedOutput(inst.Instance.InstanceID, "stdout", inst.Output))
+			}
+			if showOutput && inst.ErrorOutput != "" {
+				colors.PrintHeader("Partial error output before timeout:\n")
+				colors.PrintData("%s\n", opts.errorOutput(inst.Instance.InstanceID, inst.ErrorOutput, true))
+			}
 		} else if inst.Success {
-			colors.PrintSuccess("✓ %s (%s): success (exit code: %d)\n", inst.Instance.Name, inst.Instance.InstanceID, inst.ExitCode)
+			colors.PrintSuccess("✓ %s (%s): success (exit code: %d%s)\n", inst.Instance.Name, inst.Instance.InstanceID, inst.ExitCode, attemptsSuffix(inst.Attempts))
 
 			// Show command output
-			if inst.Output != "" {
+			if showOutput && inst.Output != "" {
 				colors.PrintHeader("Output:\n")
-				colors.PrintData("%s\n", inst.Output)
+				colors.PrintData("%s\n", opts.chunkedOutput(inst.Instance.InstanceID, "stdout", inst.Output))
 			}
 
-			if inst.ErrorOutput != "" {
+			if showOutput && inst.ErrorOutput != "" {
 				colors.PrintHeader("Error Output:\n")
-				colors.PrintData("%s\n", inst.ErrorOutput)
+				colors.PrintData("%s\n", opts.errorOutput(inst.Instance.InstanceID, inst.ErrorOutput, false))
 			}
 		} else {
-			colors.PrintError("✗ %s (%s): failed (exit code: %d)\n", inst.Instance.Name, inst.Instance.InstanceID, inst.ExitCode)
+			colors.PrintError("✗ %s (%s): failed (exit code: %d%s)\n", inst.Instance.Name, inst.Instance.InstanceID, inst.ExitCode, attemptsSuffix(inst.Attempts))
 
 			// Show error output for failed commands
-			if inst.ErrorOutput != "" {
+			if showOutput && inst.ErrorOutput != "" {
 				colors.PrintHeader("Error Output:\n")
-				colors.PrintData("%s\n", inst.ErrorOutput)
+				colors.PrintData("%s\n", opts.errorOutput(inst.Instance.InstanceID, inst.ErrorOutput, true))
 			}
 
-			if inst.Output != "" {
+			if showOutput && inst.Output != "" {
 				colors.PrintHeader("Output:\n")
-				colors.PrintData("%s\n", inst.Output)
+				colors.PrintData("%s\n", opts.chunkedOutput(inst.Instance.InstanceID, "stdout", inst.Output))
 			}
 		}
 	}
 
+	if quiet {
+		return
+	}
+
 	// Region summary
 	successful := 0
 	failed := 0
@@ -496,9 +650,103 @@ func printRegionResult(result MultiRegionResult) {
 		}
 	}
 
-	fmt.Printf("\n")
+	colors.PrintData("\n")
 	colors.PrintData("Region Summary: %d/%d successful\n", successful, len(result.Instances))
-	colors.PrintData("Duration: %v\n", result.Duration.Round(time.Millisecond))
+	colors.PrintData("Duration: %s\n", format.Duration(result.Duration))
+}
+
+// printRegionFailures prints only the failed instances of a region, for --quiet runs
+func printRegionFailures(result MultiRegionResult, opts execOptions) {
+	if result.Error == nil && !hasFailedInstances(result) {
+		return
+	}
+
+	failures := result
+	failures.Instances = nil
+	for _, inst := range result.Instances {
+		if !inst.Success || inst.Error != nil {
+			failures.Instances = append(failures.Instances, inst)
+		}
+	}
+	printRegionResult(failures, opts)
+}
+
+// countInstanceResults returns the total, successful and failed instance counts across regions
+func countInstanceResults(results []MultiRegionResult) (total, successful, failed int) {
+	for _, result := range results {
+		for _, inst := range result.Instances {
+			total++
+			if inst.Success && inst.Error == nil {
+				successful++
+			} else {
+				failed++
+			}
+		}
+	}
+	return total, successful, failed
+}
+
+// splitDeadlineTargets separates completed targets from those cancelled by the deadline.
+// Regions that never produced a result are reported as cancelled.
+func splitDeadlineTargets(regions []string, results []MultiRegionResult) (completed, cancelled []string) {
+	processed := make(map[string]bool, len(results))
+	for _, result := range results {
+		processed[result.Region] = true
+		if result.Error != nil && isCancelled(result.Error) {
+			cancelled = append(cancelled, result.Region)
+			continue
+		}
+		for _, inst := range result.Instances {
+			target := result.Region + "/" + inst.Instance.InstanceID
+			if inst.Error != nil && isCancelled(inst.Error) {
+				cancelled = append(cancelled, target)
+			} else {
+				completed = append(completed, target)
+			}
+		}
+	}
+
+	for _, region := range regions {
+		if !processed[region] {
+			cancelled = append(cancelled, region+" (not started)")
+		}
+	}
+
+	return completed, cancelled
+}
+
+// multiRegionReportInstance converts a multi-region instance result to a report entry
+func multiRegionReportInstance(region string, inst InstanceResult) execReportInstance {
+	entry := execReportInstance{
+		InstanceID:  inst.Instance.InstanceID,
+		Name:        inst.Instance.Name,
+		Region:      region,
+		Status:      reportStatusSuccess,
+		Output:      inst.Output,
+		ErrorOutput: inst.ErrorOutput,
+		DurationMS:  inst.Duration.Milliseconds(),
+		Attempts:    inst.Attempts,
+	}
+	if inst.Error != nil {
+		entry.Status = reportStatusError
+		entry.Error = inst.Error.Error()
+	} else if !inst.Success {
+		entry.Status = reportStatusFailed
+		exitCode := int32(inst.ExitCode)
+		entry.ExitCode = &exitCode
+	}
+	return entry
+}
+
+// multiRegionTagGroups tallies instance outcomes across all regions per --group-by-tag value
+func multiRegionTagGroups(results []MultiRegionResult, opts execOptions) tagGroupCounts {
+	counts := tagGroupCounts{}
+	for _, result := range results {
+		for _, inst := range result.Instances {
+			counts.add(opts.tagGroupValue(inst.Instance), inst.Success && inst.Error == nil)
+		}
+	}
+	return counts
 }
 
 // hasFailedInstances checks if any instances in the result failed
@@ -513,7 +761,7 @@ func hasFailedInstances(result MultiRegionResult) bool {
 
 // printMultiRegionSummary prints the final summary of multi-region execution
 func printMultiRegionSummary(results []MultiRegionResult, totalDuration time.Duration) {
-	fmt.Printf("\n")
+	colors.PrintData("\n")
 	colors.PrintHeader("=== MULTI-REGION SUMMARY ===\n")
 
 	totalRegions := len(results)
@@ -521,8 +769,11 @@ func printMultiRegionSummary(results []MultiRegionResult, totalDuration time.Dur
 	totalInstances := 0
 	totalSuccessful := 0
 	totalFailed := 0
+	totalExcluded := 0
 
 	for _, result := range results {
+		totalExcluded += result.Excluded
+
 		instanceCount := len(result.Instances)
 		successful := 0
 		failed := 0
@@ -548,19 +799,26 @@ func printMultiRegionSummary(results []MultiRegionResult, totalDuration time.Dur
 		if result.Error != nil || failed > 0 {
 			status = "✗"
 		}
-		colors.PrintData("%s %s (%s): %d instances, %d successful, %d failed [%v]\n",
+		colors.PrintData("%s %s (%s): %d instances, %d successful, %d failed [%s]\n",
 			status, result.Region, result.RegionName,
 			instanceCount, successful, failed,
-			result.Duration.Round(time.Millisecond))
+			format.Duration(result.Duration))
 	}
 
-	fmt.Printf("\n")
+	colors.PrintData("\n")
 	colors.PrintData("Regions processed: %d\n", totalRegions)
 	colors.PrintData("Regions successful: %d\n", successfulRegions)
 	colors.PrintData("Total instances: %d\n", totalInstances)
+	if totalExcluded > 0 {
+		colors.PrintData("Total excluded (--exclude): %d\n", totalExcluded)
+	}
 	colors.PrintData("Total successful: %d\n", totalSuccessful)
 	colors.PrintData("Total failed: %d\n", totalFailed)
-	colors.PrintData("Total execution time: %v\n", totalDuration.Round(time.Millisecond))
+	colors.PrintData("Total execution time: %s\n", format.Duration(totalDuration))
+	if cumulative, slowest := regionTimings(results); slowest != nil {
+		colors.PrintData("Cumulative region time: %s\n", format.Duration(cumulative))
+		colors.PrintData("Slowest region: %s (%s)\n", slowest.Region, format.Duration(slowest.Duration))
+	}
 
 	if totalFailed > 0 {
 		colors.PrintError("\n✗ Multi-region execution completed with failures\n")
@@ -571,10 +829,49 @@ func printMultiRegionSummary(results []MultiRegionResult, totalDuration time.Dur
 	}
 }
 
+// regionTimings returns the summed duration of all regions and the slowest region, or nil if there are none.
+// Comparing the sum with the wall-clock time shows how much --parallel-regions saved.
+func regionTimings(results []MultiRegionResult) (time.Duration, *MultiRegionResult) {
+	var cumulative time.Duration
+	var slowest *MultiRegionResult
+	for i := range results {
+		cumulative += results[i].Duration
+		if slowest == nil || results[i].Duration > slowest.Duration {
+			slowest = &results[i]
+		}
+	}
+	return cumulative, slowest
+}
+
+// multiRegionArgs picks out the positional region list, which is only read when --regions,
+// --all-regions and --region-group are not given and the first argument looks like regions
+func multiRegionArgs(cmd *cobra.Command, args []string) []string {
+	regionsFlag, _ := cmd.Flags().GetString("regions")
+	allRegions, _ := cmd.Flags().GetBool("all-regions")
+	regionGroup, _ := cmd.Flags().GetString("region-group")
+	if regionsFlag != "" || allRegions || regionGroup != "" {
+		return nil
+	}
+	count := len(args)
+	if commandSource(cmd) != "" {
+		count++
+	}
+	if count < 2 || (!strings.Contains(args[0], ",") && !looksLikeRegion(args[0])) {
+		return nil
+	}
+	var regions []string
+	for _, r := range strings.Split(args[0], ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			regions = append(regions, r)
+		}
+	}
+	return regions
+}
+
 // looksLikeRegion checks if a string looks like a region code or name
 func looksLikeRegion(s string) bool {
-	// Check if it's a known shortcode
-	if _, exists := awspkg.RegionMapping[s]; exists {
+	// Check if it's a known built-in or custom shortcode
+	if config.RegionResolver().IsShortcode(s) {
 		return true
 	}
 
@@ -592,9 +889,35 @@ func init() {
 	ssmExecMultiCmd.Flags().StringP("regions", "r", "", "Override regions (comma-separated, supports shortcodes and full names)")
 	ssmExecMultiCmd.Flags().BoolP("all-regions", "a", false, "Execute across all configured regions from ~/.ztictl.yaml")
 	ssmExecMultiCmd.Flags().String("region-group", "", "Use predefined region group from config")
-	ssmExecMultiCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, separated by commas")
+	ssmExecMultiCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, or key alone to match any value, separated by commas")
 	ssmExecMultiCmd.Flags().StringP("instances", "i", "", "Comma-separated list of instance IDs to target")
-	ssmExecMultiCmd.Flags().IntP("parallel", "p", runtime.NumCPU(), "Maximum number of concurrent executions per region")
+	addParallelFlag(ssmExecMultiCmd, "Maximum number of concurrent executions per region")
+	addMinParallelFlag(ssmExecMultiCmd)
+	addRateFlag(ssmExecMultiCmd)
 	ssmExecMultiCmd.Flags().IntP("parallel-regions", "P", DefaultRegionParallelism, "Maximum number of regions to process in parallel")
 	ssmExecMultiCmd.Flags().BoolP("continue-on-error", "c", false, "Continue execution even if a region fails")
+	ssmExecMultiCmd.Flags().String("exclude", "", "Comma-separated instance IDs or Name tag globs to remove from the targets")
+	ssmExecMultiCmd.Flags().Bool("cancel-on-timeout", false, "Cancel the command on the instance if it is still running when ztictl stops waiting")
+	ssmExecMultiCmd.Flags().Bool("sudo", false, "Run the command with sudo on Linux (requires passwordless sudo; ignored on Windows)")
+	addRunAsFlag(ssmExecMultiCmd)
+	addPlatformFlag(ssmExecMultiCmd)
+	addNoWrapFlag(ssmExecMultiCmd)
+	addOutputModeFlag(ssmExecMultiCmd)
+	addOutputPrefixFlag(ssmExecMultiCmd)
+	addNetworkScopeFlags(ssmExecMultiCmd)
+	addHideOutputFlags(ssmExecMultiCmd)
+	addChunkOutputFlags(ssmExecMultiCmd)
+	addErrorContextFlag(ssmExecMultiCmd)
+	addRetryFlags(ssmExecMultiCmd)
+	addTimeoutFlags(ssmExecMultiCmd)
+	addGroupByTagFlag(ssmExecMultiCmd)
+	addBatchSizeFlag(ssmExecMultiCmd)
+	addReportFlags(ssmExecMultiCmd)
+	addParamFromSSMFlag(ssmExecMultiCmd)
+	addEnvFlags(ssmExecMultiCmd)
+	addCommandFileFlag(ssmExecMultiCmd)
+	addCommandStepsFlags(ssmExecMultiCmd)
+	addLabelFlag(ssmExecMultiCmd)
+	addConfirmProductionFlag(ssmExecMultiCmd)
+	addExecHookFlags(ssmExecMultiCmd)
 }
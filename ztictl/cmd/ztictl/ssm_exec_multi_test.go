@@ -1,7 +1,9 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -9,6 +11,7 @@ import (
 	"ztictl/internal/interactive"
 	awspkg "ztictl/pkg/aws"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 )
@@ -720,3 +723,69 @@ func TestInstanceListProcessing(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintRegionFailuresQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	quiet = true
+	defer func() {
+		color.Output = os.Stdout
+		quiet = false
+	}()
+
+	healthy := MultiRegionResult{
+		Region: "cac1",
+		Instances: []InstanceResult{
+			{Instance: interactive.Instance{InstanceID: "i-ok", Name: "web-1"}, Output: "up 3 days", Success: true},
+		},
+	}
+	printRegionFailures(healthy, execOptions{})
+	assert.Empty(t, buf.String(), "Regions without failures should print nothing in quiet mode")
+
+	mixed := MultiRegionResult{
+		Region: "use1",
+		Instances: []InstanceResult{
+			{Instance: interactive.Instance{InstanceID: "i-ok", Name: "web-1"}, Output: "all good", Success: true},
+			{Instance: interactive.Instance{InstanceID: "i-bad", Name: "web-2"}, ErrorOutput: "disk full", ExitCode: 1},
+		},
+	}
+	printRegionFailures(mixed, execOptions{})
+
+	output := buf.String()
+	assert.Contains(t, output, "i-bad")
+	assert.Contains(t, output, "disk full")
+	assert.NotContains(t, output, "i-ok")
+	assert.NotContains(t, output, "Region Summary")
+}
+
+func TestValidateMultiRegionParallelism(t *testing.T) {
+	assert.NoError(t, validateMultiRegionParallelism(8, 2))
+	assert.ErrorContains(t, validateMultiRegionParallelism(0, 2), "--parallel must")
+	assert.ErrorContains(t, validateMultiRegionParallelism(8, 0), "--parallel-regions must")
+}
+
+func TestMultiRegionSummaryTimings(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	results := []MultiRegionResult{
+		{Region: "cac1", Duration: 2 * time.Second, Instances: []InstanceResult{{Success: true}}},
+		{Region: "use1", Duration: 5 * time.Second, Instances: []InstanceResult{{Success: true}}},
+		{Region: "euw1", Duration: 3 * time.Second},
+	}
+
+	cumulative, slowest := regionTimings(results)
+	assert.Equal(t, 10*time.Second, cumulative)
+	assert.Equal(t, "use1", slowest.Region)
+
+	_, none := regionTimings(nil)
+	assert.Nil(t, none)
+
+	printMultiRegionSummary(results, 6*time.Second)
+	output := buf.String()
+	assert.Contains(t, output, "use1 (): 1 instances, 1 successful, 0 failed [5s]")
+	assert.Contains(t, output, "Total execution time: 6s")
+	assert.Contains(t, output, "Cumulative region time: 10s")
+	assert.Contains(t, output, "Slowest region: use1 (5s)")
+}
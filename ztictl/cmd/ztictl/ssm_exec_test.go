@@ -10,8 +10,12 @@ import (
 	"testing"
 	"time"
 
+	"ztictl/internal/interactive"
+	"ztictl/internal/platform"
+	"ztictl/internal/ssm"
 	"ztictl/pkg/logging"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -1482,7 +1486,7 @@ func TestExecuteSingleCommand(t *testing.T) {
 		}
 
 		// The function should return an error or succeed, not call os.Exit
-		err := executeSingleCommand("use1", "i-test123", "echo hello")
+		err := executeSingleCommand("use1", "i-test123", "echo hello", execOptions{})
 
 		// We expect this might fail (no AWS credentials/connection), but it shouldn't panic
 		// The important thing is that it returns an error instead of calling os.Exit
@@ -1500,7 +1504,7 @@ func TestExecuteSingleCommand(t *testing.T) {
 		}
 
 		// Test with empty region code (should be handled gracefully)
-		err := executeSingleCommand("", "i-test123", "echo hello")
+		err := executeSingleCommand("", "i-test123", "echo hello", execOptions{})
 
 		// Function should handle this gracefully and return error
 		if err != nil {
@@ -1516,7 +1520,7 @@ func TestExecuteSingleCommand(t *testing.T) {
 		}
 
 		// Test with empty instance identifier
-		err := executeSingleCommand("use1", "", "echo hello")
+		err := executeSingleCommand("use1", "", "echo hello", execOptions{})
 
 		// Function should handle this gracefully
 		if err != nil {
@@ -1541,16 +1545,11 @@ func TestValidateExecTaggedArgs(t *testing.T) {
 		}
 	})
 
-	t.Run("rejects both tags and instances", func(t *testing.T) {
+	t.Run("accepts tags combined with instances", func(t *testing.T) {
 		err := validateExecTaggedArgs("Environment=Production", "i-123,i-456", 4)
 
-		if err == nil {
-			t.Error("Expected error when both tags and instances provided")
-		}
-
-		expectedMsg := "both tags and instances flags provided"
-		if err != nil && !strings.Contains(err.Error(), expectedMsg) {
-			t.Errorf("Expected error message to contain '%s', got: %v", expectedMsg, err)
+		if err != nil {
+			t.Errorf("Expected tags and instances to be combined, got: %v", err)
 		}
 	})
 
@@ -1613,7 +1612,7 @@ func TestExecuteTaggedCommand(t *testing.T) {
 		}
 
 		// The function should return success status and error, not call os.Exit
-		success, err := executeTaggedCommand("use1", "echo hello", "Environment=Production", "", 2)
+		success, err := executeTaggedCommand("use1", "echo hello", "Environment=Production", "", 2, execOptions{})
 
 		// We expect this might fail (no AWS credentials/connection), but it shouldn't panic
 		// The important thing is that it returns results instead of calling os.Exit
@@ -1633,7 +1632,7 @@ func TestExecuteTaggedCommand(t *testing.T) {
 		}
 
 		// Test invalid arguments (no tags or instances)
-		success, err := executeTaggedCommand("use1", "echo hello", "", "", 2)
+		success, err := executeTaggedCommand("use1", "echo hello", "", "", 2, execOptions{})
 
 		// Should get validation error
 		if err == nil {
@@ -1650,36 +1649,13 @@ func TestExecuteTaggedCommand(t *testing.T) {
 		}
 	})
 
-	t.Run("handles mutual exclusion validation", func(t *testing.T) {
-		if logger == nil {
-			logger = logging.NewLogger(false)
-		}
-
-		// Test both tags and instances provided
-		success, err := executeTaggedCommand("use1", "echo hello", "Environment=Production", "i-123,i-456", 2)
-
-		// Should get validation error
-		if err == nil {
-			t.Error("Expected validation error for both tags and instances")
-		}
-
-		if success {
-			t.Error("Expected success=false for validation error")
-		}
-
-		expectedMsg := "both tags and instances flags provided"
-		if err != nil && !strings.Contains(err.Error(), expectedMsg) {
-			t.Errorf("Expected error message to contain '%s', got: %v", expectedMsg, err)
-		}
-	})
-
 	t.Run("handles parallel validation", func(t *testing.T) {
 		if logger == nil {
 			logger = logging.NewLogger(false)
 		}
 
 		// Test invalid parallel value
-		success, err := executeTaggedCommand("use1", "echo hello", "Environment=Production", "", 0)
+		success, err := executeTaggedCommand("use1", "echo hello", "Environment=Production", "", 0, execOptions{})
 
 		// Should get validation error
 		if err == nil {
@@ -1702,7 +1678,7 @@ func TestExecuteTaggedCommand(t *testing.T) {
 		}
 
 		// Test instances flag with comma-separated values
-		success, err := executeTaggedCommand("use1", "echo hello", "", "i-123, i-456, i-789", 2)
+		success, err := executeTaggedCommand("use1", "echo hello", "", "i-123, i-456, i-789", 2, execOptions{})
 
 		// We expect this might fail with AWS connection issues, but it should parse instances
 		// and not fail with validation errors
@@ -1757,7 +1733,7 @@ func TestExecCommandSeparationOfConcerns(t *testing.T) {
 		done := make(chan error, 1)
 		go func() {
 			// This call should return an error or succeed, not exit the process
-			err := executeSingleCommand("invalid-region", "invalid-instance", "test command")
+			err := executeSingleCommand("invalid-region", "invalid-instance", "test command", execOptions{})
 			done <- err
 		}()
 
@@ -1795,7 +1771,7 @@ func TestExecCommandSeparationOfConcerns(t *testing.T) {
 		done := make(chan result, 1)
 		go func() {
 			// This call should return results, not exit the process
-			success, err := executeTaggedCommand("invalid-region", "test command", "InvalidTag=Value", "", 1)
+			success, err := executeTaggedCommand("invalid-region", "test command", "InvalidTag=Value", "", 1, execOptions{})
 			done <- result{success: success, err: err}
 		}()
 
@@ -1815,3 +1791,459 @@ func TestExecCommandSeparationOfConcerns(t *testing.T) {
 		t.Log("Test completed - function returned instead of calling os.Exit")
 	})
 }
+
+func TestIsRegionShortcodeRejectsNamePatterns(t *testing.T) {
+	for _, candidate := range []string{"web-1*", "db?1", "app1*"} {
+		if isRegionShortcode(candidate) {
+			t.Errorf("isRegionShortcode(%q) = true; name patterns must not be treated as regions", candidate)
+		}
+	}
+	if !isRegionShortcode("cac1") {
+		t.Error("isRegionShortcode(\"cac1\") = false; expected true")
+	}
+}
+
+func TestExecuteNamePatternCommand(t *testing.T) {
+	originalLogger := logger
+	defer func() { logger = originalLogger }()
+	if logger == nil {
+		logger = logging.NewLogger(false)
+	}
+
+	// Without AWS access the pattern cannot be resolved, but the function must return an error rather than exit
+	err := executeNamePatternCommand("use1", "web-*", "uptime", execOptions{}, &ExecutionContext{NonInteractive: true})
+	if err == nil {
+		t.Log("Name pattern execution unexpectedly succeeded (AWS access available)")
+	} else {
+		t.Logf("Name pattern execution error (may be expected): %v", err)
+	}
+}
+
+func TestResolveExecOptionsSudo(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("sudo") == nil {
+			t.Errorf("Expected --sudo flag on %s", cmd.Name())
+		}
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().Bool("sudo", false, "")
+	addExecHookFlags(cmd)
+	if err := cmd.Flags().Set("sudo", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := resolveExecOptions(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opts.Sudo || !opts.ssmOptions().Sudo {
+		t.Errorf("Expected sudo to be enabled, got %+v", opts)
+	}
+}
+
+func TestResolveExecOptionsRunAs(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("run-as") == nil {
+			t.Errorf("Expected --run-as flag on %s", cmd.Name())
+		}
+	}
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().Bool("sudo", false, "")
+		addRunAsFlag(cmd)
+		addNoWrapFlag(cmd)
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+
+	opts, err := resolveExecOptions(newCmd("--run-as", "appuser"))
+	if err != nil || opts.RunAs != "appuser" || opts.ssmOptions().RunAs != "appuser" {
+		t.Errorf("Expected --run-as to reach SSM options, got %+v (%v)", opts, err)
+	}
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--run-as", "appuser", "--sudo"}, want: "--sudo"},
+		{args: []string{"--run-as", "appuser", "--no-wrap"}, want: "--no-wrap"},
+		{args: []string{"--run-as", "app user"}, want: "invalid --run-as"},
+		{args: []string{"--run-as", "-rf"}, want: "invalid --run-as"},
+		{args: []string{"--run-as", "$(id)"}, want: "invalid --run-as"},
+	} {
+		if _, err := resolveExecOptions(newCmd(tt.args...)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Expected %v to be rejected mentioning %q, got %v", tt.args, tt.want, err)
+		}
+	}
+}
+
+func TestResolveExecOptionsNoWrap(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("no-wrap") == nil {
+			t.Errorf("Expected --no-wrap flag on %s", cmd.Name())
+		}
+	}
+
+	newCmd := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		cmd.Flags().Bool("sudo", false, "")
+		addNoWrapFlag(cmd)
+		addEnvFlags(cmd)
+		addParamFromSSMFlag(cmd)
+		if err := cmd.Flags().Parse(args); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+
+	opts, err := resolveExecOptions(newCmd("--no-wrap"))
+	if err != nil || !opts.ssmOptions().NoWrap {
+		t.Errorf("Expected --no-wrap to reach SSM options, got %+v (%v)", opts, err)
+	}
+
+	for _, args := range [][]string{
+		{"--no-wrap", "--sudo"},
+		{"--no-wrap", "--env", "A=1"},
+		{"--no-wrap", "--param-from-ssm", "A=/app/a"},
+	} {
+		if _, err := resolveExecOptions(newCmd(args...)); err == nil || !strings.Contains(err.Error(), "--no-wrap") {
+			t.Errorf("Expected %v to be rejected, got %v", args, err)
+		}
+	}
+}
+
+func TestParseExcludePatterns(t *testing.T) {
+	got := parseExcludePatterns(" i-0canary , maint-*,,")
+	if len(got) != 2 || got[0] != "i-0canary" || got[1] != "maint-*" {
+		t.Errorf("Unexpected patterns: %q", got)
+	}
+	if got := parseExcludePatterns(""); got != nil {
+		t.Errorf("Expected no patterns for empty value, got %q", got)
+	}
+}
+
+func TestExcludeInstances(t *testing.T) {
+	instances := []interactive.Instance{
+		{InstanceID: "i-0canary", Name: "web-1"},
+		{InstanceID: "i-0web2", Name: "web-2"},
+		{InstanceID: "i-0maint1", Name: "maint-a"},
+		{InstanceID: "i-0maint2", Name: "maint-b"},
+		{InstanceID: "i-0noname"},
+	}
+
+	kept, excluded := excludeInstances(instances, []string{"i-0canary", "maint-*"})
+	if excluded != 3 {
+		t.Errorf("Expected 3 excluded instances, got %d", excluded)
+	}
+	if len(kept) != 2 || kept[0].InstanceID != "i-0web2" || kept[1].InstanceID != "i-0noname" {
+		t.Errorf("Unexpected remaining instances: %+v", kept)
+	}
+
+	if kept, excluded := excludeInstances(instances, nil); excluded != 0 || len(kept) != len(instances) {
+		t.Errorf("Expected no exclusions without patterns, got %d excluded", excluded)
+	}
+
+	for _, cmd := range []*cobra.Command{ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("exclude") == nil {
+			t.Errorf("Expected --exclude flag on %s", cmd.Name())
+		}
+	}
+}
+
+func TestResolveExecOptionsCancelOnTimeout(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("cancel-on-timeout") == nil {
+			t.Errorf("Expected --cancel-on-timeout flag on %s", cmd.Name())
+		}
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().Bool("cancel-on-timeout", false, "")
+	if err := cmd.Flags().Set("cancel-on-timeout", "true"); err != nil {
+		t.Fatal(err)
+	}
+
+	if opts, err := resolveExecOptions(cmd); err != nil || !opts.ssmOptions().CancelOnTimeout {
+		t.Errorf("Expected cancel-on-timeout to reach SSM options, got %+v", opts)
+	}
+}
+
+func TestResolveExecOptionsOutputMode(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		flag := cmd.Flags().Lookup("output-mode")
+		if flag == nil || flag.DefValue != outputModeGrouped {
+			t.Errorf("Expected --output-mode flag defaulting to grouped on %s", cmd.Name())
+		}
+	}
+
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "", want: outputModeGrouped},
+		{value: "grouped", want: outputModeGrouped},
+		{value: "interleaved", want: outputModeInterleaved},
+		{value: "streaming", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			cmd.Flags().String("output-mode", "", "")
+			if err := cmd.Flags().Set("output-mode", tt.value); err != nil {
+				t.Fatal(err)
+			}
+
+			opts, err := resolveExecOptions(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveExecOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && opts.OutputMode != tt.want {
+				t.Errorf("Expected output mode %q, got %q", tt.want, opts.OutputMode)
+			}
+		})
+	}
+}
+
+func TestPrintInterleavedResult(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	exitCode := int32(2)
+	printInterleavedResult(ParallelExecutionResult{
+		Instance: interactive.Instance{InstanceID: "i-0web1", Name: "web-1"},
+		Result:   &ssm.CommandResult{Output: "line one\nline two\n", ErrorOutput: "oops\n", ExitCode: &exitCode},
+	}, execOptions{})
+
+	output := buf.String()
+	for _, want := range []string{"[i-0web1] line one\n", "[i-0web1] line two\n", "[i-0web1:stderr] oops\n", "[i-0web1] ✗ exit code 2"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in interleaved output, got:\n%s", want, output)
+		}
+	}
+
+	if got := outputLines(""); got != nil {
+		t.Errorf("Expected no lines for empty output, got %q", got)
+	}
+}
+
+func TestHideOutput(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("hide-output") == nil || cmd.Flags().Lookup("show-errors") == nil {
+			t.Errorf("Expected --hide-output and --show-errors flags on %s", cmd.Name())
+		}
+	}
+
+	tests := []struct {
+		name        string
+		opts        execOptions
+		wantSuccess bool
+		wantFailure bool
+	}{
+		{"default shows all output", execOptions{}, true, true},
+		{"hide-output hides all output", execOptions{HideOutput: true}, false, false},
+		{"show-errors keeps failed output", execOptions{HideOutput: true, ShowErrors: true}, false, true},
+		{"show-errors alone changes nothing", execOptions{ShowErrors: true}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.showsOutput(true); got != tt.wantSuccess {
+				t.Errorf("showsOutput(succeeded) = %v, want %v", got, tt.wantSuccess)
+			}
+			if got := tt.opts.showsOutput(false); got != tt.wantFailure {
+				t.Errorf("showsOutput(failed) = %v, want %v", got, tt.wantFailure)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	exitCode := int32(3)
+	opts := execOptions{HideOutput: true, ShowErrors: true}
+	printInterleavedResult(ParallelExecutionResult{
+		Instance: interactive.Instance{InstanceID: "i-0ok"},
+		Result:   &ssm.CommandResult{Output: "restarted\n"},
+	}, opts)
+	printInterleavedResult(ParallelExecutionResult{
+		Instance: interactive.Instance{InstanceID: "i-0bad"},
+		Result:   &ssm.CommandResult{ErrorOutput: "unit not found\n", ExitCode: &exitCode},
+	}, opts)
+
+	output := buf.String()
+	if strings.Contains(output, "restarted") {
+		t.Errorf("Expected successful output to be hidden, got:\n%s", output)
+	}
+	for _, want := range []string{"[i-0ok] ✓ exit code 0", "[i-0bad:stderr] unit not found", "[i-0bad] ✗ exit code 3"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestVerboseOnFailure(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("verbose-on-failure") == nil {
+			t.Errorf("Expected --verbose-on-failure flag on %s", cmd.Name())
+		}
+	}
+
+	cmd := &cobra.Command{}
+	addHideOutputFlags(cmd)
+	addOutputCompareFlag(cmd)
+	_ = cmd.Flags().Set("verbose-on-failure", "true")
+	opts, err := resolveExecOptions(cmd)
+	if err != nil {
+		t.Fatalf("resolveExecOptions() error = %v", err)
+	}
+	if !opts.VerboseOnFailure || opts.showsOutput(true) || !opts.showsOutput(false) {
+		t.Errorf("Expected only failed output to be shown, got %+v", opts)
+	}
+
+	_ = cmd.Flags().Set("output-compare", "true")
+	if _, err := resolveExecOptions(cmd); err == nil || !strings.Contains(err.Error(), "--verbose-on-failure") {
+		t.Errorf("Expected --output-compare to conflict with --verbose-on-failure, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	printSuccessLine(ParallelExecutionResult{
+		Instance:  interactive.Instance{InstanceID: "i-0ok", Name: "web-1"},
+		Result:    &ssm.CommandResult{Output: "restarted\n"},
+		Duration:  1500 * time.Millisecond,
+		Attempts:  2,
+		Collected: "i-0ok/report.txt",
+	})
+	output := buf.String()
+	if strings.Count(output, "\n") != 1 || strings.Contains(output, "restarted") {
+		t.Errorf("Expected a single status line without output, got:\n%s", output)
+	}
+	for _, want := range []string{"✓ web-1 (i-0ok): exit code 0", "2 attempts", "collected i-0ok/report.txt"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestParseParamFromSSM(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if cmd.Flags().Lookup("param-from-ssm") == nil {
+			t.Errorf("Expected --param-from-ssm flag on %s", cmd.Name())
+		}
+	}
+
+	params, err := parseParamFromSSM([]string{"DB_PASS=/app/db/password", " API_KEY = /app/api-key "})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if params["DB_PASS"] != "/app/db/password" || params["API_KEY"] != "/app/api-key" {
+		t.Errorf("Unexpected params: %v", params)
+	}
+
+	if params, err := parseParamFromSSM(nil); err != nil || params != nil {
+		t.Errorf("Expected no params without flags, got %v (%v)", params, err)
+	}
+
+	for _, value := range []string{"DB_PASS", "DB_PASS=", "1DB=/x", "DB-PASS=/x", "=/x"} {
+		if _, err := parseParamFromSSM([]string{value}); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+	if _, err := parseParamFromSSM([]string{"A=/x", "A=/y"}); err == nil {
+		t.Error("Expected duplicate variable names to be rejected")
+	}
+
+	opts := execOptions{Sudo: true, paramEnv: map[string]string{"DB_PASS": "secret"}}
+	if opts.ssmOptions().Env["DB_PASS"] != "secret" {
+		t.Error("Expected fetched values to be passed to the SSM manager as Env")
+	}
+}
+
+func TestExecExitCode(t *testing.T) {
+	if got := execExitCode(fmt.Errorf("instance selection failed")); got != 1 {
+		t.Errorf("Expected plain errors to exit with 1, got %d", got)
+	}
+
+	wrapped := fmt.Errorf("failed: %w", &exitCodeError{code: remoteExitCode(3), err: fmt.Errorf("command exited with non-zero status: 3")})
+	if got := execExitCode(wrapped); got != 3 {
+		t.Errorf("Expected remote exit code 3 to propagate, got %d", got)
+	}
+
+	for code, want := range map[int32]int{-1: 1, 0: 1, 2: 2, 255: 255, 256: 1} {
+		if got := remoteExitCode(code); got != want {
+			t.Errorf("remoteExitCode(%d) = %d, want %d", code, got, want)
+		}
+	}
+	for failures, want := range map[int]int{0: 1, 1: 1, 7: 7, 125: 125, 400: 125} {
+		if got := failureExitCode(failures); got != want {
+			t.Errorf("failureExitCode(%d) = %d, want %d", failures, got, want)
+		}
+	}
+}
+
+func TestResolveExecOptionsBatchSize(t *testing.T) {
+	for _, cmd := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if flag := cmd.Flags().Lookup("batch-size"); flag == nil || flag.DefValue != "0" {
+			t.Errorf("Expected --batch-size flag defaulting to 0 on %s", cmd.Name())
+		}
+	}
+
+	for value, wantErr := range map[string]bool{"0": false, "25": false, "50": false, "51": true, "-1": true} {
+		cmd := &cobra.Command{Use: "test"}
+		addBatchSizeFlag(cmd)
+		if err := cmd.Flags().Set("batch-size", value); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := resolveExecOptions(cmd); (err != nil) != wantErr {
+			t.Errorf("--batch-size %s: error = %v, wantErr %v", value, err, wantErr)
+		}
+	}
+}
+
+func TestChunkInstances(t *testing.T) {
+	instances := make([]interactive.Instance, 7)
+	for i := range instances {
+		instances[i].InstanceID = fmt.Sprintf("i-%d", i)
+	}
+
+	batches := chunkInstances(instances, 3)
+	if len(batches) != 3 || len(batches[0]) != 3 || len(batches[2]) != 1 {
+		t.Fatalf("Unexpected batches: %v", batches)
+	}
+	if batches[2][0].InstanceID != "i-6" {
+		t.Errorf("Expected batches to keep instance order, got %v", batches[2])
+	}
+	if got := chunkInstances(nil, 50); got != nil {
+		t.Errorf("Expected no batches for no instances, got %v", got)
+	}
+}
+
+func TestResolveExecOptionsPlatform(t *testing.T) {
+	for _, c := range []*cobra.Command{ssmExecCmd, ssmExecTaggedCmd, ssmExecMultiCmd} {
+		if c.Flags().Lookup("platform") == nil {
+			t.Errorf("Expected --platform flag on %s", c.Name())
+		}
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	addPlatformFlag(cmd)
+	_ = cmd.Flags().Set("platform", "Windows")
+	opts, err := resolveExecOptions(cmd)
+	if err != nil || opts.Platform != platform.PlatformWindows || opts.ssmOptions().Platform != platform.PlatformWindows {
+		t.Errorf("Expected --platform Windows to force the Windows document, got %q (%v)", opts.Platform, err)
+	}
+
+	_ = cmd.Flags().Set("platform", "solaris")
+	if _, err := resolveExecOptions(cmd); err == nil {
+		t.Error("Expected an error for --platform solaris")
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	"ztictl/internal/config"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	hookStagePre  = "pre"
+	hookStagePost = "post"
+)
+
+// execHooks holds the local shell commands run around an exec operation
+type execHooks struct {
+	PreHook  string
+	PostHook string
+}
+
+// hookContext describes the execution state exposed to hooks via environment variables
+type hookContext struct {
+	Stage        string
+	Region       string
+	Command      string
+	TargetCount  int
+	SuccessCount int
+	FailureCount int
+}
+
+// environ returns the hook environment variables in KEY=value form
+func (h hookContext) environ() []string {
+	return []string{
+		"ZTICTL_HOOK_STAGE=" + h.Stage,
+		"ZTICTL_REGION=" + h.Region,
+		"ZTICTL_COMMAND=" + h.Command,
+		"ZTICTL_TARGET_COUNT=" + strconv.Itoa(h.TargetCount),
+		"ZTICTL_SUCCESS_COUNT=" + strconv.Itoa(h.SuccessCount),
+		"ZTICTL_FAILURE_COUNT=" + strconv.Itoa(h.FailureCount),
+	}
+}
+
+// resolveExecHooks returns the hooks for a command, with flags taking precedence over config
+func resolveExecHooks(cmd *cobra.Command) execHooks {
+	cfg := config.Get()
+	hooks := execHooks{
+		PreHook:  cfg.Exec.PreHook,
+		PostHook: cfg.Exec.PostHook,
+	}
+
+	if cmd.Flags().Changed("pre-hook") {
+		hooks.PreHook, _ = cmd.Flags().GetString("pre-hook")
+	}
+	if cmd.Flags().Changed("post-hook") {
+		hooks.PostHook, _ = cmd.Flags().GetString("post-hook")
+	}
+
+	return hooks
+}
+
+// addExecHookFlags registers the --pre-hook and --post-hook flags on a command
+func addExecHookFlags(cmd *cobra.Command) {
+	cmd.Flags().String("pre-hook", "", "Local shell command to run before sending the command (failure aborts execution)")
+	cmd.Flags().String("post-hook", "", "Local shell command to run after results are aggregated (failure only warns)")
+}
+
+// runLocalHook runs a hook command through the local shell with the hook context in its environment
+func runLocalHook(ctx context.Context, hookCommand string, hookCtx hookContext) error {
+	if hookCommand == "" {
+		return nil
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", hookCommand) // #nosec G204 - hook command is user-configured
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", hookCommand) // #nosec G204 - hook command is user-configured
+	}
+	cmd.Env = append(os.Environ(), hookCtx.environ()...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	logging.LogDebug("Running %s-hook", hookCtx.Stage)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s-hook failed: %w", hookCtx.Stage, err)
+	}
+
+	return nil
+}
+
+// runPreHook runs the pre-hook; a failure must abort the execution
+func (h execHooks) runPreHook(ctx context.Context, hookCtx hookContext) error {
+	hookCtx.Stage = hookStagePre
+	return runLocalHook(ctx, h.PreHook, hookCtx)
+}
+
+// runPostHook runs the post-hook; failures are reported but do not affect the result
+func (h execHooks) runPostHook(ctx context.Context, hookCtx hookContext) {
+	hookCtx.Stage = hookStagePost
+	if err := runLocalHook(ctx, h.PostHook, hookCtx); err != nil {
+		logging.LogWarn("%v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"ztictl/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+func TestHookContextEnviron(t *testing.T) {
+	hookCtx := hookContext{
+		Stage:        hookStagePost,
+		Region:       "ca-central-1",
+		Command:      "uptime",
+		TargetCount:  3,
+		SuccessCount: 2,
+		FailureCount: 1,
+	}
+
+	env := strings.Join(hookCtx.environ(), "\n")
+	for _, want := range []string{
+		"ZTICTL_HOOK_STAGE=post",
+		"ZTICTL_REGION=ca-central-1",
+		"ZTICTL_COMMAND=uptime",
+		"ZTICTL_TARGET_COUNT=3",
+		"ZTICTL_SUCCESS_COUNT=2",
+		"ZTICTL_FAILURE_COUNT=1",
+	} {
+		if !strings.Contains(env, want) {
+			t.Errorf("environ() missing %q, got:\n%s", want, env)
+		}
+	}
+}
+
+func TestRunLocalHook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("empty hook is a no-op", func(t *testing.T) {
+		if err := runLocalHook(ctx, "", hookContext{Stage: hookStagePre}); err != nil {
+			t.Errorf("Expected no error for empty hook, got %v", err)
+		}
+	})
+
+	t.Run("successful hook", func(t *testing.T) {
+		if err := runLocalHook(ctx, "exit 0", hookContext{Stage: hookStagePre}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("failing hook returns error", func(t *testing.T) {
+		err := runLocalHook(ctx, "exit 3", hookContext{Stage: hookStagePre})
+		if err == nil {
+			t.Fatal("Expected error for failing hook")
+		}
+		if !strings.Contains(err.Error(), "pre-hook failed") {
+			t.Errorf("Expected error to mention pre-hook, got %v", err)
+		}
+	})
+
+	t.Run("hook receives environment", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Shell redirection syntax differs on Windows")
+		}
+
+		outFile := filepath.Join(t.TempDir(), "hook.out")
+		hookCtx := hookContext{Stage: hookStagePost, Region: "us-east-1", TargetCount: 4, SuccessCount: 3, FailureCount: 1}
+		hookCommand := `echo "$ZTICTL_HOOK_STAGE $ZTICTL_REGION $ZTICTL_TARGET_COUNT $ZTICTL_SUCCESS_COUNT $ZTICTL_FAILURE_COUNT" > "` + outFile + `"`
+
+		if err := runLocalHook(ctx, hookCommand, hookCtx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		data, err := os.ReadFile(outFile) // #nosec G304 - test temp file
+		if err != nil {
+			t.Fatalf("Failed to read hook output: %v", err)
+		}
+		if got := strings.TrimSpace(string(data)); got != "post us-east-1 4 3 1" {
+			t.Errorf("Unexpected hook output %q", got)
+		}
+	})
+}
+
+func TestExecHooksStages(t *testing.T) {
+	ctx := context.Background()
+	hooks := execHooks{PreHook: "exit 1", PostHook: "exit 1"}
+
+	err := hooks.runPreHook(ctx, hookContext{})
+	if err == nil {
+		t.Error("Expected pre-hook failure to return an error")
+	}
+
+	// Post-hook failures only warn and must not panic or propagate
+	hooks.runPostHook(ctx, hookContext{})
+}
+
+func TestResolveExecHooks(t *testing.T) {
+	cfg := config.Get()
+	original := cfg.Exec
+	defer func() { cfg.Exec = original }()
+
+	cfg.Exec = config.ExecConfig{PreHook: "config-pre", PostHook: "config-post"}
+
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{Use: "test"}
+		addExecHookFlags(cmd)
+		return cmd
+	}
+
+	t.Run("falls back to config", func(t *testing.T) {
+		hooks := resolveExecHooks(newCmd())
+		if hooks.PreHook != "config-pre" || hooks.PostHook != "config-post" {
+			t.Errorf("Expected config hooks, got %+v", hooks)
+		}
+	})
+
+	t.Run("flags override config", func(t *testing.T) {
+		cmd := newCmd()
+		if err := cmd.Flags().Set("pre-hook", "flag-pre"); err != nil {
+			t.Fatal(err)
+		}
+		if err := cmd.Flags().Set("post-hook", ""); err != nil {
+			t.Fatal(err)
+		}
+
+		hooks := resolveExecHooks(cmd)
+		if hooks.PreHook != "flag-pre" {
+			t.Errorf("Expected flag pre-hook, got %q", hooks.PreHook)
+		}
+		if hooks.PostHook != "" {
+			t.Errorf("Expected explicit empty post-hook to disable config hook, got %q", hooks.PostHook)
+		}
+	})
+}
+
+func TestCountInstanceResults(t *testing.T) {
+	results := []MultiRegionResult{
+		{Instances: []InstanceResult{{Success: true}, {Success: false}}},
+		{Instances: []InstanceResult{{Success: true}}},
+		{Instances: nil},
+	}
+
+	total, successful, failed := countInstanceResults(results)
+	if total != 3 || successful != 2 || failed != 1 {
+		t.Errorf("Expected 3/2/1, got %d/%d/%d", total, successful, failed)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// ssmInventoryCmd represents the ssm inventory command
+var ssmInventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Report OS details and installed software counts from SSM Inventory",
+	Long: `Report what SSM Inventory has collected about managed instances: the OS name and version, the agent
+version and the number of installed applications. The data comes from the AWS:InstanceInformation and
+AWS:Application inventory types, so instances need an Inventory association (for example from Quick Setup).
+Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
+
+Examples:
+  ztictl ssm inventory --region cac1
+  ztictl ssm inventory --region use1 --tags Environment=prod,Component=web
+  ztictl ssm inventory --region euw1 --output json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		regionCode, _ := cmd.Flags().GetString("region")
+		tags, _ := cmd.Flags().GetString("tags")
+		output, _ := cmd.Flags().GetString("output")
+
+		if err := performInventoryReport(os.Stdout, regionCode, tags, output); err != nil {
+			exitWithError(cmd, 1, "Inventory report failed", err)
+		}
+	},
+}
+
+// performInventoryReport prints the SSM Inventory of instances in a region, optionally limited to
+// instances matching tag filters, in the given output format
+func performInventoryReport(w io.Writer, regionCode, tags, output string) error {
+	switch output {
+	case outputFormatText, outputFormatJSON, outputFormatYAML:
+	default:
+		return fmt.Errorf("invalid --output '%s' (expected text, json or yaml)", output)
+	}
+
+	ctx := commandContext()
+	region := resolveRegion(regionCode)
+	ssmManager := ssm.NewManager(logger)
+
+	var instanceIDs []string
+	if tags != "" {
+		instances, err := ssmManager.ListInstances(ctx, region, &ssm.ListFilters{Tags: tags})
+		if err != nil {
+			return fmt.Errorf("failed to list instances: %w", err)
+		}
+		instanceIDs = make([]string, len(instances))
+		for i, instance := range instances {
+			instanceIDs[i] = instance.InstanceID
+		}
+		logging.LogDebug("Tags %s matched %d instance(s) in %s", tags, len(instanceIDs), region)
+	}
+
+	inventory, err := ssmManager.GetInventory(ctx, region, instanceIDs)
+	if err != nil {
+		return fmt.Errorf("failed to read inventory: %w", err)
+	}
+
+	if output == outputFormatJSON || output == outputFormatYAML {
+		if inventory == nil {
+			inventory = []ssm.InstanceInventory{}
+		}
+		if output == outputFormatYAML {
+			return writeYAML(w, inventory)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(inventory)
+	}
+
+	if len(inventory) == 0 {
+		colors.PrintWarning("No inventory data found in %s; check that an SSM Inventory association targets these instances\n", region)
+		return nil
+	}
+	printInventory(w, inventory)
+	return nil
+}
+
+// printInventory prints instance inventory as a table
+func printInventory(w io.Writer, inventory []ssm.InstanceInventory) {
+	formatter := NewTableFormatter(2)
+	ids := make([]string, len(inventory))
+	names := make([]string, len(inventory))
+	platforms := make([]string, len(inventory))
+	versions := make([]string, len(inventory))
+	agents := make([]string, len(inventory))
+	applications := make([]string, len(inventory))
+
+	for i, instance := range inventory {
+		ids[i] = instance.InstanceID
+		names[i] = instance.ComputerName
+		platforms[i] = instance.PlatformName
+		versions[i] = instance.PlatformVersion
+		agents[i] = instance.AgentVersion
+		applications[i] = "-"
+		if instance.Applications != nil {
+			applications[i] = strconv.Itoa(*instance.Applications)
+		}
+	}
+
+	formatter.AddColumn("Instance ID", ids, 19)
+	formatter.AddColumn("Computer Name", names, 13)
+	formatter.AddColumn("Platform", platforms, 8)
+	formatter.AddColumn("Version", versions, 7)
+	formatter.AddColumn("Agent Version", agents, 13)
+	formatter.AddColumn("Applications", applications, 12)
+
+	_, _ = fmt.Fprintf(w, "%s\n", colors.ColorHeader("%s", formatter.FormatHeader()))
+	for i := 0; i < formatter.GetRowCount(); i++ {
+		_, _ = fmt.Fprintf(w, "%s\n", formatter.FormatRow(i))
+	}
+	_, _ = fmt.Fprintf(w, "\n%d instance(s) with inventory data\n", len(inventory))
+}
+
+func init() {
+	addRegionFlag(ssmInventoryCmd)
+	ssmInventoryCmd.Flags().StringP("tags", "t", "", "Only report instances matching these tag filters (key=value or key alone, separated by commas)")
+	ssmInventoryCmd.Flags().StringP("output", "o", "text", "Output format: text, json or yaml")
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"ztictl/internal/ssm"
+)
+
+func TestPrintInventory(t *testing.T) {
+	count := 42
+	var buf bytes.Buffer
+	printInventory(&buf, []ssm.InstanceInventory{
+		{InstanceID: "i-linux", ComputerName: "web-1", PlatformName: "Amazon Linux", PlatformVersion: "2023", AgentVersion: "3.3.0.0", Applications: &count},
+		{InstanceID: "i-windows", PlatformName: "Microsoft Windows Server 2022 Datacenter"},
+	})
+
+	output := buf.String()
+	for _, want := range []string{"Instance ID", "Applications", "i-linux", "web-1", "Amazon Linux", "2023", "42", "i-windows", "2 instance(s)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+	if !strings.Contains(strings.Split(output, "\n")[2], "-") {
+		t.Errorf("Expected a dash for an unknown application count, got:\n%s", output)
+	}
+
+	data, err := json.Marshal(ssm.InstanceInventory{InstanceID: "i-linux", Applications: &count})
+	if err != nil || !strings.Contains(string(data), `"application_count":42`) {
+		t.Errorf("Unexpected JSON: %s (%v)", data, err)
+	}
+	data, _ = json.Marshal(ssm.InstanceInventory{InstanceID: "i-windows"})
+	if strings.Contains(string(data), "application_count") {
+		t.Errorf("Expected no application count when it is unknown, got %s", data)
+	}
+}
+
+func TestPerformInventoryReportValidation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := performInventoryReport(&buf, "cac1", "", "csv"); err == nil {
+		t.Error("Expected unsupported --output to be rejected")
+	}
+}
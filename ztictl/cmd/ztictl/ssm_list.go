@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -21,22 +20,41 @@ var ssmListCmd = &cobra.Command{
 	Short: "List all EC2 instances with their SSM status",
 	Long: `List all EC2 instances in a region with their SSM agent status.
 Shows all instances regardless of their state or SSM connectivity.
-Optionally filter by tags, status, or name patterns.
-Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.`,
+Optionally filter by tags, status, name patterns, VPC or subnet.
+Use --tag-columns to show tag values as extra columns in table or CSV output.
+Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
+
+Examples:
+  ztictl ssm list --region cac1 --table --tag-columns Owner,Environment
+  ztictl ssm list --region use1 --output csv --tag-columns Owner,CostCenter > instances.csv`,
 	Run: func(cmd *cobra.Command, args []string) {
 		regionCode, _ := cmd.Flags().GetString("region")
 		tagFilter, _ := cmd.Flags().GetString("tag")
 		statusFilter, _ := cmd.Flags().GetString("status")
 		nameFilter, _ := cmd.Flags().GetString("name")
 		tableFormat, _ := cmd.Flags().GetBool("table")
+		output, _ := cmd.Flags().GetString("output")
+		tagColumns, _ := cmd.Flags().GetString("tag-columns")
+		network, err := resolveNetworkScope(cmd)
+		if err != nil {
+			logging.LogError("%v", err)
+			os.Exit(1)
+		}
+
+		display := listDisplay{Table: tableFormat, Output: output, TagColumns: parseTagColumns(tagColumns)}
+		if err := display.validate(); err != nil {
+			logging.LogError("%v", err)
+			os.Exit(1)
+		}
 
 		filters := &ssm.ListFilters{
 			Tag:    tagFilter,
 			Status: statusFilter,
 			Name:   nameFilter,
 		}
+		network.apply(filters)
 
-		if err := performInstanceListing(regionCode, filters, tableFormat); err != nil {
+		if err := performInstanceListing(regionCode, filters, display); err != nil {
 			logging.LogError("Instance listing failed: %v", err)
 			os.Exit(1)
 		}
@@ -44,12 +62,15 @@ Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-
 }
 
 // performInstanceListing handles instance listing logic and returns errors instead of calling os.Exit
-func performInstanceListing(regionCode string, filters *ssm.ListFilters, tableFormat bool) error {
+func performInstanceListing(regionCode string, filters *ssm.ListFilters, display listDisplay) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 	ssmManager := ssm.NewManager(logger)
 
-	colors.PrintData("🔍 Fetching instances from region %s...\n", region)
+	csvOutput := display.Output == outputFormatCSV
+	if !csvOutput {
+		colors.PrintData("🔍 Fetching instances from region %s...\n", region)
+	}
 
 	// Convert SSM filters to AWS filters
 	awsFilters := &awsservice.ListFilters{
@@ -57,6 +78,8 @@ func performInstanceListing(regionCode string, filters *ssm.ListFilters, tableFo
 		Tags:   filters.Tags,
 		Status: filters.Status,
 		Name:   filters.Name,
+		VPC:    filters.VPC,
+		Subnet: filters.Subnet,
 	}
 
 	instances, err := ssmManager.GetInstanceService().ListInstances(ctx, region, awsFilters)
@@ -65,6 +88,11 @@ func performInstanceListing(regionCode string, filters *ssm.ListFilters, tableFo
 		return fmt.Errorf("failed to list instances: %w", err)
 	}
 
+	// CSV goes to stdout on its own, header row included even when nothing matched
+	if csvOutput {
+		return writeInstanceCSV(os.Stdout, instances, display.TagColumns)
+	}
+
 	if len(instances) == 0 {
 		colors.PrintWarning("⚠ No EC2 instances found in region: %s\n", region)
 		return nil
@@ -73,8 +101,8 @@ func performInstanceListing(regionCode string, filters *ssm.ListFilters, tableFo
 	colors.PrintSuccess("✓ Found %d instance(s) in region %s\n", len(instances), region)
 
 	// Use table format if requested, otherwise use interactive fuzzy finder
-	if tableFormat {
-		printInstanceTable(instances, region)
+	if display.Table {
+		printInstanceTable(instances, region, display.TagColumns)
 		return nil
 	}
 
@@ -97,7 +125,7 @@ func performInstanceListing(regionCode string, filters *ssm.ListFilters, tableFo
 }
 
 // printInstanceTable prints instances in a traditional table format
-func printInstanceTable(instances []interactive.Instance, region string) {
+func printInstanceTable(instances []interactive.Instance, region string, tagColumns []string) {
 	formatter := NewTableFormatter(2) // 2 spaces between columns
 
 	// Prepare column data
@@ -134,22 +162,7 @@ func printInstanceTable(instances []interactive.Instance, region string) {
 		states[i] = instance.State
 
 		// Format SSM status with color indicators
-		var ssmStatus string
-		switch instance.SSMStatus {
-		case "Online":
-			ssmStatus = colors.ColorSuccess("✓ Online")
-		case "ConnectionLost":
-			ssmStatus = colors.ColorWarning("⚠ Lost")
-		case "No Agent":
-			ssmStatus = colors.ColorError("✗ No Agent")
-		default:
-			if instance.SSMStatus == "" {
-				ssmStatus = colors.ColorError("✗ No Agent")
-			} else {
-				ssmStatus = colors.ColorWarning("? %s", instance.SSMStatus)
-			}
-		}
-		ssmStatuses[i] = ssmStatus
+		ssmStatuses[i] = formatSSMStatusShort(instance.SSMStatus)
 
 		// Platform
 		platforms[i] = instance.Platform
@@ -163,6 +176,9 @@ func printInstanceTable(instances []interactive.Instance, region string) {
 	formatter.AddColumn("State", states, 8)
 	formatter.AddColumn("SSM Status", ssmStatuses, 10)
 	formatter.AddColumn("Platform", platforms, 8)
+	for _, key := range tagColumns {
+		formatter.AddColumn(key, tagColumnValues(instances, key), 0)
+	}
 
 	fmt.Printf("\n")
 	colors.PrintHeader("All EC2 Instances in %s:\n", region)
@@ -184,6 +200,20 @@ func printInstanceTable(instances []interactive.Instance, region string) {
 	colors.PrintData("Usage: ztictl ssm connect <instance-id-or-name>\n")
 }
 
+// formatSSMStatusShort returns a compact, colored SSM status for table output
+func formatSSMStatusShort(status string) string {
+	switch status {
+	case "Online":
+		return colors.ColorSuccess("✓ Online")
+	case "ConnectionLost":
+		return colors.ColorWarning("⚠ Lost")
+	case "No Agent", "":
+		return colors.ColorError("✗ No Agent")
+	default:
+		return colors.ColorWarning("? %s", status)
+	}
+}
+
 // printInstanceDetails displays detailed information about the selected instance
 func printInstanceDetails(instance *interactive.Instance, region string) {
 	fmt.Printf("\n")
@@ -318,9 +348,13 @@ func containsIgnoreCase(s, substr string) bool {
 }
 
 func init() {
-	ssmListCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
-	ssmListCmd.Flags().StringP("tag", "t", "", "Filter by tag (format: key=value)")
+	addRegionFlag(ssmListCmd)
+	ssmListCmd.Flags().StringP("tag", "t", "", "Filter by tag (format: key=value, or key to match any value)")
 	ssmListCmd.Flags().StringP("status", "s", "", "Filter by status (running, stopped, etc.)")
 	ssmListCmd.Flags().StringP("name", "n", "", "Filter by name pattern")
+	ssmListCmd.Flags().String("vpc", "", "Filter by VPC ID (comma-separated for several)")
+	ssmListCmd.Flags().String("subnet", "", "Filter by subnet ID (comma-separated for several)")
 	ssmListCmd.Flags().Bool("table", false, "Display instances in table format instead of interactive fuzzy finder")
+	ssmListCmd.Flags().StringP("output", "o", outputFormatText, "Output format: text or csv (csv prints instances to stdout for spreadsheets and scripts)")
+	ssmListCmd.Flags().String("tag-columns", "", "Comma-separated tag keys to show as extra columns in table and CSV output (e.g. Owner,Environment)")
 }
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"ztictl/internal/interactive"
+)
+
+// outputFormatCSV prints ssm list results as comma-separated values
+const outputFormatCSV = "csv"
+
+// listDisplay controls how ssm list presents the instances it found
+type listDisplay struct {
+	Table      bool     // Print a table instead of opening the fuzzy finder
+	Output     string   // outputFormatText or outputFormatCSV
+	TagColumns []string // Tag keys shown as extra columns, in order
+}
+
+// validate rejects unknown output formats and tag columns that would never be shown
+func (d listDisplay) validate() error {
+	switch d.Output {
+	case outputFormatText, outputFormatCSV:
+	default:
+		return fmt.Errorf("invalid --output '%s' (expected text or csv)", d.Output)
+	}
+	if len(d.TagColumns) > 0 && !d.Table && d.Output != outputFormatCSV {
+		return fmt.Errorf("--tag-columns requires --table or --output csv")
+	}
+	return nil
+}
+
+// parseTagColumns splits a comma-separated list of tag keys, dropping blanks and repeats
+func parseTagColumns(value string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, key := range strings.Split(value, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// tagColumnValues returns each instance's value for a tag key, empty when the tag is not set
+func tagColumnValues(instances []interactive.Instance, key string) []string {
+	values := make([]string, len(instances))
+	for i, instance := range instances {
+		values[i] = instance.Tags[key]
+	}
+	return values
+}
+
+// writeInstanceCSV writes instances as CSV with a header row. Values are written as reported by
+// AWS, without the N/A placeholders and status symbols of the table.
+func writeInstanceCSV(w io.Writer, instances []interactive.Instance, tagColumns []string) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"Name", "Instance ID", "Private IP", "Public IP", "State", "SSM Status", "Platform"}
+	if err := writer.Write(append(header, tagColumns...)); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	for _, instance := range instances {
+		record := []string{
+			instance.Name,
+			instance.InstanceID,
+			instance.PrivateIPAddress,
+			instance.PublicIPAddress,
+			instance.State,
+			instance.SSMStatus,
+			instance.Platform,
+		}
+		for _, key := range tagColumns {
+			record = append(record, instance.Tags[key])
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
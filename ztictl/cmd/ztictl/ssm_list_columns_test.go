@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"ztictl/internal/interactive"
+)
+
+func TestParseTagColumns(t *testing.T) {
+	got := parseTagColumns(" Owner, Environment,,Owner ,Cost Center")
+	want := []string{"Owner", "Environment", "Cost Center"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTagColumns() = %v, want %v", got, want)
+	}
+	if got := parseTagColumns(""); got != nil {
+		t.Errorf("Expected no columns for an empty value, got %v", got)
+	}
+}
+
+func TestListDisplayValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		display listDisplay
+		wantErr string
+	}{
+		{"interactive", listDisplay{Output: outputFormatText}, ""},
+		{"table with tags", listDisplay{Table: true, Output: outputFormatText, TagColumns: []string{"Owner"}}, ""},
+		{"csv with tags", listDisplay{Output: outputFormatCSV, TagColumns: []string{"Owner"}}, ""},
+		{"unknown output", listDisplay{Output: "json"}, "invalid --output"},
+		{"tags without table", listDisplay{Output: outputFormatText, TagColumns: []string{"Owner"}}, "--tag-columns requires"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.display.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWriteInstanceCSV(t *testing.T) {
+	instances := []interactive.Instance{
+		{
+			Name:             "web, primary",
+			InstanceID:       "i-0123",
+			PrivateIPAddress: "10.0.0.1",
+			State:            "running",
+			SSMStatus:        "Online",
+			Platform:         "Linux",
+			Tags:             map[string]string{"Owner": "alice", "Environment": "prod"},
+		},
+		{InstanceID: "i-0456", State: "stopped", Tags: map[string]string{"Environment": "dev"}},
+	}
+
+	var buf bytes.Buffer
+	if err := writeInstanceCSV(&buf, instances, []string{"Owner", "Environment"}); err != nil {
+		t.Fatalf("writeInstanceCSV() error: %v", err)
+	}
+
+	want := "Name,Instance ID,Private IP,Public IP,State,SSM Status,Platform,Owner,Environment\n" +
+		"\"web, primary\",i-0123,10.0.0.1,,running,Online,Linux,alice,prod\n" +
+		",i-0456,,,stopped,,,,dev\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected CSV:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestTagColumnValues(t *testing.T) {
+	instances := []interactive.Instance{
+		{Tags: map[string]string{"Owner": "alice"}},
+		{},
+	}
+	got := tagColumnValues(instances, "Owner")
+	if !reflect.DeepEqual(got, []string{"alice", ""}) {
+		t.Errorf("tagColumnValues() = %v", got)
+	}
+}
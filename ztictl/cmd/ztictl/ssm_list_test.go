@@ -220,6 +220,8 @@ func TestSsmListCmdFlags(t *testing.T) {
 		{"status", "s", "", false},
 		{"name", "n", "", false},
 		{"table", "", "false", false},
+		{"output", "o", "text", false},
+		{"tag-columns", "", "", false},
 	}
 
 	for _, tt := range tests {
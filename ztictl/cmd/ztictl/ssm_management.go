@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -79,7 +78,7 @@ func performPortForwarding(regionCode, instanceIdentifier, portMapping string) e
 	logging.LogInfo("Starting port forwarding %d:%d on instance %s in region: %s", localPort, remotePort, instanceIdentifier, region)
 
 	ssmManager := ssm.NewManager(logger)
-	ctx := context.Background()
+	ctx := commandContext()
 
 	if err := ssmManager.ForwardPort(ctx, instanceIdentifier, region, localPort, remotePort); err != nil {
 		return fmt.Errorf("port forwarding failed: %w", err)
@@ -93,7 +92,7 @@ func performStatusCheck(regionCode, instanceIdentifier string) error {
 	region := resolveRegion(regionCode)
 
 	ssmManager := ssm.NewManager(logger)
-	ctx := context.Background()
+	ctx := commandContext()
 
 	if instanceIdentifier != "" {
 		// Show status for specific instance
@@ -139,6 +138,6 @@ func performStatusCheck(regionCode, instanceIdentifier string) error {
 }
 
 func init() {
-	ssmForwardCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
-	ssmStatusCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmForwardCmd)
+	addRegionFlag(ssmStatusCmd)
 }
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"ztictl/internal/config"
+	"ztictl/internal/interactive"
+	"ztictl/internal/metrics"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// ssmMetricsCmd represents the ssm metrics command
+var ssmMetricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Write SSM agent health per region as Prometheus gauges",
+	Long: `Count SSM-managed instances by agent ping status in each region and write the counts as
+Prometheus gauges, for the node_exporter textfile collector. Run it from cron to keep a fleet
+health dashboard current without a custom exporter.
+
+With --textfile the file is replaced atomically; otherwise the gauges are written to stdout.
+A region that cannot be queried reports ztictl_ssm_scrape_success 0 and the command exits non-zero
+after writing the other regions.
+
+Examples:
+  ztictl ssm metrics --regions cac1,use1
+  ztictl ssm metrics --all-regions --textfile /var/lib/node_exporter/textfile/ztictl_ssm.prom
+
+  # crontab entry refreshing the gauges every 5 minutes
+  */5 * * * * ztictl ssm metrics --all-regions --textfile /var/lib/node_exporter/textfile/ztictl_ssm.prom`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		regionsFlag, _ := cmd.Flags().GetString("regions")
+		allRegions, _ := cmd.Flags().GetBool("all-regions")
+		textfile, _ := cmd.Flags().GetString("textfile")
+
+		regions, err := metricsRegions(regionsFlag, allRegions)
+		if err != nil {
+			logging.LogError("%v", err)
+			os.Exit(1)
+		}
+
+		if err := performFleetMetrics(os.Stdout, regions, textfile); err != nil {
+			logging.LogError("Fleet metrics failed: %v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// regionScrape is the result of listing instance statuses in one region
+type regionScrape struct {
+	Region    string
+	Instances []interactive.Instance
+	Err       error
+}
+
+// metricsRegions returns the full region names to scrape: --regions, the configured regions with
+// --all-regions, or the default region
+func metricsRegions(regionsFlag string, allRegions bool) ([]string, error) {
+	if regionsFlag != "" && allRegions {
+		return nil, fmt.Errorf("--regions and --all-regions cannot be used together")
+	}
+
+	var codes []string
+	switch {
+	case regionsFlag != "":
+		for _, code := range strings.Split(regionsFlag, ",") {
+			if code = strings.TrimSpace(code); code != "" {
+				codes = append(codes, code)
+			}
+		}
+	case allRegions:
+		cfg := config.Get()
+		codes = cfg.Regions.Enabled
+		if len(codes) == 0 {
+			codes = cfg.Regions.Groups["all"]
+		}
+		if len(codes) == 0 {
+			return nil, fmt.Errorf("no regions configured for --all-regions (set regions.enabled in ~/.ztictl.yaml)")
+		}
+	default:
+		codes = []string{""}
+	}
+
+	regions := make([]string, len(codes))
+	for i, code := range codes {
+		regions[i] = resolveRegion(code)
+	}
+	return regions, nil
+}
+
+// performFleetMetrics scrapes each region and writes the gauges to textfile, or to w when empty
+func performFleetMetrics(w io.Writer, regions []string, textfile string) error {
+	ssmManager := ssm.NewManager(logger)
+
+	scrapes := make([]regionScrape, len(regions))
+	for i, region := range regions {
+		instances, err := ssmManager.ListInstanceStatuses(commandContext(), region)
+		if err != nil {
+			logging.LogWarn("Failed to list instance statuses in %s: %v", region, err)
+		}
+		scrapes[i] = regionScrape{Region: region, Instances: instances, Err: err}
+	}
+
+	gauges := fleetHealthGauges(scrapes, time.Now())
+	if textfile != "" {
+		if err := metrics.WriteTextfileAtomic(textfile, gauges); err != nil {
+			return err
+		}
+	} else if err := metrics.WriteTextfile(w, gauges); err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, scrape := range scrapes {
+		if scrape.Err != nil {
+			failed = append(failed, scrape.Region)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not scrape %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// fleetHealthGauges converts region scrapes into gauges. A failed region only reports
+// ztictl_ssm_scrape_success 0, so dashboards do not mistake it for an empty fleet.
+func fleetHealthGauges(scrapes []regionScrape, now time.Time) []metrics.Gauge {
+	online := metrics.Gauge{Name: "ztictl_ssm_instances_online", Help: "SSM-managed instances whose agent ping status is Online."}
+	connectionLost := metrics.Gauge{Name: "ztictl_ssm_instances_connection_lost", Help: "SSM-managed instances whose agent ping status is ConnectionLost."}
+	inactive := metrics.Gauge{Name: "ztictl_ssm_instances_inactive", Help: "SSM-managed instances whose agent ping status is Inactive."}
+	total := metrics.Gauge{Name: "ztictl_ssm_instances_total", Help: "SSM-managed instances registered in the region."}
+	success := metrics.Gauge{Name: "ztictl_ssm_scrape_success", Help: "Whether the region's instance statuses were listed (1) or not (0)."}
+	timestamp := metrics.Gauge{
+		Name:    "ztictl_ssm_scrape_timestamp_seconds",
+		Help:    "Unix time the gauges were written.",
+		Samples: []metrics.GaugeSample{{Value: float64(now.Unix())}},
+	}
+
+	for _, scrape := range scrapes {
+		labels := map[string]string{"region": scrape.Region}
+		if scrape.Err != nil {
+			success.Samples = append(success.Samples, metrics.GaugeSample{Labels: labels, Value: 0})
+			continue
+		}
+		success.Samples = append(success.Samples, metrics.GaugeSample{Labels: labels, Value: 1})
+
+		counts := make(map[string]float64)
+		for _, instance := range scrape.Instances {
+			counts[instance.SSMStatus]++
+		}
+		online.Samples = append(online.Samples, metrics.GaugeSample{Labels: labels, Value: counts["Online"]})
+		connectionLost.Samples = append(connectionLost.Samples, metrics.GaugeSample{Labels: labels, Value: counts["ConnectionLost"]})
+		inactive.Samples = append(inactive.Samples, metrics.GaugeSample{Labels: labels, Value: counts["Inactive"]})
+		total.Samples = append(total.Samples, metrics.GaugeSample{Labels: labels, Value: float64(len(scrape.Instances))})
+	}
+
+	return []metrics.Gauge{online, connectionLost, inactive, total, success, timestamp}
+}
+
+func init() {
+	ssmMetricsCmd.Flags().String("regions", "", "Comma-separated regions to scrape (shortcodes or full names; default: the default region)")
+	ssmMetricsCmd.Flags().Bool("all-regions", false, "Scrape every region enabled in ~/.ztictl.yaml")
+	ssmMetricsCmd.Flags().String("textfile", "", "Write the gauges to this .prom file atomically instead of stdout")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/internal/config"
+	"ztictl/internal/interactive"
+	"ztictl/internal/metrics"
+)
+
+func TestFleetHealthGauges(t *testing.T) {
+	scrapes := []regionScrape{
+		{Region: "ca-central-1", Instances: []interactive.Instance{
+			{InstanceID: "i-1", SSMStatus: "Online"},
+			{InstanceID: "i-2", SSMStatus: "Online"},
+			{InstanceID: "i-3", SSMStatus: "ConnectionLost"},
+		}},
+		{Region: "us-east-1", Err: errors.New("access denied")},
+		{Region: "eu-west-1"},
+	}
+
+	var buf bytes.Buffer
+	if err := metrics.WriteTextfile(&buf, fleetHealthGauges(scrapes, time.Unix(1700000000, 0))); err != nil {
+		t.Fatalf("WriteTextfile failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, line := range []string{
+		`ztictl_ssm_instances_online{region="ca-central-1"} 2`,
+		`ztictl_ssm_instances_connection_lost{region="ca-central-1"} 1`,
+		`ztictl_ssm_instances_inactive{region="ca-central-1"} 0`,
+		`ztictl_ssm_instances_total{region="ca-central-1"} 3`,
+		`ztictl_ssm_instances_online{region="eu-west-1"} 0`,
+		`ztictl_ssm_scrape_success{region="ca-central-1"} 1`,
+		`ztictl_ssm_scrape_success{region="us-east-1"} 0`,
+		`ztictl_ssm_scrape_timestamp_seconds 1.7e+09`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected line %q in:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, `ztictl_ssm_instances_online{region="us-east-1"}`) {
+		t.Errorf("A failed region should not report instance counts:\n%s", out)
+	}
+}
+
+func TestMetricsRegions(t *testing.T) {
+	cfg := config.Get()
+	original := cfg.Regions
+	t.Cleanup(func() { cfg.Regions = original })
+	cfg.Regions.Enabled = []string{"cac1", "use1"}
+
+	regions, err := metricsRegions(" cac1, eu-west-1 ,", false)
+	if err != nil || !slices.Equal(regions, []string{"ca-central-1", "eu-west-1"}) {
+		t.Errorf("--regions: got %v, %v", regions, err)
+	}
+
+	regions, err = metricsRegions("", true)
+	if err != nil || !slices.Equal(regions, []string{"ca-central-1", "us-east-1"}) {
+		t.Errorf("--all-regions: got %v, %v", regions, err)
+	}
+
+	if _, err := metricsRegions("cac1", true); err == nil {
+		t.Error("Expected --regions with --all-regions to be rejected")
+	}
+
+	cfg.Regions.Enabled = nil
+	cfg.Regions.Groups = nil
+	if _, err := metricsRegions("", true); err == nil {
+		t.Error("Expected --all-regions without configured regions to fail")
+	}
+}
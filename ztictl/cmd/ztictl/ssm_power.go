@@ -4,18 +4,19 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"runtime"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 	"unicode"
 
+	"ztictl/internal/metrics"
 	"ztictl/internal/ssm"
 	"ztictl/pkg/aws"
 	"ztictl/pkg/colors"
+	"ztictl/pkg/format"
 	"ztictl/pkg/logging"
 
-	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/spf13/cobra"
@@ -27,21 +28,30 @@ var ssmStartCmd = &cobra.Command{
 	Short: "Start stopped EC2 instance(s)",
 	Long: `Start stopped EC2 instance(s).
 If no instance identifier is provided, an interactive fuzzy finder will be launched.
-Instance identifier can be an instance ID (i-1234567890abcdef0) or instance name.
+Instance identifier can be an instance ID (i-1234567890abcdef0), instance name, private IP or private DNS name.
 Use --instances flag to specify multiple instance IDs (comma-separated).
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
 
 Examples:
   ztictl ssm start --region cac1                        # Interactive fuzzy finder
   ztictl ssm start i-1234567890abcdef0 --region cac1   # Specific instance
-  ztictl ssm start --instances i-1234,i-5678 --region use1  # Multiple instances`,
+  ztictl ssm start --instances i-1234,i-5678 --region use1  # Multiple instances
+  ztictl ssm start --instances i-1234,i-5678 --region use1 --dry-run  # Preview state transitions
+  ztictl ssm start i-1234567890abcdef0 --region cac1 --wait --max-wait 15m  # Wait for SSM Online`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		regionCode, _ := cmd.Flags().GetString("region")
 		instancesFlag, _ := cmd.Flags().GetString("instances")
-		parallelFlag, _ := cmd.Flags().GetInt("parallel")
+		parallelFlag := getParallelFlag(cmd)
 
-		if err := performPowerOperation(args, regionCode, instancesFlag, parallelFlag, "start"); err != nil {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		wait, err := resolvePowerWait(cmd)
+		if err != nil {
+			logging.LogError("%v", err)
+			os.Exit(1)
+		}
+
+		if err := performPowerOperation(args, regionCode, instancesFlag, parallelFlag, "start", dryRun, wait, newProductionGuard(cmd)); err != nil {
 			logging.LogError("Start operation failed: %v", err)
 			os.Exit(1)
 		}
@@ -54,7 +64,7 @@ var ssmStopCmd = &cobra.Command{
 	Short: "Stop running EC2 instance(s)",
 	Long: `Stop running EC2 instance(s).
 If no instance identifier is provided, an interactive fuzzy finder will be launched.
-Instance identifier can be an instance ID (i-1234567890abcdef0) or instance name.
+Instance identifier can be an instance ID (i-1234567890abcdef0), instance name, private IP or private DNS name.
 Use --instances flag to specify multiple instance IDs (comma-separated).
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
 
@@ -66,9 +76,11 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		regionCode, _ := cmd.Flags().GetString("region")
 		instancesFlag, _ := cmd.Flags().GetString("instances")
-		parallelFlag, _ := cmd.Flags().GetInt("parallel")
+		parallelFlag := getParallelFlag(cmd)
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
 
-		if err := performPowerOperation(args, regionCode, instancesFlag, parallelFlag, "stop"); err != nil {
+		if err := performPowerOperation(args, regionCode, instancesFlag, parallelFlag, "stop", dryRun, powerWait{}, newProductionGuard(cmd)); err != nil {
 			logging.LogError("Stop operation failed: %v", err)
 			os.Exit(1)
 		}
@@ -81,7 +93,7 @@ var ssmRebootCmd = &cobra.Command{
 	Short: "Reboot running EC2 instance(s)",
 	Long: `Reboot running EC2 instance(s).
 If no instance identifier is provided, an interactive fuzzy finder will be launched.
-Instance identifier can be an instance ID (i-1234567890abcdef0) or instance name.
+Instance identifier can be an instance ID (i-1234567890abcdef0), instance name, private IP or private DNS name.
 Use --instances flag to specify multiple instance IDs (comma-separated).
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
 
@@ -93,9 +105,11 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		regionCode, _ := cmd.Flags().GetString("region")
 		instancesFlag, _ := cmd.Flags().GetString("instances")
-		parallelFlag, _ := cmd.Flags().GetInt("parallel")
+		parallelFlag := getParallelFlag(cmd)
+
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
 
-		if err := performPowerOperation(args, regionCode, instancesFlag, parallelFlag, "reboot"); err != nil {
+		if err := performPowerOperation(args, regionCode, instancesFlag, parallelFlag, "reboot", dryRun, powerWait{}, newProductionGuard(cmd)); err != nil {
 			logging.LogError("Reboot operation failed: %v", err)
 			os.Exit(1)
 		}
@@ -109,21 +123,38 @@ var ssmStartTaggedCmd = &cobra.Command{
 	Long: `Start multiple stopped EC2 instances that match the specified tags.
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
 Use --tags flag to specify one or more tag filters in key=value format, separated by commas.
-Use --instances to explicitly specify instance IDs to target (comma-separated).
-Use --parallel to control maximum concurrent operations (default: number of CPU cores).
+Use --instances to explicitly specify instance IDs to target (comma-separated); with --tags they are added to the tag matches.
+Use --asg to target the InService and Standby instances of an Auto Scaling group.
+Use --parallel to control maximum concurrent operations (default: system.default_parallel, 10).
 
 Examples:
   ztictl ssm start-tagged --region cac1 --tags Environment=Production
   ztictl ssm start-tagged --region use1 --tags Environment=dev,Component=fts --parallel 5
-  ztictl ssm start-tagged --region cac1 --instances i-1234,i-5678`,
+  ztictl ssm start-tagged --region cac1 --instances i-1234,i-5678
+  ztictl ssm start-tagged --region cac1 --tags Environment=dev --dry-run  # Preview without starting
+  ztictl ssm start-tagged --region cac1 --tags Environment=dev --wait --poll-interval 15s  # Wait for SSM Online`,
 	Run: func(cmd *cobra.Command, args []string) {
 		regionCode, _ := cmd.Flags().GetString("region")
 		tagsFlag, _ := cmd.Flags().GetString("tags")
 		instancesFlag, _ := cmd.Flags().GetString("instances")
-		parallelFlag, _ := cmd.Flags().GetInt("parallel")
+		parallelFlag := getParallelFlag(cmd)
 
 		region := resolveRegion(regionCode)
 
+		wait, err := resolvePowerWait(cmd)
+		if err != nil {
+			colors.PrintError("✗ %v\n", err)
+			logging.LogError("Validation error for start-tagged command: %v", err)
+			os.Exit(1)
+		}
+
+		instancesFlag, err = resolveASGFlag(commandContext(), cmd, region, tagsFlag, instancesFlag)
+		if err != nil {
+			colors.PrintError("✗ %v\n", err)
+			logging.LogError("Failed to resolve --asg for start-tagged command: %v", err)
+			os.Exit(1)
+		}
+
 		// Validate arguments and flags
 		if err := validateTaggedCommandArgs(tagsFlag, instancesFlag, parallelFlag); err != nil {
 			colors.PrintError("✗ %v\n", err)
@@ -131,7 +162,7 @@ Examples:
 			os.Exit(1)
 		}
 
-		ctx := context.Background()
+		ctx := commandContext()
 		awsClient, err := aws.NewClient(ctx, aws.ClientOptions{Region: region})
 		if err != nil {
 			colors.PrintError("✗ Failed to create AWS client: %v\n", err)
@@ -139,35 +170,37 @@ Examples:
 			os.Exit(1)
 		}
 
-		var instanceIDs []string
-
-		if instancesFlag != "" {
-			// Use explicit instance IDs
-			instanceIDs = strings.Split(instancesFlag, ",")
-			for i, id := range instanceIDs {
-				instanceIDs[i] = strings.TrimSpace(id)
-			}
-			logging.LogInfo("Starting %d explicit instance IDs in region: %s", len(instanceIDs), region)
-		} else {
-			// Use tag filtering to find instances
-			instanceIDs, err = getInstanceIDsByTags(ctx, awsClient, tagsFlag)
-			if err != nil {
-				colors.PrintError("✗ Failed to find instances by tags: %v\n", err)
-				logging.LogError("Failed to find instances by tags: %v", err)
-				os.Exit(1)
-			}
-			logging.LogInfo("Starting %d instances with tags '%s' in region: %s", len(instanceIDs), tagsFlag, region)
+		// Tag matches and explicit instance IDs are combined
+		instanceIDs, err := resolveTaggedTargets(ctx, awsClient, tagsFlag, instancesFlag)
+		if err != nil {
+			colors.PrintError("✗ %v\n", err)
+			logging.LogError("%v", err)
+			os.Exit(1)
 		}
+		logging.LogInfo("Starting %d instances %s in region: %s", len(instanceIDs), describeTargets(tagsFlag, instancesFlag), region)
 
 		if len(instanceIDs) == 0 {
-			if instancesFlag != "" {
-				logging.LogInfo("No instances specified")
-			} else {
+			if tagsFlag != "" {
 				logging.LogInfo("No instances found with tags: %s", tagsFlag)
+			} else {
+				logging.LogInfo("No instances specified")
+			}
+			return
+		}
+
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			if err := previewPowerOperation(ctx, ssm.NewManager(logger), instanceIDs, region, "start"); err != nil {
+				logging.LogError("Start-tagged dry run failed: %v", err)
+				os.Exit(1)
 			}
 			return
 		}
 
+		if err := newProductionGuard(cmd).check(ctx, region, instanceIDs); err != nil {
+			logging.LogError("Start-tagged cancelled: %v", err)
+			os.Exit(1)
+		}
+
 		// Create SSM manager for validation
 		ssmManager := ssm.NewManager(logger)
 
@@ -177,7 +210,14 @@ Examples:
 		totalDuration := time.Since(startTime)
 
 		// Process and display results
-		if err := displayPowerOperationResults(results, "start", totalDuration, parallelFlag); err != nil {
+		resultErr := displayPowerOperationResults(results, "start", totalDuration, parallelFlag)
+		if wait.Enabled {
+			if err := waitForSSMOnline(ctx, startedInstanceIDs(results), wait, ssmPingStatuses(ssmManager, region)); err != nil {
+				logging.LogError("Start-tagged wait failed: %v", err)
+				os.Exit(1)
+			}
+		}
+		if resultErr != nil {
 			os.Exit(1)
 		}
 	},
@@ -190,8 +230,9 @@ var ssmStopTaggedCmd = &cobra.Command{
 	Long: `Stop multiple running EC2 instances that match the specified tags.
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
 Use --tags flag to specify one or more tag filters in key=value format, separated by commas.
-Use --instances to explicitly specify instance IDs to target (comma-separated).
-Use --parallel to control maximum concurrent operations (default: number of CPU cores).
+Use --instances to explicitly specify instance IDs to target (comma-separated); with --tags they are added to the tag matches.
+Use --asg to target the InService and Standby instances of an Auto Scaling group.
+Use --parallel to control maximum concurrent operations (default: system.default_parallel, 10).
 
 Examples:
   ztictl ssm stop-tagged --region cac1 --tags Environment=Production
@@ -201,10 +242,17 @@ Examples:
 		regionCode, _ := cmd.Flags().GetString("region")
 		tagsFlag, _ := cmd.Flags().GetString("tags")
 		instancesFlag, _ := cmd.Flags().GetString("instances")
-		parallelFlag, _ := cmd.Flags().GetInt("parallel")
+		parallelFlag := getParallelFlag(cmd)
 
 		region := resolveRegion(regionCode)
 
+		instancesFlag, err := resolveASGFlag(commandContext(), cmd, region, tagsFlag, instancesFlag)
+		if err != nil {
+			colors.PrintError("✗ %v\n", err)
+			logging.LogError("Failed to resolve --asg for stop-tagged command: %v", err)
+			os.Exit(1)
+		}
+
 		// Validate arguments and flags
 		if err := validateTaggedCommandArgs(tagsFlag, instancesFlag, parallelFlag); err != nil {
 			colors.PrintError("✗ %v\n", err)
@@ -212,7 +260,7 @@ Examples:
 			os.Exit(1)
 		}
 
-		ctx := context.Background()
+		ctx := commandContext()
 		awsClient, err := aws.NewClient(ctx, aws.ClientOptions{Region: region})
 		if err != nil {
 			colors.PrintError("✗ Failed to create AWS client: %v\n", err)
@@ -220,35 +268,37 @@ Examples:
 			os.Exit(1)
 		}
 
-		var instanceIDs []string
-
-		if instancesFlag != "" {
-			// Use explicit instance IDs
-			instanceIDs = strings.Split(instancesFlag, ",")
-			for i, id := range instanceIDs {
-				instanceIDs[i] = strings.TrimSpace(id)
-			}
-			logging.LogInfo("Stopping %d explicit instance IDs in region: %s", len(instanceIDs), region)
-		} else {
-			// Use tag filtering to find instances
-			instanceIDs, err = getInstanceIDsByTags(ctx, awsClient, tagsFlag)
-			if err != nil {
-				colors.PrintError("✗ Failed to find instances by tags: %v\n", err)
-				logging.LogError("Failed to find instances by tags: %v", err)
-				os.Exit(1)
-			}
-			logging.LogInfo("Stopping %d instances with tags '%s' in region: %s", len(instanceIDs), tagsFlag, region)
+		// Tag matches and explicit instance IDs are combined
+		instanceIDs, err := resolveTaggedTargets(ctx, awsClient, tagsFlag, instancesFlag)
+		if err != nil {
+			colors.PrintError("✗ %v\n", err)
+			logging.LogError("%v", err)
+			os.Exit(1)
 		}
+		logging.LogInfo("Stopping %d instances %s in region: %s", len(instanceIDs), describeTargets(tagsFlag, instancesFlag), region)
 
 		if len(instanceIDs) == 0 {
-			if instancesFlag != "" {
-				logging.LogInfo("No instances specified")
-			} else {
+			if tagsFlag != "" {
 				logging.LogInfo("No instances found with tags: %s", tagsFlag)
+			} else {
+				logging.LogInfo("No instances specified")
+			}
+			return
+		}
+
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			if err := previewPowerOperation(ctx, ssm.NewManager(logger), instanceIDs, region, "stop"); err != nil {
+				logging.LogError("Stop-tagged dry run failed: %v", err)
+				os.Exit(1)
 			}
 			return
 		}
 
+		if err := newProductionGuard(cmd).check(ctx, region, instanceIDs); err != nil {
+			logging.LogError("Stop-tagged cancelled: %v", err)
+			os.Exit(1)
+		}
+
 		// Create SSM manager for validation
 		ssmManager := ssm.NewManager(logger)
 
@@ -271,8 +321,9 @@ var ssmRebootTaggedCmd = &cobra.Command{
 	Long: `Reboot multiple running EC2 instances that match the specified tags.
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
 Use --tags flag to specify one or more tag filters in key=value format, separated by commas.
-Use --instances to explicitly specify instance IDs to target (comma-separated).
-Use --parallel to control maximum concurrent operations (default: number of CPU cores).
+Use --instances to explicitly specify instance IDs to target (comma-separated); with --tags they are added to the tag matches.
+Use --asg to target the InService and Standby instances of an Auto Scaling group.
+Use --parallel to control maximum concurrent operations (default: system.default_parallel, 10).
 
 Examples:
   ztictl ssm reboot-tagged --region cac1 --tags Environment=Production
@@ -282,10 +333,17 @@ Examples:
 		regionCode, _ := cmd.Flags().GetString("region")
 		tagsFlag, _ := cmd.Flags().GetString("tags")
 		instancesFlag, _ := cmd.Flags().GetString("instances")
-		parallelFlag, _ := cmd.Flags().GetInt("parallel")
+		parallelFlag := getParallelFlag(cmd)
 
 		region := resolveRegion(regionCode)
 
+		instancesFlag, err := resolveASGFlag(commandContext(), cmd, region, tagsFlag, instancesFlag)
+		if err != nil {
+			colors.PrintError("✗ %v\n", err)
+			logging.LogError("Failed to resolve --asg for reboot-tagged command: %v", err)
+			os.Exit(1)
+		}
+
 		// Validate arguments and flags
 		if err := validateTaggedCommandArgs(tagsFlag, instancesFlag, parallelFlag); err != nil {
 			colors.PrintError("✗ %v\n", err)
@@ -293,7 +351,7 @@ Examples:
 			os.Exit(1)
 		}
 
-		ctx := context.Background()
+		ctx := commandContext()
 		awsClient, err := aws.NewClient(ctx, aws.ClientOptions{Region: region})
 		if err != nil {
 			colors.PrintError("✗ Failed to create AWS client: %v\n", err)
@@ -301,35 +359,37 @@ Examples:
 			os.Exit(1)
 		}
 
-		var instanceIDs []string
-
-		if instancesFlag != "" {
-			// Use explicit instance IDs
-			instanceIDs = strings.Split(instancesFlag, ",")
-			for i, id := range instanceIDs {
-				instanceIDs[i] = strings.TrimSpace(id)
-			}
-			logging.LogInfo("Rebooting %d explicit instance IDs in region: %s", len(instanceIDs), region)
-		} else {
-			// Use tag filtering to find instances
-			instanceIDs, err = getInstanceIDsByTags(ctx, awsClient, tagsFlag)
-			if err != nil {
-				colors.PrintError("✗ Failed to find instances by tags: %v\n", err)
-				logging.LogError("Failed to find instances by tags: %v", err)
-				os.Exit(1)
-			}
-			logging.LogInfo("Rebooting %d instances with tags '%s' in region: %s", len(instanceIDs), tagsFlag, region)
+		// Tag matches and explicit instance IDs are combined
+		instanceIDs, err := resolveTaggedTargets(ctx, awsClient, tagsFlag, instancesFlag)
+		if err != nil {
+			colors.PrintError("✗ %v\n", err)
+			logging.LogError("%v", err)
+			os.Exit(1)
 		}
+		logging.LogInfo("Rebooting %d instances %s in region: %s", len(instanceIDs), describeTargets(tagsFlag, instancesFlag), region)
 
 		if len(instanceIDs) == 0 {
-			if instancesFlag != "" {
-				logging.LogInfo("No instances specified")
-			} else {
+			if tagsFlag != "" {
 				logging.LogInfo("No instances found with tags: %s", tagsFlag)
+			} else {
+				logging.LogInfo("No instances specified")
+			}
+			return
+		}
+
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			if err := previewPowerOperation(ctx, ssm.NewManager(logger), instanceIDs, region, "reboot"); err != nil {
+				logging.LogError("Reboot-tagged dry run failed: %v", err)
+				os.Exit(1)
 			}
 			return
 		}
 
+		if err := newProductionGuard(cmd).check(ctx, region, instanceIDs); err != nil {
+			logging.LogError("Reboot-tagged cancelled: %v", err)
+			os.Exit(1)
+		}
+
 		// Create SSM manager for validation
 		ssmManager := ssm.NewManager(logger)
 
@@ -347,16 +407,20 @@ Examples:
 
 // PowerOperationResult represents the result of a power operation on an instance
 type PowerOperationResult struct {
-	InstanceID string
-	Operation  string
-	Error      error
-	Duration   time.Duration
+	InstanceID    string
+	Operation     string
+	Error         error
+	Duration      time.Duration
+	PreviousState string // Instance state before the request, when reported by EC2
+	CurrentState  string // Instance state right after the request, when reported by EC2
 }
 
-// performPowerOperation handles power operations with fuzzy finder support
-func performPowerOperation(args []string, regionCode, instancesFlag string, parallelFlag int, operation string) error {
+// performPowerOperation handles power operations with fuzzy finder support.
+// With dryRun set, it only previews the state transitions; with wait enabled, it waits for the
+// instances to come online in SSM afterwards.
+func performPowerOperation(args []string, regionCode, instancesFlag string, parallelFlag int, operation string, dryRun bool, wait powerWait, guard *productionGuard) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 
 	// Case 1: Multiple instances via --instances flag
 	if instancesFlag != "" {
@@ -364,12 +428,24 @@ func performPowerOperation(args []string, regionCode, instancesFlag string, para
 			return fmt.Errorf("cannot specify both instance identifier and --instances flag")
 		}
 
+		if !validParallel(parallelFlag) {
+			return fmt.Errorf("--parallel must be greater than 0")
+		}
+
 		instanceIDs := strings.Split(instancesFlag, ",")
 		for i, id := range instanceIDs {
 			instanceIDs[i] = strings.TrimSpace(id)
 		}
 		logging.LogInfo("%s %d instances in region: %s", capitalize(operation), len(instanceIDs), region)
 
+		if dryRun {
+			return previewPowerOperation(ctx, ssm.NewManager(logger), instanceIDs, region, operation)
+		}
+
+		if err := guard.check(ctx, region, instanceIDs); err != nil {
+			return err
+		}
+
 		awsClient, err := aws.NewClient(ctx, aws.ClientOptions{Region: region})
 		if err != nil {
 			colors.PrintError("✗ Failed to create AWS client: %v\n", err)
@@ -382,7 +458,13 @@ func performPowerOperation(args []string, regionCode, instancesFlag string, para
 		startTime := time.Now()
 		results := executePowerOperationParallel(ctx, awsClient, ssmManager, instanceIDs, operation, parallelFlag, region)
 		totalDuration := time.Since(startTime)
-		return displayPowerOperationResults(results, operation, totalDuration, parallelFlag)
+		resultErr := displayPowerOperationResults(results, operation, totalDuration, parallelFlag)
+		if wait.Enabled {
+			if err := waitForSSMOnline(ctx, startedInstanceIDs(results), wait, ssmPingStatuses(ssmManager, region)); err != nil {
+				return err
+			}
+		}
+		return resultErr
 	}
 
 	// Case 2: Single instance (direct or fuzzy finder)
@@ -405,6 +487,10 @@ func performPowerOperation(args []string, regionCode, instancesFlag string, para
 
 	logging.LogInfo("%s instance %s in region: %s", capitalize(operation), instanceID, region)
 
+	if dryRun {
+		return previewPowerOperation(ctx, ssmManager, []string{instanceID}, region, operation)
+	}
+
 	// Validate instance state before attempting power operation
 	requirements, err := buildRequirementsForOperation(operation)
 	if err != nil {
@@ -413,6 +499,9 @@ func performPowerOperation(args []string, regionCode, instancesFlag string, para
 	if err := ValidateInstanceState(ctx, ssmManager, instanceID, region, requirements); err != nil {
 		return err
 	}
+	if err := guard.check(ctx, region, []string{instanceID}); err != nil {
+		return err
+	}
 
 	awsClient, err := aws.NewClient(ctx, aws.ClientOptions{Region: region})
 	if err != nil {
@@ -421,30 +510,20 @@ func performPowerOperation(args []string, regionCode, instancesFlag string, para
 	}
 
 	// Execute the power operation
-	switch operation {
-	case "start":
-		_, err = awsClient.EC2.StartInstances(ctx, &ec2.StartInstancesInput{
-			InstanceIds: []string{instanceID},
-		})
-	case "stop":
-		_, err = awsClient.EC2.StopInstances(ctx, &ec2.StopInstancesInput{
-			InstanceIds: []string{instanceID},
-		})
-	case "reboot":
-		_, err = awsClient.EC2.RebootInstances(ctx, &ec2.RebootInstancesInput{
-			InstanceIds: []string{instanceID},
-		})
-	default:
-		return fmt.Errorf("unknown operation: %s", operation)
-	}
-
+	previousState, currentState, err := requestPowerOperation(ctx, awsClient, instanceID, operation)
 	if err != nil {
 		colors.PrintError("✗ Failed to %s instance %s\n", operation, instanceID)
 		return fmt.Errorf("failed to %s instance: %w", operation, err)
 	}
 
 	colors.PrintSuccess("✓ Instance %s %s requested successfully\n", instanceID, operation)
+	if previousState != "" {
+		colors.PrintData("State: %s → %s\n", previousState, currentState)
+	}
 	logging.LogInfo("Instance %s requested successfully", operation)
+	if wait.Enabled {
+		return waitForSSMOnline(ctx, []string{instanceID}, wait, ssmPingStatuses(ssmManager, region))
+	}
 	return nil
 }
 
@@ -455,13 +534,8 @@ func validateTaggedCommandArgs(tagsFlag, instancesFlag string, parallelFlag int)
 		return fmt.Errorf("either --tags or --instances flag is required")
 	}
 
-	// Validate mutual exclusion - cannot specify both tags and instances
-	if tagsFlag != "" && instancesFlag != "" {
-		return fmt.Errorf("cannot specify both --tags and --instances flags")
-	}
-
 	// Validate parallel value
-	if parallelFlag <= 0 {
+	if !validParallel(parallelFlag) {
 		return fmt.Errorf("--parallel must be greater than 0")
 	}
 
@@ -498,20 +572,13 @@ func capitalize(s string) string {
 // getInstanceIDsByTags finds instance IDs by tag filters
 func getInstanceIDsByTags(ctx context.Context, awsClient *aws.Client, tagsFlag string) ([]string, error) {
 	// Parse tag filters
-	filters := make([]types.Filter, 0)
-	if tagsFlag != "" {
-		tagPairs := strings.Split(tagsFlag, ",")
-		for _, pair := range tagPairs {
-			parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
-			if len(parts) == 2 {
-				key := strings.TrimSpace(parts[0])
-				value := strings.TrimSpace(parts[1])
-				filters = append(filters, types.Filter{
-					Name:   awssdk.String("tag:" + key),
-					Values: []string{value},
-				})
-			}
-		}
+	filters, err := aws.TagFilters(tagsFlag)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --tags: %w", err)
+	}
+	if len(filters) == 0 {
+		// Without a filter DescribeInstances returns every instance in the region
+		return nil, fmt.Errorf("--tags does not contain any tag filters")
 	}
 
 	result, err := awsClient.EC2.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
@@ -531,99 +598,132 @@ func getInstanceIDsByTags(ctx context.Context, awsClient *aws.Client, tagsFlag s
 	return instanceIDs, nil
 }
 
-// executePowerOperationParallel runs power operations in parallel across multiple instances
+// requestPowerOperation sends a single power operation to EC2 and returns the state transition it reports.
+// Reboot requests report no states, so both are empty for them.
+func requestPowerOperation(ctx context.Context, awsClient *aws.Client, instanceID, operation string) (previousState, currentState string, err error) {
+	var changes []types.InstanceStateChange
+
+	switch operation {
+	case "start":
+		var output *ec2.StartInstancesOutput
+		output, err = awsClient.EC2.StartInstances(ctx, &ec2.StartInstancesInput{
+			InstanceIds: []string{instanceID},
+		})
+		if err == nil {
+			changes = output.StartingInstances
+		}
+	case "stop":
+		var output *ec2.StopInstancesOutput
+		output, err = awsClient.EC2.StopInstances(ctx, &ec2.StopInstancesInput{
+			InstanceIds: []string{instanceID},
+		})
+		if err == nil {
+			changes = output.StoppingInstances
+		}
+	case "reboot":
+		_, err = awsClient.EC2.RebootInstances(ctx, &ec2.RebootInstancesInput{
+			InstanceIds: []string{instanceID},
+		})
+	default:
+		err = fmt.Errorf("unknown operation: %s", operation)
+	}
+	if err == nil {
+		// Cached instance lists still show the old state
+		aws.InvalidateInstanceCache(awsClient.Config.Region)
+	}
+
+	if err != nil || len(changes) == 0 {
+		return "", "", err
+	}
+	if changes[0].PreviousState != nil {
+		previousState = string(changes[0].PreviousState.Name)
+	}
+	if changes[0].CurrentState != nil {
+		currentState = string(changes[0].CurrentState.Name)
+	}
+	return previousState, currentState, nil
+}
+
+// executePowerOperationParallel runs power operations across multiple instances, with at most
+// maxParallel in flight at once. Results are returned in the same order as instanceIDs.
 func executePowerOperationParallel(ctx context.Context, awsClient *aws.Client, ssmManager *ssm.Manager, instanceIDs []string, operation string, maxParallel int, region string) []PowerOperationResult {
-	// Create channels for work distribution and result collection
-	// Buffers sized to instance count for simplicity - memory scales linearly with instance count.
-	// For typical operations (< 1000 instances), memory overhead is negligible (~100KB).
-	// For very large deployments (> 10000 instances), consider refactoring to smaller buffers.
-	instanceChan := make(chan string, len(instanceIDs))
-	resultChan := make(chan PowerOperationResult, len(instanceIDs))
-
-	// Send instance IDs to work channel
-	for _, instanceID := range instanceIDs {
-		instanceChan <- instanceID
+	maxParallel = resolveParallel(maxParallel, len(instanceIDs))
+	if maxParallel < 1 {
+		maxParallel = 1
 	}
-	close(instanceChan)
 
-	// Start worker goroutines
+	results := make([]PowerOperationResult, len(instanceIDs))
+	semaphore := make(chan struct{}, maxParallel)
+
 	var wg sync.WaitGroup
-	for i := 0; i < maxParallel; i++ {
+	for i, instanceID := range instanceIDs {
 		wg.Add(1)
-		go func() {
+		semaphore <- struct{}{}
+		go func(i int, instanceID string) {
 			defer wg.Done()
-			for instanceID := range instanceChan {
-				startTime := time.Now()
-				logging.LogInfo("Executing %s operation on instance %s", operation, instanceID)
-
-				// Validate instance state before attempting operation
-				requirements, err := buildRequirementsForOperation(operation)
-
-				// Only proceed with validation and operation if no error yet
-				if err == nil {
-					err = ValidateInstanceState(ctx, ssmManager, instanceID, region, requirements)
-				}
-
-				// Execute power operation only if validation passed
-				if err == nil {
-					switch operation {
-					case "start":
-						_, err = awsClient.EC2.StartInstances(ctx, &ec2.StartInstancesInput{
-							InstanceIds: []string{instanceID},
-						})
-					case "stop":
-						_, err = awsClient.EC2.StopInstances(ctx, &ec2.StopInstancesInput{
-							InstanceIds: []string{instanceID},
-						})
-					case "reboot":
-						_, err = awsClient.EC2.RebootInstances(ctx, &ec2.RebootInstancesInput{
-							InstanceIds: []string{instanceID},
-						})
-					default:
-						err = fmt.Errorf("unknown operation: %s", operation)
-					}
-				}
-
-				duration := time.Since(startTime)
-
-				resultChan <- PowerOperationResult{
-					InstanceID: instanceID,
-					Operation:  operation,
-					Error:      err,
-					Duration:   duration,
-				}
+			defer func() { <-semaphore }()
+
+			startTime := time.Now()
+			logging.LogInfo("Executing %s operation on instance %s", operation, instanceID)
+
+			// Validate instance state before attempting operation
+			requirements, err := buildRequirementsForOperation(operation)
+			if err == nil {
+				err = ValidateInstanceState(ctx, ssmManager, instanceID, region, requirements)
 			}
-		}()
-	}
 
-	// Close result channel when all workers are done
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
+			// Execute power operation only if validation passed
+			var previousState, currentState string
+			if err == nil {
+				previousState, currentState, err = requestPowerOperation(ctx, awsClient, instanceID, operation)
+			}
 
-	// Collect all results
-	results := make([]PowerOperationResult, 0, len(instanceIDs))
-	for result := range resultChan {
-		results = append(results, result)
+			results[i] = PowerOperationResult{
+				InstanceID:    instanceID,
+				Operation:     operation,
+				Error:         err,
+				Duration:      time.Since(startTime),
+				PreviousState: previousState,
+				CurrentState:  currentState,
+			}
+			metrics.Record("power."+operation, region, results[i].Duration, err == nil)
+		}(i, instanceID)
 	}
+	wg.Wait()
 
 	return results
 }
 
-// displayPowerOperationResults displays the results of power operations and returns error if any operations failed
+// formatStateTransition describes a result's state change for display, or "-" when EC2 reported none
+func formatStateTransition(result PowerOperationResult) string {
+	if result.PreviousState == "" && result.CurrentState == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s → %s", result.PreviousState, result.CurrentState)
+}
+
+// displayPowerOperationResults displays the results of power operations sorted by instance ID
+// and returns an error if any operations failed
 func displayPowerOperationResults(results []PowerOperationResult, operation string, totalDuration time.Duration, maxParallel int) error {
+	maxParallel = resolveParallel(maxParallel, len(results))
+	sorted := make([]PowerOperationResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].InstanceID < sorted[j].InstanceID })
+
 	successCount := 0
-	for _, result := range results {
+	for _, result := range sorted {
 		fmt.Printf("\n")
 		colors.PrintHeader("=== Instance: %s ===\n", result.InstanceID)
 		colors.PrintHeader("Operation: %s\n", capitalize(operation))
-		colors.PrintData("Execution Time: %v\n", result.Duration.Round(time.Millisecond))
+		colors.PrintData("Execution Time: %s\n", format.Duration(result.Duration))
 
 		if result.Error != nil {
 			colors.PrintError("✗ Operation failed: %v\n", result.Error)
 		} else {
 			successCount++
+			if result.PreviousState != "" {
+				colors.PrintData("State: %s\n", formatStateTransition(result))
+			}
 			colors.PrintSuccess("✓ %s requested successfully\n", capitalize(operation))
 		}
 	}
@@ -631,15 +731,26 @@ func displayPowerOperationResults(results []PowerOperationResult, operation stri
 	// Summary
 	fmt.Printf("\n")
 	colors.PrintHeader("=== Operation Summary ===\n")
-	colors.PrintData("Total instances: %d\n", len(results))
+	if len(sorted) > 0 {
+		colors.PrintHeader("%-21s %-8s %-12s %s\n", "Instance", "Result", "Duration", "State")
+		for _, result := range sorted {
+			status := "ok"
+			if result.Error != nil {
+				status = "failed"
+			}
+			colors.PrintData("%-21s %-8s %-12s %s\n", result.InstanceID, status, format.Duration(result.Duration), formatStateTransition(result))
+		}
+		fmt.Printf("\n")
+	}
+	colors.PrintData("Total instances: %d\n", len(sorted))
 	colors.PrintData("Successful: %d\n", successCount)
-	colors.PrintData("Failed: %d\n", len(results)-successCount)
-	colors.PrintData("Total execution time: %v\n", totalDuration.Round(time.Millisecond))
+	colors.PrintData("Failed: %d\n", len(sorted)-successCount)
+	colors.PrintData("Total execution time: %s\n", format.Duration(totalDuration))
 	colors.PrintData("Max parallelism: %d\n", maxParallel)
 
-	if successCount < len(results) {
-		logging.LogWarn("Some %s operations failed: %d successful, %d failed", operation, successCount, len(results)-successCount)
-		return fmt.Errorf("some %s operations failed: %d successful, %d failed", operation, successCount, len(results)-successCount)
+	if successCount < len(sorted) {
+		logging.LogWarn("Some %s operations failed: %d successful, %d failed", operation, successCount, len(sorted)-successCount)
+		return fmt.Errorf("some %s operations failed: %d successful, %d failed", operation, successCount, len(sorted)-successCount)
 	} else {
 		logging.LogSuccess("All %s operations completed successfully", operation)
 		return nil
@@ -648,31 +759,48 @@ func displayPowerOperationResults(results []PowerOperationResult, operation stri
 
 func init() {
 	// Add flags for single instance commands
-	ssmStartCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmStartCmd)
+	addConfirmProductionFlag(ssmStartCmd)
+	addPowerDryRunFlag(ssmStartCmd)
+	addPowerWaitFlags(ssmStartCmd)
 	ssmStartCmd.Flags().StringP("instances", "i", "", "Comma-separated list of instance IDs to target explicitly")
-	ssmStartCmd.Flags().IntP("parallel", "p", runtime.NumCPU(), "Maximum number of concurrent operations")
+	addParallelFlag(ssmStartCmd, "Maximum number of concurrent operations")
 
-	ssmStopCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmStopCmd)
+	addConfirmProductionFlag(ssmStopCmd)
+	addPowerDryRunFlag(ssmStopCmd)
 	ssmStopCmd.Flags().StringP("instances", "i", "", "Comma-separated list of instance IDs to target explicitly")
-	ssmStopCmd.Flags().IntP("parallel", "p", runtime.NumCPU(), "Maximum number of concurrent operations")
+	addParallelFlag(ssmStopCmd, "Maximum number of concurrent operations")
 
-	ssmRebootCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmRebootCmd)
+	addConfirmProductionFlag(ssmRebootCmd)
+	addPowerDryRunFlag(ssmRebootCmd)
 	ssmRebootCmd.Flags().StringP("instances", "i", "", "Comma-separated list of instance IDs to target explicitly")
-	ssmRebootCmd.Flags().IntP("parallel", "p", runtime.NumCPU(), "Maximum number of concurrent operations")
+	addParallelFlag(ssmRebootCmd, "Maximum number of concurrent operations")
 
 	// Add flags for tagged commands
-	ssmStartTaggedCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
-	ssmStartTaggedCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, separated by commas")
+	addRegionFlag(ssmStartTaggedCmd)
+	addConfirmProductionFlag(ssmStartTaggedCmd)
+	addPowerDryRunFlag(ssmStartTaggedCmd)
+	addPowerWaitFlags(ssmStartTaggedCmd)
+	ssmStartTaggedCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, or key alone to match any value, separated by commas")
 	ssmStartTaggedCmd.Flags().StringP("instances", "i", "", "Comma-separated list of instance IDs to target explicitly")
-	ssmStartTaggedCmd.Flags().IntP("parallel", "p", runtime.NumCPU(), "Maximum number of concurrent operations")
+	addASGFlag(ssmStartTaggedCmd)
+	addParallelFlag(ssmStartTaggedCmd, "Maximum number of concurrent operations")
 
-	ssmStopTaggedCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
-	ssmStopTaggedCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, separated by commas")
+	addRegionFlag(ssmStopTaggedCmd)
+	addConfirmProductionFlag(ssmStopTaggedCmd)
+	addPowerDryRunFlag(ssmStopTaggedCmd)
+	ssmStopTaggedCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, or key alone to match any value, separated by commas")
 	ssmStopTaggedCmd.Flags().StringP("instances", "i", "", "Comma-separated list of instance IDs to target explicitly")
-	ssmStopTaggedCmd.Flags().IntP("parallel", "p", runtime.NumCPU(), "Maximum number of concurrent operations")
+	addASGFlag(ssmStopTaggedCmd)
+	addParallelFlag(ssmStopTaggedCmd, "Maximum number of concurrent operations")
 
-	ssmRebootTaggedCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
-	ssmRebootTaggedCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, separated by commas")
+	addRegionFlag(ssmRebootTaggedCmd)
+	addConfirmProductionFlag(ssmRebootTaggedCmd)
+	addPowerDryRunFlag(ssmRebootTaggedCmd)
+	ssmRebootTaggedCmd.Flags().StringP("tags", "t", "", "Tag filters in key=value format, or key alone to match any value, separated by commas")
 	ssmRebootTaggedCmd.Flags().StringP("instances", "i", "", "Comma-separated list of instance IDs to target explicitly")
-	ssmRebootTaggedCmd.Flags().IntP("parallel", "p", runtime.NumCPU(), "Maximum number of concurrent operations")
+	addASGFlag(ssmRebootTaggedCmd)
+	addParallelFlag(ssmRebootTaggedCmd, "Maximum number of concurrent operations")
 }
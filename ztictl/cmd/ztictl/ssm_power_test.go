@@ -2,12 +2,15 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"os"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -654,12 +657,11 @@ func TestValidateTaggedCommandArgs(t *testing.T) {
 			errorContains: "either --tags or --instances flag is required",
 		},
 		{
-			name:          "both tags and instances",
+			name:          "tags combined with instances",
 			tagsFlag:      "Environment=production",
 			instancesFlag: "i-1234567890abcdef0",
 			parallelFlag:  4,
-			expectError:   true,
-			errorContains: "cannot specify both --tags and --instances flags",
+			expectError:   false,
 		},
 		{
 			name:          "invalid parallel value - zero",
@@ -1089,3 +1091,53 @@ func TestGetInstanceIDsByTagsFormatting(t *testing.T) {
 		})
 	}
 }
+
+func TestExecutePowerOperationParallelKeepsInputOrder(t *testing.T) {
+	instanceIDs := []string{"i-0c", "i-0a", "i-0b", "i-0e", "i-0d"}
+
+	// An unknown operation fails validation before any AWS call, so no clients are needed
+	for _, maxParallel := range []int{0, 1, 2, 10} {
+		results := executePowerOperationParallel(context.Background(), nil, nil, instanceIDs, "hibernate", maxParallel, "ca-central-1")
+
+		if len(results) != len(instanceIDs) {
+			t.Fatalf("Expected %d results, got %d", len(instanceIDs), len(results))
+		}
+		for i, result := range results {
+			if result.InstanceID != instanceIDs[i] {
+				t.Errorf("parallel=%d: result %d is %s, want %s", maxParallel, i, result.InstanceID, instanceIDs[i])
+			}
+			if result.Error == nil {
+				t.Errorf("Expected unknown operation error for %s", result.InstanceID)
+			}
+		}
+	}
+}
+
+func TestDisplayPowerOperationResultsSortedWithStates(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	results := []PowerOperationResult{
+		{InstanceID: "i-0b", Operation: "start", Duration: 2 * time.Second, PreviousState: "stopped", CurrentState: "pending"},
+		{InstanceID: "i-0a", Operation: "start", Duration: time.Second, Error: errors.New("throttled")},
+	}
+
+	if err := displayPowerOperationResults(results, "start", 3*time.Second, 2); err == nil {
+		t.Error("Expected an error when one operation failed")
+	}
+	if results[0].InstanceID != "i-0b" {
+		t.Error("displayPowerOperationResults should not reorder the caller's slice")
+	}
+
+	output := buf.String()
+	if strings.Index(output, "=== Instance: i-0a") > strings.Index(output, "=== Instance: i-0b") {
+		t.Errorf("Expected results sorted by instance ID:\n%s", output)
+	}
+	if !strings.Contains(output, "stopped → pending") {
+		t.Errorf("Expected the state transition in the output:\n%s", output)
+	}
+	if !strings.Contains(output, "failed") || !strings.Contains(output, "1s") {
+		t.Errorf("Expected per-instance status and duration in the summary:\n%s", output)
+	}
+}
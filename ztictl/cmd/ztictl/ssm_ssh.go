@@ -1,7 +1,6 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -24,7 +23,7 @@ var ssmSSHCmd = &cobra.Command{
 This allows SSH access without opening port 22 or managing bastion hosts.
 
 If no instance identifier is provided, an interactive fuzzy finder will be launched.
-Instance identifier can be an instance ID (i-1234567890abcdef0) or instance name.
+Instance identifier can be an instance ID (i-1234567890abcdef0), instance name, private IP or private DNS name.
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
 
 Requirements:
@@ -95,7 +94,7 @@ Examples:
 // performSSHConnection handles SSH over SSM connection
 func performSSHConnection(regionCode, instanceIdentifier, user, identityFile string, extraArgs []string) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 	ssmManager := ssm.NewManager(logger)
 
 	// Use the shared instance selection logic
@@ -125,6 +124,11 @@ func performSSHConnection(regionCode, instanceIdentifier, user, identityFile str
 
 	logging.LogInfo("Starting SSH connection to %s@%s via SSM in region: %s", user, instanceID, region)
 
+	// The ProxyCommand runs the AWS CLI, which needs the Session Manager plugin
+	if err := ssm.CheckSessionManagerPlugin(); err != nil {
+		return err
+	}
+
 	// Build SSH command with ProxyCommand
 	sshCmd := getSSHCommand()
 	proxyCommand := buildProxyCommand(instanceID, region)
@@ -159,7 +163,7 @@ func performSSHConnection(regionCode, instanceIdentifier, user, identityFile str
 // generateSSHConfig generates an SSH config entry for SSM-based SSH access
 func generateSSHConfig(regionCode, instanceIdentifier, name, user, identityFile string, appendToConfig bool) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 	ssmManager := ssm.NewManager(logger)
 
 	// Use the shared instance selection logic
@@ -259,7 +263,7 @@ The command sets up port forwarding from a local port to the instance's RDP port
 then optionally launches your RDP client.
 
 If no instance identifier is provided, an interactive fuzzy finder will be launched.
-Instance identifier can be an instance ID (i-1234567890abcdef0) or instance name.
+Instance identifier can be an instance ID (i-1234567890abcdef0), instance name, private IP or private DNS name.
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
 
 Requirements:
@@ -293,7 +297,7 @@ Examples:
 // performRDPConnection handles RDP over SSM connection
 func performRDPConnection(regionCode, instanceIdentifier string, localPort int, launch bool) error {
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 	ssmManager := ssm.NewManager(logger)
 
 	// Use the shared instance selection logic
@@ -408,20 +412,20 @@ func getSSHCommand() string {
 
 func init() {
 	// SSH command flags
-	ssmSSHCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmSSHCmd)
 	ssmSSHCmd.Flags().StringP("user", "u", "", "SSH username (default: ec2-user)")
 	ssmSSHCmd.Flags().StringP("identity", "i", "", "Path to SSH private key file")
 	ssmSSHCmd.Flags().StringArrayP("ssh-arg", "o", []string{}, "Additional SSH arguments (can be specified multiple times)")
 
 	// SSH config command flags
-	ssmSSHConfigCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmSSHConfigCmd)
 	ssmSSHConfigCmd.Flags().StringP("name", "n", "", "Friendly name for SSH config entry (default: instance ID)")
 	ssmSSHConfigCmd.Flags().StringP("user", "u", "", "SSH username (default: ec2-user)")
 	ssmSSHConfigCmd.Flags().StringP("identity", "i", "", "Path to SSH private key file")
 	ssmSSHConfigCmd.Flags().BoolP("append", "a", false, "Append config entry to ~/.ssh/config")
 
 	// RDP command flags
-	ssmRDPCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	addRegionFlag(ssmRDPCmd)
 	ssmRDPCmd.Flags().IntP("local-port", "p", 33389, "Local port to forward (default: 33389)")
 	ssmRDPCmd.Flags().BoolP("launch", "l", false, "Automatically launch RDP client")
 }
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// ssmStaleCmd represents the ssm stale command
+var ssmStaleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "List instances whose SSM agent has not pinged recently",
+	Long: `List SSM-managed instances whose agent last pinged longer ago than --older-than, most stale first.
+These are usually instances whose agent needs a restart or that were terminated outside of normal tooling.
+Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
+
+Examples:
+  ztictl ssm stale --region cac1                        # Agents silent for more than 24 hours
+  ztictl ssm stale --region use1 --older-than 2h
+  ztictl ssm stale --region euw1 --older-than 168h --output json
+  ztictl ssm stale --region euw1 --output yaml`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		regionCode, _ := cmd.Flags().GetString("region")
+		olderThan, _ := cmd.Flags().GetDuration("older-than")
+		output, _ := cmd.Flags().GetString("output")
+
+		if err := performStaleReport(os.Stdout, regionCode, olderThan, output); err != nil {
+			exitWithError(cmd, 1, "Stale agent report failed", err)
+		}
+	},
+}
+
+// staleInstance is an SSM-managed instance whose agent last pinged before the staleness threshold
+type staleInstance struct {
+	InstanceID      string    `json:"instance_id"`
+	PingStatus      string    `json:"ping_status"`
+	LastPing        time.Time `json:"last_ping"`
+	StaleSeconds    int64     `json:"stale_seconds"`
+	SSMAgentVersion string    `json:"agent_version,omitempty"`
+	Platform        string    `json:"platform,omitempty"`
+}
+
+// performStaleReport lists instances with stale SSM agents in the given output format
+func performStaleReport(w io.Writer, regionCode string, olderThan time.Duration, output string) error {
+	if olderThan <= 0 {
+		return fmt.Errorf("--older-than must be greater than 0")
+	}
+	switch output {
+	case outputFormatText, outputFormatJSON, outputFormatYAML:
+	default:
+		return fmt.Errorf("invalid --output '%s' (expected text, json or yaml)", output)
+	}
+
+	region := resolveRegion(regionCode)
+	ssmManager := ssm.NewManager(logger)
+
+	statuses, err := ssmManager.ListInstanceStatuses(commandContext(), region)
+	if err != nil {
+		return fmt.Errorf("failed to list instance statuses: %w", err)
+	}
+
+	stale := findStaleInstances(statuses, olderThan, time.Now())
+
+	if output == outputFormatJSON || output == outputFormatYAML {
+		if stale == nil {
+			stale = []staleInstance{}
+		}
+		if output == outputFormatYAML {
+			return writeYAML(w, stale)
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stale)
+	}
+
+	if len(stale) == 0 {
+		colors.PrintSuccess("✓ No SSM agents in %s have been silent for more than %s\n", region, olderThan)
+		return nil
+	}
+	printStaleInstances(w, stale)
+	return nil
+}
+
+// findStaleInstances returns the instances whose last ping is older than olderThan, most stale first.
+// Instances without a parseable ping time are skipped.
+func findStaleInstances(instances []interactive.Instance, olderThan time.Duration, now time.Time) []staleInstance {
+	var stale []staleInstance
+	for _, instance := range instances {
+		lastPing, err := time.Parse(time.RFC3339, instance.LastPingDateTime)
+		if err != nil {
+			logging.LogDebug("Skipping instance %s: no valid last ping time (%q)", instance.InstanceID, instance.LastPingDateTime)
+			continue
+		}
+
+		age := now.Sub(lastPing)
+		if age <= olderThan {
+			continue
+		}
+		stale = append(stale, staleInstance{
+			InstanceID:      instance.InstanceID,
+			PingStatus:      instance.SSMStatus,
+			LastPing:        lastPing,
+			StaleSeconds:    int64(age.Seconds()),
+			SSMAgentVersion: instance.SSMAgentVersion,
+			Platform:        instance.Platform,
+		})
+	}
+
+	sort.SliceStable(stale, func(i, j int) bool {
+		if !stale[i].LastPing.Equal(stale[j].LastPing) {
+			return stale[i].LastPing.Before(stale[j].LastPing)
+		}
+		return stale[i].InstanceID < stale[j].InstanceID
+	})
+	return stale
+}
+
+// printStaleInstances prints stale instances as a table
+func printStaleInstances(w io.Writer, stale []staleInstance) {
+	formatter := NewTableFormatter(2)
+	ids := make([]string, len(stale))
+	statuses := make([]string, len(stale))
+	lastPings := make([]string, len(stale))
+	ages := make([]string, len(stale))
+	versions := make([]string, len(stale))
+
+	for i, instance := range stale {
+		ids[i] = instance.InstanceID
+		statuses[i] = instance.PingStatus
+		lastPings[i] = instance.LastPing.Local().Format("2006-01-02 15:04:05")
+		ages[i] = (time.Duration(instance.StaleSeconds) * time.Second).String()
+		versions[i] = instance.SSMAgentVersion
+	}
+
+	formatter.AddColumn("Instance ID", ids, 19)
+	formatter.AddColumn("Status", statuses, 6)
+	formatter.AddColumn("Last Ping", lastPings, 19)
+	formatter.AddColumn("Silent For", ages, 10)
+	formatter.AddColumn("Agent Version", versions, 13)
+
+	_, _ = fmt.Fprintf(w, "%s\n", colors.ColorHeader("%s", formatter.FormatHeader()))
+	for i := 0; i < formatter.GetRowCount(); i++ {
+		_, _ = fmt.Fprintf(w, "%s\n", formatter.FormatRow(i))
+	}
+	_, _ = fmt.Fprintf(w, "\n%d instance(s) with stale SSM agents\n", len(stale))
+}
+
+func init() {
+	addRegionFlag(ssmStaleCmd)
+	ssmStaleCmd.Flags().Duration("older-than", 24*time.Hour, "Report agents whose last ping is older than this (e.g. 2h, 24h, 168h)")
+	ssmStaleCmd.Flags().StringP("output", "o", "text", "Output format: text, json or yaml")
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/internal/interactive"
+)
+
+func TestFindStaleInstances(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	instances := []interactive.Instance{
+		{InstanceID: "i-fresh", LastPingDateTime: now.Add(-time.Hour).Format(time.RFC3339)},
+		{InstanceID: "i-week", LastPingDateTime: now.Add(-7 * 24 * time.Hour).Format(time.RFC3339), SSMStatus: "ConnectionLost"},
+		{InstanceID: "i-day", LastPingDateTime: now.Add(-30 * time.Hour).Format(time.RFC3339)},
+		{InstanceID: "i-unknown", LastPingDateTime: ""},
+	}
+
+	stale := findStaleInstances(instances, 24*time.Hour, now)
+	if len(stale) != 2 {
+		t.Fatalf("Expected 2 stale instances, got %+v", stale)
+	}
+	if stale[0].InstanceID != "i-week" || stale[1].InstanceID != "i-day" {
+		t.Errorf("Expected most stale first, got %s, %s", stale[0].InstanceID, stale[1].InstanceID)
+	}
+	if stale[1].StaleSeconds != int64((30 * time.Hour).Seconds()) {
+		t.Errorf("Unexpected staleness: %d", stale[1].StaleSeconds)
+	}
+
+	if got := findStaleInstances(instances, 30*24*time.Hour, now); len(got) != 0 {
+		t.Errorf("Expected no stale instances for a 30 day threshold, got %+v", got)
+	}
+}
+
+func TestPrintStaleInstances(t *testing.T) {
+	var buf bytes.Buffer
+	printStaleInstances(&buf, []staleInstance{{InstanceID: "i-week", PingStatus: "ConnectionLost", LastPing: time.Now().Add(-48 * time.Hour), StaleSeconds: 172800}})
+
+	output := buf.String()
+	for _, want := range []string{"Instance ID", "i-week", "ConnectionLost", "48h0m0s", "1 instance(s)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+
+	data, err := json.Marshal(staleInstance{InstanceID: "i-week", StaleSeconds: 10})
+	if err != nil || !strings.Contains(string(data), `"stale_seconds":10`) {
+		t.Errorf("Unexpected JSON: %s (%v)", data, err)
+	}
+}
+
+func TestPerformStaleReportValidation(t *testing.T) {
+	var buf bytes.Buffer
+	if err := performStaleReport(&buf, "cac1", 0, "text"); err == nil {
+		t.Error("Expected zero --older-than to be rejected")
+	}
+	if err := performStaleReport(&buf, "cac1", time.Hour, "xml"); err == nil {
+		t.Error("Expected unsupported --output to be rejected")
+	}
+}
@@ -1,12 +1,15 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"ztictl/internal/platform"
 	"ztictl/internal/ssm"
 	"ztictl/pkg/colors"
+	"ztictl/pkg/format"
 	"ztictl/pkg/logging"
 
 	"github.com/spf13/cobra"
@@ -28,12 +31,20 @@ var ssmUploadCmd = &cobra.Command{
 	Long: `Upload a local file to an EC2 instance via SSM.
 If no instance identifier is provided, an interactive fuzzy finder will be launched.
 Files are transferred directly for small files or via S3 for large files.
+Large S3 uploads are resumable: if one fails partway, running the same upload again continues
+from the parts already in S3 (use --no-resume to start over).
+With --recursive, the local path is a directory: every file under it is uploaded below the
+remote path, preserving structure. Symbolic links are skipped unless --follow-symlinks is set.
+--preserve-mode applies the local permission bits to uploaded files (chmod on Linux; on Windows
+only the read-only attribute is carried over).
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
 
 Examples:
   ztictl ssm transfer upload ./local.txt /remote/path.txt --region cac1       # Interactive fuzzy finder
-  ztictl ssm transfer upload i-1234567890abcdef0 ./local.txt /remote/path.txt --region cac1  # Specific instance`,
-	Args: cobra.RangeArgs(2, 3),
+  ztictl ssm transfer upload i-1234567890abcdef0 ./local.txt /remote/path.txt --region cac1  # Specific instance
+  ztictl ssm transfer upload i-1234567890abcdef0 ./deploy /opt/deploy --recursive --preserve-mode --region cac1  # Script bundle`,
+	Args:              cobra.RangeArgs(2, 3),
+	ValidArgsFunction: completeUploadArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		regionCode, _ := cmd.Flags().GetString("region")
 
@@ -50,7 +61,29 @@ Examples:
 			remotePath = args[1]
 		}
 
-		if err := performFileUpload(regionCode, instanceIdentifier, localFile, remotePath); err != nil {
+		recursive, _ := cmd.Flags().GetBool("recursive")
+		followSymlinks, _ := cmd.Flags().GetBool("follow-symlinks")
+		preserveMode, _ := cmd.Flags().GetBool("preserve-mode")
+		resume, err := resolveUploadResume(cmd)
+		if err != nil {
+			logging.LogError("%v", err)
+			os.Exit(1)
+		}
+		opts := ssm.UploadOptions{PreserveMode: preserveMode, Resume: resume}
+
+		if followSymlinks && !recursive {
+			logging.LogError("--follow-symlinks requires --recursive")
+			os.Exit(1)
+		}
+		if recursive {
+			if err := performDirectoryUpload(regionCode, instanceIdentifier, localFile, remotePath, followSymlinks, opts, newProductionGuard(cmd)); err != nil {
+				logging.LogError("Directory upload failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if err := performFileUpload(regionCode, instanceIdentifier, localFile, remotePath, opts, newProductionGuard(cmd)); err != nil {
 			logging.LogError("File upload failed: %v", err)
 			os.Exit(1)
 		}
@@ -64,15 +97,51 @@ var ssmDownloadCmd = &cobra.Command{
 	Long: `Download a file from an EC2 instance via SSM.
 If no instance identifier is provided, an interactive fuzzy finder will be launched.
 Files are transferred directly for small files or via S3 for large files.
+Use - as the local path to write the file to stdout; status messages then go to stderr.
+With --recursive, the remote path is a directory: every file under it is downloaded into the
+local directory, preserving structure. Trees larger than 1 GiB ask for confirmation (or --yes).
+With --tags, --instances or --asg, the file is downloaded from every target instead, up to --parallel
+at once, into <local-dir>/<instance-id>/. A failed download does not stop the others; a summary of
+sizes and durations follows, and the command exits with status 1 when any download failed.
 Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
 
 Examples:
   ztictl ssm transfer download /remote/file.txt ./local.txt --region cac1       # Interactive fuzzy finder
-  ztictl ssm transfer download i-1234567890abcdef0 /remote/file.txt ./local.txt --region cac1  # Specific instance`,
-	Args: cobra.RangeArgs(2, 3),
+  ztictl ssm transfer download i-1234567890abcdef0 /remote/file.txt ./local.txt --region cac1  # Specific instance
+  ztictl ssm transfer download i-1234567890abcdef0 /etc/hosts - --region cac1   # Print to stdout
+  ztictl ssm transfer download i-1234567890abcdef0 /var/log/app ./logs --recursive --region cac1  # Whole directory
+  ztictl ssm transfer download /var/log/app.log ./logs --tags Role=web --parallel 5 --region cac1  # From every web instance`,
+	Args:              cobra.RangeArgs(2, 3),
+	ValidArgsFunction: completeDownloadArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		regionCode, _ := cmd.Flags().GetString("region")
 
+		if err := validateDownloadTargetArgs(cmd, args); err != nil {
+			logging.LogError("Invalid arguments: %v", err)
+			os.Exit(1)
+		}
+		if hasDownloadTargets(cmd) {
+			tagsFlag, _ := cmd.Flags().GetString("tags")
+			instancesFlag, _ := cmd.Flags().GetString("instances")
+			output, _ := cmd.Flags().GetString("output")
+
+			region := resolveRegion(regionCode)
+			instancesFlag, err := resolveASGFlag(commandContext(), cmd, region, tagsFlag, instancesFlag)
+			if err != nil {
+				logging.LogError("Failed to resolve targets: %v", err)
+				os.Exit(1)
+			}
+			completed, err := performTargetedDownload(os.Stdout, region, tagsFlag, instancesFlag, getParallelFlag(cmd), args[0], args[1], output, newProductionGuard(cmd))
+			if err != nil {
+				logging.LogError("File download failed: %v", err)
+				os.Exit(1)
+			}
+			if !completed {
+				os.Exit(1)
+			}
+			return
+		}
+
 		var instanceIdentifier, remoteFile, localPath string
 		if len(args) == 3 {
 			// Old format: instance remote local
@@ -86,17 +155,39 @@ Examples:
 			localPath = args[1]
 		}
 
-		if err := performFileDownload(regionCode, instanceIdentifier, remoteFile, localPath); err != nil {
+		if recursive, _ := cmd.Flags().GetBool("recursive"); recursive {
+			if err := performDirectoryDownload(regionCode, instanceIdentifier, remoteFile, localPath, newProductionGuard(cmd), GetExecutionContext(cmd)); err != nil {
+				logging.LogError("Directory download failed: %v", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if err := performFileDownload(regionCode, instanceIdentifier, remoteFile, localPath, newProductionGuard(cmd)); err != nil {
 			logging.LogError("File download failed: %v", err)
 			os.Exit(1)
 		}
 	},
 }
 
+// resolveUploadResume reads --resume and --no-resume; resuming is on unless turned off
+func resolveUploadResume(cmd *cobra.Command) (bool, error) {
+	resume, _ := cmd.Flags().GetBool("resume")
+	noResume, _ := cmd.Flags().GetBool("no-resume")
+	if noResume && cmd.Flags().Changed("resume") && resume {
+		return false, fmt.Errorf("--resume and --no-resume cannot be combined")
+	}
+	return resume && !noResume, nil
+}
+
 // performFileUpload handles file upload logic and returns errors instead of calling os.Exit
-func performFileUpload(regionCode, instanceIdentifier, localFile, remotePath string) error {
+func performFileUpload(regionCode, instanceIdentifier, localFile, remotePath string, opts ssm.UploadOptions, guard *productionGuard) error {
+	if err := validateUploadSource(localFile, false); err != nil {
+		return err
+	}
+
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 	ssmManager := ssm.NewManager(logger)
 
 	// Use SelectInstanceWithFallback to handle both direct and fuzzy finder modes
@@ -109,25 +200,45 @@ func performFileUpload(regionCode, instanceIdentifier, localFile, remotePath str
 	if err != nil {
 		return fmt.Errorf("instance selection failed: %w", err)
 	}
+	if err := guard.check(ctx, region, []string{instanceID}); err != nil {
+		return err
+	}
 
 	logging.LogInfo("Uploading file %s to instance %s at path: %s", localFile, instanceID, remotePath)
+	recordHistory(historyOpTransferUpload, region, []string{instanceID}, localFile+" -> "+remotePath)
 
-	if err := ssmManager.UploadFile(ctx, instanceID, region, localFile, remotePath); err != nil {
+	op := ssm.FileTransferOperation{InstanceID: instanceID, Region: region, LocalPath: localFile, RemotePath: remotePath}
+	startTime := time.Now()
+	op.StartTime = &startTime
+	if err := ssmManager.UploadFileWithOptions(ctx, instanceID, region, localFile, remotePath, opts); err != nil {
 		colors.PrintError("✗ File upload failed: %s -> %s\n", localFile, remotePath)
 		return fmt.Errorf("file upload failed: %w", err)
 	}
+	endTime := time.Now()
+	op.EndTime = &endTime
+	if info, err := os.Stat(localFile); err == nil {
+		op.Size = info.Size()
+	}
 
 	logging.LogSuccess("File upload completed successfully")
 
 	// Show colored success message
-	colors.PrintSuccess("✓ File upload completed successfully: %s -> %s\n", localFile, remotePath)
+	colors.PrintSuccess("✓ File upload completed successfully: %s -> %s (%s)\n", localFile, remotePath, op.Stats())
 	return nil
 }
 
 // performFileDownload handles file download logic and returns errors instead of calling os.Exit
-func performFileDownload(regionCode, instanceIdentifier, remoteFile, localPath string) error {
+func performFileDownload(regionCode, instanceIdentifier, remoteFile, localPath string, guard *productionGuard) error {
+	// Keep stdout clean for the file content when downloading to "-"
+	if localPath == ssm.StdoutPath {
+		colors.SetOutput(os.Stderr)
+	}
+	if err := validateDownloadTarget(localPath, false); err != nil {
+		return err
+	}
+
 	region := resolveRegion(regionCode)
-	ctx := context.Background()
+	ctx := commandContext()
 	ssmManager := ssm.NewManager(logger)
 
 	// Use SelectInstanceWithFallback to handle both direct and fuzzy finder modes
@@ -140,25 +251,207 @@ func performFileDownload(regionCode, instanceIdentifier, remoteFile, localPath s
 	if err != nil {
 		return fmt.Errorf("instance selection failed: %w", err)
 	}
+	if err := guard.check(ctx, region, []string{instanceID}); err != nil {
+		return err
+	}
 
 	logging.LogInfo("Downloading file %s from instance %s to local path: %s", remoteFile, instanceID, localPath)
+	recordHistory(historyOpTransferDownload, region, []string{instanceID}, remoteFile+" -> "+localPath)
 
+	op := ssm.FileTransferOperation{InstanceID: instanceID, Region: region, LocalPath: localPath, RemotePath: remoteFile}
+	startTime := time.Now()
+	op.StartTime = &startTime
 	if err := ssmManager.DownloadFile(ctx, instanceID, region, remoteFile, localPath); err != nil {
 		colors.PrintError("✗ File download failed: %s -> %s\n", remoteFile, localPath)
 		return fmt.Errorf("file download failed: %w", err)
 	}
+	endTime := time.Now()
+	op.EndTime = &endTime
+	if localPath != ssm.StdoutPath {
+		if info, err := os.Stat(localPath); err == nil && !info.IsDir() {
+			op.Size = info.Size()
+		}
+	}
 
 	logging.LogSuccess("File download completed successfully")
 
 	// Show colored success message
-	colors.PrintSuccess("✓ File download completed successfully: %s -> %s\n", remoteFile, localPath)
+	colors.PrintSuccess("✓ File download completed successfully: %s -> %s (%s)\n", remoteFile, localPath, op.Stats())
 	return nil
 }
 
+// performDirectoryUpload uploads a local directory tree and returns errors instead of calling os.Exit
+func performDirectoryUpload(regionCode, instanceIdentifier, localDir, remoteDir string, followSymlinks bool, opts ssm.UploadOptions, guard *productionGuard) error {
+	if err := validateUploadSource(localDir, true); err != nil {
+		return err
+	}
+
+	files, skipped, err := ssm.ListLocalDirectory(localDir, followSymlinks)
+	if err != nil {
+		return err
+	}
+	if len(skipped) > 0 {
+		hint := ""
+		if !followSymlinks {
+			hint = " (use --follow-symlinks to upload link targets)"
+		}
+		colors.PrintWarning("Skipping %d symlinks or special files%s: %s\n", len(skipped), hint, strings.Join(skipped, ", "))
+	}
+	if len(files) == 0 {
+		colors.PrintWarning("No files found under %s\n", localDir)
+		return nil
+	}
+
+	region := resolveRegion(regionCode)
+	ctx := commandContext()
+	ssmManager := ssm.NewManager(logger)
+
+	instanceID, err := ssmManager.GetInstanceService().SelectInstanceWithFallback(ctx, instanceIdentifier, region, nil)
+	if err != nil {
+		return fmt.Errorf("instance selection failed: %w", err)
+	}
+	if err := guard.check(ctx, region, []string{instanceID}); err != nil {
+		return err
+	}
+
+	var total int64
+	for _, file := range files {
+		total += file.Size
+	}
+	colors.PrintData("Found %d files (%s) under %s\n", len(files), format.Bytes(total), localDir)
+
+	logging.LogInfo("Uploading directory %s to instance %s at path: %s", localDir, instanceID, remoteDir)
+	recordHistory(historyOpTransferUpload, region, []string{instanceID}, localDir+"/ -> "+remoteDir)
+
+	op := ssm.FileTransferOperation{InstanceID: instanceID, Region: region, LocalPath: localDir, RemotePath: remoteDir, Size: total}
+	startTime := time.Now()
+	op.StartTime = &startTime
+	if err := ssmManager.UploadDirectory(ctx, instanceID, region, remoteDir, files, opts); err != nil {
+		colors.PrintError("✗ Directory upload failed: %s -> %s\n", localDir, remoteDir)
+		return fmt.Errorf("directory upload failed: %w", err)
+	}
+	endTime := time.Now()
+	op.EndTime = &endTime
+
+	logging.LogSuccess("Directory upload completed successfully")
+	colors.PrintSuccess("✓ Directory upload completed successfully: %s -> %s (%d files, %s)\n", localDir, remoteDir, len(files), op.Stats())
+	return nil
+}
+
+// largeDirectoryDownloadSize is the total size above which a recursive download asks for confirmation
+const largeDirectoryDownloadSize = 1 << 30
+
+// totalRemoteSize sums the sizes of listed remote files
+func totalRemoteSize(files []platform.RemoteFile) int64 {
+	var total int64
+	for _, file := range files {
+		total += file.Size
+	}
+	return total
+}
+
+// performDirectoryDownload downloads a remote directory tree and returns errors instead of calling os.Exit
+func performDirectoryDownload(regionCode, instanceIdentifier, remoteDir, localDir string, guard *productionGuard, execCtx *ExecutionContext) error {
+	if localDir == ssm.StdoutPath {
+		return fmt.Errorf("--recursive cannot write to stdout; provide a local directory")
+	}
+	if err := validateDownloadTarget(localDir, true); err != nil {
+		return err
+	}
+
+	region := resolveRegion(regionCode)
+	ctx := commandContext()
+	ssmManager := ssm.NewManager(logger)
+
+	instanceID, err := ssmManager.GetInstanceService().SelectInstanceWithFallback(ctx, instanceIdentifier, region, nil)
+	if err != nil {
+		return fmt.Errorf("instance selection failed: %w", err)
+	}
+	if err := guard.check(ctx, region, []string{instanceID}); err != nil {
+		return err
+	}
+
+	files, err := ssmManager.ListRemoteDirectory(ctx, instanceID, region, remoteDir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		colors.PrintWarning("No files found under %s\n", remoteDir)
+		return nil
+	}
+
+	total := totalRemoteSize(files)
+	colors.PrintData("Found %d files (%s) under %s\n", len(files), format.Bytes(total), remoteDir)
+	if total > largeDirectoryDownloadSize {
+		confirmed, err := confirmAction(execCtx, fmt.Sprintf("Download %s from %s?", format.Bytes(total), instanceID))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return fmt.Errorf("download cancelled")
+		}
+	}
+
+	logging.LogInfo("Downloading directory %s from instance %s to local path: %s", remoteDir, instanceID, localDir)
+	recordHistory(historyOpTransferDownload, region, []string{instanceID}, remoteDir+"/ -> "+localDir)
+
+	op := ssm.FileTransferOperation{InstanceID: instanceID, Region: region, LocalPath: localDir, RemotePath: remoteDir, Size: total}
+	startTime := time.Now()
+	op.StartTime = &startTime
+	if err := ssmManager.DownloadDirectory(ctx, instanceID, region, localDir, files); err != nil {
+		colors.PrintError("✗ Directory download failed: %s -> %s\n", remoteDir, localDir)
+		return fmt.Errorf("directory download failed: %w", err)
+	}
+	endTime := time.Now()
+	op.EndTime = &endTime
+
+	logging.LogSuccess("Directory download completed successfully")
+	colors.PrintSuccess("✓ Directory download completed successfully: %s -> %s (%d files, %s)\n", remoteDir, localDir, len(files), op.Stats())
+	return nil
+}
+
+// ssmTransferLifecycleCmd represents the lifecycle subcommand
+var ssmTransferLifecycleCmd = &cobra.Command{
+	Use:   "lifecycle",
+	Short: "Re-apply the auto-cleanup lifecycle policy to the transfer bucket",
+	Long: `Re-apply the auto-cleanup lifecycle rule to the ztictl S3 transfer bucket in a region.
+Objects expire after system.s3_lifecycle_days (default: 1 day). Run this after changing
+that setting, or when the rule on an existing bucket was removed or modified.
+Region supports shortcuts: cac1 (ca-central-1), use1 (us-east-1), euw1 (eu-west-1), etc.
+
+Examples:
+  ztictl ssm transfer lifecycle --region cac1`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		regionCode, _ := cmd.Flags().GetString("region")
+		region := resolveRegion(regionCode)
+
+		bucketName, err := ssm.NewManager(logger).ReapplyLifecycleConfig(commandContext(), region)
+		if err != nil {
+			logging.LogError("Failed to re-apply lifecycle policy: %v", err)
+			os.Exit(1)
+		}
+
+		colors.PrintSuccess("✓ Lifecycle policy applied to %s\n", bucketName)
+	},
+}
+
 func init() {
 	ssmTransferCmd.AddCommand(ssmUploadCmd)
 	ssmTransferCmd.AddCommand(ssmDownloadCmd)
+	ssmTransferCmd.AddCommand(ssmTransferLifecycleCmd)
+
+	addRegionFlag(ssmUploadCmd)
+	addRegionFlag(ssmDownloadCmd)
+	addRegionFlag(ssmTransferLifecycleCmd)
+	addConfirmProductionFlag(ssmUploadCmd)
+	addConfirmProductionFlag(ssmDownloadCmd)
 
-	ssmUploadCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
-	ssmDownloadCmd.Flags().StringP("region", "r", "", "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config")
+	ssmUploadCmd.Flags().Bool("recursive", false, "Upload a local directory and everything under it, preserving structure")
+	ssmUploadCmd.Flags().Bool("follow-symlinks", false, "With --recursive, upload the targets of symbolic links instead of skipping them")
+	ssmUploadCmd.Flags().Bool("resume", true, "Continue an interrupted S3 upload of the same unchanged file, and keep a failed one open for the next run")
+	ssmUploadCmd.Flags().Bool("no-resume", false, "Start large uploads from scratch and abort them on failure")
+	ssmUploadCmd.Flags().Bool("preserve-mode", false, "Apply local file permissions to uploaded files (read-only attribute on Windows)")
+	ssmDownloadCmd.Flags().Bool("recursive", false, "Download a remote directory and everything under it, preserving structure")
+	addDownloadTargetFlags(ssmDownloadCmd)
 }
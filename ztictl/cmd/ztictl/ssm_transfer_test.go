@@ -3,11 +3,14 @@ package main
 import (
 	"bytes"
 	"context"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
 
+	"ztictl/internal/platform"
+	"ztictl/internal/ssm"
 	"ztictl/pkg/logging"
 
 	"github.com/spf13/cobra"
@@ -725,7 +728,7 @@ func TestPerformFileUpload(t *testing.T) {
 		}
 
 		// The function should return an error or succeed, not call os.Exit
-		err := performFileUpload("use1", "i-test123", "/tmp/testfile.txt", "/home/user/testfile.txt")
+		err := performFileUpload("use1", "i-test123", "/tmp/testfile.txt", "/home/user/testfile.txt", ssm.UploadOptions{}, nil)
 
 		// We expect this might fail (no AWS credentials/connection), but it shouldn't panic
 		// The important thing is that it returns an error instead of calling os.Exit
@@ -743,7 +746,7 @@ func TestPerformFileUpload(t *testing.T) {
 		}
 
 		// Test with empty region code (should be handled gracefully)
-		err := performFileUpload("", "i-test123", "/tmp/testfile.txt", "/home/user/testfile.txt")
+		err := performFileUpload("", "i-test123", "/tmp/testfile.txt", "/home/user/testfile.txt", ssm.UploadOptions{}, nil)
 
 		// Function should handle this gracefully and return error
 		if err != nil {
@@ -759,7 +762,7 @@ func TestPerformFileUpload(t *testing.T) {
 		}
 
 		// Test with empty local file path
-		err := performFileUpload("use1", "i-test123", "", "/home/user/testfile.txt")
+		err := performFileUpload("use1", "i-test123", "", "/home/user/testfile.txt", ssm.UploadOptions{}, nil)
 
 		// Function should handle this gracefully
 		if err != nil {
@@ -767,7 +770,7 @@ func TestPerformFileUpload(t *testing.T) {
 		}
 
 		// Test with empty remote path
-		err = performFileUpload("use1", "i-test123", "/tmp/testfile.txt", "")
+		err = performFileUpload("use1", "i-test123", "/tmp/testfile.txt", "", ssm.UploadOptions{}, nil)
 
 		if err != nil {
 			t.Logf("Expected error for empty remote path: %v", err)
@@ -789,7 +792,7 @@ func TestPerformFileDownload(t *testing.T) {
 		}
 
 		// The function should return an error or succeed, not call os.Exit
-		err := performFileDownload("use1", "i-test123", "/home/user/remotefile.txt", "/tmp/localfile.txt")
+		err := performFileDownload("use1", "i-test123", "/home/user/remotefile.txt", "/tmp/localfile.txt", nil)
 
 		// We expect this might fail (no AWS credentials/connection), but it shouldn't panic
 		// The important thing is that it returns an error instead of calling os.Exit
@@ -807,7 +810,7 @@ func TestPerformFileDownload(t *testing.T) {
 		}
 
 		// Test with empty region code (should be handled gracefully)
-		err := performFileDownload("", "i-test123", "/home/user/remotefile.txt", "/tmp/localfile.txt")
+		err := performFileDownload("", "i-test123", "/home/user/remotefile.txt", "/tmp/localfile.txt", nil)
 
 		// Function should handle this gracefully and return error
 		if err != nil {
@@ -823,7 +826,7 @@ func TestPerformFileDownload(t *testing.T) {
 		}
 
 		// Test with empty remote file path
-		err := performFileDownload("use1", "i-test123", "", "/tmp/localfile.txt")
+		err := performFileDownload("use1", "i-test123", "", "/tmp/localfile.txt", nil)
 
 		// Function should handle this gracefully
 		if err != nil {
@@ -831,7 +834,7 @@ func TestPerformFileDownload(t *testing.T) {
 		}
 
 		// Test with empty local path
-		err = performFileDownload("use1", "i-test123", "/home/user/remotefile.txt", "")
+		err = performFileDownload("use1", "i-test123", "/home/user/remotefile.txt", "", nil)
 
 		if err != nil {
 			t.Logf("Expected error for empty local path: %v", err)
@@ -856,7 +859,7 @@ func TestTransferSeparationOfConcerns(t *testing.T) {
 		}
 
 		// This call should return an error or succeed, not exit the process
-		err := performFileUpload("invalid-region", "invalid-instance", "/nonexistent/file.txt", "/remote/path")
+		err := performFileUpload("invalid-region", "invalid-instance", "/nonexistent/file.txt", "/remote/path", ssm.UploadOptions{}, nil)
 
 		// If we reach this line, the function didn't call os.Exit
 		// (which is what we want for good separation of concerns)
@@ -876,7 +879,7 @@ func TestTransferSeparationOfConcerns(t *testing.T) {
 		}
 
 		// This call should return an error or succeed, not exit the process
-		err := performFileDownload("invalid-region", "invalid-instance", "/remote/nonexistent.txt", "/tmp/local.txt")
+		err := performFileDownload("invalid-region", "invalid-instance", "/remote/nonexistent.txt", "/tmp/local.txt", nil)
 
 		// If we reach this line, the function didn't call os.Exit
 		if err == nil {
@@ -889,3 +892,73 @@ func TestTransferSeparationOfConcerns(t *testing.T) {
 		t.Log("Test completed - function returned instead of calling os.Exit")
 	})
 }
+
+func TestTotalRemoteSize(t *testing.T) {
+	files := []platform.RemoteFile{{Path: "a", Size: 1024}, {Path: "b/c", Size: 2048}}
+	if got := totalRemoteSize(files); got != 3072 {
+		t.Errorf("totalRemoteSize() = %d, want 3072", got)
+	}
+	if got := totalRemoteSize(nil); got != 0 {
+		t.Errorf("totalRemoteSize(nil) = %d, want 0", got)
+	}
+}
+
+func TestPerformDirectoryDownloadRejectsStdout(t *testing.T) {
+	if ssmDownloadCmd.Flags().Lookup("recursive") == nil {
+		t.Fatal("Expected --recursive on transfer download")
+	}
+
+	err := performDirectoryDownload("us-east-1", "i-1234567890abcdef0", "/var/log", "-", nil, &ExecutionContext{})
+	if err == nil || !strings.Contains(err.Error(), "stdout") {
+		t.Errorf("Expected a stdout error, got %v", err)
+	}
+}
+
+func TestPerformDirectoryUploadRequiresDirectory(t *testing.T) {
+	for _, name := range []string{"recursive", "follow-symlinks", "preserve-mode"} {
+		if ssmUploadCmd.Flags().Lookup(name) == nil {
+			t.Fatalf("Expected --%s on transfer upload", name)
+		}
+	}
+
+	file := filepath.Join(t.TempDir(), "deploy.sh")
+	if err := os.WriteFile(file, []byte("#!/bin/sh"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	err := performDirectoryUpload("us-east-1", "i-1234567890abcdef0", file, "/opt/deploy", false, ssm.UploadOptions{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "needs a directory") {
+		t.Errorf("Expected a directory error, got %v", err)
+	}
+}
+
+func TestResolveUploadResume(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    bool
+		wantErr bool
+	}{
+		{name: "default", want: true},
+		{name: "no-resume", args: []string{"--no-resume"}, want: false},
+		{name: "resume false", args: []string{"--resume=false"}, want: false},
+		{name: "both", args: []string{"--resume", "--no-resume"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			cmd.Flags().Bool("resume", true, "")
+			cmd.Flags().Bool("no-resume", false, "")
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			got, err := resolveUploadResume(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveUploadResume() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("resolveUploadResume() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,174 @@
 package main
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
 	"ztictl/internal/config"
-	awspkg "ztictl/pkg/aws"
+
+	"github.com/spf13/cobra"
 )
 
+// regionFlagUsage is the help text shared by every --region flag
+const regionFlagUsage = "AWS region or shortcode (cac1, use1, euw1, etc.) - default from config"
+
+// addRegionFlag registers the --region flag with shortcode completion and parse-time validation
+func addRegionFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("region", "r", "", regionFlagUsage)
+	_ = cmd.RegisterFlagCompletionFunc("region", completeRegionFlag)
+	if cmd.PreRunE == nil {
+		cmd.PreRunE = validateRegionFlag
+	}
+}
+
+// parallelAuto is the --parallel value for "auto": the worker count is picked once the number of
+// targets is known. It is out of the range of any count, so negative values stay invalid.
+const parallelAuto = math.MinInt32
+
+// autoParallelCap is the most workers --parallel auto starts
+const autoParallelCap = 20
+
+// parallelValue is the --parallel flag value, a positive number or "auto"
+type parallelValue int
+
+func (p *parallelValue) String() string {
+	if *p == parallelAuto {
+		return "auto"
+	}
+	return strconv.Itoa(int(*p))
+}
+
+func (p *parallelValue) Set(value string) error {
+	if strings.EqualFold(value, "auto") {
+		*p = parallelAuto
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("expected a number or auto")
+	}
+	*p = parallelValue(n)
+	return nil
+}
+
+func (p *parallelValue) Type() string {
+	return "int|auto"
+}
+
+// addParallelFlag registers --parallel; when it is not given, system.default_parallel applies
+func addParallelFlag(cmd *cobra.Command, usage string) {
+	value := parallelValue(0)
+	cmd.Flags().VarP(&value, "parallel", "p", usage+fmt.Sprintf(", or auto for one per target up to %d (default from system.default_parallel, 10 if unset)", autoParallelCap))
+	_ = cmd.RegisterFlagCompletionFunc("parallel", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"auto"}, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// getParallelFlag returns --parallel, or the configured default when the flag was not given.
+// The result is parallelAuto for --parallel auto; resolveParallel turns it into a worker count.
+func getParallelFlag(cmd *cobra.Command) int {
+	if cmd.Flags().Changed("parallel") {
+		return int(*cmd.Flags().Lookup("parallel").Value.(*parallelValue))
+	}
+	return defaultParallel()
+}
+
+// validParallel reports whether a --parallel value is a positive number or auto
+func validParallel(parallel int) bool {
+	return parallel > 0 || parallel == parallelAuto
+}
+
+// resolveParallel returns the worker count for a run on targets instances. Auto uses one worker per
+// target, capped at autoParallelCap, so small runs do not start an idle pool.
+func resolveParallel(parallel, targets int) int {
+	if parallel != parallelAuto {
+		return parallel
+	}
+	return max(1, min(targets, autoParallelCap))
+}
+
+// formatParallel describes a --parallel value before the targets are known
+func formatParallel(parallel int) string {
+	if parallel == parallelAuto {
+		return fmt.Sprintf("auto (up to %d)", autoParallelCap)
+	}
+	return strconv.Itoa(parallel)
+}
+
+// defaultParallel returns system.default_parallel, falling back to config.DefaultParallel
+func defaultParallel() int {
+	if parallel := config.Get().System.DefaultParallel; parallel > 0 {
+		return parallel
+	}
+	return config.DefaultParallel
+}
+
+// validateRegionFlag rejects an unknown --region value before any AWS call is made
+func validateRegionFlag(cmd *cobra.Command, args []string) error {
+	regionFlag, _ := cmd.Flags().GetString("region")
+	return validateRegionInput(regionFlag)
+}
+
+// validateRegionArgs returns a PreRunE that validates --region, when the command has one, and the
+// positional region arguments that regionArgs picks out, so a mistyped shortcode fails before any AWS call
+func validateRegionArgs(regionArgs func(cmd *cobra.Command, args []string) []string) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Lookup("region") != nil {
+			if err := validateRegionFlag(cmd, args); err != nil {
+				return err
+			}
+		}
+		for _, region := range regionArgs(cmd, args) {
+			if err := validateRegionInput(region); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// firstRegionArg picks out the region of commands whose first argument is always a region
+func firstRegionArg(cmd *cobra.Command, args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+	return args[:1]
+}
+
+// validateRegionInput checks that a region is a known shortcode or a full AWS region name.
+// An empty value is valid and falls back to the configured default region.
+func validateRegionInput(region string) error {
+	if region == "" {
+		return nil
+	}
+	if _, err := config.RegionResolver().Resolve(region); err != nil {
+		return fmt.Errorf("invalid region '%s': use a full region name (e.g. ca-central-1) or one of: %s (see 'ztictl regions')",
+			region, strings.Join(regionShortcodes(), ", "))
+	}
+	return nil
+}
+
+// regionShortcodes returns the known built-in and custom region shortcodes in sorted order
+func regionShortcodes() []string {
+	return config.RegionResolver().Shortcodes()
+}
+
+// completeRegionFlag completes --region with the known shortcodes and their region names
+func completeRegionFlag(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, mapping := range config.RegionResolver().List() {
+		if strings.HasPrefix(mapping.Shortcode, strings.ToLower(toComplete)) {
+			completions = append(completions, fmt.Sprintf("%s\t%s - %s", mapping.Shortcode, mapping.Region, mapping.Description))
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
 // Helper function to resolve region code to full region name
 // This is equivalent to the region resolution in 01_regions.sh
 func resolveRegion(regionCode string) string {
@@ -12,20 +176,32 @@ func resolveRegion(regionCode string) string {
 		return config.Get().DefaultRegion
 	}
 
-	// Handle both shortcodes and full region names
-	// First normalize to shortcode if it's a full name
-	normalized := config.NormalizeRegion(regionCode)
-
-	// Then convert shortcode to full AWS region name
-	if fullRegion, err := awspkg.GetRegion(normalized); err == nil {
+	// Handle both built-in and custom shortcodes as well as full region names
+	if fullRegion, err := config.RegionResolver().Resolve(regionCode); err == nil {
 		return fullRegion
 	}
 
-	// If it's not a known shortcode, check if it's already a valid full region
-	if isValidAWSRegion(regionCode) {
-		return regionCode
-	}
-
 	// If conversion fails, assume it's already a full region name
 	return regionCode
 }
+
+// confirmAction asks the user to confirm an action, honoring --yes and non-interactive mode
+func confirmAction(execCtx *ExecutionContext, prompt string) (bool, error) {
+	return confirmActionWithInput(execCtx, prompt, os.Stdin)
+}
+
+// confirmActionWithInput is confirmAction reading the answer from the given input
+func confirmActionWithInput(execCtx *ExecutionContext, prompt string, input io.Reader) (bool, error) {
+	if execCtx != nil && execCtx.AutoYes {
+		return true, nil
+	}
+	if execCtx != nil && execCtx.NonInteractive {
+		return false, fmt.Errorf("confirmation required in non-interactive mode, use --yes to proceed")
+	}
+
+	fmt.Printf("%s (yes/no): ", prompt)
+	response, _ := bufio.NewReader(input).ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+
+	return response == "yes" || response == "y", nil
+}
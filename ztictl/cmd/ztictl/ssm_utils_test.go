@@ -0,0 +1,203 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"ztictl/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+func TestConfirmActionWithInput(t *testing.T) {
+	tests := []struct {
+		name      string
+		execCtx   *ExecutionContext
+		input     string
+		want      bool
+		wantError bool
+	}{
+		{name: "auto yes skips prompt", execCtx: &ExecutionContext{AutoYes: true}, want: true},
+		{name: "auto yes wins over non-interactive", execCtx: &ExecutionContext{AutoYes: true, NonInteractive: true}, want: true},
+		{name: "non-interactive requires --yes", execCtx: &ExecutionContext{NonInteractive: true}, wantError: true},
+		{name: "answer yes", execCtx: &ExecutionContext{}, input: "yes\n", want: true},
+		{name: "answer y", execCtx: &ExecutionContext{}, input: "Y\n", want: true},
+		{name: "answer no", execCtx: &ExecutionContext{}, input: "no\n", want: false},
+		{name: "empty answer", execCtx: nil, input: "\n", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := confirmActionWithInput(tt.execCtx, "Proceed?", strings.NewReader(tt.input))
+			if (err != nil) != tt.wantError {
+				t.Fatalf("confirmActionWithInput() error = %v, wantError %v", err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("confirmActionWithInput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRegionInput(t *testing.T) {
+	for _, region := range []string{"", "cac1", "USE1", "us-east-1", "ap-southeast-2"} {
+		if err := validateRegionInput(region); err != nil {
+			t.Errorf("validateRegionInput(%q) unexpected error: %v", region, err)
+		}
+	}
+
+	for _, region := range []string{"use-1", "cac9", "moon-base-1"} {
+		err := validateRegionInput(region)
+		if err == nil {
+			t.Errorf("validateRegionInput(%q) expected error", region)
+			continue
+		}
+		if !strings.Contains(err.Error(), "cac1") || !strings.Contains(err.Error(), "use1") {
+			t.Errorf("Expected error to list valid shortcodes, got %v", err)
+		}
+	}
+}
+
+func TestAddRegionFlag(t *testing.T) {
+	cmd := &cobra.Command{Use: "test", Run: func(cmd *cobra.Command, args []string) {}}
+	addRegionFlag(cmd)
+
+	if cmd.Flags().Lookup("region") == nil || cmd.PreRunE == nil {
+		t.Fatal("Expected --region flag and PreRunE validation to be registered")
+	}
+
+	if err := cmd.Flags().Set("region", "use-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.PreRunE(cmd, nil); err == nil {
+		t.Error("Expected PreRunE to reject an invalid region")
+	}
+
+	for _, registered := range []*cobra.Command{ssmListCmd, ssmExecCmd, ssmConnectCmd, rdsListCmd} {
+		if registered.PreRunE == nil {
+			t.Errorf("Expected %s to validate --region at parse time", registered.CommandPath())
+		}
+	}
+}
+
+func TestValidateRegionArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		cmd       *cobra.Command
+		args      []string
+		wantError bool
+	}{
+		{name: "exec-tagged region", cmd: ssmExecTaggedCmd, args: []string{"cac1", "uptime"}},
+		{name: "exec-tagged full region name", cmd: ssmExecTaggedCmd, args: []string{"ca-central-1", "uptime"}},
+		{name: "exec-tagged mistyped region", cmd: ssmExecTaggedCmd, args: []string{"use-1", "uptime"}, wantError: true},
+		{name: "exec region instance command", cmd: ssmExecCmd, args: []string{"use1", "web-server", "uptime"}},
+		{name: "exec mistyped region", cmd: ssmExecCmd, args: []string{"cac9", "web-server", "uptime"}, wantError: true},
+		{name: "exec instance command", cmd: ssmExecCmd, args: []string{"web-server", "uptime"}},
+		{name: "exec-multi region list", cmd: ssmExecMultiCmd, args: []string{"cac1,us-east-1", "uptime"}},
+		{name: "exec-multi mistyped region in list", cmd: ssmExecMultiCmd, args: []string{"cac1,use-1", "uptime"}, wantError: true},
+		{name: "exec-multi command only", cmd: ssmExecMultiCmd, args: []string{"uptime"}},
+		{name: "cleanup region", cmd: cleanupCmd, args: []string{"euw1"}},
+		{name: "emergency-cleanup mistyped region", cmd: emergencyCleanupCmd, args: []string{"moon-base-1"}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cmd.PreRunE(tt.cmd, tt.args)
+			if (err != nil) != tt.wantError {
+				t.Errorf("PreRunE(%v) error = %v, wantError %v", tt.args, err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestExecRegionArgCountsCommandFlags(t *testing.T) {
+	cmd := &cobra.Command{Use: "exec"}
+	addRegionFlag(cmd)
+	addCommandFileFlag(cmd)
+	addCommandStepsFlags(cmd)
+	cmd.PreRunE = validateRegionArgs(execRegionArg)
+
+	if err := cmd.PreRunE(cmd, []string{"use-1", "web-server"}); err != nil {
+		t.Fatalf("Expected two arguments without a command flag to be an instance and command, got %v", err)
+	}
+	if err := cmd.Flags().Set("command-file", "steps.sh"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.PreRunE(cmd, []string{"use-1", "web-server"}); err == nil {
+		t.Error("Expected the region before the instance to be validated when --command-file supplies the command")
+	}
+}
+
+func TestGetParallelFlag(t *testing.T) {
+	system := &config.Get().System
+	original := system.DefaultParallel
+	defer func() { system.DefaultParallel = original }()
+
+	cmd := &cobra.Command{Use: "test"}
+	addParallelFlag(cmd, "Maximum number of concurrent operations")
+
+	system.DefaultParallel = 0
+	if got := getParallelFlag(cmd); got != config.DefaultParallel {
+		t.Errorf("Expected the built-in default %d when unset, got %d", config.DefaultParallel, got)
+	}
+
+	system.DefaultParallel = 32
+	if got := getParallelFlag(cmd); got != 32 {
+		t.Errorf("Expected system.default_parallel 32, got %d", got)
+	}
+
+	if err := cmd.Flags().Set("parallel", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if got := getParallelFlag(cmd); got != 3 {
+		t.Errorf("Expected --parallel to override the configured default, got %d", got)
+	}
+}
+
+func TestParallelAuto(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	addParallelFlag(cmd, "Maximum number of concurrent operations")
+
+	if err := cmd.Flags().Set("parallel", "auto"); err != nil {
+		t.Fatal(err)
+	}
+	parallel := getParallelFlag(cmd)
+	if parallel != parallelAuto || !validParallel(parallel) {
+		t.Fatalf("Expected --parallel auto to be accepted, got %d", parallel)
+	}
+
+	tests := []struct {
+		targets int
+		want    int
+	}{
+		{targets: 0, want: 1},
+		{targets: 3, want: 3},
+		{targets: autoParallelCap, want: autoParallelCap},
+		{targets: 500, want: autoParallelCap},
+	}
+	for _, tt := range tests {
+		if got := resolveParallel(parallel, tt.targets); got != tt.want {
+			t.Errorf("resolveParallel(auto, %d) = %d, expected %d", tt.targets, got, tt.want)
+		}
+	}
+	if got := resolveParallel(5, 500); got != 5 {
+		t.Errorf("Expected a fixed --parallel to be kept, got %d", got)
+	}
+
+	if err := cmd.Flags().Set("parallel", "many"); err == nil {
+		t.Error("Expected an error for a --parallel value that is neither a number nor auto")
+	}
+	if validParallel(0) {
+		t.Error("Expected --parallel 0 to be rejected")
+	}
+}
+
+func TestCompleteRegionFlag(t *testing.T) {
+	completions, directive := completeRegionFlag(nil, nil, "use")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("Expected no file completion, got %v", directive)
+	}
+	if len(completions) != 2 || !strings.HasPrefix(completions[0], "use1\tus-east-1") {
+		t.Errorf("Unexpected completions: %q", completions)
+	}
+}
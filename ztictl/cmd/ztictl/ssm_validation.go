@@ -29,8 +29,8 @@ type InstanceValidationRequirements struct {
 // ValidateInstanceState validates that an instance meets the requirements for an operation
 // It fetches instance details and checks both EC2 state and SSM agent status
 func ValidateInstanceState(ctx context.Context, ssmManager *ssm.Manager, instanceID, region string, requirements InstanceValidationRequirements) error {
-	// Get instance details
-	instances, err := ssmManager.GetInstanceService().ListInstances(ctx, region, &awsservice.ListFilters{})
+	// Get current instance details; a cached state could be stale
+	instances, err := ssmManager.GetInstanceService().ListInstances(awsservice.WithoutInstanceCache(ctx), region, &awsservice.ListFilters{})
 	if err != nil {
 		return fmt.Errorf("failed to fetch instance details: %w", err)
 	}
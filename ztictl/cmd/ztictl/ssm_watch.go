@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// DefaultWatchInterval is the default refresh interval for ssm watch
+const DefaultWatchInterval = 5 * time.Second
+
+// ssmWatchCmd represents the ssm watch command
+var ssmWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Continuously list EC2 instances with live SSM status refresh",
+	Long: `Continuously list EC2 instances and their SSM agent status, refreshing on an interval.
+Instances whose SSM agent came online since the previous refresh are highlighted.
+Supports the same filters as 'ztictl ssm list'. Press Ctrl+C to stop.
+
+Examples:
+  ztictl ssm watch --region cac1
+  ztictl ssm watch --region use1 --tag Environment=prod --interval 10s
+  ztictl ssm watch --region euw1 --name web --status running`,
+	Run: func(cmd *cobra.Command, args []string) {
+		regionCode, _ := cmd.Flags().GetString("region")
+		tagFilter, _ := cmd.Flags().GetString("tag")
+		statusFilter, _ := cmd.Flags().GetString("status")
+		nameFilter, _ := cmd.Flags().GetString("name")
+		interval, _ := cmd.Flags().GetDuration("interval")
+
+		filters := &ssm.ListFilters{
+			Tag:    tagFilter,
+			Status: statusFilter,
+			Name:   nameFilter,
+		}
+
+		if err := performInstanceWatch(regionCode, filters, interval); err != nil {
+			logging.LogError("Instance watch failed: %v", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// performInstanceWatch refreshes the instance list until interrupted
+func performInstanceWatch(regionCode string, filters *ssm.ListFilters, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0, got %v", interval)
+	}
+
+	region := resolveRegion(regionCode)
+	ctx, stop := signal.NotifyContext(commandContext(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ssmManager := ssm.NewManager(logger)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// previous is nil until the first successful refresh so nothing is highlighted initially
+	var previous map[string]string
+	for {
+		instances, err := ssmManager.ListInstances(ctx, region, filters)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil && previous == nil:
+			return fmt.Errorf("failed to list instances: %w", err)
+		case err != nil:
+			colors.PrintWarning("⚠ Refresh failed, retrying in %v: %v\n", interval, err)
+		default:
+			newlyOnline := findNewlyOnlineInstances(previous, instances)
+			clearScreen()
+			printWatchTable(instances, region, newlyOnline, interval)
+			previous = snapshotSSMStatuses(instances)
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Printf("\n")
+			colors.PrintData("Watch stopped\n")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// snapshotSSMStatuses maps instance IDs to their current SSM status
+func snapshotSSMStatuses(instances []interactive.Instance) map[string]string {
+	statuses := make(map[string]string, len(instances))
+	for _, instance := range instances {
+		statuses[instance.InstanceID] = instance.SSMStatus
+	}
+	return statuses
+}
+
+// findNewlyOnlineInstances returns the IDs of instances that are Online now but were not in the previous snapshot
+func findNewlyOnlineInstances(previous map[string]string, instances []interactive.Instance) map[string]bool {
+	newlyOnline := make(map[string]bool)
+	if previous == nil {
+		return newlyOnline
+	}
+
+	for _, instance := range instances {
+		if instance.SSMStatus == "Online" && previous[instance.InstanceID] != "Online" {
+			newlyOnline[instance.InstanceID] = true
+		}
+	}
+	return newlyOnline
+}
+
+// clearScreen clears the terminal and moves the cursor to the top-left corner
+func clearScreen() {
+	fmt.Print("\033[H\033[2J")
+}
+
+// printWatchTable prints a single watch refresh, highlighting newly online instances
+func printWatchTable(instances []interactive.Instance, region string, newlyOnline map[string]bool, interval time.Duration) {
+	sorted := make([]interactive.Instance, len(instances))
+	copy(sorted, instances)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].InstanceID < sorted[j].InstanceID
+	})
+
+	formatter := NewTableFormatter(2)
+
+	names := make([]string, len(sorted))
+	instanceIDs := make([]string, len(sorted))
+	privateIPs := make([]string, len(sorted))
+	states := make([]string, len(sorted))
+	ssmStatuses := make([]string, len(sorted))
+	online := 0
+
+	for i, instance := range sorted {
+		name := instance.Name
+		if name == "" {
+			name = "N/A"
+		}
+		names[i] = name
+		instanceIDs[i] = instance.InstanceID
+		privateIPs[i] = instance.PrivateIPAddress
+		states[i] = instance.State
+
+		if instance.SSMStatus == "Online" {
+			online++
+		}
+		if newlyOnline[instance.InstanceID] {
+			names[i] = colors.ColorSuccess("★ %s", name)
+			ssmStatuses[i] = colors.ColorSuccess("✓ Online (new)")
+		} else {
+			ssmStatuses[i] = formatSSMStatusShort(instance.SSMStatus)
+		}
+	}
+
+	formatter.AddColumn("Name", names, 8)
+	formatter.AddColumn("Instance ID", instanceIDs, 12)
+	formatter.AddColumn("Private IP", privateIPs, 10)
+	formatter.AddColumn("State", states, 8)
+	formatter.AddColumn("SSM Status", ssmStatuses, 10)
+
+	colors.PrintHeader("Watching EC2 instances in %s (every %v, Ctrl+C to stop)\n", region, interval)
+	colors.PrintData("Last refresh: %s\n\n", time.Now().Format("15:04:05"))
+
+	if len(sorted) == 0 {
+		colors.PrintWarning("⚠ No EC2 instances found in region: %s\n", region)
+		return
+	}
+
+	colors.PrintHeader("%s\n", formatter.FormatHeader())
+	for i := 0; i < formatter.GetRowCount(); i++ {
+		fmt.Printf("%s\n", formatter.FormatRow(i))
+	}
+
+	fmt.Printf("\n")
+	colors.PrintData("Total: %d instances, %d online", len(sorted), online)
+	if len(newlyOnline) > 0 {
+		fmt.Printf(", %s", colors.ColorSuccess("%d newly online", len(newlyOnline)))
+	}
+	fmt.Printf("\n")
+}
+
+func init() {
+	addRegionFlag(ssmWatchCmd)
+	ssmWatchCmd.Flags().StringP("tag", "t", "", "Filter by tag (format: key=value, or key to match any value)")
+	ssmWatchCmd.Flags().StringP("status", "s", "", "Filter by status (running, stopped, etc.)")
+	ssmWatchCmd.Flags().StringP("name", "n", "", "Filter by name pattern")
+	ssmWatchCmd.Flags().Duration("interval", DefaultWatchInterval, "Refresh interval (e.g. 5s, 1m)")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+)
+
+func TestSsmWatchCmdFlags(t *testing.T) {
+	for _, name := range []string{"region", "tag", "status", "name", "interval"} {
+		if ssmWatchCmd.Flags().Lookup(name) == nil {
+			t.Errorf("Expected flag --%s to be registered", name)
+		}
+	}
+
+	interval, err := ssmWatchCmd.Flags().GetDuration("interval")
+	if err != nil {
+		t.Fatalf("Failed to read interval flag: %v", err)
+	}
+	if interval != DefaultWatchInterval {
+		t.Errorf("Expected default interval %v, got %v", DefaultWatchInterval, interval)
+	}
+}
+
+func TestPerformInstanceWatchInvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		err := performInstanceWatch("cac1", &ssm.ListFilters{}, interval)
+		if err == nil || !strings.Contains(err.Error(), "interval must be greater than 0") {
+			t.Errorf("Expected interval validation error for %v, got %v", interval, err)
+		}
+	}
+}
+
+func TestFindNewlyOnlineInstances(t *testing.T) {
+	instances := []interactive.Instance{
+		{InstanceID: "i-already", SSMStatus: "Online"},
+		{InstanceID: "i-recovered", SSMStatus: "Online"},
+		{InstanceID: "i-launched", SSMStatus: "Online"},
+		{InstanceID: "i-pending", SSMStatus: ""},
+	}
+
+	t.Run("first refresh highlights nothing", func(t *testing.T) {
+		if got := findNewlyOnlineInstances(nil, instances); len(got) != 0 {
+			t.Errorf("Expected no highlights on first refresh, got %v", got)
+		}
+	})
+
+	t.Run("detects status transitions and new instances", func(t *testing.T) {
+		previous := map[string]string{
+			"i-already":   "Online",
+			"i-recovered": "ConnectionLost",
+			"i-pending":   "",
+		}
+
+		got := findNewlyOnlineInstances(previous, instances)
+		if len(got) != 2 || !got["i-recovered"] || !got["i-launched"] {
+			t.Errorf("Expected i-recovered and i-launched to be newly online, got %v", got)
+		}
+	})
+}
+
+func TestSnapshotSSMStatuses(t *testing.T) {
+	snapshot := snapshotSSMStatuses([]interactive.Instance{
+		{InstanceID: "i-1", SSMStatus: "Online"},
+		{InstanceID: "i-2", SSMStatus: "ConnectionLost"},
+	})
+
+	if len(snapshot) != 2 || snapshot["i-1"] != "Online" || snapshot["i-2"] != "ConnectionLost" {
+		t.Errorf("Unexpected snapshot: %v", snapshot)
+	}
+}
+
+func TestFormatSSMStatusShort(t *testing.T) {
+	tests := map[string]string{
+		"Online":         "Online",
+		"ConnectionLost": "Lost",
+		"No Agent":       "No Agent",
+		"":               "No Agent",
+		"Inactive":       "Inactive",
+	}
+
+	for status, want := range tests {
+		if got := stripAnsiCodes(formatSSMStatusShort(status)); !strings.Contains(got, want) {
+			t.Errorf("formatSSMStatusShort(%q) = %q, want it to contain %q", status, got, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/aws"
+	"ztictl/pkg/colors"
+)
+
+// splitInstanceIDs parses an --instances value, dropping blank entries
+func splitInstanceIDs(instancesFlag string) []string {
+	var instanceIDs []string
+	for _, id := range strings.Split(instancesFlag, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			instanceIDs = append(instanceIDs, id)
+		}
+	}
+	return instanceIDs
+}
+
+// mergeInstanceIDs returns the union of tagged and explicit instance IDs in that order, without duplicates
+func mergeInstanceIDs(tagged, explicit []string) []string {
+	seen := make(map[string]bool, len(tagged)+len(explicit))
+	var merged []string
+	for _, id := range append(append([]string(nil), tagged...), explicit...) {
+		if !seen[id] {
+			seen[id] = true
+			merged = append(merged, id)
+		}
+	}
+	return merged
+}
+
+// instanceLookup lists instances with their state and SSM agent status; tests replace it
+var instanceLookup = func(ctx context.Context, ssmManager *ssm.Manager, region string, filters *ssm.ListFilters) ([]interactive.Instance, error) {
+	return ssmManager.ListInstances(ctx, region, filters)
+}
+
+// mergeExplicitInstances adds the explicitly listed instances that the tag match did not already return
+func mergeExplicitInstances(tagged, explicit []interactive.Instance) []interactive.Instance {
+	seen := make(map[string]bool, len(tagged))
+	for _, instance := range tagged {
+		seen[instance.InstanceID] = true
+	}
+	merged := tagged
+	for _, instance := range explicit {
+		if !seen[instance.InstanceID] {
+			seen[instance.InstanceID] = true
+			merged = append(merged, instance)
+		}
+	}
+	return merged
+}
+
+// resolveExplicitInstances looks up --instances IDs in EC2 and SSM, so they carry the state and agent status
+// that execution checks, like tag matches do. IDs that do not exist in the region are reported and dropped.
+func resolveExplicitInstances(ctx context.Context, ssmManager *ssm.Manager, region string, instanceIDs []string) ([]interactive.Instance, error) {
+	if len(instanceIDs) == 0 {
+		return nil, nil
+	}
+	found, err := instanceLookup(ctx, ssmManager, region, &ssm.ListFilters{InstanceIDs: strings.Join(instanceIDs, ",")})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up instances: %w", err)
+	}
+	byID := make(map[string]interactive.Instance, len(found))
+	for _, instance := range found {
+		byID[instance.InstanceID] = instance
+	}
+
+	var resolved []interactive.Instance
+	var missing []string
+	for _, instanceID := range instanceIDs {
+		if instance, ok := byID[instanceID]; ok {
+			if instance.Name == "" {
+				instance.Name = instanceID
+			}
+			resolved = append(resolved, instance)
+		} else {
+			missing = append(missing, instanceID)
+		}
+	}
+	if len(missing) > 0 && !quiet {
+		colors.PrintWarning("⚠ Skipping %d instance(s) not found in region %s: %s\n", len(missing), region, strings.Join(missing, ", "))
+	}
+	return resolved, nil
+}
+
+// resolveTaggedTargets returns the instances matching tagsFlag together with those listed in
+// instancesFlag, so a tag-defined group can be extended without re-tagging. Either may be empty.
+func resolveTaggedTargets(ctx context.Context, awsClient *aws.Client, tagsFlag, instancesFlag string) ([]string, error) {
+	var tagged []string
+	if tagsFlag != "" {
+		var err error
+		tagged, err = getInstanceIDsByTags(ctx, awsClient, tagsFlag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find instances by tags: %w", err)
+		}
+	}
+	return mergeInstanceIDs(tagged, splitInstanceIDs(instancesFlag)), nil
+}
+
+// describeTargets summarizes the targeting flags for log messages
+func describeTargets(tagsFlag, instancesFlag string) string {
+	switch {
+	case tagsFlag != "" && instancesFlag != "":
+		return fmt.Sprintf("with tags '%s' plus explicit instance IDs", tagsFlag)
+	case tagsFlag != "":
+		return fmt.Sprintf("with tags '%s'", tagsFlag)
+	default:
+		return "from explicit instance IDs"
+	}
+}
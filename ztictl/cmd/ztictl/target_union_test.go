@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"ztictl/internal/interactive"
+	"ztictl/internal/ssm"
+	"ztictl/pkg/logging"
+
+	"github.com/fatih/color"
+)
+
+// fakeInstanceLookup replaces instanceLookup with a fixed set of instances, matched by tag or ID
+func fakeInstanceLookup(t *testing.T, instances []interactive.Instance) *[]ssm.ListFilters {
+	t.Helper()
+	original := instanceLookup
+	t.Cleanup(func() { instanceLookup = original })
+
+	var calls []ssm.ListFilters
+	instanceLookup = func(ctx context.Context, ssmManager *ssm.Manager, region string, filters *ssm.ListFilters) ([]interactive.Instance, error) {
+		calls = append(calls, *filters)
+		var matched []interactive.Instance
+		for _, instance := range instances {
+			switch {
+			case filters.InstanceIDs != "":
+				if slices.Contains(splitInstanceIDs(filters.InstanceIDs), instance.InstanceID) {
+					matched = append(matched, instance)
+				}
+			case filters.Tags != "":
+				key, value, _ := strings.Cut(filters.Tags, "=")
+				if instance.Tags[key] == value {
+					matched = append(matched, instance)
+				}
+			}
+		}
+		return matched, nil
+	}
+	return &calls
+}
+
+func TestSplitInstanceIDs(t *testing.T) {
+	got := splitInstanceIDs(" i-a, i-b,,i-c ")
+	if !reflect.DeepEqual(got, []string{"i-a", "i-b", "i-c"}) {
+		t.Errorf("splitInstanceIDs() = %v", got)
+	}
+	if got := splitInstanceIDs(""); got != nil {
+		t.Errorf("Expected no IDs for an empty flag, got %v", got)
+	}
+}
+
+func TestMergeInstanceIDs(t *testing.T) {
+	got := mergeInstanceIDs([]string{"i-a", "i-b"}, []string{"i-b", "i-c", "i-c"})
+	if !reflect.DeepEqual(got, []string{"i-a", "i-b", "i-c"}) {
+		t.Errorf("mergeInstanceIDs() = %v, want tag matches first without duplicates", got)
+	}
+	if got := mergeInstanceIDs(nil, []string{"i-x"}); !reflect.DeepEqual(got, []string{"i-x"}) {
+		t.Errorf("mergeInstanceIDs() with explicit IDs only = %v", got)
+	}
+}
+
+func TestMergeExplicitInstances(t *testing.T) {
+	tagged := []interactive.Instance{{InstanceID: "i-a", Name: "web-1", State: "running"}}
+	explicit := []interactive.Instance{{InstanceID: "i-a", Name: "i-a"}, {InstanceID: "i-b", Name: "i-b"}, {InstanceID: "i-b", Name: "i-b"}}
+	merged := mergeExplicitInstances(tagged, explicit)
+
+	if len(merged) != 2 {
+		t.Fatalf("Expected 2 instances, got %+v", merged)
+	}
+	if merged[0].Name != "web-1" {
+		t.Errorf("Expected the tag match's details to be kept, got %+v", merged[0])
+	}
+	if merged[1].InstanceID != "i-b" || merged[1].Name != "i-b" {
+		t.Errorf("Expected i-b once after the tag match, got %+v", merged[1])
+	}
+}
+
+func TestDescribeTargets(t *testing.T) {
+	tests := []struct {
+		tags, instances, want string
+	}{
+		{"Env=staging", "", "with tags 'Env=staging'"},
+		{"", "i-a", "from explicit instance IDs"},
+		{"Env=staging", "i-a", "with tags 'Env=staging' plus explicit instance IDs"},
+	}
+	for _, tt := range tests {
+		if got := describeTargets(tt.tags, tt.instances); got != tt.want {
+			t.Errorf("describeTargets(%q, %q) = %q, want %q", tt.tags, tt.instances, got, tt.want)
+		}
+	}
+}
+
+func TestResolveExplicitInstances(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	defer func() { color.Output = os.Stdout }()
+
+	fakeInstanceLookup(t, []interactive.Instance{
+		{InstanceID: "i-b", Name: "db-1", State: "running", SSMStatus: "Online"},
+		{InstanceID: "i-a", State: "stopped", SSMStatus: "ConnectionLost"},
+	})
+
+	resolved, err := resolveExplicitInstances(context.Background(), nil, "ca-central-1", []string{"i-a", "i-missing", "i-b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interactive.Instance{
+		{InstanceID: "i-a", Name: "i-a", State: "stopped", SSMStatus: "ConnectionLost"},
+		{InstanceID: "i-b", Name: "db-1", State: "running", SSMStatus: "Online"},
+	}
+	if !reflect.DeepEqual(resolved, want) {
+		t.Errorf("resolveExplicitInstances() = %+v, want %+v in flag order", resolved, want)
+	}
+	if !strings.Contains(buf.String(), "Skipping 1 instance(s) not found in region ca-central-1: i-missing") {
+		t.Errorf("Expected the unknown ID to be reported, got %q", buf.String())
+	}
+}
+
+func TestResolveExecTaggedInstancesUnion(t *testing.T) {
+	if logger == nil {
+		logger = logging.NewLogger(false)
+	}
+	fakeInstanceLookup(t, []interactive.Instance{
+		{InstanceID: "i-web", Name: "web-1", State: "running", SSMStatus: "Online", Tags: map[string]string{"Environment": "staging"}},
+		{InstanceID: "i-new", Name: "new-1", State: "running", SSMStatus: "Online"},
+	})
+
+	opts := execOptions{}
+	instances, err := resolveExecTaggedInstances(context.Background(), nil, "ca-central-1", "uptime", "Environment=staging", "i-new", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 2 || instances[1].InstanceID != "i-new" {
+		t.Fatalf("Expected the tag match plus i-new, got %+v", instances)
+	}
+	for _, instance := range instances {
+		if reason := execSkipReason(instance, opts); reason != "" {
+			t.Errorf("Expected %s to be executed, got skipped: %s", instance.InstanceID, reason)
+		}
+	}
+
+	count, err := countTargets(context.Background(), nil, "ca-central-1", instances, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count.Targets != 2 || count.Skipped != 0 {
+		t.Errorf("Expected both instances to be targeted, got %+v", count)
+	}
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"ztictl/internal/ssm"
+	"ztictl/pkg/aws"
+	"ztictl/pkg/colors"
+	"ztictl/pkg/format"
+	"ztictl/pkg/logging"
+
+	"github.com/spf13/cobra"
+)
+
+// instanceDownloadFunc downloads remoteFile from one instance to localPath
+type instanceDownloadFunc func(ctx context.Context, instanceID, localPath string) (ssm.FileTransferOperation, error)
+
+// addDownloadTargetFlags registers the flags that download one file from many instances
+func addDownloadTargetFlags(cmd *cobra.Command) {
+	cmd.Flags().StringP("tags", "t", "", "Download from the instances matching these tag filters (key=value, or key alone to match any value, separated by commas)")
+	cmd.Flags().StringP("instances", "i", "", "Comma-separated list of instance IDs to download from")
+	addASGFlag(cmd)
+	addParallelFlag(cmd, "Maximum number of downloads running at once with --tags, --instances or --asg")
+	cmd.Flags().StringP("output", "o", "text", "Summary format with --tags, --instances or --asg: text, json or yaml")
+}
+
+// hasDownloadTargets reports whether a download names its instances with --tags, --instances or --asg
+func hasDownloadTargets(cmd *cobra.Command) bool {
+	for _, name := range []string{"tags", "instances", "asg"} {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Value.String() != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateDownloadTargetArgs checks the arguments and flags of a download from a target set, or rejects
+// the target-only flags when a single instance is used
+func validateDownloadTargetArgs(cmd *cobra.Command, args []string) error {
+	if !hasDownloadTargets(cmd) {
+		for _, name := range []string{"parallel", "output"} {
+			if cmd.Flags().Changed(name) {
+				return fmt.Errorf("--%s requires --tags, --instances or --asg", name)
+			}
+		}
+		return nil
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("with --tags, --instances or --asg, give only <remote-file> <local-dir>")
+	}
+	if recursive, _ := cmd.Flags().GetBool("recursive"); recursive {
+		return fmt.Errorf("--recursive cannot be combined with --tags, --instances or --asg")
+	}
+	return nil
+}
+
+// targetDownloadPath returns where the file of an instance is written: <local-dir>/<instance-id>/<file name>
+func targetDownloadPath(localDir, instanceID, remoteFile string) string {
+	return filepath.Join(localDir, instanceID, remoteBaseName(remoteFile))
+}
+
+// performTargetedDownload downloads remoteFile from every tag and explicit instance target in a region
+// into a subdirectory of localDir per instance, then prints a summary in the given output format.
+// A failed download, such as a file missing on one instance, is reported in the summary without
+// stopping the others. It reports whether every download completed.
+func performTargetedDownload(w io.Writer, region, tagsFlag, instancesFlag string, parallel int, remoteFile, localDir, output string, guard *productionGuard) (bool, error) {
+	switch output {
+	case outputFormatText, outputFormatJSON, outputFormatYAML:
+	default:
+		return false, fmt.Errorf("invalid --output '%s' (expected text, json or yaml)", output)
+	}
+	if err := validateTaggedCommandArgs(tagsFlag, instancesFlag, parallel); err != nil {
+		return false, err
+	}
+	if remoteBaseName(remoteFile) == "" {
+		return false, fmt.Errorf("remote path %s is a directory; give the path of a file", remoteFile)
+	}
+	if localDir == ssm.StdoutPath {
+		return false, fmt.Errorf("downloads from several instances cannot write to stdout; provide a local directory")
+	}
+	if err := validateDownloadTarget(localDir, true); err != nil {
+		return false, err
+	}
+
+	ctx := commandContext()
+	awsClient, err := aws.NewClient(ctx, aws.ClientOptions{Region: region})
+	if err != nil {
+		return false, fmt.Errorf("failed to create AWS client: %w", err)
+	}
+	instanceIDs, err := resolveTaggedTargets(ctx, awsClient, tagsFlag, instancesFlag)
+	if err != nil {
+		return false, err
+	}
+	if len(instanceIDs) == 0 {
+		return false, fmt.Errorf("no instances found %s in %s", describeTargets(tagsFlag, instancesFlag), region)
+	}
+	if err := guard.check(ctx, region, instanceIDs); err != nil {
+		return false, err
+	}
+
+	logging.LogInfo("Downloading %s from %d instance(s) %s to %s", remoteFile, len(instanceIDs), describeTargets(tagsFlag, instancesFlag), localDir)
+	recordHistory(historyOpTransferDownload, region, instanceIDs, remoteFile+" -> "+filepath.Join(localDir, "<instance-id>")+string(filepath.Separator))
+
+	ssmManager := ssm.NewManager(logger)
+	startTime := time.Now()
+	ops := downloadFromInstances(ctx, instanceIDs, remoteFile, localDir, parallel, func(ctx context.Context, instanceID, localPath string) (ssm.FileTransferOperation, error) {
+		return ssmManager.DownloadFileOperation(ctx, instanceID, region, remoteFile, localPath)
+	})
+	totalDuration := time.Since(startTime)
+
+	completed := true
+	for _, op := range ops {
+		completed = completed && op.Status == ssm.TransferStatusCompleted
+	}
+
+	switch output {
+	case outputFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return completed, encoder.Encode(ops)
+	case outputFormatYAML:
+		return completed, writeYAML(w, ops)
+	}
+	printDownloadSummary(w, ops, totalDuration)
+	return completed, nil
+}
+
+// downloadFromInstances downloads remoteFile from each instance with at most parallel downloads at once.
+// Operations are returned in the same order as instanceIDs, failed ones included.
+func downloadFromInstances(ctx context.Context, instanceIDs []string, remoteFile, localDir string, parallel int, download instanceDownloadFunc) []ssm.FileTransferOperation {
+	parallel = max(1, resolveParallel(parallel, len(instanceIDs)))
+
+	ops := make([]ssm.FileTransferOperation, len(instanceIDs))
+	semaphore := make(chan struct{}, parallel)
+
+	var wg sync.WaitGroup
+	for i, instanceID := range instanceIDs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, instanceID string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			localPath := targetDownloadPath(localDir, instanceID, remoteFile)
+			if err := os.MkdirAll(filepath.Dir(localPath), 0700); err != nil {
+				ops[i] = ssm.FileTransferOperation{InstanceID: instanceID, LocalPath: localPath, RemotePath: remoteFile,
+					Status: ssm.TransferStatusFailed, ErrorMessage: fmt.Sprintf("failed to create %s: %v", filepath.Dir(localPath), err)}
+				return
+			}
+
+			logging.LogInfo("Downloading %s from instance %s to %s", remoteFile, instanceID, localPath)
+			op, err := download(ctx, instanceID, localPath)
+			if err != nil {
+				op.Status = ssm.TransferStatusFailed
+				op.ErrorMessage = err.Error()
+			}
+			if op.InstanceID == "" {
+				op.InstanceID = instanceID
+			}
+			ops[i] = op
+		}(i, instanceID)
+	}
+	wg.Wait()
+
+	return ops
+}
+
+// printDownloadSummary prints download operations as a table followed by their totals
+func printDownloadSummary(w io.Writer, ops []ssm.FileTransferOperation, totalDuration time.Duration) {
+	formatter := NewTableFormatter(2)
+	ids := make([]string, len(ops))
+	statuses := make([]string, len(ops))
+	sizes := make([]string, len(ops))
+	durations := make([]string, len(ops))
+	details := make([]string, len(ops))
+
+	completed := 0
+	var totalSize int64
+	for i, op := range ops {
+		ids[i] = op.InstanceID
+		statuses[i] = op.Status
+		sizes[i] = "-"
+		durations[i] = "-"
+		details[i] = op.ErrorMessage
+		if op.StartTime != nil && op.EndTime != nil {
+			durations[i] = format.Duration(op.EndTime.Sub(*op.StartTime))
+		}
+		if op.Status == ssm.TransferStatusCompleted {
+			completed++
+			totalSize += op.Size
+			sizes[i] = format.Bytes(op.Size)
+			details[i] = op.LocalPath
+		}
+	}
+
+	formatter.AddColumn("Instance ID", ids, 19)
+	formatter.AddColumn("Status", statuses, 6)
+	formatter.AddColumn("Size", sizes, 4)
+	formatter.AddColumn("Duration", durations, 8)
+	formatter.AddColumn("Local Path / Error", details, 18)
+
+	_, _ = fmt.Fprintf(w, "%s\n", colors.ColorHeader("%s", formatter.FormatHeader()))
+	for i := 0; i < formatter.GetRowCount(); i++ {
+		_, _ = fmt.Fprintf(w, "%s\n", formatter.FormatRow(i))
+	}
+
+	summary := fmt.Sprintf("\n%d of %d download(s) completed (%s in %s)\n", completed, len(ops), format.Bytes(totalSize), format.Duration(totalDuration))
+	if completed == len(ops) {
+		_, _ = fmt.Fprint(w, colors.ColorSuccess("%s", summary))
+	} else {
+		_, _ = fmt.Fprint(w, colors.ColorError("%s", summary))
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"ztictl/internal/ssm"
+
+	"github.com/spf13/cobra"
+)
+
+func TestValidateDownloadTargetArgs(t *testing.T) {
+	newCmd := func(flags ...string) *cobra.Command {
+		cmd := &cobra.Command{Use: "download"}
+		cmd.Flags().Bool("recursive", false, "")
+		addDownloadTargetFlags(cmd)
+		if err := cmd.Flags().Parse(flags); err != nil {
+			t.Fatal(err)
+		}
+		return cmd
+	}
+
+	tests := []struct {
+		name    string
+		flags   []string
+		args    []string
+		wantErr string
+	}{
+		{name: "single instance", args: []string{"i-1", "/etc/hosts", "hosts"}},
+		{name: "tags", flags: []string{"--tags", "Role=web"}, args: []string{"/var/log/app.log", "logs"}},
+		{name: "asg", flags: []string{"--asg", "web-asg", "--parallel", "auto"}, args: []string{"/var/log/app.log", "logs"}},
+		{name: "instance argument with targets", flags: []string{"--instances", "i-1,i-2"}, args: []string{"i-1", "/var/log/app.log", "logs"}, wantErr: "give only <remote-file> <local-dir>"},
+		{name: "recursive with targets", flags: []string{"--tags", "Role=web", "--recursive"}, args: []string{"/var/log", "logs"}, wantErr: "--recursive cannot be combined"},
+		{name: "parallel without targets", flags: []string{"--parallel", "5"}, args: []string{"i-1", "/etc/hosts", "hosts"}, wantErr: "--parallel requires"},
+		{name: "output without targets", flags: []string{"--output", "json"}, args: []string{"/etc/hosts", "hosts"}, wantErr: "--output requires"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDownloadTargetArgs(newCmd(tt.flags...), tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	if ssmDownloadCmd.Flags().Lookup("tags") == nil || ssmDownloadCmd.Flags().Lookup("parallel") == nil || ssmDownloadCmd.Flags().Lookup("asg") == nil {
+		t.Error("Expected --tags, --asg and --parallel flags on download")
+	}
+}
+
+func TestDownloadFromInstances(t *testing.T) {
+	localDir := t.TempDir()
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	download := func(ctx context.Context, instanceID, localPath string) (ssm.FileTransferOperation, error) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		startTime := time.Now()
+		op := ssm.FileTransferOperation{InstanceID: instanceID, LocalPath: localPath, StartTime: &startTime}
+		if instanceID == "i-missing" {
+			return op, fmt.Errorf("failed to get remote file size: No such file or directory")
+		}
+		if err := os.WriteFile(localPath, []byte(instanceID), 0600); err != nil {
+			return op, err
+		}
+		op.Size = int64(len(instanceID))
+		op.Status = ssm.TransferStatusCompleted
+		return op, nil
+	}
+
+	ids := []string{"i-web1", "i-missing", "i-web2", "i-web3"}
+	ops := downloadFromInstances(context.Background(), ids, "/var/log/app.log", localDir, 2, download)
+
+	if len(ops) != len(ids) {
+		t.Fatalf("Expected one operation per instance, got %+v", ops)
+	}
+	for i, op := range ops {
+		if op.InstanceID != ids[i] {
+			t.Errorf("Operation %d is for %s, expected %s: operations must keep the target order", i, op.InstanceID, ids[i])
+		}
+	}
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 downloads at once, got %d", maxInFlight)
+	}
+
+	if ops[1].Status != ssm.TransferStatusFailed || !strings.Contains(ops[1].ErrorMessage, "No such file") {
+		t.Errorf("Expected the missing file to fail only its instance, got %+v", ops[1])
+	}
+	for _, i := range []int{0, 2, 3} {
+		want := filepath.Join(localDir, ids[i], "app.log")
+		if ops[i].Status != ssm.TransferStatusCompleted || ops[i].LocalPath != want {
+			t.Errorf("Expected %s downloaded to %s, got %+v", ids[i], want, ops[i])
+		}
+		if content, err := os.ReadFile(want); err != nil || string(content) != ids[i] {
+			t.Errorf("Expected %s to hold the file of %s, got %q, %v", want, ids[i], content, err)
+		}
+	}
+}
+
+func TestPrintDownloadSummary(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Second)
+
+	var buf bytes.Buffer
+	printDownloadSummary(&buf, []ssm.FileTransferOperation{
+		{InstanceID: "i-web1", Status: ssm.TransferStatusCompleted, Size: 2048, LocalPath: "logs/i-web1/app.log", StartTime: &start, EndTime: &end},
+		{InstanceID: "i-web2", Status: ssm.TransferStatusFailed, ErrorMessage: "failed to get remote file size: not found", StartTime: &start, EndTime: &end},
+	}, 3*time.Second)
+
+	output := buf.String()
+	for _, want := range []string{
+		"Instance ID", "Status", "Size", "Duration", "Local Path / Error",
+		"i-web1", "completed", "2.0 KiB", "logs/i-web1/app.log",
+		"i-web2", "failed", "failed to get remote file size: not found",
+		"1 of 2 download(s) completed",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got:\n%s", want, output)
+		}
+	}
+}
+
+func TestPerformTargetedDownloadValidation(t *testing.T) {
+	var buf bytes.Buffer
+	tests := []struct {
+		name       string
+		instances  string
+		parallel   int
+		remoteFile string
+		localDir   string
+		output     string
+		wantErr    string
+	}{
+		{name: "output", instances: "i-1", parallel: 1, remoteFile: "/etc/hosts", localDir: ".", output: "csv", wantErr: "invalid --output"},
+		{name: "no targets", parallel: 1, remoteFile: "/etc/hosts", localDir: ".", output: "text", wantErr: "--tags or --instances"},
+		{name: "parallel", instances: "i-1", parallel: 0, remoteFile: "/etc/hosts", localDir: ".", output: "text", wantErr: "--parallel"},
+		{name: "remote directory", instances: "i-1", parallel: 1, remoteFile: "/var/log/", localDir: ".", output: "text", wantErr: "is a directory"},
+		{name: "stdout", instances: "i-1", parallel: 1, remoteFile: "/etc/hosts", localDir: "-", output: "text", wantErr: "cannot write to stdout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := performTargetedDownload(&buf, "ca-central-1", "", tt.instances, tt.parallel, tt.remoteFile, tt.localDir, tt.output, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ztictl/internal/ssm"
+
+	"github.com/spf13/cobra"
+)
+
+// validateUploadSource checks that the local file of an upload exists, is a regular file and can be
+// read, or for a recursive upload that it is a directory. It runs before the instance is resolved,
+// so a mistyped path fails without any AWS calls.
+func validateUploadSource(localFile string, recursive bool) error {
+	info, err := os.Stat(localFile)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("local file not found: %s (relative paths are resolved from %s)", localFile, currentDir())
+	}
+	if err != nil {
+		return fmt.Errorf("cannot access local file %s: %w", localFile, err)
+	}
+	if recursive {
+		if !info.IsDir() {
+			return fmt.Errorf("local path %s is a file; --recursive needs a directory", localFile)
+		}
+		return nil
+	}
+	if info.IsDir() {
+		return fmt.Errorf("local path %s is a directory; use --recursive to upload it", localFile)
+	}
+
+	// #nosec G304 - the file is only opened to check that it is readable
+	file, err := os.Open(localFile)
+	if err != nil {
+		return fmt.Errorf("local file %s is not readable: %w", localFile, err)
+	}
+	_ = file.Close()
+	return nil
+}
+
+// validateDownloadTarget checks that a download can be written to localPath before the transfer starts.
+// A file download needs an existing, writable parent directory and must not name a directory. A recursive
+// download creates localDir as needed, so the closest existing directory must be writable.
+func validateDownloadTarget(localPath string, recursive bool) error {
+	if localPath == ssm.StdoutPath {
+		return nil
+	}
+
+	info, err := os.Stat(localPath)
+	switch {
+	case err == nil && info.IsDir() && !recursive:
+		return fmt.Errorf("local path %s is a directory; give the file name to write, e.g. %s", localPath, filepath.Join(localPath, "file"))
+	case err == nil && !info.IsDir() && recursive:
+		return fmt.Errorf("local path %s is a file; --recursive needs a directory", localPath)
+	case err == nil && recursive:
+		return checkDirWritable(localPath)
+	case err != nil && !os.IsNotExist(err):
+		return fmt.Errorf("cannot access local path %s: %w", localPath, err)
+	}
+
+	dir := filepath.Dir(localPath)
+	if recursive {
+		// MkdirAll creates the missing directories under the closest one that exists
+		for {
+			if _, err := os.Stat(dir); err == nil || filepath.Dir(dir) == dir {
+				break
+			}
+			dir = filepath.Dir(dir)
+		}
+	}
+	info, err = os.Stat(dir)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("local directory %s does not exist; create it first", dir)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot access local directory %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return checkDirWritable(dir)
+}
+
+// checkDirWritable creates and removes a temporary file in dir, which also covers ACLs and read-only mounts
+func checkDirWritable(dir string) error {
+	probe, err := os.CreateTemp(dir, ".ztictl-write-check-*")
+	if err != nil {
+		return fmt.Errorf("local directory %s is not writable: %w", dir, err)
+	}
+	name := probe.Name()
+	_ = probe.Close()
+	_ = os.Remove(name)
+	return nil
+}
+
+// currentDir returns the working directory for error messages, or "." when it is unknown
+func currentDir() string {
+	if dir, err := os.Getwd(); err == nil {
+		return dir
+	}
+	return "."
+}
+
+// completeUploadArgs completes local file paths for the <local-file> argument of upload. The first
+// argument is the local file unless it names an instance, in which case the second one is.
+func completeUploadArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 0:
+		return nil, cobra.ShellCompDirectiveDefault
+	case len(args) == 1 && !isLocalPath(args[0]):
+		return nil, cobra.ShellCompDirectiveDefault
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeDownloadArgs completes local paths for the <local-path> argument of download, which follows
+// the remote path. Remote paths are recognised by their leading / or Windows drive letter.
+func completeDownloadArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch {
+	case len(args) == 1 && isRemotePath(args[0]):
+		return nil, cobra.ShellCompDirectiveDefault
+	case len(args) == 2 && !isRemotePath(args[0]):
+		return nil, cobra.ShellCompDirectiveDefault
+	default:
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// isLocalPath reports whether an argument names an existing local file or directory
+func isLocalPath(arg string) bool {
+	_, err := os.Stat(arg)
+	return err == nil
+}
+
+// isRemotePath reports whether an argument looks like an absolute Linux or Windows path rather than an instance
+func isRemotePath(arg string) bool {
+	return strings.HasPrefix(arg, "/") || strings.HasPrefix(arg, `\`) ||
+		(len(arg) >= 2 && arg[1] == ':' && ((arg[0] >= 'A' && arg[0] <= 'Z') || (arg[0] >= 'a' && arg[0] <= 'z')))
+}
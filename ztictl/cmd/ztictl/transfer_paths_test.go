@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestValidateUploadSource(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.tar.gz")
+	if err := os.WriteFile(file, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := validateUploadSource(file, false); err != nil {
+		t.Errorf("Expected a readable file to pass, got %v", err)
+	}
+	if err := validateUploadSource(filepath.Join(dir, "missing.txt"), false); err == nil || !strings.Contains(err.Error(), "local file not found") {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if err := validateUploadSource(dir, false); err == nil || !strings.Contains(err.Error(), "is a directory") {
+		t.Errorf("Expected a directory to be rejected, got %v", err)
+	}
+	if err := validateUploadSource(dir, true); err != nil {
+		t.Errorf("Expected a directory to pass with --recursive, got %v", err)
+	}
+	if err := validateUploadSource(file, true); err == nil || !strings.Contains(err.Error(), "needs a directory") {
+		t.Errorf("Expected a file to be rejected with --recursive, got %v", err)
+	}
+
+	if runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		unreadable := filepath.Join(dir, "secret.txt")
+		if err := os.WriteFile(unreadable, []byte("data"), 0200); err != nil {
+			t.Fatal(err)
+		}
+		if err := validateUploadSource(unreadable, false); err == nil || !strings.Contains(err.Error(), "not readable") {
+			t.Errorf("Expected an unreadable file to be rejected, got %v", err)
+		}
+	}
+}
+
+func TestValidateDownloadTarget(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.txt")
+	if err := os.WriteFile(existing, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		recursive bool
+		wantErr   string
+	}{
+		{name: "stdout", path: "-"},
+		{name: "new file", path: filepath.Join(dir, "new.txt")},
+		{name: "overwrite file", path: existing},
+		{name: "missing parent", path: filepath.Join(dir, "missing", "new.txt"), wantErr: "does not exist"},
+		{name: "directory as file", path: dir, wantErr: "is a directory"},
+		{name: "file under a file", path: filepath.Join(existing, "new.txt"), wantErr: "not a directory"},
+		{name: "recursive into new tree", path: filepath.Join(dir, "logs", "app"), recursive: true},
+		{name: "recursive into existing directory", path: dir, recursive: true},
+		{name: "recursive into file", path: existing, recursive: true, wantErr: "needs a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDownloadTarget(tt.path, tt.recursive)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected the write check to leave no files behind, got %d entries", len(entries))
+	}
+}
+
+func TestValidateDownloadTargetReadOnly(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("directory permissions are not enforced for this user")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0500); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chmod(dir, 0700) }()
+
+	if err := validateDownloadTarget(filepath.Join(dir, "file.txt"), false); err == nil || !strings.Contains(err.Error(), "not writable") {
+		t.Errorf("Expected a read-only directory to be rejected, got %v", err)
+	}
+}
+
+func TestTransferCompletion(t *testing.T) {
+	dir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Chdir(originalDir) }()
+	if err := os.WriteFile("app.conf", []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	tests := []struct {
+		name     string
+		complete func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective)
+		args     []string
+		want     cobra.ShellCompDirective
+	}{
+		{name: "upload first argument", complete: completeUploadArgs, args: nil, want: cobra.ShellCompDirectiveDefault},
+		{name: "upload remote after local file", complete: completeUploadArgs, args: []string{"app.conf"}, want: cobra.ShellCompDirectiveNoFileComp},
+		{name: "upload local file after instance", complete: completeUploadArgs, args: []string{"i-1234567890abcdef0"}, want: cobra.ShellCompDirectiveDefault},
+		{name: "upload remote after instance and file", complete: completeUploadArgs, args: []string{"i-1234567890abcdef0", "app.conf"}, want: cobra.ShellCompDirectiveNoFileComp},
+		{name: "download first argument", complete: completeDownloadArgs, args: nil, want: cobra.ShellCompDirectiveNoFileComp},
+		{name: "download local after remote", complete: completeDownloadArgs, args: []string{"/etc/hosts"}, want: cobra.ShellCompDirectiveDefault},
+		{name: "download local after Windows remote", complete: completeDownloadArgs, args: []string{`C:\logs\app.log`}, want: cobra.ShellCompDirectiveDefault},
+		{name: "download remote after instance", complete: completeDownloadArgs, args: []string{"web-1"}, want: cobra.ShellCompDirectiveNoFileComp},
+		{name: "download local after instance and remote", complete: completeDownloadArgs, args: []string{"web-1", "/etc/hosts"}, want: cobra.ShellCompDirectiveDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := tt.complete(cmd, tt.args, ""); got != tt.want {
+				t.Errorf("Expected directive %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
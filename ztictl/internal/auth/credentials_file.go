@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ztictl/pkg/security"
+)
+
+// credentialKeys are the settings WriteCredentialsProfile owns in a credentials file section
+var credentialKeys = []string{"aws_access_key_id", "aws_secret_access_key", "aws_session_token"}
+
+// WriteCredentialsProfile stores creds as a static profile in the shared credentials file
+// (AWS_SHARED_CREDENTIALS_FILE, or ~/.aws/credentials), for tools that cannot use SSO or
+// credential_process. Other sections and other settings of the profile are kept. The file is
+// left readable only by the user. It returns the path written.
+func (m *Manager) WriteCredentialsProfile(profileName string, creds *Credentials) (string, error) {
+	if strings.TrimSpace(profileName) == "" || strings.ContainsAny(profileName, "[]\r\n") {
+		return "", fmt.Errorf("invalid profile name %q", profileName)
+	}
+
+	credentialsPath := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsPath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		configDir := filepath.Join(homeDir, ".aws")
+		credentialsPath = filepath.Join(configDir, "credentials")
+
+		// Validate credentials path to prevent directory traversal
+		if err := security.ValidateFilePath(credentialsPath, configDir); err != nil {
+			return "", fmt.Errorf("invalid credentials file path: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(credentialsPath), 0750); err != nil {
+		return "", fmt.Errorf("failed to create AWS config directory: %w", err)
+	}
+
+	var content string
+	// #nosec G304
+	if existing, err := os.ReadFile(credentialsPath); err == nil {
+		content = string(existing)
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read AWS credentials file: %w", err)
+	}
+
+	content = updateProfileInCredentials(content, profileName, creds)
+
+	if err := os.WriteFile(credentialsPath, []byte(content), 0600); err != nil {
+		return "", fmt.Errorf("failed to write AWS credentials file: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file, which may be wider
+	if err := os.Chmod(credentialsPath, 0600); err != nil {
+		return "", fmt.Errorf("failed to restrict AWS credentials file permissions: %w", err)
+	}
+	return credentialsPath, nil
+}
+
+// updateProfileInCredentials replaces the keys of profileName's section in a credentials file, adding the
+// section when it is missing. Credentials file sections are named [name], without the "profile " prefix
+// used in ~/.aws/config. A session token left over from earlier credentials is removed.
+func updateProfileInCredentials(content, profileName string, creds *Credentials) string {
+	section := fmt.Sprintf("[%s]", profileName)
+	settings := []string{
+		"aws_access_key_id = " + creds.AccessKeyID,
+		"aws_secret_access_key = " + creds.SecretAccessKey,
+	}
+	if creds.SessionToken != "" {
+		settings = append(settings, "aws_session_token = "+creds.SessionToken)
+	}
+
+	var lines []string
+	if content != "" {
+		lines = strings.Split(content, "\n")
+	}
+	var result []string
+	inTarget, found := false, false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			inTarget = trimmed == section
+			result = append(result, line)
+			if inTarget {
+				found = true
+				result = append(result, settings...)
+			}
+			continue
+		}
+		if inTarget && isCredentialKey(trimmed) {
+			continue
+		}
+		result = append(result, line)
+	}
+
+	if !found {
+		for len(result) > 0 && strings.TrimSpace(result[len(result)-1]) == "" {
+			result = result[:len(result)-1]
+		}
+		if len(result) > 0 {
+			result = append(result, "")
+		}
+		result = append(result, section)
+		result = append(result, settings...)
+	}
+
+	output := strings.Join(result, "\n")
+	if !strings.HasSuffix(output, "\n") {
+		output += "\n"
+	}
+	return output
+}
+
+// isCredentialKey reports whether a credentials file line sets one of credentialKeys
+func isCredentialKey(line string) bool {
+	key, _, ok := strings.Cut(line, "=")
+	if !ok {
+		return false
+	}
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, credentialKey := range credentialKeys {
+		if key == credentialKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestUpdateProfileInCredentials(t *testing.T) {
+	creds := &Credentials{AccessKeyID: "AKIANEW", SecretAccessKey: "new-secret", SessionToken: "new-token"}
+
+	t.Run("adds a missing section", func(t *testing.T) {
+		existing := "[default]\naws_access_key_id = AKIADEFAULT\naws_secret_access_key = default-secret\n"
+		got := updateProfileInCredentials(existing, "legacy", creds)
+		want := existing + "\n[legacy]\naws_access_key_id = AKIANEW\naws_secret_access_key = new-secret\naws_session_token = new-token\n"
+		if got != want {
+			t.Errorf("Unexpected content:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("replaces keys and keeps other settings", func(t *testing.T) {
+		existing := "[legacy]\naws_access_key_id=AKIAOLD\nregion = ca-central-1\nAWS_SECRET_ACCESS_KEY = old-secret\naws_session_token = old-token\n\n[other]\naws_access_key_id = AKIAOTHER\n"
+		got := updateProfileInCredentials(existing, "legacy", creds)
+		want := "[legacy]\naws_access_key_id = AKIANEW\naws_secret_access_key = new-secret\naws_session_token = new-token\nregion = ca-central-1\n\n[other]\naws_access_key_id = AKIAOTHER\n"
+		if got != want {
+			t.Errorf("Unexpected content:\n%s\nwant:\n%s", got, want)
+		}
+	})
+
+	t.Run("drops a stale session token", func(t *testing.T) {
+		existing := "[legacy]\naws_access_key_id = AKIAOLD\naws_secret_access_key = old\naws_session_token = stale\n"
+		got := updateProfileInCredentials(existing, "legacy", &Credentials{AccessKeyID: "AKIANEW", SecretAccessKey: "new"})
+		if strings.Contains(got, "aws_session_token") {
+			t.Errorf("Expected the old session token to be removed, got:\n%s", got)
+		}
+	})
+
+	t.Run("creates an empty file", func(t *testing.T) {
+		got := updateProfileInCredentials("", "legacy", creds)
+		if !strings.HasPrefix(got, "[legacy]\n") {
+			t.Errorf("Expected the file to start with the section, got:\n%s", got)
+		}
+	})
+}
+
+func TestWriteCredentialsProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", path)
+	if err := os.WriteFile(path, []byte("[default]\naws_access_key_id = AKIADEFAULT\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewManager()
+	written, err := manager.WriteCredentialsProfile("legacy", &Credentials{AccessKeyID: "AKIANEW", SecretAccessKey: "secret"})
+	if err != nil {
+		t.Fatalf("WriteCredentialsProfile failed: %v", err)
+	}
+	if written != path {
+		t.Errorf("Expected %s to be written, got %s", path, written)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "[default]\naws_access_key_id = AKIADEFAULT") || !strings.Contains(string(content), "[legacy]\naws_access_key_id = AKIANEW") {
+		t.Errorf("Unexpected credentials file:\n%s", content)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("Expected mode 0600, got %o", info.Mode().Perm())
+		}
+	}
+
+	if _, err := manager.WriteCredentialsProfile("bad]name", &Credentials{}); err == nil {
+		t.Error("Expected an invalid profile name to be rejected")
+	}
+}
@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	appconfig "ztictl/internal/config"
+	"ztictl/pkg/logging"
+	"ztictl/pkg/security"
+)
+
+// LogoutOptions controls which cached SSO tokens Logout removes
+type LogoutOptions struct {
+	All    bool // Remove every SSO token in the cache, not just the one for the profile's start URL
+	DryRun bool // Report the files that would be removed without deleting them
+}
+
+// Logout removes cached SSO tokens from the AWS SSO cache directory, and from the encrypted
+// ztictl cache, and returns the affected files.
+// Without opts.All it removes the token for the profile's start URL, or for the configured
+// start URL when profileName is empty. Only files that parse as SSO tokens are ever removed.
+func (m *Manager) Logout(ctx context.Context, profileName string, opts LogoutOptions) ([]string, error) {
+	cacheDir, err := getAWSCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	startURL := ""
+	if !opts.All {
+		startURL, err = m.logoutStartURL(profileName)
+		if err != nil {
+			return nil, err
+		}
+		logging.LogInfo("Logging out | profile=%s start_url=%s", profileName, startURL)
+	} else {
+		logging.LogInfo("Logging out from all SSO sessions")
+	}
+
+	files, err := findTokenCacheFiles(cacheDir, startURL)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := findEncryptedTokenFiles(startURL)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, encrypted...)
+	if opts.DryRun {
+		return files, nil
+	}
+
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove cached token %s: %w", file, err)
+		}
+		logging.LogDebug("Removed cached SSO token | file=%s", file)
+	}
+	return files, nil
+}
+
+// logoutStartURL returns the SSO start URL whose token a logout should remove
+func (m *Manager) logoutStartURL(profileName string) (string, error) {
+	if profileName == "" {
+		startURL := appconfig.Get().SSO.StartURL
+		if startURL == "" {
+			return "", fmt.Errorf("SSO start URL not configured; specify a profile or use --all")
+		}
+		return startURL, nil
+	}
+
+	configDir, err := getAWSConfigDir()
+	if err != nil {
+		return "", err
+	}
+	configPath := filepath.Join(configDir, "config")
+	if err := security.ValidateFilePath(configPath, configDir); err != nil {
+		return "", fmt.Errorf("invalid config file path: %w", err)
+	}
+
+	content, err := os.ReadFile(configPath) // #nosec G304
+	if err != nil {
+		return "", fmt.Errorf("failed to read AWS config: %w", err)
+	}
+
+	for _, profile := range m.parseProfiles(string(content)) {
+		if profile.Name != profileName {
+			continue
+		}
+		if profile.SSOStartURL == "" {
+			return "", fmt.Errorf("profile %s has no sso_start_url", profileName)
+		}
+		return profile.SSOStartURL, nil
+	}
+	return "", fmt.Errorf("profile %s not found in AWS config", profileName)
+}
+
+// findTokenCacheFiles returns the SSO token files in cacheDir, sorted, limited to startURL when set.
+// The AWS CLI compatible file for startURL is checked first, then any other file holding its token.
+// Files that are not SSO tokens (such as OIDC client registrations), symlinks and
+// subdirectories are never returned.
+func findTokenCacheFiles(cacheDir, startURL string) ([]string, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read SSO cache directory: %w", err)
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	addIfToken := func(path string) {
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+		if isSSOTokenFile(path, cacheDir, startURL) {
+			files = append(files, path)
+		}
+	}
+
+	if startURL != "" {
+		addIfToken(filepath.Join(cacheDir, tokenCacheFileName(startURL)))
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		addIfToken(filepath.Join(cacheDir, entry.Name()))
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// isSSOTokenFile reports whether path is a regular file inside cacheDir holding an SSO token,
+// for startURL when it is set
+func isSSOTokenFile(path, cacheDir, startURL string) bool {
+	if err := security.ValidateFilePath(path, cacheDir); err != nil {
+		logging.LogWarn("Invalid cache file path, skipping: %v", err)
+		return false
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	content, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		logging.LogWarn("Could not read cache file %s: %v", path, err)
+		return false
+	}
+
+	var token SSOToken
+	if json.Unmarshal(content, &token) != nil || token.StartURL == "" || token.AccessToken == "" {
+		return false
+	}
+	return startURL == "" || token.StartURL == startURL
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// writeCacheFile writes a JSON value to the SSO cache directory and returns its path
+func writeCacheFile(t *testing.T, cacheDir, name string, value interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("Failed to marshal %s: %v", name, err)
+	}
+	path := filepath.Join(cacheDir, name)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func setupLogoutHome(t *testing.T) (string, string) {
+	t.Helper()
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("USERPROFILE", tempDir)
+
+	cacheDir := filepath.Join(tempDir, ".aws", "sso", "cache")
+	if err := os.MkdirAll(cacheDir, 0750); err != nil {
+		t.Fatalf("Failed to create cache dir: %v", err)
+	}
+	return tempDir, cacheDir
+}
+
+func TestLogoutProfile(t *testing.T) {
+	tempDir, cacheDir := setupLogoutHome(t)
+
+	prodURL := "https://prod.awsapps.com/start"
+	devURL := "https://dev.awsapps.com/start"
+	awsConfig := "[profile prod]\nsso_start_url = " + prodURL + "\nsso_region = us-east-1\n\n[profile static]\nregion = us-east-1\n"
+	if err := os.WriteFile(filepath.Join(tempDir, ".aws", "config"), []byte(awsConfig), 0600); err != nil {
+		t.Fatalf("Failed to write AWS config: %v", err)
+	}
+
+	expires := time.Now().Add(time.Hour)
+	prodHashed := writeCacheFile(t, cacheDir, tokenCacheFileName(prodURL), SSOToken{StartURL: prodURL, AccessToken: "a", ExpiresAt: expires})
+	prodOther := writeCacheFile(t, cacheDir, "legacy.json", SSOToken{StartURL: prodURL, AccessToken: "b", ExpiresAt: expires})
+	devToken := writeCacheFile(t, cacheDir, tokenCacheFileName(devURL), SSOToken{StartURL: devURL, AccessToken: "c", ExpiresAt: expires})
+
+	manager := NewManager()
+	ctx := context.Background()
+
+	preview, err := manager.Logout(ctx, "prod", LogoutOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Dry-run logout failed: %v", err)
+	}
+	if len(preview) != 2 {
+		t.Fatalf("Expected 2 files in dry-run preview, got %v", preview)
+	}
+	if _, err := os.Stat(prodHashed); err != nil {
+		t.Fatal("Dry run must not remove files")
+	}
+
+	removed, err := manager.Logout(ctx, "prod", LogoutOptions{})
+	if err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("Expected 2 removed files, got %v", removed)
+	}
+	for _, path := range []string{prodHashed, prodOther} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", path)
+		}
+	}
+	if _, err := os.Stat(devToken); err != nil {
+		t.Errorf("Expected token for another start URL to be kept: %v", err)
+	}
+
+	if _, err := manager.Logout(ctx, "static", LogoutOptions{}); err == nil {
+		t.Error("Expected error for profile without sso_start_url")
+	}
+	if _, err := manager.Logout(ctx, "missing", LogoutOptions{}); err == nil {
+		t.Error("Expected error for unknown profile")
+	}
+}
+
+func TestLogoutAllKeepsNonTokenFiles(t *testing.T) {
+	_, cacheDir := setupLogoutHome(t)
+
+	token := writeCacheFile(t, cacheDir, "a.json", SSOToken{StartURL: "https://a.awsapps.com/start", AccessToken: "a"})
+	registration := writeCacheFile(t, cacheDir, "botocore-client-id-us-east-1.json", map[string]string{"clientId": "id", "clientSecret": "secret"})
+	notes := filepath.Join(cacheDir, "notes.txt")
+	if err := os.WriteFile(notes, []byte("keep"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "broken.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" {
+		if err := os.Symlink(token, filepath.Join(cacheDir, "link.json")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := NewManager().Logout(context.Background(), "", LogoutOptions{All: true})
+	if err != nil {
+		t.Fatalf("Logout --all failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != token {
+		t.Errorf("Expected only %s to be removed, got %v", token, removed)
+	}
+	for _, path := range []string{registration, notes, filepath.Join(cacheDir, "broken.json")} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be kept: %v", path, err)
+		}
+	}
+}
+
+func TestFindTokenCacheFilesMissingDir(t *testing.T) {
+	files, err := findTokenCacheFiles(filepath.Join(t.TempDir(), "missing"), "")
+	if err != nil || len(files) != 0 {
+		t.Errorf("Expected no files and no error for a missing cache dir, got %v, %v", files, err)
+	}
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	appconfig "ztictl/internal/config"
+)
+
+// templateAccount finds the account of a profile template among the accounts the SSO session can access
+func templateAccount(accounts []Account, template *appconfig.ProfileTemplate) (*Account, error) {
+	for i := range accounts {
+		if accounts[i].AccountID == template.AccountID {
+			return &accounts[i], nil
+		}
+	}
+	return nil, fmt.Errorf("account %s from profile template %q is not available to this SSO session", template.AccountID, template.Name)
+}
+
+// templateRole finds the role of a profile template among the roles available in its account.
+// Role names are matched exactly, as IAM Identity Center permission sets are case-sensitive.
+func templateRole(roles []Role, account *Account, template *appconfig.ProfileTemplate) (*Role, error) {
+	names := make([]string, len(roles))
+	for i := range roles {
+		if roles[i].RoleName == template.Role {
+			return &roles[i], nil
+		}
+		names[i] = roles[i].RoleName
+	}
+	available := "none"
+	if len(names) > 0 {
+		available = strings.Join(names, ", ")
+	}
+	return nil, fmt.Errorf("role %s from profile template %q is not available in account %s (%s); available roles: %s",
+		template.Role, template.Name, account.AccountID, account.AccountName, available)
+}
+
+// profileRegion returns the region to write to the profile, or empty to use default_region.
+// An explicit ProfileRegion takes precedence over the template's region.
+func (o LoginOptions) profileRegion() string {
+	if o.ProfileRegion != "" {
+		return o.ProfileRegion
+	}
+	if o.Template != nil {
+		return o.Template.Region
+	}
+	return ""
+}
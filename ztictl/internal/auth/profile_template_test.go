@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	appconfig "ztictl/internal/config"
+)
+
+func TestTemplateAccount(t *testing.T) {
+	accounts := []Account{
+		{AccountID: "111111111111", AccountName: "dev"},
+		{AccountID: "123456789012", AccountName: "prod"},
+	}
+
+	account, err := templateAccount(accounts, &appconfig.ProfileTemplate{Name: "prod-admin", AccountID: "123456789012"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if account.AccountName != "prod" {
+		t.Errorf("Expected the prod account, got %+v", account)
+	}
+
+	_, err = templateAccount(accounts, &appconfig.ProfileTemplate{Name: "audit", AccountID: "999999999999"})
+	if err == nil || !strings.Contains(err.Error(), "999999999999") {
+		t.Errorf("Expected an error naming the missing account, got %v", err)
+	}
+}
+
+func TestTemplateRole(t *testing.T) {
+	account := &Account{AccountID: "123456789012", AccountName: "prod"}
+	roles := []Role{
+		{RoleName: "ReadOnlyAccess", AccountID: account.AccountID},
+		{RoleName: "AdministratorAccess", AccountID: account.AccountID},
+	}
+
+	role, err := templateRole(roles, account, &appconfig.ProfileTemplate{Name: "prod-admin", Role: "AdministratorAccess"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if role.RoleName != "AdministratorAccess" {
+		t.Errorf("Expected AdministratorAccess, got %+v", role)
+	}
+
+	_, err = templateRole(roles, account, &appconfig.ProfileTemplate{Name: "prod-admin", Role: "administratoraccess"})
+	if err == nil || !strings.Contains(err.Error(), "ReadOnlyAccess, AdministratorAccess") {
+		t.Errorf("Expected a case-sensitive mismatch listing the available roles, got %v", err)
+	}
+}
+
+func TestLoginOptionsProfileRegion(t *testing.T) {
+	template := &appconfig.ProfileTemplate{Name: "prod-admin", Region: "us-east-1"}
+
+	tests := []struct {
+		name     string
+		opts     LoginOptions
+		expected string
+	}{
+		{"no template or region uses default_region", LoginOptions{}, ""},
+		{"template region", LoginOptions{Template: template}, "us-east-1"},
+		{"template without region", LoginOptions{Template: &appconfig.ProfileTemplate{Name: "dev"}}, ""},
+		{"profile region without template", LoginOptions{ProfileRegion: "eu-west-1"}, "eu-west-1"},
+		{"profile region overrides template", LoginOptions{Template: template, ProfileRegion: "ca-central-1"}, "ca-central-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.profileRegion(); got != tt.expected {
+				t.Errorf("profileRegion() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"io/fs"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,6 +21,7 @@ import (
 	appconfig "ztictl/internal/config"
 	"ztictl/pkg/errors"
 	"ztictl/pkg/logging"
+	"ztictl/pkg/qrcode"
 	"ztictl/pkg/security"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -33,7 +36,7 @@ import (
 )
 
 const (
-	// SSO authentication timeout constraints
+	// Default SSO authentication timeout constraints, overridden by sso.min_timeout and sso.max_timeout
 	MinTimeoutSeconds = 60  // 1 minute minimum for user interaction
 	MaxTimeoutSeconds = 180 // 3 minute maximum for security
 
@@ -145,6 +148,36 @@ func DetectEnvironmentCredentials() (bool, CredentialType) {
 	return false, CredentialTypeNone
 }
 
+// DefaultIMDSEndpoint is the default base URL of the EC2 instance metadata service
+const DefaultIMDSEndpoint = "http://169.254.169.254"
+
+// imdsSettings controls how the instance metadata service is probed.
+// Values mirror the AWS SDK environment variables so behavior matches the SDK.
+type imdsSettings struct {
+	// Endpoint is the IMDS base URL (AWS_EC2_METADATA_SERVICE_ENDPOINT)
+	Endpoint string
+
+	// Disabled skips IMDS probing entirely (AWS_EC2_METADATA_DISABLED)
+	Disabled bool
+
+	// V1Disabled requires IMDSv2 and skips the IMDSv1 fallback (AWS_EC2_METADATA_V1_DISABLED)
+	V1Disabled bool
+}
+
+// imdsSettingsFromEnv reads IMDS settings from the AWS SDK environment variables
+func imdsSettingsFromEnv() imdsSettings {
+	endpoint := strings.TrimRight(strings.TrimSpace(os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT")), "/")
+	if endpoint == "" {
+		endpoint = DefaultIMDSEndpoint
+	}
+
+	return imdsSettings{
+		Endpoint:   endpoint,
+		Disabled:   strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true"),
+		V1Disabled: strings.EqualFold(os.Getenv("AWS_EC2_METADATA_V1_DISABLED"), "true"),
+	}
+}
+
 // isEC2Instance checks if running on an EC2 instance by querying IMDS
 // Uses a short timeout to avoid blocking if not on EC2
 func isEC2Instance() bool {
@@ -153,9 +186,18 @@ func isEC2Instance() bool {
 		Timeout: 1000 * time.Millisecond,
 	}
 
+	return probeIMDS(client, imdsSettingsFromEnv())
+}
+
+// probeIMDS reports whether the instance metadata service described by settings is reachable
+func probeIMDS(client *http.Client, settings imdsSettings) bool {
+	if settings.Disabled {
+		return false
+	}
+
 	// Try to reach EC2 instance metadata service (IMDSv2 token endpoint)
 	// Using the token endpoint because it's available on both IMDSv1 and IMDSv2
-	req, err := http.NewRequest("PUT", "http://169.254.169.254/latest/api/token", nil)
+	req, err := http.NewRequest("PUT", settings.Endpoint+"/latest/api/token", nil)
 	if err != nil {
 		return false
 	}
@@ -165,8 +207,12 @@ func isEC2Instance() bool {
 
 	resp, err := client.Do(req)
 	if err != nil {
+		if settings.V1Disabled {
+			return false
+		}
+
 		// Also try IMDSv1 as fallback (simple GET request)
-		req, err = http.NewRequest("GET", "http://169.254.169.254/latest/meta-data/", nil)
+		req, err = http.NewRequest("GET", settings.Endpoint+"/latest/meta-data/", nil)
 		if err != nil {
 			return false
 		}
@@ -422,9 +468,52 @@ func getAWSCacheDir() (string, error) {
 	return filepath.Join(configDir, "sso", "cache"), nil
 }
 
+// tokenCacheFileName returns the AWS CLI compatible cache file name for a start URL's SSO token.
+// SECURITY NOTE: SHA1 is cryptographically weak but required for AWS CLI compatibility.
+// The hash is only used for cache filename generation, not for security purposes.
+// AWS CLI expects SHA1-based filenames in ~/.aws/sso/cache/
+// TODO: Monitor AWS CLI for migration to SHA256 or other secure alternatives
+func tokenCacheFileName(startURL string) string {
+	hasher := sha1.New() // #nosec G401 -- SHA1 required for AWS CLI compatibility
+	hasher.Write([]byte(startURL))
+	return hex.EncodeToString(hasher.Sum(nil)) + ".json"
+}
+
+// LoginOptions controls how a login selects the account and role and writes the profile
+type LoginOptions struct {
+	Template      *appconfig.ProfileTemplate // Account and role to use instead of the interactive pickers
+	ProfileRegion string                     // Region written to the profile; overrides the template's region and default_region
+	NoBrowser     bool                       // Only print the verification URL and code; sso.no_browser does the same
+	Browser       string                     // Browser binary to open the verification URL with; overrides sso.browser
+	QR            bool                       // Also show the verification URL as a QR code to scan with a phone
+}
+
 // Login performs AWS SSO login with interactive account and role selection
 func (m *Manager) Login(ctx context.Context, profileName string) error {
+	return m.login(ctx, profileName, LoginOptions{})
+}
+
+// LoginWithTemplate performs AWS SSO login for the account and role of a profile template instead of
+// asking for them. Both are checked against what the SSO session can access.
+func (m *Manager) LoginWithTemplate(ctx context.Context, profileName string, template *appconfig.ProfileTemplate) error {
+	return m.login(ctx, profileName, LoginOptions{Template: template})
+}
+
+// LoginWithOptions performs AWS SSO login using the template, profile region and browser settings in opts
+func (m *Manager) LoginWithOptions(ctx context.Context, profileName string, opts LoginOptions) error {
+	return m.login(ctx, profileName, opts)
+}
+
+// login runs the SSO login flow; without a template the account and role are selected interactively
+func (m *Manager) login(ctx context.Context, profileName string, opts LoginOptions) error {
+	template := opts.Template
 	cfg := appconfig.Get()
+	if region := opts.profileRegion(); region != "" {
+		// The profile is written with this region rather than default_region
+		profileCfg := *cfg
+		profileCfg.DefaultRegion = region
+		cfg = &profileCfg
+	}
 
 	// Log the SSO configuration for debugging
 	logging.LogDebug("SSO Configuration | start_url=%s region=%s profile=%s", cfg.SSO.StartURL, cfg.SSO.Region, profileName)
@@ -454,7 +543,7 @@ func (m *Manager) Login(ctx context.Context, profileName string) error {
 		logging.LogInfo("No valid cached token found, initiating SSO login...")
 
 		// Perform SSO login
-		if err := m.performSSOLogin(ctx, awsCfg, cfg); err != nil {
+		if err := m.performSSOLogin(ctx, awsCfg, cfg, opts); err != nil {
 			return err
 		}
 
@@ -473,8 +562,13 @@ func (m *Manager) Login(ctx context.Context, profileName string) error {
 		return fmt.Errorf("failed to list accounts: %w", err)
 	}
 
-	// Step 5: Interactive account selection
-	selectedAccount, err := m.selectAccount(accounts)
+	// Step 5: Account selection, from the template or interactively
+	var selectedAccount *Account
+	if template != nil {
+		selectedAccount, err = templateAccount(accounts, template)
+	} else {
+		selectedAccount, err = m.selectAccount(accounts)
+	}
 	if err != nil {
 		return fmt.Errorf("account selection failed: %w", err)
 	}
@@ -487,8 +581,13 @@ func (m *Manager) Login(ctx context.Context, profileName string) error {
 		return fmt.Errorf("failed to list roles: %w", err)
 	}
 
-	// Step 7: Interactive role selection
-	selectedRole, err := m.selectRole(roles, selectedAccount)
+	// Step 7: Role selection, from the template or interactively
+	var selectedRole *Role
+	if template != nil {
+		selectedRole, err = templateRole(roles, selectedAccount, template)
+	} else {
+		selectedRole, err = m.selectRole(roles, selectedAccount)
+	}
 	if err != nil {
 		return fmt.Errorf("role selection failed: %w", err)
 	}
@@ -508,23 +607,6 @@ func (m *Manager) Login(ctx context.Context, profileName string) error {
 	return nil
 }
 
-// Logout performs AWS SSO logout
-func (m *Manager) Logout(ctx context.Context, profileName string) error {
-	if profileName != "" {
-		logging.LogInfo("Logging out from specific profile | profile=%s", profileName)
-		// For now, we'll clear the specific profile's cached credentials
-		// In a full implementation, this would clear SSO cache for the specific profile
-	} else {
-		logging.LogInfo("Logging out from all SSO sessions")
-		// Clear all SSO cache
-		if err := m.clearSSOCache(); err != nil {
-			return fmt.Errorf("failed to clear SSO cache: %w", err)
-		}
-	}
-
-	return nil
-}
-
 // ListProfiles returns all configured AWS profiles
 func (m *Manager) ListProfiles(ctx context.Context) ([]Profile, error) {
 	// Read AWS config file to get all profiles
@@ -556,17 +638,46 @@ func (m *Manager) ListProfiles(ctx context.Context) ([]Profile, error) {
 			logging.LogWarn("Failed to check authentication status | profile=%s error=%v", profiles[i].Name, err)
 		}
 		profiles[i].IsAuthenticated = isAuth
+		profiles[i].ExpiresAt = m.cachedTokenExpiry(profiles[i].SSOStartURL)
 	}
 
+	// Sort by name so output is stable across runs
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
 	return profiles, nil
 }
 
+// cachedTokenExpiry returns the expiry of the cached SSO token for a start URL, or nil if none is cached
+func (m *Manager) cachedTokenExpiry(startURL string) *time.Time {
+	if startURL == "" {
+		return nil
+	}
+
+	cacheDir, err := getAWSCacheDir()
+	if err != nil {
+		return nil
+	}
+	if _, err := os.Stat(cacheDir); err != nil && !encryptedCacheEnabled() {
+		return nil
+	}
+
+	token, err := m.getCachedToken(startURL)
+	if err != nil || token.ExpiresAt.IsZero() {
+		return nil
+	}
+
+	expiresAt := token.ExpiresAt
+	return &expiresAt
+}
+
 // GetCredentials returns AWS credentials for a profile
 func (m *Manager) GetCredentials(ctx context.Context, profileName string) (*Credentials, error) {
 	// First, try to get an STS token to force credential resolution
-	awsCfg, err := config.LoadDefaultConfig(ctx,
+	awsCfg, err := config.LoadDefaultConfig(ctx, append([]func(*config.LoadOptions) error{
 		config.WithSharedConfigProfile(profileName),
-	)
+	}, CredentialLoadOptions(ctx, profileName)...)...)
 	if err != nil {
 		return nil, errors.NewAuthError("failed to load AWS config for profile", err)
 	}
@@ -587,12 +698,17 @@ func (m *Manager) GetCredentials(ctx context.Context, profileName string) (*Cred
 	// Verify the credentials work by checking the caller identity
 	logging.LogInfo("Retrieved credentials | account=%s profile=%s", *callerIdentity.Account, profileName)
 
-	return &Credentials{
+	result := &Credentials{
 		AccessKeyID:     creds.AccessKeyID,
 		SecretAccessKey: creds.SecretAccessKey,
 		SessionToken:    creds.SessionToken,
 		Region:          awsCfg.Region,
-	}, nil
+	}
+	if creds.CanExpire {
+		expires := creds.Expires
+		result.ExpiresAt = &expires
+	}
+	return result, nil
 }
 
 // configureProfile sets up the AWS profile with SSO settings
@@ -632,8 +748,17 @@ func (m *Manager) configureProfile(profileName string, cfg *appconfig.Config) er
 	return nil
 }
 
-// getCachedToken retrieves a cached SSO token
+// getCachedToken retrieves a cached SSO token. With sso.encrypt_token_cache set the encrypted
+// ztictl cache is checked first, then tokens written to the AWS cache by other tools.
 func (m *Manager) getCachedToken(startURL string) (*SSOToken, error) {
+	if encryptedCacheEnabled() {
+		token, err := loadEncryptedToken(startURL)
+		if err == nil {
+			return token, nil
+		}
+		logging.LogDebug("No usable encrypted SSO token | start_url=%s error=%v", startURL, err)
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
@@ -642,14 +767,7 @@ func (m *Manager) getCachedToken(startURL string) (*SSOToken, error) {
 	cacheDir := filepath.Join(homeDir, ".aws", "sso", "cache")
 
 	// First, try the expected filename based on SHA1 hash (AWS CLI compatible)
-	// SECURITY NOTE: SHA1 is cryptographically weak but required for AWS CLI compatibility.
-	// The hash is only used for cache filename generation, not for security purposes.
-	// AWS CLI expects SHA1-based filenames in ~/.aws/sso/cache/
-	// TODO: Monitor AWS CLI for migration to SHA256 or other secure alternatives
-	hasher := sha1.New() // #nosec G401 -- SHA1 required for AWS CLI compatibility
-	hasher.Write([]byte(startURL))
-	hash := hex.EncodeToString(hasher.Sum(nil))
-	expectedFile := filepath.Join(cacheDir, fmt.Sprintf("%s.json", hash))
+	expectedFile := filepath.Join(cacheDir, tokenCacheFileName(startURL))
 
 	// Validate cache file path to prevent directory traversal
 	if err := security.ValidateFilePath(expectedFile, cacheDir); err != nil {
@@ -728,8 +846,79 @@ func (m *Manager) isTokenValid(token *SSOToken) bool {
 	return time.Now().Before(token.ExpiresAt)
 }
 
+// loginTimeoutBounds returns the configured device authorization wait bounds in seconds,
+// falling back to the defaults when a bound is unset
+func loginTimeoutBounds(cfg *appconfig.Config) (int32, int32) {
+	minTimeout, maxTimeout := int32(MinTimeoutSeconds), int32(MaxTimeoutSeconds)
+	if cfg == nil {
+		return minTimeout, maxTimeout
+	}
+
+	if cfg.SSO.MinTimeout > 0 {
+		minTimeout = int32(cfg.SSO.MinTimeout) // #nosec G115 - bounded by config validation
+	}
+	if cfg.SSO.MaxTimeout > 0 {
+		maxTimeout = int32(cfg.SSO.MaxTimeout) // #nosec G115 - bounded by config validation
+	}
+	// A single configured bound can cross the other default; the configured one wins
+	if minTimeout > maxTimeout {
+		if cfg.SSO.MaxTimeout > 0 {
+			minTimeout = maxTimeout
+		} else {
+			maxTimeout = minTimeout
+		}
+	}
+	return minTimeout, maxTimeout
+}
+
+// browserSettings returns whether to open a browser for device authorization and which binary to use,
+// with the login options taking precedence over sso.no_browser and sso.browser. An empty path means the
+// system default browser.
+func (o LoginOptions) browserSettings(sso appconfig.SSOConfig) (open bool, path string) {
+	if o.NoBrowser || sso.NoBrowser {
+		return false, ""
+	}
+	if o.Browser != "" {
+		return true, o.Browser
+	}
+	return true, sso.Browser
+}
+
+// openBrowser opens url in the browser binary at path, or in the system default browser when path is empty
+var openBrowser = func(url, path string) error {
+	if path == "" {
+		return browser.OpenURL(url)
+	}
+	binary, err := exec.LookPath(path)
+	if err != nil {
+		return fmt.Errorf("browser %s not found: %w", path, err)
+	}
+	cmd := exec.Command(binary, url) // #nosec G204 -- the browser is chosen by the user
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// Reap the browser process once it exits
+	go func() { _ = cmd.Wait() }()
+	return nil
+}
+
+// printVerificationQR prints the verification URL as a terminal QR code. Failures are only logged since
+// the URL is printed as text as well.
+func printVerificationQR(authURL string) {
+	code, err := qrcode.Encode(authURL)
+	if err != nil {
+		logging.LogWarn("Failed to render verification URL as a QR code | error=%v", err)
+		return
+	}
+	fmt.Printf("   Scan to sign in on another device:\n\n")
+	if err := code.Render(os.Stdout); err != nil {
+		logging.LogWarn("Failed to print QR code | error=%v", err)
+	}
+	fmt.Println()
+}
+
 // performSSOLogin initiates the SSO login flow
-func (m *Manager) performSSOLogin(ctx context.Context, awsCfg aws.Config, cfg *appconfig.Config) error {
+func (m *Manager) performSSOLogin(ctx context.Context, awsCfg aws.Config, cfg *appconfig.Config, opts LoginOptions) error {
 	logging.LogInfo("Starting SSO device authorization flow...")
 
 	// Create SSO OIDC client
@@ -759,16 +948,25 @@ func (m *Manager) performSSOLogin(ctx context.Context, awsCfg aws.Config, cfg *a
 	userCode := aws.ToString(authResp.UserCode)
 
 	fmt.Printf("\n🔐 AWS SSO Authentication Required\n")
-	fmt.Printf("   Opening browser automatically to: %s\n", authURL)
-	fmt.Printf("   If browser doesn't open, copy the URL above\n")
-	fmt.Printf("   Your verification code: %s\n\n", userCode)
-
-	// Attempt to open browser automatically
-	if err := browser.OpenURL(authURL); err != nil {
-		logging.LogWarn("Failed to open browser automatically | error=%v", err)
-		fmt.Printf("⚠️  Please manually open the URL above in your browser\n")
+	if opts.QR {
+		printVerificationQR(authURL)
+	}
+	if open, browserPath := opts.browserSettings(cfg.SSO); !open {
+		// Headless or SSH sessions: the URL can be opened on any device
+		fmt.Printf("   Open this URL in a browser on any device: %s\n", authURL)
+		fmt.Printf("   Your verification code: %s\n\n", userCode)
 	} else {
-		fmt.Printf("✅ Browser opened automatically\n")
+		fmt.Printf("   Opening browser automatically to: %s\n", authURL)
+		fmt.Printf("   If browser doesn't open, copy the URL above\n")
+		fmt.Printf("   Your verification code: %s\n\n", userCode)
+
+		// Attempt to open browser automatically
+		if err := openBrowser(authURL, browserPath); err != nil {
+			logging.LogWarn("Failed to open browser automatically | error=%v", err)
+			fmt.Printf("⚠️  Please manually open the URL above in your browser\n")
+		} else {
+			fmt.Printf("✅ Browser opened automatically\n")
+		}
 	}
 
 	fmt.Printf("⏳ Waiting for authentication completion (do not close this terminal)...\n\n")
@@ -781,12 +979,13 @@ func (m *Manager) performSSOLogin(ctx context.Context, awsCfg aws.Config, cfg *a
 
 	// Use intelligent timeout: respect AWS timeout but ensure reasonable minimum
 	// This balances security with usability
+	minTimeout, maxTimeout := loginTimeoutBounds(cfg)
 	timeoutSeconds := authResp.ExpiresIn
 
-	if timeoutSeconds < MinTimeoutSeconds {
-		timeoutSeconds = MinTimeoutSeconds
-	} else if timeoutSeconds > MaxTimeoutSeconds {
-		timeoutSeconds = MaxTimeoutSeconds
+	if timeoutSeconds < minTimeout {
+		timeoutSeconds = minTimeout
+	} else if timeoutSeconds > maxTimeout {
+		timeoutSeconds = maxTimeout
 	}
 
 	timeout := time.After(time.Duration(timeoutSeconds) * time.Second)
@@ -812,6 +1011,8 @@ func (m *Manager) performSSOLogin(ctx context.Context, awsCfg aws.Config, cfg *a
 		select {
 		case <-timeout:
 			return fmt.Errorf("SSO login timed out after %d seconds", timeoutSeconds)
+		case <-ctx.Done():
+			return fmt.Errorf("SSO login cancelled: %w", ctx.Err())
 		case <-ticker.C:
 			tokenResp, err := ssoOIDCClient.CreateToken(ctx, &ssooidc.CreateTokenInput{
 				ClientId:     registerResp.ClientId,
@@ -844,16 +1045,9 @@ func (m *Manager) performSSOLogin(ctx context.Context, awsCfg aws.Config, cfg *a
 	}
 }
 
-// saveTokenToCache saves an SSO token to the AWS cache
+// saveTokenToCache saves an SSO token to the AWS cache, or to the encrypted ztictl cache
+// when sso.encrypt_token_cache is set
 func (m *Manager) saveTokenToCache(tokenResp *ssooidc.CreateTokenOutput, startURL, region string) error {
-	cacheDir, err := getAWSCacheDir()
-	if err != nil {
-		return err
-	}
-	if err := os.MkdirAll(cacheDir, 0750); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
-	}
-
 	// Create token structure
 	expiresAt := time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
 	token := SSOToken{
@@ -863,16 +1057,20 @@ func (m *Manager) saveTokenToCache(tokenResp *ssooidc.CreateTokenOutput, startUR
 		ExpiresAt:   expiresAt,
 	}
 
+	if encryptedCacheEnabled() {
+		return saveEncryptedToken(token)
+	}
+
+	cacheDir, err := getAWSCacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir, 0750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
 	// Generate cache filename (AWS CLI compatible using SHA1)
-	// SECURITY NOTE: SHA1 is cryptographically weak but required for AWS CLI compatibility.
-	// The hash is only used for cache filename generation, not for security purposes.
-	// AWS CLI expects SHA1-based filenames in ~/.aws/sso/cache/
-	// TODO: Monitor AWS CLI for migration to SHA256 or other secure alternatives
-	hasher := sha1.New() // #nosec G401 -- SHA1 required for AWS CLI compatibility
-	hasher.Write([]byte(startURL))
-	hash := hex.EncodeToString(hasher.Sum(nil))
-	filename := fmt.Sprintf("%s.json", hash)
-	cachePath := filepath.Join(cacheDir, filename)
+	cachePath := filepath.Join(cacheDir, tokenCacheFileName(startURL))
 
 	// Save to file
 	data, err := json.MarshalIndent(token, "", "  ")
@@ -1521,6 +1719,12 @@ func (m *Manager) parseProfiles(content string) []Profile {
 
 // isProfileAuthenticated checks if a profile has valid cached tokens
 func (m *Manager) IsProfileAuthenticated(profileName string) bool {
+	if encryptedCacheEnabled() {
+		if token, err := loadEncryptedToken(appconfig.Get().SSO.StartURL); err == nil && m.isTokenValid(token) {
+			return true
+		}
+	}
+
 	// Check if SSO token cache exists and is valid
 	configDir, err := getAWSCacheDir()
 	if err != nil {
@@ -1580,7 +1784,9 @@ func (m *Manager) isProfileAuthenticated(ctx context.Context, profileName string
 	}
 
 	// Fallback to trying AWS API call
-	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithSharedConfigProfile(profileName))
+	awsCfg, err := config.LoadDefaultConfig(ctx, append([]func(*config.LoadOptions) error{
+		config.WithSharedConfigProfile(profileName),
+	}, CredentialLoadOptions(ctx, profileName)...)...)
 	if err != nil {
 		return false, nil
 	}
@@ -1634,10 +1840,3 @@ func (m *Manager) printSuccessMessage(profileName string, account *Account, role
 	_, _ = infoColor.Println("To list EC2 instances, run:") // #nosec G104
 	_, _ = commandColor.Printf("ztictl ssm list\n")         // #nosec G104
 }
-
-func (m *Manager) clearSSOCache() error {
-	// For Windows compatibility, we'll disable automatic cache clearing
-	// Users can manually clear cache using AWS CLI: aws sso logout
-	logging.LogInfo("Cache clearing disabled for security compatibility")
-	return nil
-}
@@ -3,6 +3,9 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -341,6 +344,67 @@ func TestRoleStructure(t *testing.T) {
 	}
 }
 
+func TestLoginTimeoutBounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		sso       config.SSOConfig
+		wantMin   int32
+		wantMax   int32
+		nilConfig bool
+	}{
+		{name: "nil config uses defaults", nilConfig: true, wantMin: MinTimeoutSeconds, wantMax: MaxTimeoutSeconds},
+		{name: "unset bounds use defaults", wantMin: MinTimeoutSeconds, wantMax: MaxTimeoutSeconds},
+		{name: "configured bounds", sso: config.SSOConfig{MinTimeout: 120, MaxTimeout: 600}, wantMin: 120, wantMax: 600},
+		{name: "min above default max raises max", sso: config.SSOConfig{MinTimeout: 300}, wantMin: 300, wantMax: 300},
+		{name: "max below default min lowers min", sso: config.SSOConfig{MaxTimeout: 45}, wantMin: 45, wantMax: 45},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{SSO: tt.sso}
+			if tt.nilConfig {
+				cfg = nil
+			}
+			gotMin, gotMax := loginTimeoutBounds(cfg)
+			if gotMin != tt.wantMin || gotMax != tt.wantMax {
+				t.Errorf("loginTimeoutBounds() = (%d, %d), want (%d, %d)", gotMin, gotMax, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestLoginOptionsBrowserSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     LoginOptions
+		sso      config.SSOConfig
+		wantOpen bool
+		wantPath string
+	}{
+		{name: "system default browser", wantOpen: true},
+		{name: "configured browser", sso: config.SSOConfig{Browser: "firefox"}, wantOpen: true, wantPath: "firefox"},
+		{name: "flag overrides configured browser", opts: LoginOptions{Browser: "/usr/bin/chromium"}, sso: config.SSOConfig{Browser: "firefox"}, wantOpen: true, wantPath: "/usr/bin/chromium"},
+		{name: "no_browser in config", sso: config.SSOConfig{NoBrowser: true, Browser: "firefox"}},
+		{name: "no-browser flag", opts: LoginOptions{NoBrowser: true}, sso: config.SSOConfig{Browser: "firefox"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotOpen, gotPath := tt.opts.browserSettings(tt.sso)
+			if gotOpen != tt.wantOpen || gotPath != tt.wantPath {
+				t.Errorf("browserSettings() = (%v, %q), want (%v, %q)", gotOpen, gotPath, tt.wantOpen, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestOpenBrowserMissingBinary(t *testing.T) {
+	err := openBrowser("https://device.sso.example.com/", filepath.Join(t.TempDir(), "no-such-browser"))
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a not found error for a missing browser binary, got %v", err)
+	}
+}
+
 func TestConstants(t *testing.T) {
 	// Test timeout constants
 	if MinTimeoutSeconds != 60 {
@@ -1356,3 +1420,138 @@ func TestValidateFilePath(t *testing.T) {
 		})
 	}
 }
+
+func TestCachedTokenExpiry(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("USERPROFILE", tempDir)
+
+	manager := NewManager()
+	startURL := "https://test.awsapps.com/start"
+
+	if got := manager.cachedTokenExpiry(""); got != nil {
+		t.Errorf("Expected nil expiry for empty start URL, got %v", got)
+	}
+
+	if got := manager.cachedTokenExpiry(startURL); got != nil {
+		t.Errorf("Expected nil expiry when cache directory is missing, got %v", got)
+	}
+
+	cacheDir := filepath.Join(tempDir, ".aws", "sso", "cache")
+	if err := os.MkdirAll(cacheDir, 0750); err != nil {
+		t.Fatalf("Failed to create cache dir: %v", err)
+	}
+
+	expiresAt := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+	data, err := json.Marshal(SSOToken{StartURL: startURL, Region: "us-east-1", AccessToken: "token", ExpiresAt: expiresAt})
+	if err != nil {
+		t.Fatalf("Failed to marshal token: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "token.json"), data, 0600); err != nil {
+		t.Fatalf("Failed to write token: %v", err)
+	}
+
+	got := manager.cachedTokenExpiry(startURL)
+	if got == nil {
+		t.Fatal("Expected expiry to be read from token cache")
+	}
+	if !got.Equal(expiresAt) {
+		t.Errorf("Expected expiry %v, got %v", expiresAt, *got)
+	}
+
+	if other := manager.cachedTokenExpiry("https://other.awsapps.com/start"); other != nil {
+		t.Errorf("Expected nil expiry for unrelated start URL, got %v", other)
+	}
+}
+
+func TestImdsSettingsFromEnv(t *testing.T) {
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", "")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+	t.Setenv("AWS_EC2_METADATA_V1_DISABLED", "")
+
+	settings := imdsSettingsFromEnv()
+	if settings.Endpoint != DefaultIMDSEndpoint || settings.Disabled || settings.V1Disabled {
+		t.Errorf("Unexpected default settings: %+v", settings)
+	}
+
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", "http://imds-proxy:8080/")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "TRUE")
+	t.Setenv("AWS_EC2_METADATA_V1_DISABLED", "true")
+
+	settings = imdsSettingsFromEnv()
+	if settings.Endpoint != "http://imds-proxy:8080" {
+		t.Errorf("Expected trailing slash to be trimmed, got %q", settings.Endpoint)
+	}
+	if !settings.Disabled || !settings.V1Disabled {
+		t.Errorf("Expected IMDS and IMDSv1 to be disabled: %+v", settings)
+	}
+}
+
+func TestProbeIMDS(t *testing.T) {
+	client := &http.Client{Timeout: time.Second}
+
+	t.Run("disabled returns false without probing", func(t *testing.T) {
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		if probeIMDS(client, imdsSettings{Endpoint: server.URL, Disabled: true}) {
+			t.Error("Expected false when IMDS is disabled")
+		}
+		if called {
+			t.Error("Expected no request when IMDS is disabled")
+		}
+	})
+
+	t.Run("IMDSv2 token endpoint", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut || r.URL.Path != "/latest/api/token" {
+				t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			}
+			if r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds") == "" {
+				t.Error("Expected token TTL header")
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		if !probeIMDS(client, imdsSettings{Endpoint: server.URL, V1Disabled: true}) {
+			t.Error("Expected IMDSv2 endpoint to be detected")
+		}
+	})
+
+	t.Run("unreachable endpoint skips v1 fallback when v1 disabled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		endpoint := server.URL
+		server.Close()
+
+		if probeIMDS(client, imdsSettings{Endpoint: endpoint, V1Disabled: true}) {
+			t.Error("Expected false for unreachable endpoint")
+		}
+	})
+
+	t.Run("non-metadata response is not EC2", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer server.Close()
+
+		if probeIMDS(client, imdsSettings{Endpoint: server.URL}) {
+			t.Error("Expected false for forbidden response")
+		}
+	})
+}
+
+func TestIsEC2InstanceRespectsDisabledEnv(t *testing.T) {
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	start := time.Now()
+	if isEC2Instance() {
+		t.Error("Expected false when AWS_EC2_METADATA_DISABLED=true")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected immediate return when IMDS is disabled, took %v", elapsed)
+	}
+}
@@ -0,0 +1,427 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	appconfig "ztictl/internal/config"
+	"ztictl/pkg/security"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+)
+
+const (
+	// keychainService and keychainAccount identify the token cache key in the OS keychain
+	keychainService = "ztictl"
+	keychainAccount = "sso-token-cache"
+
+	// encryptedTokenSuffix replaces .json on token files in the encrypted cache
+	encryptedTokenSuffix = ".enc"
+)
+
+// errCacheKeyNotFound is returned by a tokenKeyStore that holds no key yet
+var errCacheKeyNotFound = errors.New("token cache key not found")
+
+// tokenKeyStore holds the AES key that encrypts the ztictl token cache
+type tokenKeyStore interface {
+	Load() ([]byte, error)
+	Store(key []byte) error
+}
+
+// cacheKeyStore is the key store used for the encrypted token cache; tests replace it
+var cacheKeyStore tokenKeyStore = osKeyStore{}
+
+// encryptedCacheEnabled reports whether sso.encrypt_token_cache is set
+func encryptedCacheEnabled() bool {
+	return appconfig.Get().SSO.EncryptTokenCache
+}
+
+// getEncryptedCacheDir returns the directory of the encrypted token cache (~/.ztictl/sso/cache)
+func getEncryptedCacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	cacheDir := filepath.Join(homeDir, ".ztictl", "sso", "cache")
+	if err := security.ValidateFilePath(cacheDir, homeDir); err != nil {
+		return "", fmt.Errorf("invalid token cache directory path: %w", err)
+	}
+	return cacheDir, nil
+}
+
+// encryptedTokenFileName returns the encrypted cache file name for a start URL's SSO token
+func encryptedTokenFileName(startURL string) string {
+	return strings.TrimSuffix(tokenCacheFileName(startURL), ".json") + encryptedTokenSuffix
+}
+
+// tokenCacheKey returns the token cache key, creating and storing one on first use
+func tokenCacheKey() ([]byte, error) {
+	key, err := cacheKeyStore.Load()
+	if err == nil {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("token cache key in the OS keychain has an invalid length")
+		}
+		return key, nil
+	}
+	if !errors.Is(err, errCacheKeyNotFound) {
+		return nil, fmt.Errorf("failed to read token cache key from the OS keychain: %w", err)
+	}
+
+	key = make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, fmt.Errorf("failed to generate token cache key: %w", err)
+	}
+	if err := cacheKeyStore.Store(key); err != nil {
+		return nil, fmt.Errorf("failed to save token cache key to the OS keychain: %w", err)
+	}
+	return key, nil
+}
+
+// sealToken encrypts data with AES-GCM, prefixing the nonce
+func sealToken(key, data []byte) ([]byte, error) {
+	gcm, err := newTokenCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// openToken decrypts data produced by sealToken
+func openToken(key, sealed []byte) ([]byte, error) {
+	gcm, err := newTokenCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted token is truncated")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	data, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token (was the keychain entry replaced?): %w", err)
+	}
+	return data, nil
+}
+
+func newTokenCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token cache key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// saveEncryptedToken writes an SSO token to the encrypted cache
+func saveEncryptedToken(token SSOToken) error {
+	cacheDir, err := getEncryptedCacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	key, err := tokenCacheKey()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+	sealed, err := sealToken(key, data)
+	if err != nil {
+		return err
+	}
+
+	cachePath := filepath.Join(cacheDir, encryptedTokenFileName(token.StartURL))
+	if err := os.WriteFile(cachePath, sealed, 0600); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// loadEncryptedToken reads a start URL's SSO token from the encrypted cache
+func loadEncryptedToken(startURL string) (*SSOToken, error) {
+	cacheDir, err := getEncryptedCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	cachePath := filepath.Join(cacheDir, encryptedTokenFileName(startURL))
+	if err := security.ValidateFilePath(cachePath, cacheDir); err != nil {
+		return nil, fmt.Errorf("invalid cache file path: %w", err)
+	}
+
+	sealed, err := os.ReadFile(cachePath) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("no encrypted token cached for start URL %s: %w", startURL, err)
+	}
+	key, err := tokenCacheKey()
+	if err != nil {
+		return nil, err
+	}
+	data, err := openToken(key, sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	var token SSOToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+	if token.StartURL != startURL {
+		return nil, fmt.Errorf("no encrypted token cached for start URL %s", startURL)
+	}
+	return &token, nil
+}
+
+// findEncryptedTokenFiles returns the encrypted token files for startURL, or all of them when it is empty
+func findEncryptedTokenFiles(startURL string) ([]string, error) {
+	cacheDir, err := getEncryptedCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read encrypted token cache directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !strings.HasSuffix(entry.Name(), encryptedTokenSuffix) {
+			continue
+		}
+		if startURL == "" || entry.Name() == encryptedTokenFileName(startURL) {
+			files = append(files, filepath.Join(cacheDir, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// getRoleCredentialsAPI is the SSO call used to exchange a cached token for role credentials
+type getRoleCredentialsAPI interface {
+	GetRoleCredentials(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error)
+}
+
+// encryptedSSOProvider resolves role credentials for an SSO profile using a token from the
+// encrypted cache, since the SDK's own SSO provider only reads ~/.aws/sso/cache
+type encryptedSSOProvider struct {
+	client    getRoleCredentialsAPI
+	startURL  string
+	accountID string
+	roleName  string
+}
+
+// Retrieve implements aws.CredentialsProvider
+func (p *encryptedSSOProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	token, err := loadEncryptedToken(p.startURL)
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("SSO token not available, run 'ztictl auth login': %w", err)
+	}
+	if !time.Now().Before(token.ExpiresAt) {
+		return aws.Credentials{}, fmt.Errorf("SSO token expired at %s, run 'ztictl auth login'", token.ExpiresAt.Format(time.RFC3339))
+	}
+
+	out, err := p.client.GetRoleCredentials(ctx, &sso.GetRoleCredentialsInput{
+		AccessToken: aws.String(token.AccessToken),
+		AccountId:   aws.String(p.accountID),
+		RoleName:    aws.String(p.roleName),
+	})
+	if err != nil {
+		return aws.Credentials{}, fmt.Errorf("failed to get role credentials: %w", err)
+	}
+
+	rc := out.RoleCredentials
+	return aws.Credentials{
+		AccessKeyID:     aws.ToString(rc.AccessKeyId),
+		SecretAccessKey: aws.ToString(rc.SecretAccessKey),
+		SessionToken:    aws.ToString(rc.SessionToken),
+		CanExpire:       true,
+		Expires:         time.UnixMilli(rc.Expiration),
+		AccountID:       p.accountID,
+		Source:          "ztictl encrypted SSO cache",
+	}, nil
+}
+
+// CredentialLoadOptions returns config load options that resolve SSO profile credentials from the
+// encrypted token cache when sso.encrypt_token_cache is set. An empty profile means AWS_PROFILE,
+// or the default profile. Nothing is returned for other credential sources.
+func CredentialLoadOptions(ctx context.Context, profile string) []func(*config.LoadOptions) error {
+	if !encryptedCacheEnabled() {
+		return nil
+	}
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	shared, err := config.LoadSharedConfigProfile(ctx, profile, func(o *config.LoadSharedConfigOptions) {
+		if configFile := os.Getenv("AWS_CONFIG_FILE"); configFile != "" {
+			o.ConfigFiles = []string{configFile}
+		}
+	})
+	if err != nil {
+		return nil
+	}
+	startURL, region := shared.SSOStartURL, shared.SSORegion
+	if shared.SSOSession != nil {
+		startURL, region = shared.SSOSession.SSOStartURL, shared.SSOSession.SSORegion
+	}
+	if startURL == "" || shared.SSOAccountID == "" || shared.SSORoleName == "" {
+		return nil
+	}
+
+	provider := &encryptedSSOProvider{
+		client:    sso.New(sso.Options{Region: region}),
+		startURL:  startURL,
+		accountID: shared.SSOAccountID,
+		roleName:  shared.SSORoleName,
+	}
+	return []func(*config.LoadOptions) error{config.WithCredentialsProvider(aws.NewCredentialsCache(provider))}
+}
+
+// osKeyStore keeps the token cache key in the platform keychain: the macOS Keychain through
+// security(1), the Secret Service through secret-tool (libsecret) on Linux, and a DPAPI-protected
+// file on Windows
+type osKeyStore struct{}
+
+// Load implements tokenKeyStore
+func (osKeyStore) Load() ([]byte, error) {
+	var encoded string
+	switch runtime.GOOS {
+	case "darwin":
+		out, err := exec.Command("security", "find-generic-password", "-s", keychainService, "-a", keychainAccount, "-w").Output() // #nosec G204
+		if err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 44 {
+				return nil, errCacheKeyNotFound
+			}
+			return nil, err
+		}
+		encoded = string(out)
+	case "linux":
+		out, err := exec.Command("secret-tool", "lookup", "service", keychainService, "account", keychainAccount).Output() // #nosec G204
+		if err != nil {
+			if _, ok := err.(*exec.ExitError); ok && len(out) == 0 {
+				return nil, errCacheKeyNotFound
+			}
+			return nil, fmt.Errorf("secret-tool lookup failed (is libsecret installed?): %w", err)
+		}
+		encoded = string(out)
+	case "windows":
+		keyPath, err := windowsKeyPath()
+		if err != nil {
+			return nil, err
+		}
+		protected, err := os.ReadFile(keyPath) // #nosec G304
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, errCacheKeyNotFound
+			}
+			return nil, err
+		}
+		out, err := runDPAPI("Unprotect", strings.TrimSpace(string(protected)))
+		if err != nil {
+			return nil, err
+		}
+		encoded = out
+	default:
+		return nil, fmt.Errorf("encrypted token cache is not supported on %s", runtime.GOOS)
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+}
+
+// Store implements tokenKeyStore
+func (osKeyStore) Store(key []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(key)
+	switch runtime.GOOS {
+	case "darwin":
+		// The key goes through stdin in security's interactive mode, so it never appears in the process list
+		cmd := exec.Command("security", "-i") // #nosec G204
+		cmd.Stdin = strings.NewReader(keychainStoreCommand(encoded))
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil || stderr.Len() > 0 {
+			return fmt.Errorf("security add-generic-password failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	case "linux":
+		cmd := exec.Command("secret-tool", "store", "--label=ztictl SSO token cache key", "service", keychainService, "account", keychainAccount) // #nosec G204
+		cmd.Stdin = strings.NewReader(encoded)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("secret-tool store failed (is libsecret installed?): %w", err)
+		}
+		return nil
+	case "windows":
+		keyPath, err := windowsKeyPath()
+		if err != nil {
+			return err
+		}
+		protected, err := runDPAPI("Protect", encoded)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(keyPath, []byte(protected), 0600)
+	default:
+		return fmt.Errorf("encrypted token cache is not supported on %s", runtime.GOOS)
+	}
+}
+
+// keychainStoreCommand is the security(1) interactive-mode line that stores the encoded key in the
+// macOS Keychain, replacing any existing key. The base64 key needs no quoting.
+func keychainStoreCommand(encoded string) string {
+	return fmt.Sprintf("add-generic-password -U -s %s -a %s -l \"ztictl SSO token cache key\" -w %s\n",
+		keychainService, keychainAccount, encoded)
+}
+
+// windowsKeyPath returns the file holding the DPAPI-protected key on Windows
+func windowsKeyPath() (string, error) {
+	cacheDir, err := getEncryptedCacheDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, "key.dpapi"), nil
+}
+
+// runDPAPI protects or unprotects base64 data for the current Windows user through PowerShell
+func runDPAPI(operation, input string) (string, error) {
+	script := fmt.Sprintf("Add-Type -AssemblyName System.Security; "+
+		"$in = [Convert]::FromBase64String([Console]::In.ReadToEnd().Trim()); "+
+		"[Convert]::ToBase64String([Security.Cryptography.ProtectedData]::%s($in, $null, 'CurrentUser'))", operation)
+	cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script) // #nosec G204
+	cmd.Stdin = strings.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("DPAPI %s failed: %w: %s", strings.ToLower(operation), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	appconfig "ztictl/internal/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sso"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/sso/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+)
+
+// memoryKeyStore keeps the token cache key in memory instead of the OS keychain
+type memoryKeyStore struct {
+	key    []byte
+	stores int
+}
+
+func (s *memoryKeyStore) Load() ([]byte, error) {
+	if s.key == nil {
+		return nil, errCacheKeyNotFound
+	}
+	return s.key, nil
+}
+
+func (s *memoryKeyStore) Store(key []byte) error {
+	s.key = append([]byte(nil), key...)
+	s.stores++
+	return nil
+}
+
+// setupEncryptedCache enables sso.encrypt_token_cache with an in-memory key store and a temporary home
+func setupEncryptedCache(t *testing.T) (string, *memoryKeyStore) {
+	t.Helper()
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("USERPROFILE", tempDir)
+
+	store := &memoryKeyStore{}
+	originalStore := cacheKeyStore
+	cacheKeyStore = store
+
+	sso := &appconfig.Get().SSO
+	original := *sso
+	sso.EncryptTokenCache = true
+
+	t.Cleanup(func() {
+		cacheKeyStore = originalStore
+		*sso = original
+	})
+	return tempDir, store
+}
+
+func TestSealAndOpenToken(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	sealed, err := sealToken(key, []byte(`{"accessToken":"secret"}`))
+	if err != nil {
+		t.Fatalf("sealToken failed: %v", err)
+	}
+	if bytes.Contains(sealed, []byte("secret")) {
+		t.Error("Sealed token should not contain the plaintext")
+	}
+
+	opened, err := openToken(key, sealed)
+	if err != nil || string(opened) != `{"accessToken":"secret"}` {
+		t.Errorf("openToken = %q, %v", opened, err)
+	}
+
+	if _, err := openToken(bytes.Repeat([]byte{8}, 32), sealed); err == nil {
+		t.Error("Expected decryption with another key to fail")
+	}
+	if _, err := openToken(key, sealed[:4]); err == nil {
+		t.Error("Expected a truncated token to fail")
+	}
+}
+
+func TestEncryptedTokenCacheRoundTrip(t *testing.T) {
+	tempDir, store := setupEncryptedCache(t)
+	startURL := "https://prod.awsapps.com/start"
+	appconfig.Get().SSO.StartURL = startURL
+
+	manager := NewManager()
+	tokenResp := &ssooidc.CreateTokenOutput{AccessToken: aws.String("access-token"), ExpiresIn: 3600}
+	if err := manager.saveTokenToCache(tokenResp, startURL, "us-east-1"); err != nil {
+		t.Fatalf("saveTokenToCache failed: %v", err)
+	}
+	if store.stores != 1 {
+		t.Errorf("Expected the key to be stored once, got %d", store.stores)
+	}
+
+	if _, err := os.Stat(filepath.Join(tempDir, ".aws", "sso", "cache", tokenCacheFileName(startURL))); !os.IsNotExist(err) {
+		t.Error("Expected no plaintext token in the AWS cache")
+	}
+	encryptedPath := filepath.Join(tempDir, ".ztictl", "sso", "cache", encryptedTokenFileName(startURL))
+	content, err := os.ReadFile(encryptedPath)
+	if err != nil {
+		t.Fatalf("Expected an encrypted token file: %v", err)
+	}
+	if bytes.Contains(content, []byte("access-token")) {
+		t.Error("Encrypted token file should not contain the access token")
+	}
+
+	token, err := manager.getCachedToken(startURL)
+	if err != nil || token.AccessToken != "access-token" {
+		t.Fatalf("getCachedToken = %+v, %v", token, err)
+	}
+	if !manager.IsProfileAuthenticated("") {
+		t.Error("Expected an authenticated profile from the encrypted cache")
+	}
+	if manager.cachedTokenExpiry(startURL) == nil {
+		t.Error("Expected an expiry from the encrypted cache")
+	}
+
+	// A second save reuses the stored key
+	if err := manager.saveTokenToCache(tokenResp, startURL, "us-east-1"); err != nil {
+		t.Fatalf("Second saveTokenToCache failed: %v", err)
+	}
+	if store.stores != 1 {
+		t.Errorf("Expected the key to be reused, got %d stores", store.stores)
+	}
+}
+
+func TestIsProfileAuthenticatedUsesConfiguredStartURL(t *testing.T) {
+	setupEncryptedCache(t)
+	sso := &appconfig.Get().SSO
+	sso.StartURL = "https://prod.awsapps.com/start"
+
+	if NewManager().IsProfileAuthenticated("") {
+		t.Error("Expected no authentication without a cached token")
+	}
+	if err := saveEncryptedToken(SSOToken{StartURL: sso.StartURL, AccessToken: "a", ExpiresAt: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatalf("saveEncryptedToken failed: %v", err)
+	}
+	if NewManager().IsProfileAuthenticated("") {
+		t.Error("Expected an expired encrypted token not to count")
+	}
+}
+
+func TestLogoutRemovesEncryptedTokens(t *testing.T) {
+	setupEncryptedCache(t)
+	prodURL := "https://prod.awsapps.com/start"
+	devURL := "https://dev.awsapps.com/start"
+	expires := time.Now().Add(time.Hour)
+	for _, url := range []string{prodURL, devURL} {
+		if err := saveEncryptedToken(SSOToken{StartURL: url, AccessToken: "a", ExpiresAt: expires}); err != nil {
+			t.Fatalf("saveEncryptedToken failed: %v", err)
+		}
+	}
+
+	files, err := findEncryptedTokenFiles(prodURL)
+	if err != nil || len(files) != 1 || filepath.Base(files[0]) != encryptedTokenFileName(prodURL) {
+		t.Fatalf("findEncryptedTokenFiles(prod) = %v, %v", files, err)
+	}
+
+	removed, err := NewManager().Logout(context.Background(), "", LogoutOptions{All: true})
+	if err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if len(removed) != 2 {
+		t.Errorf("Expected both encrypted tokens removed, got %v", removed)
+	}
+	if files, _ := findEncryptedTokenFiles(""); len(files) != 0 {
+		t.Errorf("Expected an empty encrypted cache, got %v", files)
+	}
+}
+
+// fakeRoleCredentials returns fixed role credentials and records the access token it was given
+type fakeRoleCredentials struct {
+	accessToken string
+}
+
+func (f *fakeRoleCredentials) GetRoleCredentials(ctx context.Context, params *sso.GetRoleCredentialsInput, optFns ...func(*sso.Options)) (*sso.GetRoleCredentialsOutput, error) {
+	f.accessToken = aws.ToString(params.AccessToken)
+	return &sso.GetRoleCredentialsOutput{RoleCredentials: &ssotypes.RoleCredentials{
+		AccessKeyId:     aws.String("AKIAEXAMPLE"),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("session"),
+		Expiration:      time.Now().Add(time.Hour).UnixMilli(),
+	}}, nil
+}
+
+func TestEncryptedSSOProvider(t *testing.T) {
+	setupEncryptedCache(t)
+	startURL := "https://prod.awsapps.com/start"
+	client := &fakeRoleCredentials{}
+	provider := &encryptedSSOProvider{client: client, startURL: startURL, accountID: "123456789012", roleName: "Admin"}
+
+	if _, err := provider.Retrieve(context.Background()); err == nil {
+		t.Error("Expected an error without a cached token")
+	}
+
+	if err := saveEncryptedToken(SSOToken{StartURL: startURL, AccessToken: "expired", ExpiresAt: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatalf("saveEncryptedToken failed: %v", err)
+	}
+	if _, err := provider.Retrieve(context.Background()); err == nil {
+		t.Error("Expected an error for an expired token")
+	}
+
+	if err := saveEncryptedToken(SSOToken{StartURL: startURL, AccessToken: "valid", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("saveEncryptedToken failed: %v", err)
+	}
+	creds, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if client.accessToken != "valid" || creds.AccessKeyID != "AKIAEXAMPLE" || !creds.CanExpire {
+		t.Errorf("Unexpected credentials %+v (token %q)", creds, client.accessToken)
+	}
+}
+
+func TestCredentialLoadOptions(t *testing.T) {
+	tempDir, _ := setupEncryptedCache(t)
+	awsConfig := "[profile sso]\nsso_start_url = https://prod.awsapps.com/start\nsso_region = us-east-1\nsso_account_id = 123456789012\nsso_role_name = Admin\n\n[profile static]\nregion = us-east-1\n"
+	if err := os.MkdirAll(filepath.Join(tempDir, ".aws"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	configPath := filepath.Join(tempDir, ".aws", "config")
+	if err := os.WriteFile(configPath, []byte(awsConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", configPath)
+
+	ctx := context.Background()
+	if opts := CredentialLoadOptions(ctx, "sso"); len(opts) != 1 {
+		t.Errorf("Expected a credentials provider for an SSO profile, got %d options", len(opts))
+	}
+	if opts := CredentialLoadOptions(ctx, "static"); opts != nil {
+		t.Error("Expected no options for a non-SSO profile")
+	}
+
+	appconfig.Get().SSO.EncryptTokenCache = false
+	if opts := CredentialLoadOptions(ctx, "sso"); opts != nil {
+		t.Error("Expected no options with the encrypted cache disabled")
+	}
+}
+
+func TestKeychainStoreCommand(t *testing.T) {
+	line := keychainStoreCommand("c2VjcmV0K2tleQ==")
+	want := `add-generic-password -U -s ztictl -a sso-token-cache -l "ztictl SSO token cache key" -w c2VjcmV0K2tleQ==` + "\n"
+	if line != want {
+		t.Errorf("keychainStoreCommand() = %q, want %q", line, want)
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/spf13/viper"
 
+	"ztictl/internal/platform"
 	"ztictl/pkg/aws"
 	zti_errors "ztictl/pkg/errors"
 )
@@ -30,8 +33,77 @@ type Config struct {
 
 	// Region configuration for multi-region operations
 	Regions RegionConfig `mapstructure:"regions"`
+
+	// Command execution configuration
+	Exec ExecConfig `mapstructure:"exec"`
+
+	// Operation history configuration
+	History HistoryConfig `mapstructure:"history"`
+
+	// Tags applied to the S3 buckets, S3 objects and IAM policies ztictl creates
+	DefaultTags []ResourceTag `mapstructure:"default_tags"`
+
+	// Production safety guard for exec, power and transfer operations
+	Production ProductionConfig `mapstructure:"production"`
+
+	// Operation metrics export
+	Metrics MetricsConfig `mapstructure:"metrics"`
+
+	// Predefined SSO profiles that 'auth login --profile-template' configures without the pickers
+	ProfileTemplates []ProfileTemplate `mapstructure:"profile_templates"`
+}
+
+// ProfileTemplate is a named SSO account, role and region for an AWS profile
+type ProfileTemplate struct {
+	// Template name, also the AWS profile name unless another one is given
+	Name      string `mapstructure:"name"`
+	AccountID string `mapstructure:"account_id"`
+	Role      string `mapstructure:"role"`
+	// Region written to the profile; empty uses default_region
+	Region string `mapstructure:"region"`
+}
+
+// ProfileTemplate returns the profile template with the given name
+func (c *Config) ProfileTemplate(name string) (*ProfileTemplate, error) {
+	for i := range c.ProfileTemplates {
+		if c.ProfileTemplates[i].Name == name {
+			return &c.ProfileTemplates[i], nil
+		}
+	}
+	if len(c.ProfileTemplates) == 0 {
+		return nil, fmt.Errorf("profile template %q not found: no profile_templates are defined in ~/.ztictl.yaml", name)
+	}
+	names := make([]string, len(c.ProfileTemplates))
+	for i, template := range c.ProfileTemplates {
+		names[i] = template.Name
+	}
+	return nil, fmt.Errorf("profile template %q not found (available: %s)", name, strings.Join(names, ", "))
 }
 
+// ResourceTag is a key/value tag applied to AWS resources created by ztictl.
+// Tags are a list rather than a map because configuration map keys are lower-cased on load.
+type ResourceTag struct {
+	Key   string `mapstructure:"key"`
+	Value string `mapstructure:"value"`
+}
+
+// awsAccountIDPattern matches a 12-digit AWS account ID
+var awsAccountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+// MaxDefaultTags is the most default tags allowed, bounded by the S3 object tag limit
+const MaxDefaultTags = 10
+
+// DefaultParallel is the --parallel default when system.default_parallel is unset.
+// Parallel work is concurrent AWS API calls, so it is sized for IO rather than CPU cores.
+const DefaultParallel = 10
+
+// Defaults for state waits such as 'ssm start --wait', when system.wait_poll_interval and
+// system.wait_timeout are unset. Command execution has its own timeouts.
+const (
+	DefaultWaitPollInterval = 5   // seconds between status checks
+	DefaultWaitTimeout      = 300 // seconds before giving up
+)
+
 // SSOConfig represents SSO-specific configuration
 type SSOConfig struct {
 	// SSO start URL
@@ -39,8 +111,30 @@ type SSOConfig struct {
 
 	// SSO region
 	Region string `mapstructure:"region"`
+
+	// Bounds in seconds for how long login waits for device authorization in the browser.
+	// The wait offered by AWS is clamped to this range.
+	MinTimeout int `mapstructure:"min_timeout"`
+	MaxTimeout int `mapstructure:"max_timeout"`
+
+	// Store SSO tokens encrypted in ~/.ztictl/sso/cache, with the key held in the OS keychain,
+	// instead of as plaintext in ~/.aws/sso/cache. The AWS CLI cannot read tokens stored this way.
+	EncryptTokenCache bool `mapstructure:"encrypt_token_cache"`
+
+	// Skip opening a browser during login and only print the verification URL and code,
+	// e.g. on headless servers or over SSH
+	NoBrowser bool `mapstructure:"no_browser"`
+
+	// Browser binary used to open the verification URL instead of the system default
+	Browser string `mapstructure:"browser"`
 }
 
+// Limits accepted for sso.min_timeout and sso.max_timeout, in seconds
+const (
+	MinSSOLoginTimeout = 30
+	MaxSSOLoginTimeout = 3600
+)
+
 // LoggingConfig represents logging configuration
 type LoggingConfig struct {
 	// Log directory path
@@ -66,6 +160,42 @@ type SystemConfig struct {
 
 	// Temporary directory for file operations
 	TempDirectory string `mapstructure:"temp_directory"`
+
+	// Part size in MiB for multipart S3 uploads and downloads of large files
+	S3PartSizeMB int64 `mapstructure:"s3_part_size_mb"`
+
+	// Number of parts transferred concurrently for large S3 transfers
+	S3Concurrency int `mapstructure:"s3_concurrency"`
+
+	// Days before objects in the ztictl transfer bucket expire (the bucket lifecycle rule)
+	S3LifecycleDays int `mapstructure:"s3_lifecycle_days"`
+
+	// Default --parallel for exec and power commands: concurrent AWS API requests, not CPU work
+	DefaultParallel int `mapstructure:"default_parallel"`
+
+	// Session Manager plugin executable or directory, for installs outside PATH
+	SessionManagerPluginPath string `mapstructure:"session_manager_plugin_path"`
+
+	// Seconds instance lookups are cached in ~/.ztictl/cache across runs (0 disables the cache)
+	InstanceCacheTTL int `mapstructure:"instance_cache_ttl"`
+
+	// Seconds between status checks while waiting for instances to change state, e.g. start --wait
+	WaitPollInterval int `mapstructure:"wait_poll_interval"`
+
+	// Seconds to wait for instances to change state before giving up
+	WaitTimeout int `mapstructure:"wait_timeout"`
+
+	// Use dualstack (IPv4 and IPv6) AWS endpoints, for IPv6-only networks
+	UseDualStackEndpoint bool `mapstructure:"use_dualstack_endpoint"`
+
+	// Use FIPS 140 validated AWS endpoints, e.g. for GovCloud or FedRAMP workloads
+	UseFIPSEndpoint bool `mapstructure:"use_fips_endpoint"`
+
+	// Attempts the AWS SDK makes for each API request, retries included (0 keeps the SDK default of 3)
+	AWSMaxAttempts int `mapstructure:"aws_max_attempts"`
+
+	// AWS SDK retry mode, standard or adaptive (empty keeps the SDK default, standard)
+	AWSRetryMode string `mapstructure:"aws_retry_mode"`
 }
 
 // RegionConfig represents region configuration for multi-region operations
@@ -75,6 +205,74 @@ type RegionConfig struct {
 
 	// Enabled regions for the account
 	Enabled []string `mapstructure:"enabled"`
+
+	// Custom shortcodes mapped to full region names, added to the built-in table
+	Shortcodes map[string]string `mapstructure:"shortcodes"`
+}
+
+// ExecConfig represents configuration for SSM command execution
+type ExecConfig struct {
+	// Local shell command run before commands are sent to instances
+	PreHook string `mapstructure:"pre_hook"`
+
+	// Local shell command run after execution results are aggregated
+	PostHook string `mapstructure:"post_hook"`
+
+	// Regular expressions whose matches in command output are replaced with *** before the
+	// output is displayed or written to a report or log file
+	RedactPatterns []string `mapstructure:"redact_patterns"`
+
+	// Also redact the built-in patterns (AWS access keys, bearer tokens, private keys)
+	RedactDefaults bool `mapstructure:"redact_defaults"`
+
+	// Go template rendered around every command before it is sent, with {{.Command}} for the
+	// command and {{.Platform}} for linux or windows
+	CommandWrapperTemplate string `mapstructure:"command_wrapper_template"`
+}
+
+// HistoryConfig represents configuration for the local operation history log
+type HistoryConfig struct {
+	// Enable recording of exec, session and transfer operations
+	Enabled bool `mapstructure:"enabled"`
+
+	// History file path (defaults to ~/.ztictl/history/history.jsonl)
+	Path string `mapstructure:"path"`
+
+	// Store a SHA-256 hash of each command instead of the command text
+	HashCommands bool `mapstructure:"hash_commands"`
+}
+
+// ProductionConfig identifies production targets that need explicit confirmation
+type ProductionConfig struct {
+	// Require confirmation (or --confirm-production) before touching production targets
+	Enabled bool `mapstructure:"enabled"`
+
+	// AWS account IDs treated as production
+	AccountIDs []string `mapstructure:"account_ids"`
+
+	// Instance tags that mark an instance as production; values match case-insensitively
+	Tags []ResourceTag `mapstructure:"tags"`
+}
+
+// AWSRetryModes are the accepted system.aws_retry_mode values
+var AWSRetryModes = []string{"standard", "adaptive"}
+
+// MetricsSinks are the accepted metrics.sink values
+var MetricsSinks = []string{"none", "file", "statsd", "dogstatsd"}
+
+// MetricsConfig configures where operation metrics (exec, transfer and power counts and latencies) are sent
+type MetricsConfig struct {
+	// Sink: none (default), file, statsd or dogstatsd
+	Sink string `mapstructure:"sink"`
+
+	// Summary file for the file sink (defaults to ~/.ztictl/metrics/metrics.json)
+	Path string `mapstructure:"path"`
+
+	// UDP address of the statsd or DogStatsD agent
+	StatsdAddress string `mapstructure:"statsd_address"`
+
+	// Prefix prepended to statsd metric names
+	Prefix string `mapstructure:"prefix"`
 }
 
 var (
@@ -164,8 +362,13 @@ func loadConfigInternal(isFirstRun bool) (*ConfigValidationError, error) {
 		// The user can run 'ztictl config init' later to configure properly
 		cfg = &Config{
 			SSO: SSOConfig{
-				StartURL: "",                            // Will be empty, user needs to configure
-				Region:   viper.GetString("sso.region"), // Defaults to ca-central-1
+				StartURL:          "",                            // Will be empty, user needs to configure
+				Region:            viper.GetString("sso.region"), // Defaults to ca-central-1
+				MinTimeout:        viper.GetInt("sso.min_timeout"),
+				MaxTimeout:        viper.GetInt("sso.max_timeout"),
+				EncryptTokenCache: viper.GetBool("sso.encrypt_token_cache"),
+				NoBrowser:         viper.GetBool("sso.no_browser"),
+				Browser:           viper.GetString("sso.browser"),
 			},
 			DefaultRegion: viper.GetString("default_region"), // Defaults to ca-central-1
 			Logging: LoggingConfig{
@@ -174,13 +377,57 @@ func loadConfigInternal(isFirstRun bool) (*ConfigValidationError, error) {
 				Level:       viper.GetString("logging.level"),
 			},
 			System: SystemConfig{
-				IAMPropagationDelay: viper.GetInt("system.iam_propagation_delay"),
-				FileSizeThreshold:   viper.GetInt64("system.file_size_threshold"),
-				S3BucketPrefix:      viper.GetString("system.s3_bucket_prefix"),
-				TempDirectory:       viper.GetString("system.temp_directory"),
+				IAMPropagationDelay:      viper.GetInt("system.iam_propagation_delay"),
+				FileSizeThreshold:        viper.GetInt64("system.file_size_threshold"),
+				S3BucketPrefix:           viper.GetString("system.s3_bucket_prefix"),
+				TempDirectory:            viper.GetString("system.temp_directory"),
+				S3PartSizeMB:             viper.GetInt64("system.s3_part_size_mb"),
+				S3Concurrency:            viper.GetInt("system.s3_concurrency"),
+				S3LifecycleDays:          viper.GetInt("system.s3_lifecycle_days"),
+				DefaultParallel:          viper.GetInt("system.default_parallel"),
+				InstanceCacheTTL:         viper.GetInt("system.instance_cache_ttl"),
+				WaitPollInterval:         viper.GetInt("system.wait_poll_interval"),
+				WaitTimeout:              viper.GetInt("system.wait_timeout"),
+				SessionManagerPluginPath: expandPath(viper.GetString("system.session_manager_plugin_path")),
+				UseDualStackEndpoint:     viper.GetBool("system.use_dualstack_endpoint"),
+				UseFIPSEndpoint:          viper.GetBool("system.use_fips_endpoint"),
+				AWSMaxAttempts:           viper.GetInt("system.aws_max_attempts"),
+				AWSRetryMode:             viper.GetString("system.aws_retry_mode"),
+			},
+			Exec: ExecConfig{
+				PreHook:        viper.GetString("exec.pre_hook"),
+				PostHook:       viper.GetString("exec.post_hook"),
+				RedactPatterns: viper.GetStringSlice("exec.redact_patterns"),
+				RedactDefaults: viper.GetBool("exec.redact_defaults"),
+
+				CommandWrapperTemplate: viper.GetString("exec.command_wrapper_template"),
+			},
+			History: HistoryConfig{
+				Enabled:      viper.GetBool("history.enabled"),
+				Path:         expandPath(viper.GetString("history.path")),
+				HashCommands: viper.GetBool("history.hash_commands"),
+			},
+			Metrics: MetricsConfig{
+				Sink:          viper.GetString("metrics.sink"),
+				Path:          expandPath(viper.GetString("metrics.path")),
+				StatsdAddress: viper.GetString("metrics.statsd_address"),
+				Prefix:        viper.GetString("metrics.prefix"),
 			},
 		}
 	} else {
+		// Reject unknown keys and mistyped values before viper ignores or coerces them
+		configFile := viper.ConfigFileUsed()
+		if configFile == "" {
+			configFile = getConfigPath()
+		}
+		valErr, err := checkConfigFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+		if valErr != nil {
+			return valErr, fmt.Errorf("invalid configuration: %w", valErr)
+		}
+
 		// Try to load from config file (normal operation)
 		if err := viper.Unmarshal(cfg); err != nil {
 			return nil, zti_errors.NewConfigError("failed to unmarshal configuration", err)
@@ -194,6 +441,9 @@ func loadConfigInternal(isFirstRun bool) (*ConfigValidationError, error) {
 
 		// Expand paths with tilde support
 		cfg.Logging.Directory = expandPath(cfg.Logging.Directory)
+		cfg.History.Path = expandPath(cfg.History.Path)
+		cfg.Metrics.Path = expandPath(cfg.Metrics.Path)
+		cfg.System.SessionManagerPluginPath = expandPath(cfg.System.SessionManagerPluginPath)
 
 		// Validate loaded values and return detailed error
 		if valErr := validateLoadedConfigDetailed(cfg); valErr != nil {
@@ -243,6 +493,10 @@ func setDefaults() {
 
 	// SSO defaults - these should be overridden by user config or .env file
 	viper.SetDefault("sso.region", "ca-central-1")
+	viper.SetDefault("sso.min_timeout", 60)
+	viper.SetDefault("sso.max_timeout", 180)
+	viper.SetDefault("sso.encrypt_token_cache", false)
+	viper.SetDefault("sso.no_browser", false)
 
 	// Logging defaults
 	home, _ := os.UserHomeDir()
@@ -255,6 +509,33 @@ func setDefaults() {
 	viper.SetDefault("system.file_size_threshold", 1048576) // 1MB
 	viper.SetDefault("system.s3_bucket_prefix", "ztictl-ssm-file-transfer")
 	viper.SetDefault("system.temp_directory", os.TempDir()) // Platform-appropriate temp directory
+	viper.SetDefault("system.s3_part_size_mb", 16)
+	viper.SetDefault("system.s3_concurrency", 5)
+	viper.SetDefault("system.s3_lifecycle_days", 1)
+	viper.SetDefault("system.default_parallel", DefaultParallel)
+	viper.SetDefault("system.instance_cache_ttl", 60)
+	viper.SetDefault("system.wait_poll_interval", DefaultWaitPollInterval)
+	viper.SetDefault("system.wait_timeout", DefaultWaitTimeout)
+	viper.SetDefault("system.use_dualstack_endpoint", false)
+	viper.SetDefault("system.use_fips_endpoint", false)
+	viper.SetDefault("system.aws_max_attempts", 0)
+	viper.SetDefault("system.aws_retry_mode", "")
+
+	// Output redaction defaults
+	viper.SetDefault("exec.redact_defaults", true)
+
+	// History defaults (opt-in)
+	viper.SetDefault("history.enabled", false)
+	viper.SetDefault("history.hash_commands", false)
+
+	// Production guard defaults (opt-in)
+	viper.SetDefault("production.enabled", false)
+	viper.SetDefault("production.tags", []ResourceTag{{Key: "Environment", Value: "prod"}})
+
+	// Metrics defaults (disabled)
+	viper.SetDefault("metrics.sink", "none")
+	viper.SetDefault("metrics.statsd_address", "127.0.0.1:8125")
+	viper.SetDefault("metrics.prefix", "ztictl")
 }
 
 // validate validates the configuration
@@ -302,6 +583,21 @@ sso:
   # The AWS region where your SSO is configured
   region: "us-east-1"
 
+  # Bounds in seconds for waiting on browser authorization during login.
+  # Raise max_timeout if MFA prompts regularly take longer than 3 minutes.
+  min_timeout: 60
+  max_timeout: 180
+
+  # Encrypt cached SSO tokens with a key kept in the OS keychain (macOS Keychain,
+  # libsecret via secret-tool on Linux, DPAPI on Windows). The AWS CLI cannot use
+  # tokens cached this way, so run 'aws sso login' separately if you need it.
+  encrypt_token_cache: false
+
+  # Print the login URL and code without opening a browser (headless servers, SSH).
+  # Set browser to a binary such as firefox to use it instead of the default browser.
+  no_browser: false
+  # browser: firefox
+
 # Default AWS region for operations
 default_region: "ca-central-1"
 
@@ -329,6 +625,96 @@ system:
   
   # Temporary directory for file operations (platform-appropriate)
   temp_directory: "%s"
+
+  # Multipart S3 transfer tuning for large files (minimum part size is 5 MiB)
+  s3_part_size_mb: 16
+  s3_concurrency: 5
+
+  # Days before transfer objects in the ztictl S3 bucket expire. After changing it,
+  # run 'ztictl ssm transfer lifecycle' to update existing buckets.
+  s3_lifecycle_days: 1
+
+  # Default --parallel for exec and power commands. This is the number of concurrent
+  # AWS API requests, so size it to your API rate limits rather than CPU cores.
+  default_parallel: 10
+
+  # Seconds to reuse instance lookups (name resolution and instance lists) across
+  # runs. Use --refresh or --no-cache to bypass it for one command, 0 to disable.
+  instance_cache_ttl: 60
+
+  # Polling for state waits such as 'ssm start --wait' (seconds). Raise wait_timeout
+  # for AMIs that take several minutes to boot and register with SSM.
+  wait_poll_interval: 5
+  wait_timeout: 300
+
+  # Location of the Session Manager plugin (executable or directory) when it is
+  # installed outside PATH. The AWS CLI needs it for sessions, ssh and port forwarding.
+  # session_manager_plugin_path: /opt/aws/session-manager-plugin/bin
+
+  # Use dualstack (IPv6) and/or FIPS AWS endpoints instead of the standard ones,
+  # e.g. on IPv6-only networks or in GovCloud. --dualstack and --fips enable them per command.
+  use_dualstack_endpoint: false
+  use_fips_endpoint: false
+
+  # Retries of each AWS API request by the SDK. Raise aws_max_attempts or use the
+  # adaptive retry mode if commands fail with throttling errors. 0 and empty keep
+  # the SDK defaults (3 attempts, standard mode).
+  aws_max_attempts: 0
+  aws_retry_mode: ""
+
+# Command execution configuration
+exec:
+  # Local shell commands run before and after ssm exec, exec-tagged and exec-multi.
+  # Hooks receive ZTICTL_HOOK_STAGE, ZTICTL_REGION, ZTICTL_TARGET_COUNT,
+  # ZTICTL_SUCCESS_COUNT and ZTICTL_FAILURE_COUNT in their environment.
+  pre_hook: ""
+  post_hook: ""
+
+  # Regular expressions (Go RE2 syntax) whose matches in command output are replaced
+  # with *** before display and in reports, chunk files and logs. Built-in patterns
+  # for AWS access keys, bearer tokens and private keys apply unless redact_defaults is false.
+  redact_patterns: []
+  redact_defaults: true
+
+  # Go template wrapped around every command sent by ssm exec, exec-tagged and exec-multi,
+  # e.g. "umask 027\n{{.Command}}". {{.Platform}} is linux or windows.
+  command_wrapper_template: ""
+
+# Operation history (exec, session and transfer audit log in JSONL format)
+history:
+  # Record operations to ~/.ztictl/history/history.jsonl (view with 'ztictl history')
+  enabled: false
+
+  # Store a SHA-256 hash of each command instead of the command text
+  hash_commands: false
+
+# Production guard: exec, power and transfer operations on production targets
+# ask you to type 'production' first, or need --confirm-production when non-interactive
+production:
+  enabled: false
+  # Accounts treated as production (12-digit account IDs)
+  account_ids: []
+  # Instances carrying any of these tags are treated as production
+  tags:
+    - key: "Environment"
+      value: "prod"
+
+# Operation metrics: exec, transfer and power counts and latencies per region
+metrics:
+  # none (default), file (JSON summary of the last run), statsd or dogstatsd (UDP)
+  sink: "none"
+  # Summary file for the file sink (default: ~/.ztictl/metrics/metrics.json)
+  # path: "~/.ztictl/metrics/metrics.json"
+  statsd_address: "127.0.0.1:8125"
+  prefix: "ztictl"
+
+# Profile templates: 'ztictl auth login --profile-template NAME' configures the
+# profile with this account and role instead of asking you to pick them
+# profile_templates:
+#   - name: "prod-admin"
+#     account_id: "123456789012"
+#     role: "AdministratorAccess"
+#     region: "ca-central-1"
 `, logDir, tempDir)
 
 	// Create directory if it doesn't exist
@@ -615,7 +1001,192 @@ func validateLoadedConfigDetailed(cfg *Config) *ConfigValidationError {
 			Message: "invalid AWS region format (expected format: xx-xxxx-n)",
 		}
 	}
+	if valErr := validateSSOTimeouts(cfg.SSO); valErr != nil {
+		return valErr
+	}
+	if valErr := validateDefaultTags(cfg.DefaultTags); valErr != nil {
+		return valErr
+	}
+	if valErr := validateProductionConfig(cfg.Production); valErr != nil {
+		return valErr
+	}
+	if valErr := validateProfileTemplates(cfg.ProfileTemplates); valErr != nil {
+		return valErr
+	}
+	if cfg.Metrics.Sink != "" && !slices.Contains(MetricsSinks, cfg.Metrics.Sink) {
+		return &ConfigValidationError{
+			Field:   "metrics.sink",
+			Value:   cfg.Metrics.Sink,
+			Message: "must be one of: " + strings.Join(MetricsSinks, ", "),
+		}
+	}
+	if cfg.System.DefaultParallel < 0 {
+		return &ConfigValidationError{
+			Field:   "system.default_parallel",
+			Value:   fmt.Sprintf("%d", cfg.System.DefaultParallel),
+			Message: "must be a positive number of concurrent operations",
+		}
+	}
+	if cfg.System.InstanceCacheTTL < 0 {
+		return &ConfigValidationError{
+			Field:   "system.instance_cache_ttl",
+			Value:   fmt.Sprintf("%d", cfg.System.InstanceCacheTTL),
+			Message: "must be a number of seconds, or 0 to disable the cache",
+		}
+	}
+	if cfg.System.WaitPollInterval < 0 {
+		return &ConfigValidationError{
+			Field:   "system.wait_poll_interval",
+			Value:   fmt.Sprintf("%d", cfg.System.WaitPollInterval),
+			Message: "must be a positive number of seconds",
+		}
+	}
+	if cfg.System.WaitTimeout < 0 {
+		return &ConfigValidationError{
+			Field:   "system.wait_timeout",
+			Value:   fmt.Sprintf("%d", cfg.System.WaitTimeout),
+			Message: "must be a positive number of seconds",
+		}
+	}
+	if cfg.System.AWSMaxAttempts < 0 {
+		return &ConfigValidationError{
+			Field:   "system.aws_max_attempts",
+			Value:   fmt.Sprintf("%d", cfg.System.AWSMaxAttempts),
+			Message: "must be a positive number of attempts, or 0 for the SDK default",
+		}
+	}
+	if cfg.System.AWSRetryMode != "" && !slices.Contains(AWSRetryModes, cfg.System.AWSRetryMode) {
+		return &ConfigValidationError{
+			Field:   "system.aws_retry_mode",
+			Value:   cfg.System.AWSRetryMode,
+			Message: "must be one of: " + strings.Join(AWSRetryModes, ", "),
+		}
+	}
+	if cfg.System.S3LifecycleDays < 0 {
+		return &ConfigValidationError{
+			Field:   "system.s3_lifecycle_days",
+			Value:   fmt.Sprintf("%d", cfg.System.S3LifecycleDays),
+			Message: "must be a positive number of days",
+		}
+	}
+	for _, pattern := range cfg.Exec.RedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return &ConfigValidationError{
+				Field:   "exec.redact_patterns",
+				Value:   pattern,
+				Message: "must be a valid regular expression: " + err.Error(),
+			}
+		}
+	}
+	if cfg.Exec.CommandWrapperTemplate != "" {
+		if _, err := platform.ParseCommandTemplate(cfg.Exec.CommandWrapperTemplate); err != nil {
+			return &ConfigValidationError{
+				Field:   "exec.command_wrapper_template",
+				Value:   cfg.Exec.CommandWrapperTemplate,
+				Message: "must be a valid Go template that includes {{.Command}}: " + err.Error(),
+			}
+		}
+	}
+	for code, region := range cfg.Regions.Shortcodes {
+		if !aws.IsValidAWSRegion(region) {
+			return &ConfigValidationError{
+				Field:   fmt.Sprintf("Region shortcode '%s'", code),
+				Value:   region,
+				Message: "invalid AWS region format (expected format: xx-xxxx-n)",
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateSSOTimeouts checks the login timeout bounds; zero leaves the built-in bound in place
+func validateSSOTimeouts(sso SSOConfig) *ConfigValidationError {
+	fields := []struct {
+		name  string
+		value int
+	}{{"sso.min_timeout", sso.MinTimeout}, {"sso.max_timeout", sso.MaxTimeout}}
+
+	for _, field := range fields {
+		if field.value != 0 && (field.value < MinSSOLoginTimeout || field.value > MaxSSOLoginTimeout) {
+			return &ConfigValidationError{
+				Field:   field.name,
+				Value:   fmt.Sprintf("%d", field.value),
+				Message: fmt.Sprintf("must be between %d and %d seconds", MinSSOLoginTimeout, MaxSSOLoginTimeout),
+			}
+		}
+	}
+
+	if sso.MinTimeout != 0 && sso.MaxTimeout != 0 && sso.MinTimeout > sso.MaxTimeout {
+		return &ConfigValidationError{
+			Field:   "sso.min_timeout",
+			Value:   fmt.Sprintf("%d", sso.MinTimeout),
+			Message: fmt.Sprintf("must not be greater than sso.max_timeout (%d)", sso.MaxTimeout),
+		}
+	}
+	return nil
+}
+
+// validateDefaultTags checks default_tags against the AWS tag constraints
+func validateDefaultTags(tags []ResourceTag) *ConfigValidationError {
+	if len(tags) > MaxDefaultTags {
+		return &ConfigValidationError{
+			Field:   "default_tags",
+			Value:   fmt.Sprintf("%d tags", len(tags)),
+			Message: fmt.Sprintf("at most %d tags are allowed (the S3 object tag limit)", MaxDefaultTags),
+		}
+	}
+
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if err := aws.ValidateTag(tag.Key, tag.Value); err != nil {
+			valErr := &aws.ValidationError{}
+			if errors.As(err, &valErr) {
+				return &ConfigValidationError{Field: "default_tags: " + valErr.Field, Value: valErr.Value, Message: valErr.Message}
+			}
+			return &ConfigValidationError{Field: "default_tags", Value: tag.Key, Message: err.Error()}
+		}
+		if seen[tag.Key] {
+			return &ConfigValidationError{Field: "default_tags", Value: tag.Key, Message: "tag key is defined more than once"}
+		}
+		seen[tag.Key] = true
+	}
+	return nil
+}
 
+// validateProductionConfig checks the production account IDs and tags
+func validateProductionConfig(production ProductionConfig) *ConfigValidationError {
+	for _, accountID := range production.AccountIDs {
+		if !awsAccountIDPattern.MatchString(accountID) {
+			return &ConfigValidationError{Field: "production.account_ids", Value: accountID, Message: "must be a 12-digit AWS account ID"}
+		}
+	}
+	for _, tag := range production.Tags {
+		if err := aws.ValidateTag(tag.Key, tag.Value); err != nil {
+			return &ConfigValidationError{Field: "production.tags", Value: tag.Key, Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+// validateProfileTemplates checks that each profile template is named once and has a valid account, role and region
+func validateProfileTemplates(templates []ProfileTemplate) *ConfigValidationError {
+	seen := make(map[string]bool, len(templates))
+	for _, template := range templates {
+		switch {
+		case strings.TrimSpace(template.Name) == "" || strings.ContainsAny(template.Name, "[] \t"):
+			return &ConfigValidationError{Field: "profile_templates.name", Value: template.Name, Message: "must be a profile name without spaces or brackets"}
+		case seen[template.Name]:
+			return &ConfigValidationError{Field: "profile_templates.name", Value: template.Name, Message: "template is defined more than once"}
+		case !awsAccountIDPattern.MatchString(template.AccountID):
+			return &ConfigValidationError{Field: "profile_templates." + template.Name + ".account_id", Value: template.AccountID, Message: "must be a 12-digit AWS account ID"}
+		case strings.TrimSpace(template.Role) == "":
+			return &ConfigValidationError{Field: "profile_templates." + template.Name + ".role", Value: template.Role, Message: "must name an SSO role (permission set)"}
+		case template.Region != "" && !aws.IsValidAWSRegion(template.Region):
+			return &ConfigValidationError{Field: "profile_templates." + template.Name + ".region", Value: template.Region, Message: "invalid AWS region format (expected format: xx-xxxx-n)"}
+		}
+		seen[template.Name] = true
+	}
 	return nil
 }
 
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -122,6 +123,298 @@ func TestValidateLoadedConfigDetailed(t *testing.T) {
 			expectError: true,
 			errorField:  "Default region",
 		},
+		{
+			name: "invalid custom region shortcode",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL: "https://example.awsapps.com/start",
+					Region:   "us-east-1",
+				},
+				DefaultRegion: "us-west-2",
+				Regions:       RegionConfig{Shortcodes: map[string]string{"lab": "not-a-region"}},
+			},
+			expectError: true,
+			errorField:  "Region shortcode 'lab'",
+		},
+		{
+			name: "reserved default tag key",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL: "https://example.awsapps.com/start",
+					Region:   "us-east-1",
+				},
+				DefaultRegion: "us-west-2",
+				DefaultTags:   []ResourceTag{{Key: "aws:owner", Value: "ops"}},
+			},
+			expectError: true,
+			errorField:  "default_tags: Tag key",
+		},
+		{
+			name: "duplicate default tag key",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL: "https://example.awsapps.com/start",
+					Region:   "us-east-1",
+				},
+				DefaultRegion: "us-west-2",
+				DefaultTags:   []ResourceTag{{Key: "Owner", Value: "a"}, {Key: "Owner", Value: "b"}},
+			},
+			expectError: true,
+			errorField:  "default_tags",
+		},
+		{
+			name: "valid default tags",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL: "https://example.awsapps.com/start",
+					Region:   "us-east-1",
+				},
+				DefaultRegion: "us-west-2",
+				DefaultTags:   []ResourceTag{{Key: "CostCenter", Value: "platform"}, {Key: "ManagedBy", Value: "ztictl"}},
+			},
+			expectError: false,
+		},
+		{
+			name: "invalid production account ID",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL: "https://example.awsapps.com/start",
+					Region:   "us-east-1",
+				},
+				DefaultRegion: "us-west-2",
+				Production:    ProductionConfig{Enabled: true, AccountIDs: []string{"1234-5678-9012"}},
+			},
+			expectError: true,
+			errorField:  "production.account_ids",
+		},
+		{
+			name: "invalid production tag key",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL: "https://example.awsapps.com/start",
+					Region:   "us-east-1",
+				},
+				DefaultRegion: "us-west-2",
+				Production:    ProductionConfig{Enabled: true, Tags: []ResourceTag{{Key: "aws:env", Value: "prod"}}},
+			},
+			expectError: true,
+			errorField:  "production.tags",
+		},
+		{
+			name: "valid production settings",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL: "https://example.awsapps.com/start",
+					Region:   "us-east-1",
+				},
+				DefaultRegion: "us-west-2",
+				Production: ProductionConfig{
+					Enabled:    true,
+					AccountIDs: []string{"123456789012"},
+					Tags:       []ResourceTag{{Key: "Environment", Value: "prod"}},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "SSO min timeout above max timeout",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL:   "https://example.awsapps.com/start",
+					Region:     "us-east-1",
+					MinTimeout: 300,
+					MaxTimeout: 120,
+				},
+				DefaultRegion: "us-west-2",
+			},
+			expectError: true,
+			errorField:  "sso.min_timeout",
+		},
+		{
+			name: "SSO max timeout out of range",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL:   "https://example.awsapps.com/start",
+					Region:     "us-east-1",
+					MaxTimeout: 7200,
+				},
+				DefaultRegion: "us-west-2",
+			},
+			expectError: true,
+			errorField:  "sso.max_timeout",
+		},
+		{
+			name: "valid SSO timeouts",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL:   "https://example.awsapps.com/start",
+					Region:     "us-east-1",
+					MinTimeout: 60,
+					MaxTimeout: 600,
+				},
+				DefaultRegion: "us-west-2",
+			},
+			expectError: false,
+		},
+		{
+			name: "negative S3 lifecycle days",
+			config: &Config{
+				SSO: SSOConfig{
+					StartURL: "https://example.awsapps.com/start",
+					Region:   "us-east-1",
+				},
+				DefaultRegion: "us-west-2",
+				System:        SystemConfig{S3LifecycleDays: -1},
+			},
+			expectError: true,
+			errorField:  "system.s3_lifecycle_days",
+		},
+		{
+			name: "negative default parallel",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				System:        SystemConfig{DefaultParallel: -1},
+			},
+			expectError: true,
+			errorField:  "system.default_parallel",
+		},
+		{
+			name: "negative wait poll interval",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				System:        SystemConfig{WaitPollInterval: -5},
+			},
+			expectError: true,
+			errorField:  "system.wait_poll_interval",
+		},
+		{
+			name: "negative wait timeout",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				System:        SystemConfig{WaitTimeout: -1},
+			},
+			expectError: true,
+			errorField:  "system.wait_timeout",
+		},
+		{
+			name: "negative aws max attempts",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				System:        SystemConfig{AWSMaxAttempts: -1},
+			},
+			expectError: true,
+			errorField:  "system.aws_max_attempts",
+		},
+		{
+			name: "unknown aws retry mode",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				System:        SystemConfig{AWSRetryMode: "legacy"},
+			},
+			expectError: true,
+			errorField:  "system.aws_retry_mode",
+		},
+		{
+			name: "adaptive aws retries",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				System:        SystemConfig{AWSMaxAttempts: 10, AWSRetryMode: "adaptive"},
+			},
+			expectError: false,
+		},
+		{
+			name: "unknown metrics sink",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				Metrics:       MetricsConfig{Sink: "prometheus"},
+			},
+			expectError: true,
+			errorField:  "metrics.sink",
+		},
+		{
+			name: "invalid redaction pattern",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				Exec:          ExecConfig{RedactPatterns: []string{`token=\S+`, `(unclosed`}},
+			},
+			expectError: true,
+			errorField:  "exec.redact_patterns",
+		},
+		{
+			name: "command wrapper template without the command",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				Exec:          ExecConfig{CommandWrapperTemplate: `umask 027{{if eq .Platform "linux"}}; {{.Command}}{{end}}`},
+			},
+			expectError: true,
+			errorField:  "exec.command_wrapper_template",
+		},
+		{
+			name: "command wrapper template",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				Exec:          ExecConfig{CommandWrapperTemplate: "umask 027\n{{.Command}}"},
+			},
+			expectError: false,
+		},
+		{
+			name: "statsd metrics sink",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				Metrics:       MetricsConfig{Sink: "statsd"},
+			},
+			expectError: false,
+		},
+		{
+			name: "valid profile templates",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				ProfileTemplates: []ProfileTemplate{
+					{Name: "prod-admin", AccountID: "123456789012", Role: "AdministratorAccess", Region: "ca-central-1"},
+					{Name: "dev-readonly", AccountID: "210987654321", Role: "ReadOnlyAccess"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "duplicate profile template",
+			config: &Config{
+				DefaultRegion: "us-west-2",
+				ProfileTemplates: []ProfileTemplate{
+					{Name: "prod-admin", AccountID: "123456789012", Role: "AdministratorAccess"},
+					{Name: "prod-admin", AccountID: "210987654321", Role: "ReadOnlyAccess"},
+				},
+			},
+			expectError: true,
+			errorField:  "profile_templates.name",
+		},
+		{
+			name: "profile template with invalid account ID",
+			config: &Config{
+				DefaultRegion:    "us-west-2",
+				ProfileTemplates: []ProfileTemplate{{Name: "prod-admin", AccountID: "12345", Role: "AdministratorAccess"}},
+			},
+			expectError: true,
+			errorField:  "profile_templates.prod-admin.account_id",
+		},
+		{
+			name: "profile template without role",
+			config: &Config{
+				DefaultRegion:    "us-west-2",
+				ProfileTemplates: []ProfileTemplate{{Name: "prod-admin", AccountID: "123456789012"}},
+			},
+			expectError: true,
+			errorField:  "profile_templates.prod-admin.role",
+		},
+		{
+			name: "profile template with invalid region",
+			config: &Config{
+				DefaultRegion:    "us-west-2",
+				ProfileTemplates: []ProfileTemplate{{Name: "prod-admin", AccountID: "123456789012", Role: "AdministratorAccess", Region: "canada"}},
+			},
+			expectError: true,
+			errorField:  "profile_templates.prod-admin.region",
+		},
 	}
 
 	for _, tt := range tests {
@@ -157,6 +450,31 @@ func TestConfigDefaults(t *testing.T) {
 	if viper.GetString("sso.region") != "ca-central-1" {
 		t.Errorf("SSO region default not set correctly: %q", viper.GetString("sso.region"))
 	}
+	var loaded Config
+	if err := viper.Unmarshal(&loaded); err != nil {
+		t.Fatalf("Failed to unmarshal defaults: %v", err)
+	}
+	if loaded.Production.Enabled {
+		t.Error("Production guard should be disabled by default")
+	}
+	if len(loaded.Production.Tags) != 1 || loaded.Production.Tags[0] != (ResourceTag{Key: "Environment", Value: "prod"}) {
+		t.Errorf("Expected default production tag Environment=prod, got %+v", loaded.Production.Tags)
+	}
+	if loaded.SSO.MinTimeout != 60 || loaded.SSO.MaxTimeout != 180 {
+		t.Errorf("Expected SSO timeout defaults of 60-180 seconds, got %d-%d", loaded.SSO.MinTimeout, loaded.SSO.MaxTimeout)
+	}
+	if loaded.System.S3LifecycleDays != 1 {
+		t.Errorf("Expected S3 lifecycle default of 1 day, got %d", loaded.System.S3LifecycleDays)
+	}
+	if loaded.System.DefaultParallel != DefaultParallel {
+		t.Errorf("Expected default parallelism of %d, got %d", DefaultParallel, loaded.System.DefaultParallel)
+	}
+	if loaded.System.WaitPollInterval != DefaultWaitPollInterval || loaded.System.WaitTimeout != DefaultWaitTimeout {
+		t.Errorf("Expected wait defaults of %ds/%ds, got %ds/%ds", DefaultWaitPollInterval, DefaultWaitTimeout, loaded.System.WaitPollInterval, loaded.System.WaitTimeout)
+	}
+	if loaded.Metrics.Sink != "none" || loaded.Metrics.StatsdAddress != "127.0.0.1:8125" {
+		t.Errorf("Expected metrics disabled with the local statsd address, got %+v", loaded.Metrics)
+	}
 }
 
 func TestConfigValidation(t *testing.T) {
@@ -458,3 +776,46 @@ func TestCreateSampleConfigErrorHandling(t *testing.T) {
 		t.Error("Expected error for invalid path (file exists where directory expected), got nil")
 	}
 }
+
+func TestDefaultTagsPreserveKeyCase(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+
+	viper.SetConfigType("yaml")
+	yaml := "default_tags:\n  - key: CostCenter\n    value: Platform-42\n"
+	if err := viper.ReadConfig(strings.NewReader(yaml)); err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+
+	var loaded Config
+	if err := viper.Unmarshal(&loaded); err != nil {
+		t.Fatalf("Failed to unmarshal config: %v", err)
+	}
+	if len(loaded.DefaultTags) != 1 || loaded.DefaultTags[0] != (ResourceTag{Key: "CostCenter", Value: "Platform-42"}) {
+		t.Errorf("Expected tag key and value case to be preserved, got %+v", loaded.DefaultTags)
+	}
+}
+
+func TestProfileTemplateLookup(t *testing.T) {
+	cfg := &Config{ProfileTemplates: []ProfileTemplate{
+		{Name: "prod-admin", AccountID: "123456789012", Role: "AdministratorAccess"},
+		{Name: "dev-readonly", AccountID: "210987654321", Role: "ReadOnlyAccess"},
+	}}
+
+	template, err := cfg.ProfileTemplate("dev-readonly")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if template.AccountID != "210987654321" {
+		t.Errorf("Expected the dev-readonly template, got %+v", template)
+	}
+
+	_, err = cfg.ProfileTemplate("staging")
+	if err == nil || !strings.Contains(err.Error(), "prod-admin, dev-readonly") {
+		t.Errorf("Expected an error listing the available templates, got %v", err)
+	}
+
+	if _, err := (&Config{}).ProfileTemplate("prod-admin"); err == nil {
+		t.Error("Expected an error when no templates are defined")
+	}
+}
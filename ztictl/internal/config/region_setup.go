@@ -114,6 +114,7 @@ func parseAndNormalizeRegions(input string) []string {
 	parts := strings.Split(input, ",")
 	var normalized []string
 	seen := make(map[string]bool)
+	resolver := RegionResolver()
 
 	for _, part := range parts {
 		region := strings.TrimSpace(part)
@@ -122,7 +123,7 @@ func parseAndNormalizeRegions(input string) []string {
 		}
 
 		// Check if it's already a shortcode
-		if _, exists := aws.RegionMapping[region]; exists {
+		if resolver.IsShortcode(region) {
 			if !seen[region] {
 				normalized = append(normalized, region)
 				seen[region] = true
@@ -131,7 +132,7 @@ func parseAndNormalizeRegions(input string) []string {
 		}
 
 		// Check if it's a full region name - convert to shortcode
-		shortcode := aws.GetRegionCode(region)
+		shortcode := resolver.Shortcode(region)
 		if shortcode != region {
 			// Successfully found a shortcode
 			if !seen[shortcode] {
@@ -173,11 +174,14 @@ func saveRegionConfig(enabledRegions []string, regionGroups map[string][]string)
 		existingConfig = make(map[string]interface{})
 	}
 
-	// Update only the regions section
-	existingConfig["regions"] = map[string]interface{}{
-		"enabled": enabledRegions,
-		"groups":  regionGroups,
+	// Update only the enabled regions and groups, keeping other region settings such as custom shortcodes
+	regionsSection, ok := existingConfig["regions"].(map[string]interface{})
+	if !ok {
+		regionsSection = make(map[string]interface{})
 	}
+	regionsSection["enabled"] = enabledRegions
+	regionsSection["groups"] = regionGroups
+	existingConfig["regions"] = regionsSection
 
 	// Marshal back to YAML with proper formatting
 	data, err := yaml.Marshal(existingConfig)
@@ -194,35 +198,22 @@ func saveRegionConfig(enabledRegions []string, regionGroups map[string][]string)
 	return Load()
 }
 
+// RegionResolver returns a resolver for the built-in shortcodes plus those configured under regions.shortcodes
+func RegionResolver() *aws.RegionResolver {
+	return aws.NewRegionResolver(Get().Regions.Shortcodes)
+}
+
 // NormalizeRegion converts a region (shortcode or full name) to shortcode
 func NormalizeRegion(region string) string {
-	// If it's already a shortcode, return it
-	if _, exists := aws.RegionMapping[region]; exists {
-		return region
-	}
-
-	// Try to get shortcode from full name
-	shortcode := aws.GetRegionCode(region)
-	if shortcode != region {
-		return shortcode
-	}
-
-	// Return as-is if we can't normalize
-	return region
+	return RegionResolver().Shortcode(region)
 }
 
 // ResolveRegionInput takes a region input (shortcode or full) and returns the full AWS region name
 func ResolveRegionInput(regionInput string) string {
-	// First check if it's a shortcode
-	if fullRegion, exists := aws.RegionMapping[regionInput]; exists {
+	if fullRegion, err := RegionResolver().Resolve(regionInput); err == nil {
 		return fullRegion
 	}
 
-	// Check if it's already a full region name
-	if aws.IsValidAWSRegion(regionInput) {
-		return regionInput
-	}
-
 	// Default: return as-is
 	return regionInput
 }
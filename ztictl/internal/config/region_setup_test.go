@@ -84,8 +84,8 @@ func TestNormalizeRegion(t *testing.T) {
 		},
 		{
 			name:     "Unknown full region unchanged",
-			input:    "il-central-1",
-			expected: "il-central-1",
+			input:    "ap-southeast-9",
+			expected: "ap-southeast-9",
 		},
 		{
 			name:     "Invalid format unchanged",
@@ -120,8 +120,8 @@ func TestResolveRegionInput(t *testing.T) {
 		},
 		{
 			name:     "Unknown but valid format",
-			input:    "il-central-1",
-			expected: "il-central-1",
+			input:    "ap-southeast-9",
+			expected: "ap-southeast-9",
 		},
 		{
 			name:     "Invalid format unchanged",
@@ -260,3 +260,14 @@ func saveConfigForTest(t *testing.T, configPath string, enabledRegions []string,
 	err = os.WriteFile(configPath, data, 0600)
 	require.NoError(t, err)
 }
+
+func TestRegionResolverUsesConfiguredShortcodes(t *testing.T) {
+	cfg := Get()
+	original := cfg.Regions.Shortcodes
+	cfg.Regions.Shortcodes = map[string]string{"tlv": "il-central-1"}
+	defer func() { cfg.Regions.Shortcodes = original }()
+
+	assert.Equal(t, "il-central-1", ResolveRegionInput("tlv"))
+	assert.Equal(t, "tlv", NormalizeRegion("il-central-1"))
+	assert.Equal(t, []string{"cac1", "tlv"}, parseAndNormalizeRegions("ca-central-1,il-central-1"))
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	zti_errors "ztictl/pkg/errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// checkConfigFile strictly checks the config file at path against the Config schema.
+// Unknown keys and values of the wrong type are reported with their full key path
+// (e.g. "system.file_size_threshold"), since viper would otherwise ignore or coerce them.
+func checkConfigFile(path string) (*ConfigValidationError, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is the ztictl config file
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, zti_errors.NewConfigError("failed to read configuration file", err)
+	}
+
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, zti_errors.NewConfigError(fmt.Sprintf("failed to parse %s", path), err)
+	}
+
+	return checkConfigKeys(raw, reflect.TypeOf(Config{}), ""), nil
+}
+
+// checkConfigKeys checks every key in raw against the mapstructure-tagged fields of struct type t.
+// Keys are matched case-insensitively, as viper does. Keys are checked in sorted order so the
+// first error reported is stable.
+func checkConfigKeys(raw map[string]interface{}, t reflect.Type, prefix string) *ConfigValidationError {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag := field.Tag.Get("mapstructure"); tag != "" && tag != "-" {
+			fields[tag] = field
+		}
+	}
+
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path := prefix + key
+		field, ok := fields[strings.ToLower(key)]
+		if !ok {
+			return &ConfigValidationError{Field: path, Value: formatRawValue(raw[key]), Message: unknownKeyMessage(key, fields)}
+		}
+		if valErr := checkConfigValue(raw[key], field.Type, path); valErr != nil {
+			return valErr
+		}
+	}
+	return nil
+}
+
+// checkConfigValue checks that a raw YAML value can be decoded into type t without coercion.
+// Empty values (a key with nothing after the colon) are allowed and leave the default in place.
+func checkConfigValue(value interface{}, t reflect.Type, path string) *ConfigValidationError {
+	if value == nil {
+		return nil
+	}
+
+	mismatch := func() *ConfigValidationError {
+		return &ConfigValidationError{
+			Field:   path,
+			Value:   formatRawValue(value),
+			Message: fmt.Sprintf("must be %s, got %s", describeType(t), describeValue(value)),
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return mismatch()
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return mismatch()
+		}
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		switch value.(type) {
+		case int, int64, uint64:
+		default:
+			return mismatch()
+		}
+	case reflect.Float32, reflect.Float64:
+		// Whole numbers are decoded as integers, and viper converts them without loss
+		switch value.(type) {
+		case float64, int, int64, uint64:
+		default:
+			return mismatch()
+		}
+	case reflect.Ptr:
+		// A pointer field takes the same values as the type it points to
+		return checkConfigValue(value, t.Elem(), path)
+	case reflect.Struct:
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return mismatch()
+		}
+		return checkConfigKeys(nested, t, path+".")
+	case reflect.Slice:
+		items, ok := value.([]interface{})
+		if !ok {
+			return mismatch()
+		}
+		for i, item := range items {
+			if valErr := checkConfigValue(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); valErr != nil {
+				return valErr
+			}
+		}
+	case reflect.Map:
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return mismatch()
+		}
+		// Map keys are user-defined (region groups, shortcodes), so only the values are checked
+		for key, entry := range entries {
+			if valErr := checkConfigValue(entry, t.Elem(), path+"."+key); valErr != nil {
+				return valErr
+			}
+		}
+	}
+	return nil
+}
+
+// unknownKeyMessage explains an unknown key, suggesting the closest known key when there is one
+func unknownKeyMessage(key string, fields map[string]reflect.StructField) string {
+	known := make([]string, 0, len(fields))
+	for name := range fields {
+		known = append(known, name)
+	}
+	sort.Strings(known)
+
+	best, bestDistance := "", len(key)/2+1
+	for _, name := range known {
+		if d := editDistance(strings.ToLower(key), name); d < bestDistance {
+			best, bestDistance = name, d
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("unknown configuration key (did you mean '%s'?)", best)
+	}
+	return fmt.Sprintf("unknown configuration key (expected one of: %s)", strings.Join(known, ", "))
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// describeType names a config field type for error messages
+func describeType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean (true or false)"
+	case reflect.Int, reflect.Int64, reflect.Int32:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Ptr:
+		return describeType(t.Elem())
+	case reflect.Slice:
+		return "a list"
+	default:
+		return "a mapping"
+	}
+}
+
+// describeValue names the type of a raw YAML value for error messages
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "list"
+	case map[string]interface{}:
+		return "mapping"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// formatRawValue renders a raw YAML value for ConfigValidationError.Value
+func formatRawValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		return describeValue(v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
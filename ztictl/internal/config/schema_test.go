@@ -0,0 +1,183 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckConfigFileAcceptsGeneratedConfigs(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("USERPROFILE", tempDir)
+
+	samplePath := filepath.Join(tempDir, "sample.yaml")
+	if err := CreateSampleConfig(samplePath); err != nil {
+		t.Fatalf("CreateSampleConfig failed: %v", err)
+	}
+	if valErr, err := checkConfigFile(samplePath); err != nil || valErr != nil {
+		t.Errorf("Sample config should pass the schema check, got %v / %v", valErr, err)
+	}
+
+	if err := writeInteractiveConfig(&Config{
+		SSO:           SSOConfig{StartURL: "https://d-1234567890.awsapps.com/start", Region: "us-east-1"},
+		DefaultRegion: "ca-central-1",
+		System:        SystemConfig{IAMPropagationDelay: 5, FileSizeThreshold: 1048576},
+	}); err != nil {
+		t.Fatalf("writeInteractiveConfig failed: %v", err)
+	}
+	if valErr, err := checkConfigFile(filepath.Join(tempDir, ".ztictl.yaml")); err != nil || valErr != nil {
+		t.Errorf("Interactive config should pass the schema check, got %v / %v", valErr, err)
+	}
+
+	if valErr, err := checkConfigFile(filepath.Join(tempDir, "missing.yaml")); err != nil || valErr != nil {
+		t.Errorf("A missing config file is not an error, got %v / %v", valErr, err)
+	}
+}
+
+func TestCheckConfigFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantField   string
+		wantMessage string
+	}{
+		{
+			name:    "valid",
+			content: "sso:\n  start_url: https://d-1234567890.awsapps.com/start\n  region: us-east-1\nsystem:\n  file_size_threshold: 2048\nregions:\n  groups:\n    prod: [use1, cac1]\n  shortcodes:\n    aps9: ap-south-9\ndefault_tags:\n  - key: Team\n    value: platform\nexec:\n  pre_hook:\n",
+		},
+		{
+			name:    "keys are case-insensitive like viper",
+			content: "SSO:\n  Start_URL: https://d-1234567890.awsapps.com/start\n",
+		},
+		{
+			name:        "type mismatch",
+			content:     "system:\n  file_size_threshold: big\n",
+			wantField:   "system.file_size_threshold",
+			wantMessage: "must be an integer, got string",
+		},
+		{
+			name:        "typo in key",
+			content:     "sso:\n  start_ulr: https://d-1234567890.awsapps.com/start\n",
+			wantField:   "sso.start_ulr",
+			wantMessage: "did you mean 'start_url'",
+		},
+		{
+			name:        "unrelated unknown key",
+			content:     "colour_scheme: dark\n",
+			wantField:   "colour_scheme",
+			wantMessage: "expected one of:",
+		},
+		{
+			name:        "bool given as string",
+			content:     "history:\n  enabled: \"yes\"\n",
+			wantField:   "history.enabled",
+			wantMessage: "must be a boolean",
+		},
+		{
+			name:        "section given as scalar",
+			content:     "logging: verbose\n",
+			wantField:   "logging",
+			wantMessage: "must be a mapping, got string",
+		},
+		{
+			name:        "list item field",
+			content:     "default_tags:\n  - key: Team\n    vaule: platform\n",
+			wantField:   "default_tags[0].vaule",
+			wantMessage: "did you mean 'value'",
+		},
+		{
+			name:        "region group must be a list",
+			content:     "regions:\n  groups:\n    prod: use1\n",
+			wantField:   "regions.groups.prod",
+			wantMessage: "must be a list, got string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			valErr, err := checkConfigFile(path)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.wantField == "" {
+				if valErr != nil {
+					t.Errorf("Expected no validation error, got %v", valErr)
+				}
+				return
+			}
+			if valErr == nil {
+				t.Fatalf("Expected validation error for %s", tt.wantField)
+			}
+			if valErr.Field != tt.wantField || !strings.Contains(valErr.Message, tt.wantMessage) {
+				t.Errorf("Expected %s: %q, got %s: %q", tt.wantField, tt.wantMessage, valErr.Field, valErr.Message)
+			}
+		})
+	}
+}
+
+func TestCheckConfigValueFloat(t *testing.T) {
+	floatType := reflect.TypeOf(float64(0))
+	for _, value := range []interface{}{1.5, 2, int64(3), uint64(4)} {
+		if valErr := checkConfigValue(value, floatType, "system.ratio"); valErr != nil {
+			t.Errorf("Expected %v to be a valid number, got %v", value, valErr)
+		}
+	}
+
+	valErr := checkConfigValue("fast", reflect.TypeOf(float32(0)), "system.ratio")
+	if valErr == nil {
+		t.Fatal("Expected a string to be rejected for a float field")
+	}
+	if valErr.Field != "system.ratio" || valErr.Message != "must be a number, got string" {
+		t.Errorf("Unexpected validation error %s: %q", valErr.Field, valErr.Message)
+	}
+}
+
+func TestCheckConfigValuePointer(t *testing.T) {
+	type limits struct {
+		MaxRetries *int `mapstructure:"max_retries"`
+	}
+	pointerType := reflect.TypeOf(&limits{})
+
+	if valErr := checkConfigValue(map[string]interface{}{"max_retries": 3}, pointerType, "limits"); valErr != nil {
+		t.Errorf("Expected a pointer to a struct to be checked like the struct, got %v", valErr)
+	}
+	if valErr := checkConfigValue(nil, reflect.TypeOf((*int)(nil)), "limits.max_retries"); valErr != nil {
+		t.Errorf("Expected an empty value to be allowed for a pointer field, got %v", valErr)
+	}
+
+	valErr := checkConfigValue(map[string]interface{}{"max_retries": "many"}, pointerType, "limits")
+	if valErr == nil {
+		t.Fatal("Expected a string to be rejected for a *int field")
+	}
+	if valErr.Field != "limits.max_retries" || valErr.Message != "must be an integer, got string" {
+		t.Errorf("Unexpected validation error %s: %q", valErr.Field, valErr.Message)
+	}
+
+	valErr = checkConfigValue("verbose", pointerType, "limits")
+	if valErr == nil || valErr.Message != "must be a mapping, got string" {
+		t.Errorf("Expected a scalar to be rejected for a pointer to a struct, got %v", valErr)
+	}
+}
+
+func TestCheckConfigFileMalformedYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("sso:\n  start_url: [unclosed\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	valErr, err := checkConfigFile(path)
+	if err == nil || valErr != nil {
+		t.Fatalf("Expected a parse error, got %v / %v", valErr, err)
+	}
+	if !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("Expected parse error message, got %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package history
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"ztictl/pkg/security"
+)
+
+const (
+	// FileName is the name of the JSONL history file
+	FileName = "history.jsonl"
+
+	// filePermissions restricts the history file to the current user
+	filePermissions = 0600
+
+	// dirPermissions restricts the history directory to the current user
+	dirPermissions = 0700
+)
+
+// Entry represents a single recorded operation
+type Entry struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Operation   string    `json:"operation"`
+	Region      string    `json:"region,omitempty"`
+	Targets     []string  `json:"targets,omitempty"`
+	Command     string    `json:"command,omitempty"`
+	CommandHash string    `json:"command_hash,omitempty"`
+}
+
+// Recorder appends entries to a history file
+type Recorder struct {
+	path         string
+	hashCommands bool
+	mu           sync.Mutex
+}
+
+// DefaultPath returns the default history file path (~/.ztictl/history/history.jsonl)
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".ztictl", "history", FileName), nil
+}
+
+// NewRecorder creates a recorder writing to path, or to the default path when empty.
+// When hashCommands is set, commands are stored as SHA-256 hashes instead of plain text.
+func NewRecorder(path string, hashCommands bool) (*Recorder, error) {
+	if path == "" {
+		defaultPath, err := DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+
+	if security.ContainsUnsafePath(path) {
+		return nil, fmt.Errorf("unsafe history file path: %s", path)
+	}
+
+	return &Recorder{
+		path:         filepath.Clean(path),
+		hashCommands: hashCommands,
+	}, nil
+}
+
+// Path returns the history file path
+func (r *Recorder) Path() string {
+	return r.path
+}
+
+// Record appends an entry to the history file as a single JSON line
+func (r *Recorder) Record(entry Entry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+
+	if r.hashCommands && entry.Command != "" {
+		entry.CommandHash = HashCommand(entry.Command)
+		entry.Command = ""
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(r.path), dirPermissions); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePermissions) // #nosec G304 - path validated in NewRecorder
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write history entry: %w", err)
+	}
+
+	return nil
+}
+
+// Read returns all entries in the history file, oldest first.
+// A missing file yields no entries; malformed lines are skipped.
+func Read(path string) ([]Entry, error) {
+	if security.ContainsUnsafePath(path) {
+		return nil, fmt.Errorf("unsafe history file path: %s", path)
+	}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Filter returns entries where the operation, region, targets, command or command hash
+// contain pattern (case-insensitive). An empty pattern matches everything.
+func Filter(entries []Entry, pattern string) []Entry {
+	if pattern == "" {
+		return entries
+	}
+
+	pattern = strings.ToLower(pattern)
+	var matched []Entry
+	for _, entry := range entries {
+		fields := append([]string{entry.Operation, entry.Region, entry.Command, entry.CommandHash}, entry.Targets...)
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(field), pattern) {
+				matched = append(matched, entry)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// Last returns the most recent n entries, or all entries when n <= 0
+func Last(entries []Entry, n int) []Entry {
+	if n <= 0 || n >= len(entries) {
+		return entries
+	}
+	return entries[len(entries)-n:]
+}
+
+// HashCommand returns the hex-encoded SHA-256 hash of a command
+func HashCommand(command string) string {
+	sum := sha256.Sum256([]byte(command))
+	return hex.EncodeToString(sum[:])
+}
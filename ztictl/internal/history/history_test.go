@@ -0,0 +1,135 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorderRecordAndRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", FileName)
+
+	recorder, err := NewRecorder(path, false)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+
+	entries := []Entry{
+		{Operation: "exec", Region: "ca-central-1", Targets: []string{"i-111"}, Command: "uptime"},
+		{Operation: "transfer-upload", Region: "us-east-1", Targets: []string{"i-222"}, Command: "app.tar.gz -> /tmp/app.tar.gz"},
+	}
+	for _, entry := range entries {
+		if err := recorder.Record(entry); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		if perm := info.Mode().Perm(); perm != filePermissions {
+			t.Errorf("Expected file permissions %o, got %o", filePermissions, perm)
+		}
+	}
+
+	got, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(got))
+	}
+	if got[0].Command != "uptime" || got[0].Targets[0] != "i-111" {
+		t.Errorf("Unexpected first entry: %+v", got[0])
+	}
+	if got[1].Timestamp.IsZero() {
+		t.Error("Expected timestamp to be set automatically")
+	}
+}
+
+func TestRecorderHashCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	recorder, err := NewRecorder(path, true)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	if err := recorder.Record(Entry{Operation: "exec", Command: "cat /etc/secret"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - test temp file
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(data), "/etc/secret") {
+		t.Error("Expected plain-text command to be omitted when hashing is enabled")
+	}
+	if !strings.Contains(string(data), HashCommand("cat /etc/secret")) {
+		t.Error("Expected command hash to be recorded")
+	}
+}
+
+func TestNewRecorderRejectsUnsafePath(t *testing.T) {
+	if _, err := NewRecorder("../../etc/history.jsonl", false); err == nil {
+		t.Error("Expected error for path traversal")
+	}
+}
+
+func TestReadMissingAndMalformed(t *testing.T) {
+	dir := t.TempDir()
+
+	entries, err := Read(filepath.Join(dir, "missing.jsonl"))
+	if err != nil || entries != nil {
+		t.Errorf("Expected no entries and no error for missing file, got %v, %v", entries, err)
+	}
+
+	path := filepath.Join(dir, FileName)
+	content := `{"operation":"exec","region":"ca-central-1"}
+not json
+
+{"operation":"connect","region":"us-east-1"}
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = Read(path)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected malformed lines to be skipped, got %d entries", len(entries))
+	}
+}
+
+func TestFilterAndLast(t *testing.T) {
+	now := time.Now()
+	entries := []Entry{
+		{Timestamp: now, Operation: "exec", Region: "ca-central-1", Targets: []string{"i-web1"}, Command: "uptime"},
+		{Timestamp: now, Operation: "connect", Region: "us-east-1", Targets: []string{"i-db1"}},
+		{Timestamp: now, Operation: "exec-tagged", Region: "ca-central-1", Targets: []string{"i-web2"}, Command: "systemctl restart nginx"},
+	}
+
+	if got := Filter(entries, ""); len(got) != 3 {
+		t.Errorf("Expected empty pattern to match all, got %d", len(got))
+	}
+	if got := Filter(entries, "NGINX"); len(got) != 1 || got[0].Operation != "exec-tagged" {
+		t.Errorf("Expected case-insensitive command match, got %+v", got)
+	}
+	if got := Filter(entries, "i-web"); len(got) != 2 {
+		t.Errorf("Expected target match on 2 entries, got %d", len(got))
+	}
+
+	if got := Last(entries, 2); len(got) != 2 || got[0].Operation != "connect" {
+		t.Errorf("Expected last 2 entries, got %+v", got)
+	}
+	if got := Last(entries, 0); len(got) != 3 {
+		t.Errorf("Expected all entries for n=0, got %d", len(got))
+	}
+}
@@ -0,0 +1,157 @@
+// Package idempotency records operations completed under a caller-chosen key, so that a retried
+// invocation of the same operation can be recognized and skipped.
+package idempotency
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"ztictl/pkg/security"
+)
+
+const (
+	// FileName is the name of the JSONL file of completed operations
+	FileName = "completed.jsonl"
+
+	// filePermissions restricts the state file to the current user
+	filePermissions = 0600
+
+	// dirPermissions restricts the state directory to the current user
+	dirPermissions = 0700
+)
+
+// Record is an operation that completed under an idempotency key
+type Record struct {
+	Key           string    `json:"key"`
+	OperationHash string    `json:"operation_hash"`
+	CompletedAt   time.Time `json:"completed_at"`
+}
+
+// Store appends completed operations to a state file and looks them up by key
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// DefaultPath returns the default state file path (~/.ztictl/idempotency/completed.jsonl)
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".ztictl", "idempotency", FileName), nil
+}
+
+// NewStore creates a store backed by path, or by the default path when empty
+func NewStore(path string) (*Store, error) {
+	if path == "" {
+		defaultPath, err := DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+
+	if security.ContainsUnsafePath(path) {
+		return nil, fmt.Errorf("unsafe idempotency state path: %s", path)
+	}
+
+	return &Store{path: filepath.Clean(path)}, nil
+}
+
+// Path returns the state file path
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Lookup returns the latest completed operation recorded under key.
+// A missing file yields no record; malformed lines are skipped.
+func (s *Store) Lookup(key string) (Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Record{}, false, nil
+		}
+		return Record{}, false, fmt.Errorf("failed to open idempotency state: %w", err)
+	}
+	defer file.Close()
+
+	var latest Record
+	found := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			continue
+		}
+		if record.Key == key {
+			latest = record
+			found = true
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Record{}, false, fmt.Errorf("failed to read idempotency state: %w", err)
+	}
+
+	return latest, found, nil
+}
+
+// Complete appends a completed operation as a single JSON line. A later record for the same key
+// replaces earlier ones in Lookup.
+func (s *Store) Complete(record Record) error {
+	if record.CompletedAt.IsZero() {
+		record.CompletedAt = time.Now().UTC()
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode idempotency record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), dirPermissions); err != nil {
+		return fmt.Errorf("failed to create idempotency state directory: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePermissions) // #nosec G304 - path validated in NewStore
+	if err != nil {
+		return fmt.Errorf("failed to open idempotency state: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write idempotency record: %w", err)
+	}
+
+	return nil
+}
+
+// HashOperation returns the hex-encoded SHA-256 hash of the parts that identify an operation.
+// Parts are length-prefixed, so moving text from one part to the next changes the hash.
+func HashOperation(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		_, _ = fmt.Fprintf(hash, "%d:%s\n", len(part), part)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,99 @@
+package idempotency
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStoreCompleteAndLookup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", FileName)
+
+	store, err := NewStore(path)
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	if _, found, err := store.Lookup("deploy-42"); err != nil || found {
+		t.Fatalf("Expected no record before the state file exists, got found=%v, error=%v", found, err)
+	}
+
+	completedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, record := range []Record{
+		{Key: "deploy-42", OperationHash: "aaa", CompletedAt: completedAt},
+		{Key: "deploy-43", OperationHash: "bbb"},
+		{Key: "deploy-42", OperationHash: "ccc", CompletedAt: completedAt.Add(time.Hour)},
+	} {
+		if err := store.Complete(record); err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat() error = %v", err)
+		}
+		if perm := info.Mode().Perm(); perm != filePermissions {
+			t.Errorf("Expected file permissions %o, got %o", filePermissions, perm)
+		}
+	}
+
+	record, found, err := store.Lookup("deploy-42")
+	if err != nil || !found {
+		t.Fatalf("Lookup() = %v, %v", found, err)
+	}
+	if record.OperationHash != "ccc" || !record.CompletedAt.Equal(completedAt.Add(time.Hour)) {
+		t.Errorf("Expected the latest record of the key, got %+v", record)
+	}
+
+	record, found, _ = store.Lookup("deploy-43")
+	if !found || record.OperationHash != "bbb" || record.CompletedAt.IsZero() {
+		t.Errorf("Expected the completion time to be filled in, got %+v", record)
+	}
+
+	if _, found, _ := store.Lookup("deploy-44"); found {
+		t.Error("Expected no record for an unused key")
+	}
+}
+
+func TestStoreLookupSkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	content := "not json\n\n{\"key\":\"nightly\",\"operation_hash\":\"abc\",\"completed_at\":\"2024-03-01T12:00:00Z\"}\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, found, err := store.Lookup("nightly")
+	if err != nil || !found || record.OperationHash != "abc" {
+		t.Errorf("Lookup() = %+v, %v, %v; want the valid record", record, found, err)
+	}
+}
+
+func TestNewStoreRejectsUnsafePath(t *testing.T) {
+	if _, err := NewStore("../../etc/completed.jsonl"); err == nil {
+		t.Error("Expected an unsafe path to be rejected")
+	}
+}
+
+func TestHashOperation(t *testing.T) {
+	hash := HashOperation("ssm exec", "cac1", "i-1234", "systemctl restart nginx")
+	if len(hash) != 64 {
+		t.Errorf("Expected a hex SHA-256 hash, got %q", hash)
+	}
+	if hash != HashOperation("ssm exec", "cac1", "i-1234", "systemctl restart nginx") {
+		t.Error("Expected the hash to be stable")
+	}
+	if hash == HashOperation("ssm exec", "cac1", "i-1234", "systemctl restart nginx ") {
+		t.Error("Expected a different command to change the hash")
+	}
+	if HashOperation("ab", "c") == HashOperation("a", "bc") {
+		t.Error("Expected part boundaries to change the hash")
+	}
+}
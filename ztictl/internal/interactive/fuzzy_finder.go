@@ -32,11 +32,22 @@ func getDisplayItemCount() int {
 
 // FuzzyFind is a generic fuzzy finder function.
 func FuzzyFind(items interface{}, itemFunc func(i int) string, header string, previewFunc func(i, w, h int) string) (int, error) {
+	return fuzzyfinder.Find(items, itemFunc, fuzzyFinderOptions(header, previewFunc)...)
+}
+
+// FuzzyFindMulti is a fuzzy finder that lets the user mark several items with Tab.
+// It returns the indexes of the marked items in the order they were marked, or the
+// item under the cursor if none were marked.
+func FuzzyFindMulti(items interface{}, itemFunc func(i int) string, header string, previewFunc func(i, w, h int) string) ([]int, error) {
+	return fuzzyfinder.FindMulti(items, itemFunc, fuzzyFinderOptions(header, previewFunc)...)
+}
+
+// fuzzyFinderOptions returns the look and behavior shared by all ztictl fuzzy finders
+func fuzzyFinderOptions(header string, previewFunc func(i, w, h int) string) []fuzzyfinder.Option {
 	maxDisplayItems := getDisplayItemCount()
 	totalHeight := maxDisplayItems + 5
 
-	return fuzzyfinder.Find(items,
-		itemFunc,
+	return []fuzzyfinder.Option{
 		fuzzyfinder.WithCursorPosition(fuzzyfinder.CursorPositionBottom),
 		fuzzyfinder.WithPromptString("🔍 Type to search > "),
 		fuzzyfinder.WithHeader(header),
@@ -45,5 +56,5 @@ func FuzzyFind(items interface{}, itemFunc func(i int) string, header string, pr
 		fuzzyfinder.WithHorizontalAlignment(fuzzyfinder.AlignLeft),
 		fuzzyfinder.WithBorder(),
 		fuzzyfinder.WithPreviewWindow(previewFunc),
-	)
+	}
 }
@@ -19,6 +19,7 @@ type Instance struct {
 	SSMStatus        string
 	SSMAgentVersion  string
 	LastPingDateTime string
+	AvailabilityZone string
 	Tags             map[string]string
 }
 
@@ -27,6 +28,11 @@ type InstanceSelector interface {
 	SelectInstance(instances []Instance) (*Instance, error)
 }
 
+// MultiInstanceSelector is an interface for selecting several instances at once.
+type MultiInstanceSelector interface {
+	SelectInstances(instances []Instance) ([]Instance, error)
+}
+
 // FuzzyInstanceSelector is a fuzzy finder for selecting an instance.
 type FuzzyInstanceSelector struct{}
 
@@ -35,6 +41,11 @@ func (s *FuzzyInstanceSelector) SelectInstance(instances []Instance) (*Instance,
 	return SelectInstance(instances, "Select EC2 Instance")
 }
 
+// SelectInstances uses a fuzzy finder to select one or more instances from a list.
+func (s *FuzzyInstanceSelector) SelectInstances(instances []Instance) ([]Instance, error) {
+	return SelectInstances(instances, "Select EC2 Instances")
+}
+
 // SelectInstance provides a common interface for instance selection
 func SelectInstance(instances []Instance, title string) (*Instance, error) {
 	if len(instances) == 0 {
@@ -42,54 +53,44 @@ func SelectInstance(instances []Instance, title string) (*Instance, error) {
 	}
 
 	idx, err := FuzzyFind(instances,
-		func(i int) string {
-			instance := instances[i]
-			name := instance.Name
-			if name == "" {
-				name = "N/A"
-			}
-			return fmt.Sprintf("%s (%s)", name, instance.InstanceID)
-		},
+		func(i int) string { return instanceLabel(instances[i]) },
 		fmt.Sprintf("%s (%d available)", title, len(instances)),
 		func(i, w, h int) string {
 			if i < 0 || i >= len(instances) {
 				return ""
 			}
-			instance := instances[i]
-			name := instance.Name
-			if name == "" {
-				name = "N/A"
-			}
+			return instancePreview(instances[i])
+		},
+	)
 
-			var ssmStatus string
-			switch instance.SSMStatus {
-			case "Online":
-				ssmStatus = colors.ColorSuccess("✓ Online")
-			case "ConnectionLost":
-				ssmStatus = colors.ColorWarning("⚠ Lost")
-			case "No Agent":
-				ssmStatus = colors.ColorError("✗ No Agent")
-			default:
-				if instance.SSMStatus == "" {
-					ssmStatus = colors.ColorError("✗ No Agent")
-				} else {
-					ssmStatus = colors.ColorWarning("? %s", instance.SSMStatus)
-				}
-			}
+	if err != nil {
+		if err.Error() == "abort" {
+			color.New(color.FgRed).Println("❌ Instance selection cancelled")
+			return nil, fmt.Errorf("instance selection cancelled")
+		}
+		return nil, fmt.Errorf("instance selection failed: %w", err)
+	}
 
-			publicIP := instance.PublicIPAddress
-			if publicIP == "" {
-				publicIP = "N/A"
-			}
+	color.New(color.FgGreen, color.Bold).Printf("✅ Selected: %s (%s)\n", instances[idx].Name, instances[idx].InstanceID)
 
-			return fmt.Sprintf("Name:         %s\n"+
-				"Instance ID:  %s\n"+
-				"State:        %s\n"+
-				"Platform:     %s\n"+
-				"Private IP:   %s\n"+
-				"Public IP:    %s\n"+
-				"SSM Status:   %s",
-				name, instance.InstanceID, instance.State, instance.Platform, instance.PrivateIPAddress, publicIP, ssmStatus)
+	return &instances[idx], nil
+}
+
+// SelectInstances lets the user mark several instances with Tab and confirm with Enter.
+// Pressing Enter without marking anything selects the instance under the cursor.
+func SelectInstances(instances []Instance, title string) ([]Instance, error) {
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("no instances available")
+	}
+
+	idxs, err := FuzzyFindMulti(instances,
+		func(i int) string { return instanceLabel(instances[i]) },
+		fmt.Sprintf("%s (%d available, Tab to mark)", title, len(instances)),
+		func(i, w, h int) string {
+			if i < 0 || i >= len(instances) {
+				return ""
+			}
+			return instancePreview(instances[i])
 		},
 	)
 
@@ -101,7 +102,73 @@ func SelectInstance(instances []Instance, title string) (*Instance, error) {
 		return nil, fmt.Errorf("instance selection failed: %w", err)
 	}
 
-	color.New(color.FgGreen, color.Bold).Printf("✅ Selected: %s (%s)\n", instances[idx].Name, instances[idx].InstanceID)
+	selected := selectedInstances(instances, idxs)
+	color.New(color.FgGreen, color.Bold).Printf("✅ Selected %d instance(s)\n", len(selected))
+	for _, instance := range selected {
+		fmt.Printf("   %s\n", instanceLabel(instance))
+	}
 
-	return &instances[idx], nil
+	return selected, nil
+}
+
+// selectedInstances returns the instances at idxs in the given order, ignoring out-of-range
+// and repeated indexes
+func selectedInstances(instances []Instance, idxs []int) []Instance {
+	seen := make(map[int]bool, len(idxs))
+	selected := make([]Instance, 0, len(idxs))
+	for _, idx := range idxs {
+		if idx < 0 || idx >= len(instances) || seen[idx] {
+			continue
+		}
+		seen[idx] = true
+		selected = append(selected, instances[idx])
+	}
+	return selected
+}
+
+// instanceLabel is the line shown for an instance in the finder list
+func instanceLabel(instance Instance) string {
+	name := instance.Name
+	if name == "" {
+		name = "N/A"
+	}
+	return fmt.Sprintf("%s (%s)", name, instance.InstanceID)
+}
+
+// instancePreview renders the details pane for an instance
+func instancePreview(instance Instance) string {
+	name := instance.Name
+	if name == "" {
+		name = "N/A"
+	}
+
+	var ssmStatus string
+	switch instance.SSMStatus {
+	case "Online":
+		ssmStatus = colors.ColorSuccess("✓ Online")
+	case "ConnectionLost":
+		ssmStatus = colors.ColorWarning("⚠ Lost")
+	case "No Agent":
+		ssmStatus = colors.ColorError("✗ No Agent")
+	default:
+		if instance.SSMStatus == "" {
+			ssmStatus = colors.ColorError("✗ No Agent")
+		} else {
+			ssmStatus = colors.ColorWarning("? %s", instance.SSMStatus)
+		}
+	}
+
+	publicIP := instance.PublicIPAddress
+	if publicIP == "" {
+		publicIP = "N/A"
+	}
+
+	return fmt.Sprintf("Name:         %s\n"+
+		"Instance ID:  %s\n"+
+		"State:        %s\n"+
+		"Platform:     %s\n"+
+		"Private IP:   %s\n"+
+		"Public IP:    %s\n"+
+		"SSM Status:   %s",
+		name, instance.InstanceID, instance.State, instance.Platform, instance.PrivateIPAddress, publicIP, ssmStatus)
 }
@@ -1,6 +1,7 @@
 package interactive
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -198,6 +199,39 @@ func TestSelectInstanceEmptyList(t *testing.T) {
 
 func TestFuzzyInstanceSelectorInterface(t *testing.T) {
 	var _ InstanceSelector = (*FuzzyInstanceSelector)(nil)
+	var _ MultiInstanceSelector = (*FuzzyInstanceSelector)(nil)
+}
+
+func TestSelectInstancesEmptyList(t *testing.T) {
+	_, err := SelectInstances(nil, "Select instances")
+	if err == nil || err.Error() != "no instances available" {
+		t.Errorf("Expected 'no instances available' error, got %v", err)
+	}
+}
+
+func TestSelectedInstances(t *testing.T) {
+	instances := []Instance{{InstanceID: "i-0a"}, {InstanceID: "i-0b"}, {InstanceID: "i-0c"}}
+
+	got := selectedInstances(instances, []int{2, 0, 2, 7, -1})
+	if len(got) != 2 || got[0].InstanceID != "i-0c" || got[1].InstanceID != "i-0a" {
+		t.Errorf("Expected [i-0c i-0a] in marking order, got %+v", got)
+	}
+}
+
+func TestInstanceLabelAndPreview(t *testing.T) {
+	if got := instanceLabel(Instance{InstanceID: "i-0a"}); got != "N/A (i-0a)" {
+		t.Errorf("instanceLabel() = %q, want %q", got, "N/A (i-0a)")
+	}
+	if got := instanceLabel(Instance{InstanceID: "i-0a", Name: "web-1"}); got != "web-1 (i-0a)" {
+		t.Errorf("instanceLabel() = %q, want %q", got, "web-1 (i-0a)")
+	}
+
+	preview := instancePreview(Instance{InstanceID: "i-0a", Name: "web-1", PrivateIPAddress: "10.0.0.5", SSMStatus: "Online"})
+	for _, want := range []string{"Name:         web-1", "Instance ID:  i-0a", "Private IP:   10.0.0.5", "Public IP:    N/A", "Online"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("Expected %q in preview, got:\n%s", want, preview)
+		}
+	}
 }
 
 func TestInstanceTagsMap(t *testing.T) {
@@ -0,0 +1,256 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ztictl/pkg/security"
+)
+
+const (
+	// SinkNone disables metrics
+	SinkNone = "none"
+
+	// SinkFile writes a JSON summary file
+	SinkFile = "file"
+
+	// SinkStatsd sends plain statsd metrics over UDP; the region is folded into the metric name
+	SinkStatsd = "statsd"
+
+	// SinkDogStatsd sends DogStatsD metrics over UDP with the region as a tag
+	SinkDogStatsd = "dogstatsd"
+
+	// FileName is the name of the default metrics summary file
+	FileName = "metrics.json"
+)
+
+// Sink receives operation metrics
+type Sink interface {
+	Count(name, region string, value int64)
+	Timing(name, region string, d time.Duration)
+}
+
+var (
+	mu   sync.RWMutex
+	sink Sink
+)
+
+// SetSink installs the sink metrics are sent to; nil disables metrics
+func SetSink(s Sink) {
+	mu.Lock()
+	defer mu.Unlock()
+	sink = s
+}
+
+// current returns the installed sink, or nil when metrics are disabled
+func current() Sink {
+	mu.RLock()
+	defer mu.RUnlock()
+	return sink
+}
+
+// Count adds value to a counter
+func Count(name, region string, value int64) {
+	if s := current(); s != nil {
+		s.Count(name, region, value)
+	}
+}
+
+// Record reports one operation outcome as a <name>.success or <name>.failure count
+// and a <name>.duration timing
+func Record(name, region string, duration time.Duration, succeeded bool) {
+	s := current()
+	if s == nil {
+		return
+	}
+	if succeeded {
+		s.Count(name+".success", region, 1)
+	} else {
+		s.Count(name+".failure", region, 1)
+	}
+	s.Timing(name+".duration", region, duration)
+}
+
+// DefaultPath returns the default summary file path (~/.ztictl/metrics/metrics.json)
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".ztictl", "metrics", FileName), nil
+}
+
+// Stat is the aggregate of one metric in one region
+type Stat struct {
+	Name   string `json:"name"`
+	Region string `json:"region,omitempty"`
+	// Value is the counter total
+	Value int64 `json:"value,omitempty"`
+	// Count is the number of timings recorded
+	Count   int64   `json:"count,omitempty"`
+	TotalMs float64 `json:"total_ms,omitempty"`
+	MinMs   float64 `json:"min_ms,omitempty"`
+	MaxMs   float64 `json:"max_ms,omitempty"`
+}
+
+// Summary is the content of the metrics summary file
+type Summary struct {
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Metrics   []Stat    `json:"metrics"`
+}
+
+// FileSink aggregates metrics for the current run into a JSON summary file.
+// The file is rewritten on every update so it stays complete when the process exits early.
+type FileSink struct {
+	path    string
+	started time.Time
+	stats   map[string]*Stat
+	mu      sync.Mutex
+}
+
+// NewFileSink creates a sink writing to path, or to the default path when empty
+func NewFileSink(path string) (*FileSink, error) {
+	if path == "" {
+		defaultPath, err := DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+
+	if security.ContainsUnsafePath(path) {
+		return nil, fmt.Errorf("unsafe metrics file path: %s", path)
+	}
+
+	return &FileSink{path: filepath.Clean(path), started: time.Now().UTC(), stats: make(map[string]*Stat)}, nil
+}
+
+// Path returns the summary file path
+func (f *FileSink) Path() string {
+	return f.path
+}
+
+// stat returns the aggregate for a metric and region, creating it when missing
+func (f *FileSink) stat(name, region string) *Stat {
+	key := name + "\x00" + region
+	s, ok := f.stats[key]
+	if !ok {
+		s = &Stat{Name: name, Region: region}
+		f.stats[key] = s
+	}
+	return s
+}
+
+func (f *FileSink) Count(name, region string, value int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stat(name, region).Value += value
+	_ = f.write()
+}
+
+func (f *FileSink) Timing(name, region string, d time.Duration) {
+	ms := float64(d.Microseconds()) / 1000
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.stat(name, region)
+	if s.Count == 0 || ms < s.MinMs {
+		s.MinMs = ms
+	}
+	if ms > s.MaxMs {
+		s.MaxMs = ms
+	}
+	s.Count++
+	s.TotalMs += ms
+	_ = f.write()
+}
+
+// Summary returns the metrics aggregated so far, sorted by name and region
+func (f *FileSink) Summary() Summary {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.summary()
+}
+
+func (f *FileSink) summary() Summary {
+	stats := make([]Stat, 0, len(f.stats))
+	for _, s := range f.stats {
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Name != stats[j].Name {
+			return stats[i].Name < stats[j].Name
+		}
+		return stats[i].Region < stats[j].Region
+	})
+	return Summary{StartedAt: f.started, UpdatedAt: time.Now().UTC(), Metrics: stats}
+}
+
+// write replaces the summary file; callers hold f.mu
+func (f *FileSink) write() error {
+	data, err := json.MarshalIndent(f.summary(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics summary: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write metrics summary: %w", err)
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// StatsdSink sends metrics over UDP, fire and forget
+type StatsdSink struct {
+	conn   net.Conn
+	prefix string
+	tags   bool
+}
+
+// NewStatsdSink connects to a statsd server at address. With dogStatsd set, the region is
+// sent as a tag; otherwise it is appended to the metric name.
+func NewStatsdSink(address, prefix string, dogStatsd bool) (*StatsdSink, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to statsd at %s: %w", address, err)
+	}
+	return &StatsdSink{conn: conn, prefix: strings.TrimSuffix(prefix, "."), tags: dogStatsd}, nil
+}
+
+// line formats a single statsd metric
+func (s *StatsdSink) line(name, region, value, metricType string) string {
+	if s.prefix != "" {
+		name = s.prefix + "." + name
+	}
+	if region == "" {
+		return fmt.Sprintf("%s:%s|%s", name, value, metricType)
+	}
+	if s.tags {
+		return fmt.Sprintf("%s:%s|%s|#region:%s", name, value, metricType, region)
+	}
+	return fmt.Sprintf("%s.%s:%s|%s", name, region, value, metricType)
+}
+
+func (s *StatsdSink) Count(name, region string, value int64) {
+	_, _ = s.conn.Write([]byte(s.line(name, region, fmt.Sprintf("%d", value), "c")))
+}
+
+func (s *StatsdSink) Timing(name, region string, d time.Duration) {
+	_, _ = s.conn.Write([]byte(s.line(name, region, fmt.Sprintf("%d", d.Milliseconds()), "ms")))
+}
+
+// Close closes the UDP connection
+func (s *StatsdSink) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// recordingSink keeps the metrics it receives
+type recordingSink struct {
+	counts  map[string]int64
+	timings []string
+}
+
+func (r *recordingSink) Count(name, region string, value int64) {
+	r.counts[name+"/"+region] += value
+}
+
+func (r *recordingSink) Timing(name, region string, d time.Duration) {
+	r.timings = append(r.timings, name+"/"+region)
+}
+
+func TestRecord(t *testing.T) {
+	// Disabled metrics are a no-op
+	SetSink(nil)
+	Record("exec", "us-east-1", time.Second, true)
+	Count("transfer.upload.bytes", "us-east-1", 10)
+
+	sink := &recordingSink{counts: map[string]int64{}}
+	SetSink(sink)
+	defer SetSink(nil)
+
+	Record("exec", "us-east-1", time.Second, true)
+	Record("exec", "us-east-1", time.Second, false)
+	Record("exec", "us-east-1", time.Second, true)
+	Count("transfer.upload.bytes", "eu-west-1", 10)
+
+	if sink.counts["exec.success/us-east-1"] != 2 || sink.counts["exec.failure/us-east-1"] != 1 {
+		t.Errorf("Unexpected counts %v", sink.counts)
+	}
+	if sink.counts["transfer.upload.bytes/eu-west-1"] != 10 {
+		t.Errorf("Expected transferred bytes counted, got %v", sink.counts)
+	}
+	if len(sink.timings) != 3 || sink.timings[0] != "exec.duration/us-east-1" {
+		t.Errorf("Unexpected timings %v", sink.timings)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics", "summary.json")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink failed: %v", err)
+	}
+
+	sink.Count("exec.success", "us-east-1", 1)
+	sink.Count("exec.success", "us-east-1", 1)
+	sink.Timing("exec.duration", "us-east-1", 200*time.Millisecond)
+	sink.Timing("exec.duration", "us-east-1", 100*time.Millisecond)
+	sink.Count("exec.failure", "eu-west-1", 1)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected a summary file: %v", err)
+	}
+	var summary Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("Summary is not valid JSON: %v", err)
+	}
+
+	want := []Stat{
+		{Name: "exec.duration", Region: "us-east-1", Count: 2, TotalMs: 300, MinMs: 100, MaxMs: 200},
+		{Name: "exec.failure", Region: "eu-west-1", Value: 1},
+		{Name: "exec.success", Region: "us-east-1", Value: 2},
+	}
+	if len(summary.Metrics) != len(want) {
+		t.Fatalf("Expected %d metrics, got %+v", len(want), summary.Metrics)
+	}
+	for i := range want {
+		if summary.Metrics[i] != want[i] {
+			t.Errorf("Metric %d = %+v, want %+v", i, summary.Metrics[i], want[i])
+		}
+	}
+}
+
+func TestFileSinkRejectsUnsafePath(t *testing.T) {
+	if _, err := NewFileSink("../../etc/metrics.json"); err == nil {
+		t.Error("Expected an unsafe path to be rejected")
+	}
+}
+
+func TestStatsdSink(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP not available: %v", err)
+	}
+	defer conn.Close()
+
+	read := func() string {
+		buf := make([]byte, 512)
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("No statsd packet received: %v", err)
+		}
+		return string(buf[:n])
+	}
+
+	tests := []struct {
+		name      string
+		dogStatsd bool
+		count     string
+		timing    string
+	}{
+		{name: "statsd", count: "ztictl.exec.success.us-east-1:1|c", timing: "ztictl.exec.duration.us-east-1:1500|ms"},
+		{name: "dogstatsd", dogStatsd: true, count: "ztictl.exec.success:1|c|#region:us-east-1", timing: "ztictl.exec.duration:1500|ms|#region:us-east-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := NewStatsdSink(conn.LocalAddr().String(), "ztictl.", tt.dogStatsd)
+			if err != nil {
+				t.Fatalf("NewStatsdSink failed: %v", err)
+			}
+			defer sink.Close()
+
+			sink.Count("exec.success", "us-east-1", 1)
+			if got := read(); got != tt.count {
+				t.Errorf("Count packet = %q, want %q", got, tt.count)
+			}
+			sink.Timing("exec.duration", "us-east-1", 1500*time.Millisecond)
+			if got := read(); got != tt.timing {
+				t.Errorf("Timing packet = %q, want %q", got, tt.timing)
+			}
+		})
+	}
+
+	if got := (&StatsdSink{}).line("exec.success", "", "1", "c"); got != "exec.success:1|c" {
+		t.Errorf("Expected an unprefixed metric without a region, got %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ztictl/pkg/security"
+)
+
+// Gauge is a Prometheus gauge and its samples
+type Gauge struct {
+	Name    string
+	Help    string
+	Samples []GaugeSample
+}
+
+// GaugeSample is one value of a gauge for a set of labels
+type GaugeSample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// labelEscaper escapes label values as the Prometheus text format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// WriteTextfile writes gauges in the Prometheus text exposition format. Gauges without samples
+// are left out; labels are written in name order.
+func WriteTextfile(w io.Writer, gauges []Gauge) error {
+	bw := bufio.NewWriter(w)
+	for _, gauge := range gauges {
+		if len(gauge.Samples) == 0 {
+			continue
+		}
+		_, _ = fmt.Fprintf(bw, "# HELP %s %s\n", gauge.Name, gauge.Help)
+		_, _ = fmt.Fprintf(bw, "# TYPE %s gauge\n", gauge.Name)
+		for _, sample := range gauge.Samples {
+			_, _ = fmt.Fprintf(bw, "%s%s %s\n", gauge.Name, formatLabels(sample.Labels), strconv.FormatFloat(sample.Value, 'g', -1, 64))
+		}
+	}
+	return bw.Flush()
+}
+
+// formatLabels renders a label set as {name="value",...}, or nothing when empty
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, labelEscaper.Replace(labels[name]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// WriteTextfileAtomic replaces the file at path with gauges. The content is written to a temporary
+// file in the same directory and renamed, so the node_exporter textfile collector never reads a
+// partly written file.
+func WriteTextfileAtomic(path string, gauges []Gauge) error {
+	if security.ContainsUnsafePath(path) {
+		return fmt.Errorf("unsafe textfile path: %s", path)
+	}
+	path = filepath.Clean(path)
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create textfile: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if err := WriteTextfile(tmp, gauges); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write textfile: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write textfile: %w", err)
+	}
+	// #nosec G302 - the textfile collector usually runs as another user and must be able to read it
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to set textfile permissions: %w", err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
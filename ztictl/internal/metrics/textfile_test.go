@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteTextfile(t *testing.T) {
+	gauges := []Gauge{
+		{
+			Name: "ztictl_test_online",
+			Help: "Instances online.",
+			Samples: []GaugeSample{
+				{Labels: map[string]string{"region": "us-east-1", "account": "prod"}, Value: 3},
+				{Labels: map[string]string{"region": `odd"name\`}, Value: 0.5},
+			},
+		},
+		{Name: "ztictl_test_empty", Help: "Left out without samples."},
+		{Name: "ztictl_test_timestamp", Help: "Unlabelled.", Samples: []GaugeSample{{Value: 1700000000}}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteTextfile(&buf, gauges); err != nil {
+		t.Fatalf("WriteTextfile failed: %v", err)
+	}
+
+	want := `# HELP ztictl_test_online Instances online.
+# TYPE ztictl_test_online gauge
+ztictl_test_online{account="prod",region="us-east-1"} 3
+ztictl_test_online{region="odd\"name\\"} 0.5
+# HELP ztictl_test_timestamp Unlabelled.
+# TYPE ztictl_test_timestamp gauge
+ztictl_test_timestamp 1.7e+09
+`
+	if buf.String() != want {
+		t.Errorf("Unexpected textfile:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteTextfileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ztictl.prom")
+	gauges := []Gauge{{Name: "ztictl_test", Help: "Test.", Samples: []GaugeSample{{Value: 1}}}}
+
+	if err := os.WriteFile(path, []byte("stale"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteTextfileAtomic(path, gauges); err != nil {
+		t.Fatalf("WriteTextfileAtomic failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "# HELP ztictl_test Test.\n# TYPE ztictl_test gauge\nztictl_test 1\n" {
+		t.Errorf("Unexpected content %q", data)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the textfile to remain, got %d entries", len(entries))
+	}
+}
+
+func TestWriteTextfileAtomicRejectsUnsafePath(t *testing.T) {
+	if err := WriteTextfileAtomic("../../etc/ztictl.prom", nil); err == nil {
+		t.Error("Expected an unsafe path to be rejected")
+	}
+}
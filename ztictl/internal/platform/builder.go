@@ -2,8 +2,13 @@ package platform
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -16,6 +21,22 @@ type CommandBuilder interface {
 	// BuildExecCommand wraps a command for execution with error handling
 	BuildExecCommand(command string) string
 
+	// BuildSudoCommand wraps a command to run with elevated privileges.
+	// Returns false when the platform has no sudo equivalent and the command is unchanged.
+	BuildSudoCommand(command string) (string, bool)
+
+	// BuildRunAsCommand wraps a command to run as another OS user, in that user's environment.
+	// Returns false when the platform cannot switch users and the command is unchanged.
+	BuildRunAsCommand(user, command string) (string, bool)
+
+	// BuildEnvCommand prefixes a command with environment variable assignments, in sorted name order
+	BuildEnvCommand(env map[string]string, command string) string
+
+	// BuildSequenceCommand runs steps in order, each in a scope of its own, printing a StepMarker before
+	// each step on stdout and stderr. The first failing step ends the sequence with its exit code unless
+	// continueOnError is set, in which case every step runs and the first failure's code is returned.
+	BuildSequenceCommand(steps []string, continueOnError bool) string
+
 	// BuildFileExistsCommand creates a command to check if a file exists
 	BuildFileExistsCommand(path string) string
 
@@ -28,6 +49,13 @@ type CommandBuilder interface {
 	// BuildFileReadCommand creates a command to read a file (base64 encoded)
 	BuildFileReadCommand(path string) string
 
+	// BuildDirectoryListCommand creates a command to list every regular file under a directory, recursively
+	BuildDirectoryListCommand(path string) string
+
+	// BuildFileModeCommand creates a command that applies local permission bits to a file.
+	// Windows has no mode bits, so only the read-only attribute is carried over there.
+	BuildFileModeCommand(path string, mode os.FileMode) string
+
 	// BuildFileWriteCommand creates a command to write base64 data to a file
 
 	// This is necessary for security validation of PowerShell here-strings on Windows.
@@ -49,6 +77,47 @@ type CommandBuilder interface {
 
 	// ParseFileExists interprets command output to determine if file exists
 	ParseFileExists(output string, exitCode int) (bool, error)
+
+	// ParseDirectoryListing extracts the files from directory listing output.
+	// It returns ErrDirectoryNotFound when the listed path is not a directory.
+	ParseDirectoryListing(output string) ([]RemoteFile, error)
+}
+
+// RemoteFile is a file found by a directory listing
+type RemoteFile struct {
+	Path       string // Relative to the listed directory, with forward slashes
+	RemotePath string // Full path on the instance
+	Size       int64
+}
+
+// ErrDirectoryNotFound is returned by ParseDirectoryListing when the listed path is not a directory
+var ErrDirectoryNotFound = errors.New("remote directory not found")
+
+// directoryNotFoundMarker is printed by directory listing commands when the path is not a directory
+const directoryNotFoundMarker = "NOT_A_DIRECTORY"
+
+// parseDirectoryListing parses "size<TAB>relative path<TAB>full path" lines
+func parseDirectoryListing(output string) ([]RemoteFile, error) {
+	var files []RemoteFile
+	for _, line := range strings.Split(strings.ReplaceAll(output, "\r", ""), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.TrimSpace(line) == directoryNotFoundMarker {
+			return nil, ErrDirectoryNotFound
+		}
+
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected directory listing line: %q", line)
+		}
+		size, err := strconv.ParseInt(strings.TrimSpace(fields[0]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse file size in listing line %q: %w", line, err)
+		}
+		files = append(files, RemoteFile{Path: fields[1], RemotePath: fields[2], Size: size})
+	}
+	return files, nil
 }
 
 // BuilderFactory creates the appropriate CommandBuilder for a platform
@@ -71,6 +140,41 @@ func (f *BuilderFactory) GetBuilder(platform Platform) (CommandBuilder, error) {
 	}
 }
 
+// ParsePlatform parses a user-supplied platform name (linux or windows, any case); empty means auto-detect
+func ParsePlatform(value string) (Platform, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return "", nil
+	case "linux":
+		return PlatformLinux, nil
+	case "windows":
+		return PlatformWindows, nil
+	default:
+		return "", fmt.Errorf("unsupported platform %q (expected linux or windows)", value)
+	}
+}
+
+// usernamePattern matches portable OS user names: a letter or underscore followed by up to 31
+// letters, digits, dots, underscores or hyphens
+var usernamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9._-]{0,31}$`)
+
+// ValidateUsername checks a user name to run commands as
+func ValidateUsername(name string) error {
+	if !usernamePattern.MatchString(name) {
+		return fmt.Errorf("invalid user name %q (expected a letter or underscore followed by up to 31 letters, digits, '.', '_' or '-')", name)
+	}
+	return nil
+}
+
+// AlternateBuilder returns the builder for the other platform, whose SSM document an instance
+// may accept when platform detection picked the wrong one
+func AlternateBuilder(builder CommandBuilder) (CommandBuilder, Platform) {
+	if _, isWindows := builder.(*WindowsBuilder); isWindows {
+		return NewLinuxBuilder(), PlatformLinux
+	}
+	return NewWindowsBuilder(), PlatformWindows
+}
+
 // CommandContext provides context for command execution
 type CommandContext struct {
 	Platform    Platform
@@ -123,6 +227,44 @@ func (b *BaseBuilder) EscapeShellArg(arg string) string {
 	return fmt.Sprintf("'%s'", arg)
 }
 
+// StepMarkerPrefix starts the line printed before each step of a sequence, and the line reporting a
+// failed step, so the output of each step can be told apart
+const StepMarkerPrefix = "==> [ztictl] step "
+
+// maxStepLabelLength caps the command shown in a step marker
+const maxStepLabelLength = 80
+
+// StepMarker returns the line printed before step n of total: the prefix, the step number and the
+// first line of the command, shortened to keep markers on one line
+func StepMarker(n, total int, command string) string {
+	first, rest, _ := strings.Cut(strings.TrimSpace(command), "\n")
+	label := strings.TrimSpace(first)
+	shortened := strings.TrimSpace(rest) != ""
+	if runes := []rune(label); len(runes) > maxStepLabelLength {
+		label = string(runes[:maxStepLabelLength])
+		shortened = true
+	}
+	if shortened {
+		label += " ..."
+	}
+	return fmt.Sprintf("%s%d/%d: %s", StepMarkerPrefix, n, total, label)
+}
+
+// stepFailureMarker returns the start of the line printed when step n of total fails; the exit code follows it
+func stepFailureMarker(n, total int) string {
+	return fmt.Sprintf("%s%d/%d failed with exit code ", StepMarkerPrefix, n, total)
+}
+
+// sortedEnvNames returns the variable names of env in sorted order, so built commands are deterministic
+func sortedEnvNames(env map[string]string) []string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // BuilderManager manages command builders and platform detection
 type BuilderManager struct {
 	detector *Detector
@@ -170,6 +312,13 @@ func (m *BuilderManager) GetBuilder(ctx context.Context, instanceID string) (Com
 	return builder, nil
 }
 
+// SetBuilder replaces the cached builder of an instance, e.g. after its detected platform proved wrong
+func (m *BuilderManager) SetBuilder(instanceID string, builder CommandBuilder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.builders[instanceID] = builder
+}
+
 // ClearCache clears the builder cache
 func (m *BuilderManager) ClearCache() {
 	m.mu.Lock()
@@ -0,0 +1,69 @@
+package platform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePlatform(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Platform
+		wantErr bool
+	}{
+		{value: "", want: ""},
+		{value: "linux", want: PlatformLinux},
+		{value: " Windows ", want: PlatformWindows},
+		{value: "macos", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePlatform(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePlatform(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParsePlatform(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	for _, name := range []string{"appuser", "_svc", "deploy-bot", "first.last", "www-data", "Administrator"} {
+		if err := ValidateUsername(name); err != nil {
+			t.Errorf("ValidateUsername(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", "-u", "1user", "app user", "app;id", "$(id)", "a/b", "averyveryverylongusernamethatisover32"} {
+		if err := ValidateUsername(name); err == nil {
+			t.Errorf("ValidateUsername(%q) = nil, want an error", name)
+		}
+	}
+}
+
+func TestAlternateBuilder(t *testing.T) {
+	if builder, platform := AlternateBuilder(NewLinuxBuilder()); platform != PlatformWindows || builder.GetSSMDocument() != "AWS-RunPowerShellScript" {
+		t.Errorf("Expected the Windows builder as the Linux alternate, got %s (%s)", platform, builder.GetSSMDocument())
+	}
+	if builder, platform := AlternateBuilder(NewWindowsBuilder()); platform != PlatformLinux || builder.GetSSMDocument() != "AWS-RunShellScript" {
+		t.Errorf("Expected the Linux builder as the Windows alternate, got %s (%s)", platform, builder.GetSSMDocument())
+	}
+}
+
+func TestStepMarker(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{command: "apt update", want: "==> [ztictl] step 1/3: apt update"},
+		{command: "  systemctl restart nginx\n", want: "==> [ztictl] step 1/3: systemctl restart nginx"},
+		{command: "cd /opt/app\n./deploy.sh", want: "==> [ztictl] step 1/3: cd /opt/app ..."},
+		{command: strings.Repeat("x", 100), want: "==> [ztictl] step 1/3: " + strings.Repeat("x", 80) + " ..."},
+	}
+
+	for _, tt := range tests {
+		if got := StepMarker(1, 3, tt.command); got != tt.want {
+			t.Errorf("StepMarker(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
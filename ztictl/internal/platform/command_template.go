@@ -0,0 +1,74 @@
+package platform
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// commandTemplateProbe stands in for the command when a template is checked
+const commandTemplateProbe = "__ztictl_command__"
+
+// CommandTemplateData is the data a command wrapper template is rendered with
+type CommandTemplateData struct {
+	// Command is the command to run, or the sequence of steps built from --command
+	Command string
+	// Platform is "linux" or "windows", so one template can hold a preamble for each
+	Platform string
+}
+
+// ParseCommandTemplate parses a command wrapper template, such as an organization-wide preamble
+// followed by {{.Command}}. Every platform's rendering must include the command.
+func ParseCommandTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("command-wrapper").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, platform := range []Platform{PlatformLinux, PlatformWindows} {
+		rendered, err := renderCommandTemplate(tmpl, platform, commandTemplateProbe)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.Contains(rendered, commandTemplateProbe) {
+			return nil, fmt.Errorf("the template must include {{.Command}} for %s", strings.ToLower(string(platform)))
+		}
+	}
+	return tmpl, nil
+}
+
+// RenderCommandTemplate renders command inside a wrapper template for the builder's platform. The command
+// runs in a subshell on Linux, or a script block on Windows, and its exit status is saved, so the rest of the
+// template still runs after an exit in the command. The rendered script ends with the command's status.
+func RenderCommandTemplate(tmpl *template.Template, builder CommandBuilder, command string) (string, error) {
+	platform := PlatformLinux
+	isolated := "(\n" + command + "\n)\nZTICTL_COMMAND_EXIT=$?"
+	restore := "exit $ZTICTL_COMMAND_EXIT"
+	if windows, isWindows := builder.(*WindowsBuilder); isWindows {
+		platform = PlatformWindows
+		isolated = fmt.Sprintf(`$global:LASTEXITCODE = 0
+try {
+    & ([scriptblock]::Create(%s))
+    $ztictlCommandExit = $LASTEXITCODE
+    if ($ztictlCommandExit -eq $null) { $ztictlCommandExit = 0 }
+} catch {
+    [Console]::Error.WriteLine($_.Exception.Message)
+    $ztictlCommandExit = 1
+}`, windows.EscapePowerShellArg(command))
+		restore = "$global:LASTEXITCODE = $ztictlCommandExit"
+	}
+
+	rendered, err := renderCommandTemplate(tmpl, platform, isolated)
+	if err != nil {
+		return "", err
+	}
+	return rendered + "\n" + restore, nil
+}
+
+func renderCommandTemplate(tmpl *template.Template, platform Platform, command string) (string, error) {
+	var rendered strings.Builder
+	data := CommandTemplateData{Command: command, Platform: strings.ToLower(string(platform))}
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("failed to render command wrapper template: %w", err)
+	}
+	return rendered.String(), nil
+}
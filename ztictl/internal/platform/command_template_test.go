@@ -0,0 +1,49 @@
+package platform
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCommandTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "preamble", text: "umask 027\n{{.Command}}"},
+		{name: "per platform", text: `{{if eq .Platform "windows"}}$ErrorActionPreference = 'Stop'{{else}}set -o pipefail{{end}}
+{{.Command}}`},
+		{name: "no command", text: "umask 027", wantErr: "must include {{.Command}} for linux"},
+		{name: "no command on windows", text: `{{if eq .Platform "linux"}}{{.Command}}{{end}}`, wantErr: "must include {{.Command}} for windows"},
+		{name: "unknown field", text: "{{.Cmd}}", wantErr: "failed to render"},
+		{name: "bad syntax", text: "{{.Command", wantErr: "unclosed action"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseCommandTemplate(tt.text)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("ParseCommandTemplate() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRenderCommandTemplate(t *testing.T) {
+	tmpl, err := ParseCommandTemplate(`{{if eq .Platform "windows"}}Set-Location C:\{{else}}cd /{{end}}; {{.Command}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linux, err := RenderCommandTemplate(tmpl, NewLinuxBuilder(), "uptime")
+	if err != nil || !strings.HasPrefix(linux, "cd /; (\nuptime\n)\nZTICTL_COMMAND_EXIT=$?") || !strings.HasSuffix(linux, "\nexit $ZTICTL_COMMAND_EXIT") {
+		t.Errorf("RenderCommandTemplate(linux) = %q, %v", linux, err)
+	}
+	windows, err := RenderCommandTemplate(tmpl, NewWindowsBuilder(), "Get-Service")
+	if err != nil || !strings.HasPrefix(windows, `Set-Location C:\; $global:LASTEXITCODE = 0`) ||
+		!strings.Contains(windows, "[scriptblock]::Create('Get-Service')") || !strings.HasSuffix(windows, "\n$global:LASTEXITCODE = $ztictlCommandExit") {
+		t.Errorf("RenderCommandTemplate(windows) = %q, %v", windows, err)
+	}
+}
@@ -2,6 +2,7 @@ package platform
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -20,13 +21,55 @@ func (b *LinuxBuilder) GetSSMDocument() string {
 	return "AWS-RunShellScript"
 }
 
+// BuildExecCommand runs the command in a shell of its own and reports its exit code. The command is
+// passed as one quoted argument, so its quotes, backslashes and substitutions reach that shell as typed,
+// and an exit or syntax error in it cannot skip the exit code report.
 func (b *LinuxBuilder) BuildExecCommand(command string) string {
 	return fmt.Sprintf(`
-set -e
-%s
+sh -c %s
 EXIT_CODE=$?
 echo "EXIT_CODE:$EXIT_CODE"
-exit $EXIT_CODE`, command)
+exit $EXIT_CODE`, b.EscapeShellArg(command))
+}
+
+// BuildSudoCommand runs the command through a root shell; requires passwordless sudo on the instance
+func (b *LinuxBuilder) BuildSudoCommand(command string) (string, bool) {
+	return fmt.Sprintf("sudo -n sh -c %s", b.EscapeShellArg(command)), true
+}
+
+// BuildRunAsCommand runs the command in a shell of the given user with their home directory set;
+// requires passwordless sudo for that user on the instance
+func (b *LinuxBuilder) BuildRunAsCommand(user, command string) (string, bool) {
+	return fmt.Sprintf("sudo -n -u %s -H sh -c %s", b.EscapeShellArg(user), b.EscapeShellArg(command)), true
+}
+
+// BuildEnvCommand exports each variable before the command runs
+func (b *LinuxBuilder) BuildEnvCommand(env map[string]string, command string) string {
+	var lines []string
+	for _, name := range sortedEnvNames(env) {
+		lines = append(lines, fmt.Sprintf("export %s=%s", name, b.EscapeShellArg(env[name])))
+	}
+	return strings.Join(append(lines, command), "\n")
+}
+
+// BuildSequenceCommand runs each step in a subshell, so an exit in one step ends only that step, and
+// checks its exit status before the next one starts. Directory changes and variables do not carry over.
+func (b *LinuxBuilder) BuildSequenceCommand(steps []string, continueOnError bool) string {
+	lines := []string{"ZTICTL_SEQUENCE_EXIT=0"}
+	for i, step := range steps {
+		marker := b.EscapeShellArg(StepMarker(i+1, len(steps), step))
+		onFailure := "exit $ZTICTL_STEP_EXIT"
+		if continueOnError {
+			onFailure = `if [ "$ZTICTL_SEQUENCE_EXIT" -eq 0 ]; then ZTICTL_SEQUENCE_EXIT=$ZTICTL_STEP_EXIT; fi`
+		}
+		lines = append(lines,
+			fmt.Sprintf("printf '%%s\\n' %s; printf '%%s\\n' %s >&2", marker, marker),
+			"(\n"+step+"\n)",
+			"ZTICTL_STEP_EXIT=$?",
+			fmt.Sprintf(`if [ "$ZTICTL_STEP_EXIT" -ne 0 ]; then printf '%%s%%s\n' %s "$ZTICTL_STEP_EXIT" >&2; %s; fi`,
+				b.EscapeShellArg(stepFailureMarker(i+1, len(steps))), onFailure))
+	}
+	return strings.Join(append(lines, "exit $ZTICTL_SEQUENCE_EXIT"), "\n")
 }
 
 func (b *LinuxBuilder) BuildFileExistsCommand(path string) string {
@@ -61,6 +104,21 @@ func (b *LinuxBuilder) BuildFileReadCommand(path string) string {
 	return fmt.Sprintf("base64 -w 0 %s 2>/dev/null || base64 %s", safePath, safePath)
 }
 
+func (b *LinuxBuilder) BuildDirectoryListCommand(path string) string {
+	sanitized := b.SanitizePath(path)
+	// Ensure Unix-style paths regardless of host OS
+	sanitized = strings.ReplaceAll(sanitized, "\\", "/")
+	safePath := b.EscapeShellArg(sanitized)
+	return fmt.Sprintf("if [ -d %s ]; then find %s -type f -printf '%%s\\t%%P\\t%%p\\n'; else echo '%s'; fi", safePath, safePath, directoryNotFoundMarker)
+}
+
+func (b *LinuxBuilder) BuildFileModeCommand(path string, mode os.FileMode) string {
+	sanitized := b.SanitizePath(path)
+	// Ensure Unix-style paths regardless of host OS
+	sanitized = strings.ReplaceAll(sanitized, "\\", "/")
+	return fmt.Sprintf("chmod %04o %s", mode.Perm(), b.EscapeShellArg(sanitized))
+}
+
 func (b *LinuxBuilder) BuildFileWriteCommand(path string, base64Data string) (string, error) {
 	sanitized := b.SanitizePath(path)
 	// Ensure Unix-style paths regardless of host OS
@@ -157,6 +215,10 @@ func (b *LinuxBuilder) ParseFileSize(output string) (int64, error) {
 	return size, nil
 }
 
+func (b *LinuxBuilder) ParseDirectoryListing(output string) ([]RemoteFile, error) {
+	return parseDirectoryListing(output)
+}
+
 func (b *LinuxBuilder) ParseFileExists(output string, exitCode int) (bool, error) {
 	output = strings.TrimSpace(output)
 
@@ -1,6 +1,10 @@
 package platform
 
 import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -33,7 +37,7 @@ func TestLinuxBuilder_BuildExecCommand(t *testing.T) {
 			name:    "Complex command with pipes",
 			command: "ps aux | grep nginx | awk '{print $2}'",
 			contains: []string{
-				"ps aux | grep nginx | awk '{print $2}'",
+				`sh -c "ps aux | grep nginx | awk '{print \$2}'"`,
 				"EXIT_CODE=$?",
 			},
 		},
@@ -49,6 +53,190 @@ func TestLinuxBuilder_BuildExecCommand(t *testing.T) {
 	}
 }
 
+func TestLinuxBuilder_BuildExecCommandPreservesQuoting(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Requires a POSIX shell")
+	}
+
+	builder := NewLinuxBuilder()
+	tests := []struct {
+		name     string
+		command  string
+		output   string
+		exitCode string
+	}{
+		{name: "single quotes", command: `echo 'it'"'"'s here'`, output: "it's here\n", exitCode: "0"},
+		{name: "double quotes", command: `echo "say \"hi\""`, output: "say \"hi\"\n", exitCode: "0"},
+		{name: "backslashes", command: `printf '%s\n' 'C:\temp\new'`, output: `C:\temp\new` + "\n", exitCode: "0"},
+		{name: "command substitution", command: "echo \"$(echo sub) `echo tick`\"", output: "sub tick\n", exitCode: "0"},
+		{name: "variables expand on the instance", command: `X=1; echo "$X" '$X'`, output: "1 $X\n", exitCode: "0"},
+		{name: "exit still reports the code", command: "echo before; exit 3", output: "before\n", exitCode: "3"},
+		{name: "failure does not stop later commands", command: "false; echo after", output: "after\n", exitCode: "0"},
+		{name: "unbalanced quote stays inside its shell", command: `echo "unterminated`, exitCode: "2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, _ := exec.Command("sh", "-c", builder.BuildExecCommand(tt.command)).Output() // #nosec G204 - test input
+			stdout, marker, found := strings.Cut(string(output), "EXIT_CODE:")
+			if !found {
+				t.Fatalf("Expected an exit code report, got %q", output)
+			}
+			assert.Equal(t, tt.output, stdout)
+			assert.Equal(t, tt.exitCode, strings.TrimSpace(marker))
+		})
+	}
+}
+
+func TestLinuxBuilder_BuildSudoCommand(t *testing.T) {
+	builder := NewLinuxBuilder()
+
+	result, applied := builder.BuildSudoCommand("systemctl restart nginx")
+	assert.True(t, applied)
+	assert.Equal(t, "sudo -n sh -c 'systemctl restart nginx'", result)
+
+	// The wrapped command must still capture the exit code
+	wrapped := builder.BuildExecCommand(result)
+	assert.Contains(t, wrapped, "sudo -n sh -c 'systemctl restart nginx'")
+	assert.Contains(t, wrapped, "EXIT_CODE=$?")
+}
+
+func TestLinuxBuilder_BuildSudoCommandPreservesQuoting(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Requires a POSIX shell")
+	}
+
+	builder := NewLinuxBuilder()
+	commands := []string{
+		"echo hello",
+		"awk '{print $2}' /etc/hosts",
+		`echo "home is $HOME" && echo 'it''s'`,
+		"printf '%s\n' `date` | grep -v x",
+	}
+
+	for _, command := range commands {
+		result, _ := builder.BuildSudoCommand(command)
+
+		// Replace the sudo shell with printf to see the exact argument the root shell would receive
+		probe := strings.Replace(result, "sudo -n sh -c ", "printf %s ", 1)
+		output, err := exec.Command("sh", "-c", probe).Output() // #nosec G204 - test input
+		if err != nil {
+			t.Fatalf("Failed to evaluate %q: %v", probe, err)
+		}
+		assert.Equal(t, command, string(output))
+	}
+}
+
+func TestLinuxBuilder_BuildRunAsCommand(t *testing.T) {
+	builder := NewLinuxBuilder()
+
+	result, applied := builder.BuildRunAsCommand("appuser", "whoami")
+	assert.True(t, applied)
+	assert.Equal(t, "sudo -n -u 'appuser' -H sh -c 'whoami'", result)
+
+	// The wrapped command must still capture the exit code
+	wrapped := builder.BuildExecCommand(result)
+	assert.Contains(t, wrapped, result)
+	assert.Contains(t, wrapped, "EXIT_CODE=$?")
+}
+
+func TestLinuxBuilder_BuildRunAsCommandPreservesQuoting(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Requires a POSIX shell")
+	}
+
+	builder := NewLinuxBuilder()
+	for _, command := range []string{
+		"echo hello",
+		"awk '{print $2}' /etc/passwd",
+		`echo "home is $HOME" && echo 'it''s'`,
+	} {
+		result, _ := builder.BuildRunAsCommand("appuser", command)
+
+		// Replace the sudo shell with printf to see the exact argument the user's shell would receive
+		probe := strings.Replace(result, "sudo -n -u 'appuser' -H sh -c ", "printf %s ", 1)
+		output, err := exec.Command("sh", "-c", probe).Output() // #nosec G204 - test input
+		if err != nil {
+			t.Fatalf("Failed to evaluate %q: %v", probe, err)
+		}
+		assert.Equal(t, command, string(output))
+	}
+}
+
+func TestLinuxBuilder_BuildEnvCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Requires a POSIX shell")
+	}
+
+	builder := NewLinuxBuilder()
+	env := map[string]string{"DB_PASS": "s3cr'et $HOME", "API_KEY": "abc"}
+
+	result := builder.BuildEnvCommand(env, `printf '%s|%s' "$API_KEY" "$DB_PASS"`)
+	assert.True(t, strings.HasPrefix(result, "export API_KEY="), "variables should be exported in sorted order")
+
+	// Values must reach the command verbatim, including through sudo's nested shell
+	for _, command := range []string{result, strings.Replace(mustSudo(builder, result), "sudo -n sh -c ", "sh -c ", 1)} {
+		output, err := exec.Command("sh", "-c", command).Output() // #nosec G204 - test input
+		if err != nil {
+			t.Fatalf("Failed to evaluate %q: %v", command, err)
+		}
+		assert.Equal(t, "abc|s3cr'et $HOME", string(output))
+	}
+}
+
+func TestLinuxBuilder_BuildSequenceCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Requires a POSIX shell")
+	}
+
+	builder := NewLinuxBuilder()
+	steps := []string{`echo "it's $((1+1))"`, "echo oops >&2; exit 3", "echo after"}
+
+	tests := []struct {
+		name            string
+		continueOnError bool
+		wantStdout      string
+	}{
+		{
+			name:       "stops at the first failure",
+			wantStdout: "==> [ztictl] step 1/3: echo \"it's $((1+1))\"\nit's 2\n==> [ztictl] step 2/3: echo oops >&2; exit 3\n",
+		},
+		{
+			name:            "continues after a failure",
+			continueOnError: true,
+			wantStdout: "==> [ztictl] step 1/3: echo \"it's $((1+1))\"\nit's 2\n==> [ztictl] step 2/3: echo oops >&2; exit 3\n" +
+				"==> [ztictl] step 3/3: echo after\nafter\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr strings.Builder
+			cmd := exec.Command("sh", "-c", builder.BuildSequenceCommand(steps, tt.continueOnError)) // #nosec G204 - test input
+			cmd.Stdout, cmd.Stderr = &stdout, &stderr
+			err := cmd.Run()
+
+			// The failed step's exit code is the sequence's, even when later steps succeed
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+				t.Fatalf("Expected exit code 3, got %v", err)
+			}
+			assert.Equal(t, tt.wantStdout, stdout.String())
+			assert.Contains(t, stderr.String(), "==> [ztictl] step 2/3: echo oops >&2; exit 3\noops\n==> [ztictl] step 2/3 failed with exit code 3\n")
+		})
+	}
+
+	output, err := exec.Command("sh", "-c", builder.BuildSequenceCommand([]string{"true", "echo done"}, false)).Output() // #nosec G204 - test input
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(string(output), "\ndone\n"))
+}
+
+// mustSudo wraps a command with BuildSudoCommand
+func mustSudo(builder *LinuxBuilder, command string) string {
+	result, _ := builder.BuildSudoCommand(command)
+	return result
+}
+
 func TestLinuxBuilder_BuildFileExistsCommand(t *testing.T) {
 	builder := NewLinuxBuilder()
 
@@ -134,6 +322,13 @@ func TestLinuxBuilder_BuildDirectoryCreateCommand(t *testing.T) {
 	}
 }
 
+func TestLinuxBuilder_BuildFileModeCommand(t *testing.T) {
+	builder := NewLinuxBuilder()
+
+	assert.Equal(t, "chmod 0755 '/opt/app/deploy.sh'", builder.BuildFileModeCommand("/opt/app/deploy.sh", 0755))
+	assert.Equal(t, `chmod 0600 "/opt/app/it's.conf"`, builder.BuildFileModeCommand("/opt/app/it's.conf", os.ModeSymlink|0600))
+}
+
 func TestLinuxBuilder_BuildFileReadCommand(t *testing.T) {
 	builder := NewLinuxBuilder()
 
@@ -144,6 +339,36 @@ func TestLinuxBuilder_BuildFileReadCommand(t *testing.T) {
 	assert.Contains(t, result, "-w 0")
 }
 
+func TestLinuxBuilder_BuildDirectoryListCommand(t *testing.T) {
+	builder := NewLinuxBuilder()
+
+	result := builder.BuildDirectoryListCommand("/var/log/app dir")
+	assert.Contains(t, result, "[ -d '/var/log/app dir' ]")
+	assert.Contains(t, result, "find '/var/log/app dir' -type f")
+	assert.Contains(t, result, "NOT_A_DIRECTORY")
+}
+
+func TestLinuxBuilder_ParseDirectoryListing(t *testing.T) {
+	builder := NewLinuxBuilder()
+
+	files, err := builder.ParseDirectoryListing("12\tapp.log\t/var/log/app/app.log\n2048\tarchive/old log.gz\t/var/log/app/archive/old log.gz\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []RemoteFile{
+		{Path: "app.log", RemotePath: "/var/log/app/app.log", Size: 12},
+		{Path: "archive/old log.gz", RemotePath: "/var/log/app/archive/old log.gz", Size: 2048},
+	}, files)
+
+	files, err = builder.ParseDirectoryListing("")
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+
+	_, err = builder.ParseDirectoryListing("NOT_A_DIRECTORY\n")
+	assert.ErrorIs(t, err, ErrDirectoryNotFound)
+
+	_, err = builder.ParseDirectoryListing("find: permission denied")
+	assert.Error(t, err)
+}
+
 func TestLinuxBuilder_BuildFileWriteCommand(t *testing.T) {
 	builder := NewLinuxBuilder()
 
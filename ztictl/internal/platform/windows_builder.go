@@ -2,6 +2,7 @@ package platform
 
 import (
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"ztictl/pkg/security"
@@ -19,11 +20,13 @@ func (b *WindowsBuilder) GetSSMDocument() string {
 	return "AWS-RunPowerShellScript"
 }
 
+// BuildExecCommand runs the command as a script block and reports its exit code. The command is passed
+// as a single-quoted string, so unbalanced braces or quotes in it cannot break the surrounding try block.
 func (b *WindowsBuilder) BuildExecCommand(command string) string {
 	return fmt.Sprintf(`
 $ErrorActionPreference = 'Continue'
 try {
-    %s
+    & ([scriptblock]::Create(%s))
     $exitCode = $LASTEXITCODE
     if ($exitCode -eq $null) { $exitCode = 0 }
 } catch {
@@ -31,7 +34,55 @@ try {
     $exitCode = 1
 }
 Write-Output "EXIT_CODE:$exitCode"
-exit $exitCode`, command)
+exit $exitCode`, b.EscapePowerShellArg(command))
+}
+
+// BuildSudoCommand is a no-op on Windows, where the SSM agent already runs as SYSTEM
+func (b *WindowsBuilder) BuildSudoCommand(command string) (string, bool) {
+	return command, false
+}
+
+// BuildRunAsCommand is not supported on Windows, where switching users needs that user's password
+func (b *WindowsBuilder) BuildRunAsCommand(user, command string) (string, bool) {
+	return command, false
+}
+
+// BuildEnvCommand sets each variable in the PowerShell session before the command runs
+func (b *WindowsBuilder) BuildEnvCommand(env map[string]string, command string) string {
+	var lines []string
+	for _, name := range sortedEnvNames(env) {
+		lines = append(lines, fmt.Sprintf("$env:%s = %s", name, b.EscapePowerShellArg(env[name])))
+	}
+	return strings.Join(append(lines, command), "\n")
+}
+
+// BuildSequenceCommand runs each step in a child scope, so its variables do not carry over to later steps.
+// Errors stop a step as they would with set -e, and its exit code is checked before the next one starts.
+// The code is returned through $LASTEXITCODE, which the exec wrapper reports.
+func (b *WindowsBuilder) BuildSequenceCommand(steps []string, continueOnError bool) string {
+	lines := []string{"$ErrorActionPreference = 'Stop'", "$ztictlSequenceExit = 0"}
+	for i, step := range steps {
+		marker := b.EscapePowerShellArg(StepMarker(i+1, len(steps), step))
+		onFailure := "$global:LASTEXITCODE = $ztictlStepExit; return"
+		if continueOnError {
+			onFailure = "if ($ztictlSequenceExit -eq 0) { $ztictlSequenceExit = $ztictlStepExit }"
+		}
+		lines = append(lines, fmt.Sprintf(`Write-Output %s; [Console]::Error.WriteLine(%s)
+$global:LASTEXITCODE = 0
+try {
+    & ([scriptblock]::Create(%s))
+    $ztictlStepExit = $LASTEXITCODE
+    if ($ztictlStepExit -eq $null) { $ztictlStepExit = 0 }
+} catch {
+    [Console]::Error.WriteLine($_.Exception.Message)
+    $ztictlStepExit = 1
+}
+if ($ztictlStepExit -ne 0) {
+    [Console]::Error.WriteLine(%s + $ztictlStepExit)
+    %s
+}`, marker, marker, b.EscapePowerShellArg(step), b.EscapePowerShellArg(stepFailureMarker(i+1, len(steps))), onFailure))
+	}
+	return strings.Join(append(lines, "$global:LASTEXITCODE = $ztictlSequenceExit"), "\n")
 }
 
 func (b *WindowsBuilder) BuildFileExistsCommand(path string) string {
@@ -44,6 +95,15 @@ func (b *WindowsBuilder) BuildFileSizeCommand(path string) string {
 	return fmt.Sprintf(`(Get-Item %s -ErrorAction SilentlyContinue).Length`, safePath)
 }
 
+func (b *WindowsBuilder) BuildFileModeCommand(path string, mode os.FileMode) string {
+	safePath := b.EscapePowerShellArg(b.SanitizePath(path))
+	readOnly := "$false"
+	if mode.Perm()&0200 == 0 {
+		readOnly = "$true"
+	}
+	return fmt.Sprintf(`Set-ItemProperty -LiteralPath %s -Name IsReadOnly -Value %s`, safePath, readOnly)
+}
+
 func (b *WindowsBuilder) BuildDirectoryCreateCommand(path string) string {
 	safePath := b.EscapePowerShellArg(b.SanitizePath(path))
 	return fmt.Sprintf(`New-Item -ItemType Directory -Force -Path %s | Out-Null`, safePath)
@@ -54,6 +114,14 @@ func (b *WindowsBuilder) BuildFileReadCommand(path string) string {
 	return fmt.Sprintf(`[Convert]::ToBase64String([System.IO.File]::ReadAllBytes(%s))`, safePath)
 }
 
+func (b *WindowsBuilder) BuildDirectoryListCommand(path string) string {
+	safePath := b.EscapePowerShellArg(b.SanitizePath(path))
+	return fmt.Sprintf("if (Test-Path -LiteralPath %s -PathType Container) { "+
+		"$root = (Get-Item -LiteralPath %s -Force).FullName.TrimEnd('\\'); "+
+		"Get-ChildItem -LiteralPath %s -Recurse -File -Force | ForEach-Object { \"{0}`t{1}`t{2}\" -f $_.Length, $_.FullName.Substring($root.Length + 1), $_.FullName } "+
+		"} else { Write-Output '%s' }", safePath, safePath, safePath, directoryNotFoundMarker)
+}
+
 func (b *WindowsBuilder) validateBase64ForHereString(base64Data string) error {
 	// PowerShell here-strings are terminated by "'@" on a new line.
 	// This validation ensures that the base64 data does not contain the "'@" sequence,
@@ -213,6 +281,14 @@ func (b *WindowsBuilder) ParseFileSize(output string) (int64, error) {
 	return size, nil
 }
 
+func (b *WindowsBuilder) ParseDirectoryListing(output string) ([]RemoteFile, error) {
+	files, err := parseDirectoryListing(output)
+	for i := range files {
+		files[i].Path = strings.ReplaceAll(files[i].Path, "\\", "/")
+	}
+	return files, err
+}
+
 func (b *WindowsBuilder) ParseFileExists(output string, exitCode int) (bool, error) {
 	output = strings.TrimSpace(output)
 	output = strings.ReplaceAll(output, "\r", "")
@@ -12,6 +12,45 @@ func TestWindowsBuilder_GetSSMDocument(t *testing.T) {
 	assert.Equal(t, "AWS-RunPowerShellScript", builder.GetSSMDocument())
 }
 
+func TestWindowsBuilder_BuildSudoCommand(t *testing.T) {
+	builder := NewWindowsBuilder()
+
+	result, applied := builder.BuildSudoCommand("Get-Service")
+	assert.False(t, applied)
+	assert.Equal(t, "Get-Service", result)
+}
+
+func TestWindowsBuilder_BuildRunAsCommand(t *testing.T) {
+	builder := NewWindowsBuilder()
+
+	result, applied := builder.BuildRunAsCommand("appuser", "Get-Service")
+	assert.False(t, applied)
+	assert.Equal(t, "Get-Service", result)
+}
+
+func TestWindowsBuilder_BuildEnvCommand(t *testing.T) {
+	builder := NewWindowsBuilder()
+
+	result := builder.BuildEnvCommand(map[string]string{"TOKEN": "it's", "A": "1"}, "Get-ChildItem env:")
+	assert.Equal(t, "$env:A = '1'\n$env:TOKEN = 'it''s'\nGet-ChildItem env:", result)
+}
+
+func TestWindowsBuilder_BuildSequenceCommand(t *testing.T) {
+	builder := NewWindowsBuilder()
+
+	result := builder.BuildSequenceCommand([]string{"Set-Location C:\\Temp", "Write-Output 'it''s here'"}, false)
+	assert.True(t, strings.HasPrefix(result, "$ErrorActionPreference = 'Stop'\n"), "errors should stop a step")
+	assert.Contains(t, result, "Write-Output '==> [ztictl] step 2/2: Write-Output ''it''''s here'''")
+	assert.Contains(t, result, "& ([scriptblock]::Create('Write-Output ''it''''s here'''))")
+	assert.Contains(t, result, "[Console]::Error.WriteLine('==> [ztictl] step 1/2 failed with exit code ' + $ztictlStepExit)")
+	assert.Contains(t, result, "$global:LASTEXITCODE = $ztictlStepExit; return")
+	assert.True(t, strings.HasSuffix(result, "$global:LASTEXITCODE = $ztictlSequenceExit"))
+
+	result = builder.BuildSequenceCommand([]string{"Get-Service"}, true)
+	assert.NotContains(t, result, "return")
+	assert.Contains(t, result, "if ($ztictlSequenceExit -eq 0) { $ztictlSequenceExit = $ztictlStepExit }")
+}
+
 func TestWindowsBuilder_BuildExecCommand(t *testing.T) {
 	builder := NewWindowsBuilder()
 
@@ -33,7 +72,7 @@ func TestWindowsBuilder_BuildExecCommand(t *testing.T) {
 			name:    "Command with pipes",
 			command: "Get-Service | Where-Object {$_.Status -eq 'Running'}",
 			contains: []string{
-				"Get-Service | Where-Object {$_.Status -eq 'Running'}",
+				"& ([scriptblock]::Create('Get-Service | Where-Object {$_.Status -eq ''Running''}'))",
 				"$LASTEXITCODE",
 			},
 		},
@@ -49,6 +88,30 @@ func TestWindowsBuilder_BuildExecCommand(t *testing.T) {
 	}
 }
 
+func TestWindowsBuilder_BuildExecCommandQuotesCommand(t *testing.T) {
+	builder := NewWindowsBuilder()
+	commands := []string{
+		`Write-Output "it's $env:COMPUTERNAME"`,
+		"Write-Output '}' ; if ($true) {",
+		"Get-Content 'C:\\temp\\a.txt' | Select-String \"x\"",
+		"@'\nhere-string\n'@",
+	}
+
+	for _, command := range commands {
+		result := builder.BuildExecCommand(command)
+
+		start := strings.Index(result, "[scriptblock]::Create('")
+		end := strings.LastIndex(result, "'))")
+		if start < 0 || end < start {
+			t.Fatalf("Expected the command as a script block literal, got %q", result)
+		}
+		literal := result[start+len("[scriptblock]::Create('") : end]
+		assert.NotContains(t, strings.ReplaceAll(literal, "''", ""), "'", "single quotes must be doubled inside the literal")
+		assert.Equal(t, command, strings.ReplaceAll(literal, "''", "'"))
+		assert.True(t, strings.HasSuffix(result, "exit $exitCode"))
+	}
+}
+
 func TestWindowsBuilder_BuildFileExistsCommand(t *testing.T) {
 	builder := NewWindowsBuilder()
 
@@ -116,6 +179,13 @@ func TestWindowsBuilder_BuildFileSizeCommand(t *testing.T) {
 	}
 }
 
+func TestWindowsBuilder_BuildFileModeCommand(t *testing.T) {
+	builder := NewWindowsBuilder()
+
+	assert.Equal(t, `Set-ItemProperty -LiteralPath 'C:\app\run.ps1' -Name IsReadOnly -Value $false`, builder.BuildFileModeCommand(`C:\app\run.ps1`, 0755))
+	assert.Equal(t, `Set-ItemProperty -LiteralPath 'C:\app\settings.json' -Name IsReadOnly -Value $true`, builder.BuildFileModeCommand(`C:\app\settings.json`, 0444))
+}
+
 func TestWindowsBuilder_BuildDirectoryCreateCommand(t *testing.T) {
 	builder := NewWindowsBuilder()
 
@@ -163,6 +233,29 @@ func TestWindowsBuilder_BuildFileReadCommand(t *testing.T) {
 	assert.Contains(t, result, "C:\\temp\\test.txt")
 }
 
+func TestWindowsBuilder_BuildDirectoryListCommand(t *testing.T) {
+	builder := NewWindowsBuilder()
+
+	result := builder.BuildDirectoryListCommand("C:\\logs")
+	assert.Contains(t, result, "Test-Path -LiteralPath 'C:\\logs' -PathType Container")
+	assert.Contains(t, result, "Get-ChildItem -LiteralPath 'C:\\logs' -Recurse -File")
+	assert.Contains(t, result, "NOT_A_DIRECTORY")
+}
+
+func TestWindowsBuilder_ParseDirectoryListing(t *testing.T) {
+	builder := NewWindowsBuilder()
+
+	files, err := builder.ParseDirectoryListing("10\tapp.log\tC:\\logs\\app.log\r\n20\tarchive\\old.log\tC:\\logs\\archive\\old.log\r\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []RemoteFile{
+		{Path: "app.log", RemotePath: "C:\\logs\\app.log", Size: 10},
+		{Path: "archive/old.log", RemotePath: "C:\\logs\\archive\\old.log", Size: 20},
+	}, files)
+
+	_, err = builder.ParseDirectoryListing("NOT_A_DIRECTORY\r\n")
+	assert.ErrorIs(t, err, ErrDirectoryNotFound)
+}
+
 func TestWindowsBuilder_BuildFileWriteCommand(t *testing.T) {
 	builder := NewWindowsBuilder()
 
@@ -0,0 +1,70 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"ztictl/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	asgtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+)
+
+// autoScalingAPI is the subset of the Auto Scaling API used to resolve group members
+type autoScalingAPI interface {
+	DescribeAutoScalingGroups(ctx context.Context, params *autoscaling.DescribeAutoScalingGroupsInput, optFns ...func(*autoscaling.Options)) (*autoscaling.DescribeAutoScalingGroupsOutput, error)
+}
+
+// AutoScalingInstance is a member of an Auto Scaling group
+type AutoScalingInstance struct {
+	InstanceID     string `json:"instance_id"`
+	LifecycleState string `json:"lifecycle_state"`
+	HealthStatus   string `json:"health_status,omitempty"`
+}
+
+// Targetable reports whether the instance is running as part of the group: InService, or in or
+// entering Standby. Pending instances may still be booting, and terminating or detaching ones are
+// on their way out of the group.
+func (i AutoScalingInstance) Targetable() bool {
+	switch asgtypes.LifecycleState(i.LifecycleState) {
+	case asgtypes.LifecycleStateInService, asgtypes.LifecycleStateStandby, asgtypes.LifecycleStateEnteringStandby:
+		return true
+	}
+	return false
+}
+
+// AutoScalingGroupInstances returns the current members of an Auto Scaling group in every lifecycle
+// state, sorted by instance ID
+func (m *Manager) AutoScalingGroupInstances(ctx context.Context, region, groupName string) ([]AutoScalingInstance, error) {
+	client, err := m.clientPool.GetAutoScalingClient(ctx, region)
+	if err != nil {
+		return nil, errors.NewAWSError("failed to get Auto Scaling client", err)
+	}
+	return fetchAutoScalingGroupInstances(ctx, client, region, groupName)
+}
+
+// fetchAutoScalingGroupInstances describes one group by its exact name
+func fetchAutoScalingGroupInstances(ctx context.Context, api autoScalingAPI, region, groupName string) ([]AutoScalingInstance, error) {
+	output, err := api.DescribeAutoScalingGroups(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []string{groupName},
+	})
+	if err != nil {
+		return nil, errors.NewAWSError("failed to describe Auto Scaling group", err)
+	}
+	if len(output.AutoScalingGroups) == 0 {
+		return nil, fmt.Errorf("no Auto Scaling group named %s in %s", groupName, region)
+	}
+
+	var instances []AutoScalingInstance
+	for _, instance := range output.AutoScalingGroups[0].Instances {
+		instances = append(instances, AutoScalingInstance{
+			InstanceID:     aws.ToString(instance.InstanceId),
+			LifecycleState: string(instance.LifecycleState),
+			HealthStatus:   aws.ToString(instance.HealthStatus),
+		})
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].InstanceID < instances[j].InstanceID })
+	return instances, nil
+}
@@ -0,0 +1,59 @@
+package ssm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	asgtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+)
+
+// fakeAutoScalingAPI serves groups by name
+type fakeAutoScalingAPI struct {
+	groups map[string][]asgtypes.Instance
+}
+
+func (f *fakeAutoScalingAPI) DescribeAutoScalingGroups(ctx context.Context, params *autoscaling.DescribeAutoScalingGroupsInput, optFns ...func(*autoscaling.Options)) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	output := &autoscaling.DescribeAutoScalingGroupsOutput{}
+	for _, name := range params.AutoScalingGroupNames {
+		if instances, ok := f.groups[name]; ok {
+			output.AutoScalingGroups = append(output.AutoScalingGroups, asgtypes.AutoScalingGroup{AutoScalingGroupName: aws.String(name), Instances: instances})
+		}
+	}
+	return output, nil
+}
+
+func TestFetchAutoScalingGroupInstances(t *testing.T) {
+	api := &fakeAutoScalingAPI{groups: map[string][]asgtypes.Instance{
+		"web-asg": {
+			{InstanceId: aws.String("i-0c"), LifecycleState: asgtypes.LifecycleStateTerminating, HealthStatus: aws.String("Unhealthy")},
+			{InstanceId: aws.String("i-0a"), LifecycleState: asgtypes.LifecycleStateInService, HealthStatus: aws.String("Healthy")},
+			{InstanceId: aws.String("i-0b"), LifecycleState: asgtypes.LifecycleStatePending},
+			{InstanceId: aws.String("i-0d"), LifecycleState: asgtypes.LifecycleStateStandby},
+		},
+	}}
+
+	instances, err := fetchAutoScalingGroupInstances(context.Background(), api, "us-east-1", "web-asg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids, targetable []string
+	for _, instance := range instances {
+		ids = append(ids, instance.InstanceID)
+		if instance.Targetable() {
+			targetable = append(targetable, instance.InstanceID)
+		}
+	}
+	if strings.Join(ids, ",") != "i-0a,i-0b,i-0c,i-0d" {
+		t.Errorf("Expected every member sorted by ID, got %v", ids)
+	}
+	if strings.Join(targetable, ",") != "i-0a,i-0d" {
+		t.Errorf("Expected only InService and Standby members to be targetable, got %v", targetable)
+	}
+
+	if _, err := fetchAutoScalingGroupInstances(context.Background(), api, "us-east-1", "missing"); err == nil || !strings.Contains(err.Error(), "no Auto Scaling group named missing") {
+		t.Errorf("Expected a not-found error, got %v", err)
+	}
+}
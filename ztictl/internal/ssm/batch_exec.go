@@ -0,0 +1,214 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"ztictl/internal/platform"
+	"ztictl/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// MaxInstancesPerCommand is the SendCommand limit on instance IDs per call
+const MaxInstancesPerCommand = 50
+
+// BatchCommandResult is the outcome of a batched command on one instance
+type BatchCommandResult struct {
+	InstanceID string
+	Result     *CommandResult // Also set alongside Err when the command timed out with partial output
+	Err        error
+}
+
+// ExecuteCommandBatch runs a command on up to MaxInstancesPerCommand instances with a single SendCommand
+// call per platform document, then polls the invocations of the whole batch together.
+// Results are returned in the order of instanceIDs; per-instance failures are reported in each result.
+func (m *Manager) ExecuteCommandBatch(ctx context.Context, region string, instanceIDs []string, command, comment string, opts ExecOptions) ([]BatchCommandResult, error) {
+	if len(instanceIDs) == 0 || len(instanceIDs) > MaxInstancesPerCommand {
+		return nil, fmt.Errorf("batch must contain between 1 and %d instances, got %d", MaxInstancesPerCommand, len(instanceIDs))
+	}
+
+	if err := m.initializePlatformComponents(ctx, region); err != nil {
+		return nil, fmt.Errorf("failed to initialize platform components: %w", err)
+	}
+
+	ssmClient, err := m.clientPool.GetSSMClient(ctx, region)
+	if err != nil {
+		return nil, errors.NewAWSError("failed to get SSM client", err)
+	}
+
+	if comment == "" {
+		comment = "Command executed via ztictl"
+	}
+
+	m.logger.Info("Executing batched command", "instances", len(instanceIDs), "command", command)
+	startTime := time.Now()
+
+	results := make(map[string]BatchCommandResult, len(instanceIDs))
+	groups, order := m.groupByDocument(ctx, instanceIDs, opts, results)
+
+	for _, documentName := range order {
+		group := groups[documentName]
+		wrappedCommand, applied, err := wrapCommand(group.builder, command, opts)
+		if err != nil {
+			for _, instanceID := range group.instanceIDs {
+				results[instanceID] = BatchCommandResult{InstanceID: instanceID, Err: err}
+			}
+			continue
+		}
+		if !applied {
+			m.logger.Warn(unwrappedWarning(opts), "document", documentName)
+		}
+
+		sendResp, err := ssmClient.SendCommand(ctx, sendCommandInput(documentName, group.instanceIDs, wrappedCommand, comment, opts))
+		if err != nil && opts.Platform == "" && isUnsupportedPlatform(err) {
+			m.logger.Warn("SSM document rejected for part of the batch, sending to each instance separately", "document", documentName, "instances", len(group.instanceIDs))
+			for instanceID, result := range m.executeIndividually(ctx, region, group.instanceIDs, command, comment, opts) {
+				results[instanceID] = result
+			}
+			continue
+		}
+		if err != nil {
+			for _, instanceID := range group.instanceIDs {
+				results[instanceID] = BatchCommandResult{InstanceID: instanceID, Err: errors.NewSSMError("failed to send command", err)}
+			}
+			continue
+		}
+
+		commandID := aws.ToString(sendResp.Command.CommandId)
+		m.logger.Debug("Batched command sent", "commandID", commandID, "instances", len(group.instanceIDs))
+
+		for instanceID, result := range m.waitForBatchCompletion(ctx, ssmClient, commandID, group.instanceIDs, opts) {
+			results[instanceID] = result
+		}
+	}
+
+	executionTime := time.Since(startTime)
+	ordered := make([]BatchCommandResult, len(instanceIDs))
+	for i, instanceID := range instanceIDs {
+		result := results[instanceID]
+		result.InstanceID = instanceID
+		if result.Result != nil {
+			result.Result.ExecutionTime = &executionTime
+			result.Result.Command = command
+		}
+		ordered[i] = result
+	}
+	return ordered, nil
+}
+
+// documentGroup is a set of instances that share an SSM document and command builder
+type documentGroup struct {
+	builder     platform.CommandBuilder
+	instanceIDs []string
+}
+
+// groupByDocument groups instances by the SSM document for their platform, in first-seen order.
+// Instances whose platform cannot be determined get an error in results instead.
+func (m *Manager) groupByDocument(ctx context.Context, instanceIDs []string, opts ExecOptions, results map[string]BatchCommandResult) (map[string]*documentGroup, []string) {
+	groups := make(map[string]*documentGroup)
+	var order []string
+
+	for _, instanceID := range instanceIDs {
+		builder, err := m.commandBuilder(ctx, instanceID, opts)
+		if err != nil {
+			results[instanceID] = BatchCommandResult{InstanceID: instanceID, Err: fmt.Errorf("failed to get command builder: %w", err)}
+			continue
+		}
+
+		documentName := builder.GetSSMDocument()
+		group, exists := groups[documentName]
+		if !exists {
+			group = &documentGroup{builder: builder}
+			groups[documentName] = group
+			order = append(order, documentName)
+		}
+		group.instanceIDs = append(group.instanceIDs, instanceID)
+	}
+
+	return groups, order
+}
+
+// waitForBatchCompletion polls all invocations of a command together until each instance finishes or the wait times out
+func (m *Manager) waitForBatchCompletion(ctx context.Context, ssmClient commandInvocationAPI, commandID string, instanceIDs []string, opts ExecOptions) map[string]BatchCommandResult {
+	results := make(map[string]BatchCommandResult, len(instanceIDs))
+	pending := make(map[string]bool, len(instanceIDs))
+	for _, instanceID := range instanceIDs {
+		pending[instanceID] = true
+	}
+
+	failPending := func(err error) map[string]BatchCommandResult {
+		for instanceID := range pending {
+			results[instanceID] = BatchCommandResult{InstanceID: instanceID, Err: err}
+		}
+		return results
+	}
+
+	maxWait := opts.completionTimeout()
+	deadline := time.Now().Add(maxWait)
+	for time.Now().Before(deadline) {
+		statuses, err := listInvocationStatuses(ctx, ssmClient, commandID)
+		if err != nil {
+			return failPending(fmt.Errorf("failed to check command status: %w", err))
+		}
+
+		for instanceID := range pending {
+			status, found := statuses[instanceID]
+			if !found || commandInProgress(status) {
+				continue
+			}
+			delete(pending, instanceID)
+
+			detailResp, err := ssmClient.GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
+				CommandId:  aws.String(commandID),
+				InstanceId: aws.String(instanceID),
+			})
+			if err != nil {
+				results[instanceID] = BatchCommandResult{InstanceID: instanceID, Err: fmt.Errorf("failed to get command result: %w", err)}
+				continue
+			}
+			results[instanceID] = BatchCommandResult{InstanceID: instanceID, Result: commandResultFromInvocation(instanceID, status, detailResp)}
+		}
+
+		if len(pending) == 0 {
+			return results
+		}
+		if err := sleepWithContext(ctx, commandPollInterval); err != nil {
+			m.cancelAbandoned(ctx, ssmClient, commandID, slices.Sorted(maps.Keys(pending)))
+			return failPending(fmt.Errorf("stopped waiting for command: %w", err))
+		}
+	}
+
+	timeoutErr := fmt.Errorf("command execution timed out after %v", maxWait)
+	for instanceID := range pending {
+		results[instanceID] = BatchCommandResult{
+			InstanceID: instanceID,
+			Result:     m.timedOutCommandResult(ctx, ssmClient, commandID, instanceID, opts.CancelOnTimeout),
+			Err:        timeoutErr,
+		}
+	}
+	return results
+}
+
+// listInvocationStatuses returns the invocation status of every instance targeted by a command
+func listInvocationStatuses(ctx context.Context, ssmClient commandInvocationAPI, commandID string) (map[string]string, error) {
+	statuses := make(map[string]string)
+	input := &ssm.ListCommandInvocationsInput{CommandId: aws.String(commandID)}
+	for {
+		listResp, err := ssmClient.ListCommandInvocations(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, invocation := range listResp.CommandInvocations {
+			statuses[aws.ToString(invocation.InstanceId)] = string(invocation.Status)
+		}
+		if aws.ToString(listResp.NextToken) == "" {
+			return statuses, nil
+		}
+		input.NextToken = listResp.NextToken
+	}
+}
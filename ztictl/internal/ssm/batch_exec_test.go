@@ -0,0 +1,112 @@
+package ssm
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"ztictl/pkg/logging"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// fakeBatchInvocationAPI reports a fixed status per instance, split across two pages
+type fakeBatchInvocationAPI struct {
+	statuses    map[string]ssmtypes.CommandInvocationStatus
+	exitCodes   map[string]int32
+	listCalls   int
+	detailCalls int
+	cancelled   []string
+}
+
+func (f *fakeBatchInvocationAPI) ListCommandInvocations(ctx context.Context, params *ssm.ListCommandInvocationsInput, optFns ...func(*ssm.Options)) (*ssm.ListCommandInvocationsOutput, error) {
+	f.listCalls++
+	// Sorted so that both pages are built from the same order and together cover every instance
+	var page []ssmtypes.CommandInvocation
+	for _, instanceID := range slices.Sorted(maps.Keys(f.statuses)) {
+		page = append(page, ssmtypes.CommandInvocation{InstanceId: aws.String(instanceID), Status: f.statuses[instanceID]})
+	}
+	if params.NextToken == nil {
+		return &ssm.ListCommandInvocationsOutput{CommandInvocations: page[:1], NextToken: aws.String("page-2")}, nil
+	}
+	return &ssm.ListCommandInvocationsOutput{CommandInvocations: page[1:]}, nil
+}
+
+func (f *fakeBatchInvocationAPI) GetCommandInvocation(ctx context.Context, params *ssm.GetCommandInvocationInput, optFns ...func(*ssm.Options)) (*ssm.GetCommandInvocationOutput, error) {
+	f.detailCalls++
+	instanceID := aws.ToString(params.InstanceId)
+	return &ssm.GetCommandInvocationOutput{
+		StandardOutputContent: aws.String("hello from " + instanceID + "\nEXIT_CODE:0"),
+		ResponseCode:          f.exitCodes[instanceID],
+	}, nil
+}
+
+func (f *fakeBatchInvocationAPI) CancelCommand(ctx context.Context, params *ssm.CancelCommandInput, optFns ...func(*ssm.Options)) (*ssm.CancelCommandOutput, error) {
+	f.cancelled = append(f.cancelled, params.InstanceIds...)
+	return &ssm.CancelCommandOutput{}, nil
+}
+
+func TestWaitForBatchCompletion(t *testing.T) {
+	manager := NewManager(logging.NewNoOpLogger())
+	api := &fakeBatchInvocationAPI{
+		statuses: map[string]ssmtypes.CommandInvocationStatus{
+			"i-aaa": ssmtypes.CommandInvocationStatusSuccess,
+			"i-bbb": ssmtypes.CommandInvocationStatusFailed,
+		},
+		exitCodes: map[string]int32{"i-bbb": 3},
+	}
+
+	results := manager.waitForBatchCompletion(context.Background(), api, "cmd-1", []string{"i-aaa", "i-bbb"}, ExecOptions{})
+
+	if api.listCalls != 2 {
+		t.Errorf("Expected one paginated poll for the whole batch (2 pages), got %d list calls", api.listCalls)
+	}
+	if api.detailCalls != 2 {
+		t.Errorf("Expected one detail call per instance, got %d", api.detailCalls)
+	}
+	if got := results["i-aaa"]; got.Err != nil || got.Result.Output != "hello from i-aaa" || got.Result.ExitCode != nil {
+		t.Errorf("Unexpected result for i-aaa: %+v", got)
+	}
+	if got := results["i-bbb"]; got.Result == nil || got.Result.ExitCode == nil || *got.Result.ExitCode != 3 {
+		t.Errorf("Expected exit code 3 for i-bbb, got %+v", got)
+	}
+}
+
+func TestWaitForBatchCompletionTimeout(t *testing.T) {
+	origTimeout, origPoll := commandCompletionTimeout, commandPollInterval
+	commandCompletionTimeout, commandPollInterval = 20*time.Millisecond, 5*time.Millisecond
+	defer func() { commandCompletionTimeout, commandPollInterval = origTimeout, origPoll }()
+
+	manager := NewManager(logging.NewNoOpLogger())
+	api := &fakeBatchInvocationAPI{statuses: map[string]ssmtypes.CommandInvocationStatus{
+		"i-done": ssmtypes.CommandInvocationStatusSuccess,
+		"i-slow": ssmtypes.CommandInvocationStatusInProgress,
+	}}
+
+	results := manager.waitForBatchCompletion(context.Background(), api, "cmd-1", []string{"i-done", "i-slow"}, ExecOptions{})
+
+	if results["i-done"].Err != nil {
+		t.Errorf("Expected finished instance to succeed, got %v", results["i-done"].Err)
+	}
+	slow := results["i-slow"]
+	if slow.Err == nil || !strings.Contains(slow.Err.Error(), "timed out") || !slow.Result.TimedOut() {
+		t.Errorf("Expected timed out result for i-slow, got %+v", slow)
+	}
+}
+
+func TestExecuteCommandBatchSizeLimits(t *testing.T) {
+	manager := NewManager(logging.NewNoOpLogger())
+
+	if _, err := manager.ExecuteCommandBatch(context.Background(), "us-east-1", nil, "uptime", "", ExecOptions{}); err == nil {
+		t.Error("Expected empty batch to be rejected")
+	}
+	tooMany := make([]string, MaxInstancesPerCommand+1)
+	if _, err := manager.ExecuteCommandBatch(context.Background(), "us-east-1", tooMany, "uptime", "", ExecOptions{}); err == nil {
+		t.Error("Expected batch above the SendCommand limit to be rejected")
+	}
+}
@@ -0,0 +1,56 @@
+package ssm
+
+import (
+	"context"
+	"sync"
+)
+
+// bucketCache holds the transfer bucket of each region once it has been resolved and ensured, so
+// that repeated transfers through one Manager skip the STS, HeadBucket and lifecycle calls
+type bucketCache struct {
+	mu      sync.Mutex
+	entries map[string]*bucketEntry
+}
+
+// bucketEntry is the bucket of one region. Its lock is held while the bucket is ensured, so only
+// transfers to the same region wait for it.
+type bucketEntry struct {
+	mu         sync.Mutex
+	bucketName string
+}
+
+// get returns the cached bucket of a region, or resolves it with ensure and caches it on success.
+// Concurrent transfers to a new region wait for a single ensure instead of racing to create the bucket,
+// while transfers to other regions go ahead.
+func (c *bucketCache) get(ctx context.Context, region string, ensure func(ctx context.Context, region string) (string, error)) (string, error) {
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]*bucketEntry)
+	}
+	entry, ok := c.entries[region]
+	if !ok {
+		entry = &bucketEntry{}
+		c.entries[region] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.bucketName != "" {
+		return entry.bucketName, nil
+	}
+
+	bucketName, err := ensure(ctx, region)
+	if err != nil {
+		return "", err
+	}
+	entry.bucketName = bucketName
+	return bucketName, nil
+}
+
+// invalidate forgets the bucket of a region, so the next transfer checks it again
+func (c *bucketCache) invalidate(region string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, region)
+}
@@ -0,0 +1,119 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBucketCache(t *testing.T) {
+	var cache bucketCache
+	calls := map[string]int{}
+	fail := false
+	ensure := func(ctx context.Context, region string) (string, error) {
+		calls[region]++
+		if fail {
+			return "", fmt.Errorf("AccessDenied")
+		}
+		return "ztictl-ssm-file-transfer-123456789012-" + region, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		bucketName, err := cache.get(context.Background(), "ca-central-1", ensure)
+		if err != nil || bucketName != "ztictl-ssm-file-transfer-123456789012-ca-central-1" {
+			t.Fatalf("get() = %q, %v", bucketName, err)
+		}
+	}
+	if _, err := cache.get(context.Background(), "us-east-1", ensure); err != nil {
+		t.Fatal(err)
+	}
+	if calls["ca-central-1"] != 1 || calls["us-east-1"] != 1 {
+		t.Errorf("Expected the bucket of each region to be ensured once, got %v", calls)
+	}
+
+	// After invalidation the bucket is ensured again, and a failure is not cached
+	cache.invalidate("ca-central-1")
+	fail = true
+	if _, err := cache.get(context.Background(), "ca-central-1", ensure); err == nil {
+		t.Error("Expected the ensure error to be returned")
+	}
+	fail = false
+	if _, err := cache.get(context.Background(), "ca-central-1", ensure); err != nil {
+		t.Fatal(err)
+	}
+	if calls["ca-central-1"] != 3 {
+		t.Errorf("Expected the bucket to be ensured again after invalidation and failure, got %d calls", calls["ca-central-1"])
+	}
+	if _, err := cache.get(context.Background(), "us-east-1", ensure); err != nil || calls["us-east-1"] != 1 {
+		t.Errorf("Expected other regions to stay cached, got %d calls, %v", calls["us-east-1"], err)
+	}
+}
+
+func TestBucketCacheConcurrentFirstUse(t *testing.T) {
+	var cache bucketCache
+	var mu sync.Mutex
+	calls := 0
+	ensure := func(ctx context.Context, region string) (string, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		return "bucket-" + region, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = cache.get(context.Background(), "eu-west-1", ensure)
+		}()
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected concurrent transfers to ensure the bucket once, got %d", calls)
+	}
+}
+
+func TestBucketCacheSlowRegionDoesNotBlockOthers(t *testing.T) {
+	var cache bucketCache
+	if _, err := cache.get(context.Background(), "us-east-1", func(ctx context.Context, region string) (string, error) {
+		return "bucket-" + region, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = cache.get(context.Background(), "eu-west-1", func(ctx context.Context, region string) (string, error) {
+			close(started)
+			<-release
+			return "bucket-" + region, nil
+		})
+	}()
+	<-started
+
+	// The first setup of eu-west-1 is still running; a cached region must not wait for it
+	lookup := make(chan string)
+	go func() {
+		bucketName, _ := cache.get(context.Background(), "us-east-1", func(ctx context.Context, region string) (string, error) {
+			return "", fmt.Errorf("unexpected ensure for %s", region)
+		})
+		lookup <- bucketName
+	}()
+	select {
+	case bucketName := <-lookup:
+		if bucketName != "bucket-us-east-1" {
+			t.Errorf("Expected the cached bucket, got %q", bucketName)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("Expected a cached region to be returned while another region is being ensured")
+	}
+
+	close(release)
+	<-done
+}
@@ -0,0 +1,82 @@
+package ssm
+
+import (
+	"sync"
+
+	"ztictl/pkg/logging"
+)
+
+// pendingCleanup is a registered cleanup of a temporary transfer resource
+type pendingCleanup struct {
+	description string
+	run         func() error
+}
+
+// cleanupRegistry holds the temporary resources (IAM policies, staged S3 objects) of transfers in progress.
+// Transfers release their entries through the normal deferred cleanups; entries still registered when the
+// process panics are drained by CleanupOnPanic. Resources left behind by a hard crash are found later by
+// ReconcileOrphans through their naming conventions.
+var cleanupRegistry = struct {
+	sync.Mutex
+	next    int
+	pending map[int]pendingCleanup
+}{pending: make(map[int]pendingCleanup)}
+
+// registerCleanup records a cleanup until it runs. The returned function runs it at most once,
+// whether it is called by the owner's defer or by a drain, and removes it from the registry.
+func registerCleanup(description string, cleanup func() error) func() error {
+	var once sync.Once
+	var err error
+
+	cleanupRegistry.Lock()
+	id := cleanupRegistry.next
+	cleanupRegistry.next++
+	run := func() error {
+		once.Do(func() {
+			cleanupRegistry.Lock()
+			delete(cleanupRegistry.pending, id)
+			cleanupRegistry.Unlock()
+			err = cleanup()
+		})
+		return err
+	}
+	cleanupRegistry.pending[id] = pendingCleanup{description: description, run: run}
+	cleanupRegistry.Unlock()
+
+	return run
+}
+
+// pendingCleanupCount returns how many cleanups are registered
+func pendingCleanupCount() int {
+	cleanupRegistry.Lock()
+	defer cleanupRegistry.Unlock()
+	return len(cleanupRegistry.pending)
+}
+
+// DrainPendingCleanups runs every registered cleanup and returns how many ran
+func DrainPendingCleanups() int {
+	cleanupRegistry.Lock()
+	pending := make([]pendingCleanup, 0, len(cleanupRegistry.pending))
+	for _, entry := range cleanupRegistry.pending {
+		pending = append(pending, entry)
+	}
+	cleanupRegistry.Unlock()
+
+	for _, entry := range pending {
+		logging.LogWarn("Cleaning up %s", entry.description)
+		if err := entry.run(); err != nil {
+			logging.LogWarn("Failed to clean up %s: %v", entry.description, err)
+		}
+	}
+	return len(pending)
+}
+
+// CleanupOnPanic drains pending transfer cleanups when the calling goroutine panics, then re-panics.
+// Defer it at the top of main and of goroutines that run transfers: a panic in any goroutine ends the
+// process, and deferred cleanups of other goroutines never run.
+func CleanupOnPanic() {
+	if r := recover(); r != nil {
+		DrainPendingCleanups()
+		panic(r)
+	}
+}
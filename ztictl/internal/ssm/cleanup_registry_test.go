@@ -0,0 +1,83 @@
+package ssm
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegisterCleanupRunsOnce(t *testing.T) {
+	runs := 0
+	release := registerCleanup("test resource", func() error {
+		runs++
+		return errors.New("already gone")
+	})
+	if pendingCleanupCount() != 1 {
+		t.Fatalf("pending = %d, want 1", pendingCleanupCount())
+	}
+
+	if err := release(); err == nil {
+		t.Error("expected the cleanup error to be returned")
+	}
+	if err := release(); err == nil {
+		t.Error("expected a repeated call to return the first error")
+	}
+	if runs != 1 {
+		t.Errorf("cleanup ran %d times, want 1", runs)
+	}
+	if pendingCleanupCount() != 0 {
+		t.Errorf("pending = %d after release, want 0", pendingCleanupCount())
+	}
+}
+
+func TestDrainPendingCleanups(t *testing.T) {
+	var ran []string
+	releaseA := registerCleanup("a", func() error { ran = append(ran, "a"); return nil })
+	registerCleanup("b", func() error { ran = append(ran, "b"); return errors.New("failed") })
+
+	if drained := DrainPendingCleanups(); drained != 2 {
+		t.Errorf("drained %d cleanups, want 2", drained)
+	}
+	if len(ran) != 2 || pendingCleanupCount() != 0 {
+		t.Errorf("ran %v with %d pending, want both run and none pending", ran, pendingCleanupCount())
+	}
+
+	// The owner's deferred release after a drain must not clean up twice
+	_ = releaseA()
+	if len(ran) != 2 {
+		t.Errorf("cleanup ran again after drain: %v", ran)
+	}
+	if DrainPendingCleanups() != 0 {
+		t.Error("expected nothing left to drain")
+	}
+}
+
+func TestCleanupOnPanic(t *testing.T) {
+	ran := false
+	registerCleanup("panicking transfer", func() error { ran = true; return nil })
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("expected the panic to be re-raised, got %v", r)
+		}
+		if !ran || pendingCleanupCount() != 0 {
+			t.Error("expected pending cleanups to be drained on panic")
+		}
+	}()
+
+	func() {
+		defer CleanupOnPanic()
+		panic("boom")
+	}()
+}
+
+func TestCleanupOnPanicWithoutPanic(t *testing.T) {
+	release := registerCleanup("finished transfer", func() error { return nil })
+	defer func() { _ = release() }()
+
+	func() {
+		defer CleanupOnPanic()
+	}()
+	if pendingCleanupCount() != 1 {
+		t.Error("cleanups must not be drained without a panic")
+	}
+}
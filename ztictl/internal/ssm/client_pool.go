@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"sync"
 
+	awsservice "ztictl/pkg/aws"
+
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
@@ -27,6 +30,7 @@ type clientSet struct {
 	S3Client  *s3.Client
 	STSClient *sts.Client
 	RDSClient *rds.Client
+	ASGClient *autoscaling.Client
 }
 
 type ClientPool struct {
@@ -68,9 +72,9 @@ func (p *ClientPool) GetClients(ctx context.Context, region string) (*clientSet,
 }
 
 func (p *ClientPool) createClientSet(ctx context.Context, region string) (*clientSet, error) {
-	cfg, err := config.LoadDefaultConfig(ctx,
+	cfg, err := config.LoadDefaultConfig(ctx, append([]func(*config.LoadOptions) error{
 		config.WithRegion(region),
-	)
+	}, awsservice.LoadOptions(ctx, "")...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config for region %s: %w", region, err)
 	}
@@ -80,14 +84,25 @@ func (p *ClientPool) createClientSet(ctx context.Context, region string) (*clien
 		SSMClient: ssm.NewFromConfig(cfg),
 		EC2Client: ec2.NewFromConfig(cfg),
 		IAMClient: iam.NewFromConfig(cfg),
-		S3Client:  s3.NewFromConfig(cfg),
+		S3Client:  s3.NewFromConfig(cfg, s3PathStyle(cfg)),
 		STSClient: sts.NewFromConfig(cfg),
 		RDSClient: rds.NewFromConfig(cfg),
+		ASGClient: autoscaling.NewFromConfig(cfg),
 	}
 
 	return clients, nil
 }
 
+// s3PathStyle uses path-style bucket addressing when a custom endpoint is set (--aws-endpoint-url or
+// AWS_ENDPOINT_URL), as emulators such as LocalStack do not serve virtual-hosted bucket names
+func s3PathStyle(cfg aws.Config) func(*s3.Options) {
+	return func(o *s3.Options) {
+		if cfg.BaseEndpoint != nil {
+			o.UsePathStyle = true
+		}
+	}
+}
+
 func (p *ClientPool) GetSSMClient(ctx context.Context, region string) (*ssm.Client, error) {
 	clients, err := p.GetClients(ctx, region)
 	if err != nil {
@@ -136,6 +151,14 @@ func (p *ClientPool) GetRDSClient(ctx context.Context, region string) (*rds.Clie
 	return clients.RDSClient, nil
 }
 
+func (p *ClientPool) GetAutoScalingClient(ctx context.Context, region string) (*autoscaling.Client, error) {
+	clients, err := p.GetClients(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	return clients.ASGClient, nil
+}
+
 func (p *ClientPool) GetPlatformClients(ctx context.Context, region string) (*ssm.Client, *ec2.Client, error) {
 	clients, err := p.GetClients(ctx, region)
 	if err != nil {
@@ -0,0 +1,42 @@
+package ssm
+
+import (
+	"context"
+	"testing"
+
+	awsservice "ztictl/pkg/aws"
+)
+
+func TestClientPoolEndpointOverride(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL", "")
+	if err := awsservice.SetEndpointURL("http://localhost:4566"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = awsservice.SetEndpointURL("") }()
+
+	clients, err := NewClientPool().GetClients(context.Background(), "us-east-1")
+	if err != nil {
+		t.Fatalf("GetClients failed: %v", err)
+	}
+	if clients.Config.BaseEndpoint == nil || *clients.Config.BaseEndpoint != "http://localhost:4566" {
+		t.Errorf("Expected the endpoint override on the pooled config, got %v", clients.Config.BaseEndpoint)
+	}
+	if !clients.S3Client.Options().UsePathStyle {
+		t.Error("Expected path-style S3 addressing with a custom endpoint")
+	}
+}
+
+func TestClientPoolDefaultEndpoint(t *testing.T) {
+	t.Setenv("AWS_ENDPOINT_URL", "")
+
+	clients, err := NewClientPool().GetClients(context.Background(), "us-east-1")
+	if err != nil {
+		t.Fatalf("GetClients failed: %v", err)
+	}
+	if clients.Config.BaseEndpoint != nil {
+		t.Errorf("Expected the default endpoint resolver, got %q", *clients.Config.BaseEndpoint)
+	}
+	if clients.S3Client.Options().UsePathStyle {
+		t.Error("Expected virtual-hosted S3 addressing without a custom endpoint")
+	}
+}
@@ -0,0 +1,76 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// MaxCommandTimeout is the longest execution timeout the AWS-RunShellScript and AWS-RunPowerShellScript
+// documents accept
+const MaxCommandTimeout = 48 * time.Hour
+
+const (
+	// minDeliveryTimeoutSeconds is the lowest SendCommand TimeoutSeconds SSM accepts
+	minDeliveryTimeoutSeconds = 30
+
+	// abandonCancelTimeout bounds the CancelCommand call sent after the batch timeout has expired
+	abandonCancelTimeout = 10 * time.Second
+)
+
+// ErrBatchTimeout is the cause of a context ended by the overall batch timeout. It wraps
+// context.DeadlineExceeded, so it is reported like any other expired deadline.
+var ErrBatchTimeout = fmt.Errorf("batch timeout reached: %w", context.DeadlineExceeded)
+
+// completionTimeout returns how long to wait for the command on each instance
+func (o ExecOptions) completionTimeout() time.Duration {
+	if o.Timeout > 0 {
+		return o.Timeout
+	}
+	return commandCompletionTimeout
+}
+
+// sendCommandInput builds the SendCommand request for a wrapped command. With a Timeout, the command
+// is also stopped on the instances after it, and SSM gives up delivering it to an instance that does
+// not pick it up in that time.
+func sendCommandInput(documentName string, instanceIDs []string, wrappedCommand, comment string, opts ExecOptions) *ssm.SendCommandInput {
+	input := &ssm.SendCommandInput{
+		DocumentName: aws.String(documentName),
+		InstanceIds:  instanceIDs,
+		Parameters: map[string][]string{
+			"commands": {wrappedCommand},
+		},
+		Comment: aws.String(comment),
+	}
+	if opts.Timeout > 0 {
+		seconds := int32(math.Ceil(opts.Timeout.Seconds()))
+		input.Parameters["executionTimeout"] = []string{strconv.Itoa(int(seconds))}
+		input.TimeoutSeconds = aws.Int32(max(seconds, minDeliveryTimeoutSeconds))
+	}
+	return input
+}
+
+// cancelAbandoned cancels a command on the instances ztictl stopped waiting for because the batch
+// timeout ended ctx. Other reasons for ctx ending leave the command running.
+func (m *Manager) cancelAbandoned(ctx context.Context, ssmClient commandInvocationAPI, commandID string, instanceIDs []string) {
+	if len(instanceIDs) == 0 || !errors.Is(context.Cause(ctx), ErrBatchTimeout) {
+		return
+	}
+
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), abandonCancelTimeout)
+	defer cancel()
+	if _, err := ssmClient.CancelCommand(cancelCtx, &ssm.CancelCommandInput{
+		CommandId:   aws.String(commandID),
+		InstanceIds: instanceIDs,
+	}); err != nil {
+		m.logger.Warn("Failed to cancel command after batch timeout", "commandID", commandID, "error", err)
+		return
+	}
+	m.logger.Info("Cancelled command after batch timeout", "commandID", commandID, "instances", len(instanceIDs))
+}
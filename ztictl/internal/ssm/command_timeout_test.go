@@ -0,0 +1,111 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"ztictl/pkg/logging"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+func TestSendCommandInputTimeout(t *testing.T) {
+	tests := []struct {
+		name          string
+		timeout       time.Duration
+		wantExecution []string
+		wantDelivery  *int32
+	}{
+		{name: "no timeout keeps document defaults", timeout: 0},
+		{name: "whole seconds", timeout: 10 * time.Minute, wantExecution: []string{"600"}, wantDelivery: aws.Int32(600)},
+		{name: "rounds up partial seconds", timeout: 1500 * time.Millisecond, wantExecution: []string{"2"}, wantDelivery: aws.Int32(30)},
+		{name: "delivery timeout has a floor of 30s", timeout: 5 * time.Second, wantExecution: []string{"5"}, wantDelivery: aws.Int32(30)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := sendCommandInput("AWS-RunShellScript", []string{"i-1"}, "uptime", "comment", ExecOptions{Timeout: tt.timeout})
+
+			if got := input.Parameters["commands"]; !slices.Equal(got, []string{"uptime"}) {
+				t.Errorf("commands = %v, want [uptime]", got)
+			}
+			if got := input.Parameters["executionTimeout"]; !slices.Equal(got, tt.wantExecution) {
+				t.Errorf("executionTimeout = %v, want %v", got, tt.wantExecution)
+			}
+			if aws.ToInt32(input.TimeoutSeconds) != aws.ToInt32(tt.wantDelivery) {
+				t.Errorf("TimeoutSeconds = %d, want %d", aws.ToInt32(input.TimeoutSeconds), aws.ToInt32(tt.wantDelivery))
+			}
+		})
+	}
+}
+
+func TestWaitForBatchCompletionUsesTimeoutOption(t *testing.T) {
+	origPoll := commandPollInterval
+	commandPollInterval = 5 * time.Millisecond
+	defer func() { commandPollInterval = origPoll }()
+
+	manager := NewManager(logging.NewNoOpLogger())
+	api := &fakeBatchInvocationAPI{statuses: map[string]ssmtypes.CommandInvocationStatus{
+		"i-slow": ssmtypes.CommandInvocationStatusInProgress,
+		"i-zzz":  ssmtypes.CommandInvocationStatusInProgress,
+	}}
+
+	start := time.Now()
+	results := manager.waitForBatchCompletion(context.Background(), api, "cmd-1", []string{"i-slow", "i-zzz"}, ExecOptions{Timeout: 20 * time.Millisecond})
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the wait to stop at the 20ms timeout, took %v", elapsed)
+	}
+	if !results["i-slow"].Result.TimedOut() {
+		t.Errorf("Expected a timed out result, got %+v", results["i-slow"])
+	}
+}
+
+func TestBatchTimeoutCancelsOutstandingCommands(t *testing.T) {
+	origPoll := commandPollInterval
+	commandPollInterval = 5 * time.Millisecond
+	defer func() { commandPollInterval = origPoll }()
+
+	tests := []struct {
+		name          string
+		cause         error
+		wantCancelled []string
+	}{
+		{name: "batch timeout cancels pending instances", cause: ErrBatchTimeout, wantCancelled: []string{"i-slow"}},
+		{name: "other cancellation leaves them running", cause: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewManager(logging.NewNoOpLogger())
+			api := &fakeBatchInvocationAPI{statuses: map[string]ssmtypes.CommandInvocationStatus{
+				"i-done": ssmtypes.CommandInvocationStatusSuccess,
+				"i-slow": ssmtypes.CommandInvocationStatusInProgress,
+			}}
+			ctx, cancel := context.WithCancelCause(context.Background())
+			time.AfterFunc(20*time.Millisecond, func() { cancel(tt.cause) })
+
+			results := manager.waitForBatchCompletion(ctx, api, "cmd-1", []string{"i-done", "i-slow"}, ExecOptions{})
+
+			if results["i-done"].Err != nil {
+				t.Errorf("Expected finished instance to keep its result, got %v", results["i-done"].Err)
+			}
+			if err := results["i-slow"].Err; !errors.Is(err, tt.cause) {
+				t.Errorf("Expected pending instance to fail with %v, got %v", tt.cause, err)
+			}
+			if !slices.Equal(api.cancelled, tt.wantCancelled) {
+				t.Errorf("cancelled = %v, want %v", api.cancelled, tt.wantCancelled)
+			}
+		})
+	}
+}
+
+func TestErrBatchTimeoutIsDeadline(t *testing.T) {
+	if !errors.Is(ErrBatchTimeout, context.DeadlineExceeded) {
+		t.Error("ErrBatchTimeout should wrap context.DeadlineExceeded")
+	}
+}
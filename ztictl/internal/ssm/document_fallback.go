@@ -0,0 +1,104 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"ztictl/internal/platform"
+
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// sendCommandAPI is the SendCommand call, separated so the document fallback can be tested
+type sendCommandAPI interface {
+	SendCommand(ctx context.Context, params *ssm.SendCommandInput, optFns ...func(*ssm.Options)) (*ssm.SendCommandOutput, error)
+}
+
+// isUnsupportedPlatform reports whether SendCommand rejected a document that does not match the instance platform
+func isUnsupportedPlatform(err error) bool {
+	var unsupported *ssmtypes.UnsupportedPlatformType
+	return errors.As(err, &unsupported)
+}
+
+// commandBuilder returns the builder for opts.Platform when it is forced, or the one detected for the instance
+func (m *Manager) commandBuilder(ctx context.Context, instanceID string, opts ExecOptions) (platform.CommandBuilder, error) {
+	if opts.Platform != "" {
+		return platform.NewBuilderFactory().GetBuilder(opts.Platform)
+	}
+	return m.builderManager.GetBuilder(ctx, instanceID)
+}
+
+// sendCommand sends command to one instance with builder's SSM document and wrapper. When the platform was
+// auto-detected and the instance rejects the document as the wrong platform, it retries once with the other
+// platform's document and keeps that builder for later commands on the instance.
+func (m *Manager) sendCommand(ctx context.Context, ssmClient sendCommandAPI, instanceID string, builder platform.CommandBuilder, command, comment string, opts ExecOptions) (*ssm.SendCommandOutput, error) {
+	send := func(builder platform.CommandBuilder) (*ssm.SendCommandOutput, error) {
+		wrappedCommand, applied, err := wrapCommand(builder, command, opts)
+		if err != nil {
+			return nil, err
+		}
+		if !applied {
+			m.logger.Warn(unwrappedWarning(opts), "instanceID", instanceID)
+		}
+		return ssmClient.SendCommand(ctx, sendCommandInput(builder.GetSSMDocument(), []string{instanceID}, wrappedCommand, comment, opts))
+	}
+
+	sendResp, err := send(builder)
+	if err == nil || opts.Platform != "" || !isUnsupportedPlatform(err) {
+		return sendResp, err
+	}
+
+	alternate, alternatePlatform := platform.AlternateBuilder(builder)
+	m.logger.Warn("Instance rejected the SSM document for its detected platform, retrying as "+string(alternatePlatform)+" (use --platform to skip detection)",
+		"instanceID", instanceID, "document", builder.GetSSMDocument(), "retryDocument", alternate.GetSSMDocument())
+	sendResp, err = send(alternate)
+	if err == nil && m.builderManager != nil {
+		m.builderManager.SetBuilder(instanceID, alternate)
+	}
+	return sendResp, err
+}
+
+// executeIndividually runs a command on each instance with its own SendCommand call, so that each can fall
+// back to the other platform's document. It is used when a batched send was rejected because some instance
+// in it does not match the document.
+func (m *Manager) executeIndividually(ctx context.Context, region string, instanceIDs []string, command, comment string, opts ExecOptions) map[string]BatchCommandResult {
+	return executeEach(ctx, instanceIDs, opts, func(instanceID string) (*CommandResult, error) {
+		return m.ExecuteCommandWithOptions(ctx, instanceID, region, command, comment, opts)
+	})
+}
+
+// executeEach calls execute for each instance, running at most opts.FallbackParallel at once and waiting
+// on opts.FallbackWait before each call. An instance whose wait fails is not started.
+func executeEach(ctx context.Context, instanceIDs []string, opts ExecOptions, execute func(instanceID string) (*CommandResult, error)) map[string]BatchCommandResult {
+	results := make(map[string]BatchCommandResult, len(instanceIDs))
+	semaphore := make(chan struct{}, max(1, opts.FallbackParallel))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, instanceID := range instanceIDs {
+		wg.Add(1)
+		go func(instanceID string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			var result *CommandResult
+			var err error
+			if opts.FallbackWait != nil {
+				if waitErr := opts.FallbackWait(ctx); waitErr != nil {
+					err = fmt.Errorf("not started: %w", waitErr)
+				}
+			}
+			if err == nil {
+				result, err = execute(instanceID)
+			}
+			mu.Lock()
+			results[instanceID] = BatchCommandResult{InstanceID: instanceID, Result: result, Err: err}
+			mu.Unlock()
+		}(instanceID)
+	}
+	wg.Wait()
+	return results
+}
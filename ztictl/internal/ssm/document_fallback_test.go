@@ -0,0 +1,133 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ztictl/internal/platform"
+	"ztictl/pkg/logging"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// fakeSendCommandAPI rejects every document except accepted as the wrong platform
+type fakeSendCommandAPI struct {
+	accepted  string
+	documents []string
+}
+
+func (f *fakeSendCommandAPI) SendCommand(ctx context.Context, params *ssm.SendCommandInput, optFns ...func(*ssm.Options)) (*ssm.SendCommandOutput, error) {
+	document := aws.ToString(params.DocumentName)
+	f.documents = append(f.documents, document)
+	if document != f.accepted {
+		return nil, &ssmtypes.UnsupportedPlatformType{Message: aws.String("document is not supported on this platform")}
+	}
+	return &ssm.SendCommandOutput{Command: &ssmtypes.Command{CommandId: aws.String("cmd-1")}}, nil
+}
+
+func TestSendCommandFallsBackToTheOtherDocument(t *testing.T) {
+	manager := NewManager(logging.NewNoOpLogger())
+	manager.builderManager = platform.NewBuilderManager(nil)
+	api := &fakeSendCommandAPI{accepted: "AWS-RunPowerShellScript"}
+
+	resp, err := manager.sendCommand(context.Background(), api, "i-odd", platform.NewLinuxBuilder(), "hostname", "test", ExecOptions{})
+	if err != nil {
+		t.Fatalf("Expected the retry with the Windows document to succeed, got %v", err)
+	}
+	if aws.ToString(resp.Command.CommandId) != "cmd-1" {
+		t.Errorf("Unexpected response %+v", resp)
+	}
+	if len(api.documents) != 2 || api.documents[0] != "AWS-RunShellScript" || api.documents[1] != "AWS-RunPowerShellScript" {
+		t.Errorf("Expected one retry with the other document, got %v", api.documents)
+	}
+
+	builder, err := manager.builderManager.GetBuilder(context.Background(), "i-odd")
+	if err != nil || builder.GetSSMDocument() != "AWS-RunPowerShellScript" {
+		t.Errorf("Expected the corrected builder to be remembered, got %v (%v)", builder, err)
+	}
+}
+
+func TestSendCommandKeepsAForcedPlatform(t *testing.T) {
+	manager := NewManager(logging.NewNoOpLogger())
+	api := &fakeSendCommandAPI{accepted: "AWS-RunPowerShellScript"}
+
+	_, err := manager.sendCommand(context.Background(), api, "i-odd", platform.NewLinuxBuilder(), "hostname", "test", ExecOptions{Platform: platform.PlatformLinux})
+	if !isUnsupportedPlatform(err) {
+		t.Errorf("Expected the rejection to be returned with --platform set, got %v", err)
+	}
+	if len(api.documents) != 1 {
+		t.Errorf("Expected no retry with --platform set, got %v", api.documents)
+	}
+}
+
+func TestIsUnsupportedPlatform(t *testing.T) {
+	if isUnsupportedPlatform(errors.New("throttled")) {
+		t.Error("Expected other errors not to count as a platform mismatch")
+	}
+	wrapped := fmt.Errorf("send: %w", &ssmtypes.UnsupportedPlatformType{})
+	if !isUnsupportedPlatform(wrapped) {
+		t.Error("Expected a wrapped UnsupportedPlatformType to count as a platform mismatch")
+	}
+}
+
+func TestExecuteEachBoundsConcurrency(t *testing.T) {
+	instanceIDs := make([]string, 20)
+	for i := range instanceIDs {
+		instanceIDs[i] = fmt.Sprintf("i-%02d", i)
+	}
+
+	for _, tt := range []struct {
+		parallel int
+		want     int32
+	}{
+		{parallel: 0, want: 1},
+		{parallel: 3, want: 3},
+	} {
+		var running, peak atomic.Int32
+		var waits atomic.Int32
+		opts := ExecOptions{FallbackParallel: tt.parallel, FallbackWait: func(ctx context.Context) error {
+			waits.Add(1)
+			return nil
+		}}
+		results := executeEach(context.Background(), instanceIDs, opts, func(instanceID string) (*CommandResult, error) {
+			now := running.Add(1)
+			for {
+				old := peak.Load()
+				if now <= old || peak.CompareAndSwap(old, now) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			return &CommandResult{InstanceID: instanceID, Status: "Success"}, nil
+		})
+
+		if len(results) != len(instanceIDs) || results["i-07"].Result == nil {
+			t.Fatalf("Expected a result for every instance, got %d", len(results))
+		}
+		if peak.Load() > tt.want {
+			t.Errorf("FallbackParallel %d: %d sends ran at once, want at most %d", tt.parallel, peak.Load(), tt.want)
+		}
+		if waits.Load() != int32(len(instanceIDs)) {
+			t.Errorf("Expected FallbackWait before each of the %d sends, got %d", len(instanceIDs), waits.Load())
+		}
+	}
+}
+
+func TestExecuteEachWaitFailureLeavesInstanceUnstarted(t *testing.T) {
+	opts := ExecOptions{FallbackParallel: 2, FallbackWait: func(ctx context.Context) error { return context.DeadlineExceeded }}
+	results := executeEach(context.Background(), []string{"i-1"}, opts, func(instanceID string) (*CommandResult, error) {
+		t.Error("Expected no send after the wait failed")
+		return nil, nil
+	})
+	if err := results["i-1"].Err; err == nil || !strings.Contains(err.Error(), "not started") {
+		t.Errorf("Expected the instance to be reported as not started, got %v", err)
+	}
+}
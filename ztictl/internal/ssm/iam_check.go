@@ -0,0 +1,216 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ztictl/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// IAM check statuses of an instance
+const (
+	IAMCheckReady              = "ready"
+	IAMCheckNoInstanceProfile  = "no_instance_profile"
+	IAMCheckNoRole             = "no_role"
+	IAMCheckMissingPermissions = "missing_permissions"
+	IAMCheckUnverified         = "unverified"
+	IAMCheckError              = "error"
+)
+
+// maxInstanceIDFilterValues is the DescribeInstances limit on values per filter
+const maxInstanceIDFilterValues = 200
+
+// transferRoleActions are the SSM agent permissions the instance role needs to receive the commands of a
+// transfer, as granted by the AmazonSSMManagedInstanceCore managed policy. S3 access to the transfer
+// bucket is attached to the role by ztictl for the duration of each transfer.
+var transferRoleActions = []string{
+	"ssm:UpdateInstanceInformation",
+	"ssmmessages:CreateControlChannel",
+	"ssmmessages:OpenControlChannel",
+}
+
+// iamCheckAPI is the subset of the IAM API used to check instance roles
+type iamCheckAPI interface {
+	GetInstanceProfile(ctx context.Context, params *iam.GetInstanceProfileInput, optFns ...func(*iam.Options)) (*iam.GetInstanceProfileOutput, error)
+	SimulatePrincipalPolicy(ctx context.Context, params *iam.SimulatePrincipalPolicyInput, optFns ...func(*iam.Options)) (*iam.SimulatePrincipalPolicyOutput, error)
+}
+
+// IAMCheckResult is whether one instance meets the IAM prerequisites of S3 transfers
+type IAMCheckResult struct {
+	InstanceID      string   `json:"instance_id"`
+	Status          string   `json:"status"`
+	InstanceProfile string   `json:"instance_profile,omitempty"`
+	Role            string   `json:"role,omitempty"`
+	MissingActions  []string `json:"missing_actions,omitempty"`
+	Error           string   `json:"error,omitempty"`
+}
+
+// Ready reports whether the instance passed every check
+func (r IAMCheckResult) Ready() bool {
+	return r.Status == IAMCheckReady
+}
+
+// CheckInstanceIAM checks the IAM setup that ValidateInstanceIAMSetup requires before a transfer, for each
+// instance in order: an instance profile with a role, and the role's SSM agent permissions, evaluated with
+// the IAM policy simulator. Nothing is changed.
+func (m *Manager) CheckInstanceIAM(ctx context.Context, region string, instanceIDs []string) ([]IAMCheckResult, error) {
+	clients, err := m.clientPool.GetClients(ctx, region)
+	if err != nil {
+		return nil, errors.NewAWSError("failed to get AWS clients", err)
+	}
+	return checkInstanceIAM(ctx, clients.EC2Client, clients.IAMClient, instanceIDs)
+}
+
+// checkInstanceIAM checks instances against the EC2 and IAM APIs. Instance profiles and roles shared by
+// several instances are looked up once.
+func checkInstanceIAM(ctx context.Context, ec2API ec2.DescribeInstancesAPIClient, iamAPI iamCheckAPI, instanceIDs []string) ([]IAMCheckResult, error) {
+	profiles, err := describeInstanceProfiles(ctx, ec2API, instanceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	type profileRole struct {
+		role *iamtypes.Role
+		err  error
+	}
+	roles := make(map[string]profileRole)
+	checked := make(map[string]IAMCheckResult)
+
+	results := make([]IAMCheckResult, 0, len(instanceIDs))
+	for _, instanceID := range instanceIDs {
+		result := IAMCheckResult{InstanceID: instanceID}
+		profileARN, found := profiles[instanceID]
+		if !found {
+			result.Status = IAMCheckError
+			result.Error = "instance not found"
+			results = append(results, result)
+			continue
+		}
+		if profileARN == "" {
+			result.Status = IAMCheckNoInstanceProfile
+			result.Error = "no IAM instance profile is attached"
+			results = append(results, result)
+			continue
+		}
+
+		profileName, err := instanceProfileName(profileARN)
+		if err != nil {
+			result.Status = IAMCheckError
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.InstanceProfile = profileName
+
+		lookup, done := roles[profileName]
+		if !done {
+			lookup.role, lookup.err = instanceProfileRole(ctx, iamAPI, profileName)
+			roles[profileName] = lookup
+		}
+		role := lookup.role
+		if lookup.err != nil {
+			result.Status = IAMCheckError
+			result.Error = lookup.err.Error()
+			results = append(results, result)
+			continue
+		}
+		if role == nil {
+			result.Status = IAMCheckNoRole
+			result.Error = fmt.Sprintf("instance profile %s has no role", profileName)
+			results = append(results, result)
+			continue
+		}
+
+		roleARN := aws.ToString(role.Arn)
+		permissions, done := checked[roleARN]
+		if !done {
+			permissions = checkRolePermissions(ctx, iamAPI, roleARN)
+			checked[roleARN] = permissions
+		}
+		result.Role = aws.ToString(role.RoleName)
+		result.Status = permissions.Status
+		result.MissingActions = permissions.MissingActions
+		result.Error = permissions.Error
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// describeInstanceProfiles returns the instance profile ARN of each instance found, empty when it has
+// none. The instance-id filter is used, so unknown IDs are left out instead of failing the call.
+func describeInstanceProfiles(ctx context.Context, api ec2.DescribeInstancesAPIClient, instanceIDs []string) (map[string]string, error) {
+	profiles := make(map[string]string, len(instanceIDs))
+	for start := 0; start < len(instanceIDs); start += maxInstanceIDFilterValues {
+		end := min(start+maxInstanceIDFilterValues, len(instanceIDs))
+		paginator := ec2.NewDescribeInstancesPaginator(api, &ec2.DescribeInstancesInput{
+			Filters: []ec2types.Filter{{Name: aws.String("instance-id"), Values: instanceIDs[start:end]}},
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, errors.NewAWSError("failed to describe instances", err)
+			}
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					profileARN := ""
+					if instance.IamInstanceProfile != nil {
+						profileARN = aws.ToString(instance.IamInstanceProfile.Arn)
+					}
+					profiles[aws.ToString(instance.InstanceId)] = profileARN
+				}
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// instanceProfileName extracts the instance profile name from its ARN, dropping any path
+func instanceProfileName(profileARN string) (string, error) {
+	arnParts := strings.Split(profileARN, "/")
+	if len(arnParts) < 2 || arnParts[len(arnParts)-1] == "" {
+		return "", fmt.Errorf("invalid instance profile ARN format")
+	}
+	return arnParts[len(arnParts)-1], nil
+}
+
+// instanceProfileRole returns the role of an instance profile; nil when it has none
+func instanceProfileRole(ctx context.Context, api iamCheckAPI, profileName string) (*iamtypes.Role, error) {
+	output, err := api.GetInstanceProfile(ctx, &iam.GetInstanceProfileInput{InstanceProfileName: aws.String(profileName)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance profile %s: %w", profileName, err)
+	}
+	if output.InstanceProfile == nil || len(output.InstanceProfile.Roles) == 0 {
+		return nil, nil
+	}
+	return &output.InstanceProfile.Roles[0], nil
+}
+
+// checkRolePermissions simulates transferRoleActions for a role. The result is unverified when the
+// simulation itself is not allowed, since the role may still be set up correctly.
+func checkRolePermissions(ctx context.Context, api iamCheckAPI, roleARN string) IAMCheckResult {
+	output, err := api.SimulatePrincipalPolicy(ctx, &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(roleARN),
+		ActionNames:     transferRoleActions,
+	})
+	if err != nil {
+		return IAMCheckResult{Status: IAMCheckUnverified, Error: fmt.Sprintf("could not simulate the role's permissions: %v", err)}
+	}
+
+	var missing []string
+	for _, evaluation := range output.EvaluationResults {
+		if evaluation.EvalDecision != iamtypes.PolicyEvaluationDecisionTypeAllowed {
+			missing = append(missing, aws.ToString(evaluation.EvalActionName))
+		}
+	}
+	if len(missing) > 0 {
+		return IAMCheckResult{Status: IAMCheckMissingPermissions, MissingActions: missing, Error: "the role is missing SSM agent permissions (see AmazonSSMManagedInstanceCore)"}
+	}
+	return IAMCheckResult{Status: IAMCheckReady}
+}
@@ -0,0 +1,171 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// fakeIAMCheckEC2 serves the instance profile ARN of each known instance
+type fakeIAMCheckEC2 struct {
+	profiles map[string]string // instance ID to instance profile ARN, empty for none
+}
+
+func (f *fakeIAMCheckEC2) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+	var instances []ec2types.Instance
+	for _, id := range params.Filters[0].Values {
+		profileARN, ok := f.profiles[id]
+		if !ok {
+			continue
+		}
+		instance := ec2types.Instance{InstanceId: aws.String(id)}
+		if profileARN != "" {
+			instance.IamInstanceProfile = &ec2types.IamInstanceProfile{Arn: aws.String(profileARN)}
+		}
+		instances = append(instances, instance)
+	}
+	return &ec2.DescribeInstancesOutput{Reservations: []ec2types.Reservation{{Instances: instances}}}, nil
+}
+
+// fakeIAMCheckIAM serves instance profile roles and the actions each role is allowed
+type fakeIAMCheckIAM struct {
+	roles         map[string]string          // instance profile name to role name, empty for none
+	allowed       map[string]map[string]bool // role ARN to allowed actions
+	simulateError error
+	profileCalls  int
+	simulateCalls int
+}
+
+func (f *fakeIAMCheckIAM) GetInstanceProfile(ctx context.Context, params *iam.GetInstanceProfileInput, optFns ...func(*iam.Options)) (*iam.GetInstanceProfileOutput, error) {
+	f.profileCalls++
+	roleName, ok := f.roles[aws.ToString(params.InstanceProfileName)]
+	if !ok {
+		return nil, fmt.Errorf("NoSuchEntity")
+	}
+	profile := &iamtypes.InstanceProfile{}
+	if roleName != "" {
+		profile.Roles = []iamtypes.Role{{RoleName: aws.String(roleName), Arn: aws.String("arn:aws:iam::123456789012:role/" + roleName)}}
+	}
+	return &iam.GetInstanceProfileOutput{InstanceProfile: profile}, nil
+}
+
+func (f *fakeIAMCheckIAM) SimulatePrincipalPolicy(ctx context.Context, params *iam.SimulatePrincipalPolicyInput, optFns ...func(*iam.Options)) (*iam.SimulatePrincipalPolicyOutput, error) {
+	f.simulateCalls++
+	if f.simulateError != nil {
+		return nil, f.simulateError
+	}
+	output := &iam.SimulatePrincipalPolicyOutput{}
+	for _, action := range params.ActionNames {
+		decision := iamtypes.PolicyEvaluationDecisionTypeImplicitDeny
+		if f.allowed[aws.ToString(params.PolicySourceArn)][action] {
+			decision = iamtypes.PolicyEvaluationDecisionTypeAllowed
+		}
+		output.EvaluationResults = append(output.EvaluationResults, iamtypes.EvaluationResult{EvalActionName: aws.String(action), EvalDecision: decision})
+	}
+	return output, nil
+}
+
+func TestCheckInstanceIAM(t *testing.T) {
+	allActions := map[string]bool{}
+	for _, action := range transferRoleActions {
+		allActions[action] = true
+	}
+
+	ec2API := &fakeIAMCheckEC2{profiles: map[string]string{
+		"i-ready1":     "arn:aws:iam::123456789012:instance-profile/app/web-profile",
+		"i-ready2":     "arn:aws:iam::123456789012:instance-profile/app/web-profile",
+		"i-noprofile":  "",
+		"i-norole":     "arn:aws:iam::123456789012:instance-profile/empty-profile",
+		"i-restricted": "arn:aws:iam::123456789012:instance-profile/batch-profile",
+		"i-gone":       "arn:aws:iam::123456789012:instance-profile/deleted-profile",
+	}}
+	iamAPI := &fakeIAMCheckIAM{
+		roles: map[string]string{"web-profile": "web-role", "empty-profile": "", "batch-profile": "batch-role"},
+		allowed: map[string]map[string]bool{
+			"arn:aws:iam::123456789012:role/web-role":   allActions,
+			"arn:aws:iam::123456789012:role/batch-role": {"ssm:UpdateInstanceInformation": true},
+		},
+	}
+
+	ids := []string{"i-ready1", "i-noprofile", "i-norole", "i-restricted", "i-gone", "i-missing", "i-ready2"}
+	results, err := checkInstanceIAM(context.Background(), ec2API, iamAPI, ids)
+	if err != nil {
+		t.Fatalf("checkInstanceIAM() error = %v", err)
+	}
+
+	want := map[string]string{
+		"i-ready1":     IAMCheckReady,
+		"i-noprofile":  IAMCheckNoInstanceProfile,
+		"i-norole":     IAMCheckNoRole,
+		"i-restricted": IAMCheckMissingPermissions,
+		"i-gone":       IAMCheckError,
+		"i-missing":    IAMCheckError,
+		"i-ready2":     IAMCheckReady,
+	}
+	if len(results) != len(ids) {
+		t.Fatalf("Expected %d results, got %+v", len(ids), results)
+	}
+	for i, result := range results {
+		if result.InstanceID != ids[i] {
+			t.Errorf("Result %d is for %s, expected %s: results must keep the target order", i, result.InstanceID, ids[i])
+		}
+		if result.Status != want[result.InstanceID] {
+			t.Errorf("%s: status = %s, want %s (%s)", result.InstanceID, result.Status, want[result.InstanceID], result.Error)
+		}
+	}
+
+	if results[0].InstanceProfile != "web-profile" || results[0].Role != "web-role" || !results[0].Ready() {
+		t.Errorf("Unexpected ready result %+v", results[0])
+	}
+	if !reflect.DeepEqual(results[3].MissingActions, []string{"ssmmessages:CreateControlChannel", "ssmmessages:OpenControlChannel"}) {
+		t.Errorf("Expected the denied actions to be listed, got %v", results[3].MissingActions)
+	}
+	if results[5].Error != "instance not found" {
+		t.Errorf("Expected an unknown instance to be reported as not found, got %+v", results[5])
+	}
+
+	// The shared instance profile and role are looked up once
+	if iamAPI.profileCalls != 4 || iamAPI.simulateCalls != 2 {
+		t.Errorf("Expected 4 instance profile lookups and 2 simulations, got %d and %d", iamAPI.profileCalls, iamAPI.simulateCalls)
+	}
+}
+
+func TestCheckInstanceIAMUnverified(t *testing.T) {
+	ec2API := &fakeIAMCheckEC2{profiles: map[string]string{"i-1": "arn:aws:iam::123456789012:instance-profile/web-profile"}}
+	iamAPI := &fakeIAMCheckIAM{roles: map[string]string{"web-profile": "web-role"}, simulateError: fmt.Errorf("AccessDenied")}
+
+	results, err := checkInstanceIAM(context.Background(), ec2API, iamAPI, []string{"i-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Status != IAMCheckUnverified || results[0].Role != "web-role" || results[0].Ready() {
+		t.Errorf("Expected the role permissions to be unverified, got %+v", results[0])
+	}
+}
+
+func TestInstanceProfileName(t *testing.T) {
+	tests := []struct {
+		arn     string
+		want    string
+		wantErr bool
+	}{
+		{arn: "arn:aws:iam::123456789012:instance-profile/web-profile", want: "web-profile"},
+		{arn: "arn:aws:iam::123456789012:instance-profile/teams/app/web-profile", want: "web-profile"},
+		{arn: "web-profile", wantErr: true},
+		{arn: "arn:aws:iam::123456789012:instance-profile/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := instanceProfileName(tt.arn)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("instanceProfileName(%q) = %q, %v; want %q, error %v", tt.arn, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
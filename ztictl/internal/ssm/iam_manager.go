@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"os"
 	"strconv"
-	"strings"
 	"time"
 
 	"ztictl/pkg/logging"
@@ -102,22 +101,21 @@ func (m *IAMManager) getInstanceProfileRole(ctx context.Context, instanceID stri
 	}
 
 	// Extract instance profile name from ARN
-	arnParts := strings.Split(*instance.IamInstanceProfile.Arn, "/")
-	if len(arnParts) < 2 {
-		return "", fmt.Errorf("invalid instance profile ARN format")
+	profileName, err := instanceProfileName(*instance.IamInstanceProfile.Arn)
+	if err != nil {
+		return "", err
 	}
-	instanceProfileName := arnParts[len(arnParts)-1]
 
 	// Get role name from instance profile
 	getProfileResult, err := m.iamClient.GetInstanceProfile(ctx, &iam.GetInstanceProfileInput{
-		InstanceProfileName: aws.String(instanceProfileName),
+		InstanceProfileName: aws.String(profileName),
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to get instance profile: %w", err)
 	}
 
 	if len(getProfileResult.InstanceProfile.Roles) == 0 {
-		return "", fmt.Errorf("no role found in instance profile %s", instanceProfileName)
+		return "", fmt.Errorf("no role found in instance profile %s", profileName)
 	}
 
 	roleName := *getProfileResult.InstanceProfile.Roles[0].RoleName
@@ -183,6 +181,7 @@ func (m *IAMManager) AttachS3Permissions(ctx context.Context, instanceID, region
 		PolicyName:     aws.String(policyName),
 		PolicyDocument: aws.String(policyDoc),
 		Description:    aws.String("Temporary S3 access for ztiaws SSM file transfer"),
+		Tags:           iamTags(defaultResourceTags()),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create S3 policy: %w", err)
@@ -203,16 +202,15 @@ func (m *IAMManager) AttachS3Permissions(ctx context.Context, instanceID, region
 
 	m.logger.Debug("Attached policy to role", "roleName", roleName)
 
-	// Wait for IAM propagation
-	m.logger.Debug("Waiting for IAM changes to propagate", "delay", IAMPropagationDelay)
-	time.Sleep(IAMPropagationDelay)
+	// Cleanup must still work after the caller's context is cancelled (e.g. --deadline expiry)
+	cleanupCtx := context.WithoutCancel(ctx)
 
 	// Return cleanup function
 	cleanupFunc := func() error {
 		m.logger.Debug("Cleaning up IAM policy", "policyARN", policyARN, "roleName", roleName)
 
 		// Detach policy from role
-		if _, err := m.iamClient.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+		if _, err := m.iamClient.DetachRolePolicy(cleanupCtx, &iam.DetachRolePolicyInput{
 			RoleName:  aws.String(roleName),
 			PolicyArn: aws.String(policyARN),
 		}); err != nil {
@@ -222,7 +220,7 @@ func (m *IAMManager) AttachS3Permissions(ctx context.Context, instanceID, region
 		}
 
 		// Delete the policy
-		if _, err := m.iamClient.DeletePolicy(ctx, &iam.DeletePolicyInput{
+		if _, err := m.iamClient.DeletePolicy(cleanupCtx, &iam.DeletePolicyInput{
 			PolicyArn: aws.String(policyARN),
 		}); err != nil {
 			m.logger.Warn("Failed to delete policy", "policyARN", policyARN, "error", err)
@@ -234,6 +232,13 @@ func (m *IAMManager) AttachS3Permissions(ctx context.Context, instanceID, region
 		return nil
 	}
 
+	// Wait for IAM propagation
+	m.logger.Debug("Waiting for IAM changes to propagate", "delay", IAMPropagationDelay)
+	if err := sleepWithContext(ctx, IAMPropagationDelay); err != nil {
+		_ = cleanupFunc() // #nosec G104 - cleanup operation
+		return nil, fmt.Errorf("interrupted while waiting for IAM propagation: %w", err)
+	}
+
 	return cleanupFunc, nil
 }
 
@@ -0,0 +1,170 @@
+package ssm
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"ztictl/pkg/errors"
+	"ztictl/pkg/logging"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+const (
+	// inventoryInstanceInformation is the inventory type holding OS and agent details
+	inventoryInstanceInformation = "AWS:InstanceInformation"
+	// inventoryApplication is the inventory type listing installed software
+	inventoryApplication = "AWS:Application"
+	// maxInventoryFilterValues is the GetInventory limit on values per filter
+	maxInventoryFilterValues = 40
+	// inventoryApplicationWorkers caps concurrent ListInventoryEntries calls
+	inventoryApplicationWorkers = 5
+)
+
+// inventoryAPI is the subset of the SSM API used to read inventory data
+type inventoryAPI interface {
+	ssm.GetInventoryAPIClient
+	ListInventoryEntries(ctx context.Context, params *ssm.ListInventoryEntriesInput, optFns ...func(*ssm.Options)) (*ssm.ListInventoryEntriesOutput, error)
+}
+
+// InstanceInventory is the SSM Inventory summary of one managed instance
+type InstanceInventory struct {
+	InstanceID      string `json:"instance_id"`
+	ComputerName    string `json:"computer_name,omitempty"`
+	PlatformType    string `json:"platform_type,omitempty"`
+	PlatformName    string `json:"platform_name,omitempty"`
+	PlatformVersion string `json:"platform_version,omitempty"`
+	AgentVersion    string `json:"agent_version,omitempty"`
+	IPAddress       string `json:"ip_address,omitempty"`
+	// Applications is the number of AWS:Application entries; nil when they could not be read
+	Applications *int `json:"application_count,omitempty"`
+}
+
+// GetInventory returns the inventory of managed instances in a region, sorted by instance ID. With
+// instanceIDs, only those instances are returned; instances without inventory data are left out.
+func (m *Manager) GetInventory(ctx context.Context, region string, instanceIDs []string) ([]InstanceInventory, error) {
+	ssmClient, err := m.clientPool.GetSSMClient(ctx, region)
+	if err != nil {
+		return nil, errors.NewAWSError("failed to get SSM client", err)
+	}
+
+	inventory, err := fetchInstanceInformation(ctx, ssmClient, instanceIDs)
+	if err != nil {
+		return nil, err
+	}
+	countApplications(ctx, ssmClient, inventory)
+	return inventory, nil
+}
+
+// fetchInstanceInformation reads the AWS:InstanceInformation inventory type, filtered to instanceIDs in
+// batches of the API's filter limit when given. Terminated instances are skipped.
+func fetchInstanceInformation(ctx context.Context, api inventoryAPI, instanceIDs []string) ([]InstanceInventory, error) {
+	var filterBatches [][]ssmtypes.InventoryFilter
+	if instanceIDs == nil {
+		filterBatches = [][]ssmtypes.InventoryFilter{nil}
+	}
+	for start := 0; start < len(instanceIDs); start += maxInventoryFilterValues {
+		end := min(start+maxInventoryFilterValues, len(instanceIDs))
+		filterBatches = append(filterBatches, []ssmtypes.InventoryFilter{{
+			Key:    aws.String(inventoryInstanceInformation + ".InstanceId"),
+			Values: instanceIDs[start:end],
+			Type:   ssmtypes.InventoryQueryOperatorTypeEqual,
+		}})
+	}
+
+	var inventory []InstanceInventory
+	for _, filters := range filterBatches {
+		paginator := ssm.NewGetInventoryPaginator(api, &ssm.GetInventoryInput{
+			Filters:          filters,
+			ResultAttributes: []ssmtypes.ResultAttribute{{TypeName: aws.String(inventoryInstanceInformation)}},
+		})
+		for paginator.HasMorePages() {
+			resp, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, errors.NewSSMError("failed to get inventory", err)
+			}
+			for _, entity := range resp.Entities {
+				if instance, ok := instanceInventory(entity); ok {
+					inventory = append(inventory, instance)
+				}
+			}
+		}
+	}
+
+	sort.Slice(inventory, func(i, j int) bool { return inventory[i].InstanceID < inventory[j].InstanceID })
+	return inventory, nil
+}
+
+// instanceInventory converts an inventory entity to an InstanceInventory. It returns false for
+// entities without instance information and for terminated instances.
+func instanceInventory(entity ssmtypes.InventoryResultEntity) (InstanceInventory, bool) {
+	item, ok := entity.Data[inventoryInstanceInformation]
+	if !ok || len(item.Content) == 0 {
+		return InstanceInventory{}, false
+	}
+	content := item.Content[0]
+	if content["InstanceStatus"] == "Terminated" {
+		return InstanceInventory{}, false
+	}
+
+	instanceID := content["InstanceId"]
+	if instanceID == "" {
+		instanceID = aws.ToString(entity.Id)
+	}
+	return InstanceInventory{
+		InstanceID:      instanceID,
+		ComputerName:    content["ComputerName"],
+		PlatformType:    content["PlatformType"],
+		PlatformName:    content["PlatformName"],
+		PlatformVersion: content["PlatformVersion"],
+		AgentVersion:    content["AgentVersion"],
+		IPAddress:       content["IpAddress"],
+	}, true
+}
+
+// countApplications fills in the AWS:Application entry count of each instance. An instance whose
+// entries cannot be listed keeps a nil count, so one failure does not hide the rest of the report.
+func countApplications(ctx context.Context, api inventoryAPI, inventory []InstanceInventory) {
+	semaphore := make(chan struct{}, inventoryApplicationWorkers)
+	var wg sync.WaitGroup
+	for i := range inventory {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(instance *InstanceInventory) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			count, err := countInventoryEntries(ctx, api, instance.InstanceID, inventoryApplication)
+			if err != nil {
+				logging.LogWarn("Could not list installed applications of %s: %v", instance.InstanceID, err)
+				return
+			}
+			instance.Applications = &count
+		}(&inventory[i])
+	}
+	wg.Wait()
+}
+
+// countInventoryEntries counts the entries of one inventory type on an instance, following pagination
+func countInventoryEntries(ctx context.Context, api inventoryAPI, instanceID, typeName string) (int, error) {
+	count := 0
+	input := &ssm.ListInventoryEntriesInput{
+		InstanceId: aws.String(instanceID),
+		TypeName:   aws.String(typeName),
+		MaxResults: aws.Int32(50),
+	}
+	for {
+		resp, err := api.ListInventoryEntries(ctx, input)
+		if err != nil {
+			return 0, errors.NewSSMError("failed to list inventory entries", err)
+		}
+		count += len(resp.Entries)
+		if aws.ToString(resp.NextToken) == "" {
+			return count, nil
+		}
+		input.NextToken = resp.NextToken
+	}
+}
@@ -0,0 +1,136 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// fakeInventoryAPI serves instance information and application entries from maps
+type fakeInventoryAPI struct {
+	mu           sync.Mutex
+	instances    map[string]map[string]string // instance ID to AWS:InstanceInformation content
+	applications map[string]int               // instance ID to number of AWS:Application entries
+	failing      map[string]bool              // instances whose entries cannot be listed
+	filters      [][]string                   // instance ID filter values of each GetInventory call
+}
+
+func (f *fakeInventoryAPI) GetInventory(ctx context.Context, params *ssm.GetInventoryInput, optFns ...func(*ssm.Options)) (*ssm.GetInventoryOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	wanted := map[string]bool{}
+	for _, filter := range params.Filters {
+		f.filters = append(f.filters, filter.Values)
+		for _, value := range filter.Values {
+			wanted[value] = true
+		}
+	}
+
+	output := &ssm.GetInventoryOutput{}
+	for id, content := range f.instances {
+		if len(params.Filters) > 0 && !wanted[id] {
+			continue
+		}
+		output.Entities = append(output.Entities, ssmtypes.InventoryResultEntity{
+			Id:   aws.String(id),
+			Data: map[string]ssmtypes.InventoryResultItem{inventoryInstanceInformation: {Content: []map[string]string{content}}},
+		})
+	}
+	return output, nil
+}
+
+func (f *fakeInventoryAPI) ListInventoryEntries(ctx context.Context, params *ssm.ListInventoryEntriesInput, optFns ...func(*ssm.Options)) (*ssm.ListInventoryEntriesOutput, error) {
+	id := aws.ToString(params.InstanceId)
+	if f.failing[id] {
+		return nil, fmt.Errorf("access denied")
+	}
+
+	// Serve two entries per page to exercise pagination
+	start := 0
+	if params.NextToken != nil {
+		_, _ = fmt.Sscan(*params.NextToken, &start)
+	}
+	end := min(start+2, f.applications[id])
+	output := &ssm.ListInventoryEntriesOutput{}
+	for i := start; i < end; i++ {
+		output.Entries = append(output.Entries, map[string]string{"Name": fmt.Sprintf("app-%d", i)})
+	}
+	if end < f.applications[id] {
+		output.NextToken = aws.String(fmt.Sprint(end))
+	}
+	return output, nil
+}
+
+func newFakeInventoryAPI() *fakeInventoryAPI {
+	return &fakeInventoryAPI{
+		instances: map[string]map[string]string{
+			"i-linux":      {"InstanceId": "i-linux", "PlatformType": "Linux", "PlatformName": "Amazon Linux", "PlatformVersion": "2023", "AgentVersion": "3.3.0.0", "IpAddress": "10.0.0.5"},
+			"i-windows":    {"InstanceId": "i-windows", "PlatformType": "Windows", "PlatformName": "Microsoft Windows Server 2022 Datacenter", "PlatformVersion": "10.0.20348"},
+			"i-terminated": {"InstanceId": "i-terminated", "InstanceStatus": "Terminated"},
+		},
+		applications: map[string]int{"i-linux": 5, "i-windows": 0},
+		failing:      map[string]bool{},
+	}
+}
+
+func TestFetchInstanceInformation(t *testing.T) {
+	api := newFakeInventoryAPI()
+
+	inventory, err := fetchInstanceInformation(context.Background(), api, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(inventory) != 2 || inventory[0].InstanceID != "i-linux" || inventory[1].InstanceID != "i-windows" {
+		t.Fatalf("Expected the two live instances sorted by ID, got %+v", inventory)
+	}
+	if inventory[0].PlatformName != "Amazon Linux" || inventory[0].PlatformVersion != "2023" || inventory[0].IPAddress != "10.0.0.5" {
+		t.Errorf("Unexpected instance information: %+v", inventory[0])
+	}
+	if len(api.filters) != 0 {
+		t.Errorf("Expected no instance ID filter without instance IDs, got %v", api.filters)
+	}
+}
+
+func TestFetchInstanceInformationFiltered(t *testing.T) {
+	api := newFakeInventoryAPI()
+	ids := []string{"i-windows"}
+	for i := 0; i < maxInventoryFilterValues; i++ {
+		ids = append(ids, fmt.Sprintf("i-missing-%02d", i))
+	}
+
+	inventory, err := fetchInstanceInformation(context.Background(), api, ids)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(inventory) != 1 || inventory[0].InstanceID != "i-windows" {
+		t.Errorf("Expected only i-windows, got %+v", inventory)
+	}
+	if len(api.filters) != 2 || len(api.filters[0]) != maxInventoryFilterValues || len(api.filters[1]) != 1 {
+		t.Errorf("Expected instance IDs to be split into filter batches of %d, got %v", maxInventoryFilterValues, api.filters)
+	}
+
+	if inventory, err := fetchInstanceInformation(context.Background(), api, []string{}); err != nil || len(inventory) != 0 {
+		t.Errorf("Expected no inventory for an empty instance list, got %+v (%v)", inventory, err)
+	}
+}
+
+func TestCountApplications(t *testing.T) {
+	api := newFakeInventoryAPI()
+	api.failing["i-windows"] = true
+	inventory := []InstanceInventory{{InstanceID: "i-linux"}, {InstanceID: "i-windows"}}
+
+	countApplications(context.Background(), api, inventory)
+
+	if inventory[0].Applications == nil || *inventory[0].Applications != 5 {
+		t.Errorf("Expected 5 applications across pages, got %v", inventory[0].Applications)
+	}
+	if inventory[1].Applications != nil {
+		t.Errorf("Expected no count when entries cannot be listed, got %d", *inventory[1].Applications)
+	}
+}
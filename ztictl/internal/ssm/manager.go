@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -15,13 +16,17 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"text/template"
 	"time"
+	"unicode/utf8"
 
 	appconfig "ztictl/internal/config"
 	"ztictl/internal/interactive"
+	"ztictl/internal/metrics"
 	"ztictl/internal/platform"
 	awsservice "ztictl/pkg/aws"
 	"ztictl/pkg/errors"
+	"ztictl/pkg/format"
 	"ztictl/pkg/logging"
 	"ztictl/pkg/security"
 
@@ -49,6 +54,9 @@ type Manager struct {
 	platformDetector   *platform.Detector
 	builderManager     *platform.BuilderManager
 	clientPool         *ClientPool
+	parameterStore     *ParameterStore
+	reconcileOnce      sync.Once
+	transferBuckets    bucketCache
 }
 
 // CommandResult represents the result of a command execution
@@ -60,6 +68,47 @@ type CommandResult struct {
 	Output        string         `json:"output"`
 	ErrorOutput   string         `json:"error_output,omitempty"`
 	ExecutionTime *time.Duration `json:"execution_time,omitempty"`
+
+	// OutputTruncated and ErrorOutputTruncated report that SSM cut the inline output at its limit
+	OutputTruncated      bool `json:"output_truncated,omitempty"`
+	ErrorOutputTruncated bool `json:"error_output_truncated,omitempty"`
+}
+
+// SSM returns at most this many characters of inline output from GetCommandInvocation
+const (
+	MaxInlineOutputChars      = 24000
+	MaxInlineErrorOutputChars = 8000
+)
+
+// setOutput stores the inline output of an invocation, recording whether SSM truncated either stream.
+// The check runs on the raw content, before the exit code line is removed.
+func (r *CommandResult) setOutput(stdout, stderr string) {
+	r.Output = removeExitCodeLine(stdout)
+	r.ErrorOutput = stderr
+	r.OutputTruncated = utf8.RuneCountInString(stdout) >= MaxInlineOutputChars
+	r.ErrorOutputTruncated = utf8.RuneCountInString(stderr) >= MaxInlineErrorOutputChars
+}
+
+// CommandStatusTimedOut is the status of a CommandResult whose command did not finish before ztictl stopped waiting
+const CommandStatusTimedOut = "TimedOut"
+
+// TimedOut reports whether ztictl stopped waiting before the command finished.
+// Output and ErrorOutput then hold whatever the instance had produced so far.
+func (r *CommandResult) TimedOut() bool {
+	return r != nil && r.Status == CommandStatusTimedOut
+}
+
+// Command completion polling settings (variables so tests can shorten them)
+var (
+	commandCompletionTimeout = 5 * time.Minute
+	commandPollInterval      = 2 * time.Second
+)
+
+// commandInvocationAPI is the subset of the SSM client used while waiting for a command
+type commandInvocationAPI interface {
+	ListCommandInvocations(ctx context.Context, params *ssm.ListCommandInvocationsInput, optFns ...func(*ssm.Options)) (*ssm.ListCommandInvocationsOutput, error)
+	GetCommandInvocation(ctx context.Context, params *ssm.GetCommandInvocationInput, optFns ...func(*ssm.Options)) (*ssm.GetCommandInvocationOutput, error)
+	CancelCommand(ctx context.Context, params *ssm.CancelCommandInput, optFns ...func(*ssm.Options)) (*ssm.CancelCommandOutput, error)
 }
 
 // ListFilters represents filters for listing instances
@@ -68,8 +117,23 @@ type ListFilters struct {
 	Tags   string `json:"tags,omitempty"`   // Format: key1=value1,key2=value2
 	Status string `json:"status,omitempty"` // Instance state
 	Name   string `json:"name,omitempty"`   // Name pattern
+	// NamePattern matches the Name tag exactly, with * and ? wildcards (e.g. web-*)
+	NamePattern string `json:"name_pattern,omitempty"`
+	// VPC and Subnet keep instances in one of these comma-separated VPC or subnet IDs
+	VPC    string `json:"vpc,omitempty"`
+	Subnet string `json:"subnet,omitempty"`
+	// InstanceIDs keeps only these comma-separated instance IDs
+	InstanceIDs string `json:"instance_ids,omitempty"`
 }
 
+// File transfer methods and statuses
+const (
+	TransferMethodDirect    = "direct"
+	TransferMethodS3        = "s3"
+	TransferStatusCompleted = "completed"
+	TransferStatusFailed    = "failed"
+)
+
 // FileTransferOperation represents a file transfer operation
 type FileTransferOperation struct {
 	InstanceID   string     `json:"instance_id"`
@@ -77,13 +141,26 @@ type FileTransferOperation struct {
 	LocalPath    string     `json:"local_path"`
 	RemotePath   string     `json:"remote_path"`
 	Size         int64      `json:"size"`
-	Method       string     `json:"method"` // "direct" or "s3"
+	Method       string     `json:"method"` // TransferMethodDirect or TransferMethodS3
 	Status       string     `json:"status"`
 	StartTime    *time.Time `json:"start_time,omitempty"`
 	EndTime      *time.Time `json:"end_time,omitempty"`
 	ErrorMessage string     `json:"error_message,omitempty"`
 }
 
+// Stats summarizes a completed transfer for display, e.g. "1.4 GiB in 1m23s".
+// Parts that are unknown (no size, no end time) are left out.
+func (op FileTransferOperation) Stats() string {
+	var parts []string
+	if op.Size > 0 {
+		parts = append(parts, format.Bytes(op.Size))
+	}
+	if op.StartTime != nil && op.EndTime != nil {
+		parts = append(parts, format.Duration(op.EndTime.Sub(*op.StartTime)))
+	}
+	return strings.Join(parts, " in ")
+}
+
 // NewManager creates a new SSM manager
 func NewManager(logger *logging.Logger) *Manager {
 	// Note: Platform detector and builder manager are not initialized here.
@@ -95,6 +172,7 @@ func NewManager(logger *logging.Logger) *Manager {
 		logger:          logger,
 		clientPool:      clientPool,
 		instanceService: awsservice.NewInstanceService(clientPoolAdapter, logger),
+		parameterStore:  NewParameterStore(clientPool),
 	}
 }
 
@@ -115,6 +193,9 @@ func (m *Manager) StartSession(ctx context.Context, instanceIdentifier, region s
 	if err := validateAWSRegion(region); err != nil {
 		return fmt.Errorf("invalid region: %w", err)
 	}
+	if err := CheckSessionManagerPlugin(); err != nil {
+		return err
+	}
 
 	// Use AWS CLI for session manager (Go SDK doesn't support interactive sessions)
 	// Build command with validated and sanitized parameters
@@ -180,6 +261,11 @@ func (m *Manager) GetInstanceService() *awsservice.InstanceService {
 	return m.instanceService
 }
 
+// GetParameterStore returns the Parameter Store reader sharing this manager's client pool
+func (m *Manager) GetParameterStore() *ParameterStore {
+	return m.parameterStore
+}
+
 // getAWSCommand returns the platform-appropriate AWS CLI command name
 func getAWSCommand() string {
 	if runtime.GOOS == "windows" {
@@ -224,18 +310,70 @@ func (m *Manager) ListInstances(ctx context.Context, region string, filters *Lis
 	var awsFilters *awsservice.ListFilters
 	if filters != nil {
 		awsFilters = &awsservice.ListFilters{
-			Tag:    filters.Tag,
-			Tags:   filters.Tags,
-			Status: filters.Status,
-			Name:   filters.Name,
+			Tag:         filters.Tag,
+			Tags:        filters.Tags,
+			Status:      filters.Status,
+			Name:        filters.Name,
+			NamePattern: filters.NamePattern,
+			VPC:         filters.VPC,
+			Subnet:      filters.Subnet,
+			InstanceIDs: filters.InstanceIDs,
 		}
 	}
 
 	return m.instanceService.ListInstances(ctx, region, awsFilters)
 }
 
+// ExecOptions controls how a command is executed on an instance
+type ExecOptions struct {
+	// Sudo runs the command with elevated privileges (Linux only)
+	Sudo bool
+
+	// RunAs runs the command as this OS user, with their home directory (Linux only); it replaces Sudo
+	RunAs string
+
+	// CancelOnTimeout cancels the invocation on the instance when ztictl stops waiting for it
+	CancelOnTimeout bool
+
+	// Env is exported on the instance before the command runs (inside sudo when Sudo or RunAs is set).
+	// Values are never logged.
+	Env map[string]string
+
+	// Platform forces the SSM document and command wrapper instead of detecting them; empty auto-detects
+	Platform platform.Platform
+
+	// NoWrap sends the command exactly as given, without the exit code wrapper, Env, Sudo or RunAs.
+	// The exit code then comes only from the SSM response code.
+	NoWrap bool
+
+	// Steps, when set, are run in order in place of the command, which then only names the run in logs
+	// and results. A marker line before each step attributes its output; the first failing step ends
+	// the sequence unless ContinueOnStepError is set.
+	Steps               []string
+	ContinueOnStepError bool
+
+	// Timeout limits how long the command may run on each instance: ztictl stops waiting after it and
+	// SSM stops the command. 0 waits up to 5 minutes and keeps the document's execution timeout.
+	Timeout time.Duration
+
+	// CommandTemplate wraps the command, or the Steps sequence, in an organization-wide preamble and
+	// epilogue inside the Env, Sudo or RunAs and exit code wrappers; nil sends it unchanged. NoWrap skips it.
+	CommandTemplate *template.Template
+
+	// FallbackParallel bounds how many instances of a batch rejected for its SSM document are sent to at
+	// once, each with its own SendCommand call; 0 sends them one at a time. FallbackWait, when set, is
+	// called before each of those sends so the caller's rate limit applies to them.
+	FallbackParallel int
+	FallbackWait     func(ctx context.Context) error
+}
+
 // ExecuteCommand executes a command on an instance via SSM
 func (m *Manager) ExecuteCommand(ctx context.Context, instanceIdentifier, region, command, comment string) (*CommandResult, error) {
+	return m.ExecuteCommandWithOptions(ctx, instanceIdentifier, region, command, comment, ExecOptions{})
+}
+
+// ExecuteCommandWithOptions executes a command on an instance via SSM with execution options
+func (m *Manager) ExecuteCommandWithOptions(ctx context.Context, instanceIdentifier, region, command, comment string, opts ExecOptions) (*CommandResult, error) {
 	// Resolve instance identifier
 	instanceID, err := m.resolveInstanceIdentifier(ctx, instanceIdentifier, region)
 	if err != nil {
@@ -250,7 +388,7 @@ func (m *Manager) ExecuteCommand(ctx context.Context, instanceIdentifier, region
 	}
 
 	// Get command builder for the instance platform
-	builder, err := m.builderManager.GetBuilder(ctx, instanceID)
+	builder, err := m.commandBuilder(ctx, instanceID, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get command builder: %w", err)
 	}
@@ -268,21 +406,11 @@ func (m *Manager) ExecuteCommand(ctx context.Context, instanceIdentifier, region
 
 	startTime := time.Now()
 
-	// Get the appropriate SSM document for the platform
-	documentName := builder.GetSSMDocument()
-
-	// Build the command with platform-specific wrapper
-	wrappedCommand := builder.BuildExecCommand(command)
-
-	sendResp, err := ssmClient.SendCommand(ctx, &ssm.SendCommandInput{
-		DocumentName: aws.String(documentName),
-		InstanceIds:  []string{instanceID},
-		Parameters: map[string][]string{
-			"commands": {wrappedCommand},
-		},
-		Comment: aws.String(comment),
-	})
+	// Send with the SSM document and wrapper for the platform, falling back to the other platform's
+	// document when auto-detection picked one the instance rejects
+	sendResp, err := m.sendCommand(ctx, ssmClient, instanceID, builder, command, comment, opts)
 	if err != nil {
+		metrics.Record("exec", region, time.Since(startTime), false)
 		return nil, errors.NewSSMError("failed to send command", err)
 	}
 
@@ -290,20 +418,69 @@ func (m *Manager) ExecuteCommand(ctx context.Context, instanceIdentifier, region
 	m.logger.Debug("Command sent with ID", "commandID", commandID)
 
 	// Wait for command completion
-	result, err := m.waitForCommandCompletion(ctx, ssmClient, commandID, instanceID)
-	if err != nil {
+	result, err := m.waitForCommandCompletion(ctx, ssmClient, commandID, instanceID, opts)
+	if result == nil {
+		metrics.Record("exec", region, time.Since(startTime), false)
 		return nil, err
 	}
 
 	executionTime := time.Since(startTime)
+	metrics.Record("exec", region, executionTime, err == nil && result.Status == "Success")
 	result.ExecutionTime = &executionTime
 	result.Command = command
 
-	return result, nil
+	// A timed-out result carries partial output alongside the timeout error
+	return result, err
+}
+
+// wrapCommand builds the opts.Steps sequence, renders it or the command in opts.CommandTemplate, then applies
+// the environment, sudo or run-as, and platform exec wrappers. It returns false when sudo or run-as was
+// requested but the platform has no equivalent.
+func wrapCommand(builder platform.CommandBuilder, command string, opts ExecOptions) (string, bool, error) {
+	if len(opts.Steps) > 0 {
+		command = builder.BuildSequenceCommand(opts.Steps, opts.ContinueOnStepError)
+	}
+	if opts.NoWrap {
+		return command, true, nil
+	}
+
+	execCommand := command
+	if opts.CommandTemplate != nil {
+		rendered, err := platform.RenderCommandTemplate(opts.CommandTemplate, builder, execCommand)
+		if err != nil {
+			return "", false, err
+		}
+		execCommand = rendered
+	}
+	if len(opts.Env) > 0 {
+		execCommand = builder.BuildEnvCommand(opts.Env, execCommand)
+	}
+
+	applied := true
+	switch {
+	case opts.RunAs != "":
+		execCommand, applied = builder.BuildRunAsCommand(opts.RunAs, execCommand)
+	case opts.Sudo:
+		execCommand, applied = builder.BuildSudoCommand(execCommand)
+	}
+	return builder.BuildExecCommand(execCommand), applied, nil
+}
+
+// unwrappedWarning is logged when the platform cannot apply opts.Sudo or opts.RunAs
+func unwrappedWarning(opts ExecOptions) string {
+	if opts.RunAs != "" {
+		return "Running as another user is not supported on this platform, running command as the SSM agent user"
+	}
+	return "Sudo is not supported on this platform, running command unchanged"
 }
 
 // UploadFile uploads a file to an instance via SSM
 func (m *Manager) UploadFile(ctx context.Context, instanceIdentifier, region, localPath, remotePath string) error {
+	return m.UploadFileWithOptions(ctx, instanceIdentifier, region, localPath, remotePath, UploadOptions{})
+}
+
+// UploadFileWithOptions uploads a file to an instance via SSM, applying upload options
+func (m *Manager) UploadFileWithOptions(ctx context.Context, instanceIdentifier, region, localPath, remotePath string, opts UploadOptions) error {
 	// Resolve instance identifier
 	instanceID, err := m.resolveInstanceIdentifier(ctx, instanceIdentifier, region)
 	if err != nil {
@@ -323,45 +500,196 @@ func (m *Manager) UploadFile(ctx context.Context, instanceIdentifier, region, lo
 
 	cfg := appconfig.Get()
 
-	m.logger.Info("Uploading file to instance", "instanceID", instanceID, "localPath", localPath, "remotePath", remotePath, "size", fileInfo.Size())
+	m.logger.Info("Uploading file to instance", "instanceID", instanceID, "localPath", localPath, "remotePath", remotePath, "size", format.Bytes(fileInfo.Size()))
 
 	// Choose transfer method based on file size
+	startTime := time.Now()
 	if fileInfo.Size() < cfg.System.FileSizeThreshold {
-		return m.uploadFileSmall(ctx, instanceID, region, localPath, remotePath)
+		err = m.uploadFileSmall(ctx, instanceID, region, localPath, remotePath)
 	} else {
-		return m.uploadFileLarge(ctx, instanceID, region, localPath, remotePath)
+		err = m.uploadFileLarge(ctx, instanceID, region, localPath, remotePath, opts)
+	}
+	if err == nil && opts.PreserveMode {
+		err = m.applyFileMode(ctx, instanceID, region, remotePath, fileInfo.Mode())
+	}
+	recordTransfer("upload", region, fileInfo.Size(), startTime, err)
+	return err
+}
+
+// recordTransfer reports a file transfer's outcome, duration and size to the metrics sink
+func recordTransfer(direction, region string, size int64, startTime time.Time, err error) {
+	metrics.Record("transfer."+direction, region, time.Since(startTime), err == nil)
+	if err == nil {
+		metrics.Count("transfer."+direction+".bytes", region, size)
+	}
+}
+
+// StdoutPath is the local path that makes DownloadFile write the file content to stdout
+const StdoutPath = "-"
+
+// downloadStdout is where downloads to StdoutPath are written; tests replace it
+var downloadStdout io.Writer = os.Stdout
+
+// validateDownloadPath checks that a download target is within safe boundaries, allowing StdoutPath
+func validateDownloadPath(localPath string) error {
+	if localPath == StdoutPath {
+		return nil
+	}
+	return security.ValidateFilePathWithWorkingDir(localPath)
+}
+
+// writeDownloadedContent writes downloaded content to the local file, or to stdout for StdoutPath
+func writeDownloadedContent(localPath string, content []byte) error {
+	if localPath == StdoutPath {
+		if _, err := downloadStdout.Write(content); err != nil {
+			return fmt.Errorf("failed to write to stdout: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(localPath, content, 0600); err != nil {
+		return fmt.Errorf("failed to write local file: %w", err)
 	}
+	return nil
 }
 
-// DownloadFile downloads a file from an instance via SSM
+// DownloadFile downloads a file from an instance via SSM.
+// A localPath of StdoutPath ("-") writes the content to stdout instead of a file.
 func (m *Manager) DownloadFile(ctx context.Context, instanceIdentifier, region, remotePath, localPath string) error {
+	_, err := m.DownloadFileOperation(ctx, instanceIdentifier, region, remotePath, localPath)
+	return err
+}
+
+// DownloadFileOperation downloads a file like DownloadFile and returns the operation with its size,
+// method and timing. A failed operation has the failed status and the error as its message.
+func (m *Manager) DownloadFileOperation(ctx context.Context, instanceIdentifier, region, remotePath, localPath string) (FileTransferOperation, error) {
+	op := FileTransferOperation{InstanceID: instanceIdentifier, Region: region, LocalPath: localPath, RemotePath: remotePath}
+	startTime := time.Now()
+	op.StartTime = &startTime
+	err := m.downloadFile(ctx, &op)
+	endTime := time.Now()
+	op.EndTime = &endTime
+	if err != nil {
+		op.Status = TransferStatusFailed
+		op.ErrorMessage = err.Error()
+		return op, err
+	}
+	op.Status = TransferStatusCompleted
+	return op, nil
+}
+
+// downloadFile runs a download operation, filling in its instance ID, size and method as they are known
+func (m *Manager) downloadFile(ctx context.Context, op *FileTransferOperation) error {
 	// Resolve instance identifier
-	instanceID, err := m.resolveInstanceIdentifier(ctx, instanceIdentifier, region)
+	instanceID, err := m.resolveInstanceIdentifier(ctx, op.InstanceID, op.Region)
 	if err != nil {
 		return fmt.Errorf("failed to resolve instance: %w", err)
 	}
+	op.InstanceID = instanceID
 
 	// Validate that the local path is within safe boundaries
-	if err := security.ValidateFilePathWithWorkingDir(localPath); err != nil {
+	if err := validateDownloadPath(op.LocalPath); err != nil {
 		return fmt.Errorf("unsafe file path: %w", err)
 	}
 
-	m.logger.Info("Downloading file from instance", "instanceID", instanceID, "remotePath", remotePath, "localPath", localPath)
+	m.logger.Info("Downloading file from instance", "instanceID", instanceID, "remotePath", op.RemotePath, "localPath", op.LocalPath)
 
 	// First, get file size to determine transfer method
-	fileSize, err := m.getRemoteFileSize(ctx, instanceID, region, remotePath)
+	fileSize, err := m.getRemoteFileSize(ctx, instanceID, op.Region, op.RemotePath)
 	if err != nil {
 		return fmt.Errorf("failed to get remote file size: %w", err)
 	}
+	op.Size = fileSize
 
 	cfg := appconfig.Get()
 
 	// Choose transfer method based on file size
+	startTime := time.Now()
 	if fileSize < cfg.System.FileSizeThreshold {
-		return m.downloadFileSmall(ctx, instanceID, region, remotePath, localPath)
+		op.Method = TransferMethodDirect
+		err = m.downloadFileSmall(ctx, instanceID, op.Region, op.RemotePath, op.LocalPath)
 	} else {
-		return m.downloadFileLarge(ctx, instanceID, region, remotePath, localPath)
+		op.Method = TransferMethodS3
+		err = m.downloadFileLarge(ctx, instanceID, op.Region, op.RemotePath, op.LocalPath)
+	}
+	recordTransfer("download", op.Region, fileSize, startTime, err)
+	return err
+}
+
+// ListRemoteDirectory lists every regular file under a directory on an instance, recursively
+func (m *Manager) ListRemoteDirectory(ctx context.Context, instanceIdentifier, region, remoteDir string) ([]platform.RemoteFile, error) {
+	instanceID, err := m.resolveInstanceIdentifier(ctx, instanceIdentifier, region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve instance: %w", err)
+	}
+
+	if err := m.initializePlatformComponents(ctx, region); err != nil {
+		return nil, fmt.Errorf("failed to initialize platform components: %w", err)
+	}
+
+	builder, err := m.builderManager.GetBuilder(ctx, instanceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get command builder: %w", err)
+	}
+
+	result, err := m.ExecuteCommand(ctx, instanceID, region, builder.BuildDirectoryListCommand(remoteDir), "List directory via ztictl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote directory: %w", err)
+	}
+	if result.Status != "Success" {
+		return nil, fmt.Errorf("failed to list remote directory: %s", result.ErrorOutput)
+	}
+
+	files, err := builder.ParseDirectoryListing(result.Output)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, remoteDir)
 	}
+	return files, nil
+}
+
+// DownloadDirectory downloads files listed by ListRemoteDirectory into localDir, preserving
+// their relative paths. Each file uses the S3 transfer path when it exceeds the size threshold.
+// Failed files do not stop the download; they are reported together in the returned error.
+func (m *Manager) DownloadDirectory(ctx context.Context, instanceIdentifier, region, localDir string, files []platform.RemoteFile) error {
+	instanceID, err := m.resolveInstanceIdentifier(ctx, instanceIdentifier, region)
+	if err != nil {
+		return fmt.Errorf("failed to resolve instance: %w", err)
+	}
+
+	if err := security.ValidateFilePathWithWorkingDir(localDir); err != nil {
+		return fmt.Errorf("unsafe directory path: %w", err)
+	}
+
+	cfg := appconfig.Get()
+	var failed []string
+	for _, file := range files {
+		localPath := filepath.Join(localDir, filepath.FromSlash(file.Path))
+		if err := security.ValidateFilePath(localPath, localDir); err != nil {
+			return fmt.Errorf("unsafe remote file path %s: %w", file.Path, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(localPath), 0750); err != nil {
+			return fmt.Errorf("failed to create local directory: %w", err)
+		}
+
+		m.logger.Info("Downloading file from instance", "instanceID", instanceID, "remotePath", file.RemotePath, "localPath", localPath, "size", format.Bytes(file.Size))
+
+		startTime := time.Now()
+		if file.Size < cfg.System.FileSizeThreshold {
+			err = m.downloadFileSmall(ctx, instanceID, region, file.RemotePath, localPath)
+		} else {
+			err = m.downloadFileLarge(ctx, instanceID, region, file.RemotePath, localPath)
+		}
+		recordTransfer("download", region, file.Size, startTime, err)
+		if err != nil {
+			m.logger.Error("Failed to download file", "remotePath", file.RemotePath, "error", err)
+			failed = append(failed, file.Path)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to download %d of %d files: %s", len(failed), len(files), strings.Join(failed, ", "))
+	}
+	return nil
 }
 
 // ForwardPort sets up port forwarding through SSM
@@ -387,6 +715,9 @@ func (m *Manager) ForwardPort(ctx context.Context, instanceIdentifier, region st
 	if err := validatePortNumber(remotePort); err != nil {
 		return fmt.Errorf("invalid remote port: %w", err)
 	}
+	if err := CheckSessionManagerPlugin(); err != nil {
+		return err
+	}
 
 	// Use AWS CLI for port forwarding (Go SDK doesn't support this directly)
 	// Build command with validated and sanitized parameters
@@ -483,20 +814,26 @@ func (m *Manager) ListInstanceStatuses(ctx context.Context, region string) ([]in
 		return nil, errors.NewAWSError("failed to get SSM client", err)
 	}
 
-	// Get all SSM instances
-	resp, err := ssmClient.DescribeInstanceInformation(ctx, &ssm.DescribeInstanceInformationInput{})
-	if err != nil {
-		return nil, errors.NewSSMError("failed to describe instance information", err)
-	}
+	// Get all SSM instances, following pagination
+	var instances []interactive.Instance
+	paginator := ssm.NewDescribeInstanceInformationPaginator(ssmClient, &ssm.DescribeInstanceInformationInput{})
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.NewSSMError("failed to describe instance information", err)
+		}
 
-	instances := make([]interactive.Instance, len(resp.InstanceInformationList))
-	for i, info := range resp.InstanceInformationList {
-		instances[i] = interactive.Instance{
-			InstanceID:       aws.ToString(info.InstanceId),
-			SSMStatus:        string(info.PingStatus),
-			SSMAgentVersion:  aws.ToString(info.AgentVersion),
-			LastPingDateTime: info.LastPingDateTime.Format(time.RFC3339),
-			Platform:         aws.ToString(info.PlatformName),
+		for _, info := range resp.InstanceInformationList {
+			instance := interactive.Instance{
+				InstanceID:      aws.ToString(info.InstanceId),
+				SSMStatus:       string(info.PingStatus),
+				SSMAgentVersion: aws.ToString(info.AgentVersion),
+				Platform:        aws.ToString(info.PlatformName),
+			}
+			if info.LastPingDateTime != nil {
+				instance.LastPingDateTime = info.LastPingDateTime.Format(time.RFC3339)
+			}
+			instances = append(instances, instance)
 		}
 	}
 
@@ -543,10 +880,13 @@ func (m *Manager) resolveInstanceIdentifier(ctx context.Context, identifier, reg
 	return m.instanceService.ResolveInstanceIdentifier(ctx, identifier, region)
 }
 
-// waitForCommandCompletion waits for a command to complete and returns the result
-func (m *Manager) waitForCommandCompletion(ctx context.Context, ssmClient *ssm.Client, commandID, instanceID string) (*CommandResult, error) {
-	maxWait := 5 * time.Minute
-	pollInterval := 2 * time.Second
+// waitForCommandCompletion waits for a command to complete and returns the result.
+// On timeout it returns a TimedOut result with the partial output together with an error,
+// cancelling the invocation first when opts.CancelOnTimeout is set. When the batch timeout
+// ends ctx first, the invocation is cancelled as well.
+func (m *Manager) waitForCommandCompletion(ctx context.Context, ssmClient commandInvocationAPI, commandID, instanceID string, opts ExecOptions) (*CommandResult, error) {
+	maxWait := opts.completionTimeout()
+	pollInterval := commandPollInterval
 	deadline := time.Now().Add(maxWait)
 
 	for time.Now().Before(deadline) {
@@ -559,20 +899,17 @@ func (m *Manager) waitForCommandCompletion(ctx context.Context, ssmClient *ssm.C
 			return nil, fmt.Errorf("failed to check command status: %w", err)
 		}
 
-		if len(listResp.CommandInvocations) == 0 {
-			time.Sleep(pollInterval)
+		if len(listResp.CommandInvocations) == 0 || commandInProgress(string(listResp.CommandInvocations[0].Status)) {
+			if err := sleepWithContext(ctx, pollInterval); err != nil {
+				m.cancelAbandoned(ctx, ssmClient, commandID, []string{instanceID})
+				return nil, fmt.Errorf("stopped waiting for command: %w", err)
+			}
 			continue
 		}
 
 		invocation := listResp.CommandInvocations[0]
 		status := string(invocation.Status)
 
-		// If still in progress, continue waiting
-		if status == "InProgress" || status == "Pending" || status == "Delayed" {
-			time.Sleep(pollInterval)
-			continue
-		}
-
 		// Command completed, get detailed results
 		detailResp, err := ssmClient.GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
 			CommandId:  aws.String(commandID),
@@ -582,24 +919,78 @@ func (m *Manager) waitForCommandCompletion(ctx context.Context, ssmClient *ssm.C
 			return nil, fmt.Errorf("failed to get command result: %w", err)
 		}
 
-		// Clean the output to remove the EXIT_CODE line that was added by the wrapper script
-		cleanOutput := removeExitCodeLine(aws.ToString(detailResp.StandardOutputContent))
+		return commandResultFromInvocation(instanceID, status, detailResp), nil
+	}
 
-		result := &CommandResult{
-			InstanceID:  instanceID,
-			Status:      status,
-			Output:      cleanOutput,
-			ErrorOutput: aws.ToString(detailResp.StandardErrorContent),
-		}
+	return m.timedOutCommandResult(ctx, ssmClient, commandID, instanceID, opts.CancelOnTimeout),
+		fmt.Errorf("command execution timed out after %v", maxWait)
+}
 
-		if detailResp.ResponseCode != 0 {
-			result.ExitCode = &detailResp.ResponseCode
-		}
+// commandInProgress reports whether an invocation status means the command has not finished yet
+func commandInProgress(status string) bool {
+	return status == "InProgress" || status == "Pending" || status == "Delayed"
+}
+
+// commandResultFromInvocation builds the result of a finished invocation from its details
+func commandResultFromInvocation(instanceID, status string, detail *ssm.GetCommandInvocationOutput) *CommandResult {
+	// Clean the output to remove the EXIT_CODE line that was added by the wrapper script
+	result := &CommandResult{
+		InstanceID: instanceID,
+		Status:     status,
+	}
+	result.setOutput(aws.ToString(detail.StandardOutputContent), aws.ToString(detail.StandardErrorContent))
+
+	if detail.ResponseCode != 0 {
+		exitCode := detail.ResponseCode
+		result.ExitCode = &exitCode
+	}
+
+	return result
+}
 
-		return result, nil
+// timedOutCommandResult collects the partial output of a command that did not finish in time
+// and optionally cancels it on the instance. Failures only reduce the detail available.
+func (m *Manager) timedOutCommandResult(ctx context.Context, ssmClient commandInvocationAPI, commandID, instanceID string, cancel bool) *CommandResult {
+	result := &CommandResult{
+		InstanceID: instanceID,
+		Status:     CommandStatusTimedOut,
 	}
 
-	return nil, fmt.Errorf("command execution timed out after %v", maxWait)
+	detailResp, err := ssmClient.GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
+		CommandId:  aws.String(commandID),
+		InstanceId: aws.String(instanceID),
+	})
+	if err != nil {
+		m.logger.Warn("Failed to fetch partial output for timed out command", "commandID", commandID, "error", err)
+	} else {
+		result.setOutput(aws.ToString(detailResp.StandardOutputContent), aws.ToString(detailResp.StandardErrorContent))
+	}
+
+	if cancel {
+		if _, err := ssmClient.CancelCommand(ctx, &ssm.CancelCommandInput{
+			CommandId:   aws.String(commandID),
+			InstanceIds: []string{instanceID},
+		}); err != nil {
+			m.logger.Warn("Failed to cancel timed out command", "commandID", commandID, "error", err)
+		} else {
+			m.logger.Info("Cancelled timed out command", "commandID", commandID, "instanceID", instanceID)
+		}
+	}
+
+	return result
+}
+
+// sleepWithContext pauses for d, returning early with the context error if ctx is done
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-timer.C:
+		return nil
+	}
 }
 
 // removeExitCodeLine removes the EXIT_CODE line from command output
@@ -732,15 +1123,10 @@ func (m *Manager) downloadFileSmall(ctx context.Context, instanceID, region, rem
 		return fmt.Errorf("failed to decode file content: %w", err)
 	}
 
-	// Write to local file
-	if err := os.WriteFile(localPath, content, 0600); err != nil {
-		return fmt.Errorf("failed to write local file: %w", err)
-	}
-
-	return nil
+	return writeDownloadedContent(localPath, content)
 }
 
-func (m *Manager) uploadFileLarge(ctx context.Context, instanceID, region, localPath, remotePath string) error {
+func (m *Manager) uploadFileLarge(ctx context.Context, instanceID, region, localPath, remotePath string, opts UploadOptions) (err error) {
 	// Note: File path validation is performed in UploadFile() caller
 	m.logger.Info("Starting large file upload via S3 for instance", "instanceID", instanceID, "localPath", localPath)
 
@@ -751,21 +1137,23 @@ func (m *Manager) uploadFileLarge(ctx context.Context, instanceID, region, local
 		}
 	}
 
+	m.reconcileBeforeTransfer(ctx, region)
+
 	// Validate instance IAM setup
 	if err := m.iamManager.ValidateInstanceIAMSetup(ctx, instanceID, region); err != nil {
 		return fmt.Errorf("instance IAM validation failed: %w", err)
 	}
 
-	// Get S3 bucket name
-	bucketName, err := m.s3LifecycleManager.GetS3BucketName(ctx, region)
+	// Get the S3 bucket, ensured with its lifecycle configuration on the first transfer to the region
+	bucketName, err := m.transferBucket(ctx, region)
 	if err != nil {
-		return fmt.Errorf("failed to get S3 bucket name: %w", err)
-	}
-
-	// Ensure bucket exists with lifecycle configuration
-	if err := m.s3LifecycleManager.EnsureS3Bucket(ctx, bucketName, region); err != nil {
-		return fmt.Errorf("failed to ensure S3 bucket exists: %w", err)
+		return err
 	}
+	defer func() {
+		if err != nil {
+			m.transferBuckets.invalidate(region)
+		}
+	}()
 
 	// Attach S3 permissions to instance IAM role
 	m.logger.Info("Attaching temporary S3 permissions to instance", "instanceID", instanceID)
@@ -774,7 +1162,8 @@ func (m *Manager) uploadFileLarge(ctx context.Context, instanceID, region, local
 		return fmt.Errorf("failed to attach S3 permissions: %w", err)
 	}
 
-	// Defer cleanup of IAM permissions
+	// Defer cleanup of IAM permissions; the registry also runs it if the process panics first
+	cleanup = registerCleanup("temporary IAM policy for "+instanceID, cleanup)
 	defer func() {
 		m.logger.Info("Cleaning up temporary IAM permissions for instance", "instanceID", instanceID)
 		if err := cleanup(); err != nil {
@@ -795,16 +1184,30 @@ func (m *Manager) uploadFileLarge(ctx context.Context, instanceID, region, local
 	}
 	timestamp := time.Now().Unix()
 	s3Key := fmt.Sprintf("uploads/%d-%s-%s", timestamp, hex.EncodeToString(randomBytes), filepath.Base(localPath))
+	if opts.Resume {
+		if pendingKey, ok := m.s3LifecycleManager.PendingUploadKey(bucketName, localPath); ok {
+			m.logger.Info("Resuming incomplete upload to S3", "bucket", bucketName, "s3Key", pendingKey)
+			s3Key = pendingKey
+		}
+	}
 
-	// Defer cleanup of S3 object
+	// Defer cleanup of S3 object, which must still run after ctx is cancelled
+	cleanupObject := registerCleanup(fmt.Sprintf("staged S3 object s3://%s/%s", bucketName, s3Key), func() error {
+		return m.s3LifecycleManager.CleanupS3Object(context.WithoutCancel(ctx), bucketName, s3Key, region)
+	})
 	defer func() {
-		if err := m.s3LifecycleManager.CleanupS3Object(ctx, bucketName, s3Key, region); err != nil {
+		if err := cleanupObject(); err != nil {
 			m.logger.Warn("Failed to cleanup S3 object", "bucketName", bucketName, "s3Key", s3Key, "error", err)
 		}
 	}()
 
-	// Upload to S3
-	if err := m.s3LifecycleManager.UploadToS3(ctx, bucketName, s3Key, localPath, region); err != nil {
+	// Upload to S3; a resumable upload that fails stays open so the next run can continue it
+	if opts.Resume {
+		err = m.s3LifecycleManager.UploadToS3Resumable(ctx, bucketName, s3Key, localPath, region)
+	} else {
+		err = m.s3LifecycleManager.UploadToS3(ctx, bucketName, s3Key, localPath, region)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to upload to S3: %w", err)
 	}
 
@@ -843,7 +1246,7 @@ func (m *Manager) uploadFileLarge(ctx context.Context, instanceID, region, local
 	return nil
 }
 
-func (m *Manager) downloadFileLarge(ctx context.Context, instanceID, region, remotePath, localPath string) error {
+func (m *Manager) downloadFileLarge(ctx context.Context, instanceID, region, remotePath, localPath string) (err error) {
 	// Note: File path validation is performed in DownloadFile() caller
 	m.logger.Info("Starting large file download via S3 for instance", "instanceID", instanceID, "remotePath", remotePath)
 
@@ -854,21 +1257,23 @@ func (m *Manager) downloadFileLarge(ctx context.Context, instanceID, region, rem
 		}
 	}
 
+	m.reconcileBeforeTransfer(ctx, region)
+
 	// Validate instance IAM setup
 	if err := m.iamManager.ValidateInstanceIAMSetup(ctx, instanceID, region); err != nil {
 		return fmt.Errorf("instance IAM validation failed: %w", err)
 	}
 
-	// Get S3 bucket name
-	bucketName, err := m.s3LifecycleManager.GetS3BucketName(ctx, region)
+	// Get the S3 bucket, ensured with its lifecycle configuration on the first transfer to the region
+	bucketName, err := m.transferBucket(ctx, region)
 	if err != nil {
-		return fmt.Errorf("failed to get S3 bucket name: %w", err)
-	}
-
-	// Ensure bucket exists with lifecycle configuration
-	if err := m.s3LifecycleManager.EnsureS3Bucket(ctx, bucketName, region); err != nil {
-		return fmt.Errorf("failed to ensure S3 bucket exists: %w", err)
+		return err
 	}
+	defer func() {
+		if err != nil {
+			m.transferBuckets.invalidate(region)
+		}
+	}()
 
 	// Attach S3 permissions to instance IAM role
 	m.logger.Info("Attaching temporary S3 permissions to instance", "instanceID", instanceID)
@@ -877,7 +1282,8 @@ func (m *Manager) downloadFileLarge(ctx context.Context, instanceID, region, rem
 		return fmt.Errorf("failed to attach S3 permissions: %w", err)
 	}
 
-	// Defer cleanup of IAM permissions
+	// Defer cleanup of IAM permissions; the registry also runs it if the process panics first
+	cleanup = registerCleanup("temporary IAM policy for "+instanceID, cleanup)
 	defer func() {
 		m.logger.Info("Cleaning up temporary IAM permissions for instance", "instanceID", instanceID)
 		if err := cleanup(); err != nil {
@@ -899,9 +1305,12 @@ func (m *Manager) downloadFileLarge(ctx context.Context, instanceID, region, rem
 	timestamp := time.Now().Unix()
 	s3Key := fmt.Sprintf("downloads/%d-%s-%s", timestamp, hex.EncodeToString(randomBytes), filepath.Base(remotePath))
 
-	// Defer cleanup of S3 object
+	// Defer cleanup of S3 object, which must still run after ctx is cancelled
+	cleanupObject := registerCleanup(fmt.Sprintf("staged S3 object s3://%s/%s", bucketName, s3Key), func() error {
+		return m.s3LifecycleManager.CleanupS3Object(context.WithoutCancel(ctx), bucketName, s3Key, region)
+	})
 	defer func() {
-		if err := m.s3LifecycleManager.CleanupS3Object(ctx, bucketName, s3Key, region); err != nil {
+		if err := cleanupObject(); err != nil {
 			m.logger.Warn("Failed to cleanup S3 object", "bucketName", bucketName, "s3Key", s3Key, "error", err)
 		}
 	}()
@@ -943,6 +1352,14 @@ func (m *Manager) downloadFileLarge(ctx context.Context, instanceID, region, rem
 
 	m.logger.Info("File uploaded from instance, now downloading locally")
 
+	// Stream straight from S3 when writing to stdout
+	if localPath == StdoutPath {
+		if err := m.s3LifecycleManager.StreamFromS3(ctx, bucketName, s3Key, downloadStdout); err != nil {
+			return fmt.Errorf("failed to download from S3: %w", err)
+		}
+		return nil
+	}
+
 	// Create local directory if needed
 	localDir := filepath.Dir(localPath)
 	if err := os.MkdirAll(localDir, 0750); err != nil {
@@ -958,6 +1375,22 @@ func (m *Manager) downloadFileLarge(ctx context.Context, instanceID, region, rem
 	return nil
 }
 
+// transferBucket returns the region's S3 transfer bucket, creating it and its lifecycle configuration
+// when needed. The bucket is cached for the lifetime of the Manager; a large transfer that fails drops
+// it, so the next one checks the bucket again.
+func (m *Manager) transferBucket(ctx context.Context, region string) (string, error) {
+	return m.transferBuckets.get(ctx, region, func(ctx context.Context, region string) (string, error) {
+		bucketName, err := m.s3LifecycleManager.GetS3BucketName(ctx, region)
+		if err != nil {
+			return "", fmt.Errorf("failed to get S3 bucket name: %w", err)
+		}
+		if err := m.s3LifecycleManager.EnsureS3Bucket(ctx, bucketName, region); err != nil {
+			return "", fmt.Errorf("failed to ensure S3 bucket exists: %w", err)
+		}
+		return bucketName, nil
+	})
+}
+
 // EmergencyCleanup performs emergency cleanup of IAM policies and resources
 func (m *Manager) EmergencyCleanup(ctx context.Context, region string) error {
 	m.logger.Info("Performing emergency cleanup in region", "region", region)
@@ -970,25 +1403,50 @@ func (m *Manager) EmergencyCleanup(ctx context.Context, region string) error {
 		}
 	}
 
-	// Perform IAM emergency cleanup
-	if err := m.iamManager.EmergencyCleanup(ctx, region); err != nil {
-		m.logger.Warn("Failed to perform IAM emergency cleanup", "error", err)
+	// Remove every transfer policy and staged object regardless of age, including those of transfers still running
+	policies, objects, err := m.ReconcileOrphans(ctx, region, 0)
+	if err != nil {
+		return fmt.Errorf("emergency cleanup failed: %w", err)
 	}
 
-	m.logger.Info("Emergency cleanup completed")
+	m.logger.Info("Emergency cleanup completed", "policies", policies, "objects", objects)
 	return nil
 }
 
-// Cleanup performs routine cleanup operations
+// Cleanup performs routine cleanup operations: it removes transfer policies and staged objects
+// older than OrphanedTransferAge, which are left behind by transfers that were killed
 func (m *Manager) Cleanup(ctx context.Context, region string) error {
-	// Initialize managers if not already done
+	policies, objects, err := m.ReconcileOrphans(ctx, region, OrphanedTransferAge)
+	if err != nil {
+		return err
+	}
+
+	m.logger.Info("Cleanup completed", "policies", policies, "objects", objects)
+	return nil
+}
+
+// ReapplyLifecycleConfig re-applies the auto-cleanup lifecycle rule to the region's transfer bucket,
+// correcting a rule that was removed, disabled or changed outside ztictl. It returns the bucket name.
+func (m *Manager) ReapplyLifecycleConfig(ctx context.Context, region string) (string, error) {
 	if m.iamManager == nil || m.s3LifecycleManager == nil {
 		if err := m.initializeManagers(ctx, region); err != nil {
-			return fmt.Errorf("failed to initialize managers: %w", err)
+			return "", fmt.Errorf("failed to initialize managers: %w", err)
 		}
 	}
 
-	return nil
+	bucketName, err := m.s3LifecycleManager.GetS3BucketName(ctx, region)
+	if err != nil {
+		return "", err
+	}
+
+	if err := m.s3LifecycleManager.VerifyLifecycleConfig(ctx, bucketName, region); err != nil {
+		m.logger.Info("Lifecycle configuration drifted, re-applying", "bucketName", bucketName, "reason", err)
+	}
+	if err := m.s3LifecycleManager.ApplyLifecycleConfig(ctx, bucketName, region); err != nil {
+		return bucketName, err
+	}
+
+	return bucketName, nil
 }
 
 func (m *Manager) getRemoteFileSize(ctx context.Context, instanceID, region, remotePath string) (int64, error) {
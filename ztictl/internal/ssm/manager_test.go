@@ -1,9 +1,12 @@
 package ssm
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
+	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -11,7 +14,13 @@ import (
 	"time"
 
 	"ztictl/internal/interactive"
+	"ztictl/internal/platform"
+	awsservice "ztictl/pkg/aws"
 	"ztictl/pkg/logging"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
 )
 
 func TestNewManager(t *testing.T) {
@@ -1252,6 +1261,28 @@ func TestValidateAWSRegion(t *testing.T) {
 	}
 }
 
+func TestBuiltInShortcodesResolveToValidRegions(t *testing.T) {
+	resolver := awsservice.NewRegionResolver(nil)
+
+	for code, region := range awsservice.RegionMapping {
+		t.Run(code, func(t *testing.T) {
+			if err := validateAWSRegion(region); err != nil {
+				t.Errorf("Shortcode %s maps to invalid region: %v", code, err)
+			}
+			if !awsservice.IsValidAWSRegion(region) {
+				t.Errorf("Shortcode %s maps to %s, which IsValidAWSRegion rejects", code, region)
+			}
+			resolved, err := resolver.Resolve(code)
+			if err != nil {
+				t.Fatalf("Resolve(%s) returned error: %v", code, err)
+			}
+			if resolved != region {
+				t.Errorf("Resolve(%s) = %s, want %s", code, resolved, region)
+			}
+		})
+	}
+}
+
 func TestValidatePortNumber(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -1420,3 +1451,256 @@ func TestConcurrentMixedInitialization(t *testing.T) {
 
 	t.Log("Concurrent mixed initialization completed without race conditions")
 }
+
+func TestSleepWithContext(t *testing.T) {
+	if err := sleepWithContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("Expected sleep to complete, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if err := sleepWithContext(ctx, time.Minute); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected sleep to return promptly on cancellation, took %v", elapsed)
+	}
+}
+
+// fakeInvocationAPI simulates a command that never leaves InProgress
+type fakeInvocationAPI struct {
+	cancelled []string
+}
+
+func (f *fakeInvocationAPI) ListCommandInvocations(ctx context.Context, params *ssm.ListCommandInvocationsInput, optFns ...func(*ssm.Options)) (*ssm.ListCommandInvocationsOutput, error) {
+	return &ssm.ListCommandInvocationsOutput{
+		CommandInvocations: []ssmtypes.CommandInvocation{{Status: ssmtypes.CommandInvocationStatusInProgress}},
+	}, nil
+}
+
+func (f *fakeInvocationAPI) GetCommandInvocation(ctx context.Context, params *ssm.GetCommandInvocationInput, optFns ...func(*ssm.Options)) (*ssm.GetCommandInvocationOutput, error) {
+	return &ssm.GetCommandInvocationOutput{
+		StandardOutputContent: aws.String("step 1 done\nstep 2 running"),
+		StandardErrorContent:  aws.String("warning: slow disk"),
+	}, nil
+}
+
+func (f *fakeInvocationAPI) CancelCommand(ctx context.Context, params *ssm.CancelCommandInput, optFns ...func(*ssm.Options)) (*ssm.CancelCommandOutput, error) {
+	f.cancelled = append(f.cancelled, aws.ToString(params.CommandId))
+	return &ssm.CancelCommandOutput{}, nil
+}
+
+func TestWaitForCommandCompletionTimeout(t *testing.T) {
+	origTimeout, origPoll := commandCompletionTimeout, commandPollInterval
+	commandCompletionTimeout, commandPollInterval = 20*time.Millisecond, 5*time.Millisecond
+	defer func() { commandCompletionTimeout, commandPollInterval = origTimeout, origPoll }()
+
+	manager := NewManager(logging.NewNoOpLogger())
+
+	for _, cancel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("cancel=%v", cancel), func(t *testing.T) {
+			api := &fakeInvocationAPI{}
+
+			result, err := manager.waitForCommandCompletion(context.Background(), api, "cmd-123", "i-1234567890abcdef0", ExecOptions{CancelOnTimeout: cancel})
+			if err == nil || !strings.Contains(err.Error(), "timed out") {
+				t.Fatalf("Expected timeout error, got %v", err)
+			}
+			if !result.TimedOut() {
+				t.Fatalf("Expected TimedOut result, got %+v", result)
+			}
+			if result.Output != "step 1 done\nstep 2 running" || result.ErrorOutput != "warning: slow disk" {
+				t.Errorf("Expected partial output to be captured, got %+v", result)
+			}
+
+			wantCancelled := 0
+			if cancel {
+				wantCancelled = 1
+			}
+			if len(api.cancelled) != wantCancelled {
+				t.Errorf("Expected %d CancelCommand calls, got %d", wantCancelled, len(api.cancelled))
+			}
+		})
+	}
+}
+
+func TestCommandResultTimedOut(t *testing.T) {
+	var nilResult *CommandResult
+	if nilResult.TimedOut() {
+		t.Error("Expected nil result not to be timed out")
+	}
+	if (&CommandResult{Status: "Success"}).TimedOut() {
+		t.Error("Expected successful result not to be timed out")
+	}
+	if !(&CommandResult{Status: CommandStatusTimedOut}).TimedOut() {
+		t.Error("Expected TimedOut status to report a timeout")
+	}
+}
+
+func TestValidateDownloadPath(t *testing.T) {
+	if err := validateDownloadPath(StdoutPath); err != nil {
+		t.Errorf("Expected stdout path to be accepted, got %v", err)
+	}
+	if err := validateDownloadPath("../../../etc/passwd"); err == nil {
+		t.Error("Expected path traversal to be rejected")
+	}
+}
+
+func TestWriteDownloadedContent(t *testing.T) {
+	var stdout bytes.Buffer
+	original := downloadStdout
+	downloadStdout = &stdout
+	defer func() { downloadStdout = original }()
+
+	if err := writeDownloadedContent(StdoutPath, []byte("127.0.0.1 localhost\n")); err != nil {
+		t.Fatalf("Unexpected error writing to stdout: %v", err)
+	}
+	if stdout.String() != "127.0.0.1 localhost\n" {
+		t.Errorf("Unexpected stdout content: %q", stdout.String())
+	}
+
+	localPath := filepath.Join(t.TempDir(), "hosts")
+	if err := writeDownloadedContent(localPath, []byte("content")); err != nil {
+		t.Fatalf("Unexpected error writing file: %v", err)
+	}
+	if data, err := os.ReadFile(localPath); err != nil || string(data) != "content" {
+		t.Errorf("Expected file content to be written, got %q (%v)", data, err)
+	}
+	if stdout.Len() != len("127.0.0.1 localhost\n") {
+		t.Error("File downloads should not write to stdout")
+	}
+}
+
+func TestFileTransferOperationStats(t *testing.T) {
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(83 * time.Second)
+
+	tests := []struct {
+		name string
+		op   FileTransferOperation
+		want string
+	}{
+		{"size and duration", FileTransferOperation{Size: 1503238553, StartTime: &start, EndTime: &end}, "1.4 GiB in 1m23s"},
+		{"size only", FileTransferOperation{Size: 2048}, "2.0 KiB"},
+		{"duration only", FileTransferOperation{StartTime: &start, EndTime: &end}, "1m23s"},
+		{"nothing known", FileTransferOperation{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.op.Stats(); got != tt.want {
+				t.Errorf("Stats() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandResultFromInvocationTruncation(t *testing.T) {
+	tests := []struct {
+		name          string
+		stdout        string
+		stderr        string
+		wantTruncated bool
+		wantErrTrunc  bool
+	}{
+		{name: "short output", stdout: "ok\nEXIT_CODE:0\n", stderr: "warn"},
+		{name: "stdout at the limit", stdout: strings.Repeat("x", MaxInlineOutputChars), wantTruncated: true},
+		{name: "stderr at the limit", stdout: "ok", stderr: strings.Repeat("e", MaxInlineErrorOutputChars), wantErrTrunc: true},
+		// The limit counts characters, not bytes
+		{name: "multibyte output below the limit", stdout: strings.Repeat("é", MaxInlineOutputChars-1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := commandResultFromInvocation("i-1", "Success", &ssm.GetCommandInvocationOutput{
+				StandardOutputContent: aws.String(tt.stdout),
+				StandardErrorContent:  aws.String(tt.stderr),
+			})
+			if result.OutputTruncated != tt.wantTruncated {
+				t.Errorf("OutputTruncated = %v, expected %v", result.OutputTruncated, tt.wantTruncated)
+			}
+			if result.ErrorOutputTruncated != tt.wantErrTrunc {
+				t.Errorf("ErrorOutputTruncated = %v, expected %v", result.ErrorOutputTruncated, tt.wantErrTrunc)
+			}
+		})
+	}
+}
+
+func TestWrapCommandRunAs(t *testing.T) {
+	wrapped, applied, _ := wrapCommand(platform.NewLinuxBuilder(), "id -un", ExecOptions{RunAs: "appuser", Env: map[string]string{"A": "1"}})
+	if !applied || !strings.Contains(wrapped, "sudo -n -u 'appuser' -H sh -c") || !strings.Contains(wrapped, "EXIT_CODE") {
+		t.Errorf("Expected the command to run as appuser inside the exit code wrapper, got %q", wrapped)
+	}
+	if !strings.Contains(wrapped, "export A=") {
+		t.Errorf("Expected the environment to be exported inside the user's shell, got %q", wrapped)
+	}
+
+	wrapped, applied, _ = wrapCommand(platform.NewWindowsBuilder(), "whoami", ExecOptions{RunAs: "appuser"})
+	if applied || !strings.Contains(wrapped, "whoami") {
+		t.Errorf("Expected Windows to report run-as as not applied, got %v: %q", applied, wrapped)
+	}
+	if !strings.Contains(unwrappedWarning(ExecOptions{RunAs: "appuser"}), "another user") {
+		t.Errorf("Expected a run-as specific warning, got %q", unwrappedWarning(ExecOptions{RunAs: "appuser"}))
+	}
+}
+
+func TestWrapCommandNoWrap(t *testing.T) {
+	builder := platform.NewLinuxBuilder()
+	command := `echo "it's" | tr a-z A-Z`
+
+	wrapped, sudoApplied, _ := wrapCommand(builder, command, ExecOptions{Sudo: true, Env: map[string]string{"A": "1"}})
+	if !sudoApplied || wrapped == command || !strings.Contains(wrapped, "EXIT_CODE") {
+		t.Errorf("Expected the default wrapper around the command, got %q", wrapped)
+	}
+
+	wrapped, sudoApplied, _ = wrapCommand(builder, command, ExecOptions{NoWrap: true})
+	if !sudoApplied || wrapped != command {
+		t.Errorf("Expected --no-wrap to send the command verbatim, got %q", wrapped)
+	}
+}
+
+func TestWrapCommandSteps(t *testing.T) {
+	opts := ExecOptions{Steps: []string{"apt update", "apt install -y jq"}, Env: map[string]string{"A": "1"}}
+	wrapped, _, _ := wrapCommand(platform.NewLinuxBuilder(), "apt update && apt install -y jq", opts)
+	if strings.Contains(wrapped, "&&") || !strings.Contains(wrapped, platform.StepMarkerPrefix+"2/2: apt install -y jq") {
+		t.Errorf("Expected the steps to replace the command, got %q", wrapped)
+	}
+	if !strings.Contains(wrapped, "export A=") || !strings.Contains(wrapped, "EXIT_CODE") {
+		t.Errorf("Expected the sequence inside the environment and exit code wrappers, got %q", wrapped)
+	}
+}
+
+func TestWrapCommandTemplate(t *testing.T) {
+	tmpl, err := platform.ParseCommandTemplate("umask 027\n{{.Command}}\necho epilogue")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		command  string
+		exitCode string
+	}{
+		{command: "umask; exit 3", exitCode: "EXIT_CODE:3"},
+		{command: "umask; false", exitCode: "EXIT_CODE:1"},
+		{command: "umask", exitCode: "EXIT_CODE:0"},
+	} {
+		wrapped, _, err := wrapCommand(platform.NewLinuxBuilder(), tt.command, ExecOptions{CommandTemplate: tmpl})
+		if err != nil {
+			t.Fatalf("wrapCommand() error = %v", err)
+		}
+		output, _ := exec.Command("sh", "-c", wrapped).Output() // #nosec G204 - test input
+		if got := string(output); !strings.Contains(got, "0027\nepilogue\n") || !strings.Contains(got, tt.exitCode) {
+			t.Errorf("%q: expected the preamble, command and epilogue to run with %s reported, got %q", tt.command, tt.exitCode, got)
+		}
+	}
+
+	wrapped, _, _ := wrapCommand(platform.NewLinuxBuilder(), "uptime", ExecOptions{CommandTemplate: tmpl, Sudo: true, Env: map[string]string{"A": "1"}})
+	if !strings.Contains(wrapped, "export A=") || strings.Index(wrapped, "export A=") > strings.Index(wrapped, "umask 027") {
+		t.Errorf("Expected the template inside the environment and sudo wrappers, got %q", wrapped)
+	}
+
+	wrapped, _, _ = wrapCommand(platform.NewLinuxBuilder(), "uptime", ExecOptions{CommandTemplate: tmpl, NoWrap: true})
+	if wrapped != "uptime" {
+		t.Errorf("Expected --no-wrap to skip the template, got %q", wrapped)
+	}
+}
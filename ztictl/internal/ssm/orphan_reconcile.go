@@ -0,0 +1,191 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// OrphanedTransferAge is how old a temporary transfer resource must be before a reconcile removes it.
+// It is well beyond the length of any transfer, so resources of transfers still running are left alone.
+const OrphanedTransferAge = 6 * time.Hour
+
+// transferObjectPrefixes are the key prefixes of objects staged in the transfer bucket
+var transferObjectPrefixes = []string{"uploads/", "downloads/"}
+
+// policyCreatedAt returns the creation time encoded in a transfer policy name
+// (<PolicyNamePrefix>-<unix seconds>-<hostname>-<random>), and false for other policies
+func policyCreatedAt(policyName string) (time.Time, bool) {
+	rest, ok := strings.CutPrefix(policyName, PolicyNamePrefix+"-")
+	if !ok {
+		return time.Time{}, false
+	}
+	timestamp, _, _ := strings.Cut(rest, "-")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+// isOrphaned reports whether a resource created at createdAt is older than olderThan
+func isOrphaned(createdAt, now time.Time, olderThan time.Duration) bool {
+	return !createdAt.After(now.Add(-olderThan))
+}
+
+// ReconcileOrphanedPolicies detaches and deletes transfer policies older than olderThan and returns how many were removed
+func (m *IAMManager) ReconcileOrphanedPolicies(ctx context.Context, olderThan time.Duration) (int, error) {
+	now := time.Now()
+	var orphaned []iamtypes.Policy
+
+	paginator := iam.NewListPoliciesPaginator(m.iamClient, &iam.ListPoliciesInput{Scope: iamtypes.PolicyScopeTypeLocal})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list IAM policies: %w", err)
+		}
+		for _, policy := range page.Policies {
+			createdAt, ok := policyCreatedAt(aws.ToString(policy.PolicyName))
+			if ok && isOrphaned(createdAt, now, olderThan) {
+				orphaned = append(orphaned, policy)
+			}
+		}
+	}
+
+	removed := 0
+	for _, policy := range orphaned {
+		if err := m.deletePolicy(ctx, aws.ToString(policy.Arn)); err != nil {
+			m.logger.Warn("Failed to remove orphaned transfer policy", "policyName", aws.ToString(policy.PolicyName), "error", err)
+			continue
+		}
+		m.logger.Info("Removed orphaned transfer policy", "policyName", aws.ToString(policy.PolicyName))
+		removed++
+	}
+	return removed, nil
+}
+
+// deletePolicy detaches a policy from every role it is attached to and deletes it
+func (m *IAMManager) deletePolicy(ctx context.Context, policyARN string) error {
+	paginator := iam.NewListEntitiesForPolicyPaginator(m.iamClient, &iam.ListEntitiesForPolicyInput{
+		PolicyArn:    aws.String(policyARN),
+		EntityFilter: iamtypes.EntityTypeRole,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list policy attachments: %w", err)
+		}
+		for _, role := range page.PolicyRoles {
+			if _, err := m.iamClient.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+				RoleName:  role.RoleName,
+				PolicyArn: aws.String(policyARN),
+			}); err != nil {
+				return fmt.Errorf("failed to detach policy from role %s: %w", aws.ToString(role.RoleName), err)
+			}
+		}
+	}
+
+	if _, err := m.iamClient.DeletePolicy(ctx, &iam.DeletePolicyInput{PolicyArn: aws.String(policyARN)}); err != nil {
+		return fmt.Errorf("failed to delete policy: %w", err)
+	}
+	return nil
+}
+
+// DeleteStaleTransferObjects removes staged transfer objects older than olderThan and returns how many were removed.
+// The bucket lifecycle rule expires them eventually; this removes them without waiting for it.
+func (m *S3LifecycleManager) DeleteStaleTransferObjects(ctx context.Context, bucketName string, olderThan time.Duration) (int, error) {
+	now := time.Now()
+	removed := 0
+
+	for _, prefix := range transferObjectPrefixes {
+		paginator := s3.NewListObjectsV2Paginator(m.s3Client, &s3.ListObjectsV2Input{
+			Bucket: aws.String(bucketName),
+			Prefix: aws.String(prefix),
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return removed, fmt.Errorf("failed to list transfer objects: %w", err)
+			}
+			for _, object := range page.Contents {
+				if object.LastModified == nil || !isOrphaned(*object.LastModified, now, olderThan) {
+					continue
+				}
+				if _, err := m.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+					Bucket: aws.String(bucketName),
+					Key:    object.Key,
+				}); err != nil {
+					m.logger.Warn("Failed to remove stale transfer object", "bucketName", bucketName, "key", aws.ToString(object.Key), "error", err)
+					continue
+				}
+				removed++
+			}
+		}
+	}
+	return removed, nil
+}
+
+// ReconcileOrphans removes temporary transfer resources older than olderThan: IAM policies named with
+// PolicyNamePrefix and objects staged in the region's transfer bucket. These are left behind when a
+// transfer is killed before its cleanups run. It returns how many policies and objects were removed.
+func (m *Manager) ReconcileOrphans(ctx context.Context, region string, olderThan time.Duration) (policies, objects int, err error) {
+	if m.iamManager == nil || m.s3LifecycleManager == nil {
+		if err := m.initializeManagers(ctx, region); err != nil {
+			return 0, 0, fmt.Errorf("failed to initialize managers: %w", err)
+		}
+	}
+
+	policies, err = m.iamManager.ReconcileOrphanedPolicies(ctx, olderThan)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bucketName, err := m.s3LifecycleManager.GetS3BucketName(ctx, region)
+	if err != nil {
+		return policies, 0, fmt.Errorf("failed to get S3 bucket name: %w", err)
+	}
+	exists, err := m.s3LifecycleManager.bucketExists(ctx, bucketName)
+	if err != nil || !exists {
+		return policies, 0, err
+	}
+	objects, err = m.s3LifecycleManager.DeleteStaleTransferObjects(ctx, bucketName, olderThan)
+	return policies, objects, err
+}
+
+// bucketExists reports whether the transfer bucket has been created
+func (m *S3LifecycleManager) bucketExists(ctx context.Context, bucketName string) (bool, error) {
+	_, err := m.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucketName)})
+	var notFound *s3types.NotFound
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.As(err, &notFound):
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to check S3 bucket %s: %w", bucketName, err)
+	}
+}
+
+// reconcileBeforeTransfer runs a best-effort reconcile once per manager, before its first S3 transfer,
+// so resources orphaned by an earlier crashed run do not accumulate
+func (m *Manager) reconcileBeforeTransfer(ctx context.Context, region string) {
+	m.reconcileOnce.Do(func() {
+		policies, objects, err := m.ReconcileOrphans(ctx, region, OrphanedTransferAge)
+		if err != nil {
+			m.logger.Debug("Skipped orphaned transfer resource reconcile", "error", err)
+			return
+		}
+		if policies > 0 || objects > 0 {
+			m.logger.Info("Removed resources orphaned by earlier transfers", "policies", policies, "objects", objects)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package ssm
+
+import (
+	"testing"
+	"time"
+
+	"ztictl/pkg/logging"
+)
+
+func TestPolicyCreatedAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		want   time.Time
+		wantOK bool
+	}{
+		{name: "transfer policy", policy: PolicyNamePrefix + "-1700000000-build-host-0123456789abcdef", want: time.Unix(1700000000, 0), wantOK: true},
+		{name: "other policy", policy: "AppServerPolicy", wantOK: false},
+		{name: "prefix without timestamp", policy: PolicyNamePrefix + "-manual", wantOK: false},
+		{name: "prefix only", policy: PolicyNamePrefix, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := policyCreatedAt(tt.policy)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("policyCreatedAt(%q) = %v, %v; want %v, %v", tt.policy, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestPolicyCreatedAtMatchesGeneratedNames(t *testing.T) {
+	manager := &IAMManager{logger: logging.NewNoOpLogger()}
+	before := time.Now().Add(-time.Second)
+	createdAt, ok := policyCreatedAt(PolicyNamePrefix + "-" + manager.generateUniqueID())
+	if !ok || createdAt.Before(before.Truncate(time.Second)) {
+		t.Errorf("generated policy name not recognised: %v, %v", createdAt, ok)
+	}
+}
+
+func TestIsOrphaned(t *testing.T) {
+	now := time.Now()
+	if !isOrphaned(now.Add(-7*time.Hour), now, OrphanedTransferAge) {
+		t.Error("a 7h old resource should be orphaned")
+	}
+	if isOrphaned(now.Add(-time.Minute), now, OrphanedTransferAge) {
+		t.Error("a resource of a running transfer must not be orphaned")
+	}
+	if !isOrphaned(now, now, 0) {
+		t.Error("an age of 0 should match every resource")
+	}
+}
@@ -0,0 +1,82 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"ztictl/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+// maxParametersPerRequest is the GetParameters API limit on names per call
+const maxParametersPerRequest = 10
+
+// parameterAPI is the subset of the SSM API used to read Parameter Store values
+type parameterAPI interface {
+	GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error)
+}
+
+// ParameterStore reads values from SSM Parameter Store using the shared client pool.
+// Values may be secrets, so they are never logged.
+type ParameterStore struct {
+	clientPool *ClientPool
+}
+
+// NewParameterStore creates a Parameter Store reader backed by the given client pool
+func NewParameterStore(clientPool *ClientPool) *ParameterStore {
+	return &ParameterStore{clientPool: clientPool}
+}
+
+// GetParameters returns the decrypted values of the named parameters, keyed by parameter name.
+// It fails if any parameter does not exist.
+func (p *ParameterStore) GetParameters(ctx context.Context, region string, names []string) (map[string]string, error) {
+	ssmClient, err := p.clientPool.GetSSMClient(ctx, region)
+	if err != nil {
+		return nil, errors.NewAWSError("failed to get SSM client", err)
+	}
+	return fetchParameters(ctx, ssmClient, names)
+}
+
+// fetchParameters reads the named parameters in batches, decrypting SecureString values
+func fetchParameters(ctx context.Context, api parameterAPI, names []string) (map[string]string, error) {
+	unique := make(map[string]bool, len(names))
+	var pending []string
+	for _, name := range names {
+		if !unique[name] {
+			unique[name] = true
+			pending = append(pending, name)
+		}
+	}
+
+	values := make(map[string]string, len(pending))
+	var missing []string
+	for start := 0; start < len(pending); start += maxParametersPerRequest {
+		end := start + maxParametersPerRequest
+		if end > len(pending) {
+			end = len(pending)
+		}
+
+		output, err := api.GetParameters(ctx, &ssm.GetParametersInput{
+			Names:          pending[start:end],
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return nil, errors.NewAWSError("failed to get parameters from Parameter Store", err)
+		}
+
+		for _, parameter := range output.Parameters {
+			values[aws.ToString(parameter.Name)] = aws.ToString(parameter.Value)
+		}
+		missing = append(missing, output.InvalidParameters...)
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("parameter(s) not found in Parameter Store: %s", strings.Join(missing, ", "))
+	}
+	return values, nil
+}
@@ -0,0 +1,70 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// fakeParameterAPI serves parameters from a map and records each request
+type fakeParameterAPI struct {
+	values   map[string]string
+	requests []*ssm.GetParametersInput
+}
+
+func (f *fakeParameterAPI) GetParameters(ctx context.Context, params *ssm.GetParametersInput, optFns ...func(*ssm.Options)) (*ssm.GetParametersOutput, error) {
+	f.requests = append(f.requests, params)
+	output := &ssm.GetParametersOutput{}
+	for _, name := range params.Names {
+		if value, exists := f.values[name]; exists {
+			output.Parameters = append(output.Parameters, ssmtypes.Parameter{Name: aws.String(name), Value: aws.String(value)})
+		} else {
+			output.InvalidParameters = append(output.InvalidParameters, name)
+		}
+	}
+	return output, nil
+}
+
+func TestFetchParameters(t *testing.T) {
+	api := &fakeParameterAPI{values: map[string]string{}}
+	var names []string
+	for i := 0; i < 12; i++ {
+		name := fmt.Sprintf("/app/param-%02d", i)
+		api.values[name] = fmt.Sprintf("value-%d", i)
+		names = append(names, name)
+	}
+	names = append(names, "/app/param-00") // duplicates are fetched once
+
+	values, err := fetchParameters(context.Background(), api, names)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(values) != 12 || values["/app/param-11"] != "value-11" {
+		t.Errorf("Unexpected values: %v", values)
+	}
+	if len(api.requests) != 2 || len(api.requests[0].Names) != maxParametersPerRequest {
+		t.Errorf("Expected two batched requests, got %d", len(api.requests))
+	}
+	for _, request := range api.requests {
+		if !aws.ToBool(request.WithDecryption) {
+			t.Error("Expected parameters to be requested with decryption")
+		}
+	}
+}
+
+func TestFetchParametersMissing(t *testing.T) {
+	api := &fakeParameterAPI{values: map[string]string{"/app/db/password": "hunter2"}}
+
+	_, err := fetchParameters(context.Background(), api, []string{"/app/db/password", "/app/missing"})
+	if err == nil || !strings.Contains(err.Error(), "/app/missing") {
+		t.Fatalf("Expected error naming the missing parameter, got %v", err)
+	}
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Error("Error must not include parameter values")
+	}
+}
@@ -0,0 +1,71 @@
+package ssm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"ztictl/pkg/errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// s3BucketNamePattern matches valid S3 bucket names (3 to 63 lowercase letters, digits, dots and hyphens)
+var s3BucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// putObjectAPI is the subset of the S3 API used to archive reports
+type putObjectAPI interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// ParseS3URI splits an s3://bucket/prefix URI into the bucket and the key prefix, without
+// surrounding slashes. The prefix may be empty.
+func ParseS3URI(uri string) (bucket, prefix string, err error) {
+	rest, ok := strings.CutPrefix(uri, "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("expected s3://bucket/prefix, got '%s'", uri)
+	}
+	bucket, prefix, _ = strings.Cut(rest, "/")
+	if !s3BucketNamePattern.MatchString(bucket) {
+		return "", "", fmt.Errorf("invalid S3 bucket name '%s'", bucket)
+	}
+	return bucket, strings.Trim(prefix, "/"), nil
+}
+
+// ArchiveObject uploads data as a new object at s3://bucket/key, tagged with the default tags. The
+// bucket's own region is looked up from region's S3 client, so the archive bucket can live anywhere.
+// An existing object is never overwritten.
+func (m *Manager) ArchiveObject(ctx context.Context, region, bucket, key, contentType string, data []byte) error {
+	s3Client, err := m.clientPool.GetS3Client(ctx, region)
+	if err != nil {
+		return errors.NewAWSError("failed to get S3 client", err)
+	}
+	if bucketRegion, err := manager.GetBucketRegion(ctx, s3Client, bucket); err != nil {
+		m.logger.Debug("Could not look up the archive bucket region, using the command region", "bucket", bucket, "error", err)
+	} else if bucketRegion != region {
+		if s3Client, err = m.clientPool.GetS3Client(ctx, bucketRegion); err != nil {
+			return errors.NewAWSError("failed to get S3 client", err)
+		}
+	}
+	return putArchiveObject(ctx, s3Client, bucket, key, contentType, data)
+}
+
+// putArchiveObject writes the object only if the key does not exist yet
+func putArchiveObject(ctx context.Context, api putObjectAPI, bucket, key, contentType string, data []byte) error {
+	_, err := api.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+		IfNoneMatch: aws.String("*"),
+		Tagging:     s3ObjectTagging(defaultResourceTags()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload s3://%s/%s: %w", bucket, key, err)
+	}
+	return nil
+}
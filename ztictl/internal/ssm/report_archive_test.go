@@ -0,0 +1,79 @@
+package ssm
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestParseS3URI(t *testing.T) {
+	tests := []struct {
+		uri        string
+		wantBucket string
+		wantPrefix string
+		wantErr    bool
+	}{
+		{uri: "s3://audit-logs", wantBucket: "audit-logs"},
+		{uri: "s3://audit-logs/", wantBucket: "audit-logs"},
+		{uri: "s3://audit-logs/ztictl/exec/", wantBucket: "audit-logs", wantPrefix: "ztictl/exec"},
+		{uri: "s3://audit.logs.example/runs", wantBucket: "audit.logs.example", wantPrefix: "runs"},
+		{uri: "audit-logs/runs", wantErr: true},
+		{uri: "https://audit-logs.s3.amazonaws.com/runs", wantErr: true},
+		{uri: "s3://", wantErr: true},
+		{uri: "s3://Audit-Logs", wantErr: true},
+		{uri: "s3://ab", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		bucket, prefix, err := ParseS3URI(tt.uri)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseS3URI(%q) expected an error, got %q %q", tt.uri, bucket, prefix)
+			}
+			continue
+		}
+		if err != nil || bucket != tt.wantBucket || prefix != tt.wantPrefix {
+			t.Errorf("ParseS3URI(%q) = %q, %q, %v; want %q, %q", tt.uri, bucket, prefix, err, tt.wantBucket, tt.wantPrefix)
+		}
+	}
+}
+
+type mockPutObjectAPI struct {
+	input *s3.PutObjectInput
+	body  string
+	err   error
+}
+
+func (m *mockPutObjectAPI) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	m.input = params
+	data, _ := io.ReadAll(params.Body)
+	m.body = string(data)
+	return &s3.PutObjectOutput{}, m.err
+}
+
+func TestPutArchiveObject(t *testing.T) {
+	api := &mockPutObjectAPI{}
+	if err := putArchiveObject(context.Background(), api, "audit-logs", "runs/r1.json", "application/json", []byte(`{"run_id":"r1"}`)); err != nil {
+		t.Fatalf("putArchiveObject() error = %v", err)
+	}
+	if aws.ToString(api.input.Bucket) != "audit-logs" || aws.ToString(api.input.Key) != "runs/r1.json" || api.body != `{"run_id":"r1"}` {
+		t.Errorf("Unexpected upload %+v with body %q", api.input, api.body)
+	}
+	if aws.ToString(api.input.IfNoneMatch) != "*" {
+		t.Error("Expected archived reports never to overwrite an existing object")
+	}
+	if aws.ToString(api.input.ContentType) != "application/json" {
+		t.Errorf("Expected a JSON content type, got %q", aws.ToString(api.input.ContentType))
+	}
+
+	api = &mockPutObjectAPI{err: errors.New("AccessDenied")}
+	err := putArchiveObject(context.Background(), api, "audit-logs", "runs/r1.json", "application/json", nil)
+	if err == nil || !strings.Contains(err.Error(), "s3://audit-logs/runs/r1.json") {
+		t.Errorf("Expected the failed location in the error, got %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package ssm
+
+import (
+	"net/url"
+
+	appconfig "ztictl/internal/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// defaultResourceTags returns the configured default_tags applied to resources ztictl creates.
+// The tags were validated against the AWS constraints when the configuration was loaded.
+func defaultResourceTags() []appconfig.ResourceTag {
+	if cfg := appconfig.Get(); cfg != nil {
+		return cfg.DefaultTags
+	}
+	return nil
+}
+
+// s3ObjectTagging encodes tags in the URL query format used by PutObject, or nil when there are none
+func s3ObjectTagging(tags []appconfig.ResourceTag) *string {
+	if len(tags) == 0 {
+		return nil
+	}
+	values := url.Values{}
+	for _, tag := range tags {
+		values.Set(tag.Key, tag.Value)
+	}
+	return aws.String(values.Encode())
+}
+
+// s3BucketTags converts tags to S3 bucket tags
+func s3BucketTags(tags []appconfig.ResourceTag) []s3types.Tag {
+	s3Tags := make([]s3types.Tag, len(tags))
+	for i, tag := range tags {
+		s3Tags[i] = s3types.Tag{Key: aws.String(tag.Key), Value: aws.String(tag.Value)}
+	}
+	return s3Tags
+}
+
+// iamTags converts tags to IAM tags, or nil when there are none
+func iamTags(tags []appconfig.ResourceTag) []iamtypes.Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+	iamTagList := make([]iamtypes.Tag, len(tags))
+	for i, tag := range tags {
+		iamTagList[i] = iamtypes.Tag{Key: aws.String(tag.Key), Value: aws.String(tag.Value)}
+	}
+	return iamTagList
+}
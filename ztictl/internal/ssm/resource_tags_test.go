@@ -0,0 +1,36 @@
+package ssm
+
+import (
+	"net/url"
+	"testing"
+
+	appconfig "ztictl/internal/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestResourceTagConversions(t *testing.T) {
+	if s3ObjectTagging(nil) != nil || iamTags(nil) != nil {
+		t.Error("Expected no tagging when no default tags are configured")
+	}
+
+	tags := []appconfig.ResourceTag{{Key: "CostCenter", Value: "platform & ops"}, {Key: "team:owner", Value: "sre@example.com"}}
+
+	tagging, err := url.ParseQuery(aws.ToString(s3ObjectTagging(tags)))
+	if err != nil {
+		t.Fatalf("Object tagging is not a valid query string: %v", err)
+	}
+	if tagging.Get("CostCenter") != "platform & ops" || tagging.Get("team:owner") != "sre@example.com" {
+		t.Errorf("Unexpected object tagging: %v", tagging)
+	}
+
+	bucketTags := s3BucketTags(tags)
+	if len(bucketTags) != 2 || aws.ToString(bucketTags[0].Key) != "CostCenter" {
+		t.Errorf("Unexpected bucket tags: %+v", bucketTags)
+	}
+
+	policyTags := iamTags(tags)
+	if len(policyTags) != 2 || aws.ToString(policyTags[1].Value) != "sre@example.com" {
+		t.Errorf("Unexpected IAM tags: %+v", policyTags)
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"os"
 	"strings"
 
+	appconfig "ztictl/internal/config"
 	"ztictl/pkg/logging"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
@@ -54,6 +56,43 @@ const (
 	DefaultAbortUploadDays = 1
 )
 
+// Multipart transfer defaults, used when the configured values are unset or invalid
+const (
+	DefaultS3PartSizeMB  = 16
+	DefaultS3Concurrency = 5
+)
+
+// s3TransferSettings returns the multipart part size in bytes and the part concurrency from configuration.
+// Part sizes below the S3 minimum of 5 MiB are raised to it.
+func s3TransferSettings() (int64, int) {
+	partSizeMB := int64(DefaultS3PartSizeMB)
+	concurrency := DefaultS3Concurrency
+
+	if cfg := appconfig.Get(); cfg != nil {
+		if cfg.System.S3PartSizeMB > 0 {
+			partSizeMB = cfg.System.S3PartSizeMB
+		}
+		if cfg.System.S3Concurrency > 0 {
+			concurrency = cfg.System.S3Concurrency
+		}
+	}
+
+	partSize := partSizeMB * 1024 * 1024
+	if partSize < manager.MinUploadPartSize {
+		partSize = manager.MinUploadPartSize
+	}
+
+	return partSize, concurrency
+}
+
+// lifecycleExpirationDays returns how many days transfer objects are kept, from system.s3_lifecycle_days
+func lifecycleExpirationDays() int32 {
+	if cfg := appconfig.Get(); cfg != nil && cfg.System.S3LifecycleDays > 0 {
+		return int32(cfg.System.S3LifecycleDays) // #nosec G115 - bounded by config validation and S3 limits
+	}
+	return DefaultExpirationDays
+}
+
 // NewS3LifecycleManager creates a new S3 lifecycle manager
 func NewS3LifecycleManager(logger *logging.Logger, s3Client *s3.Client, stsClient *sts.Client) *S3LifecycleManager {
 	return &S3LifecycleManager{
@@ -88,7 +127,7 @@ func (m *S3LifecycleManager) createLifecycleConfiguration() *s3types.BucketLifec
 					Prefix: aws.String(""), // Apply to all objects
 				},
 				Expiration: &s3types.LifecycleExpiration{
-					Days: aws.Int32(DefaultExpirationDays),
+					Days: aws.Int32(lifecycleExpirationDays()),
 				},
 				AbortIncompleteMultipartUpload: &s3types.AbortIncompleteMultipartUpload{
 					DaysAfterInitiation: aws.Int32(DefaultAbortUploadDays),
@@ -132,16 +171,34 @@ func (m *S3LifecycleManager) VerifyLifecycleConfig(ctx context.Context, bucketNa
 		return fmt.Errorf("failed to get lifecycle configuration: %w", err)
 	}
 
-	// Check if our specific rule exists and is enabled
-	for _, rule := range result.Rules {
-		if rule.ID != nil && *rule.ID == LifecycleRuleID {
-			if rule.Status == s3types.ExpirationStatusEnabled {
-				m.logger.Debug("Lifecycle configuration verified as enabled for bucket", "bucketName", bucketName)
-				return nil
-			} else {
-				return fmt.Errorf("lifecycle configuration exists but is not enabled for bucket: %s", bucketName)
+	if err := checkLifecycleRules(result.Rules, bucketName); err != nil {
+		return err
+	}
+
+	m.logger.Debug("Lifecycle configuration verified as enabled for bucket", "bucketName", bucketName)
+	return nil
+}
+
+// checkLifecycleRules verifies that the ztictl cleanup rule exists, is enabled and still expires
+// objects after the configured number of days
+func checkLifecycleRules(rules []s3types.LifecycleRule, bucketName string) error {
+	for _, rule := range rules {
+		if rule.ID == nil || *rule.ID != LifecycleRuleID {
+			continue
+		}
+		if rule.Status != s3types.ExpirationStatusEnabled {
+			return fmt.Errorf("lifecycle configuration exists but is not enabled for bucket: %s", bucketName)
+		}
+
+		want := lifecycleExpirationDays()
+		if rule.Expiration == nil || aws.ToInt32(rule.Expiration.Days) != want {
+			var got int32
+			if rule.Expiration != nil {
+				got = aws.ToInt32(rule.Expiration.Days)
 			}
+			return fmt.Errorf("lifecycle rule %s expires objects after %d day(s), expected %d for bucket: %s", LifecycleRuleID, got, want, bucketName)
 		}
+		return nil
 	}
 
 	return fmt.Errorf("lifecycle rule %s not found for bucket: %s", LifecycleRuleID, bucketName)
@@ -178,6 +235,15 @@ func (m *S3LifecycleManager) EnsureS3Bucket(ctx context.Context, bucketName, reg
 		}
 
 		bucketCreated = true
+
+		if tags := defaultResourceTags(); len(tags) > 0 {
+			if _, err := m.s3Client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+				Bucket:  aws.String(bucketName),
+				Tagging: &s3types.Tagging{TagSet: s3BucketTags(tags)},
+			}); err != nil {
+				m.logger.Warn("Failed to apply default tags to S3 bucket (continuing anyway)", "bucketName", bucketName, "error", err)
+			}
+		}
 	} else {
 		m.logger.Info("S3 bucket already exists", "bucketName", bucketName)
 	}
@@ -230,7 +296,8 @@ func (m *S3LifecycleManager) CleanupS3Object(ctx context.Context, bucketName, ob
 	return nil
 }
 
-// UploadToS3 uploads a file to S3
+// UploadToS3 uploads a file to S3.
+// Files larger than the configured part size are sent as a parallel multipart upload.
 func (m *S3LifecycleManager) UploadToS3(ctx context.Context, bucketName, objectKey, filePath, region string) error {
 	m.logger.Info("Uploading to S3", "bucket", fmt.Sprintf("s3://%s/%s", bucketName, objectKey))
 
@@ -241,10 +308,17 @@ func (m *S3LifecycleManager) UploadToS3(ctx context.Context, bucketName, objectK
 	}
 	defer file.Close()
 
-	_, err = m.s3Client.PutObject(ctx, &s3.PutObjectInput{
-		Bucket: aws.String(bucketName),
-		Key:    aws.String(objectKey),
-		Body:   file,
+	partSize, concurrency := s3TransferSettings()
+	uploader := manager.NewUploader(m.s3Client, func(u *manager.Uploader) {
+		u.PartSize = partSize
+		u.Concurrency = concurrency
+	})
+
+	_, err = uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket:  aws.String(bucketName),
+		Key:     aws.String(objectKey),
+		Body:    file,
+		Tagging: s3ObjectTagging(defaultResourceTags()),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to upload file to S3: %w", err)
@@ -254,18 +328,29 @@ func (m *S3LifecycleManager) UploadToS3(ctx context.Context, bucketName, objectK
 	return nil
 }
 
-// DownloadFromS3 downloads a file from S3
-func (m *S3LifecycleManager) DownloadFromS3(ctx context.Context, bucketName, objectKey, filePath, region string) error {
-	m.logger.Info("Downloading from S3", "bucket", fmt.Sprintf("s3://%s/%s", bucketName, objectKey))
+// StreamFromS3 copies an S3 object to w sequentially, for destinations that cannot seek such as stdout
+func (m *S3LifecycleManager) StreamFromS3(ctx context.Context, bucketName, objectKey string, w io.Writer) error {
+	m.logger.Info("Streaming from S3", "bucket", fmt.Sprintf("s3://%s/%s", bucketName, objectKey))
 
-	result, err := m.s3Client.GetObject(ctx, &s3.GetObjectInput{
+	output, err := m.s3Client.GetObject(ctx, &s3.GetObjectInput{
 		Bucket: aws.String(bucketName),
 		Key:    aws.String(objectKey),
 	})
 	if err != nil {
-		return fmt.Errorf("failed to download file from S3: %w", err)
+		return fmt.Errorf("failed to get object from S3: %w", err)
+	}
+	defer output.Body.Close()
+
+	if _, err := io.Copy(w, output.Body); err != nil {
+		return fmt.Errorf("failed to stream object from S3: %w", err)
 	}
-	defer result.Body.Close()
+	return nil
+}
+
+// DownloadFromS3 downloads a file from S3.
+// Objects larger than the configured part size are fetched as parallel ranged GETs.
+func (m *S3LifecycleManager) DownloadFromS3(ctx context.Context, bucketName, objectKey, filePath, region string) error {
+	m.logger.Info("Downloading from S3", "bucket", fmt.Sprintf("s3://%s/%s", bucketName, objectKey))
 
 	// #nosec G304 - filePath is validated by caller using security.ValidateFilePathWithWorkingDir()
 	file, err := os.Create(filePath)
@@ -274,9 +359,20 @@ func (m *S3LifecycleManager) DownloadFromS3(ctx context.Context, bucketName, obj
 	}
 	defer file.Close()
 
-	_, err = io.Copy(file, result.Body)
+	partSize, concurrency := s3TransferSettings()
+	downloader := manager.NewDownloader(m.s3Client, func(d *manager.Downloader) {
+		d.PartSize = partSize
+		d.Concurrency = concurrency
+	})
+
+	_, err = downloader.Download(ctx, file, &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to write file content: %w", err)
+		_ = file.Close()
+		_ = os.Remove(filePath) // Don't leave a partial file behind
+		return fmt.Errorf("failed to download file from S3: %w", err)
 	}
 
 	m.logger.Info("Successfully downloaded from S3", "bucket", fmt.Sprintf("s3://%s/%s", bucketName, objectKey))
@@ -6,7 +6,12 @@ import (
 	"strings"
 	"testing"
 
+	appconfig "ztictl/internal/config"
 	"ztictl/pkg/logging"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
 
 func TestNewS3LifecycleManager(t *testing.T) {
@@ -683,3 +688,97 @@ func TestS3URIFormat(t *testing.T) {
 		t.Error("Bucket URI should end with bucket name")
 	}
 }
+
+func TestS3TransferSettings(t *testing.T) {
+	system := &appconfig.Get().System
+	original := *system
+	defer func() { *system = original }()
+
+	tests := []struct {
+		name            string
+		partSizeMB      int64
+		concurrency     int
+		wantPartSize    int64
+		wantConcurrency int
+	}{
+		{"unset uses defaults", 0, 0, DefaultS3PartSizeMB * 1024 * 1024, DefaultS3Concurrency},
+		{"configured values", 64, 10, 64 * 1024 * 1024, 10},
+		{"part size raised to S3 minimum", 1, 2, manager.MinUploadPartSize, 2},
+		{"negative values use defaults", -8, -1, DefaultS3PartSizeMB * 1024 * 1024, DefaultS3Concurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			system.S3PartSizeMB = tt.partSizeMB
+			system.S3Concurrency = tt.concurrency
+
+			partSize, concurrency := s3TransferSettings()
+			if partSize != tt.wantPartSize {
+				t.Errorf("part size = %d, want %d", partSize, tt.wantPartSize)
+			}
+			if concurrency != tt.wantConcurrency {
+				t.Errorf("concurrency = %d, want %d", concurrency, tt.wantConcurrency)
+			}
+		})
+	}
+}
+
+func TestLifecycleExpirationDaysFromConfig(t *testing.T) {
+	system := &appconfig.Get().System
+	original := *system
+	defer func() { *system = original }()
+
+	lifecycleManager := &S3LifecycleManager{}
+	for _, tt := range []struct {
+		configured int
+		want       int32
+	}{{0, DefaultExpirationDays}, {7, 7}, {-3, DefaultExpirationDays}} {
+		system.S3LifecycleDays = tt.configured
+		rule := lifecycleManager.createLifecycleConfiguration().Rules[0]
+		if got := aws.ToInt32(rule.Expiration.Days); got != tt.want {
+			t.Errorf("s3_lifecycle_days=%d: expiration = %d, want %d", tt.configured, got, tt.want)
+		}
+	}
+}
+
+func TestCheckLifecycleRules(t *testing.T) {
+	system := &appconfig.Get().System
+	original := *system
+	defer func() { *system = original }()
+	system.S3LifecycleDays = 3
+
+	rule := func(id string, status s3types.ExpirationStatus, days *int32) s3types.LifecycleRule {
+		r := s3types.LifecycleRule{ID: aws.String(id), Status: status}
+		if days != nil {
+			r.Expiration = &s3types.LifecycleExpiration{Days: days}
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		rules   []s3types.LifecycleRule
+		wantErr string
+	}{
+		{"matching rule", []s3types.LifecycleRule{rule("other", s3types.ExpirationStatusEnabled, nil), rule(LifecycleRuleID, s3types.ExpirationStatusEnabled, aws.Int32(3))}, ""},
+		{"rule missing", []s3types.LifecycleRule{rule("other", s3types.ExpirationStatusEnabled, aws.Int32(3))}, "not found"},
+		{"rule disabled", []s3types.LifecycleRule{rule(LifecycleRuleID, s3types.ExpirationStatusDisabled, aws.Int32(3))}, "not enabled"},
+		{"expiration drifted", []s3types.LifecycleRule{rule(LifecycleRuleID, s3types.ExpirationStatusEnabled, aws.Int32(30))}, "after 30 day(s), expected 3"},
+		{"expiration removed", []s3types.LifecycleRule{rule(LifecycleRuleID, s3types.ExpirationStatusEnabled, nil)}, "after 0 day(s), expected 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkLifecycleRules(tt.rules, "test-bucket")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
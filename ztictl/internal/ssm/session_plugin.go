@@ -0,0 +1,86 @@
+package ssm
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// sessionManagerPluginDocs is the AWS guide for installing the Session Manager plugin
+const sessionManagerPluginDocs = "https://docs.aws.amazon.com/systems-manager/latest/userguide/session-manager-working-with-install-plugin.html"
+
+// SessionManagerPluginBinary returns the platform-specific executable name of the Session Manager plugin
+func SessionManagerPluginBinary() string {
+	if runtime.GOOS == "windows" {
+		return "session-manager-plugin.exe"
+	}
+	return "session-manager-plugin"
+}
+
+// SetSessionManagerPluginPath makes a plugin installed outside PATH visible to the AWS CLI, which looks it
+// up by name. path may be the plugin executable or its directory; the directory is prepended to PATH of
+// this process, so every AWS CLI and ssh subprocess started afterwards inherits it. Empty is a no-op.
+func SetSessionManagerPluginPath(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("session manager plugin path %s: %w", path, err)
+	}
+	dir := path
+	if !info.IsDir() {
+		dir = filepath.Dir(path)
+	}
+	if _, err := os.Stat(filepath.Join(dir, SessionManagerPluginBinary())); err != nil {
+		return fmt.Errorf("%s not found in %s", SessionManagerPluginBinary(), dir)
+	}
+
+	return os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// CheckSessionManagerPlugin returns an error with install instructions when the plugin is not on PATH,
+// so commands fail with a useful hint instead of the AWS CLI's raw error
+func CheckSessionManagerPlugin() error {
+	if _, err := exec.LookPath(SessionManagerPluginBinary()); err != nil {
+		return fmt.Errorf("%s not found on PATH; it is required by the AWS CLI for sessions.\n%s\nIf it is installed elsewhere, set system.session_manager_plugin_path in ~/.ztictl.yaml",
+			SessionManagerPluginBinary(), SessionManagerPluginInstallHint())
+	}
+	return nil
+}
+
+// SessionManagerPluginInstallHint returns the download link for the current platform
+func SessionManagerPluginInstallHint() string {
+	return sessionManagerPluginInstallHint(runtime.GOOS, runtime.GOARCH)
+}
+
+// sessionManagerPluginInstallHint returns the download link of the plugin package for an OS and architecture
+func sessionManagerPluginInstallHint(goos, goarch string) string {
+	const base = "https://s3.amazonaws.com/session-manager-downloads/plugin/latest/"
+
+	switch goos {
+	case "windows":
+		return "Download the installer: " + base + "windows/SessionManagerPluginSetup.exe"
+	case "darwin":
+		if goarch == "arm64" {
+			return "Install with 'brew install --cask session-manager-plugin' or download: " + base + "mac_arm64/session-manager-plugin.pkg"
+		}
+		return "Install with 'brew install --cask session-manager-plugin' or download: " + base + "mac/session-manager-plugin.pkg"
+	case "linux":
+		arch := "64bit"
+		if goarch == "arm64" {
+			arch = "arm64"
+		}
+		return strings.Join([]string{
+			"Download the package for your distribution:",
+			"  Debian/Ubuntu: " + base + "ubuntu_" + arch + "/session-manager-plugin.deb",
+			"  RHEL/Amazon Linux: " + base + "linux_" + arch + "/session-manager-plugin.rpm",
+		}, "\n")
+	default:
+		return "Install instructions: " + sessionManagerPluginDocs
+	}
+}
@@ -0,0 +1,110 @@
+package ssm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFakePlugin creates an empty executable named like the plugin in dir
+func writeFakePlugin(t *testing.T, dir string) string {
+	t.Helper()
+	path := filepath.Join(dir, SessionManagerPluginBinary())
+	if err := os.WriteFile(path, nil, 0755); err != nil { // #nosec G306 - test executable
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSetSessionManagerPluginPath(t *testing.T) {
+	t.Run("empty is a no-op", func(t *testing.T) {
+		t.Setenv("PATH", "/usr/bin")
+		if err := SetSessionManagerPluginPath(""); err != nil {
+			t.Fatal(err)
+		}
+		if os.Getenv("PATH") != "/usr/bin" {
+			t.Errorf("PATH changed to %q", os.Getenv("PATH"))
+		}
+	})
+
+	t.Run("executable path", func(t *testing.T) {
+		dir := t.TempDir()
+		plugin := writeFakePlugin(t, dir)
+		t.Setenv("PATH", "/usr/bin")
+
+		if err := SetSessionManagerPluginPath(plugin); err != nil {
+			t.Fatal(err)
+		}
+		if want := dir + string(os.PathListSeparator) + "/usr/bin"; os.Getenv("PATH") != want {
+			t.Errorf("PATH = %q, want %q", os.Getenv("PATH"), want)
+		}
+		if err := CheckSessionManagerPlugin(); err != nil {
+			t.Errorf("plugin should be found after setting its path: %v", err)
+		}
+	})
+
+	t.Run("directory path", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFakePlugin(t, dir)
+		t.Setenv("PATH", "/usr/bin")
+
+		if err := SetSessionManagerPluginPath(dir); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(os.Getenv("PATH"), dir+string(os.PathListSeparator)) {
+			t.Errorf("PATH = %q, want it to start with %s", os.Getenv("PATH"), dir)
+		}
+	})
+
+	t.Run("directory without the plugin", func(t *testing.T) {
+		t.Setenv("PATH", "/usr/bin")
+		if err := SetSessionManagerPluginPath(t.TempDir()); err == nil {
+			t.Error("expected an error for a directory without the plugin")
+		}
+		if os.Getenv("PATH") != "/usr/bin" {
+			t.Errorf("PATH changed to %q", os.Getenv("PATH"))
+		}
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		if err := SetSessionManagerPluginPath(filepath.Join(t.TempDir(), "missing")); err == nil {
+			t.Error("expected an error for a missing path")
+		}
+	})
+}
+
+func TestCheckSessionManagerPluginMissing(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	err := CheckSessionManagerPlugin()
+	if err == nil {
+		t.Fatal("expected an error when the plugin is not on PATH")
+	}
+	if !strings.Contains(err.Error(), "session_manager_plugin_path") {
+		t.Errorf("error should point at the config option: %v", err)
+	}
+}
+
+func TestSessionManagerPluginInstallHint(t *testing.T) {
+	tests := []struct {
+		goos, goarch string
+		want         []string
+	}{
+		{"linux", "amd64", []string{"ubuntu_64bit/session-manager-plugin.deb", "linux_64bit/session-manager-plugin.rpm"}},
+		{"linux", "arm64", []string{"ubuntu_arm64/session-manager-plugin.deb", "linux_arm64/session-manager-plugin.rpm"}},
+		{"darwin", "amd64", []string{"mac/session-manager-plugin.pkg", "brew install"}},
+		{"darwin", "arm64", []string{"mac_arm64/session-manager-plugin.pkg"}},
+		{"windows", "amd64", []string{"windows/SessionManagerPluginSetup.exe"}},
+		{"freebsd", "amd64", []string{sessionManagerPluginDocs}},
+	}
+
+	for _, tt := range tests {
+		hint := sessionManagerPluginInstallHint(tt.goos, tt.goarch)
+		for _, want := range tt.want {
+			if !strings.Contains(hint, want) {
+				t.Errorf("%s/%s hint %q does not contain %q", tt.goos, tt.goarch, hint, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	appconfig "ztictl/internal/config"
+	"ztictl/pkg/format"
+	"ztictl/pkg/security"
+)
+
+// UploadOptions controls optional behaviour of file uploads
+type UploadOptions struct {
+	// PreserveMode applies the local permission bits to the uploaded file. Windows instances
+	// only receive the read-only attribute.
+	PreserveMode bool
+	// Resume keeps the S3 multipart upload of a large file open when it fails, and continues a
+	// recorded incomplete upload of the same unchanged file instead of starting over
+	Resume bool
+}
+
+// LocalFile is a regular file found under a local directory by ListLocalDirectory
+type LocalFile struct {
+	Path      string // Relative to the listed directory, with forward slashes
+	LocalPath string // Path on the local machine
+	Size      int64
+	Mode      os.FileMode
+}
+
+// ListLocalDirectory lists every regular file under localDir, recursively, sorted by relative path.
+// Symbolic links are skipped unless followSymlinks is set; skipped links, broken links and special
+// files such as sockets are returned separately so the caller can report them. Followed directory
+// links that lead back into a directory already being walked are skipped to avoid loops.
+func ListLocalDirectory(localDir string, followSymlinks bool) (files []LocalFile, skipped []string, err error) {
+	info, err := os.Stat(localDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("local directory not found: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, nil, fmt.Errorf("local path %s is not a directory", localDir)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(localDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve local directory: %w", err)
+	}
+
+	var walk func(dir, relDir string, active map[string]bool) error
+	walk = func(dir, relDir string, active map[string]bool) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("failed to read local directory %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			entryPath := filepath.Join(dir, entry.Name())
+			relPath := path.Join(relDir, entry.Name())
+
+			mode := entry.Type()
+			if mode&os.ModeSymlink != 0 {
+				if !followSymlinks {
+					skipped = append(skipped, relPath)
+					continue
+				}
+				target, err := os.Stat(entryPath)
+				if err != nil {
+					// Broken link
+					skipped = append(skipped, relPath)
+					continue
+				}
+				mode = target.Mode().Type()
+			}
+
+			switch {
+			case mode.IsDir():
+				realDir, err := filepath.EvalSymlinks(entryPath)
+				if err != nil || active[realDir] {
+					skipped = append(skipped, relPath)
+					continue
+				}
+				active[realDir] = true
+				if err := walk(entryPath, relPath, active); err != nil {
+					return err
+				}
+				delete(active, realDir)
+			case mode.IsRegular():
+				fileInfo, err := os.Stat(entryPath)
+				if err != nil {
+					return fmt.Errorf("cannot access local file %s: %w", entryPath, err)
+				}
+				files = append(files, LocalFile{Path: relPath, LocalPath: entryPath, Size: fileInfo.Size(), Mode: fileInfo.Mode()})
+			default:
+				skipped = append(skipped, relPath)
+			}
+		}
+		return nil
+	}
+
+	if err := walk(localDir, "", map[string]bool{realRoot: true}); err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	sort.Strings(skipped)
+	return files, skipped, nil
+}
+
+// UploadDirectory uploads files listed by ListLocalDirectory under remoteDir, preserving their
+// relative paths. Each file uses the S3 transfer path when it exceeds the size threshold.
+// Failed files do not stop the upload; they are reported together in the returned error.
+func (m *Manager) UploadDirectory(ctx context.Context, instanceIdentifier, region, remoteDir string, files []LocalFile, opts UploadOptions) error {
+	instanceID, err := m.resolveInstanceIdentifier(ctx, instanceIdentifier, region)
+	if err != nil {
+		return fmt.Errorf("failed to resolve instance: %w", err)
+	}
+
+	if err := m.initializePlatformComponents(ctx, region); err != nil {
+		return fmt.Errorf("failed to initialize platform components: %w", err)
+	}
+	builder, err := m.builderManager.GetBuilder(ctx, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to get command builder: %w", err)
+	}
+
+	// Create the remote tree in one command; S3 transfers do not create parent directories on Windows
+	dirs := map[string]bool{remoteDir: true}
+	for _, file := range files {
+		dirs[remoteFilePath(remoteDir, path.Dir(file.Path))] = true
+	}
+	commands := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		commands = append(commands, builder.BuildDirectoryCreateCommand(dir))
+	}
+	sort.Strings(commands)
+	result, err := m.ExecuteCommand(ctx, instanceID, region, strings.Join(commands, "\n"), "Create directories via ztictl")
+	if err != nil {
+		return fmt.Errorf("failed to create remote directories: %w", err)
+	}
+	if result.Status != "Success" {
+		return fmt.Errorf("failed to create remote directories: %s", result.ErrorOutput)
+	}
+
+	cfg := appconfig.Get()
+	var failed []string
+	for _, file := range files {
+		if err := security.ValidateFilePathWithWorkingDir(file.LocalPath); err != nil {
+			return fmt.Errorf("unsafe file path %s: %w", file.LocalPath, err)
+		}
+		remotePath := remoteFilePath(remoteDir, file.Path)
+
+		m.logger.Info("Uploading file to instance", "instanceID", instanceID, "localPath", file.LocalPath, "remotePath", remotePath, "size", format.Bytes(file.Size))
+
+		startTime := time.Now()
+		if file.Size < cfg.System.FileSizeThreshold {
+			err = m.uploadFileSmall(ctx, instanceID, region, file.LocalPath, remotePath)
+		} else {
+			err = m.uploadFileLarge(ctx, instanceID, region, file.LocalPath, remotePath, opts)
+		}
+		if err == nil && opts.PreserveMode {
+			err = m.applyFileMode(ctx, instanceID, region, remotePath, file.Mode)
+		}
+		recordTransfer("upload", region, file.Size, startTime, err)
+		if err != nil {
+			m.logger.Error("Failed to upload file", "localPath", file.LocalPath, "error", err)
+			failed = append(failed, file.Path)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to upload %d of %d files: %s", len(failed), len(files), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// applyFileMode sets the permission bits of an uploaded file to match the local file
+func (m *Manager) applyFileMode(ctx context.Context, instanceID, region, remotePath string, mode os.FileMode) error {
+	if err := m.initializePlatformComponents(ctx, region); err != nil {
+		return fmt.Errorf("failed to initialize platform components: %w", err)
+	}
+	builder, err := m.builderManager.GetBuilder(ctx, instanceID)
+	if err != nil {
+		return fmt.Errorf("failed to get command builder: %w", err)
+	}
+
+	result, err := m.ExecuteCommand(ctx, instanceID, region, builder.BuildFileModeCommand(remotePath, mode), "Set file mode via ztictl")
+	if err != nil {
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if result.Status != "Success" {
+		return fmt.Errorf("failed to set file mode: %s", result.ErrorOutput)
+	}
+	return nil
+}
+
+// remoteFilePath joins a relative path with forward slashes onto a remote directory, keeping the
+// directory's separator style so Windows paths stay backslash-separated
+func remoteFilePath(remoteDir, relPath string) string {
+	if relPath == "" || relPath == "." {
+		return remoteDir
+	}
+	separator := "/"
+	if strings.Contains(remoteDir, `\`) && !strings.Contains(remoteDir, "/") {
+		separator = `\`
+		relPath = strings.ReplaceAll(relPath, "/", `\`)
+	}
+	return strings.TrimRight(remoteDir, `/\`) + separator + relPath
+}
@@ -0,0 +1,95 @@
+package ssm
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestListLocalDirectory(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links needs extra privileges on Windows")
+	}
+	dir := t.TempDir()
+	outside := t.TempDir()
+	mustWrite := func(path string, mode os.FileMode) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("data"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustLink := func(target, link string) {
+		t.Helper()
+		if err := os.Symlink(target, link); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mustWrite(filepath.Join(dir, "run.sh"), 0700)
+	mustWrite(filepath.Join(dir, "conf", "app.conf"), 0600)
+	mustWrite(filepath.Join(outside, "shared", "lib.sh"), 0600)
+	mustLink(filepath.Join(dir, "run.sh"), filepath.Join(dir, "start.sh"))
+	mustLink(filepath.Join(outside, "shared"), filepath.Join(dir, "shared"))
+	mustLink(filepath.Join(dir, "missing"), filepath.Join(dir, "broken"))
+	mustLink(dir, filepath.Join(dir, "conf", "loop"))
+
+	paths := func(files []LocalFile) []string {
+		var result []string
+		for _, file := range files {
+			result = append(result, file.Path)
+		}
+		return result
+	}
+
+	files, skipped, err := ListLocalDirectory(dir, false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, want := paths(files), []string{"conf/app.conf", "run.sh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected files %v without following links, got %v", want, got)
+	}
+	if want := []string{"broken", "conf/loop", "shared", "start.sh"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("Expected skipped links %v, got %v", want, skipped)
+	}
+	if files[1].Mode.Perm() != 0700 || files[1].Size != 4 {
+		t.Errorf("Expected run.sh with mode 0700 and size 4, got %v and %d", files[1].Mode, files[1].Size)
+	}
+
+	files, skipped, err = ListLocalDirectory(dir, true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, want := paths(files), []string{"conf/app.conf", "run.sh", "shared/lib.sh", "start.sh"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected files %v when following links, got %v", want, got)
+	}
+	if want := []string{"broken", "conf/loop"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("Expected the broken link and the loop to be skipped, got %v", skipped)
+	}
+
+	if _, _, err := ListLocalDirectory(filepath.Join(dir, "run.sh"), false); err == nil {
+		t.Error("Expected an error for a file")
+	}
+}
+
+func TestRemoteFilePath(t *testing.T) {
+	tests := []struct {
+		dir, rel, want string
+	}{
+		{"/opt/deploy", "bin/run.sh", "/opt/deploy/bin/run.sh"},
+		{"/opt/deploy/", "run.sh", "/opt/deploy/run.sh"},
+		{"/", "run.sh", "/run.sh"},
+		{"/opt/deploy", ".", "/opt/deploy"},
+		{`C:\deploy`, "bin/run.ps1", `C:\deploy\bin\run.ps1`},
+		{"C:/deploy", "bin/run.ps1", "C:/deploy/bin/run.ps1"},
+	}
+	for _, tt := range tests {
+		if got := remoteFilePath(tt.dir, tt.rel); got != tt.want {
+			t.Errorf("remoteFilePath(%q, %q) = %q, want %q", tt.dir, tt.rel, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package ssm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"ztictl/pkg/security"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// maxMultipartParts is the S3 limit on parts per multipart upload
+	maxMultipartParts = 10000
+	// partUploadAttempts is how many times one part is sent before the upload is given up for this run
+	partUploadAttempts = 3
+	// pendingUploadsFile holds incomplete multipart uploads under ~/.ztictl/transfers
+	pendingUploadsFile = "pending-uploads.json"
+)
+
+// partRetryDelay is the wait before re-sending a failed part, multiplied by the attempt; tests shorten it
+var partRetryDelay = 2 * time.Second
+
+// multipartAPI is the subset of the S3 API used for resumable multipart uploads
+type multipartAPI interface {
+	CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	ListParts(ctx context.Context, params *s3.ListPartsInput, optFns ...func(*s3.Options)) (*s3.ListPartsOutput, error)
+	CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error)
+}
+
+// PendingUpload records an incomplete multipart upload of a local file so a later run can continue it
+type PendingUpload struct {
+	Bucket    string    `json:"bucket"`
+	Key       string    `json:"key"`
+	UploadID  string    `json:"upload_id"`
+	LocalPath string    `json:"local_path"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"mod_time"`
+	PartSize  int64     `json:"part_size"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// matches reports whether the upload was started for the same, unchanged local file
+func (p PendingUpload) matches(bucket, localPath string, info os.FileInfo) bool {
+	return p.Bucket == bucket && p.LocalPath == localPath && p.Size == info.Size() && p.ModTime.Equal(info.ModTime())
+}
+
+// uploadStateStore persists pending multipart uploads in a small JSON file
+type uploadStateStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// defaultUploadStatePath returns ~/.ztictl/transfers/pending-uploads.json
+func defaultUploadStatePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".ztictl", "transfers", pendingUploadsFile), nil
+}
+
+// newUploadStateStore returns a store backed by path, or by the default path when empty
+func newUploadStateStore(path string) (*uploadStateStore, error) {
+	if path == "" {
+		defaultPath, err := defaultUploadStatePath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	if security.ContainsUnsafePath(path) {
+		return nil, fmt.Errorf("unsafe upload state path: %s", path)
+	}
+	return &uploadStateStore{path: filepath.Clean(path)}, nil
+}
+
+// load reads the pending uploads, dropping those the bucket lifecycle rule has already aborted
+func (s *uploadStateStore) load() ([]PendingUpload, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload state: %w", err)
+	}
+
+	var uploads []PendingUpload
+	if err := json.Unmarshal(data, &uploads); err != nil {
+		return nil, fmt.Errorf("failed to parse upload state %s: %w", s.path, err)
+	}
+	cutoff := time.Now().AddDate(0, 0, -DefaultAbortUploadDays)
+	live := uploads[:0]
+	for _, upload := range uploads {
+		if upload.StartedAt.After(cutoff) {
+			live = append(live, upload)
+		}
+	}
+	return live, nil
+}
+
+// write replaces the state file, creating its directory as needed
+func (s *uploadStateStore) write(uploads []PendingUpload) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("failed to create upload state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(uploads, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode upload state: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write upload state: %w", err)
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// find returns the pending upload of an unchanged local file to a bucket
+func (s *uploadStateStore) find(bucket, localPath string, info os.FileInfo) (PendingUpload, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uploads, err := s.load()
+	if err != nil {
+		return PendingUpload{}, false
+	}
+	for _, upload := range uploads {
+		if upload.matches(bucket, localPath, info) {
+			return upload, true
+		}
+	}
+	return PendingUpload{}, false
+}
+
+// save records a pending upload, replacing any earlier one of the same local file to the same bucket
+func (s *uploadStateStore) save(pending PendingUpload) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uploads, err := s.load()
+	if err != nil {
+		return err
+	}
+	kept := uploads[:0]
+	for _, upload := range uploads {
+		if upload.Bucket != pending.Bucket || upload.LocalPath != pending.LocalPath {
+			kept = append(kept, upload)
+		}
+	}
+	return s.write(append(kept, pending))
+}
+
+// remove forgets the pending upload of an object
+func (s *uploadStateStore) remove(bucket, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	uploads, err := s.load()
+	if err != nil {
+		return err
+	}
+	kept := uploads[:0]
+	for _, upload := range uploads {
+		if upload.Bucket != bucket || upload.Key != key {
+			kept = append(kept, upload)
+		}
+	}
+	return s.write(kept)
+}
+
+// resumeKey returns the S3 key of an incomplete upload of localPath to the bucket, if one is recorded.
+// Reusing that key lets the upload continue the parts already in S3.
+func resumeKey(store *uploadStateStore, bucket, localPath string) (string, bool) {
+	absPath, err := filepath.Abs(localPath)
+	if err != nil {
+		return "", false
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		return "", false
+	}
+	pending, ok := store.find(bucket, absPath, info)
+	return pending.Key, ok
+}
+
+// PendingUploadKey returns the S3 key of an incomplete upload of localPath to the bucket, so a
+// retried transfer can reuse it and continue the upload
+func (m *S3LifecycleManager) PendingUploadKey(bucketName, localPath string) (string, bool) {
+	store, err := newUploadStateStore("")
+	if err != nil {
+		return "", false
+	}
+	return resumeKey(store, bucketName, localPath)
+}
+
+// UploadToS3Resumable uploads a file to S3 like UploadToS3, but keeps an interrupted multipart upload
+// open and recorded in ~/.ztictl/transfers so the next run continues it instead of starting over.
+// Files that fit in one part are uploaded with UploadToS3.
+func (m *S3LifecycleManager) UploadToS3Resumable(ctx context.Context, bucketName, objectKey, filePath, region string) error {
+	partSize, concurrency := s3TransferSettings()
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to stat file %s: %w", filePath, err)
+	}
+	if info.Size() <= partSize {
+		return m.UploadToS3(ctx, bucketName, objectKey, filePath, region)
+	}
+
+	store, err := newUploadStateStore("")
+	if err != nil {
+		return err
+	}
+
+	m.logger.Info("Uploading to S3 (resumable)", "bucket", fmt.Sprintf("s3://%s/%s", bucketName, objectKey))
+	if err := resumableUpload(ctx, m.s3Client, store, bucketName, objectKey, filePath, partSize, concurrency); err != nil {
+		return fmt.Errorf("failed to upload file to S3: %w", err)
+	}
+	m.logger.Info("Successfully uploaded to S3", "bucket", fmt.Sprintf("s3://%s/%s", bucketName, objectKey))
+	return nil
+}
+
+// multipartPartSize returns the part size for a file, raised when needed to stay within the S3 part limit
+func multipartPartSize(size, partSize int64) int64 {
+	if minimum := (size + maxMultipartParts - 1) / maxMultipartParts; partSize < minimum {
+		return minimum
+	}
+	return partSize
+}
+
+// resumableUpload sends a file as a multipart upload whose ID is kept in store until it completes. When
+// the store holds an unfinished upload of the same unchanged file to the same key, the parts S3 already
+// has are skipped. A failed upload is left open for the next run; the bucket lifecycle rule aborts it
+// after a day if it is never resumed.
+func resumableUpload(ctx context.Context, api multipartAPI, store *uploadStateStore, bucket, key, filePath string, partSize int64, concurrency int) error {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return fmt.Errorf("invalid local file path: %w", err)
+	}
+	// #nosec G304 - filePath is validated by caller using security.ValidateFilePathWithWorkingDir()
+	file, err := os.Open(absPath)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %w", filePath, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file %s: %w", filePath, err)
+	}
+
+	partSize = multipartPartSize(info.Size(), partSize)
+	partCount := int32((info.Size() + partSize - 1) / partSize)
+
+	var pending PendingUpload
+	var uploaded map[int32]s3types.CompletedPart
+	if previous, ok := store.find(bucket, absPath, info); ok && previous.Key == key && previous.PartSize == partSize {
+		if uploaded, err = listUploadedParts(ctx, api, previous, partSize, info.Size()); err == nil {
+			pending = previous
+		}
+	}
+
+	if pending.UploadID == "" {
+		created, err := api.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+			Bucket:            aws.String(bucket),
+			Key:               aws.String(key),
+			ChecksumAlgorithm: s3types.ChecksumAlgorithmCrc32,
+			Tagging:           s3ObjectTagging(defaultResourceTags()),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start multipart upload: %w", err)
+		}
+		pending = PendingUpload{
+			Bucket:    bucket,
+			Key:       key,
+			UploadID:  aws.ToString(created.UploadId),
+			LocalPath: absPath,
+			Size:      info.Size(),
+			ModTime:   info.ModTime(),
+			PartSize:  partSize,
+			StartedAt: time.Now(),
+		}
+		uploaded = map[int32]s3types.CompletedPart{}
+		if err := store.save(pending); err != nil {
+			return fmt.Errorf("failed to record multipart upload for resume: %w", err)
+		}
+	}
+
+	var mu sync.Mutex
+	var firstErr error
+	semaphore := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	for partNumber := int32(1); partNumber <= partCount; partNumber++ {
+		if _, done := uploaded[partNumber]; done {
+			continue
+		}
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(partNumber int32) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			offset := int64(partNumber-1) * partSize
+			part, err := uploadPart(ctx, api, pending, partNumber, io.NewSectionReader(file, offset, min(partSize, info.Size()-offset)))
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			uploaded[partNumber] = part
+		}(partNumber)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return fmt.Errorf("multipart upload incomplete (%d of %d parts in S3; run the upload again to resume): %w", len(uploaded), partCount, firstErr)
+	}
+
+	parts := make([]s3types.CompletedPart, 0, len(uploaded))
+	for _, part := range uploaded {
+		parts = append(parts, part)
+	}
+	sort.Slice(parts, func(i, j int) bool { return aws.ToInt32(parts[i].PartNumber) < aws.ToInt32(parts[j].PartNumber) })
+
+	if _, err := api.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(pending.UploadID),
+		MultipartUpload: &s3types.CompletedMultipartUpload{Parts: parts},
+	}); err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+
+	if err := store.remove(bucket, key); err != nil {
+		return fmt.Errorf("upload completed but its resume state could not be cleared: %w", err)
+	}
+	return nil
+}
+
+// uploadPart sends one part, retrying transient failures a few times before giving up
+func uploadPart(ctx context.Context, api multipartAPI, pending PendingUpload, partNumber int32, body *io.SectionReader) (s3types.CompletedPart, error) {
+	var err error
+	for attempt := 1; attempt <= partUploadAttempts; attempt++ {
+		if attempt > 1 {
+			if _, seekErr := body.Seek(0, io.SeekStart); seekErr != nil {
+				return s3types.CompletedPart{}, seekErr
+			}
+			if sleepErr := sleepWithContext(ctx, partRetryDelay*time.Duration(attempt-1)); sleepErr != nil {
+				return s3types.CompletedPart{}, sleepErr
+			}
+		}
+
+		var output *s3.UploadPartOutput
+		output, err = api.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:            aws.String(pending.Bucket),
+			Key:               aws.String(pending.Key),
+			UploadId:          aws.String(pending.UploadID),
+			PartNumber:        aws.Int32(partNumber),
+			Body:              body,
+			ChecksumAlgorithm: s3types.ChecksumAlgorithmCrc32,
+		})
+		if err == nil {
+			return s3types.CompletedPart{
+				PartNumber:    aws.Int32(partNumber),
+				ETag:          output.ETag,
+				ChecksumCRC32: output.ChecksumCRC32,
+			}, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return s3types.CompletedPart{}, fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+}
+
+// listUploadedParts returns the parts S3 already holds for a pending upload, keeping only those of the
+// expected size. It fails when the upload no longer exists, e.g. after the lifecycle rule aborted it.
+func listUploadedParts(ctx context.Context, api multipartAPI, pending PendingUpload, partSize, fileSize int64) (map[int32]s3types.CompletedPart, error) {
+	parts := map[int32]s3types.CompletedPart{}
+	input := &s3.ListPartsInput{
+		Bucket:   aws.String(pending.Bucket),
+		Key:      aws.String(pending.Key),
+		UploadId: aws.String(pending.UploadID),
+	}
+	for {
+		output, err := api.ListParts(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list uploaded parts: %w", err)
+		}
+		for _, part := range output.Parts {
+			number := aws.ToInt32(part.PartNumber)
+			offset := int64(number-1) * partSize
+			if number < 1 || offset >= fileSize || aws.ToInt64(part.Size) != min(partSize, fileSize-offset) {
+				continue
+			}
+			parts[number] = s3types.CompletedPart{
+				PartNumber:    part.PartNumber,
+				ETag:          part.ETag,
+				ChecksumCRC32: part.ChecksumCRC32,
+			}
+		}
+		if !aws.ToBool(output.IsTruncated) {
+			return parts, nil
+		}
+		input.PartNumberMarker = output.NextPartNumberMarker
+	}
+}
@@ -0,0 +1,207 @@
+package ssm
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// fakeMultipartAPI keeps multipart uploads in memory and can fail chosen parts
+type fakeMultipartAPI struct {
+	mu        sync.Mutex
+	uploads   map[string]map[int32][]byte // upload ID to part contents
+	created   int
+	sent      []int32           // part numbers passed to UploadPart, in call order
+	failParts map[int32]bool    // parts whose upload always fails
+	completed map[string][]byte // object key to completed content
+}
+
+func newFakeMultipartAPI() *fakeMultipartAPI {
+	return &fakeMultipartAPI{uploads: map[string]map[int32][]byte{}, failParts: map[int32]bool{}, completed: map[string][]byte{}}
+}
+
+func (f *fakeMultipartAPI) CreateMultipartUpload(ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CreateMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created++
+	id := fmt.Sprintf("upload-%d", f.created)
+	f.uploads[id] = map[int32][]byte{}
+	return &s3.CreateMultipartUploadOutput{UploadId: aws.String(id)}, nil
+}
+
+func (f *fakeMultipartAPI) UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	number := aws.ToInt32(params.PartNumber)
+	f.sent = append(f.sent, number)
+	if f.failParts[number] {
+		return nil, fmt.Errorf("connection reset")
+	}
+	data := make([]byte, 64)
+	n, _ := params.Body.Read(data)
+	f.uploads[aws.ToString(params.UploadId)][number] = data[:n]
+	return &s3.UploadPartOutput{ETag: aws.String(fmt.Sprintf("etag-%d", number))}, nil
+}
+
+func (f *fakeMultipartAPI) ListParts(ctx context.Context, params *s3.ListPartsInput, optFns ...func(*s3.Options)) (*s3.ListPartsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	parts, ok := f.uploads[aws.ToString(params.UploadId)]
+	if !ok {
+		return nil, fmt.Errorf("NoSuchUpload")
+	}
+	output := &s3.ListPartsOutput{}
+	for number, data := range parts {
+		output.Parts = append(output.Parts, s3types.Part{PartNumber: aws.Int32(number), Size: aws.Int64(int64(len(data))), ETag: aws.String(fmt.Sprintf("etag-%d", number))})
+	}
+	return output, nil
+}
+
+func (f *fakeMultipartAPI) CompleteMultipartUpload(ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options)) (*s3.CompleteMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	parts := f.uploads[aws.ToString(params.UploadId)]
+	var content []byte
+	for i, part := range params.MultipartUpload.Parts {
+		if aws.ToInt32(part.PartNumber) != int32(i+1) {
+			return nil, fmt.Errorf("parts out of order")
+		}
+		content = append(content, parts[int32(i+1)]...)
+	}
+	f.completed[aws.ToString(params.Key)] = content
+	delete(f.uploads, aws.ToString(params.UploadId))
+	return &s3.CompleteMultipartUploadOutput{}, nil
+}
+
+func TestResumableUpload(t *testing.T) {
+	originalDelay := partRetryDelay
+	partRetryDelay = time.Millisecond
+	defer func() { partRetryDelay = originalDelay }()
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "bundle.tar")
+	content := "aaaabbbbccccdd"
+	if err := os.WriteFile(file, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	store, err := newUploadStateStore(filepath.Join(dir, "state", pendingUploadsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	api := newFakeMultipartAPI()
+	api.failParts[2] = true
+	err = resumableUpload(context.Background(), api, store, "bucket", "uploads/key", file, 4, 1)
+	if err == nil || !strings.Contains(err.Error(), "run the upload again to resume") {
+		t.Fatalf("Expected an incomplete upload error, got %v", err)
+	}
+	if key, ok := resumeKey(store, "bucket", file); !ok || key != "uploads/key" {
+		t.Fatalf("Expected the pending upload to be recorded, got %q (%v)", key, ok)
+	}
+	attempts := 0
+	for _, number := range api.sent {
+		if number == 2 {
+			attempts++
+		}
+	}
+	if attempts != partUploadAttempts {
+		t.Errorf("Expected part 2 to be tried %d times, got %v", partUploadAttempts, api.sent)
+	}
+
+	// The second run continues the same upload and sends only the parts S3 does not have;
+	// part 3 was already running when part 2 failed, part 4 was never started
+	delete(api.failParts, 2)
+	api.sent = nil
+	if err := resumableUpload(context.Background(), api, store, "bucket", "uploads/key", file, 4, 2); err != nil {
+		t.Fatalf("Unexpected error resuming: %v", err)
+	}
+	sort.Slice(api.sent, func(i, j int) bool { return api.sent[i] < api.sent[j] })
+	if api.created != 1 || fmt.Sprint(api.sent) != "[2 4]" {
+		t.Errorf("Expected one upload resumed from part 2, got %d uploads and parts %v", api.created, api.sent)
+	}
+	if got := string(api.completed["uploads/key"]); got != content {
+		t.Errorf("Expected completed content %q, got %q", content, got)
+	}
+	if _, ok := resumeKey(store, "bucket", file); ok {
+		t.Error("Expected the resume state to be cleared after completion")
+	}
+}
+
+func TestResumableUploadStartsOverWhenUploadIsGone(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "bundle.tar")
+	if err := os.WriteFile(file, []byte("aaaabbbb"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(file)
+	store, _ := newUploadStateStore(filepath.Join(dir, pendingUploadsFile))
+	absPath, _ := filepath.Abs(file)
+	if err := store.save(PendingUpload{Bucket: "bucket", Key: "uploads/key", UploadID: "aborted", LocalPath: absPath, Size: info.Size(), ModTime: info.ModTime(), PartSize: 4, StartedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	api := newFakeMultipartAPI()
+	if err := resumableUpload(context.Background(), api, store, "bucket", "uploads/key", file, 4, 2); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if api.created != 1 || string(api.completed["uploads/key"]) != "aaaabbbb" {
+		t.Errorf("Expected a fresh upload after the recorded one was aborted, got %d uploads", api.created)
+	}
+}
+
+func TestUploadStateStore(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "bundle.tar")
+	if err := os.WriteFile(file, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	info, _ := os.Stat(file)
+	store, _ := newUploadStateStore(filepath.Join(dir, pendingUploadsFile))
+
+	fresh := PendingUpload{Bucket: "bucket", Key: "fresh", LocalPath: file, Size: info.Size(), ModTime: info.ModTime(), StartedAt: time.Now()}
+	expired := PendingUpload{Bucket: "bucket", Key: "expired", LocalPath: "/other", StartedAt: time.Now().AddDate(0, 0, -2)}
+	if err := store.write([]PendingUpload{fresh, expired}); err != nil {
+		t.Fatal(err)
+	}
+
+	uploads, err := store.load()
+	if err != nil || len(uploads) != 1 || uploads[0].Key != "fresh" {
+		t.Errorf("Expected uploads past the lifecycle abort to be dropped, got %+v (%v)", uploads, err)
+	}
+	if _, ok := store.find("other-bucket", file, info); ok {
+		t.Error("Expected no match for another bucket")
+	}
+
+	// A modified file no longer matches its pending upload
+	if err := os.WriteFile(file, []byte("changed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	changed, _ := os.Stat(file)
+	if _, ok := store.find("bucket", file, changed); ok {
+		t.Error("Expected a changed file not to match")
+	}
+
+	if stat, err := os.Stat(store.path); err != nil || stat.Mode().Perm() != 0600 {
+		t.Errorf("Expected the state file to be private, got %v (%v)", stat, err)
+	}
+}
+
+func TestMultipartPartSize(t *testing.T) {
+	if got := multipartPartSize(100<<20, 16<<20); got != 16<<20 {
+		t.Errorf("Expected the configured part size, got %d", got)
+	}
+	size := int64(maxMultipartParts)*(16<<20) + 1
+	if got := multipartPartSize(size, 16<<20); (size+got-1)/got > maxMultipartParts {
+		t.Errorf("Expected at most %d parts, got part size %d", maxMultipartParts, got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	awspkg "ztictl/pkg/aws"
 	"ztictl/pkg/logging"
 
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -169,7 +170,7 @@ func (c *RequirementsChecker) checkAWSCredentials() RequirementResult {
 	result := RequirementResult{Name: "AWS Credentials"}
 
 	ctx := context.Background()
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := config.LoadDefaultConfig(ctx, awspkg.LoadOptions(ctx, "")...)
 	if err != nil {
 		result.Error = "Failed to load AWS configuration"
 		result.Suggestion = "Configure AWS credentials using 'aws configure' or 'ztictl auth login'"
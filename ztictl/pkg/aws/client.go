@@ -2,6 +2,8 @@ package aws
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 
 	"ztictl/pkg/errors"
 
@@ -30,13 +32,119 @@ type ClientOptions struct {
 	Profile string
 }
 
+// endpointURL sends every AWS service client ztictl creates to a custom endpoint, such as
+// LocalStack; empty leaves endpoint resolution to the SDK (which honors AWS_ENDPOINT_URL)
+var endpointURL string
+
+// SetEndpointURL sets the endpoint override for AWS clients created after the call.
+// It is set once at startup from --aws-endpoint-url.
+func SetEndpointURL(rawURL string) error {
+	if rawURL != "" {
+		parsed, err := url.Parse(rawURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return &ValidationError{Field: "AWS endpoint URL", Value: rawURL, Message: "must be an http:// or https:// URL with a host"}
+		}
+	}
+	endpointURL = rawURL
+	return nil
+}
+
+// useDualStack and useFIPS select the dualstack (IPv6) and FIPS variants of the AWS service endpoints.
+// Both off leaves the standard endpoints, or whatever the shared config and AWS_USE_* variables choose.
+var (
+	useDualStack bool
+	useFIPS      bool
+)
+
+// SetEndpointVariants enables dualstack and/or FIPS endpoints for AWS clients created after the call.
+// It is set once at startup from --dualstack/--fips and the system config.
+func SetEndpointVariants(dualStack, fips bool) {
+	useDualStack = dualStack
+	useFIPS = fips
+}
+
+// EndpointLoadOptions returns the config load options that apply the endpoint override and variants, if any
+func EndpointLoadOptions() []func(*config.LoadOptions) error {
+	var opts []func(*config.LoadOptions) error
+	if endpointURL != "" {
+		opts = append(opts, config.WithBaseEndpoint(endpointURL))
+	}
+	if useDualStack {
+		opts = append(opts, config.WithUseDualStackEndpoint(aws.DualStackEndpointStateEnabled))
+	}
+	if useFIPS {
+		opts = append(opts, config.WithUseFIPSEndpoint(aws.FIPSEndpointStateEnabled))
+	}
+	return opts
+}
+
+// retryMaxAttempts and retryMode tune the SDK's retries of each AWS API request. Zero and empty keep
+// the SDK defaults (standard mode with 3 attempts, unless AWS_MAX_ATTEMPTS or AWS_RETRY_MODE is set).
+var (
+	retryMaxAttempts int
+	retryMode        aws.RetryMode
+)
+
+// SetRetryOptions sets the SDK retry attempts and mode (standard or adaptive) for AWS clients created
+// after the call. It is set once at startup from system.aws_max_attempts and system.aws_retry_mode.
+func SetRetryOptions(maxAttempts int, mode string) error {
+	if maxAttempts < 0 {
+		return &ValidationError{Field: "AWS max attempts", Value: fmt.Sprintf("%d", maxAttempts), Message: "must be a positive number of attempts, or 0 for the SDK default"}
+	}
+	var parsed aws.RetryMode
+	if mode != "" {
+		var err error
+		if parsed, err = aws.ParseRetryMode(mode); err != nil {
+			return &ValidationError{Field: "AWS retry mode", Value: mode, Message: "must be standard or adaptive"}
+		}
+	}
+	retryMaxAttempts = maxAttempts
+	retryMode = parsed
+	return nil
+}
+
+// RetryLoadOptions returns the config load options that apply the SDK retry settings, if any
+func RetryLoadOptions() []func(*config.LoadOptions) error {
+	var opts []func(*config.LoadOptions) error
+	if retryMaxAttempts > 0 {
+		opts = append(opts, config.WithRetryMaxAttempts(retryMaxAttempts))
+	}
+	if retryMode != "" {
+		opts = append(opts, config.WithRetryMode(retryMode))
+	}
+	return opts
+}
+
+// CredentialOptionsFunc returns config load options that supply credentials for a profile
+// (empty for AWS_PROFILE or the default profile), or nil to leave them to the SDK
+type CredentialOptionsFunc func(ctx context.Context, profile string) []func(*config.LoadOptions) error
+
+// credentialOptions supplies credentials the SDK cannot resolve on its own, such as SSO tokens
+// held in ztictl's encrypted token cache
+var credentialOptions CredentialOptionsFunc
+
+// SetCredentialOptions registers the credential source used by AWS clients created after the call.
+// It is set once at startup.
+func SetCredentialOptions(fn CredentialOptionsFunc) {
+	credentialOptions = fn
+}
+
+// LoadOptions returns the endpoint override, the SDK retry settings and any registered credential options for a profile
+func LoadOptions(ctx context.Context, profile string) []func(*config.LoadOptions) error {
+	opts := append(EndpointLoadOptions(), RetryLoadOptions()...)
+	if credentialOptions != nil {
+		opts = append(opts, credentialOptions(ctx, profile)...)
+	}
+	return opts
+}
+
 // NewClient creates a new AWS client with the specified options
 func NewClient(ctx context.Context, opts ClientOptions) (*Client, error) {
 	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(ctx,
+	cfg, err := config.LoadDefaultConfig(ctx, append([]func(*config.LoadOptions) error{
 		config.WithRegion(opts.Region),
 		config.WithSharedConfigProfile(opts.Profile),
-	)
+	}, LoadOptions(ctx, opts.Profile)...)...)
 	if err != nil {
 		return nil, errors.NewAWSError("failed to load AWS configuration", err)
 	}
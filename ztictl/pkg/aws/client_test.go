@@ -3,6 +3,10 @@ package aws
 import (
 	"context"
 	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 )
 
 func TestClientOptions(t *testing.T) {
@@ -378,3 +382,109 @@ func TestClientMethodAvailability(t *testing.T) {
 	}
 
 }
+
+func TestSetEndpointURL(t *testing.T) {
+	defer func() { _ = SetEndpointURL("") }()
+
+	for _, invalid := range []string{"localhost:4566", "ftp://localhost:4566", "http://", "://bad"} {
+		if err := SetEndpointURL(invalid); err == nil {
+			t.Errorf("Expected %q to be rejected", invalid)
+		}
+	}
+
+	if err := SetEndpointURL("http://localhost:4566"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client, err := NewClient(context.Background(), ClientOptions{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.Config.BaseEndpoint == nil || *client.Config.BaseEndpoint != "http://localhost:4566" {
+		t.Errorf("Expected the endpoint override on the client config, got %v", client.Config.BaseEndpoint)
+	}
+
+	if err := SetEndpointURL(""); err != nil {
+		t.Fatalf("Unexpected error clearing the override: %v", err)
+	}
+	if opts := EndpointLoadOptions(); opts != nil {
+		t.Errorf("Expected no load options without an override, got %d", len(opts))
+	}
+}
+
+func TestSetEndpointVariants(t *testing.T) {
+	defer SetEndpointVariants(false, false)
+
+	SetEndpointVariants(true, true)
+	var loaded config.LoadOptions
+	for _, opt := range EndpointLoadOptions() {
+		if err := opt(&loaded); err != nil {
+			t.Fatalf("Load option failed: %v", err)
+		}
+	}
+	if loaded.UseDualStackEndpoint != aws.DualStackEndpointStateEnabled {
+		t.Errorf("Expected dualstack endpoints to be enabled, got %v", loaded.UseDualStackEndpoint)
+	}
+	if loaded.UseFIPSEndpoint != aws.FIPSEndpointStateEnabled {
+		t.Errorf("Expected FIPS endpoints to be enabled, got %v", loaded.UseFIPSEndpoint)
+	}
+
+	SetEndpointVariants(false, false)
+	if opts := EndpointLoadOptions(); opts != nil {
+		t.Errorf("Expected standard endpoints by default, got %d load options", len(opts))
+	}
+}
+
+func TestSetRetryOptions(t *testing.T) {
+	defer func() { _ = SetRetryOptions(0, "") }()
+
+	if err := SetRetryOptions(-1, ""); err == nil {
+		t.Error("Expected negative max attempts to be rejected")
+	}
+	if err := SetRetryOptions(5, "legacy"); err == nil {
+		t.Error("Expected an unknown retry mode to be rejected")
+	}
+
+	if err := SetRetryOptions(10, "adaptive"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client, err := NewClient(context.Background(), ClientOptions{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	if client.Config.RetryMaxAttempts != 10 || client.Config.RetryMode != aws.RetryModeAdaptive {
+		t.Errorf("Expected 10 attempts in adaptive mode, got %d in %q mode", client.Config.RetryMaxAttempts, client.Config.RetryMode)
+	}
+
+	if err := SetRetryOptions(0, ""); err != nil {
+		t.Fatalf("Unexpected error clearing the retry options: %v", err)
+	}
+	if opts := RetryLoadOptions(); opts != nil {
+		t.Errorf("Expected the SDK retry defaults without settings, got %d load options", len(opts))
+	}
+}
+
+func TestLoadOptionsUsesCredentialOptions(t *testing.T) {
+	defer SetCredentialOptions(nil)
+
+	if opts := LoadOptions(context.Background(), "dev"); opts != nil {
+		t.Errorf("Expected no load options by default, got %d", len(opts))
+	}
+
+	var gotProfile string
+	SetCredentialOptions(func(ctx context.Context, profile string) []func(*config.LoadOptions) error {
+		gotProfile = profile
+		return []func(*config.LoadOptions) error{config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("AKIAREGISTERED", "secret", ""))}
+	})
+	if opts := LoadOptions(context.Background(), "dev"); len(opts) != 1 || gotProfile != "dev" {
+		t.Errorf("Expected the credential options for profile dev, got %d options for %q", len(opts), gotProfile)
+	}
+
+	client, err := NewClient(context.Background(), ClientOptions{Region: "us-east-1"})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	creds, err := client.Config.Credentials.Retrieve(context.Background())
+	if err != nil || creds.AccessKeyID != "AKIAREGISTERED" {
+		t.Errorf("Expected credentials from the registered provider, got %q (%v)", creds.AccessKeyID, err)
+	}
+}
@@ -0,0 +1,184 @@
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"ztictl/pkg/logging"
+)
+
+const (
+	// DefaultInstanceCacheTTL is how long cached instance lookups are reused
+	DefaultInstanceCacheTTL = 60 * time.Second
+
+	// instanceCacheDirPermissions and instanceCacheFilePermissions restrict the cache to the current user
+	instanceCacheDirPermissions  = 0700
+	instanceCacheFilePermissions = 0600
+)
+
+// InstanceCache keeps instance inventories and identifier resolutions on disk for a short time,
+// so commands run in quick succession skip repeated DescribeInstances calls. Entries are kept
+// apart per credential source (profile or access key), endpoint and region.
+type InstanceCache struct {
+	dir     string
+	ttl     time.Duration
+	refresh bool // Ignore cached entries but store fresh results
+}
+
+// instanceCacheEntry is the on-disk form of a cached value
+type instanceCacheEntry struct {
+	CachedAt time.Time       `json:"cached_at"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// instanceCache is the cache used by instance services; nil disables caching
+var instanceCache *InstanceCache
+
+// SetInstanceCache sets the cache used by instance lookups. It is set once at startup; nil disables caching.
+func SetInstanceCache(cache *InstanceCache) {
+	instanceCache = cache
+}
+
+// NewInstanceCache creates a cache in dir whose entries expire after ttl.
+// With refresh set, cached entries are ignored and replaced by fresh results.
+func NewInstanceCache(dir string, ttl time.Duration, refresh bool) *InstanceCache {
+	return &InstanceCache{dir: filepath.Clean(dir), ttl: ttl, refresh: refresh}
+}
+
+// DefaultInstanceCacheDir returns the default cache directory (~/.ztictl/cache)
+func DefaultInstanceCacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".ztictl", "cache"), nil
+}
+
+type noInstanceCacheKey struct{}
+
+// WithoutInstanceCache returns a context whose instance lookups always query AWS,
+// for checks that must see the current instance state
+func WithoutInstanceCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noInstanceCacheKey{}, true)
+}
+
+// InvalidateInstanceCache drops the cached lookups for a region, after an operation that changed instance state
+func InvalidateInstanceCache(region string) {
+	if instanceCache == nil {
+		return
+	}
+	if err := os.RemoveAll(instanceCache.regionDir(region)); err != nil {
+		logging.LogDebug("Failed to invalidate instance cache for %s: %v", region, err)
+	}
+}
+
+// cacheFor returns the cache to use for a lookup, or nil when caching is off for it
+func cacheFor(ctx context.Context) *InstanceCache {
+	if instanceCache == nil || instanceCache.ttl <= 0 {
+		return nil
+	}
+	if bypass, _ := ctx.Value(noInstanceCacheKey{}).(bool); bypass {
+		return nil
+	}
+	return instanceCache
+}
+
+// cacheNamespace identifies the account a lookup runs against without storing credentials or profile names
+func cacheNamespace() string {
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = os.Getenv("AWS_DEFAULT_PROFILE")
+	}
+	source := "profile:" + profile
+	if accessKey := os.Getenv("AWS_ACCESS_KEY_ID"); accessKey != "" {
+		source = "key:" + accessKey
+	}
+	return source + "\x00endpoint:" + endpointURL
+}
+
+// hashName returns a file-name-safe hash of its parts
+func hashName(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// regionDir returns the directory holding the entries for a region in the current namespace
+func (c *InstanceCache) regionDir(region string) string {
+	return filepath.Join(c.dir, hashName(cacheNamespace(), region))
+}
+
+// path returns the file of one cache entry
+func (c *InstanceCache) path(region, key string) string {
+	return filepath.Join(c.regionDir(region), hashName(key)+".json")
+}
+
+// get decodes a fresh cached value into value and reports whether one was found
+func (c *InstanceCache) get(region, key string, value interface{}) bool {
+	if c.refresh {
+		return false
+	}
+
+	data, err := os.ReadFile(c.path(region, key)) // #nosec G304 - path is built from hashes under the cache directory
+	if err != nil {
+		return false
+	}
+	var entry instanceCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || time.Since(entry.CachedAt) > c.ttl {
+		return false
+	}
+	if err := json.Unmarshal(entry.Value, value); err != nil {
+		return false
+	}
+	logging.LogDebug("Using cached instance lookup %q in %s", key, region)
+	return true
+}
+
+// put stores a value; failures only cost a cache miss later, so they are logged and ignored
+func (c *InstanceCache) put(region, key string, value interface{}) {
+	encoded, err := json.Marshal(value)
+	if err == nil {
+		var data []byte
+		data, err = json.Marshal(instanceCacheEntry{CachedAt: time.Now().UTC(), Value: encoded})
+		if err == nil {
+			err = c.write(c.path(region, key), data)
+		}
+	}
+	if err != nil {
+		logging.LogDebug("Failed to cache instance lookup %q: %v", key, err)
+	}
+}
+
+// write replaces a cache file atomically with owner-only permissions
+func (c *InstanceCache) write(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), instanceCacheDirPermissions); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(instanceCacheFilePermissions); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
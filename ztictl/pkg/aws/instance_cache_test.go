@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestInstanceCacheRoundTrip(t *testing.T) {
+	t.Setenv("AWS_PROFILE", "dev")
+	cache := NewInstanceCache(t.TempDir(), time.Minute, false)
+
+	cache.put("us-east-1", "resolve:web", "i-1234567890abcdef0")
+
+	var got string
+	if !cache.get("us-east-1", "resolve:web", &got) {
+		t.Fatal("expected a cache hit")
+	}
+	if got != "i-1234567890abcdef0" {
+		t.Errorf("got %q, want i-1234567890abcdef0", got)
+	}
+	if cache.get("us-west-2", "resolve:web", &got) {
+		t.Error("entries must not be shared across regions")
+	}
+}
+
+func TestInstanceCacheExpiry(t *testing.T) {
+	cache := NewInstanceCache(t.TempDir(), time.Nanosecond, false)
+	cache.put("us-east-1", "resolve:web", "i-1234567890abcdef0")
+	time.Sleep(time.Millisecond)
+
+	var got string
+	if cache.get("us-east-1", "resolve:web", &got) {
+		t.Error("expected an expired entry to miss")
+	}
+}
+
+func TestInstanceCacheNamespacedByCredentials(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewInstanceCache(dir, time.Minute, false)
+
+	t.Setenv("AWS_PROFILE", "prod")
+	cache.put("us-east-1", "resolve:web", "i-prod")
+
+	var got string
+	t.Setenv("AWS_PROFILE", "dev")
+	if cache.get("us-east-1", "resolve:web", &got) {
+		t.Error("entries must not be shared across profiles")
+	}
+
+	t.Setenv("AWS_PROFILE", "prod")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIAEXAMPLE")
+	if cache.get("us-east-1", "resolve:web", &got) {
+		t.Error("entries must not be shared between a profile and static credentials")
+	}
+}
+
+func TestInstanceCacheFilePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX permissions are not enforced on Windows")
+	}
+	cache := NewInstanceCache(t.TempDir(), time.Minute, false)
+	cache.put("us-east-1", "resolve:web", "i-1234567890abcdef0")
+
+	info, err := os.Stat(cache.path("us-east-1", "resolve:web"))
+	if err != nil {
+		t.Fatalf("cache entry not written: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("cache entry permissions = %o, want 600", perm)
+	}
+	dirInfo, err := os.Stat(filepath.Dir(cache.path("us-east-1", "resolve:web")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := dirInfo.Mode().Perm(); perm != 0700 {
+		t.Errorf("cache directory permissions = %o, want 700", perm)
+	}
+}
+
+func TestInstanceCacheRefresh(t *testing.T) {
+	dir := t.TempDir()
+	NewInstanceCache(dir, time.Minute, false).put("us-east-1", "resolve:web", "i-old")
+
+	refreshing := NewInstanceCache(dir, time.Minute, true)
+	var got string
+	if refreshing.get("us-east-1", "resolve:web", &got) {
+		t.Error("refresh must ignore cached entries")
+	}
+	refreshing.put("us-east-1", "resolve:web", "i-new")
+
+	if !NewInstanceCache(dir, time.Minute, false).get("us-east-1", "resolve:web", &got) || got != "i-new" {
+		t.Errorf("refresh should store fresh results, got %q", got)
+	}
+}
+
+func TestCacheForBypassAndInvalidate(t *testing.T) {
+	defer SetInstanceCache(nil)
+
+	ctx := context.Background()
+	if cacheFor(ctx) != nil {
+		t.Fatal("expected no cache before SetInstanceCache")
+	}
+
+	cache := NewInstanceCache(t.TempDir(), time.Minute, false)
+	SetInstanceCache(cache)
+	if cacheFor(ctx) != cache {
+		t.Error("expected the configured cache")
+	}
+	if cacheFor(WithoutInstanceCache(ctx)) != nil {
+		t.Error("WithoutInstanceCache must bypass the cache")
+	}
+
+	cache.put("us-east-1", "resolve:web", "i-1234567890abcdef0")
+	cache.put("us-west-2", "resolve:web", "i-0fedcba0987654321")
+	InvalidateInstanceCache("us-east-1")
+
+	var got string
+	if cache.get("us-east-1", "resolve:web", &got) {
+		t.Error("expected invalidated region to miss")
+	}
+	if !cache.get("us-west-2", "resolve:web", &got) {
+		t.Error("invalidation must not touch other regions")
+	}
+}
+
+func TestInstanceListCacheKey(t *testing.T) {
+	if instanceListCacheKey(nil) != instanceListCacheKey(&ListFilters{}) {
+		t.Error("nil filters should share the key of empty filters")
+	}
+	if instanceListCacheKey(&ListFilters{Tag: "Env=prod"}) == instanceListCacheKey(&ListFilters{}) {
+		t.Error("different filters must use different keys")
+	}
+}
@@ -2,7 +2,10 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"sort"
 	"strings"
 
 	"ztictl/internal/interactive"
@@ -33,6 +36,13 @@ type ListFilters struct {
 	Tags   string `json:"tags,omitempty"`   // Format: key1=value1,key2=value2
 	Status string `json:"status,omitempty"` // Instance state
 	Name   string `json:"name,omitempty"`   // Name pattern
+	// NamePattern matches the Name tag exactly, with * and ? wildcards (e.g. web-*)
+	NamePattern string `json:"name_pattern,omitempty"`
+	// VPC and Subnet keep instances in one of these comma-separated VPC or subnet IDs
+	VPC    string `json:"vpc,omitempty"`
+	Subnet string `json:"subnet,omitempty"`
+	// InstanceIDs keeps only these comma-separated instance IDs
+	InstanceIDs string `json:"instance_ids,omitempty"`
 }
 
 // NewInstanceService creates a new instance service
@@ -45,6 +55,31 @@ func NewInstanceService(clientPool ClientPoolInterface, logger *logging.Logger)
 
 // ListInstances retrieves instances with SSM status - shared between auth and ssm commands
 func (s *InstanceService) ListInstances(ctx context.Context, region string, filters *ListFilters) ([]interactive.Instance, error) {
+	cache := cacheFor(ctx)
+	cacheKey := instanceListCacheKey(filters)
+	var cached []interactive.Instance
+	if cache != nil && cache.get(region, cacheKey, &cached) {
+		return cached, nil
+	}
+
+	instances, err := s.listInstances(ctx, region, filters)
+	if err == nil && cache != nil {
+		cache.put(region, cacheKey, instances)
+	}
+	return instances, err
+}
+
+// instanceListCacheKey identifies an instance listing by its filters
+func instanceListCacheKey(filters *ListFilters) string {
+	if filters == nil {
+		filters = &ListFilters{}
+	}
+	encoded, _ := json.Marshal(filters)
+	return "list:" + string(encoded)
+}
+
+// listInstances queries EC2 and SSM for instances matching filters
+func (s *InstanceService) listInstances(ctx context.Context, region string, filters *ListFilters) ([]interactive.Instance, error) {
 	s.logger.Debug("Listing all EC2 instances with SSM status in region", "region", region)
 
 	// Get clients from pool
@@ -129,6 +164,11 @@ func (s *InstanceService) ListInstances(ctx context.Context, region string, filt
 			publicIP = *ec2Instance.PublicIpAddress
 		}
 
+		var availabilityZone string
+		if ec2Instance.Placement != nil && ec2Instance.Placement.AvailabilityZone != nil {
+			availabilityZone = *ec2Instance.Placement.AvailabilityZone
+		}
+
 		instance := interactive.Instance{
 			InstanceID:       instanceID,
 			Name:             instanceName,
@@ -139,6 +179,7 @@ func (s *InstanceService) ListInstances(ctx context.Context, region string, filt
 			SSMStatus:        ssmStatus,
 			SSMAgentVersion:  ssmAgentVersion,
 			LastPingDateTime: lastPingDateTime,
+			AvailabilityZone: availabilityZone,
 			Tags:             tagMap,
 		}
 
@@ -148,8 +189,25 @@ func (s *InstanceService) ListInstances(ctx context.Context, region string, filt
 	return instances, nil
 }
 
-// ResolveInstanceIdentifier resolves an instance name or ID to an instance ID
+// ResolveInstanceIdentifier resolves an instance ID, private IP address, private DNS name
+// or Name tag to an instance ID
 func (s *InstanceService) ResolveInstanceIdentifier(ctx context.Context, identifier, region string) (string, error) {
+	cache := cacheFor(ctx)
+	cacheKey := "resolve:" + identifier
+	var cached string
+	if cache != nil && cache.get(region, cacheKey, &cached) {
+		return cached, nil
+	}
+
+	instanceID, err := s.resolveInstanceIdentifier(ctx, identifier, region)
+	if err == nil && cache != nil {
+		cache.put(region, cacheKey, instanceID)
+	}
+	return instanceID, err
+}
+
+// resolveInstanceIdentifier looks up an identifier in EC2
+func (s *InstanceService) resolveInstanceIdentifier(ctx context.Context, identifier, region string) (string, error) {
 	// If it's already an instance ID, validate and return it
 	if isInstanceID(identifier) {
 		err := s.validateInstanceID(ctx, identifier, region)
@@ -159,6 +217,11 @@ func (s *InstanceService) ResolveInstanceIdentifier(ctx context.Context, identif
 		return identifier, nil
 	}
 
+	// IP addresses and private DNS names are never Name tags worth searching for
+	if filter, kind := addressFilter(identifier); filter != nil {
+		return s.findInstanceByAddress(ctx, identifier, kind, *filter, region)
+	}
+
 	// Search by name tag
 	return s.findInstanceByName(ctx, identifier, region)
 }
@@ -200,7 +263,7 @@ func (s *InstanceService) getAllEC2Instances(ctx context.Context, ec2Client *ec2
 	var ec2Filters []types.Filter
 	if filters != nil {
 		// Handle tag filters (both old single tag and new multiple tags)
-		tagFilters := make(map[string]string)
+		var tagFilters []tagFilter
 
 		// Parse legacy single tag filter
 		if filters.Tag != "" {
@@ -208,9 +271,7 @@ func (s *InstanceService) getAllEC2Instances(ctx context.Context, ec2Client *ec2
 			if err != nil {
 				return nil, fmt.Errorf("invalid tag filter format: %w", err)
 			}
-			for k, v := range parsed {
-				tagFilters[k] = v
-			}
+			tagFilters = append(tagFilters, parsed)
 		}
 
 		// Parse new multiple tags filter
@@ -219,17 +280,12 @@ func (s *InstanceService) getAllEC2Instances(ctx context.Context, ec2Client *ec2
 			if err != nil {
 				return nil, fmt.Errorf("invalid tags filter format: %w", err)
 			}
-			for k, v := range parsed {
-				tagFilters[k] = v
-			}
+			tagFilters = append(tagFilters, parsed...)
 		}
 
 		// Apply tag filters
-		for key, value := range tagFilters {
-			ec2Filters = append(ec2Filters, types.Filter{
-				Name:   aws.String("tag:" + key),
-				Values: []string{value},
-			})
+		for _, filter := range tagFilters {
+			ec2Filters = append(ec2Filters, filter.ec2Filter())
 		}
 
 		// Apply status filter
@@ -247,6 +303,24 @@ func (s *InstanceService) getAllEC2Instances(ctx context.Context, ec2Client *ec2
 				Values: []string{"*" + filters.Name + "*"},
 			})
 		}
+
+		// Apply Name tag glob filter
+		if filters.NamePattern != "" {
+			ec2Filters = append(ec2Filters, types.Filter{
+				Name:   aws.String("tag:Name"),
+				Values: []string{filters.NamePattern},
+			})
+		}
+
+		ec2Filters = append(ec2Filters, NetworkFilters(filters.VPC, filters.Subnet)...)
+
+		// Filter on instance-id rather than setting InstanceIds, so unknown IDs are left out instead of failing the call
+		if ids := splitIDs(filters.InstanceIDs); len(ids) > 0 {
+			ec2Filters = append(ec2Filters, types.Filter{
+				Name:   aws.String("instance-id"),
+				Values: ids,
+			})
+		}
 	}
 
 	if len(ec2Filters) > 0 {
@@ -343,14 +417,94 @@ func (s *InstanceService) findInstanceByName(ctx context.Context, name, region s
 	}
 
 	if len(foundInstances) > 1 {
+		if IsNamePattern(name) {
+			return "", fmt.Errorf("name pattern '%s' matches %d instances, use a more specific name or an instance ID", name, len(foundInstances))
+		}
 		return "", fmt.Errorf("multiple instances found with name '%s', use instance ID instead", name)
 	}
 
 	return *foundInstances[0].InstanceId, nil
 }
 
+// findInstanceByAddress finds the one live instance matching an address filter.
+// The same private IP can exist in several VPCs, so multiple matches must be disambiguated by ID.
+func (s *InstanceService) findInstanceByAddress(ctx context.Context, address, kind string, filter types.Filter, region string) (string, error) {
+	ec2Client, err := s.clientPool.GetEC2Client(ctx, region)
+	if err != nil {
+		return "", fmt.Errorf("failed to get EC2 client for region %s: %w", region, err)
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			filter,
+			{
+				// Terminated instances can keep reporting their old address for a while
+				Name:   aws.String("instance-state-name"),
+				Values: []string{"pending", "running", "stopping", "stopped", "shutting-down"},
+			},
+		},
+	}
+
+	var foundInstances []types.Instance
+	paginator := ec2.NewDescribeInstancesPaginator(ec2Client, input)
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to search for instance by %s '%s': %w", kind, address, err)
+		}
+		for _, reservation := range output.Reservations {
+			foundInstances = append(foundInstances, reservation.Instances...)
+		}
+	}
+
+	if len(foundInstances) == 0 {
+		return "", fmt.Errorf("no instance found with %s '%s' in region %s", kind, address, region)
+	}
+	if len(foundInstances) > 1 {
+		return "", ambiguousAddressError(address, kind, foundInstances)
+	}
+
+	s.logger.Debug("Resolved instance by address", "address", address, "instance_id", aws.ToString(foundInstances[0].InstanceId))
+	return aws.ToString(foundInstances[0].InstanceId), nil
+}
+
+// ambiguousAddressError lists every instance sharing an address, with its VPC, so one can be picked by ID
+func ambiguousAddressError(address, kind string, instances []types.Instance) error {
+	matches := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		matches = append(matches, fmt.Sprintf("%s (%s)", aws.ToString(instance.InstanceId), aws.ToString(instance.VpcId)))
+	}
+	sort.Strings(matches)
+	return fmt.Errorf("%s '%s' matches %d instances in different VPCs: %s; use an instance ID instead",
+		kind, address, len(instances), strings.Join(matches, ", "))
+}
+
 // Helper functions
 
+// privateDNSSuffixes are the domain suffixes of EC2 private DNS names
+// (ip-10-0-1-5.ec2.internal in us-east-1, ip-10-0-1-5.ca-central-1.compute.internal elsewhere)
+var privateDNSSuffixes = []string{".ec2.internal", ".compute.internal"}
+
+// addressFilter returns the DescribeInstances filter for an identifier shaped like a private
+// IP address or private DNS name, and a description of it for messages. It returns nil for
+// anything else.
+func addressFilter(identifier string) (*types.Filter, string) {
+	if ip := net.ParseIP(identifier); ip != nil {
+		if ip.To4() != nil {
+			return &types.Filter{Name: aws.String("private-ip-address"), Values: []string{identifier}}, "private IP"
+		}
+		return &types.Filter{Name: aws.String("network-interface.ipv6-addresses.ipv6-address"), Values: []string{identifier}}, "IPv6 address"
+	}
+
+	lower := strings.ToLower(strings.TrimSuffix(identifier, "."))
+	for _, suffix := range privateDNSSuffixes {
+		if strings.HasSuffix(lower, suffix) && len(lower) > len(suffix) {
+			return &types.Filter{Name: aws.String("private-dns-name"), Values: []string{lower}}, "private DNS name"
+		}
+	}
+	return nil, ""
+}
+
 // AWS instance ID format constants
 // Instance IDs follow the pattern: i-[0-9a-f]{8,17}
 // Reference: https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/resource-ids.html
@@ -383,33 +537,43 @@ func isInstanceID(identifier string) bool {
 	return true
 }
 
-// parseTagFilter parses a single tag filter in the format key=value
-func parseTagFilter(tagStr string) (map[string]string, error) {
-	result := make(map[string]string)
+// IsNamePattern reports whether an instance identifier is a Name tag glob (contains * or ?)
+func IsNamePattern(identifier string) bool {
+	return !isInstanceID(identifier) && strings.ContainsAny(identifier, "*?")
+}
 
-	parts := strings.SplitN(tagStr, "=", 2)
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("tag filter must be in format key=value, got: %s", tagStr)
-	}
+// tagFilter is one parsed tag condition: Key=Value, or a bare Key that matches any value of the tag
+type tagFilter struct {
+	Key      string
+	Value    string
+	AnyValue bool
+}
 
-	key := strings.TrimSpace(parts[0])
-	value := strings.TrimSpace(parts[1])
+// ec2Filter converts the condition to a DescribeInstances filter: tag:Key for a value, tag-key for a bare key
+func (f tagFilter) ec2Filter() types.Filter {
+	if f.AnyValue {
+		return types.Filter{Name: aws.String("tag-key"), Values: []string{f.Key}}
+	}
+	return types.Filter{Name: aws.String("tag:" + f.Key), Values: []string{f.Value}}
+}
 
+// parseTagFilter parses a single tag filter in the format key=value, or key alone to require only the key
+func parseTagFilter(tagStr string) (tagFilter, error) {
+	key, value, found := strings.Cut(tagStr, "=")
+	key = strings.TrimSpace(key)
 	if key == "" {
-		return nil, fmt.Errorf("tag key cannot be empty")
+		return tagFilter{}, fmt.Errorf("tag key cannot be empty in '%s'", strings.TrimSpace(tagStr))
 	}
-
-	result[key] = value
-	return result, nil
+	if !found {
+		return tagFilter{Key: key, AnyValue: true}, nil
+	}
+	return tagFilter{Key: key, Value: strings.TrimSpace(value)}, nil
 }
 
-// parseTagFilters parses comma-separated tag filters into individual key=value pairs
-func parseTagFilters(tagsStr string) (map[string]string, error) {
-	result := make(map[string]string)
-
-	if tagsStr == "" {
-		return result, nil
-	}
+// parseTagFilters parses comma-separated tag filters. A later filter on the same key replaces an earlier one.
+func parseTagFilters(tagsStr string) ([]tagFilter, error) {
+	var result []tagFilter
+	index := make(map[string]int)
 
 	// Split by comma and process each tag
 	tagPairs := strings.Split(tagsStr, ",")
@@ -424,15 +588,55 @@ func parseTagFilters(tagsStr string) (map[string]string, error) {
 			return nil, err
 		}
 
-		// Merge the single tag into the result
-		for k, v := range parsed {
-			result[k] = v
+		if i, exists := index[parsed.Key]; exists {
+			result[i] = parsed
+			continue
 		}
+		index[parsed.Key] = len(result)
+		result = append(result, parsed)
 	}
 
 	return result, nil
 }
 
+// TagFilters converts comma-separated tag filters (key=value, or key to match any value) to
+// DescribeInstances filters. All filters must match.
+func TagFilters(tagsStr string) ([]types.Filter, error) {
+	parsed, err := parseTagFilters(tagsStr)
+	if err != nil {
+		return nil, err
+	}
+	filters := make([]types.Filter, len(parsed))
+	for i, filter := range parsed {
+		filters[i] = filter.ec2Filter()
+	}
+	return filters, nil
+}
+
+// NetworkFilters converts comma-separated VPC and subnet IDs to DescribeInstances filters.
+// An instance matches when it is in any of the VPCs and any of the subnets.
+func NetworkFilters(vpcs, subnets string) []types.Filter {
+	var filters []types.Filter
+	if ids := splitIDs(vpcs); len(ids) > 0 {
+		filters = append(filters, types.Filter{Name: aws.String("vpc-id"), Values: ids})
+	}
+	if ids := splitIDs(subnets); len(ids) > 0 {
+		filters = append(filters, types.Filter{Name: aws.String("subnet-id"), Values: ids})
+	}
+	return filters
+}
+
+// splitIDs splits a comma-separated list of IDs, dropping blanks
+func splitIDs(list string) []string {
+	var ids []string
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
 // getPlatformFromInstance determines the platform from EC2 instance information
 func getPlatformFromInstance(instance types.Instance) string {
 	// Check platform details first (most reliable)
@@ -3,11 +3,15 @@ package aws
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	"ztictl/pkg/logging"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
 )
 
@@ -110,6 +114,81 @@ func TestIsInstanceIDValidation(t *testing.T) {
 	}
 }
 
+func TestIsNamePattern(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"web-*", true},
+		{"web-?", true},
+		{"*", true},
+		{"web-server", false},
+		{"i-1234567890abcdef", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsNamePattern(tt.input); got != tt.expected {
+			t.Errorf("IsNamePattern(%q) = %v; expected %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestAddressFilter(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantFilter string
+		wantValue  string
+	}{
+		{"10.0.1.5", "private-ip-address", "10.0.1.5"},
+		{"2600:1f14:abc::10", "network-interface.ipv6-addresses.ipv6-address", "2600:1f14:abc::10"},
+		{"ip-10-0-1-5.ec2.internal", "private-dns-name", "ip-10-0-1-5.ec2.internal"},
+		{"IP-10-0-1-5.ca-central-1.compute.internal.", "private-dns-name", "ip-10-0-1-5.ca-central-1.compute.internal"},
+		{"web-server", "", ""},
+		{"10.0.1", "", ""},
+		{"i-1234567890abcdef", "", ""},
+		{".compute.internal", "", ""},
+	}
+
+	for _, tt := range tests {
+		filter, kind := addressFilter(tt.input)
+		if tt.wantFilter == "" {
+			if filter != nil {
+				t.Errorf("addressFilter(%q) = %s filter, want none", tt.input, aws.ToString(filter.Name))
+			}
+			continue
+		}
+		if filter == nil {
+			t.Errorf("addressFilter(%q) = nil, want %s filter", tt.input, tt.wantFilter)
+			continue
+		}
+		if aws.ToString(filter.Name) != tt.wantFilter || filter.Values[0] != tt.wantValue || kind == "" {
+			t.Errorf("addressFilter(%q) = %s=%v (%q), want %s=%s", tt.input, aws.ToString(filter.Name), filter.Values, kind, tt.wantFilter, tt.wantValue)
+		}
+	}
+}
+
+func TestAmbiguousAddressError(t *testing.T) {
+	err := ambiguousAddressError("10.0.1.5", "private IP", []types.Instance{
+		{InstanceId: aws.String("i-0bbbbbbbbbbbbbbbb"), VpcId: aws.String("vpc-2")},
+		{InstanceId: aws.String("i-0aaaaaaaaaaaaaaaa"), VpcId: aws.String("vpc-1")},
+	})
+
+	want := "private IP '10.0.1.5' matches 2 instances in different VPCs: i-0aaaaaaaaaaaaaaaa (vpc-1), i-0bbbbbbbbbbbbbbbb (vpc-2)"
+	if !strings.HasPrefix(err.Error(), want) {
+		t.Errorf("Unexpected error message: %q", err.Error())
+	}
+}
+
+func TestResolveInstanceIdentifierByAddressNeedsClient(t *testing.T) {
+	service := NewInstanceService(&MockClientPool{}, logging.NewNoOpLogger())
+
+	_, err := service.ResolveInstanceIdentifier(context.Background(), "10.0.1.5", "ca-central-1")
+	if err == nil || !strings.Contains(err.Error(), "failed to get EC2 client") {
+		t.Errorf("Expected the IP lookup to go through the EC2 client, got %v", err)
+	}
+}
+
 func TestTagFilterParsing(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -121,8 +200,11 @@ func TestTagFilterParsing(t *testing.T) {
 		{"multiple tags", "Environment=prod,Team=backend", 2, false},
 		{"tag with equals in value", "Config=key=value", 1, false},
 		{"empty string", "", 0, false},
-		{"invalid format", "Environment", 0, true},
+		{"key exists", "Backup", 1, false},
+		{"key exists with value filter", "Backup,Environment=prod", 2, false},
+		{"repeated key", "Environment=dev,Environment=prod", 1, false},
 		{"empty key", "=value", 0, true},
+		{"empty key in list", "Backup, =value", 0, true},
 	}
 
 	for _, tt := range tests {
@@ -147,3 +229,54 @@ func TestTagFilterParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestTagFilters(t *testing.T) {
+	filters, err := TagFilters("Backup, Environment=prod ,Monitoring,Environment=staging")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []struct{ name, value string }{
+		{"tag-key", "Backup"},
+		{"tag:Environment", "staging"},
+		{"tag-key", "Monitoring"},
+	}
+	if len(filters) != len(want) {
+		t.Fatalf("Expected %d filters, got %d", len(want), len(filters))
+	}
+	for i, w := range want {
+		if aws.ToString(filters[i].Name) != w.name || len(filters[i].Values) != 1 || filters[i].Values[0] != w.value {
+			t.Errorf("Filter %d = %s=%v, want %s=%s", i, aws.ToString(filters[i].Name), filters[i].Values, w.name, w.value)
+		}
+	}
+
+	if _, err := TagFilters("Backup,="); err == nil {
+		t.Error("Expected an empty key to be rejected")
+	}
+}
+
+func TestNetworkFilters(t *testing.T) {
+	if filters := NetworkFilters("", " , "); len(filters) != 0 {
+		t.Errorf("Expected no filters for empty lists, got %v", filters)
+	}
+
+	filters := NetworkFilters("vpc-111", "subnet-aaa, subnet-bbb")
+	if len(filters) != 2 {
+		t.Fatalf("Expected 2 filters, got %d", len(filters))
+	}
+	if aws.ToString(filters[0].Name) != "vpc-id" || !slices.Equal(filters[0].Values, []string{"vpc-111"}) {
+		t.Errorf("Unexpected VPC filter %s=%v", aws.ToString(filters[0].Name), filters[0].Values)
+	}
+	if aws.ToString(filters[1].Name) != "subnet-id" || !slices.Equal(filters[1].Values, []string{"subnet-aaa", "subnet-bbb"}) {
+		t.Errorf("Unexpected subnet filter %s=%v", aws.ToString(filters[1].Name), filters[1].Values)
+	}
+}
+
+func TestInstanceListCacheKeySeparatesNetworkFilters(t *testing.T) {
+	if instanceListCacheKey(&ListFilters{Tags: "App=web", VPC: "vpc-1"}) == instanceListCacheKey(&ListFilters{Tags: "App=web", VPC: "vpc-2"}) {
+		t.Error("Expected listings for different VPCs to be cached separately")
+	}
+	if instanceListCacheKey(&ListFilters{InstanceIDs: "i-1"}) == instanceListCacheKey(&ListFilters{InstanceIDs: "i-2"}) {
+		t.Error("Expected lookups of different instance IDs to be cached separately")
+	}
+}
@@ -0,0 +1,122 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"ztictl/pkg/errors"
+)
+
+// RegionShortcode is a single shortcode to AWS region mapping known to a RegionResolver
+type RegionShortcode struct {
+	Shortcode   string
+	Region      string
+	Description string
+	Custom      bool // Defined in the user's configuration rather than built in
+}
+
+// RegionResolver resolves region shortcodes using the built-in table plus user-configured shortcodes.
+// Custom shortcodes take precedence over built-in ones with the same name.
+type RegionResolver struct {
+	custom map[string]string
+}
+
+// NewRegionResolver creates a resolver with the given custom shortcode to region mappings
+func NewRegionResolver(custom map[string]string) *RegionResolver {
+	normalized := make(map[string]string, len(custom))
+	for code, region := range custom {
+		code = strings.ToLower(strings.TrimSpace(code))
+		region = strings.TrimSpace(region)
+		if code != "" && region != "" {
+			normalized[code] = region
+		}
+	}
+	return &RegionResolver{custom: normalized}
+}
+
+// Resolve converts a shortcode or full AWS region name to the full region name
+func (r *RegionResolver) Resolve(shortcode string) (string, error) {
+	code := strings.ToLower(strings.TrimSpace(shortcode))
+
+	if region, exists := r.custom[code]; exists {
+		return region, nil
+	}
+	if region, exists := RegionMapping[code]; exists {
+		return region, nil
+	}
+	if IsValidAWSRegion(shortcode) {
+		return shortcode, nil
+	}
+
+	return "", errors.NewValidationError(fmt.Sprintf("invalid region code: %s", shortcode))
+}
+
+// Shortcode returns the shortcode for a region (shortcode or full name), or the input if none is known
+func (r *RegionResolver) Shortcode(region string) string {
+	code := strings.ToLower(region)
+	if _, exists := r.custom[code]; exists {
+		return code
+	}
+	if _, exists := RegionMapping[code]; exists {
+		return code
+	}
+
+	// Prefer a custom alias, picking the alphabetically first for a stable result
+	var aliases []string
+	for alias, fullRegion := range r.custom {
+		if fullRegion == region {
+			aliases = append(aliases, alias)
+		}
+	}
+	if len(aliases) > 0 {
+		sort.Strings(aliases)
+		return aliases[0]
+	}
+
+	return GetRegionCode(region)
+}
+
+// IsShortcode reports whether the input is a known built-in or custom shortcode
+func (r *RegionResolver) IsShortcode(shortcode string) bool {
+	code := strings.ToLower(shortcode)
+	_, custom := r.custom[code]
+	_, builtin := RegionMapping[code]
+	return custom || builtin
+}
+
+// Description returns a human-readable description for a built-in or custom shortcode
+func (r *RegionResolver) Description(shortcode string) string {
+	if region, exists := r.custom[strings.ToLower(shortcode)]; exists {
+		return fmt.Sprintf("Custom shortcode for %s", region)
+	}
+	return GetRegionDescription(shortcode)
+}
+
+// List returns every known shortcode mapping sorted by shortcode.
+// A custom shortcode replaces a built-in one with the same name.
+func (r *RegionResolver) List() []RegionShortcode {
+	mappings := make([]RegionShortcode, 0, len(RegionMapping)+len(r.custom))
+	for code, region := range RegionMapping {
+		if _, overridden := r.custom[code]; overridden {
+			continue
+		}
+		mappings = append(mappings, RegionShortcode{Shortcode: code, Region: region, Description: RegionDescriptions[code]})
+	}
+	for code, region := range r.custom {
+		mappings = append(mappings, RegionShortcode{Shortcode: code, Region: region, Description: r.Description(code), Custom: true})
+	}
+
+	sort.Slice(mappings, func(i, j int) bool { return mappings[i].Shortcode < mappings[j].Shortcode })
+	return mappings
+}
+
+// Shortcodes returns the known shortcodes in sorted order
+func (r *RegionResolver) Shortcodes() []string {
+	mappings := r.List()
+	codes := make([]string, len(mappings))
+	for i, mapping := range mappings {
+		codes[i] = mapping.Shortcode
+	}
+	return codes
+}
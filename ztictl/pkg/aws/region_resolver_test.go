@@ -0,0 +1,83 @@
+package aws
+
+import (
+	"testing"
+)
+
+func TestRegionResolverResolve(t *testing.T) {
+	resolver := NewRegionResolver(map[string]string{"Lab": "us-west-2", "cac1": "ca-west-1"})
+
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "use1", expected: "us-east-1"},
+		{input: "USE1", expected: "us-east-1"},
+		{input: "lab", expected: "us-west-2"},
+		{input: "cac1", expected: "ca-west-1"}, // custom overrides built-in
+		{input: "eu-west-3", expected: "eu-west-3"},
+		{input: "nowhere", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := resolver.Resolve(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Resolve(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegionResolverShortcode(t *testing.T) {
+	resolver := NewRegionResolver(map[string]string{"tlv": "il-central-1"})
+
+	tests := map[string]string{
+		"cac1":         "cac1",
+		"ca-central-1": "cac1",
+		"il-central-1": "tlv",
+		"tlv":          "tlv",
+		"xx-nowhere-9": "xx-nowhere-9",
+	}
+	for input, expected := range tests {
+		if got := resolver.Shortcode(input); got != expected {
+			t.Errorf("Shortcode(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestRegionResolverList(t *testing.T) {
+	resolver := NewRegionResolver(map[string]string{"tlv": "il-central-1", "cac1": "ca-west-1"})
+	mappings := resolver.List()
+
+	if len(mappings) != len(RegionMapping)+1 {
+		t.Fatalf("Expected %d mappings, got %d", len(RegionMapping)+1, len(mappings))
+	}
+	for i := 1; i < len(mappings); i++ {
+		if mappings[i-1].Shortcode >= mappings[i].Shortcode {
+			t.Fatalf("Mappings not sorted: %s before %s", mappings[i-1].Shortcode, mappings[i].Shortcode)
+		}
+	}
+
+	for _, mapping := range mappings {
+		switch mapping.Shortcode {
+		case "cac1":
+			if !mapping.Custom || mapping.Region != "ca-west-1" {
+				t.Errorf("Expected custom cac1 to override the built-in, got %+v", mapping)
+			}
+		case "tlv":
+			if !mapping.Custom || mapping.Region != "il-central-1" {
+				t.Errorf("Unexpected custom mapping: %+v", mapping)
+			}
+		case "use1":
+			if mapping.Custom || mapping.Description != "US East (N. Virginia)" {
+				t.Errorf("Unexpected built-in mapping: %+v", mapping)
+			}
+		}
+	}
+}
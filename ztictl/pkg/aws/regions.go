@@ -7,7 +7,9 @@ import (
 	"ztictl/pkg/errors"
 )
 
-// RegionMapping maps region codes to AWS region names
+// RegionMapping maps region codes to AWS region names for all commercial AWS regions.
+// Codes are the area prefix, the initials of the direction and the region number
+// (ap-southeast-5 -> apse5, il-central-1 -> ilc1).
 var RegionMapping = map[string]string{
 	// Canada
 	"cac1": "ca-central-1", // Montreal
@@ -19,6 +21,9 @@ var RegionMapping = map[string]string{
 	"usw1": "us-west-1", // N. California
 	"usw2": "us-west-2", // Oregon
 
+	// Mexico
+	"mxc1": "mx-central-1", // Querétaro
+
 	// Europe
 	"euw1": "eu-west-1",    // Ireland
 	"euw2": "eu-west-2",    // London
@@ -30,12 +35,17 @@ var RegionMapping = map[string]string{
 	"eus2": "eu-south-2",   // Spain
 
 	// Asia Pacific
+	"ape1":  "ap-east-1",      // Hong Kong
+	"ape2":  "ap-east-2",      // Taipei
 	"aps1":  "ap-south-1",     // Mumbai
 	"aps2":  "ap-south-2",     // Hyderabad
 	"apse1": "ap-southeast-1", // Singapore
 	"apse2": "ap-southeast-2", // Sydney
 	"apse3": "ap-southeast-3", // Jakarta
 	"apse4": "ap-southeast-4", // Melbourne
+	"apse5": "ap-southeast-5", // Malaysia
+	"apse6": "ap-southeast-6", // New Zealand
+	"apse7": "ap-southeast-7", // Thailand
 	"apne1": "ap-northeast-1", // Tokyo
 	"apne2": "ap-northeast-2", // Seoul
 	"apne3": "ap-northeast-3", // Osaka
@@ -49,6 +59,7 @@ var RegionMapping = map[string]string{
 	// Middle East
 	"mes1": "me-south-1",   // Bahrain
 	"mec1": "me-central-1", // UAE
+	"ilc1": "il-central-1", // Tel Aviv
 }
 
 // RegionDescriptions provides human-readable descriptions for regions
@@ -59,6 +70,7 @@ var RegionDescriptions = map[string]string{
 	"use2":  "US East (Ohio)",
 	"usw1":  "US West (N. California)",
 	"usw2":  "US West (Oregon)",
+	"mxc1":  "Mexico Central (Querétaro)",
 	"euw1":  "EU West (Ireland)",
 	"euw2":  "EU West (London)",
 	"euw3":  "EU West (Paris)",
@@ -67,12 +79,17 @@ var RegionDescriptions = map[string]string{
 	"eun1":  "EU North (Stockholm)",
 	"eus1":  "EU South (Milan)",
 	"eus2":  "EU South (Spain)",
+	"ape1":  "Asia Pacific East (Hong Kong)",
+	"ape2":  "Asia Pacific East (Taipei)",
 	"aps1":  "Asia Pacific South (Mumbai)",
 	"aps2":  "Asia Pacific South (Hyderabad)",
 	"apse1": "Asia Pacific Southeast (Singapore)",
 	"apse2": "Asia Pacific Southeast (Sydney)",
 	"apse3": "Asia Pacific Southeast (Jakarta)",
 	"apse4": "Asia Pacific Southeast (Melbourne)",
+	"apse5": "Asia Pacific Southeast (Malaysia)",
+	"apse6": "Asia Pacific Southeast (New Zealand)",
+	"apse7": "Asia Pacific Southeast (Thailand)",
 	"apne1": "Asia Pacific Northeast (Tokyo)",
 	"apne2": "Asia Pacific Northeast (Seoul)",
 	"apne3": "Asia Pacific Northeast (Osaka)",
@@ -80,6 +97,7 @@ var RegionDescriptions = map[string]string{
 	"afs1":  "Africa South (Cape Town)",
 	"mes1":  "Middle East South (Bahrain)",
 	"mec1":  "Middle East Central (UAE)",
+	"ilc1":  "Israel Central (Tel Aviv)",
 }
 
 // GetRegion converts a region code to an AWS region name
@@ -0,0 +1,24 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// throttleErrorCodes are the API error codes treated as throttling, including SSM's RateExceeded
+var throttleErrorCodes = func() map[string]struct{} {
+	codes := map[string]struct{}{"RateExceeded": {}}
+	for code := range retry.DefaultThrottleErrorCodes {
+		codes[code] = struct{}{}
+	}
+	return codes
+}()
+
+// IsThrottlingError reports whether err, or any error it wraps, is an AWS throttling error.
+// These are returned once the SDK's own retries are exhausted.
+func IsThrottlingError(err error) bool {
+	if err == nil {
+		return false
+	}
+	return retry.ThrottleErrorCode{Codes: throttleErrorCodes}.IsErrorThrottle(err) == aws.TrueTernary
+}
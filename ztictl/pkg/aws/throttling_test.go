@@ -0,0 +1,32 @@
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestIsThrottlingError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "plain error", err: errors.New("connection reset"), want: false},
+		{name: "throttling exception", err: &smithy.GenericAPIError{Code: "ThrottlingException"}, want: true},
+		{name: "rate exceeded", err: &smithy.GenericAPIError{Code: "RateExceeded"}, want: true},
+		{name: "wrapped throttling", err: fmt.Errorf("failed to send command: %w", &smithy.GenericAPIError{Code: "ThrottlingException"}), want: true},
+		{name: "other api error", err: &smithy.GenericAPIError{Code: "InvalidInstanceId"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsThrottlingError(tt.err); got != tt.want {
+				t.Errorf("IsThrottlingError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
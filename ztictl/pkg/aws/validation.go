@@ -2,7 +2,9 @@ package aws
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 // IsValidAWSRegion validates if a string is a properly formatted AWS region.
@@ -38,6 +40,8 @@ func IsValidAWSRegion(region string) bool {
 		"sa":     true, // South America
 		"me":     true, // Middle East
 		"af":     true, // Africa
+		"il":     true, // Israel
+		"mx":     true, // Mexico
 		"cn":     true, // China
 		"us-gov": true, // GovCloud
 	}
@@ -167,6 +171,35 @@ func ValidateRegionInput(input string) (string, error) {
 	}
 }
 
+// Resource tag limits shared by S3 and IAM
+const (
+	MaxTagKeyLength   = 128
+	MaxTagValueLength = 256
+)
+
+// tagCharacters matches the characters S3 and IAM accept in tag keys and values
+var tagCharacters = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// ValidateTag checks a resource tag against the AWS key and value constraints
+func ValidateTag(key, value string) error {
+	field := fmt.Sprintf("Tag '%s'", key)
+	switch {
+	case key == "":
+		return &ValidationError{Field: "Tag key", Value: key, Message: "tag key cannot be empty"}
+	case utf8.RuneCountInString(key) > MaxTagKeyLength:
+		return &ValidationError{Field: "Tag key", Value: key, Message: fmt.Sprintf("must be at most %d characters", MaxTagKeyLength)}
+	case strings.HasPrefix(strings.ToLower(key), "aws:"):
+		return &ValidationError{Field: "Tag key", Value: key, Message: "the aws: prefix is reserved for AWS"}
+	case !tagCharacters.MatchString(key):
+		return &ValidationError{Field: "Tag key", Value: key, Message: "may only contain letters, numbers, spaces and _ . : / = + - @"}
+	case utf8.RuneCountInString(value) > MaxTagValueLength:
+		return &ValidationError{Field: field, Value: value, Message: fmt.Sprintf("value must be at most %d characters", MaxTagValueLength)}
+	case !tagCharacters.MatchString(value):
+		return &ValidationError{Field: field, Value: value, Message: "value may only contain letters, numbers, spaces and _ . : / = + - @"}
+	}
+	return nil
+}
+
 // ValidationError represents a validation error with details
 type ValidationError struct {
 	Field   string
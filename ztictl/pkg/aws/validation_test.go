@@ -2,6 +2,7 @@ package aws
 
 import (
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -150,3 +151,28 @@ func TestValidationError(t *testing.T) {
 		t.Errorf("ValidationError.Error() = %q, expected %q", err.Error(), expected)
 	}
 }
+
+func TestValidateTag(t *testing.T) {
+	tests := []struct {
+		key, value string
+		wantErr    bool
+	}{
+		{key: "CostCenter", value: "platform-42"},
+		{key: "team:owner", value: "ops@example.com"},
+		{key: "Empty", value: ""},
+		{key: "", value: "x", wantErr: true},
+		{key: "aws:createdBy", value: "me", wantErr: true},
+		{key: "AWS:Name", value: "me", wantErr: true},
+		{key: strings.Repeat("k", MaxTagKeyLength+1), value: "x", wantErr: true},
+		{key: "Owner", value: strings.Repeat("v", MaxTagValueLength+1), wantErr: true},
+		{key: "Bad#Key", value: "x", wantErr: true},
+		{key: "Owner", value: "semi;colon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateTag(tt.key, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateTag(%q, %q) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package colors
 
-import "github.com/fatih/color"
+import (
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+)
 
 // Standardized color definitions for ztictl
 // These colors are used consistently across all SSM commands and other UI elements
@@ -22,6 +27,22 @@ var (
 	Warning = color.New(color.FgHiYellow, color.Bold)
 )
 
+// NoColorRequested reports whether color output should be disabled, either by
+// the --no-color flag or the NO_COLOR convention (https://no-color.org)
+func NoColorRequested(noColorFlag bool) bool {
+	return noColorFlag || os.Getenv("NO_COLOR") != ""
+}
+
+// Disable turns off color output globally, including colors created with color.New elsewhere
+func Disable() {
+	color.NoColor = true
+}
+
+// SetOutput redirects colored output, e.g. to stderr when stdout carries data such as a downloaded file
+func SetOutput(w io.Writer) {
+	color.Output = w
+}
+
 // Convenience functions for common color operations
 func PrintHeader(format string, args ...interface{}) {
 	_, _ = Header.Printf(format, args...) // #nosec G104
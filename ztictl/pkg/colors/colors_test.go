@@ -220,6 +220,38 @@ func TestColorDisabling(t *testing.T) {
 	}
 }
 
+func TestNoColorRequested(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if NoColorRequested(false) {
+		t.Error("Expected colors to stay enabled without flag or NO_COLOR")
+	}
+	if !NoColorRequested(true) {
+		t.Error("Expected --no-color flag to disable colors")
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if !NoColorRequested(false) {
+		t.Error("Expected NO_COLOR to disable colors")
+	}
+}
+
+func TestDisable(t *testing.T) {
+	originalNoColor := color.NoColor
+	defer func() { color.NoColor = originalNoColor }()
+
+	color.NoColor = false
+	Disable()
+
+	// Colors created outside this package must also be plain
+	result := color.New(color.FgRed).Sprintf("plain")
+	if result != "plain" {
+		t.Errorf("Expected uncolored output after Disable, got %q", result)
+	}
+	if ColorSuccess("ok") != "ok" {
+		t.Errorf("Expected uncolored ColorSuccess after Disable, got %q", ColorSuccess("ok"))
+	}
+}
+
 func TestPrintFunctionsWithDisabledColors(t *testing.T) {
 	// Test print functions with colors disabled
 	originalNoColor := color.NoColor
@@ -0,0 +1,58 @@
+// Package format renders durations and sizes for human-readable summaries.
+// JSON output keeps raw values; these helpers are for text output only.
+package format
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Duration renders d compactly: sub-second values in milliseconds (850ms),
+// values under a minute with at most one decimal (12.3s, 5s), and longer values to the
+// second without zero components (1m23s, 2h5m, 1h).
+func Duration(d time.Duration) string {
+	if d < 0 {
+		return "-" + Duration(-d)
+	}
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	if r := d.Round(100 * time.Millisecond); r < time.Minute {
+		return strings.TrimSuffix(fmt.Sprintf("%.1f", r.Seconds()), ".0") + "s"
+	}
+
+	d = d.Round(time.Second)
+	hours := d / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	seconds := (d % time.Minute) / time.Second
+
+	var b strings.Builder
+	if hours > 0 {
+		fmt.Fprintf(&b, "%dh", hours)
+	}
+	if minutes > 0 {
+		fmt.Fprintf(&b, "%dm", minutes)
+	}
+	if seconds > 0 {
+		fmt.Fprintf(&b, "%ds", seconds)
+	}
+	return b.String()
+}
+
+// Bytes renders n using IEC units, e.g. 512 B, 1.4 KiB, 1.4 GiB
+func Bytes(n int64) string {
+	if n < 0 {
+		return "-" + Bytes(-n)
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit && exp < 5; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,52 @@
+package format
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{850 * time.Millisecond, "850ms"},
+		{1234567 * time.Microsecond, "1.2s"},
+		{12340 * time.Millisecond, "12.3s"},
+		{59960 * time.Millisecond, "1m"},
+		{5 * time.Second, "5s"},
+		{83 * time.Second, "1m23s"},
+		{2 * time.Minute, "2m"},
+		{time.Hour, "1h"},
+		{2*time.Hour + 5*time.Minute + 400*time.Millisecond, "2h5m"},
+		{time.Hour + 3*time.Second, "1h3s"},
+		{-1500 * time.Millisecond, "-1.5s"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.in); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1536, "1.5 KiB"},
+		{1024 * 1024, "1.0 MiB"},
+		{1503238553, "1.4 GiB"},
+		{5 * 1024 * 1024 * 1024 * 1024, "5.0 TiB"},
+		{-2048, "-2.0 KiB"},
+	}
+	for _, tt := range tests {
+		if got := Bytes(tt.in); got != tt.want {
+			t.Errorf("Bytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
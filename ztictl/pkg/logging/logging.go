@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"ztictl/pkg/colors"
@@ -18,6 +19,9 @@ var (
 	fileLogger  *log.Logger
 	logFile     *os.File // Store file handle for proper cleanup
 	loggerMutex sync.RWMutex
+
+	// quietMode suppresses console info, debug and success messages; they are still written to the log file
+	quietMode atomic.Bool
 )
 
 func init() {
@@ -144,10 +148,22 @@ func logToFile(level string, message string) {
 	}
 }
 
+// SetQuiet enables or disables quiet mode, in which only warnings and errors reach the console
+func SetQuiet(quiet bool) {
+	quietMode.Store(quiet)
+}
+
+// IsQuiet reports whether quiet mode is enabled
+func IsQuiet() bool {
+	return quietMode.Load()
+}
+
 // LogInfo logs an info message - colored to console, timestamped to file
 func LogInfo(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	_, _ = colors.Success.Printf("[INFO] %s\n", message)
+	if !IsQuiet() {
+		_, _ = colors.Success.Printf("[INFO] %s\n", message)
+	}
 	logToFile("INFO", message)
 }
 
@@ -168,14 +184,18 @@ func LogError(format string, args ...interface{}) {
 // LogDebug logs a debug message - colored to console, timestamped to file
 func LogDebug(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	_, _ = colors.Data.Printf("[DEBUG] %s\n", message)
+	if !IsQuiet() {
+		_, _ = colors.Data.Printf("[DEBUG] %s\n", message)
+	}
 	logToFile("DEBUG", message)
 }
 
 // LogSuccess logs a success message - colored to console, timestamped to file
 func LogSuccess(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
-	_, _ = colors.Success.Printf("[SUCCESS] %s\n", message)
+	if !IsQuiet() {
+		_, _ = colors.Success.Printf("[SUCCESS] %s\n", message)
+	}
 	logToFile("SUCCESS", message)
 }
 
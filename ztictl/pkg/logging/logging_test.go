@@ -1,8 +1,12 @@
 package logging
 
 import (
+	"bytes"
+	"os"
 	"strings"
 	"testing"
+
+	"github.com/fatih/color"
 )
 
 // TestLoggerFormatFields tests that the formatFields method properly formats structured logging fields
@@ -153,3 +157,32 @@ func TestFormatFieldsNoPipeCharacter(t *testing.T) {
 		}
 	}
 }
+
+// TestQuietModeSuppressesInfo ensures quiet mode hides info and success lines but keeps warnings and errors
+func TestQuietModeSuppressesInfo(t *testing.T) {
+	var buf bytes.Buffer
+	color.Output = &buf
+	SetQuiet(true)
+	defer func() {
+		color.Output = os.Stdout
+		SetQuiet(false)
+	}()
+
+	LogInfo("info message")
+	LogSuccess("success message")
+	LogDebug("debug message")
+	LogWarn("warn message")
+	LogError("error message")
+
+	output := buf.String()
+	for _, hidden := range []string{"info message", "success message", "debug message"} {
+		if strings.Contains(output, hidden) {
+			t.Errorf("Expected %q to be suppressed in quiet mode, got:\n%s", hidden, output)
+		}
+	}
+	for _, shown := range []string{"warn message", "error message"} {
+		if !strings.Contains(output, shown) {
+			t.Errorf("Expected %q to be printed in quiet mode, got:\n%s", shown, output)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// Version 1-M "HELLO WORLD" from the worked example of the QR code tutorial at thonky.com
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder() = %v, want %v", got, want)
+	}
+}
+
+func TestBCHRemainder(t *testing.T) {
+	// Format information for level M with mask 0 is all zeros before the XOR mask
+	if got := (0<<10 | bchRemainder(0, 0x537, 10)) ^ 0x5412; got != 0x5412 {
+		t.Errorf("format bits for M/0 = %#x, want 0x5412", got)
+	}
+	// Level M mask 4 is 100010111111001
+	if got := (4<<10 | bchRemainder(4, 0x537, 10)) ^ 0x5412; got != 0x45F9 {
+		t.Errorf("format bits for M/4 = %#x, want 0x45f9", got)
+	}
+	// Version information for version 7 is 000111110010010100
+	if got := 7<<12 | bchRemainder(7, 0x1F25, 12); got != 0x07C94 {
+		t.Errorf("version bits for 7 = %#x, want 0x07c94", got)
+	}
+}
+
+func TestEncodeData(t *testing.T) {
+	got := encodeData(1, []byte("ab"))
+	// Byte mode, a count of 2, 'a', 'b', the terminator, then alternating pad bytes
+	want := []byte{0x40, 0x26, 0x16, 0x20, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11}
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeData() = % x, want % x", got, want)
+	}
+}
+
+func TestEncodeVersion(t *testing.T) {
+	tests := []struct {
+		length  int
+		version int
+	}{
+		{length: 1, version: 1},
+		{length: 14, version: 1},
+		{length: 15, version: 2},
+		{length: 66, version: 5}, // A typical SSO device verification URL
+		{length: 213, version: 10},
+	}
+
+	for _, tt := range tests {
+		c, err := Encode(strings.Repeat("a", tt.length))
+		if err != nil {
+			t.Fatalf("Encode() of %d bytes failed: %v", tt.length, err)
+		}
+		if c.Version != tt.version || c.Size != 17+4*tt.version {
+			t.Errorf("Encode() of %d bytes = version %d size %d, want version %d", tt.length, c.Version, c.Size, tt.version)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("a", 214)); err == nil || !strings.Contains(err.Error(), "at most 213") {
+		t.Errorf("Expected an error for text over 213 bytes, got %v", err)
+	}
+}
+
+func TestEncodeFunctionPatterns(t *testing.T) {
+	c, err := Encode("https://device.sso.us-east-1.amazonaws.com/?user_code=WXYZ-ABCD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Finder patterns in three corners
+	for _, corner := range [][2]int{{0, 0}, {c.Size - 7, 0}, {0, c.Size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				ring := max(abs(dx-3), abs(dy-3))
+				if got := c.Dark(corner[0]+dx, corner[1]+dy); got != (ring != 2) {
+					t.Fatalf("Finder pattern at %v has the wrong module at (%d, %d)", corner, dx, dy)
+				}
+			}
+		}
+	}
+	// Timing patterns and the dark module
+	for i := 8; i < c.Size-8; i++ {
+		if c.Dark(i, 6) != (i%2 == 0) || c.Dark(6, i) != (i%2 == 0) {
+			t.Fatalf("Timing pattern wrong at %d", i)
+		}
+	}
+	if !c.Dark(8, c.Size-8) {
+		t.Error("Expected the dark module next to the bottom left finder pattern")
+	}
+
+	// Both copies of the format information agree
+	var first, second int
+	for _, x := range []int{0, 1, 2, 3, 4, 5, 7, 8} {
+		first = first<<1 | boolBit(c.Dark(x, 8))
+	}
+	for _, y := range []int{7, 5, 4, 3, 2, 1, 0} {
+		first = first<<1 | boolBit(c.Dark(8, y))
+	}
+	for y := c.Size - 1; y > c.Size-8; y-- {
+		second = second<<1 | boolBit(c.Dark(8, y))
+	}
+	for x := c.Size - 8; x < c.Size; x++ {
+		second = second<<1 | boolBit(c.Dark(x, 8))
+	}
+	if first != second || first>>13 != 0b10 {
+		t.Errorf("Format information copies %015b and %015b should match and start with level M", first, second)
+	}
+}
+
+func TestRender(t *testing.T) {
+	c, err := Encode("ztictl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := c.Render(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	width := c.Size + 2*quietZone
+	if len(lines) != (width+1)/2 {
+		t.Errorf("Expected %d lines, got %d", (width+1)/2, len(lines))
+	}
+	for i, line := range lines {
+		if n := utf8.RuneCountInString(line); n != width {
+			t.Fatalf("Line %d is %d characters wide, want %d", i, n, width)
+		}
+	}
+	// The top of the quiet zone is light, drawn as full blocks
+	if lines[0] != strings.Repeat("█", width) {
+		t.Errorf("Expected the first line to be quiet zone, got %q", lines[0])
+	}
+}
+
+func boolBit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// The round trip test below decodes complete symbols from Encode with a reader that shares nothing
+// with the encoder but the block layout. Its constants come from ISO/IEC 18004.
+
+// specCodewords holds the total and level M data codewords of versions 1 to 10 (table 7)
+var specCodewords = [MaxVersion + 1][2]int{
+	1: {26, 16}, 2: {44, 28}, 3: {70, 44}, 4: {100, 64}, 5: {134, 86},
+	6: {172, 108}, 7: {196, 124}, 8: {242, 154}, 9: {292, 182}, 10: {346, 216},
+}
+
+// specRemainderBits holds the data modules left over after the last codeword (table 1)
+var specRemainderBits = [MaxVersion + 1]int{0, 0, 7, 7, 7, 7, 7, 0, 0, 0, 0}
+
+// specFormatM holds the level M format information of masks 0 to 7 (table C.1)
+var specFormatM = [8]string{
+	"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+	"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+}
+
+// specVersionInfo holds the version information of versions 7 to 10 (table D.1)
+var specVersionInfo = map[int]int{7: 0x07C94, 8: 0x085BC, 9: 0x09A99, 10: 0x0A4D3}
+
+// specFunctionModules marks the function patterns and the format and version areas of a version
+func specFunctionModules(version int) [][]bool {
+	size := 17 + 4*version
+	function := make([][]bool, size)
+	for row := range function {
+		function[row] = make([]bool, size)
+	}
+	mark := func(row, col, rows, cols int) {
+		for r := row; r < row+rows; r++ {
+			for c := col; c < col+cols; c++ {
+				function[r][c] = true
+			}
+		}
+	}
+
+	// Finder patterns with separators and format areas, then the alignment patterns that do not
+	// overlap them, and the timing and version areas
+	mark(0, 0, 9, 9)
+	mark(0, size-8, 9, 8)
+	mark(size-8, 0, 8, 9)
+	if version >= 2 {
+		// Alignment centres run from 6 to size-7 in equal even steps (annex E)
+		count := version/7 + 2
+		step := (size - 13) / (count - 1)
+		if step%2 != 0 {
+			step++
+		}
+		centres := []int{6}
+		for i := count - 1; i >= 1; i-- {
+			centres = append(centres, size-7-(count-1-i)*step)
+		}
+		for _, r := range centres {
+			for _, c := range centres {
+				if !function[r][c] {
+					mark(r-2, c-2, 5, 5)
+				}
+			}
+		}
+	}
+	mark(6, 0, 1, size)
+	mark(0, 6, size, 1)
+	if version >= 7 {
+		mark(0, size-11, 6, 3)
+		mark(size-11, 0, 3, 6)
+	}
+	return function
+}
+
+// specMask reports whether a mask pattern inverts the module at row i and column j (table 10)
+func specMask(mask, i, j int) bool {
+	switch mask {
+	case 0:
+		return (i+j)%2 == 0
+	case 1:
+		return i%2 == 0
+	case 2:
+		return j%3 == 0
+	case 3:
+		return (i+j)%3 == 0
+	case 4:
+		return (i/2+j/3)%2 == 0
+	case 5:
+		return (i*j)%2+(i*j)%3 == 0
+	case 6:
+		return ((i*j)%2+(i*j)%3)%2 == 0
+	default:
+		return ((i*j)%3+(i+j)%2)%2 == 0
+	}
+}
+
+// decodeSymbol reads the text back from a level M byte mode symbol, checking the format and
+// version information and that every Reed-Solomon block is a valid codeword
+func decodeSymbol(t *testing.T, c *Code) string {
+	t.Helper()
+	size := c.Size
+	module := func(row, col int) int {
+		if c.Dark(col, row) {
+			return 1
+		}
+		return 0
+	}
+
+	// Format information, most significant bit first, around the top left finder
+	var format strings.Builder
+	for _, col := range []int{0, 1, 2, 3, 4, 5, 7, 8} {
+		format.WriteByte(byte('0' + module(8, col)))
+	}
+	for _, row := range []int{7, 5, 4, 3, 2, 1, 0} {
+		format.WriteByte(byte('0' + module(row, 8)))
+	}
+	mask := -1
+	for m, bits := range specFormatM {
+		if bits == format.String() {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format information %s is not level M", format.String())
+	}
+	// The second copy, beside the bottom left finder and below the top right one
+	var second strings.Builder
+	for row := size - 1; row >= size-7; row-- {
+		second.WriteByte(byte('0' + module(row, 8)))
+	}
+	for col := size - 8; col < size; col++ {
+		second.WriteByte(byte('0' + module(8, col)))
+	}
+	if second.String() != format.String() || module(size-8, 8) != 1 {
+		t.Errorf("second format copy %s or dark module does not match %s", second.String(), format.String())
+	}
+
+	// Version information, least significant bit first, in both corners
+	if want, ok := specVersionInfo[c.Version]; ok {
+		topRight, bottomLeft := 0, 0
+		for i := 0; i < 18; i++ {
+			topRight |= module(i/3, size-11+i%3) << i
+			bottomLeft |= module(size-11+i%3, i/3) << i
+		}
+		if topRight != want || bottomLeft != want {
+			t.Errorf("version information = %#x and %#x, want %#x", topRight, bottomLeft, want)
+		}
+	}
+
+	// Read the data modules upwards and downwards in two-column strips from the right
+	function := specFunctionModules(c.Version)
+	var bits []int
+	upward := true
+	for right := size - 1; right > 0; right -= 2 {
+		if right == 6 {
+			right--
+		}
+		for n := 0; n < size; n++ {
+			row := n
+			if upward {
+				row = size - 1 - n
+			}
+			for _, col := range []int{right, right - 1} {
+				if !function[row][col] {
+					bit := module(row, col)
+					if specMask(mask, row, col) {
+						bit ^= 1
+					}
+					bits = append(bits, bit)
+				}
+			}
+		}
+		upward = !upward
+	}
+	total, dataTotal := specCodewords[c.Version][0], specCodewords[c.Version][1]
+	if len(bits) != 8*total+specRemainderBits[c.Version] {
+		t.Fatalf("version %d has %d data modules, want %d", c.Version, len(bits), 8*total+specRemainderBits[c.Version])
+	}
+	codewords := make([]byte, total)
+	for i := range codewords {
+		for _, bit := range bits[8*i : 8*i+8] {
+			codewords[i] = codewords[i]<<1 | byte(bit)
+		}
+	}
+
+	// De-interleave the blocks and check each one's syndromes in GF(256) with polynomial 0x11D
+	var exp [512]byte
+	var log [256]int
+	for i, x := 0, 1; i < 255; i++ {
+		exp[i], exp[i+255] = byte(x), byte(x)
+		log[x] = i
+		if x <<= 1; x >= 256 {
+			x ^= 0x11D
+		}
+	}
+	layout := layouts[c.Version]
+	var sizes []int
+	for _, group := range [][2]int{layout.group1, layout.group2} {
+		for i := 0; i < group[0]; i++ {
+			sizes = append(sizes, group[1])
+		}
+	}
+	if layout.dataCodewords() != dataTotal || dataTotal+len(sizes)*layout.ecPerBlock != total {
+		t.Fatalf("version %d block layout does not add up to %d data of %d codewords", c.Version, dataTotal, total)
+	}
+	blocks := make([][]byte, len(sizes))
+	next := 0
+	for i := 0; i < sizes[len(sizes)-1]; i++ {
+		for b, n := range sizes {
+			if i < n {
+				blocks[b] = append(blocks[b], codewords[next])
+				next++
+			}
+		}
+	}
+	for i := 0; i < layout.ecPerBlock; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], codewords[next])
+			next++
+		}
+	}
+	var data []byte
+	for b, block := range blocks {
+		for root := 0; root < layout.ecPerBlock; root++ {
+			var syndrome byte
+			for _, cw := range block {
+				if syndrome != 0 {
+					syndrome = exp[log[syndrome]+root]
+				}
+				syndrome ^= cw
+			}
+			if syndrome != 0 {
+				t.Fatalf("version %d block %d has a non-zero syndrome for root %d", c.Version, b, root)
+			}
+		}
+		data = append(data, block[:sizes[b]]...)
+	}
+
+	// Byte mode indicator and character count, then the text
+	stream := bitReader{data: data}
+	if mode := stream.read(4); mode != 0x4 {
+		t.Fatalf("mode indicator = %#x, want byte mode", mode)
+	}
+	count := stream.read(countBits(c.Version))
+	text := make([]byte, count)
+	for i := range text {
+		text[i] = byte(stream.read(8))
+	}
+	return string(text)
+}
+
+// bitReader reads big-endian bit fields from a byte slice
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) read(n int) int {
+	value := 0
+	for i := 0; i < n; i++ {
+		value = value<<1 | int(r.data[r.pos/8]>>(7-r.pos%8)&1)
+		r.pos++
+	}
+	return value
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	tests := []string{
+		"https://device.sso.ca-central-1.amazonaws.com/?user_code=QWER-TYUI",
+		"https://zsoftly.awsapps.com/start/#/device?user_code=ABCD-EFGH",
+		"https://d-1234567890.awsapps.com/start/#/device?user_code=WXYZ-ABCD&region=us-east-1&extra=" + strings.Repeat("x", 60),
+		"https://zsoftly.awsapps.com/start/#/device?user_code=ABCD-EFGH&state=" + strings.Repeat("7", 46),
+		"a",
+		"é✓",
+		strings.Repeat("0123456789", 21) + "abc",
+	}
+
+	versions := map[int]bool{}
+	for _, text := range tests {
+		c, err := Encode(text)
+		if err != nil {
+			t.Fatalf("Encode(%q) failed: %v", text, err)
+		}
+		versions[c.Version] = true
+		if got := decodeSymbol(t, c); got != text {
+			t.Errorf("decoded %q, want %q", got, text)
+		}
+	}
+	for _, version := range []int{1, 5, 7, 10} {
+		if !versions[version] {
+			t.Errorf("Expected the round trip to cover version %d, got %v", version, versions)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package security
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// RedactedText replaces every match of a redaction pattern
+const RedactedText = "***"
+
+// DefaultRedactPatterns match common secrets that end up in command output
+var DefaultRedactPatterns = []string{
+	// AWS access key IDs, long-term (AKIA) and temporary (ASIA)
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	// AWS secret access keys written as a key/value pair, e.g. by 'aws configure list' or env dumps
+	`(?i)aws_secret_access_key["']?\s*[=:]\s*["']?[A-Za-z0-9/+=]{40}`,
+	// Bearer tokens in HTTP Authorization headers
+	`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`,
+	// PEM private keys
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+}
+
+// Redactor replaces the matches of a set of regular expressions with RedactedText.
+// A nil Redactor leaves text unchanged.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor compiles the given patterns, which use Go regexp (RE2) syntax
+func NewRedactor(patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Redact returns text with every match of every pattern replaced by RedactedText
+func (r *Redactor) Redact(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	for _, re := range r.patterns {
+		text = re.ReplaceAllString(text, RedactedText)
+	}
+	return text
+}